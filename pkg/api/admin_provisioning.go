@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"errors"
+	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -107,3 +108,12 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// AdminProvisioningGetAlertingStatus returns the status of the alerting provisioning files, when they are watched for changes.
+func (hs *HTTPServer) AdminProvisioningGetAlertingStatus(c *contextmodel.ReqContext) response.Response {
+	status, ok := hs.ProvisioningService.GetAlertingProvisioningStatus()
+	if !ok {
+		return response.Error(http.StatusNotFound, "Alerting provisioning files are not watched for changes", nil)
+	}
+	return response.JSON(http.StatusOK, status)
+}
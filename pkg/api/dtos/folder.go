@@ -35,4 +35,38 @@ type FolderSearchHit struct {
 	UID       string `json:"uid" xorm:"uid"`
 	Title     string `json:"title"`
 	ParentUID string `json:"parentUid,omitempty"`
+	// true if the user does not have access to the folder, in which case its UID and title are not returned
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// FolderPath describes the location of a folder in the folder tree
+type FolderPath struct {
+	// the folders starting from the root going down to the requested folder (included)
+	Path []FolderSearchHit `json:"path"`
+	// the titles of the folders in the path separated by a slash, redacted folders are omitted
+	Fullpath string `json:"fullpath"`
+}
+
+// FolderDescendants describes the subtree of a folder
+type FolderDescendants struct {
+	// all folders in the subtree that the user can read
+	Folders []FolderDescendant `json:"folders"`
+	// a page of the alert rules stored in the folder and in the folders of the subtree that the user can read
+	AlertRules []FolderDescendantAlertRule `json:"alertRules"`
+	// the number of descendants of each kind, including the ones the user can not read
+	Counts map[string]int64 `json:"counts"`
+}
+
+type FolderDescendant struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	ParentUID string `json:"parentUid,omitempty"`
+	// the titles of the folders from the root to this folder separated by a slash
+	Fullpath string `json:"fullpath,omitempty"`
+}
+
+type FolderDescendantAlertRule struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
 }
@@ -79,6 +79,9 @@ type MetricRequest struct {
 	Queries []*simplejson.Json `json:"queries"`
 	// required: false
 	Debug bool `json:"debug"`
+	// PartialResults allows expressions with several inputs to be executed when some of their inputs failed.
+	// required: false
+	PartialResults bool `json:"partialResults"`
 }
 
 func (mr *MetricRequest) GetUniqueDatasourceTypes() []string {
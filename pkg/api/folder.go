@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/apierrors"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -21,12 +22,16 @@ import (
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/store/entity"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 const REDACTED = "redacted"
 
+// defaultDescendantRulesLimit is the default size of the page of alert rules returned with the descendants of a folder.
+const defaultDescendantRulesLimit = 1000
+
 // swagger:route GET /folders folders getFolders
 //
 // Get all folders.
@@ -144,6 +149,9 @@ func (hs *HTTPServer) GetFolderByID(c *contextmodel.ReqContext) response.Respons
 //
 // If nested folders are enabled then it additionally expects the parent folder UID.
 //
+// If `ignoreExisting` is set and a folder with the same UID, or with the same title in the parent folder,
+// already exists then the existing folder is returned instead of a conflict error.
+//
 // Responses:
 // 200: folderResponse
 // 400: badRequestError
@@ -159,20 +167,29 @@ func (hs *HTTPServer) CreateFolder(c *contextmodel.ReqContext) response.Response
 	cmd.OrgID = c.SignedInUser.GetOrgID()
 	cmd.SignedInUser = c.SignedInUser
 
-	folder, err := hs.folderService.Create(c.Req.Context(), &cmd)
+	var f *folder.Folder
+	var err error
+	created := true
+	if cmd.IgnoreExisting {
+		f, created, err = hs.folderService.CreateOrGet(c.Req.Context(), &cmd)
+	} else {
+		f, err = hs.folderService.Create(c.Req.Context(), &cmd)
+	}
 	if err != nil {
 		return apierrors.ToFolderErrorResponse(err)
 	}
 
-	if err := hs.setDefaultFolderPermissions(c.Req.Context(), cmd.OrgID, cmd.SignedInUser, folder); err != nil {
-		hs.log.Error("Could not set the default folder permissions", "folder", folder.Title, "user", cmd.SignedInUser, "error", err)
-	}
+	if created {
+		if err := hs.setDefaultFolderPermissions(c.Req.Context(), cmd.OrgID, cmd.SignedInUser, f); err != nil {
+			hs.log.Error("Could not set the default folder permissions", "folder", f.Title, "user", cmd.SignedInUser, "error", err)
+		}
 
-	// Clear permission cache for the user who's created the folder, so that new permissions are fetched for their next call
-	// Required for cases when caller wants to immediately interact with the newly created object
-	hs.accesscontrolService.ClearUserPermissionCache(c.SignedInUser)
+		// Clear permission cache for the user who's created the folder, so that new permissions are fetched for their next call
+		// Required for cases when caller wants to immediately interact with the newly created object
+		hs.accesscontrolService.ClearUserPermissionCache(c.SignedInUser)
+	}
 
-	folderDTO, err := hs.newToFolderDto(c, folder)
+	folderDTO, err := hs.newToFolderDto(c, f)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -286,6 +303,7 @@ func (hs *HTTPServer) UpdateFolder(c *contextmodel.ReqContext) response.Response
 //
 // Deletes an existing folder identified by UID along with all dashboards (and their alerts) stored in the folder. This operation cannot be reverted.
 // If nested folders are enabled then it also deletes all the subfolders.
+// If the folder or its subfolders contain alert rules, the request fails with a conflict that lists their rule groups, unless `forceDeleteRules` is `true`.
 //
 // Responses:
 // 200: deleteFolderResponse
@@ -293,6 +311,7 @@ func (hs *HTTPServer) UpdateFolder(c *contextmodel.ReqContext) response.Response
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 409: conflictError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteFolder(c *contextmodel.ReqContext) response.Response { // temporarily adding this function to HTTPServer, will be removed from HTTPServer when librarypanels featuretoggle is removed
 	err := hs.LibraryElementService.DeleteLibraryElementsInFolder(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
@@ -339,6 +358,121 @@ func (hs *HTTPServer) GetFolderDescendantCounts(c *contextmodel.ReqContext) resp
 
 	return response.JSON(http.StatusOK, counts)
 }
+
+// swagger:route GET /folders/{folder_uid}/path folders getFolderPath
+//
+// Gets the full path of a folder, starting from the root folder down to the folder identified by UID.
+//
+// Ancestors the user does not have access to are redacted in the path and omitted from the full path.
+//
+// Responses:
+// 200: getFolderPathResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFolderPath(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	uid := web.Params(c.Req)[":uid"]
+	f, err := hs.folderService.Get(ctx, &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	parents, err := hs.folderService.GetParents(ctx, folder.GetParentsQuery{UID: f.UID, OrgID: f.OrgID})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	result := dtos.FolderPath{
+		Path: make([]dtos.FolderSearchHit, 0, len(parents)+1),
+	}
+	titles := make([]string, 0, len(parents)+1)
+	for _, p := range parents {
+		hit := dtos.FolderSearchHit{UID: p.UID, Title: p.Title, ParentUID: p.ParentUID}
+		g, err := guardian.NewByFolder(ctx, p, c.SignedInUser.GetOrgID(), c.SignedInUser)
+		if err != nil {
+			return response.Err(err)
+		}
+		if canView, _ := g.CanView(); !canView {
+			result.Path = append(result.Path, dtos.FolderSearchHit{Redacted: true})
+			continue
+		}
+		result.Path = append(result.Path, hit)
+		titles = append(titles, strings.ReplaceAll(hit.Title, "/", "\\/"))
+	}
+	result.Path = append(result.Path, dtos.FolderSearchHit{UID: f.UID, Title: f.Title, ParentUID: f.ParentUID})
+	titles = append(titles, strings.ReplaceAll(f.Title, "/", "\\/"))
+	result.Fullpath = strings.Join(titles, "/")
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /folders/{folder_uid}/descendants folders getFolderDescendants
+//
+// Gets all the folders in the subtree of a folder, a page of the alert rules stored in the subtree, and the count of each descendant by kind.
+// The folder is identified by UID.
+//
+// Only the folders the user has access to, and the alert rules stored in them, are returned.
+//
+// Responses:
+// 200: getFolderDescendantsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFolderDescendants(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	uid := web.Params(c.Req)[":uid"]
+	folders, err := hs.folderService.GetDescendants(ctx, &folder.GetDescendantsQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	counts, err := hs.folderService.GetDescendantCounts(ctx, &folder.GetDescendantCountsQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	result := dtos.FolderDescendants{
+		Folders: make([]dtos.FolderDescendant, 0, len(folders)),
+		Counts:  counts,
+	}
+	folderUIDs := make([]string, 0, len(folders)+1)
+	folderUIDs = append(folderUIDs, uid)
+	for _, f := range folders {
+		result.Folders = append(result.Folders, dtos.FolderDescendant{
+			UID:       f.UID,
+			Title:     f.Title,
+			ParentUID: f.ParentUID,
+			Fullpath:  f.Fullpath,
+		})
+		folderUIDs = append(folderUIDs, f.UID)
+	}
+
+	limit := c.QueryInt64("limit")
+	if limit <= 0 {
+		limit = defaultDescendantRulesLimit
+	}
+	rules, err := hs.folderService.GetItemsInFolders(ctx, &folder.GetItemsInFoldersQuery{
+		FolderUIDs:   folderUIDs,
+		OrgID:        c.SignedInUser.GetOrgID(),
+		Kind:         entity.StandardKindAlertRule,
+		Limit:        limit,
+		Page:         c.QueryInt64("page"),
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	result.AlertRules = make([]dtos.FolderDescendantAlertRule, 0, len(rules))
+	for _, r := range rules {
+		result.AlertRules = append(result.AlertRules, dtos.FolderDescendantAlertRule{UID: r.UID, Title: r.Title, FolderUID: r.FolderUID})
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
 func (hs *HTTPServer) newToFolderDto(c *contextmodel.ReqContext, f *folder.Folder) (dtos.Folder, error) {
 	ctx := c.Req.Context()
 	toDTO := func(f *folder.Folder, checkCanView bool) (dtos.Folder, error) {
@@ -548,8 +682,8 @@ type DeleteFolderParams struct {
 	// in:path
 	// required:true
 	FolderUID string `json:"folder_uid"`
-	// If `true` any Grafana 8 Alerts under this folder will be deleted.
-	// Set to `false` so that the request will fail if the folder contains any Grafana 8 Alerts.
+	// If `true` the alert rules stored in the folder and its subfolders will be deleted along with the folder.
+	// Set to `false` so that the request will fail with a list of their rule groups if the folder contains any alert rules.
 	// in:query
 	// required:false
 	// default:false
@@ -599,6 +733,42 @@ type GetFolderDescendantCountsParams struct {
 	FolderUID string `json:"folder_uid"`
 }
 
+// swagger:parameters getFolderPath
+type GetFolderPathParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:response getFolderPathResponse
+type GetFolderPathResponse struct {
+	// in: body
+	Body dtos.FolderPath `json:"body"`
+}
+
+// swagger:parameters getFolderDescendants
+type GetFolderDescendantsParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// Limit the maximum number of alert rules to return
+	// in:query
+	// required:false
+	// default:1000
+	Limit int64 `json:"limit"`
+	// Page index for starting fetching alert rules
+	// in:query
+	// required:false
+	// default:1
+	Page int64 `json:"page"`
+}
+
+// swagger:response getFolderDescendantsResponse
+type GetFolderDescendantsResponse struct {
+	// in: body
+	Body dtos.FolderDescendants `json:"body"`
+}
+
 // swagger:response getFolderDescendantCountsResponse
 type GetFolderDescendantCountsResponse struct {
 	// The response message
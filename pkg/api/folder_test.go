@@ -152,6 +152,63 @@ func TestFoldersCreateAPIEndpoint(t *testing.T) {
 	}
 }
 
+func TestFoldersCreateAPIEndpointIgnoreExisting(t *testing.T) {
+	setUpRBACGuardian(t)
+
+	testCases := []struct {
+		desc                   string
+		created                bool
+		expectedSetPermissions bool
+	}{
+		{
+			desc:                   "sets default permissions if the folder is created",
+			created:                true,
+			expectedSetPermissions: true,
+		},
+		{
+			desc:                   "returns the existing folder without changing its permissions",
+			created:                false,
+			expectedSetPermissions: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			folderService := &foldertest.FakeService{
+				ExpectedFolder:  &folder.Folder{UID: "uid", Title: "Folder"},
+				ExpectedCreated: tc.created,
+			}
+			folderPermService := acmock.NewMockedPermissionsService()
+			folderPermService.On("SetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]accesscontrol.ResourcePermission{}, nil)
+
+			srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.folderService = folderService
+				hs.folderPermissionsService = folderPermService
+				hs.accesscontrolService = actest.FakeService{}
+			})
+
+			input := strings.NewReader("{ \"uid\": \"uid\", \"title\": \"Folder\", \"ignoreExisting\": true}")
+			req := srv.NewPostRequest("/api/folders", input)
+			req = webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{{Action: dashboards.ActionFoldersCreate}}))
+			resp, err := srv.SendJSON(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			f := dtos.Folder{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&f))
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, "uid", f.UID)
+
+			if tc.expectedSetPermissions {
+				folderPermService.AssertCalled(t, "SetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				folderPermService.AssertNotCalled(t, "SetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestFoldersUpdateAPIEndpoint(t *testing.T) {
 	folderService := &foldertest.FakeService{}
 	setUpRBACGuardian(t)
@@ -522,3 +579,112 @@ func TestFolderGetAPIEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestFolderPathAPIEndpoint(t *testing.T) {
+	folderService := &foldertest.FakeService{
+		ExpectedFolder: &folder.Folder{UID: "uid", Title: "uid/title", ParentUID: "subfolder"},
+		ExpectedFolders: []*folder.Folder{
+			{UID: "parent", Title: "parent title"},
+			{UID: "subfolder", Title: "subfolder title", ParentUID: "parent"},
+		},
+	}
+	srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders)
+		hs.folderService = folderService
+	})
+	permissions := []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("uid")},
+	}
+
+	testCases := []struct {
+		desc             string
+		canView          bool
+		expectedUIDs     []string
+		expectedRedacted []bool
+		expectedFullpath string
+	}{
+		{
+			desc:             "returns the path from the root folder",
+			canView:          true,
+			expectedUIDs:     []string{"parent", "subfolder", "uid"},
+			expectedRedacted: []bool{false, false, false},
+			expectedFullpath: "parent title/subfolder title/uid\\/title",
+		},
+		{
+			desc:             "redacts ancestors the user can not view",
+			canView:          false,
+			expectedUIDs:     []string{"", "", "uid"},
+			expectedRedacted: []bool{true, true, false},
+			expectedFullpath: "uid\\/title",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			origNewGuardian := guardian.New
+			t.Cleanup(func() {
+				guardian.New = origNewGuardian
+			})
+			guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: tc.canView})
+
+			req := webtest.RequestWithSignedInUser(srv.NewGetRequest("/api/folders/uid/path"), userWithPermissions(1, permissions))
+			resp, err := srv.Send(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			result := dtos.FolderPath{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			require.NoError(t, resp.Body.Close())
+
+			uids := make([]string, 0, len(result.Path))
+			redacted := make([]bool, 0, len(result.Path))
+			for _, p := range result.Path {
+				uids = append(uids, p.UID)
+				redacted = append(redacted, p.Redacted)
+			}
+			assert.Equal(t, tc.expectedUIDs, uids)
+			assert.Equal(t, tc.expectedRedacted, redacted)
+			assert.Equal(t, tc.expectedFullpath, result.Fullpath)
+		})
+	}
+}
+
+func TestFolderDescendantsAPIEndpoint(t *testing.T) {
+	setUpRBACGuardian(t)
+	folderService := &foldertest.FakeService{
+		ExpectedFolders: []*folder.Folder{
+			{UID: "child", Title: "child", ParentUID: "uid", Fullpath: "uid/child"},
+			{UID: "grandchild", Title: "grandchild", ParentUID: "child", Fullpath: "uid/child/grandchild"},
+		},
+		ExpectedDescendantCounts: map[string]int64{"folder": 2, "alertrule": 5},
+		ExpectedItems: []folder.RegistryItem{
+			{UID: "rule", Title: "rule title", FolderUID: "child"},
+		},
+	}
+	srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders)
+		hs.folderService = folderService
+	})
+
+	req := webtest.RequestWithSignedInUser(srv.NewGetRequest("/api/folders/uid/descendants"), userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("uid")},
+	}))
+	resp, err := srv.Send(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	result := dtos.FolderDescendants{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, []dtos.FolderDescendant{
+		{UID: "child", Title: "child", ParentUID: "uid", Fullpath: "uid/child"},
+		{UID: "grandchild", Title: "grandchild", ParentUID: "child", Fullpath: "uid/child/grandchild"},
+	}, result.Folders)
+	assert.Equal(t, []dtos.FolderDescendantAlertRule{
+		{UID: "rule", Title: "rule title", FolderUID: "child"},
+	}, result.AlertRules)
+	assert.Equal(t, map[string]int64{"folder": 2, "alertrule": 5}, result.Counts)
+}
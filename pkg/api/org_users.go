@@ -96,6 +96,12 @@ func (hs *HTTPServer) addOrgUserHelper(c *contextmodel.ReqContext, cmd org.AddOr
 		return response.Error(http.StatusInternalServerError, "Could not add user to organization", err)
 	}
 
+	// The user can have cached permissions from a previous membership or from being signed in without a role
+	hs.accesscontrolService.ClearUserPermissionCache(&user.SignedInUser{
+		UserID: cmd.UserID,
+		OrgID:  cmd.OrgID,
+	})
+
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "User added to organization",
 		"userId":  cmd.UserID,
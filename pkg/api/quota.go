@@ -47,7 +47,13 @@ func (hs *HTTPServer) GetOrgQuotas(c *contextmodel.ReqContext) response.Response
 }
 
 func (hs *HTTPServer) getOrgQuotasHelper(c *contextmodel.ReqContext, orgID int64) response.Response {
-	q, err := hs.QuotaService.GetQuotasByScope(c.Req.Context(), quota.OrgScope, orgID)
+	var q []quota.QuotaDTO
+	var err error
+	if c.QueryBool("breakdown") {
+		q, err = hs.QuotaService.GetQuotasByScopeWithBreakdown(c.Req.Context(), quota.OrgScope, orgID)
+	} else {
+		q, err = hs.QuotaService.GetQuotasByScope(c.Req.Context(), quota.OrgScope, orgID)
+	}
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get quota", err)
 	}
@@ -185,6 +191,18 @@ type GetOrgQuotaParams struct {
 	// in:path
 	// required:true
 	OrgID int64 `json:"org_id"`
+	// Include the usage breakdown per resource (e.g. per folder) reported by the services supporting it.
+	// in:query
+	// required:false
+	Breakdown bool `json:"breakdown"`
+}
+
+// swagger:parameters getCurrentOrgQuota
+type GetCurrentOrgQuotaParams struct {
+	// Include the usage breakdown per resource (e.g. per folder) reported by the services supporting it.
+	// in:query
+	// required:false
+	Breakdown bool `json:"breakdown"`
 }
 
 // swagger:parameters updateOrgQuota
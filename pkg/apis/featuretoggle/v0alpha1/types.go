@@ -29,6 +29,12 @@ type FeatureSpec struct {
 	// Enabled by default for version >=
 	EnabledVersion string `json:"enabledVersion,omitempty"`
 
+	// The date (YYYY-MM-DD) the flag should be removed, or the feature reach GA
+	Expires string `json:"expires,omitempty"`
+
+	// The flag is past its expiry date
+	Expired bool `json:"expired,omitempty"`
+
 	// Must be run using in development mode (early dev)
 	RequiresDevMode bool `json:"requiresDevMode,omitempty"`
 
@@ -112,3 +118,24 @@ type ToggleStatus struct {
 	// eg: unknown flag
 	Warning string `json:"warning,omitempty"`
 }
+
+// ToggleValidationReport lists the problems of the feature toggle configuration detected at startup
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ToggleValidationReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The problems of the configuration
+	Problems []ToggleValidationProblem `json:"problems"`
+}
+
+type ToggleValidationProblem struct {
+	// The feature toggle name, as it is written in the configuration
+	Name string `json:"name"`
+
+	// The kind of problem
+	// eg: unknown | deprecated | conflict | requiresDevMode
+	Type string `json:"type"`
+
+	// Description of the problem
+	Message string `json:"message"`
+}
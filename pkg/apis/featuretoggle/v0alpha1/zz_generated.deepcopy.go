@@ -213,3 +213,49 @@ func (in *ToggleStatus) DeepCopy() *ToggleStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToggleValidationProblem) DeepCopyInto(out *ToggleValidationProblem) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToggleValidationProblem.
+func (in *ToggleValidationProblem) DeepCopy() *ToggleValidationProblem {
+	if in == nil {
+		return nil
+	}
+	out := new(ToggleValidationProblem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToggleValidationReport) DeepCopyInto(out *ToggleValidationReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Problems != nil {
+		in, out := &in.Problems, &out.Problems
+		*out = make([]ToggleValidationProblem, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToggleValidationReport.
+func (in *ToggleValidationReport) DeepCopy() *ToggleValidationReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ToggleValidationReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ToggleValidationReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -16,13 +16,15 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.Feature":             schema_pkg_apis_featuretoggle_v0alpha1_Feature(ref),
-		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureList":         schema_pkg_apis_featuretoggle_v0alpha1_FeatureList(ref),
-		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureSpec":         schema_pkg_apis_featuretoggle_v0alpha1_FeatureSpec(ref),
-		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureToggles":      schema_pkg_apis_featuretoggle_v0alpha1_FeatureToggles(ref),
-		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureTogglesList":  schema_pkg_apis_featuretoggle_v0alpha1_FeatureTogglesList(ref),
-		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ResolvedToggleState": schema_pkg_apis_featuretoggle_v0alpha1_ResolvedToggleState(ref),
-		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ToggleStatus":        schema_pkg_apis_featuretoggle_v0alpha1_ToggleStatus(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.Feature":                 schema_pkg_apis_featuretoggle_v0alpha1_Feature(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureList":             schema_pkg_apis_featuretoggle_v0alpha1_FeatureList(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureSpec":             schema_pkg_apis_featuretoggle_v0alpha1_FeatureSpec(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureToggles":          schema_pkg_apis_featuretoggle_v0alpha1_FeatureToggles(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.FeatureTogglesList":      schema_pkg_apis_featuretoggle_v0alpha1_FeatureTogglesList(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ResolvedToggleState":     schema_pkg_apis_featuretoggle_v0alpha1_ResolvedToggleState(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ToggleStatus":            schema_pkg_apis_featuretoggle_v0alpha1_ToggleStatus(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ToggleValidationProblem": schema_pkg_apis_featuretoggle_v0alpha1_ToggleValidationProblem(ref),
+		"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ToggleValidationReport":  schema_pkg_apis_featuretoggle_v0alpha1_ToggleValidationReport(ref),
 	}
 }
 
@@ -150,6 +152,20 @@ func schema_pkg_apis_featuretoggle_v0alpha1_FeatureSpec(ref common.ReferenceCall
 							Format:      "",
 						},
 					},
+					"expires": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The date (YYYY-MM-DD) the flag should be removed, or the feature reach GA",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expired": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The flag is past its expiry date",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"requiresDevMode": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Must be run using in development mode (early dev)",
@@ -428,3 +444,84 @@ func schema_pkg_apis_featuretoggle_v0alpha1_ToggleStatus(ref common.ReferenceCal
 			"github.com/grafana/grafana/pkg/apis/common/v0alpha1.ObjectReference"},
 	}
 }
+
+func schema_pkg_apis_featuretoggle_v0alpha1_ToggleValidationProblem(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The feature toggle name, as it is written in the configuration",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The kind of problem eg: unknown | deprecated | conflict | requiresDevMode",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description of the problem",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "type", "message"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_featuretoggle_v0alpha1_ToggleValidationReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ToggleValidationReport lists the problems of the feature toggle configuration detected at startup",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"problems": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The problems of the configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ToggleValidationProblem"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"problems"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ToggleValidationProblem"},
+	}
+}
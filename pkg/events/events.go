@@ -19,6 +19,11 @@ type OrgUpdated struct {
 	Name      string    `json:"name"`
 }
 
+type OrgDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	Id        int64     `json:"id"`
+}
+
 type UserCreated struct {
 	Timestamp time.Time `json:"timestamp"`
 	Id        int64     `json:"id"`
@@ -78,3 +83,9 @@ type FolderTitleUpdated struct {
 	UID       string    `json:"uid"`
 	OrgID     int64     `json:"org_id"`
 }
+
+type FolderDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	UIDs      []string  `json:"uids"`
+	OrgID     int64     `json:"org_id"`
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"go.opentelemetry.io/otel/attribute"
 	"gonum.org/v1/gonum/graph/simple"
 	"gonum.org/v1/gonum/graph/topo"
@@ -60,6 +64,9 @@ type DataPipeline []Node
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
+	// partial holds the refIDs of the failed queries and expressions that are missing from the results of the nodes
+	// that were executed with partial results.
+	partial := make(map[string][]string)
 
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
 	// Execute datasource nodes first, and grouped by datasource.
@@ -80,26 +87,20 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			continue // already executed via executeDSNodesGrouped
 		}
 
-		// Don't execute nodes that have dependent nodes that have failed
-		var hasDepError bool
-		for _, neededVar := range node.NeedsVars() {
-			if res, ok := vars[neededVar]; ok {
-				if res.Error != nil {
-					errResult := mathexp.Results{
-						Error: makeDependencyError(node.RefID(), neededVar),
-					}
-					vars[node.RefID()] = errResult
-					hasDepError = true
-					break
-				}
+		// Don't execute nodes that have dependent nodes that have failed, unless the node accepts partial results
+		nodeVars, missing, err := dependencyVars(node, vars, partial)
+		if err != nil {
+			vars[node.RefID()] = mathexp.Results{
+				Error: err,
 			}
-		}
-		if hasDepError {
 			continue
 		}
 
 		c, span := s.tracer.Start(c, "SSE.ExecuteNode")
-		span.SetAttributes(attribute.String("node.refId", node.RefID()))
+		span.SetAttributes(
+			attribute.String("node.refId", node.RefID()),
+			attribute.String("node.type", node.NodeType().String()),
+		)
 		if len(node.NeedsVars()) > 0 {
 			inputRefIDs := node.NeedsVars()
 			span.SetAttributes(attribute.StringSlice("node.inputRefIDs", inputRefIDs))
@@ -111,9 +112,12 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
-		res, err := execNode.Execute(c, now, vars, s)
+		res, err := execNode.Execute(c, now, nodeVars, s)
 		if err != nil {
 			res.Error = err
+		} else if len(missing) > 0 {
+			partial[node.RefID()] = missing
+			addPartialResultsNotice(res, missing)
 		}
 
 		vars[node.RefID()] = res
@@ -121,6 +125,77 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 	return vars, nil
 }
 
+// dependencyVars returns the variables the node is executed with, and the refIDs of the failed queries and
+// expressions its results are missing. If an input of the node failed, it returns a dependency error, unless the
+// node accepts partial results and at least one of its inputs did not fail. In that case, the failed inputs are
+// replaced with no data.
+func dependencyVars(node Node, vars mathexp.Vars, partial map[string][]string) (mathexp.Vars, []string, error) {
+	cmdNode, ok := node.(*CMDNode)
+	allowPartial := ok && cmdNode.partialResults
+
+	var failed, missing []string
+	inputs := make(map[string]struct{})
+	for _, neededVar := range node.NeedsVars() {
+		res, ok := vars[neededVar]
+		if !ok {
+			continue
+		}
+		if _, ok := inputs[neededVar]; ok {
+			continue
+		}
+		inputs[neededVar] = struct{}{}
+		if res.Error != nil {
+			if !allowPartial {
+				return nil, nil, makeDependencyError(node.RefID(), neededVar)
+			}
+			failed = append(failed, neededVar)
+			continue
+		}
+		missing = appendMissing(missing, partial[neededVar]...)
+	}
+	if len(failed) == 0 {
+		return vars, missing, nil
+	}
+	if len(failed) == len(inputs) {
+		return nil, nil, makeDependencyError(node.RefID(), failed[0])
+	}
+
+	nodeVars := make(mathexp.Vars, len(vars))
+	for refID, res := range vars {
+		nodeVars[refID] = res
+	}
+	for _, refID := range failed {
+		nodeVars[refID] = mathexp.Results{Values: mathexp.Values{mathexp.NoData{}.New()}}
+	}
+	return nodeVars, appendMissing(missing, failed...), nil
+}
+
+// appendMissing appends the refIDs that are not in the slice yet.
+func appendMissing(missing []string, refIDs ...string) []string {
+	for _, refID := range refIDs {
+		if !slices.Contains(missing, refID) {
+			missing = append(missing, refID)
+		}
+	}
+	return missing
+}
+
+// addPartialResultsNotice adds a warning to every value of the results about the failed queries and expressions
+// the results are missing. Values can be passed through from the inputs of the node, so the warning is not added
+// again to values that already have it.
+func addPartialResultsNotice(res mathexp.Results, missing []string) {
+	notice := data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("Results are partial: %s failed and were treated as no data", strings.Join(missing, ", ")),
+	}
+	for _, v := range res.Values {
+		if frame := v.AsDataFrame(); frame != nil && frame.Meta != nil && slices.Contains(frame.Meta.Notices, notice) {
+			continue
+		}
+		v.AddNotice(notice)
+	}
+}
+
 // BuildPipeline builds a graph of the nodes, and returns the nodes in an
 // executable order.
 func (s *Service) buildPipeline(req *Request) (DataPipeline, error) {
@@ -225,7 +300,12 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 		case TypeDatasourceNode:
 			node, err = s.buildDSNode(dp, rn, req)
 		case TypeCMDNode:
-			node, err = buildCMDNode(rn, s.features)
+			var cmdNode *CMDNode
+			cmdNode, err = buildCMDNode(rn, s.features)
+			if err == nil {
+				cmdNode.partialResults = req.PartialResults
+				node = cmdNode
+			}
 		case TypeMLNode:
 			if s.features.IsEnabledGlobally(featuremgmt.FlagMlExpressions) {
 				node, err = s.buildMLNode(dp, rn, req)
@@ -0,0 +1,74 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// convertLongFramesKey is the property of the query model that disables the conversion of long frames to wide frames.
+const convertLongFramesKey = "convertLongFrames"
+
+// convertLongFrames converts the long time series frames of a data source, e.g. the results of SQL queries in table
+// format with a time column, to wide frames, so that they can be used by expressions. The labels of the series are
+// inferred from the string and boolean columns: every distinct combination of their values becomes a series of every
+// numeric column. Frames that are not long time series, or that are dataplane frames, are returned unchanged.
+func convertLongFrames(frames data.Frames) (data.Frames, error) {
+	converted := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		if frame == nil || isDataplaneFrame(frame) || frame.TimeSeriesSchema().Type != data.TimeSeriesTypeLong {
+			converted = append(converted, frame)
+			continue
+		}
+		if frame.Rows() == 0 {
+			// there is nothing to convert, and LongToWide fails on empty frames
+			converted = append(converted, frame)
+			continue
+		}
+
+		wide, err := data.LongToWide(sortLongFrameByTime(frame), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert long frame %q to wide frame: %w", frame.Name, err)
+		}
+		converted = append(converted, wide)
+	}
+	return converted, nil
+}
+
+func isDataplaneFrame(frame *data.Frame) bool {
+	return frame.Meta != nil && frame.Meta.Type != "" && !frame.Meta.TypeVersion.IsZero()
+}
+
+// sortLongFrameByTime returns the frame with its rows sorted ascending by time, as required by LongToWide. The frame
+// is returned as is if it is already sorted.
+func sortLongFrameByTime(frame *data.Frame) *data.Frame {
+	timeIdx := frame.TimeSeriesSchema().TimeIndex
+	timeAt := func(row int) time.Time {
+		switch v := frame.Fields[timeIdx].At(row).(type) {
+		case time.Time:
+			return v
+		case *time.Time:
+			if v != nil {
+				return *v
+			}
+		}
+		return time.Time{}
+	}
+
+	rows := make([]int, frame.Rows())
+	for i := range rows {
+		rows[i] = i
+	}
+	if sort.SliceIsSorted(rows, func(i, j int) bool { return timeAt(rows[i]).Before(timeAt(rows[j])) }) {
+		return frame
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return timeAt(rows[i]).Before(timeAt(rows[j])) })
+
+	sorted := frame.EmptyCopy()
+	for _, row := range rows {
+		sorted.AppendRow(frame.RowCopy(row)...)
+	}
+	return sorted
+}
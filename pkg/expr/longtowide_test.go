@@ -0,0 +1,88 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestConvertLongFrames(t *testing.T) {
+	t1 := time.Unix(60, 0).UTC()
+	t2 := time.Unix(120, 0).UTC()
+
+	t.Run("should convert long frames of SQL queries to series", func(t *testing.T) {
+		frames := data.Frames{data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t2, t1, t1, t2}),
+			data.NewField("host", nil, []string{"a", "a", "b", "b"}),
+			data.NewField("value", nil, []float64{2, 1, 3, 4}),
+		)}
+
+		converted, err := convertLongFrames(frames)
+		require.NoError(t, err)
+		require.Len(t, converted, 1)
+		require.Equal(t, data.TimeSeriesTypeWide, converted[0].TimeSeriesSchema().Type)
+
+		s := &Service{
+			cfg:      setting.NewCfg(),
+			features: &featuremgmt.FeatureManager{},
+			tracer:   tracing.InitializeTracerForTest(),
+			metrics:  newMetrics(nil),
+		}
+		_, res, err := convertDataFramesToResults(context.Background(), converted, datasources.DS_MYSQL, s, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+
+		a := res.Values[0].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a"}, a.GetLabels())
+		require.Equal(t, 2, a.Len())
+		_, v := a.GetPoint(0)
+		require.Equal(t, 1.0, *v)
+
+		b := res.Values[1].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "b"}, b.GetLabels())
+		_, v = b.GetPoint(1)
+		require.Equal(t, 4.0, *v)
+	})
+
+	t.Run("should not change wide, table and dataplane frames", func(t *testing.T) {
+		wide := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t1}),
+			data.NewField("value", data.Labels{"host": "a"}, []float64{1}),
+		)
+		table := data.NewFrame("",
+			data.NewField("host", nil, []string{"a"}),
+			data.NewField("value", nil, []float64{1}),
+		)
+		dataplane := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t1}),
+			data.NewField("host", nil, []string{"a"}),
+			data.NewField("value", nil, []float64{1}),
+		).SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesLong, TypeVersion: data.FrameTypeVersion{0, 1}})
+
+		frames := data.Frames{wide, table, dataplane}
+		converted, err := convertLongFrames(frames)
+		require.NoError(t, err)
+		require.Equal(t, frames, converted)
+	})
+
+	t.Run("should not convert empty long frames", func(t *testing.T) {
+		empty := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{}),
+			data.NewField("host", nil, []string{}),
+			data.NewField("value", nil, []float64{}),
+		)
+		converted, err := convertLongFrames(data.Frames{empty})
+		require.NoError(t, err)
+		require.Same(t, empty, converted[0])
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -69,6 +70,9 @@ type CMDNode struct {
 	baseNode
 	CMDType CommandType
 	Command Command
+
+	// partialResults is true if the node is executed when some, but not all, of its inputs failed.
+	partialResults bool
 }
 
 // ID returns the id of the node so it can fulfill the gonum's graph Node interface.
@@ -149,6 +153,8 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+	// convertLongFrames enables the conversion of the long time series frames of the data source to wide frames
+	convertLongFrames bool
 }
 
 // NodeType returns the data pipeline node type.
@@ -183,6 +189,14 @@ func (s *Service) buildDSNode(dp *simple.DirectedGraph, rn *rawNode, req *Reques
 		timeRange:  rn.TimeRange,
 		request:    *req,
 		datasource: rn.DataSource,
+
+		convertLongFrames: true,
+	}
+
+	if rawConvert, ok := rn.Query[convertLongFramesKey]; ok {
+		if dsNode.convertLongFrames, ok = rawConvert.(bool); !ok {
+			return nil, fmt.Errorf("expected %s to be a bool, got type %T for refId %v", convertLongFramesKey, rawConvert, rn.RefID)
+		}
 	}
 
 	var floatIntervalMS float64
@@ -237,9 +251,14 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 				"datasourceVersion", firstNode.datasource.Version,
 			)
 
+			refIDs := make([]string, 0, len(nodeGroup))
+			for _, dn := range nodeGroup {
+				refIDs = append(refIDs, dn.refID)
+			}
 			span.SetAttributes(
 				attribute.String("datasource.type", firstNode.datasource.Type),
 				attribute.String("datasource.uid", firstNode.datasource.UID),
+				attribute.StringSlice("query.refIds", refIDs),
 			)
 
 			req := &backend.QueryDataRequest{
@@ -289,6 +308,14 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 					return
 				}
 
+				if dn.convertLongFrames {
+					if dataFrames, err = convertLongFrames(dataFrames); err != nil {
+						vars[dn.refID] = mathexp.Results{Error: makeConversionError(dn.RefID(), err)}
+						instrument(err, "")
+						return
+					}
+				}
+
 				var result mathexp.Results
 				responseType, result, err := convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 				if err != nil {
@@ -316,6 +343,7 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 	span.SetAttributes(
 		attribute.String("datasource.type", dn.datasource.Type),
 		attribute.String("datasource.uid", dn.datasource.UID),
+		attribute.String("query.refId", dn.refID),
 	)
 
 	req := &backend.QueryDataRequest{
@@ -357,6 +385,12 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
 	}
 
+	if dn.convertLongFrames {
+		if dataFrames, err = convertLongFrames(dataFrames); err != nil {
+			return mathexp.Results{}, makeConversionError(dn.refID, err)
+		}
+	}
+
 	var result mathexp.Results
 	responseType, result, err = convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 	if err != nil {
@@ -410,21 +444,26 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 		if len(frame.Fields) == 0 {
 			return "no-data", mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, nil
 		}
+	}
 
-		// Handle Numeric Table
-		if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeNot && isNumberTable(frame) {
+	// Handle Numeric Tables, e.g. the results of SQL queries without a time column
+	if isNumberTables(frames) {
+		var vals []mathexp.Value
+		for _, frame := range frames {
 			numberSet, err := extractNumberSet(frame)
 			if err != nil {
 				return "", mathexp.Results{}, err
 			}
-			vals := make([]mathexp.Value, 0, len(numberSet))
 			for _, n := range numberSet {
 				vals = append(vals, n)
 			}
-			return "number set", mathexp.Results{
-				Values: vals,
-			}, nil
 		}
+		if len(vals) == 0 {
+			return "no data", mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frames[0]}}}, nil
+		}
+		return "number set", mathexp.Results{
+			Values: vals,
+		}, nil
 	}
 
 	filtered := make([]*data.Frame, 0, len(frames))
@@ -515,62 +554,110 @@ func framesToNumbers(frames data.Frames) ([]mathexp.Value, error) {
 	return vals, nil
 }
 
+// isNumberTables returns true if all frames are number tables.
+func isNumberTables(frames data.Frames) bool {
+	for _, frame := range frames {
+		if frame == nil || frame.TimeSeriesSchema().Type != data.TimeSeriesTypeNot || !isNumberTable(frame) {
+			return false
+		}
+	}
+	return len(frames) > 0
+}
+
+// isNumberTable returns true if the frame is a table of one or more numeric columns, and any number of
+// string or boolean columns that are the dimensions of the rows.
 func isNumberTable(frame *data.Frame) bool {
 	if frame == nil || frame.Fields == nil {
 		return false
 	}
 	numericCount := 0
-	stringCount := 0
 	otherCount := 0
 	for _, field := range frame.Fields {
-		fType := field.Type()
 		switch {
-		case fType.Numeric():
+		case field.Type().Numeric():
 			numericCount++
-		case fType == data.FieldTypeString || fType == data.FieldTypeNullableString:
-			stringCount++
+		case isDimensionField(field):
 		default:
 			otherCount++
 		}
 	}
-	return numericCount == 1 && otherCount == 0
+	return numericCount > 0 && otherCount == 0
 }
 
+// isDimensionField returns true if the values of the field can be used as labels.
+func isDimensionField(field *data.Field) bool {
+	switch field.Type() {
+	case data.FieldTypeString, data.FieldTypeNullableString, data.FieldTypeBool, data.FieldTypeNullableBool:
+		return true
+	default:
+		return false
+	}
+}
+
+// extractNumberSet returns a number for every numeric column of every row of a number table. The labels of a number
+// are the values of the dimension columns of its row; null dimensions are omitted. If the table has more than one
+// numeric column, the name of the column is added as the __name__ label so that the numbers of a row are unique.
 func extractNumberSet(frame *data.Frame) ([]mathexp.Number, error) {
-	numericField := 0
-	stringFieldIdxs := []int{}
-	stringFieldNames := []string{}
+	numericFieldIdxs := []int{}
+	dimensionFieldIdxs := []int{}
+	dimensions := make(map[string]struct{})
 	for i, field := range frame.Fields {
-		fType := field.Type()
 		switch {
-		case fType.Numeric():
-			numericField = i
-		case fType == data.FieldTypeString || fType == data.FieldTypeNullableString:
-			stringFieldIdxs = append(stringFieldIdxs, i)
-			stringFieldNames = append(stringFieldNames, field.Name)
+		case field.Type().Numeric():
+			numericFieldIdxs = append(numericFieldIdxs, i)
+		case isDimensionField(field):
+			if _, ok := dimensions[field.Name]; ok {
+				return nil, fmt.Errorf("duplicate dimension column %q in table", field.Name)
+			}
+			dimensions[field.Name] = struct{}{}
+			dimensionFieldIdxs = append(dimensionFieldIdxs, i)
 		}
 	}
-	numbers := make([]mathexp.Number, frame.Rows())
+	numbers := make([]mathexp.Number, 0, frame.Rows()*len(numericFieldIdxs))
 
 	for rowIdx := 0; rowIdx < frame.Rows(); rowIdx++ {
-		val, _ := frame.FloatAt(numericField, rowIdx)
-		var labels data.Labels
-		for i := 0; i < len(stringFieldIdxs); i++ {
-			if i == 0 {
-				labels = make(data.Labels)
+		labels := make(data.Labels, len(dimensionFieldIdxs))
+		for _, idx := range dimensionFieldIdxs {
+			key := frame.Fields[idx].Name
+			val, ok := frame.ConcreteAt(idx, rowIdx)
+			if !ok {
+				continue
+			}
+			switch v := val.(type) {
+			case string:
+				labels[key] = v
+			case bool:
+				labels[key] = strconv.FormatBool(v)
 			}
-			key := stringFieldNames[i] // TODO check for duplicate string column names
-			val, _ := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
-			labels[key] = val.(string) // TODO check assertion / return error
 		}
 
-		n := mathexp.NewNumber(frame.Fields[numericField].Name, labels)
+		for _, idx := range numericFieldIdxs {
+			field := frame.Fields[idx]
+			numberLabels := labels
+			if len(numericFieldIdxs) > 1 {
+				numberLabels = labels.Copy()
+				numberLabels[nameLabelName] = field.Name
+			}
+			if len(numberLabels) == 0 {
+				numberLabels = nil
+			}
 
-		// The new value fields' configs gets pointed to the one in the original frame
-		n.Frame.Fields[0].Config = frame.Fields[numericField].Config
-		n.SetValue(&val)
+			n := mathexp.NewNumber(field.Name, numberLabels)
 
-		numbers[rowIdx] = n
+			// The new value fields' configs gets pointed to the one in the original frame
+			n.Frame.Fields[0].Config = field.Config
+			if _, ok := field.ConcreteAt(rowIdx); ok {
+				val, err := field.FloatAt(rowIdx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read value of column %q as float: %w", field.Name, err)
+				}
+				n.SetValue(&val)
+			} else {
+				n.SetValue(nil)
+			}
+
+			numbers = append(numbers, n)
+		}
 	}
 	return numbers, nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 type expectedError struct{}
@@ -271,4 +272,81 @@ func TestConvertDataFramesToResults(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("should convert tables without time column to numbers", func(t *testing.T) {
+		t.Run("with a number per row", func(t *testing.T) {
+			frames := []*data.Frame{
+				data.NewFrame("",
+					data.NewField("host", nil, []string{"a", "b"}),
+					data.NewField("region", nil, []*string{util.Pointer("eu"), nil}),
+					data.NewField("up", nil, []bool{true, false}),
+					data.NewField("cpu", nil, []*float64{fp(1), nil})),
+			}
+			resultType, res, err := convertDataFramesToResults(context.Background(), frames, datasources.DS_MYSQL, s, &logtest.Fake{})
+			require.NoError(t, err)
+			assert.Equal(t, "number set", resultType)
+			require.Len(t, res.Values, 2)
+
+			require.IsType(t, mathexp.Number{}, res.Values[0])
+			require.Equal(t, data.Labels{"host": "a", "region": "eu", "up": "true"}, res.Values[0].GetLabels())
+			require.Equal(t, fp(1), res.Values[0].(mathexp.Number).GetFloat64Value())
+			require.Equal(t, data.Labels{"host": "b", "up": "false"}, res.Values[1].GetLabels())
+			require.Nil(t, res.Values[1].(mathexp.Number).GetFloat64Value())
+		})
+
+		t.Run("with several numbers per row", func(t *testing.T) {
+			frames := []*data.Frame{
+				data.NewFrame("",
+					data.NewField("host", nil, []string{"a", "b"}),
+					data.NewField("cpu", nil, []float64{1, 2}),
+					data.NewField("mem", nil, []int64{3, 4})),
+			}
+			_, res, err := convertDataFramesToResults(context.Background(), frames, datasources.DS_POSTGRES, s, &logtest.Fake{})
+			require.NoError(t, err)
+			require.Len(t, res.Values, 4)
+
+			var labels []data.Labels
+			for _, v := range res.Values {
+				labels = append(labels, v.GetLabels())
+			}
+			require.Equal(t, []data.Labels{
+				{"host": "a", nameLabelName: "cpu"},
+				{"host": "a", nameLabelName: "mem"},
+				{"host": "b", nameLabelName: "cpu"},
+				{"host": "b", nameLabelName: "mem"},
+			}, labels)
+			require.Equal(t, fp(4), res.Values[3].(mathexp.Number).GetFloat64Value())
+		})
+
+		t.Run("from several frames", func(t *testing.T) {
+			frames := []*data.Frame{
+				data.NewFrame("", data.NewField("host", nil, []string{"a"}), data.NewField("cpu", nil, []float64{1})),
+				data.NewFrame("", data.NewField("host", nil, []string{"b"}), data.NewField("cpu", nil, []float64{2})),
+			}
+			resultType, res, err := convertDataFramesToResults(context.Background(), frames, datasources.DS_MSSQL, s, &logtest.Fake{})
+			require.NoError(t, err)
+			assert.Equal(t, "number set", resultType)
+			require.Len(t, res.Values, 2)
+		})
+
+		t.Run("with no rows", func(t *testing.T) {
+			frames := []*data.Frame{
+				data.NewFrame("", data.NewField("host", nil, []string{}), data.NewField("cpu", nil, []float64{})),
+			}
+			_, res, err := convertDataFramesToResults(context.Background(), frames, datasources.DS_MYSQL, s, &logtest.Fake{})
+			require.NoError(t, err)
+			require.True(t, res.IsNoData())
+		})
+
+		t.Run("fails with duplicate dimension columns", func(t *testing.T) {
+			frames := []*data.Frame{
+				data.NewFrame("",
+					data.NewField("host", nil, []string{"a"}),
+					data.NewField("host", nil, []string{"b"}),
+					data.NewField("cpu", nil, []float64{1})),
+			}
+			_, _, err := convertDataFramesToResults(context.Background(), frames, datasources.DS_MYSQL, s, &logtest.Fake{})
+			require.ErrorContains(t, err, "duplicate dimension column")
+		})
+	})
 }
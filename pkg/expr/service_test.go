@@ -180,6 +180,97 @@ func TestDSQueryError(t *testing.T) {
 	require.Equal(t, fp(42), resp.Responses["C"].Frames[0].Fields[0].At(0))
 }
 
+func TestPartialResults(t *testing.T) {
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Error: fmt.Errorf("womp womp")},
+			"B": {Frames: data.Frames{data.NewFrame("",
+				data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+				data.NewField("value", nil, []float64{2}),
+			)}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeCacheService{}, &datafakes.FakeDataSourceService{}, nil, nil, &config.Cfg{})
+
+	s := Service{
+		cfg:          setting.NewCfg(),
+		dataService:  me,
+		pCtxProvider: pCtxProvider,
+		features:     &featuremgmt.FeatureManager{},
+		tracer:       tracing.InitializeTracerForTest(),
+		metrics:      newMetrics(nil),
+	}
+
+	dsQuery := func(refID string) Query {
+		return Query{
+			RefID: refID,
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{
+				From: time.Time{},
+				To:   time.Time{},
+			},
+		}
+	}
+	mathQuery := func(refID, expression string) Query {
+		return Query{
+			RefID:      refID,
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "` + expression + `" }`),
+		}
+	}
+	queries := []Query{
+		dsQuery("A"),
+		dsQuery("B"),
+		mathQuery("C", "$A + $B"),
+		mathQuery("D", "$C * 2"),
+		mathQuery("E", "$A * 2"),
+	}
+
+	execute := func(t *testing.T, partialResults bool) *backend.QueryDataResponse {
+		t.Helper()
+		pl, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{}, PartialResults: partialResults})
+		require.NoError(t, err)
+		resp, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("expressions fail if any input failed when partial results are disabled", func(t *testing.T) {
+		resp := execute(t, false)
+		require.ErrorIs(t, resp.Responses["C"].Error, DependencyError)
+		require.ErrorIs(t, resp.Responses["D"].Error, DependencyError)
+	})
+
+	t.Run("expressions are executed without the failed inputs when partial results are enabled", func(t *testing.T) {
+		resp := execute(t, true)
+		require.ErrorContains(t, resp.Responses["A"].Error, "womp womp")
+		for _, refID := range []string{"C", "D"} {
+			r := resp.Responses[refID]
+			require.NoError(t, r.Error)
+			require.Len(t, r.Frames, 1)
+			require.NotNil(t, r.Frames[0].Meta)
+			require.Len(t, r.Frames[0].Meta.Notices, 1)
+			require.Equal(t, data.NoticeSeverityWarning, r.Frames[0].Meta.Notices[0].Severity)
+			require.Contains(t, r.Frames[0].Meta.Notices[0].Text, "A failed")
+		}
+	})
+
+	t.Run("expressions fail if all inputs failed when partial results are enabled", func(t *testing.T) {
+		resp := execute(t, true)
+		require.ErrorIs(t, resp.Responses["E"].Error, DependencyError)
+	})
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
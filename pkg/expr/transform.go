@@ -19,6 +19,9 @@ type Request struct {
 	OrgId   int64
 	Queries []Query
 	User    identity.Requester
+	// PartialResults allows expressions with several inputs to be executed when some, but not all, of their
+	// inputs failed. The failed inputs are replaced with no data, and the results are marked with a notice.
+	PartialResults bool
 }
 
 // Query is like plugins.DataSubQuery, but with a a time range, and only the UID
@@ -113,6 +113,18 @@ var (
 
 	// MFolderIDsServicesCount is a metric counter for folder ids count in the services package
 	MFolderIDsServiceCount *prometheus.CounterVec
+
+	// MAlertingReceiverSecretsReads is a metric counter for reads of decrypted receiver secure settings labelled by result and granting action
+	MAlertingReceiverSecretsReads *prometheus.CounterVec
+
+	// MDataSourceProxyRateLimited is a metric counter for data proxy requests rejected by the rate limiter
+	MDataSourceProxyRateLimited *prometheus.CounterVec
+
+	// MDataSourceProxyCircuitOpened is a metric counter for the number of times the circuit of a data source was opened
+	MDataSourceProxyCircuitOpened *prometheus.CounterVec
+
+	// MDataSourceProxyCircuitRejected is a metric counter for data proxy requests rejected because the circuit is open
+	MDataSourceProxyCircuitRejected *prometheus.CounterVec
 )
 
 // Timers
@@ -480,6 +492,30 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"service"}, map[string][]string{"service": folderIDServices})
 
+	MAlertingReceiverSecretsReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "alerting_receiver_secrets_reads_total",
+		Help:      "counter for reads of decrypted receiver secure settings",
+		Namespace: ExporterName,
+	}, []string{"result", "action"})
+
+	MDataSourceProxyRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "datasource_proxy_rate_limited_total",
+		Help:      "counter for data proxy requests rejected by the rate limiter labelled by datasource type",
+		Namespace: ExporterName,
+	}, []string{"datasource_type"})
+
+	MDataSourceProxyCircuitOpened = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "datasource_proxy_circuit_opened_total",
+		Help:      "counter for the number of times the data proxy circuit breaker opened labelled by datasource type",
+		Namespace: ExporterName,
+	}, []string{"datasource_type"})
+
+	MDataSourceProxyCircuitRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "datasource_proxy_circuit_rejected_total",
+		Help:      "counter for data proxy requests rejected by an open circuit breaker labelled by datasource type",
+		Namespace: ExporterName,
+	}, []string{"datasource_type"})
+
 	MStatTotalDashboards = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name:      "stat_totals_dashboard",
 		Help:      "total amount of dashboards",
@@ -777,5 +813,9 @@ func initMetricVars(reg prometheus.Registerer) {
 		MStatTotalCorrelations,
 		MFolderIDsAPICount,
 		MFolderIDsServiceCount,
+		MAlertingReceiverSecretsReads,
+		MDataSourceProxyRateLimited,
+		MDataSourceProxyCircuitOpened,
+		MDataSourceProxyCircuitRejected,
 	)
 }
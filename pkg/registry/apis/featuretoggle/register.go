@@ -53,6 +53,7 @@ func addKnownTypes(scheme *runtime.Scheme, gv schema.GroupVersion) {
 		&v0alpha1.FeatureToggles{},
 		&v0alpha1.FeatureTogglesList{},
 		&v0alpha1.ResolvedToggleState{},
+		&v0alpha1.ToggleValidationReport{},
 	)
 }
 
@@ -83,12 +84,18 @@ func (b *FeatureFlagAPIBuilder) GetAPIGroupInfo(
 ) (*genericapiserver.APIGroupInfo, error) {
 	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(v0alpha1.GROUP, scheme, metav1.ParameterCodec, codecs)
 
-	featureStore := NewFeaturesStorage(b.features.GetFlags())
-	toggleStore := NewTogglesStorage(b.features)
+	featureStore, err := NewFeaturesStorage(b.features.GetFlags())
+	if err != nil {
+		return nil, err
+	}
+	toggleStore, err := NewTogglesStorage(b.features)
+	if err != nil {
+		return nil, err
+	}
 
 	storage := map[string]rest.Storage{}
-	storage[featureStore.resource.StoragePath()] = featureStore
-	storage[toggleStore.resource.StoragePath()] = toggleStore
+	storage[v0alpha1.FeatureResourceInfo.StoragePath()] = featureStore
+	storage[v0alpha1.TogglesResourceInfo.StoragePath()] = toggleStore
 
 	apiGroupInfo.VersionedResourcesStorageMap[v0alpha1.VERSION] = storage
 	return &apiGroupInfo, nil
@@ -106,6 +113,7 @@ func (b *FeatureFlagAPIBuilder) GetAuthorizer() authorizer.Authorizer {
 func (b *FeatureFlagAPIBuilder) GetAPIRoutes() *builder.APIRoutes {
 	defs := v0alpha1.GetOpenAPIDefinitions(func(path string) spec.Ref { return spec.Ref{} })
 	stateSchema := defs["github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ResolvedToggleState"].Schema
+	validationSchema := defs["github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1.ToggleValidationReport"].Schema
 
 	tags := []string{"Editor"}
 	return &builder.APIRoutes{
@@ -205,6 +213,37 @@ func (b *FeatureFlagAPIBuilder) GetAPIRoutes() *builder.APIRoutes {
 				},
 				Handler: b.handleCurrentStatus,
 			},
+			{
+				Path: "validation",
+				Spec: &spec3.PathProps{
+					Get: &spec3.Operation{
+						OperationProps: spec3.OperationProps{
+							Tags:        tags,
+							Summary:     "Configuration problems",
+							Description: "Show the problems of the toggle configuration detected at startup",
+							Responses: &spec3.Responses{
+								ResponsesProps: spec3.ResponsesProps{
+									StatusCodeResponses: map[int]*spec3.Response{
+										200: {
+											ResponseProps: spec3.ResponseProps{
+												Content: map[string]*spec3.MediaType{
+													"application/json": {
+														MediaTypeProps: spec3.MediaTypeProps{
+															Schema: &validationSchema,
+														},
+													},
+												},
+												Description: "OK",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Handler: b.handleValidationReport,
+			},
 		},
 	}
 }
@@ -0,0 +1,33 @@
+package featuretoggle
+
+import (
+	"encoding/json"
+	"net/http"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1"
+)
+
+func (b *FeatureFlagAPIBuilder) getValidationReport() v0alpha1.ToggleValidationReport {
+	report := v0alpha1.ToggleValidationReport{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: v0alpha1.APIVERSION,
+			Kind:       "ToggleValidationReport",
+		},
+		Problems: []v0alpha1.ToggleValidationProblem{},
+	}
+	for _, p := range b.features.GetValidationProblems() {
+		report.Problems = append(report.Problems, v0alpha1.ToggleValidationProblem{
+			Name:    p.Name,
+			Type:    p.Type,
+			Message: p.Message,
+		})
+	}
+	return report
+}
+
+// The problems are detected once at startup, so the report is read-only
+func (b *FeatureFlagAPIBuilder) handleValidationReport(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(b.getValidationReport())
+}
@@ -0,0 +1,389 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	common "github.com/grafana/grafana/pkg/apis/common/v0alpha1"
+)
+
+var (
+	_ rest.Storage              = (*ReadOnlyStore)(nil)
+	_ rest.Scoper               = (*ReadOnlyStore)(nil)
+	_ rest.SingularNameProvider = (*ReadOnlyStore)(nil)
+	_ rest.Getter               = (*ReadOnlyStore)(nil)
+	_ rest.Lister               = (*ReadOnlyStore)(nil)
+	_ rest.Watcher              = (*ReadOnlyStore)(nil)
+
+	_ rest.Creater         = (*Store)(nil)
+	_ rest.Updater         = (*Store)(nil)
+	_ rest.GracefulDeleter = (*Store)(nil)
+)
+
+// The number of events that are queued for each watcher before they are dropped
+const watchQueueLength = 100
+
+// ReadOnlyStore keeps the objects of a resource in memory, and serves them with get, list and watch.
+// Registries add the objects at startup, or embed the Store to also support writes.
+type ReadOnlyStore struct {
+	resource       *common.ResourceInfo
+	tableConverter rest.TableConvertor
+	namespaced     bool
+
+	mu sync.RWMutex
+	// objects keyed by namespace/name
+	objects map[string]runtime.Object
+	// the resource version of the last change, shared by all objects
+	rv          int64
+	broadcaster *watch.Broadcaster
+}
+
+// NewReadOnlyStore creates an empty store. The default table converter is used when tableConverter is nil.
+func NewReadOnlyStore(resource common.ResourceInfo, namespaced bool, tableConverter rest.TableConvertor) *ReadOnlyStore {
+	if tableConverter == nil {
+		tableConverter = rest.NewDefaultTableConvertor(resource.GroupResource())
+	}
+	return &ReadOnlyStore{
+		resource:       &resource,
+		tableConverter: tableConverter,
+		namespaced:     namespaced,
+		objects:        make(map[string]runtime.Object),
+		broadcaster:    watch.NewBroadcaster(watchQueueLength, watch.DropIfChannelFull),
+	}
+}
+
+// Add adds or replaces an object without any validation, eg: to register the objects known at startup
+func (s *ReadOnlyStore) Add(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.objects[s.key(accessor.GetNamespace(), accessor.GetName())]
+	if accessor.GetCreationTimestamp().IsZero() {
+		accessor.SetCreationTimestamp(metav1.Now())
+	}
+	if accessor.GetUID() == "" {
+		accessor.SetUID(uuid.NewUUID())
+	}
+	if exists {
+		s.save(accessor, obj, watch.Modified)
+	} else {
+		s.save(accessor, obj, watch.Added)
+	}
+	return nil
+}
+
+func (s *ReadOnlyStore) New() runtime.Object {
+	return s.resource.NewFunc()
+}
+
+func (s *ReadOnlyStore) Destroy() {
+	s.broadcaster.Shutdown()
+}
+
+func (s *ReadOnlyStore) NamespaceScoped() bool {
+	return s.namespaced
+}
+
+func (s *ReadOnlyStore) GetSingularName() string {
+	return s.resource.GetSingularName()
+}
+
+func (s *ReadOnlyStore) NewList() runtime.Object {
+	return s.resource.NewListFunc()
+}
+
+func (s *ReadOnlyStore) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return s.tableConverter.ConvertToTable(ctx, object, tableOptions)
+}
+
+func (s *ReadOnlyStore) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[s.key(s.namespace(ctx), name)]
+	if !ok {
+		return nil, s.resource.NewNotFound(name)
+	}
+	return obj.DeepCopyObject(), nil
+}
+
+func (s *ReadOnlyStore) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+	matches, err := s.matcher(s.namespace(ctx), options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]runtime.Object, 0, len(keys))
+	for _, k := range keys {
+		obj := s.objects[k]
+		if matches(obj) {
+			items = append(items, obj.DeepCopyObject())
+		}
+	}
+
+	list := s.resource.NewListFunc()
+	if err := meta.SetList(list, items); err != nil {
+		return nil, err
+	}
+	listAccessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return nil, err
+	}
+	listAccessor.SetResourceVersion(strconv.FormatInt(s.rv, 10))
+	return list, nil
+}
+
+// Watch sends the current objects as added, unless a resource version is requested, followed by all the changes.
+// Changes are dropped for watchers that do not keep up with them.
+func (s *ReadOnlyStore) Watch(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+	matches, err := s.matcher(s.namespace(ctx), options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var initial []watch.Event
+	if options == nil || options.ResourceVersion == "" || options.ResourceVersion == "0" {
+		for _, obj := range s.objects {
+			initial = append(initial, watch.Event{Type: watch.Added, Object: obj.DeepCopyObject()})
+		}
+	}
+	// Registering the watcher while holding the lock ensures that no change is missed
+	w, err := s.broadcaster.WatchWithPrefix(initial)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		return in, matches(in.Object)
+	}), nil
+}
+
+// namespace returns the namespace of the request, or an empty string for cluster scoped resources
+func (s *ReadOnlyStore) namespace(ctx context.Context) string {
+	if !s.namespaced {
+		return ""
+	}
+	return request.NamespaceValue(ctx)
+}
+
+func (s *ReadOnlyStore) key(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// matcher returns a function that checks if an object is in the namespace, and matches the selectors of the options.
+// An empty namespace matches all namespaces.
+func (s *ReadOnlyStore) matcher(namespace string, options *internalversion.ListOptions) (func(runtime.Object) bool, error) {
+	labelSelector := labels.Everything()
+	fieldSelector := fields.Everything()
+	if options != nil {
+		if options.LabelSelector != nil {
+			labelSelector = options.LabelSelector
+		}
+		if options.FieldSelector != nil {
+			fieldSelector = options.FieldSelector
+		}
+	}
+	for _, r := range fieldSelector.Requirements() {
+		if r.Field != "metadata.name" && r.Field != "metadata.namespace" {
+			return nil, errors.NewBadRequest(fmt.Sprintf("unsupported field selector: %s", r.Field))
+		}
+	}
+
+	return func(obj runtime.Object) bool {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return false
+		}
+		if namespace != "" && accessor.GetNamespace() != namespace {
+			return false
+		}
+		return labelSelector.Matches(labels.Set(accessor.GetLabels())) &&
+			fieldSelector.Matches(fields.Set{
+				"metadata.name":      accessor.GetName(),
+				"metadata.namespace": accessor.GetNamespace(),
+			})
+	}, nil
+}
+
+// save stores the object with the next resource version and notifies the watchers.
+// It must be called with the lock held.
+func (s *ReadOnlyStore) save(accessor metav1.Object, obj runtime.Object, event watch.EventType) {
+	s.rv++
+	accessor.SetResourceVersion(strconv.FormatInt(s.rv, 10))
+	stored := obj.DeepCopyObject()
+	s.objects[s.key(accessor.GetNamespace(), accessor.GetName())] = stored
+	_ = s.broadcaster.Action(event, stored.DeepCopyObject())
+}
+
+// Store keeps the objects of a resource in memory, and also supports create, update and delete.
+// Updates use optimistic concurrency: they are rejected if they set a resource version that is not the current one.
+type Store struct {
+	*ReadOnlyStore
+}
+
+// NewStore creates an empty store. The default table converter is used when tableConverter is nil.
+func NewStore(resource common.ResourceInfo, namespaced bool, tableConverter rest.TableConvertor) *Store {
+	return &Store{ReadOnlyStore: NewReadOnlyStore(resource, namespaced, tableConverter)}
+}
+
+func (s *Store) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if accessor.GetName() == "" {
+		return nil, errors.NewBadRequest("name is required")
+	}
+	if s.namespaced {
+		accessor.SetNamespace(request.NamespaceValue(ctx))
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.objects[s.key(accessor.GetNamespace(), accessor.GetName())]; exists {
+		return nil, errors.NewAlreadyExists(s.resource.GroupResource(), accessor.GetName())
+	}
+	accessor.SetCreationTimestamp(metav1.Now())
+	accessor.SetUID(uuid.NewUUID())
+	accessor.SetGeneration(1)
+	if options == nil || len(options.DryRun) == 0 {
+		s.save(accessor, obj, watch.Added)
+	}
+	return obj, nil
+}
+
+func (s *Store) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	namespace := s.namespace(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, exists := s.objects[s.key(namespace, name)]
+	if !exists && !forceAllowCreate {
+		return nil, false, s.resource.NewNotFound(name)
+	}
+
+	var old runtime.Object
+	if exists {
+		old = existing.DeepCopyObject()
+	}
+	obj, err := objInfo.UpdatedObject(ctx, old)
+	if err != nil {
+		return nil, false, err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	if accessor.GetName() != name {
+		return nil, false, errors.NewBadRequest(fmt.Sprintf("the name of the object (%s) does not match the name of the request (%s)", accessor.GetName(), name))
+	}
+	accessor.SetNamespace(namespace)
+	dryRun := options != nil && len(options.DryRun) > 0
+
+	if !exists {
+		if createValidation != nil {
+			if err := createValidation(ctx, obj); err != nil {
+				return nil, false, err
+			}
+		}
+		accessor.SetCreationTimestamp(metav1.Now())
+		accessor.SetUID(uuid.NewUUID())
+		accessor.SetGeneration(1)
+		if !dryRun {
+			s.save(accessor, obj, watch.Added)
+		}
+		return obj, true, nil
+	}
+
+	oldAccessor, err := meta.Accessor(existing)
+	if err != nil {
+		return nil, false, err
+	}
+	if rv := accessor.GetResourceVersion(); rv != "" && rv != oldAccessor.GetResourceVersion() {
+		return nil, false, errors.NewConflict(s.resource.GroupResource(), name,
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+	if updateValidation != nil {
+		if err := updateValidation(ctx, obj, old); err != nil {
+			return nil, false, err
+		}
+	}
+	accessor.SetCreationTimestamp(oldAccessor.GetCreationTimestamp())
+	accessor.SetUID(oldAccessor.GetUID())
+	accessor.SetGeneration(oldAccessor.GetGeneration() + 1)
+	if !dryRun {
+		s.save(accessor, obj, watch.Modified)
+	}
+	return obj, false, nil
+}
+
+func (s *Store) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	key := s.key(s.namespace(ctx), name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, exists := s.objects[key]
+	if !exists {
+		return nil, false, s.resource.NewNotFound(name)
+	}
+	accessor, err := meta.Accessor(existing)
+	if err != nil {
+		return nil, false, err
+	}
+	if options != nil && options.Preconditions != nil {
+		if uid := options.Preconditions.UID; uid != nil && *uid != accessor.GetUID() {
+			return nil, false, errors.NewConflict(s.resource.GroupResource(), name,
+				fmt.Errorf("the UID in the precondition (%s) does not match the UID of the object (%s)", *uid, accessor.GetUID()))
+		}
+		if rv := options.Preconditions.ResourceVersion; rv != nil && *rv != accessor.GetResourceVersion() {
+			return nil, false, errors.NewConflict(s.resource.GroupResource(), name,
+				fmt.Errorf("the resource version in the precondition (%s) does not match the resource version of the object (%s)", *rv, accessor.GetResourceVersion()))
+		}
+	}
+	if deleteValidation != nil {
+		if err := deleteValidation(ctx, existing.DeepCopyObject()); err != nil {
+			return nil, false, err
+		}
+	}
+	if options != nil && len(options.DryRun) > 0 {
+		return existing.DeepCopyObject(), true, nil
+	}
+
+	delete(s.objects, key)
+	s.rv++
+	_ = s.broadcaster.Action(watch.Deleted, existing.DeepCopyObject())
+	return existing, true, nil
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/grafana/grafana/pkg/apis/featuretoggle/v0alpha1"
+)
+
+func newToggles(name string, lbls map[string]string) *v0alpha1.FeatureToggles {
+	return &v0alpha1.FeatureToggles{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: lbls,
+		},
+		Spec: map[string]bool{"a": true},
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := request.WithNamespace(context.Background(), "default")
+	otherCtx := request.WithNamespace(context.Background(), "other")
+	store := NewStore(v0alpha1.TogglesResourceInfo, true, nil)
+
+	created, err := store.Create(ctx, newToggles("first", map[string]string{"team": "a"}), nil, &metav1.CreateOptions{})
+	require.NoError(t, err)
+	first := created.(*v0alpha1.FeatureToggles)
+	require.Equal(t, "default", first.Namespace)
+	require.Equal(t, "1", first.ResourceVersion)
+	require.NotEmpty(t, first.UID)
+
+	_, err = store.Create(ctx, newToggles("second", map[string]string{"team": "b"}), nil, &metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = store.Create(otherCtx, newToggles("first", nil), nil, &metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Run("should reject existing names", func(t *testing.T) {
+		_, err := store.Create(ctx, newToggles("first", nil), nil, &metav1.CreateOptions{})
+		require.True(t, apierrors.IsAlreadyExists(err))
+	})
+
+	t.Run("should get objects of the namespace", func(t *testing.T) {
+		obj, err := store.Get(ctx, "first", &metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, first, obj)
+
+		_, err = store.Get(otherCtx, "second", &metav1.GetOptions{})
+		require.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("should list objects matching the selectors", func(t *testing.T) {
+		obj, err := store.List(ctx, &internalversion.ListOptions{})
+		require.NoError(t, err)
+		list := obj.(*v0alpha1.FeatureTogglesList)
+		require.Len(t, list.Items, 2)
+		require.Equal(t, "3", list.ResourceVersion)
+
+		obj, err = store.List(ctx, &internalversion.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{"team": "b"})})
+		require.NoError(t, err)
+		list = obj.(*v0alpha1.FeatureTogglesList)
+		require.Len(t, list.Items, 1)
+		require.Equal(t, "second", list.Items[0].Name)
+	})
+
+	t.Run("should reject updates of outdated versions", func(t *testing.T) {
+		updated := first.DeepCopy()
+		updated.Spec = map[string]bool{"a": false}
+		obj, created, err := store.Update(ctx, "first", rest.DefaultUpdatedObjectInfo(updated), nil, nil, false, &metav1.UpdateOptions{})
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, int64(2), obj.(*v0alpha1.FeatureToggles).Generation)
+		require.Equal(t, first.UID, obj.(*v0alpha1.FeatureToggles).UID)
+
+		_, _, err = store.Update(ctx, "first", rest.DefaultUpdatedObjectInfo(updated), nil, nil, false, &metav1.UpdateOptions{})
+		require.True(t, apierrors.IsConflict(err))
+	})
+
+	t.Run("should create objects on update only if allowed", func(t *testing.T) {
+		_, _, err := store.Update(ctx, "third", rest.DefaultUpdatedObjectInfo(newToggles("third", nil)), nil, nil, false, &metav1.UpdateOptions{})
+		require.True(t, apierrors.IsNotFound(err))
+
+		_, created, err := store.Update(ctx, "third", rest.DefaultUpdatedObjectInfo(newToggles("third", nil)), nil, nil, true, &metav1.UpdateOptions{})
+		require.NoError(t, err)
+		require.True(t, created)
+	})
+
+	t.Run("should delete objects", func(t *testing.T) {
+		_, deleted, err := store.Delete(ctx, "third", nil, &metav1.DeleteOptions{})
+		require.NoError(t, err)
+		require.True(t, deleted)
+
+		_, err = store.Get(ctx, "third", &metav1.GetOptions{})
+		require.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestStoreWatch(t *testing.T) {
+	ctx := request.WithNamespace(context.Background(), "default")
+	store := NewStore(v0alpha1.TogglesResourceInfo, true, nil)
+	require.NoError(t, store.Add(&v0alpha1.FeatureToggles{ObjectMeta: metav1.ObjectMeta{Name: "startup", Namespace: "default"}}))
+
+	w, err := store.Watch(ctx, &internalversion.ListOptions{})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	_, err = store.Create(request.WithNamespace(context.Background(), "other"), newToggles("ignored", nil), nil, &metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, _, err = store.Delete(ctx, "startup", nil, &metav1.DeleteOptions{})
+	require.NoError(t, err)
+
+	for _, expected := range []watch.EventType{watch.Added, watch.Deleted} {
+		select {
+		case event := <-w.ResultChan():
+			require.Equal(t, expected, event.Type)
+			require.Equal(t, "startup", event.Object.(*v0alpha1.FeatureToggles).Name)
+		case <-time.After(time.Second):
+			t.Fatalf("expected a %s event", expected)
+		}
+	}
+}
@@ -28,8 +28,12 @@ type Service interface {
 	SearchUsersPermissions(ctx context.Context, user identity.Requester, options SearchOptions) (map[int64][]Permission, error)
 	// ClearUserPermissionCache removes the permission cache entry for the given user
 	ClearUserPermissionCache(user identity.Requester)
+	// ClearOrgPermissionCache removes the permission cache entries of all identities in the given organization
+	ClearOrgPermissionCache(orgID int64)
 	// SearchUserPermissions returns single user's permissions filtered by an action prefix or an action
 	SearchUserPermissions(ctx context.Context, orgID int64, filterOptions SearchOptions) ([]Permission, error)
+	// SearchTeamPermissions returns the permissions granted to a team filtered by an action prefix or an action
+	SearchTeamPermissions(ctx context.Context, orgID, teamID int64, filterOptions SearchOptions) ([]Permission, error)
 	// DeleteUserPermissions removes all permissions user has in org and all permission to that user
 	// If orgID is set to 0 remove permissions from all orgs
 	DeleteUserPermissions(ctx context.Context, orgID, userID int64) error
@@ -11,6 +11,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -33,7 +35,8 @@ import (
 var _ plugins.RoleRegistry = &Service{}
 
 const (
-	cacheTTL = 10 * time.Second
+	cacheTTL              = 10 * time.Second
+	permissionCachePrefix = "rbac-permissions-"
 )
 
 var SharedWithMeFolderPermission = accesscontrol.Permission{
@@ -42,8 +45,11 @@ var SharedWithMeFolderPermission = accesscontrol.Permission{
 }
 
 func ProvideService(cfg *setting.Cfg, db db.DB, routeRegister routing.RouteRegister, cache *localcache.CacheService,
-	accessControl accesscontrol.AccessControl, userSvc user.Service, features featuremgmt.FeatureToggles) (*Service, error) {
+	accessControl accesscontrol.AccessControl, userSvc user.Service, features featuremgmt.FeatureToggles, bus bus.Bus) (*Service, error) {
 	service := ProvideOSSService(cfg, database.ProvideService(db), cache, userSvc, features)
+	if bus != nil {
+		bus.AddEventListener(service.handleFolderDeleted)
+	}
 
 	api.NewAccessControlAPI(routeRegister, accessControl, service, features).RegisterAPIEndpoints()
 	if err := accesscontrol.DeclareFixedRoles(service, cfg); err != nil {
@@ -174,8 +180,33 @@ func (s *Service) ClearUserPermissionCache(user identity.Requester) {
 	s.cache.Delete(permissionCacheKey(user))
 }
 
+// ClearOrgPermissionCache removes the cached permissions of every identity of the organization,
+// it is used when a change can affect the permissions of several users at once (e.g. team or role changes).
+// If orgID is set to 0 the cached permissions of all organizations are removed.
+func (s *Service) ClearOrgPermissionCache(orgID int64) {
+	prefix := orgPermissionCachePrefix(orgID)
+	cleared := 0
+	for key := range s.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			s.cache.Delete(key)
+			cleared++
+		}
+	}
+	s.log.Debug("Cleared permission cache", "orgID", orgID, "entries", cleared)
+}
+
+// handleFolderDeleted drops the cached permissions of the organization since they can reference the deleted folders.
+func (s *Service) handleFolderDeleted(_ context.Context, evt *events.FolderDeleted) error {
+	s.ClearOrgPermissionCache(evt.OrgID)
+	return nil
+}
+
 func (s *Service) DeleteUserPermissions(ctx context.Context, orgID int64, userID int64) error {
-	return s.store.DeleteUserPermissions(ctx, orgID, userID)
+	if err := s.store.DeleteUserPermissions(ctx, orgID, userID); err != nil {
+		return err
+	}
+	s.ClearOrgPermissionCache(orgID)
+	return nil
 }
 
 // DeclareFixedRoles allow the caller to declare, to the service, fixed roles and their assignments
@@ -214,7 +245,15 @@ func (s *Service) RegisterFixedRoles(ctx context.Context) error {
 }
 
 func permissionCacheKey(user identity.Requester) string {
-	return fmt.Sprintf("rbac-permissions-%s", user.GetCacheKey())
+	return fmt.Sprintf("%s%s", permissionCachePrefix, user.GetCacheKey())
+}
+
+// orgPermissionCachePrefix returns the prefix shared by the permission cache keys of the organization's identities.
+func orgPermissionCachePrefix(orgID int64) string {
+	if orgID == 0 {
+		return permissionCachePrefix
+	}
+	return fmt.Sprintf("%s%d-", permissionCachePrefix, orgID)
 }
 
 // DeclarePluginRoles allow the caller to declare, to the service, plugin roles and their assignments
@@ -395,6 +434,33 @@ func (s *Service) searchUserPermissions(ctx context.Context, orgID int64, search
 	return permissions, nil
 }
 
+func (s *Service) SearchTeamPermissions(ctx context.Context, orgID, teamID int64, searchOptions accesscontrol.SearchOptions) ([]accesscontrol.Permission, error) {
+	timer := prometheus.NewTimer(metrics.MAccessPermissionsSummary)
+	defer timer.ObserveDuration()
+
+	if teamID == 0 {
+		return nil, fmt.Errorf("expected team ID to be specified")
+	}
+
+	// Unlike user permissions, team permissions are not completed by the basic role permissions, so fixed and custom
+	// roles assigned to the team must be included alongside the managed ones.
+	dbPermissions, err := s.store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
+		OrgID:   orgID,
+		TeamIDs: []int64{teamID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]accesscontrol.Permission, 0, len(dbPermissions))
+	for _, permission := range dbPermissions {
+		if PermissionMatchesSearchOptions(permission, &searchOptions) {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
 func (s *Service) searchUserPermissionsFromCache(orgID int64, searchOptions accesscontrol.SearchOptions) ([]accesscontrol.Permission, bool) {
 	// Create a temp signed in user object to retrieve cache key
 	tempUser := &user.SignedInUser{
@@ -446,7 +512,11 @@ func (s *Service) SaveExternalServiceRole(ctx context.Context, cmd accesscontrol
 		return err
 	}
 
-	return s.store.SaveExternalServiceRole(ctx, cmd)
+	if err := s.store.SaveExternalServiceRole(ctx, cmd); err != nil {
+		return err
+	}
+	s.ClearOrgPermissionCache(cmd.AssignmentOrgID)
+	return nil
 }
 
 func (s *Service) DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error {
@@ -457,7 +527,12 @@ func (s *Service) DeleteExternalServiceRole(ctx context.Context, externalService
 
 	slug := slugify.Slugify(externalServiceID)
 
-	return s.store.DeleteExternalServiceRole(ctx, slug)
+	if err := s.store.DeleteExternalServiceRole(ctx, slug); err != nil {
+		return err
+	}
+	// the role can be assigned in any organization
+	s.ClearOrgPermissionCache(0)
+	return nil
 }
 
 func (*Service) SyncUserRoles(ctx context.Context, orgID int64, cmd accesscontrol.SyncUserRolesCommand) error {
@@ -835,6 +835,53 @@ func TestPermissionCacheKey(t *testing.T) {
 	}
 }
 
+func TestService_ClearOrgPermissionCache(t *testing.T) {
+	users := []*user.SignedInUser{
+		{OrgID: 1, UserID: 1},
+		{OrgID: 1, UserID: 2, IsServiceAccount: true},
+		{OrgID: 11, UserID: 1},
+		{OrgID: 2, UserID: 1},
+	}
+
+	setup := func(t *testing.T) *Service {
+		t.Helper()
+		ac := setupTestEnv(t)
+		for _, u := range users {
+			ac.cache.Set(permissionCacheKey(u), []accesscontrol.Permission{{Action: "users:read"}}, cacheTTL)
+		}
+		ac.cache.Set("other-cache-key", "value", cacheTTL)
+		return ac
+	}
+
+	cached := func(ac *Service, u *user.SignedInUser) bool {
+		_, ok := ac.cache.Get(permissionCacheKey(u))
+		return ok
+	}
+
+	t.Run("should only clear the cache of the given org", func(t *testing.T) {
+		ac := setup(t)
+		ac.ClearOrgPermissionCache(1)
+
+		assert.False(t, cached(ac, users[0]))
+		assert.False(t, cached(ac, users[1]))
+		assert.True(t, cached(ac, users[2]))
+		assert.True(t, cached(ac, users[3]))
+		_, ok := ac.cache.Get("other-cache-key")
+		assert.True(t, ok)
+	})
+
+	t.Run("should clear the cache of all orgs with org 0", func(t *testing.T) {
+		ac := setup(t)
+		ac.ClearOrgPermissionCache(0)
+
+		for _, u := range users {
+			assert.False(t, cached(ac, u))
+		}
+		_, ok := ac.cache.Get("other-cache-key")
+		assert.True(t, ok)
+	})
+}
+
 func TestService_SaveExternalServiceRole(t *testing.T) {
 	type run struct {
 		cmd     accesscontrol.SaveExternalServiceRoleCommand
@@ -17,6 +17,7 @@ type FakeService struct {
 	ExpectedPermissions             []accesscontrol.Permission
 	ExpectedFilteredUserPermissions []accesscontrol.Permission
 	ExpectedUsersPermissions        map[int64][]accesscontrol.Permission
+	ExpectedTeamPermissions         []accesscontrol.Permission
 }
 
 func (f FakeService) GetUsageStats(ctx context.Context) map[string]any {
@@ -35,8 +36,14 @@ func (f FakeService) SearchUserPermissions(ctx context.Context, orgID int64, sea
 	return f.ExpectedFilteredUserPermissions, f.ExpectedErr
 }
 
+func (f FakeService) SearchTeamPermissions(ctx context.Context, orgID, teamID int64, searchOptions accesscontrol.SearchOptions) ([]accesscontrol.Permission, error) {
+	return f.ExpectedTeamPermissions, f.ExpectedErr
+}
+
 func (f FakeService) ClearUserPermissionCache(user identity.Requester) {}
 
+func (f FakeService) ClearOrgPermissionCache(orgID int64) {}
+
 func (f FakeService) DeleteUserPermissions(ctx context.Context, orgID, userID int64) error {
 	return f.ExpectedErr
 }
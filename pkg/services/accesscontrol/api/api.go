@@ -11,6 +11,8 @@ import (
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func NewAccessControlAPI(router routing.RouteRegister, accesscontrol ac.AccessControl, service ac.Service,
@@ -36,6 +38,8 @@ func (api *AccessControlAPI) RegisterAPIEndpoints() {
 	api.RouteRegister.Group("/api/access-control", func(rr routing.RouteRegister) {
 		rr.Get("/user/actions", middleware.ReqSignedIn, routing.Wrap(api.getUserActions))
 		rr.Get("/user/permissions", middleware.ReqSignedIn, routing.Wrap(api.getUserPermissions))
+		rr.Post("/permissions/preview", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead)), routing.Wrap(api.previewPermission))
+		rr.Post("/cache/invalidate", authorize(ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(api.invalidateOrgPermissionCache))
 		if api.features.IsEnabledGlobally(featuremgmt.FlagAccessControlOnCall) {
 			rr.Get("/users/permissions/search", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead)), routing.Wrap(api.searchUsersPermissions))
 		}
@@ -66,6 +70,12 @@ func (api *AccessControlAPI) getUserPermissions(c *contextmodel.ReqContext) resp
 	return response.JSON(http.StatusOK, ac.GroupScopesByAction(permissions))
 }
 
+// POST /api/access-control/cache/invalidate
+func (api *AccessControlAPI) invalidateOrgPermissionCache(c *contextmodel.ReqContext) response.Response {
+	api.Service.ClearOrgPermissionCache(c.SignedInUser.GetOrgID())
+	return response.Success("Permission cache invalidated")
+}
+
 // GET /api/access-control/users/permissions/search
 func (api *AccessControlAPI) searchUsersPermissions(c *contextmodel.ReqContext) response.Response {
 	searchOptions := ac.SearchOptions{
@@ -109,3 +119,87 @@ func (api *AccessControlAPI) searchUsersPermissions(c *contextmodel.ReqContext)
 
 	return response.JSON(http.StatusOK, permsByAction)
 }
+
+// PermissionPreviewCommand describes the access to evaluate for a user or a team.
+type PermissionPreviewCommand struct {
+	UserID int64  `json:"userId"`
+	TeamID int64  `json:"teamId"`
+	Action string `json:"action"`
+	Scope  string `json:"scope"`
+}
+
+// PermissionPreview is the result of the evaluation of a PermissionPreviewCommand.
+type PermissionPreview struct {
+	Allowed bool   `json:"allowed"`
+	Action  string `json:"action"`
+	Scope   string `json:"scope,omitempty"`
+	// Matched contains the permissions granting the access
+	Matched []ac.Permission `json:"matched"`
+	// Permissions contains all permissions of the user or team for the action
+	Permissions []ac.Permission `json:"permissions"`
+}
+
+// POST /api/access-control/permissions/preview
+func (api *AccessControlAPI) previewPermission(c *contextmodel.ReqContext) response.Response {
+	cmd := PermissionPreviewCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if cmd.Action == "" {
+		return response.Error(http.StatusBadRequest, "'action' must be provided", nil)
+	}
+	if (cmd.UserID > 0) == (cmd.TeamID > 0) {
+		return response.Error(http.StatusBadRequest, "exactly one of 'userId' and 'teamId' must be provided", nil)
+	}
+
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+	searchOptions := ac.SearchOptions{Action: cmd.Action}
+
+	var permissions []ac.Permission
+	var err error
+	if cmd.UserID > 0 {
+		searchOptions.UserID = cmd.UserID
+		permissions, err = api.Service.SearchUserPermissions(ctx, orgID, searchOptions)
+	} else {
+		permissions, err = api.Service.SearchTeamPermissions(ctx, orgID, cmd.TeamID, searchOptions)
+	}
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "could not get permissions", err)
+	}
+
+	evaluator := ac.EvalPermission(cmd.Action)
+	if cmd.Scope != "" {
+		evaluator = ac.EvalPermission(cmd.Action, cmd.Scope)
+	}
+
+	preview := PermissionPreview{
+		Action:      cmd.Action,
+		Scope:       cmd.Scope,
+		Matched:     make([]ac.Permission, 0),
+		Permissions: permissions,
+	}
+	// Evaluate every permission on its own to report which ones grant the access.
+	// This goes through the scope resolvers so inherited permissions (e.g. from parent folders) are matched as well.
+	for _, permission := range permissions {
+		ok, err := api.AccessControl.Evaluate(ctx, previewIdentity(orgID, cmd.UserID, permission), evaluator)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "could not evaluate permission", err)
+		}
+		if ok {
+			preview.Matched = append(preview.Matched, permission)
+		}
+	}
+	preview.Allowed = len(preview.Matched) > 0
+
+	return response.JSON(http.StatusOK, preview)
+}
+
+func previewIdentity(orgID, userID int64, permissions ...ac.Permission) *user.SignedInUser {
+	return &user.SignedInUser{
+		UserID:      userID,
+		OrgID:       orgID,
+		Permissions: map[int64]map[string][]string{orgID: ac.GroupScopesByAction(permissions)},
+	}
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,6 +12,8 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -205,3 +209,133 @@ func TestAccessControlAPI_searchUsersPermissions(t *testing.T) {
 		})
 	}
 }
+
+// evaluatingAccessControl evaluates the permissions of the requester without scope resolution
+type evaluatingAccessControl struct {
+	actest.FakeAccessControl
+}
+
+func (a evaluatingAccessControl) Evaluate(ctx context.Context, usr identity.Requester, evaluator ac.Evaluator) (bool, error) {
+	return evaluator.Evaluate(usr.GetPermissions()), nil
+}
+
+func TestAccessControlAPI_previewPermission(t *testing.T) {
+	type testCase struct {
+		desc            string
+		body            string
+		userPermissions []ac.Permission
+		teamPermissions []ac.Permission
+		expectedCode    int
+		expectedOutput  PermissionPreview
+	}
+
+	folderWrite := ac.Permission{Action: "alert.rules:write", Scope: "folders:uid:folder1"}
+	allFoldersWrite := ac.Permission{Action: "alert.rules:write", Scope: "folders:*"}
+
+	tests := []testCase{
+		{
+			desc:         "Should reject request without action",
+			body:         `{"userId": 2}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Should reject request with both user and team",
+			body:         `{"userId": 2, "teamId": 3, "action": "alert.rules:write"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:            "Should return matched user permissions",
+			body:            `{"userId": 2, "action": "alert.rules:write", "scope": "folders:uid:folder1"}`,
+			userPermissions: []ac.Permission{folderWrite, {Action: "alert.rules:write", Scope: "folders:uid:folder2"}, allFoldersWrite},
+			expectedCode:    http.StatusOK,
+			expectedOutput: PermissionPreview{
+				Allowed:     true,
+				Action:      "alert.rules:write",
+				Scope:       "folders:uid:folder1",
+				Matched:     []ac.Permission{folderWrite, allFoldersWrite},
+				Permissions: []ac.Permission{folderWrite, {Action: "alert.rules:write", Scope: "folders:uid:folder2"}, allFoldersWrite},
+			},
+		},
+		{
+			desc:            "Should not allow team without matching permissions",
+			body:            `{"teamId": 3, "action": "alert.rules:write", "scope": "folders:uid:folder2"}`,
+			teamPermissions: []ac.Permission{folderWrite},
+			expectedCode:    http.StatusOK,
+			expectedOutput: PermissionPreview{
+				Allowed:     false,
+				Action:      "alert.rules:write",
+				Scope:       "folders:uid:folder2",
+				Matched:     []ac.Permission{},
+				Permissions: []ac.Permission{folderWrite},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			acSvc := actest.FakeService{ExpectedFilteredUserPermissions: tt.userPermissions, ExpectedTeamPermissions: tt.teamPermissions}
+			api := NewAccessControlAPI(routing.NewRouteRegister(), evaluatingAccessControl{}, acSvc, featuremgmt.WithFeatures())
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewPostRequest("/api/access-control/permissions/preview", strings.NewReader(tt.body))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID: 1,
+				Permissions: map[int64]map[string][]string{
+					1: {ac.ActionUsersPermissionsRead: {"users:*"}},
+				},
+			})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, res.Body.Close()) }()
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+
+			if tt.expectedCode == http.StatusOK {
+				var output PermissionPreview
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&output))
+				require.Equal(t, tt.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestAccessControlAPI_invalidateOrgPermissionCache(t *testing.T) {
+	type testCase struct {
+		desc          string
+		permissions   map[string][]string
+		expectedCode  int
+		expectedCalls []interface{}
+	}
+
+	tests := []testCase{
+		{
+			desc:         "Should reject user without orgs:write",
+			permissions:  map[string][]string{},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:          "Should clear the permission cache of the current org",
+			permissions:   map[string][]string{ac.ActionOrgsWrite: {}},
+			expectedCode:  http.StatusOK,
+			expectedCalls: []interface{}{[]interface{}{int64(2)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			acSvc := mock.New()
+			api := NewAccessControlAPI(routing.NewRouteRegister(), evaluatingAccessControl{}, acSvc, featuremgmt.WithFeatures())
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewPostRequest("/api/access-control/cache/invalidate", nil)
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:       2,
+				Permissions: map[int64]map[string][]string{2: tt.permissions},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, res.Body.Close()) }()
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+			require.Equal(t, tt.expectedCalls, acSvc.Calls.ClearOrgPermissionCache)
+		})
+	}
+}
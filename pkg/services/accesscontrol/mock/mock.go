@@ -22,6 +22,7 @@ type Calls struct {
 	Evaluate                       []interface{}
 	GetUserPermissions             []interface{}
 	ClearUserPermissionCache       []interface{}
+	ClearOrgPermissionCache        []interface{}
 	DeclareFixedRoles              []interface{}
 	DeclarePluginRoles             []interface{}
 	GetUserBuiltInRoles            []interface{}
@@ -30,6 +31,7 @@ type Calls struct {
 	DeleteUserPermissions          []interface{}
 	SearchUsersPermissions         []interface{}
 	SearchUserPermissions          []interface{}
+	SearchTeamPermissions          []interface{}
 	SaveExternalServiceRole        []interface{}
 	DeleteExternalServiceRole      []interface{}
 }
@@ -47,6 +49,7 @@ type Mock struct {
 	EvaluateFunc                       func(context.Context, identity.Requester, accesscontrol.Evaluator) (bool, error)
 	GetUserPermissionsFunc             func(context.Context, identity.Requester, accesscontrol.Options) ([]accesscontrol.Permission, error)
 	ClearUserPermissionCacheFunc       func(identity.Requester)
+	ClearOrgPermissionCacheFunc        func(int64)
 	DeclareFixedRolesFunc              func(...accesscontrol.RoleRegistration) error
 	DeclarePluginRolesFunc             func(context.Context, string, string, []plugins.RoleRegistration) error
 	GetUserBuiltInRolesFunc            func(user identity.Requester) []string
@@ -55,6 +58,7 @@ type Mock struct {
 	DeleteUserPermissionsFunc          func(context.Context, int64) error
 	SearchUsersPermissionsFunc         func(context.Context, identity.Requester, int64, accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error)
 	SearchUserPermissionsFunc          func(ctx context.Context, orgID int64, searchOptions accesscontrol.SearchOptions) ([]accesscontrol.Permission, error)
+	SearchTeamPermissionsFunc          func(ctx context.Context, orgID, teamID int64, searchOptions accesscontrol.SearchOptions) ([]accesscontrol.Permission, error)
 	SaveExternalServiceRoleFunc        func(ctx context.Context, cmd accesscontrol.SaveExternalServiceRoleCommand) error
 	DeleteExternalServiceRoleFunc      func(ctx context.Context, externalServiceID string) error
 	SyncUserRolesFunc                  func(ctx context.Context, orgID int64, cmd accesscontrol.SyncUserRolesCommand) error
@@ -146,6 +150,14 @@ func (m *Mock) ClearUserPermissionCache(user identity.Requester) {
 	}
 }
 
+func (m *Mock) ClearOrgPermissionCache(orgID int64) {
+	m.Calls.ClearOrgPermissionCache = append(m.Calls.ClearOrgPermissionCache, []interface{}{orgID})
+	// Use override if provided
+	if m.ClearOrgPermissionCacheFunc != nil {
+		m.ClearOrgPermissionCacheFunc(orgID)
+	}
+}
+
 // DeclareFixedRoles allow the caller to declare, to the service, fixed roles and their
 // assignments to organization roles ("Viewer", "Editor", "Admin") or "Grafana Admin"
 // This mock returns no error unless an override is provided.
@@ -219,6 +231,15 @@ func (m *Mock) SearchUserPermissions(ctx context.Context, orgID int64, searchOpt
 	return nil, nil
 }
 
+func (m *Mock) SearchTeamPermissions(ctx context.Context, orgID, teamID int64, searchOptions accesscontrol.SearchOptions) ([]accesscontrol.Permission, error) {
+	m.Calls.SearchTeamPermissions = append(m.Calls.SearchTeamPermissions, []interface{}{ctx, orgID, teamID, searchOptions})
+	// Use override if provided
+	if m.SearchTeamPermissionsFunc != nil {
+		return m.SearchTeamPermissionsFunc(ctx, orgID, teamID, searchOptions)
+	}
+	return nil, nil
+}
+
 func (m *Mock) SaveExternalServiceRole(ctx context.Context, cmd accesscontrol.SaveExternalServiceRoleCommand) error {
 	m.Calls.SaveExternalServiceRole = append(m.Calls.SaveExternalServiceRole, []interface{}{ctx, cmd})
 	// Use override if provided
@@ -153,13 +153,19 @@ func (s *Service) SetUserPermission(ctx context.Context, orgID int64, user acces
 		return nil, err
 	}
 
-	return s.store.SetUserResourcePermission(ctx, orgID, user, SetResourcePermissionCommand{
+	permissions, err := s.store.SetUserResourcePermission(ctx, orgID, user, SetResourcePermissionCommand{
 		Actions:           actions,
 		Permission:        permission,
 		Resource:          s.options.Resource,
 		ResourceID:        resourceID,
 		ResourceAttribute: s.options.ResourceAttribute,
 	}, s.options.OnSetUser)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clearPermissionCache(orgID)
+	return permissions, nil
 }
 
 func (s *Service) SetTeamPermission(ctx context.Context, orgID, teamID int64, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
@@ -176,13 +182,19 @@ func (s *Service) SetTeamPermission(ctx context.Context, orgID, teamID int64, re
 		return nil, err
 	}
 
-	return s.store.SetTeamResourcePermission(ctx, orgID, teamID, SetResourcePermissionCommand{
+	permissions, err := s.store.SetTeamResourcePermission(ctx, orgID, teamID, SetResourcePermissionCommand{
 		Actions:           actions,
 		Permission:        permission,
 		Resource:          s.options.Resource,
 		ResourceID:        resourceID,
 		ResourceAttribute: s.options.ResourceAttribute,
 	}, s.options.OnSetTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clearPermissionCache(orgID)
+	return permissions, nil
 }
 
 func (s *Service) SetBuiltInRolePermission(ctx context.Context, orgID int64, builtInRole, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
@@ -199,13 +211,19 @@ func (s *Service) SetBuiltInRolePermission(ctx context.Context, orgID int64, bui
 		return nil, err
 	}
 
-	return s.store.SetBuiltInResourcePermission(ctx, orgID, builtInRole, SetResourcePermissionCommand{
+	permissions, err := s.store.SetBuiltInResourcePermission(ctx, orgID, builtInRole, SetResourcePermissionCommand{
 		Actions:           actions,
 		Permission:        permission,
 		Resource:          s.options.Resource,
 		ResourceID:        resourceID,
 		ResourceAttribute: s.options.ResourceAttribute,
 	}, s.options.OnSetBuiltInRole)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clearPermissionCache(orgID)
+	return permissions, nil
 }
 
 func (s *Service) SetPermissions(
@@ -251,11 +269,17 @@ func (s *Service) SetPermissions(
 		})
 	}
 
-	return s.store.SetResourcePermissions(ctx, orgID, dbCommands, ResourceHooks{
+	permissions, err := s.store.SetResourcePermissions(ctx, orgID, dbCommands, ResourceHooks{
 		User:        s.options.OnSetUser,
 		Team:        s.options.OnSetTeam,
 		BuiltInRole: s.options.OnSetBuiltInRole,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.clearPermissionCache(orgID)
+	return permissions, nil
 }
 
 func (s *Service) MapActions(permission accesscontrol.ResourcePermission) string {
@@ -268,11 +292,24 @@ func (s *Service) MapActions(permission accesscontrol.ResourcePermission) string
 }
 
 func (s *Service) DeleteResourcePermissions(ctx context.Context, orgID int64, resourceID string) error {
-	return s.store.DeleteResourcePermissions(ctx, orgID, &DeleteResourcePermissionsCmd{
+	if err := s.store.DeleteResourcePermissions(ctx, orgID, &DeleteResourcePermissionsCmd{
 		Resource:          s.options.Resource,
 		ResourceAttribute: s.options.ResourceAttribute,
 		ResourceID:        resourceID,
-	})
+	}); err != nil {
+		return err
+	}
+
+	s.clearPermissionCache(orgID)
+	return nil
+}
+
+// clearPermissionCache drops the cached permissions of the organization so that managed permission changes,
+// including team memberships managed through the team permissions, are visible on the next request.
+func (s *Service) clearPermissionCache(orgID int64) {
+	if s.service != nil {
+		s.service.ClearOrgPermissionCache(orgID)
+	}
 }
 
 func (s *Service) mapPermission(permission string) ([]string, error) {
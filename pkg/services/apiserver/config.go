@@ -45,4 +45,6 @@ func applyGrafanaConfig(cfg *setting.Cfg, features featuremgmt.FeatureToggles, o
 	o.ExtraOptions.ExternalAddress = host
 	o.ExtraOptions.APIURL = apiURL
 	o.ExtraOptions.Verbosity = defaultLogLevel
+	o.ExtraOptions.MaxResponseSize = cfg.SectionWithEnvOverrides("grafana-apiserver").Key("max_response_size").MustInt64(0)
+	o.ExtraOptions.MaxRequestDuration = cfg.SectionWithEnvOverrides("grafana-apiserver").Key("max_request_duration").MustDuration(0)
 }
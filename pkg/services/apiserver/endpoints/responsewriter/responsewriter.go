@@ -0,0 +1,206 @@
+package responsewriter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sresponsewriter "k8s.io/apiserver/pkg/endpoints/responsewriter"
+)
+
+var _ http.ResponseWriter = (*ResponseAdapter)(nil)
+var _ k8sresponsewriter.UserProvidedDecorator = (*ResponseAdapter)(nil)
+
+// errTerminated is returned to the handler when it writes after the response was terminated
+var errTerminated = errors.New("the response was terminated")
+
+// Limits of the responses of handlers served in process. Zero values mean no limit.
+type Limits struct {
+	// The maximum size of the response body in bytes
+	MaxResponseSize int64
+
+	// The maximum duration of the handler
+	MaxDuration time.Duration
+}
+
+// ResponseAdapter records the response of a handler served in process, so it can be returned by a RoundTripper.
+// Once a limit is exceeded, the response is replaced with a k8s Status error and the rest of it is dropped.
+type ResponseAdapter struct {
+	limits Limits
+
+	mu       sync.Mutex
+	recorder *httptest.ResponseRecorder
+	size     int64
+	// the Status error that replaced the response, if it was terminated
+	terminated *metav1.Status
+
+	// closed when the response is terminated, so the handler can stop
+	done chan bool
+	// cancels the context of the handler, set while the handler is served
+	cancel context.CancelFunc
+}
+
+func NewResponseAdapter(limits Limits) *ResponseAdapter {
+	return &ResponseAdapter{
+		limits:   limits,
+		recorder: httptest.NewRecorder(),
+		done:     make(chan bool),
+	}
+}
+
+// WrapHandler returns a RoundTripper function that serves the requests with the handler, within the limits
+func WrapHandler(handler http.Handler, limits Limits) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		w := NewResponseAdapter(limits)
+		w.ServeHTTP(handler, req)
+		return w.Result(), nil
+	}
+}
+
+// ServeHTTP serves the request with the handler. If the handler runs longer than the maximum duration, or its
+// response exceeds the maximum size, its context is canceled and the response is terminated without waiting for the
+// handler to return.
+func (w *ResponseAdapter) ServeHTTP(handler http.Handler, req *http.Request) {
+	resp := k8sresponsewriter.WrapForHTTP1Or2(w)
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	w.mu.Lock()
+	w.cancel = cancel
+	w.mu.Unlock()
+
+	if w.limits.MaxDuration <= 0 {
+		handler.ServeHTTP(resp, req.WithContext(ctx))
+		return
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		handler.ServeHTTP(resp, req.WithContext(ctx))
+	}()
+
+	timer := time.NewTimer(w.limits.MaxDuration)
+	defer timer.Stop()
+	select {
+	case <-finished:
+	case <-w.done:
+	case <-timer.C:
+		w.terminate(apierrors.NewTimeoutError(fmt.Sprintf("the request did not finish within %s", w.limits.MaxDuration), 0))
+	}
+}
+
+func (w *ResponseAdapter) Header() http.Header {
+	return w.recorder.Header()
+}
+
+func (w *ResponseAdapter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.terminated != nil {
+		return
+	}
+	w.recorder.WriteHeader(status)
+}
+
+func (w *ResponseAdapter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.terminated != nil {
+		w.mu.Unlock()
+		return 0, errTerminated
+	}
+	if max := w.limits.MaxResponseSize; max > 0 && w.size+int64(len(p)) > max {
+		w.mu.Unlock()
+		w.terminate(apierrors.NewInternalError(fmt.Errorf("the response exceeds the maximum size of %d bytes", max)))
+		return 0, errTerminated
+	}
+	defer w.mu.Unlock()
+	w.size += int64(len(p))
+	return w.recorder.Write(p)
+}
+
+func (w *ResponseAdapter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.recorder.Flush()
+}
+
+// Unwrap returns a writer that writes through the adapter, so that the writes of code that unwraps the response
+// writer are within the limits too. The recorder is not exposed, as it is not safe for concurrent use.
+func (w *ResponseAdapter) Unwrap() http.ResponseWriter {
+	return adapterWriter{adapter: w}
+}
+
+func (w *ResponseAdapter) CloseNotify() <-chan bool {
+	return w.done
+}
+
+// terminate replaces the response with the error, and drops anything the handler writes afterwards
+func (w *ResponseAdapter) terminate(err *apierrors.StatusError) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.terminated != nil {
+		return
+	}
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	w.terminated = &status
+	close(w.done)
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// adapterWriter is the response writer that ResponseAdapter.Unwrap returns.
+type adapterWriter struct {
+	adapter *ResponseAdapter
+}
+
+func (a adapterWriter) Header() http.Header {
+	return a.adapter.Header()
+}
+
+func (a adapterWriter) Write(p []byte) (int, error) {
+	return a.adapter.Write(p)
+}
+
+func (a adapterWriter) WriteHeader(status int) {
+	a.adapter.WriteHeader(status)
+}
+
+// Result returns the response. It must be called once the handler returned, or the response was terminated.
+func (w *ResponseAdapter) Result() *http.Response {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.terminated == nil {
+		return w.recorder.Result()
+	}
+
+	body, err := json.Marshal(w.terminated)
+	if err != nil {
+		body = []byte(w.terminated.Message)
+	}
+	code := int(w.terminated.Code)
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	return &http.Response{
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		StatusCode:    code,
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
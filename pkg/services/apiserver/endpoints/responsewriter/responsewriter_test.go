@@ -0,0 +1,119 @@
+package responsewriter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWrapHandler(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		for i := 0; i < 10; i++ {
+			if _, err := w.Write([]byte("0123456789")); err != nil {
+				return
+			}
+		}
+	})
+	slowHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		_, _ = w.Write([]byte("too late"))
+	})
+
+	readStatus := func(t *testing.T, resp *http.Response) metav1.Status {
+		t.Helper()
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		status := metav1.Status{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		require.Equal(t, "Status", status.Kind)
+		require.Equal(t, metav1.StatusFailure, status.Status)
+		return status
+	}
+
+	t.Run("should return the response within the limits", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/apis", nil)
+		require.NoError(t, err)
+		resp, err := WrapHandler(handler, Limits{MaxResponseSize: 100, MaxDuration: time.Second})(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Len(t, body, 100)
+	})
+
+	t.Run("should terminate responses that are too large", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/apis", nil)
+		require.NoError(t, err)
+		resp, err := WrapHandler(handler, Limits{MaxResponseSize: 50})(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		status := readStatus(t, resp)
+		require.Equal(t, metav1.StatusReasonInternalError, status.Reason)
+		require.Contains(t, status.Message, "the response exceeds the maximum size of 50 bytes")
+	})
+
+	t.Run("should terminate handlers that run too long", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/apis", nil)
+		require.NoError(t, err)
+		resp, err := WrapHandler(slowHandler, Limits{MaxDuration: 10 * time.Millisecond})(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		status := readStatus(t, resp)
+		require.Equal(t, metav1.StatusReasonTimeout, status.Reason)
+	})
+
+	t.Run("should cancel the context of the handler when a limit is exceeded", func(t *testing.T) {
+		for name, limits := range map[string]Limits{
+			"size":                {MaxResponseSize: 5},
+			"size with duration":  {MaxResponseSize: 5, MaxDuration: time.Minute},
+			"duration":            {MaxDuration: 10 * time.Millisecond},
+			"duration with limit": {MaxResponseSize: 100, MaxDuration: 10 * time.Millisecond},
+		} {
+			t.Run(name, func(t *testing.T) {
+				canceled := make(chan struct{})
+				handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					defer close(canceled)
+					_, _ = w.Write([]byte("0123456789"))
+					<-r.Context().Done()
+				})
+				req, err := http.NewRequest(http.MethodGet, "/apis", nil)
+				require.NoError(t, err)
+				resp, err := WrapHandler(handler, limits)(req)
+				require.NoError(t, err)
+				readStatus(t, resp)
+				select {
+				case <-canceled:
+				case <-time.After(time.Second):
+					t.Fatal("the context of the handler was not canceled")
+				}
+			})
+		}
+	})
+
+	t.Run("should apply the limits to the unwrapped writer", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for {
+				u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+				if !ok {
+					break
+				}
+				w = u.Unwrap()
+			}
+			_, _ = w.Write([]byte("0123456789"))
+		})
+		req, err := http.NewRequest(http.MethodGet, "/apis", nil)
+		require.NoError(t, err)
+		resp, err := WrapHandler(handler, Limits{MaxResponseSize: 5})(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		status := readStatus(t, resp)
+		require.Contains(t, status.Message, "the response exceeds the maximum size of 5 bytes")
+	})
+}
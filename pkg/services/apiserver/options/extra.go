@@ -1,13 +1,17 @@
 package options
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/component-base/logs"
 	"k8s.io/klog/v2"
+
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/responsewriter"
 )
 
 type ExtraOptions struct {
@@ -15,6 +19,10 @@ type ExtraOptions struct {
 	ExternalAddress string
 	APIURL          string
 	Verbosity       int
+
+	// Limits of the responses of requests served in process, zero means no limit
+	MaxResponseSize    int64
+	MaxRequestDuration time.Duration
 }
 
 func NewExtraOptions() *ExtraOptions {
@@ -29,10 +37,27 @@ func (o *ExtraOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.ExternalAddress, "grafana-apiserver-host", o.ExternalAddress, "Host")
 	fs.StringVar(&o.APIURL, "grafana-apiserver-api-url", o.APIURL, "API URL")
 	fs.IntVar(&o.Verbosity, "verbosity", o.Verbosity, "Verbosity")
+	fs.Int64Var(&o.MaxResponseSize, "grafana-apiserver-max-response-size", o.MaxResponseSize, "Maximum size in bytes of the responses of requests served in process")
+	fs.DurationVar(&o.MaxRequestDuration, "grafana-apiserver-max-request-duration", o.MaxRequestDuration, "Maximum duration of requests served in process")
+}
+
+// ResponseLimits returns the limits of the responses of requests served in process
+func (o *ExtraOptions) ResponseLimits() responsewriter.Limits {
+	return responsewriter.Limits{
+		MaxResponseSize: o.MaxResponseSize,
+		MaxDuration:     o.MaxRequestDuration,
+	}
 }
 
 func (o *ExtraOptions) Validate() []error {
-	return nil
+	errs := []error{}
+	if o.MaxResponseSize < 0 {
+		errs = append(errs, fmt.Errorf("--grafana-apiserver-max-response-size must not be negative"))
+	}
+	if o.MaxRequestDuration < 0 {
+		errs = append(errs, fmt.Errorf("--grafana-apiserver-max-request-duration must not be negative"))
+	}
+	return errs
 }
 
 func (o *ExtraOptions) ApplyTo(c *genericapiserver.RecommendedConfig) error {
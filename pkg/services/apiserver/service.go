@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/http/httptest"
 	"path"
 
 	"github.com/grafana/dskit/services"
@@ -28,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/apiserver/auth/authorizer"
 	"github.com/grafana/grafana/pkg/services/apiserver/builder"
+	grafanaresponsewriter "github.com/grafana/grafana/pkg/services/apiserver/endpoints/responsewriter"
 	grafanaapiserveroptions "github.com/grafana/grafana/pkg/services/apiserver/options"
 	entitystorage "github.com/grafana/grafana/pkg/services/apiserver/storage/entity"
 	filestorage "github.com/grafana/grafana/pkg/services/apiserver/storage/file"
@@ -292,12 +292,7 @@ func (s *service) start(ctx context.Context) error {
 	}
 
 	// set the transport function and signal that it's ready
-	transport.fn = func(req *http.Request) (*http.Response, error) {
-		w := newWrappedResponseWriter()
-		resp := responsewriter.WrapForHTTP1Or2(w)
-		server.Handler.ServeHTTP(resp, req)
-		return w.Result(), nil
-	}
+	transport.fn = grafanaresponsewriter.WrapHandler(server.Handler, o.ExtraOptions.ResponseLimits())
 	close(transport.ready)
 
 	// only write kubeconfig in dev mode
@@ -325,8 +320,8 @@ func (s *service) GetDirectRestConfig(c *contextmodel.ReqContext) *clientrest.Co
 		Transport: &roundTripperFunc{
 			fn: func(req *http.Request) (*http.Response, error) {
 				ctx := appcontext.WithUser(req.Context(), c.SignedInUser)
-				w := httptest.NewRecorder()
-				s.handler.ServeHTTP(w, req.WithContext(ctx))
+				w := grafanaresponsewriter.NewResponseAdapter(s.options.ExtraOptions.ResponseLimits())
+				w.ServeHTTP(s.handler, req.WithContext(ctx))
 				return w.Result(), nil
 			},
 		},
@@ -367,24 +362,3 @@ func (f *roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 	return f.fn(req)
 }
-
-var _ http.ResponseWriter = (*wrappedResponseWriter)(nil)
-var _ responsewriter.UserProvidedDecorator = (*wrappedResponseWriter)(nil)
-
-type wrappedResponseWriter struct {
-	*httptest.ResponseRecorder
-}
-
-func newWrappedResponseWriter() *wrappedResponseWriter {
-	w := httptest.NewRecorder()
-	return &wrappedResponseWriter{w}
-}
-
-func (w *wrappedResponseWriter) Unwrap() http.ResponseWriter {
-	return w.ResponseRecorder
-}
-
-func (w *wrappedResponseWriter) CloseNotify() <-chan bool {
-	// TODO: this is probably not the right thing to do here
-	return make(<-chan bool)
-}
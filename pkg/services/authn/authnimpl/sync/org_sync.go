@@ -75,6 +75,8 @@ func (s *OrgSync) SyncOrgRolesHook(ctx context.Context, id *authn.Identity, _ *a
 				s.log.FromContext(ctx).Error("Failed to update active org user", "id", id.ID, "error", err)
 				return err
 			}
+			// The permissions granted by the previous role must not be served from the cache
+			s.accessControl.ClearUserPermissionCache(&user.SignedInUser{UserID: userID, OrgID: orga.OrgID})
 		}
 	}
 
@@ -93,6 +95,9 @@ func (s *OrgSync) SyncOrgRolesHook(ctx context.Context, id *authn.Identity, _ *a
 			s.log.FromContext(ctx).Error("Failed to update active org for user", "id", id.ID, "error", err)
 			return err
 		}
+		if err == nil {
+			s.accessControl.ClearUserPermissionCache(&user.SignedInUser{UserID: userID, OrgID: orgId})
+		}
 	}
 
 	// delete any removed org roles
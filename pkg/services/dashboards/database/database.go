@@ -56,9 +56,10 @@ func ProvideDashboardStore(sqlStore db.DB, cfg *setting.Cfg, features featuremgm
 	}
 
 	if err := quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
-		TargetSrv:     dashboards.QuotaTargetSrv,
-		DefaultLimits: defaultLimits,
-		Reporter:      s.Count,
+		TargetSrv:         dashboards.QuotaTargetSrv,
+		DefaultLimits:     defaultLimits,
+		Reporter:          s.Count,
+		BreakdownReporter: s.CountByFolder,
 	}); err != nil {
 		return nil, err
 	}
@@ -269,6 +270,47 @@ func (d *dashboardStore) Count(ctx context.Context, scopeParams *quota.ScopePara
 	return u, nil
 }
 
+// CountByFolder reports the number of dashboards of the organization grouped by their parent folder UID.
+// Dashboards in the root folder are reported with an empty key.
+func (d *dashboardStore) CountByFolder(ctx context.Context, scopeParams *quota.ScopeParameters) (map[quota.Tag][]quota.UsageBreakdown, error) {
+	if scopeParams == nil || scopeParams.OrgID == 0 {
+		return nil, nil
+	}
+
+	type result struct {
+		FolderUID *string `xorm:"folder_uid"`
+		Count     int64
+	}
+
+	rows := make([]result, 0)
+	if err := d.store.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		rawSQL := fmt.Sprintf("SELECT folder_uid, COUNT(*) AS count FROM dashboard WHERE org_id=? AND is_folder=%s GROUP BY folder_uid ORDER BY folder_uid", d.store.GetDialect().BooleanStr(false))
+		return sess.SQL(rawSQL, scopeParams.OrgID).Find(&rows)
+	}); err != nil {
+		return nil, err
+	}
+
+	tag, err := quota.NewTag(dashboards.QuotaTargetSrv, dashboards.QuotaTarget, quota.OrgScope)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := make([]quota.UsageBreakdown, 0, len(rows))
+	for _, r := range rows {
+		key := ""
+		if r.FolderUID != nil {
+			key = *r.FolderUID
+		}
+		breakdown = append(breakdown, quota.UsageBreakdown{
+			Kind: quota.UsageBreakdownKindFolder,
+			Key:  key,
+			Used: r.Count,
+		})
+	}
+
+	return map[quota.Tag][]quota.UsageBreakdown{tag: breakdown}, nil
+}
+
 func getExistingDashboardByIDOrUIDForUpdate(sess *db.Session, dash *dashboards.Dashboard, dialect migrator.Dialect, overwrite bool) (bool, error) {
 	dashWithIdExists := false
 	isParentFolderChanged := false
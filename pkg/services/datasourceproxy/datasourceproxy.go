@@ -39,6 +39,7 @@ func ProvideService(dataSourceCache datasources.CacheService, plugReqValidator v
 		tracer:                 tracer,
 		secretsService:         secretsService,
 		features:               features,
+		guard:                  newRequestGuard(cfg),
 	}
 }
 
@@ -53,6 +54,7 @@ type DataSourceProxyService struct {
 	tracer                 tracing.Tracer
 	secretsService         secrets.Service
 	features               featuremgmt.FeatureToggles
+	guard                  *requestGuard
 }
 
 func (p *DataSourceProxyService) ProxyDataSourceRequest(c *contextmodel.ReqContext) {
@@ -121,6 +123,12 @@ func (p *DataSourceProxyService) proxyDatasourceRequest(c *contextmodel.ReqConte
 		return
 	}
 
+	if r := p.guard.allow(ds); r != nil {
+		c.Resp.Header().Set("Retry-After", r.retryAfterSeconds())
+		c.JsonApiErr(r.status, r.message, nil)
+		return
+	}
+
 	proxyPath := getProxyPath(c)
 	proxy, err := pluginproxy.NewDataSourceProxy(ds, plugin.Routes, c, proxyPath, p.Cfg, p.HTTPClientProvider,
 		p.OAuthTokenService, p.DataSourcesService, p.tracer, p.features)
@@ -134,6 +142,7 @@ func (p *DataSourceProxyService) proxyDatasourceRequest(c *contextmodel.ReqConte
 		return
 	}
 	proxy.HandleRequest()
+	p.guard.observe(ds, c.Resp.Status())
 }
 
 var proxyPathRegexp = regexp.MustCompile(`^\/api\/datasources\/proxy\/([\d]+|uid\/[\w-]+)\/?`)
@@ -0,0 +1,208 @@
+package datasourceproxy
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	rateLimitJSONField      = "dataProxyRateLimit"
+	rateLimitBurstJSONField = "dataProxyRateLimitBurst"
+)
+
+// requestGuard protects data sources from being flooded by proxied requests.
+// Each data source gets its own rate limiter and circuit breaker, both disabled unless configured.
+type requestGuard struct {
+	rateLimit        float64
+	rateLimitBurst   int
+	breakerThreshold int
+	breakerTimeout   time.Duration
+	now              func() time.Time
+
+	mtx      sync.Mutex
+	limiters map[string]*dsLimiter
+	breakers map[string]*circuitBreaker
+}
+
+type dsLimiter struct {
+	limit   rate.Limit
+	burst   int
+	limiter *rate.Limiter
+}
+
+func newRequestGuard(cfg *setting.Cfg) *requestGuard {
+	return &requestGuard{
+		rateLimit:        cfg.DataProxyRateLimit,
+		rateLimitBurst:   cfg.DataProxyRateLimitBurst,
+		breakerThreshold: cfg.DataProxyBreakerThreshold,
+		breakerTimeout:   cfg.DataProxyBreakerOpenTimeout,
+		now:              time.Now,
+		limiters:         make(map[string]*dsLimiter),
+		breakers:         make(map[string]*circuitBreaker),
+	}
+}
+
+// rejection describes why a request to a data source was not proxied.
+type rejection struct {
+	status     int
+	message    string
+	retryAfter time.Duration
+}
+
+// retryAfterSeconds returns the value of the Retry-After header of the response.
+func (r *rejection) retryAfterSeconds() string {
+	seconds := int64(math.Ceil(r.retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
+
+// allow checks whether a request can be proxied to the data source, and returns the reason if it cannot.
+func (g *requestGuard) allow(ds *datasources.DataSource) *rejection {
+	if g == nil {
+		return nil
+	}
+
+	if limiter := g.limiter(ds); limiter != nil {
+		now := g.now()
+		reservation := limiter.ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			metrics.MDataSourceProxyRateLimited.WithLabelValues(ds.Type).Inc()
+			return &rejection{status: http.StatusTooManyRequests, message: "Too many requests to the data source", retryAfter: delay}
+		}
+	}
+
+	if breaker := g.breaker(ds); breaker != nil {
+		if ok, retryAfter := breaker.allow(g.now()); !ok {
+			metrics.MDataSourceProxyCircuitRejected.WithLabelValues(ds.Type).Inc()
+			return &rejection{status: http.StatusServiceUnavailable, message: "Data source is unavailable after too many failed requests", retryAfter: retryAfter}
+		}
+	}
+
+	return nil
+}
+
+// observe reports the status of a proxied request to the circuit breaker of the data source.
+func (g *requestGuard) observe(ds *datasources.DataSource, status int) {
+	if g == nil {
+		return
+	}
+	breaker := g.breaker(ds)
+	if breaker == nil {
+		return
+	}
+	if opened := breaker.report(g.now(), status < http.StatusInternalServerError); opened {
+		metrics.MDataSourceProxyCircuitOpened.WithLabelValues(ds.Type).Inc()
+	}
+}
+
+func (g *requestGuard) limiter(ds *datasources.DataSource) *rate.Limiter {
+	limit, burst := g.rateLimit, g.rateLimitBurst
+	if ds.JsonData != nil {
+		if override := ds.JsonData.Get(rateLimitJSONField).MustFloat64(0); override > 0 {
+			limit = override
+			burst = ds.JsonData.Get(rateLimitBurstJSONField).MustInt(int(math.Ceil(override)))
+		}
+	}
+	if limit <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(limit))
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	l, ok := g.limiters[ds.UID]
+	// recreate the limiter if the data source settings changed
+	if !ok || l.limit != rate.Limit(limit) || l.burst != burst {
+		l = &dsLimiter{limit: rate.Limit(limit), burst: burst, limiter: rate.NewLimiter(rate.Limit(limit), burst)}
+		g.limiters[ds.UID] = l
+	}
+	return l.limiter
+}
+
+func (g *requestGuard) breaker(ds *datasources.DataSource) *circuitBreaker {
+	if g.breakerThreshold <= 0 {
+		return nil
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	b, ok := g.breakers[ds.UID]
+	if !ok {
+		b = &circuitBreaker{threshold: g.breakerThreshold, openTimeout: g.breakerTimeout}
+		g.breakers[ds.UID] = b
+	}
+	return b
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops requests to a data source after a number of consecutive failures.
+// Once the open timeout elapsed a single probe request is let through, closing the circuit if it succeeds.
+type circuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mtx      sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func (b *circuitBreaker) allow(now time.Time) (bool, time.Duration) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	switch b.state {
+	case circuitOpen, circuitHalfOpen:
+		// in the half-open state a probe request is in flight, another one is only
+		// let through if its outcome was not reported within the open timeout
+		if elapsed := now.Sub(b.openedAt); elapsed < b.openTimeout {
+			return false, b.openTimeout - elapsed
+		}
+		b.state = circuitHalfOpen
+		b.openedAt = now
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+// report records the outcome of a request and returns true if it opened the circuit.
+func (b *circuitBreaker) report(now time.Time, success bool) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if success {
+		b.state = circuitClosed
+		b.failures = 0
+		return false
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || (b.state == circuitClosed && b.failures >= b.threshold) {
+		b.state = circuitOpen
+		b.openedAt = now
+		return true
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package datasourceproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRequestGuard_RateLimit(t *testing.T) {
+	now := time.Now()
+	newGuard := func(cfg *setting.Cfg) *requestGuard {
+		g := newRequestGuard(cfg)
+		g.now = func() time.Time { return now }
+		return g
+	}
+
+	t.Run("nil guard allows all requests", func(t *testing.T) {
+		var g *requestGuard
+		require.Nil(t, g.allow(&datasources.DataSource{UID: "ds"}))
+	})
+
+	t.Run("requests are limited per data source", func(t *testing.T) {
+		g := newGuard(&setting.Cfg{DataProxyRateLimit: 1, DataProxyRateLimitBurst: 2})
+		ds1 := &datasources.DataSource{UID: "ds1"}
+		ds2 := &datasources.DataSource{UID: "ds2"}
+
+		require.Nil(t, g.allow(ds1))
+		require.Nil(t, g.allow(ds1))
+		r := g.allow(ds1)
+		require.NotNil(t, r)
+		require.Equal(t, http.StatusTooManyRequests, r.status)
+		require.Equal(t, "1", r.retryAfterSeconds())
+
+		require.Nil(t, g.allow(ds2))
+
+		now = now.Add(time.Second)
+		require.Nil(t, g.allow(ds1))
+	})
+
+	t.Run("data source settings override the configured limit", func(t *testing.T) {
+		g := newGuard(&setting.Cfg{})
+		ds := &datasources.DataSource{UID: "ds", JsonData: simplejson.NewFromAny(map[string]any{
+			rateLimitJSONField:      0.5,
+			rateLimitBurstJSONField: 1,
+		})}
+
+		require.Nil(t, g.allow(ds))
+		r := g.allow(ds)
+		require.NotNil(t, r)
+		require.Equal(t, "2", r.retryAfterSeconds())
+
+		require.Nil(t, g.allow(&datasources.DataSource{UID: "other"}))
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := &circuitBreaker{threshold: 2, openTimeout: 10 * time.Second}
+
+	ok, _ := b.allow(now)
+	require.True(t, ok)
+	require.False(t, b.report(now, false))
+	require.True(t, b.report(now, false), "circuit should open after 2 consecutive failures")
+
+	ok, retryAfter := b.allow(now.Add(4 * time.Second))
+	require.False(t, ok)
+	require.Equal(t, 6*time.Second, retryAfter)
+
+	// a single probe request is allowed once the timeout elapsed
+	now = now.Add(10 * time.Second)
+	ok, _ = b.allow(now)
+	require.True(t, ok)
+	ok, _ = b.allow(now)
+	require.False(t, ok)
+
+	// a failed probe opens the circuit again
+	require.True(t, b.report(now, false))
+	ok, _ = b.allow(now.Add(time.Second))
+	require.False(t, ok)
+
+	// a successful probe closes it
+	now = now.Add(10 * time.Second)
+	ok, _ = b.allow(now)
+	require.True(t, ok)
+	require.False(t, b.report(now, true))
+	ok, _ = b.allow(now)
+	require.True(t, ok)
+	require.False(t, b.report(now, false), "failures are counted from zero after the circuit closed")
+}
+
+func TestDatasourceProxy_proxyDatasourceRequest_limits(t *testing.T) {
+	pluginID := datasources.DS_PROMETHEUS
+	p := DataSourceProxyService{
+		PluginRequestValidator: &fakePluginRequestValidator{},
+		pluginStore: &pluginstore.FakePluginStore{PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: pluginID}},
+		}},
+		guard: newRequestGuard(&setting.Cfg{DataProxyBreakerThreshold: 1, DataProxyBreakerOpenTimeout: time.Minute}),
+	}
+	ds := &datasources.DataSource{UID: "ds", Type: pluginID}
+	p.guard.observe(ds, http.StatusBadGateway)
+
+	responseRecorder := httptest.NewRecorder()
+	c := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req:  &http.Request{URL: &url.URL{}},
+			Resp: web.NewResponseWriter("GET", responseRecorder),
+		},
+		Logger: log.NewNopLogger(),
+	}
+	p.proxyDatasourceRequest(c, ds)
+
+	resp := responseRecorder.Result()
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "60", resp.Header.Get("Retry-After"))
+}
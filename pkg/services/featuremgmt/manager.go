@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
@@ -24,6 +25,9 @@ type FeatureManager struct {
 	startup  map[string]bool   // the explicit values registered at startup
 	warnings map[string]string // potential warnings about the flag
 	log      log.Logger
+
+	// problems of the configuration detected at startup
+	validationProblems []ValidationProblem
 }
 
 // This will merge the flags with the current configuration
@@ -46,6 +50,9 @@ func (fm *FeatureManager) registerFlags(flags ...FeatureFlag) {
 		if add.Expression != "" {
 			flag.Expression = add.Expression
 		}
+		if !add.Expires.IsZero() {
+			flag.Expires = add.Expires
+		}
 
 		// The most recently defined state
 		if add.Stage != FeatureStageUnknown {
@@ -101,6 +108,21 @@ func (fm *FeatureManager) update() {
 	fm.enabled = enabled
 }
 
+// checkExpiry warns about the flags past their expiry date, so they are retired
+func (fm *FeatureManager) checkExpiry(now time.Time) {
+	for _, flag := range fm.flags {
+		if !flag.IsExpired(now) {
+			featureToggleExpired.WithLabelValues(flag.Name).Set(0)
+			continue
+		}
+		featureToggleExpired.WithLabelValues(flag.Name).Set(1)
+		if _, ok := fm.warnings[flag.Name]; !ok {
+			fm.warnings[flag.Name] = fmt.Sprintf("expired on %s", flag.Expires.Format(time.DateOnly))
+		}
+		fm.log.Warn("Feature toggle is past its expiry date", "flag", flag.Name, "expires", flag.Expires.Format(time.DateOnly), "owner", flag.Owner)
+	}
+}
+
 // IsEnabled checks if a feature is enabled
 func (fm *FeatureManager) IsEnabled(ctx context.Context, flag string) bool {
 	return fm.enabled[flag]
@@ -3,8 +3,11 @@ package featuremgmt
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 )
 
 func TestFeatureManager(t *testing.T) {
@@ -65,4 +68,22 @@ func TestFeatureManager(t *testing.T) {
 		require.False(t, ft.IsEnabledGlobally("b"))
 		require.False(t, ft.IsEnabledGlobally("c"))
 	})
+	t.Run("check expired flags", func(t *testing.T) {
+		ft := FeatureManager{
+			flags:    map[string]*FeatureFlag{},
+			warnings: map[string]string{},
+			log:      log.NewNopLogger(),
+		}
+		ft.registerFlags(FeatureFlag{
+			Name:    "a",
+			Expires: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, FeatureFlag{
+			Name:    "b",
+			Expires: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		}, FeatureFlag{
+			Name: "c",
+		})
+		ft.checkExpiry(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, map[string]string{"a": "expired on 2024-01-01"}, ft.GetWarning())
+	})
 }
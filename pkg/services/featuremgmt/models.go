@@ -116,6 +116,7 @@ type FeatureFlag struct {
 	Description string           `json:"description"`
 	Stage       FeatureFlagStage `json:"stage,omitempty"`
 	Created     time.Time        `json:"created,omitempty"` // when the flag was introduced
+	Expires     time.Time        `json:"expires,omitempty"` // when the flag should be removed, or the feature reach GA
 	Owner       codeowner        `json:"-"`                 // Owner person or team that owns this feature flag
 
 	// Recommended properties - control behavior of the feature toggle management page in the UI
@@ -134,6 +135,11 @@ type FeatureFlag struct {
 	RequiresRestart bool `json:"requiresRestart,omitempty"`
 }
 
+// IsExpired checks if the flag is past its expiry date
+func (f *FeatureFlag) IsExpired(now time.Time) bool {
+	return !f.Expires.IsZero() && now.After(f.Expires)
+}
+
 type FeatureToggleWebhookPayload struct {
 	FeatureToggles map[string]string `json:"feature_toggles"`
 	User           string            `json:"user"`
@@ -1,6 +1,8 @@
 package featuremgmt
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
@@ -15,6 +17,12 @@ var (
 		Help:      "info metric that exposes what feature toggles are enabled or not",
 		Namespace: "grafana",
 	}, []string{"name"})
+
+	featureToggleExpired = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "feature_toggles_expired",
+		Help:      "info metric that exposes what feature toggles are past their expiry date",
+		Namespace: "grafana",
+	}, []string{"name"})
 )
 
 func ProvideManagerService(cfg *setting.Cfg) (*FeatureManager, error) {
@@ -32,18 +40,22 @@ func ProvideManagerService(cfg *setting.Cfg) (*FeatureManager, error) {
 	mgmt.registerFlags(standardFeatureFlags...)
 
 	// Load the flags from `custom.ini` files
-	flags, err := setting.ReadFeatureTogglesFromInitFile(cfg.Raw.Section("feature_toggles"))
+	section := cfg.Raw.Section("feature_toggles")
+	flags, err := setting.ReadFeatureTogglesFromInitFile(section)
 	if err != nil {
 		return mgmt, err
 	}
+	mgmt.validationProblems = mgmt.validateStartupConfig(section)
+	for _, p := range mgmt.validationProblems {
+		mgmt.log.Warn("Invalid feature toggle configuration", "flag", p.Name, "problem", p.Type, "message", p.Message)
+	}
+
 	for key, val := range flags {
 		_, ok := mgmt.flags[key]
 		if !ok {
-			switch key {
-			// renamed the flag so it supports more panels
-			case "autoMigrateGraphPanels":
-				key = FlagAutoMigrateOldPanels
-			default:
+			if newName, renamed := renamedFlags[key]; renamed {
+				key = newName
+			} else {
 				mgmt.flags[key] = &FeatureFlag{
 					Name:  key,
 					Stage: FeatureStageUnknown,
@@ -56,6 +68,7 @@ func ProvideManagerService(cfg *setting.Cfg) (*FeatureManager, error) {
 
 	// update the values
 	mgmt.update()
+	mgmt.checkExpiry(time.Now())
 
 	// Minimum approach to avoid circular dependency
 	// nolint:staticcheck
@@ -49,6 +49,10 @@ func TestFeatureToggleFiles(t *testing.T) {
 				t.Errorf("flag requires a reasonable created date.  See: %s (%s)",
 					flag.Name, flag.Created.Format(time.DateOnly))
 			}
+			if !flag.Expires.IsZero() && !flag.Expires.After(flag.Created) {
+				t.Errorf("flag expiry date must be after its created date.  See: %s (%s)",
+					flag.Name, flag.Expires.Format(time.DateOnly))
+			}
 		}
 	})
 
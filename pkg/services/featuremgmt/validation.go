@@ -0,0 +1,123 @@
+package featuremgmt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// Types of the problems of the feature toggle configuration
+const (
+	ValidationProblemUnknown         = "unknown"
+	ValidationProblemDeprecated      = "deprecated"
+	ValidationProblemConflict        = "conflict"
+	ValidationProblemRequiresDevMode = "requiresDevMode"
+)
+
+// ValidationProblem is a problem of the feature toggle configuration detected at startup
+type ValidationProblem struct {
+	// The flag name, as it is written in the configuration
+	Name    string
+	Type    string
+	Message string
+}
+
+// The old names of renamed flags, mapped to their current names
+var renamedFlags = map[string]string{
+	// renamed the flag so it supports more panels
+	"autoMigrateGraphPanels": FlagAutoMigrateOldPanels,
+}
+
+// validateStartupConfig returns the problems of the `feature_toggles` section of the configuration.
+// The `enable` list and the individual settings are read separately, since an individual setting
+// silently overrides the list.
+func (fm *FeatureManager) validateStartupConfig(section *ini.Section) []ValidationProblem {
+	listed := make(map[string]bool)
+	resolved := make(map[string]bool)
+	for _, name := range util.SplitString(section.Key("enable").MustString("")) {
+		listed[name] = true
+		resolved[name] = true
+	}
+
+	var problems []ValidationProblem
+	for _, key := range section.Keys() {
+		if key.Name() == "enable" {
+			continue
+		}
+		val, err := strconv.ParseBool(key.Value())
+		if err != nil {
+			continue // grafana does not start with invalid values
+		}
+		if listed[key.Name()] && !val {
+			problems = append(problems, ValidationProblem{
+				Name:    key.Name(),
+				Type:    ValidationProblemConflict,
+				Message: "the flag is in the enable list, but it is disabled by its own setting, so it is disabled",
+			})
+		}
+		resolved[key.Name()] = val
+	}
+
+	names := make([]string, 0, len(resolved))
+	for name := range resolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		enabled := resolved[name]
+		if newName, ok := renamedFlags[name]; ok {
+			problems = append(problems, ValidationProblem{
+				Name:    name,
+				Type:    ValidationProblemDeprecated,
+				Message: fmt.Sprintf("the flag was renamed to %s", newName),
+			})
+			if val, ok := resolved[newName]; ok && val != enabled {
+				problems = append(problems, ValidationProblem{
+					Name:    name,
+					Type:    ValidationProblemConflict,
+					Message: fmt.Sprintf("the flag and its new name %s are set to different values, only one of them is used", newName),
+				})
+			}
+			continue
+		}
+
+		flag, ok := fm.flags[name]
+		if !ok || flag.Stage == FeatureStageUnknown {
+			problems = append(problems, ValidationProblem{
+				Name:    name,
+				Type:    ValidationProblemUnknown,
+				Message: "unknown flag in config, it has no effect",
+			})
+			continue
+		}
+		if flag.Stage == FeatureStageDeprecated {
+			problems = append(problems, ValidationProblem{
+				Name:    name,
+				Type:    ValidationProblemDeprecated,
+				Message: "the flag is deprecated and will be removed in a future release",
+			})
+		}
+		if enabled && flag.RequiresDevMode && !fm.isDevMod {
+			problems = append(problems, ValidationProblem{
+				Name:    name,
+				Type:    ValidationProblemRequiresDevMode,
+				Message: "the flag requires dev mode, so it is not enabled",
+			})
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Name < problems[j].Name
+	})
+	return problems
+}
+
+// GetValidationProblems returns the problems of the feature toggle configuration detected at startup
+func (fm *FeatureManager) GetValidationProblems() []ValidationProblem {
+	return fm.validationProblems
+}
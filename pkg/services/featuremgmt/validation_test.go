@@ -0,0 +1,42 @@
+package featuremgmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestValidateStartupConfig(t *testing.T) {
+	raw, err := ini.Load([]byte(`
+[feature_toggles]
+enable = unknownFlag, unifiedStorage, topnav, grpcServer
+grpcServer = false
+autoMigrateGraphPanels = true
+autoMigrateOldPanels = false
+`))
+	require.NoError(t, err)
+
+	cfg := setting.NewCfg()
+	cfg.Raw = raw
+	cfg.Env = setting.Prod
+	mgmt, err := ProvideManagerService(cfg)
+	require.NoError(t, err)
+
+	require.Equal(t, []ValidationProblem{
+		{Name: "autoMigrateGraphPanels", Type: ValidationProblemDeprecated, Message: "the flag was renamed to autoMigrateOldPanels"},
+		{Name: "autoMigrateGraphPanels", Type: ValidationProblemConflict, Message: "the flag and its new name autoMigrateOldPanels are set to different values, only one of them is used"},
+		{Name: "grpcServer", Type: ValidationProblemConflict, Message: "the flag is in the enable list, but it is disabled by its own setting, so it is disabled"},
+		{Name: "topnav", Type: ValidationProblemDeprecated, Message: "the flag is deprecated and will be removed in a future release"},
+		{Name: "unifiedStorage", Type: ValidationProblemRequiresDevMode, Message: "the flag requires dev mode, so it is not enabled"},
+		{Name: "unknownFlag", Type: ValidationProblemUnknown, Message: "unknown flag in config, it has no effect"},
+	}, mgmt.GetValidationProblems())
+
+	t.Run("should not report problems without configuration", func(t *testing.T) {
+		mgmt, err := ProvideManagerService(setting.NewCfg())
+		require.NoError(t, err)
+		require.Empty(t, mgmt.GetValidationProblems())
+	})
+}
@@ -599,6 +599,42 @@ func (s *Service) Create(ctx context.Context, cmd *folder.CreateFolderCommand) (
 	return f, nil
 }
 
+func (s *Service) CreateOrGet(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, bool, error) {
+	f, err := s.Create(ctx, cmd)
+	if err == nil {
+		return f, true, nil
+	}
+
+	q := &folder.GetFolderQuery{
+		OrgID:        cmd.OrgID,
+		SignedInUser: cmd.SignedInUser,
+	}
+	switch {
+	case errors.Is(err, dashboards.ErrFolderWithSameUIDExists):
+		uid := strings.TrimSpace(cmd.UID)
+		q.UID = &uid
+	case errors.Is(err, dashboards.ErrFolderSameNameExists):
+		title := cmd.Title
+		q.Title = &title
+		if cmd.ParentUID != "" {
+			parentUID := cmd.ParentUID
+			q.ParentUID = &parentUID
+		}
+	default:
+		return nil, false, err
+	}
+
+	existing, getErr := s.Get(ctx, q)
+	if getErr != nil {
+		// the UID can be taken by a dashboard, in which case the conflict is reported as is
+		if errors.Is(getErr, dashboards.ErrFolderNotFound) {
+			return nil, false, err
+		}
+		return nil, false, getErr
+	}
+	return existing, false, nil
+}
+
 func (s *Service) Update(ctx context.Context, cmd *folder.UpdateFolderCommand) (*folder.Folder, error) {
 	logger := s.log.FromContext(ctx)
 
@@ -791,7 +827,7 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 				return err
 			}
 			if alertRulesInFolder > 0 {
-				return folder.ErrFolderNotEmpty.Errorf("folder contains %d alert rules", alertRulesInFolder)
+				return s.alertRulesInFolderError(ctx, cmd, alertRuleSrv, folders, alertRulesInFolder)
 			}
 		}
 
@@ -801,7 +837,40 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.bus.Publish(ctx, &events.FolderDeleted{
+		Timestamp: time.Now(),
+		UIDs:      folders,
+		OrgID:     cmd.OrgID,
+	}); err != nil {
+		logger.Error("failed to publish FolderDeleted event", "folder", cmd.UID, "error", err)
+	}
+
+	return nil
+}
 
+// alertRulesInFolderError returns the conflict error of a folder that cannot be deleted because it, or one of its
+// subfolders, contains alert rules. The rule groups are listed in the public payload of the error so that users know
+// what would be deleted along with the folder.
+func (s *Service) alertRulesInFolderError(ctx context.Context, cmd *folder.DeleteFolderCommand, alertRuleSrv folder.RegistryService, folderUIDs []string, count int64) error {
+	err := folder.ErrFolderContainsAlertRules.Errorf("folder %s contains %d alert rules", cmd.UID, count)
+	groups := []folder.RegistryGroup{}
+	if lister, ok := alertRuleSrv.(folder.RegistryGroupLister); ok {
+		var listErr error
+		groups, listErr = lister.ListGroupsInFolders(ctx, cmd.OrgID, folderUIDs, cmd.SignedInUser)
+		if listErr != nil {
+			s.log.FromContext(ctx).Error("failed to list alert rule groups in folder", "error", listErr)
+			return listErr
+		}
+	}
+	err.PublicPayload = map[string]any{
+		"folderUid":  cmd.UID,
+		"alertRules": count,
+		"ruleGroups": groups,
+	}
 	return err
 }
 
@@ -948,6 +1017,42 @@ func (s *Service) nestedFolderDelete(ctx context.Context, cmd *folder.DeleteFold
 	return descendantUIDs, nil
 }
 
+func (s *Service) GetDescendants(ctx context.Context, q *folder.GetDescendantsQuery) ([]*folder.Folder, error) {
+	if q.SignedInUser == nil {
+		return nil, folder.ErrBadRequest.Errorf("missing signed-in user")
+	}
+	if q.UID == nil || *q.UID == "" {
+		return nil, folder.ErrBadRequest.Errorf("missing UID")
+	}
+	if q.OrgID < 1 {
+		return nil, folder.ErrBadRequest.Errorf("invalid orgID")
+	}
+
+	if !s.features.IsEnabled(ctx, featuremgmt.FlagNestedFolders) {
+		return []*folder.Folder{}, nil
+	}
+
+	descendants, err := s.store.GetDescendants(ctx, q.OrgID, *q.UID)
+	if err != nil {
+		return nil, err
+	}
+	if len(descendants) == 0 {
+		return []*folder.Folder{}, nil
+	}
+
+	uids := make([]string, 0, len(descendants))
+	for _, f := range descendants {
+		uids = append(uids, f.UID)
+	}
+
+	return s.GetFolders(ctx, folder.GetFoldersQuery{
+		OrgID:        q.OrgID,
+		UIDs:         uids,
+		WithFullpath: true,
+		SignedInUser: q.SignedInUser,
+	})
+}
+
 func (s *Service) GetDescendantCounts(ctx context.Context, q *folder.GetDescendantCountsQuery) (folder.DescendantCounts, error) {
 	logger := s.log.FromContext(ctx)
 	if q.SignedInUser == nil {
@@ -985,6 +1090,28 @@ func (s *Service) GetDescendantCounts(ctx context.Context, q *folder.GetDescenda
 	return countsMap, nil
 }
 
+func (s *Service) GetItemsInFolders(ctx context.Context, q *folder.GetItemsInFoldersQuery) ([]folder.RegistryItem, error) {
+	if q.SignedInUser == nil {
+		return nil, folder.ErrBadRequest.Errorf("missing signed-in user")
+	}
+	if q.OrgID < 1 {
+		return nil, folder.ErrBadRequest.Errorf("invalid orgID")
+	}
+	if len(q.FolderUIDs) == 0 {
+		return []folder.RegistryItem{}, nil
+	}
+
+	r, ok := s.registry[q.Kind]
+	if !ok {
+		return nil, folder.ErrBadRequest.Errorf("unknown kind %s", q.Kind)
+	}
+	lister, ok := r.(folder.RegistryLister)
+	if !ok {
+		return nil, folder.ErrBadRequest.Errorf("resources of kind %s cannot be listed", q.Kind)
+	}
+	return lister.ListInFolders(ctx, q.OrgID, q.FolderUIDs, q.SignedInUser, q.Limit, q.Page)
+}
+
 // buildSaveDashboardCommand is a simplified version on DashboardServiceImpl.buildSaveDashboardCommand
 // keeping only the meaningful functionality for folders
 func (s *Service) buildSaveDashboardCommand(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*dashboards.SaveDashboardCommand, error) {
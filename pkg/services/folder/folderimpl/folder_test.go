@@ -44,6 +44,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var orgID = int64(1)
@@ -588,7 +589,7 @@ func TestIntegrationNestedFolderService(t *testing.T) {
 				prefix:       "flagon-noforce",
 				depth:        3,
 				forceDelete:  false,
-				deletionErr:  folder.ErrFolderNotEmpty,
+				deletionErr:  folder.ErrFolderContainsAlertRules,
 				desc:         "With nested folder feature flag on and no force deletion of rules",
 			},
 			{
@@ -608,7 +609,7 @@ func TestIntegrationNestedFolderService(t *testing.T) {
 				prefix:       "flagoff-noforce",
 				depth:        1,
 				forceDelete:  false,
-				deletionErr:  folder.ErrFolderNotEmpty,
+				deletionErr:  folder.ErrFolderContainsAlertRules,
 				desc:         "With nested folder feature flag off and no force deletion of rules",
 			},
 		}
@@ -671,6 +672,15 @@ func TestIntegrationNestedFolderService(t *testing.T) {
 
 				err = tc.service.Delete(context.Background(), &deleteCmd)
 				require.ErrorIs(t, err, tc.deletionErr)
+				if tc.deletionErr != nil {
+					var conflictErr errutil.Error
+					require.ErrorAs(t, err, &conflictErr)
+					expectedGroups := []folder.RegistryGroup{{FolderUID: parent.UID, Count: 1}}
+					if tc.depth > 1 {
+						expectedGroups = append(expectedGroups, folder.RegistryGroup{FolderUID: subfolder.UID, Count: 1})
+					}
+					require.ElementsMatch(t, expectedGroups, conflictErr.PublicPayload["ruleGroups"])
+				}
 
 				for i, ancestor := range ancestors {
 					// dashboard table
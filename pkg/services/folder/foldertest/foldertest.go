@@ -11,6 +11,9 @@ type FakeService struct {
 	ExpectedFolder           *folder.Folder
 	ExpectedError            error
 	ExpectedDescendantCounts map[string]int64
+	ExpectedItems            []folder.RegistryItem
+	// ExpectedCreated is returned by CreateOrGet
+	ExpectedCreated bool
 }
 
 func NewFakeService() *FakeService {
@@ -30,6 +33,11 @@ func (s *FakeService) GetParents(ctx context.Context, q folder.GetParentsQuery)
 func (s *FakeService) Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error) {
 	return s.ExpectedFolder, s.ExpectedError
 }
+
+func (s *FakeService) CreateOrGet(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, bool, error) {
+	return s.ExpectedFolder, s.ExpectedCreated, s.ExpectedError
+}
+
 func (s *FakeService) Get(ctx context.Context, q *folder.GetFolderQuery) (*folder.Folder, error) {
 	return s.ExpectedFolder, s.ExpectedError
 }
@@ -52,6 +60,14 @@ func (s *FakeService) GetDescendantCounts(ctx context.Context, q *folder.GetDesc
 	return s.ExpectedDescendantCounts, s.ExpectedError
 }
 
+func (s *FakeService) GetDescendants(ctx context.Context, q *folder.GetDescendantsQuery) ([]*folder.Folder, error) {
+	return s.ExpectedFolders, s.ExpectedError
+}
+
+func (s *FakeService) GetItemsInFolders(ctx context.Context, q *folder.GetItemsInFoldersQuery) ([]folder.RegistryItem, error) {
+	return s.ExpectedItems, s.ExpectedError
+}
+
 func (s *FakeService) GetFolders(ctx context.Context, q folder.GetFoldersQuery) ([]*folder.Folder, error) {
 	return s.ExpectedFolders, s.ExpectedError
 }
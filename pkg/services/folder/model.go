@@ -19,6 +19,7 @@ var ErrInternal = errutil.Internal("folder.internal")
 var ErrCircularReference = errutil.BadRequest("folder.circular-reference", errutil.WithPublicMessage("Circular reference detected"))
 var ErrTargetRegistrySrvConflict = errutil.Internal("folder.target-registry-srv-conflict")
 var ErrFolderNotEmpty = errutil.BadRequest("folder.not-empty", errutil.WithPublicMessage("Folder cannot be deleted: folder is not empty"))
+var ErrFolderContainsAlertRules = errutil.Conflict("folder.contains-alert-rules", errutil.WithPublicMessage("Folder cannot be deleted: folder contains alert rules, use forceDeleteRules=true to delete them along with the folder"))
 
 const (
 	GeneralFolderUID      = "general"
@@ -97,6 +98,9 @@ type CreateFolderCommand struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	ParentUID   string `json:"parentUid"`
+	// IgnoreExisting makes the API return the existing folder instead of a conflict error
+	// if a folder with the same UID or the same title in the parent folder already exists.
+	IgnoreExisting bool `json:"ignoreExisting"`
 
 	SignedInUser identity.Requester `json:"-"`
 }
@@ -208,3 +212,24 @@ type GetDescendantCountsQuery struct {
 }
 
 type DescendantCounts map[string]int64
+
+// GetDescendantsQuery captures the information required by the folder service
+// to return all the folders of the subtree of a given folder.
+type GetDescendantsQuery struct {
+	UID   *string
+	OrgID int64
+
+	SignedInUser identity.Requester `json:"-"`
+}
+
+// GetItemsInFoldersQuery captures the information required by the folder service
+// to return a page of the resources of a given kind that are stored in the given folders.
+type GetItemsInFoldersQuery struct {
+	FolderUIDs []string
+	OrgID      int64
+	Kind       string
+	Limit      int64
+	Page       int64
+
+	SignedInUser identity.Requester `json:"-"`
+}
@@ -11,3 +11,31 @@ type RegistryService interface {
 	CountInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester) (int64, error)
 	Kind() string
 }
+
+// RegistryLister is implemented by the registry services that can list the resources they store in folders.
+type RegistryLister interface {
+	// ListInFolders returns a page of the resources stored in the given folders, ordered by folder and title.
+	// If limit is zero all the resources are returned.
+	ListInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester, limit, page int64) ([]RegistryItem, error)
+}
+
+// RegistryItem is a resource stored in a folder.
+type RegistryItem struct {
+	UID       string
+	Title     string
+	FolderUID string
+}
+
+// RegistryGroupLister is implemented by the registry services that store their resources in named groups, like the
+// rule groups of alert rules.
+type RegistryGroupLister interface {
+	// ListGroupsInFolders returns the groups of resources stored in the given folders, ordered by folder and name.
+	ListGroupsInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester) ([]RegistryGroup, error)
+}
+
+// RegistryGroup is a named group of resources stored in a folder.
+type RegistryGroup struct {
+	FolderUID string `json:"folderUid"`
+	Name      string `json:"name"`
+	Count     int64  `json:"count"`
+}
@@ -11,6 +11,10 @@ type Service interface {
 	// otherwise it returns an empty array
 	GetParents(ctx context.Context, q GetParentsQuery) ([]*Folder, error)
 	Create(ctx context.Context, cmd *CreateFolderCommand) (*Folder, error)
+	// CreateOrGet behaves like Create but if a folder with the same UID, or with the same title
+	// in the same parent folder, already exists it returns the existing folder instead of an error.
+	// The returned boolean reports whether the folder has been created.
+	CreateOrGet(ctx context.Context, cmd *CreateFolderCommand) (*Folder, bool, error)
 
 	// GetFolder takes a GetFolderCommand and returns a folder matching the
 	// request. One of UID, ID or Title must be included. If multiple values
@@ -34,6 +38,12 @@ type Service interface {
 	// If FullpathUIDs is true it computes a string that contains the UIDs of all parent folders separated by slash.
 	GetFolders(ctx context.Context, q GetFoldersQuery) ([]*Folder, error)
 	GetDescendantCounts(ctx context.Context, q *GetDescendantCountsQuery) (DescendantCounts, error)
+	// GetDescendants returns all the folders in the subtree of the given folder that are accessible by the signed in user.
+	// The full path of each folder is computed. It returns an empty list if nested folders are disabled.
+	GetDescendants(ctx context.Context, q *GetDescendantsQuery) ([]*Folder, error)
+	// GetItemsInFolders returns a page of the resources of the given kind stored in the given folders.
+	// It returns ErrBadRequest if resources of that kind cannot be listed.
+	GetItemsInFolders(ctx context.Context, q *GetItemsInFoldersQuery) ([]RegistryItem, error)
 }
 
 // FolderStore is a folder store.
@@ -194,6 +194,9 @@ var (
 				{
 					Action: accesscontrol.ActionAlertingProvisioningReadSecrets, // organization scope
 				},
+				{
+					Action: accesscontrol.ActionAlertingReceiversReadSecrets, // organization scope
+				},
 				{
 					Action: accesscontrol.ActionAlertingProvisioningRead, // organization scope
 				},
@@ -5,7 +5,6 @@ import (
 
 	"golang.org/x/net/context"
 
-	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -58,16 +57,12 @@ func (r *RuleService) getRulesQueryEvaluator(rules ...*models.AlertRule) accessc
 	added := make(map[string]struct{}, 2)
 	evals := make([]accesscontrol.Evaluator, 0, 2)
 	for _, rule := range rules {
-		for _, query := range rule.Data {
-			if query.QueryType == expr.DatasourceType || query.DatasourceUID == expr.DatasourceUID || query.
-				DatasourceUID == expr.OldDatasourceUID {
+		for _, uid := range rule.GetDatasourceUIDs() {
+			if _, ok := added[uid]; ok {
 				continue
 			}
-			if _, ok := added[query.DatasourceUID]; ok {
-				continue
-			}
-			evals = append(evals, accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID)))
-			added[query.DatasourceUID] = struct{}{}
+			evals = append(evals, accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid)))
+			added[uid] = struct{}{}
 		}
 	}
 	if len(evals) == 1 {
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// validateAnnotationSchema checks the rules against the annotation schema of the organization.
+// Returns nil if the rules satisfy the schema, or a response that describes all violations.
+func validateAnnotationSchema(cfg *ngmodels.AdminConfiguration, rules ...*ngmodels.AlertRule) response.Response {
+	var schema ngmodels.AnnotationSchema
+	if cfg != nil {
+		schema = cfg.AnnotationSchema
+	}
+
+	var violations []apimodels.AnnotationViolation
+	for _, rule := range rules {
+		for _, v := range schema.Check(rule) {
+			violations = append(violations, apimodels.AnnotationViolation{
+				RuleUID:    v.RuleUID,
+				RuleTitle:  v.RuleTitle,
+				Annotation: v.Annotation,
+				Reason:     v.Reason,
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return response.JSON(http.StatusBadRequest, apimodels.AnnotationViolationsError{
+		Message:    fmt.Sprintf("%d annotations do not satisfy the annotation schema of the organization", len(violations)),
+		Violations: violations,
+	})
+}
+
+// rulesWithChangedAnnotations returns the rules that are not in the existing group, or whose annotations differ from
+// the existing rule. Only they are checked against the annotation schema, so that rules created before the schema was
+// changed do not block unrelated changes to their group.
+func rulesWithChangedAnnotations(existing ngmodels.RulesGroup, rules []*ngmodels.AlertRule) []*ngmodels.AlertRule {
+	byUID := make(map[string]*ngmodels.AlertRule, len(existing))
+	for _, rule := range existing {
+		byUID[rule.UID] = rule
+	}
+	result := make([]*ngmodels.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if e, ok := byUID[rule.UID]; ok && rule.UID != "" && maps.Equal(e.Annotations, rule.Annotations) {
+			continue
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+func annotationSchemaFromAPI(schema []apimodels.AnnotationRequirement) ngmodels.AnnotationSchema {
+	if len(schema) == 0 {
+		return nil
+	}
+	result := make(ngmodels.AnnotationSchema, 0, len(schema))
+	for _, r := range schema {
+		result = append(result, ngmodels.AnnotationRequirement{
+			Name:     r.Name,
+			Required: r.Required,
+			URL:      r.URL,
+		})
+	}
+	return result
+}
+
+func annotationSchemaToAPI(schema ngmodels.AnnotationSchema) []apimodels.AnnotationRequirement {
+	if len(schema) == 0 {
+		return nil
+	}
+	result := make([]apimodels.AnnotationRequirement, 0, len(schema))
+	for _, r := range schema {
+		result = append(result, apimodels.AnnotationRequirement{
+			Name:     r.Name,
+			Required: r.Required,
+			URL:      r.URL,
+		})
+	}
+	return result
+}
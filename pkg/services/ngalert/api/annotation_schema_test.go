@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestValidateAnnotationSchema(t *testing.T) {
+	rules := []*ngmodels.AlertRule{
+		ngmodels.AlertRuleGen(ngmodels.WithAnnotations(map[string]string{ngmodels.RunbookURLAnnotation: "https://runbooks.example.com"}))(),
+		ngmodels.AlertRuleGen(ngmodels.WithAnnotations(map[string]string{ngmodels.RunbookURLAnnotation: "not a url"}))(),
+		ngmodels.AlertRuleGen(ngmodels.WithAnnotations(nil))(),
+	}
+
+	t.Run("should only validate the runbook URL if the organization has no schema", func(t *testing.T) {
+		require.Nil(t, validateAnnotationSchema(nil, rules[0], rules[2]))
+		require.Nil(t, validateAnnotationSchema(&ngmodels.AdminConfiguration{}, rules[0], rules[2]))
+
+		resp := validateAnnotationSchema(nil, rules...)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should return all violations", func(t *testing.T) {
+		cfg := &ngmodels.AdminConfiguration{AnnotationSchema: ngmodels.AnnotationSchema{
+			{Name: ngmodels.RunbookURLAnnotation, Required: true, URL: true},
+		}}
+		require.Nil(t, validateAnnotationSchema(cfg, rules[0]))
+
+		resp := validateAnnotationSchema(cfg, rules...)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+
+		var body apimodels.AnnotationViolationsError
+		require.NoError(t, json.Unmarshal(resp.Body(), &body))
+		require.Len(t, body.Violations, 2)
+		require.Equal(t, rules[1].UID, body.Violations[0].RuleUID)
+		require.Equal(t, ngmodels.RunbookURLAnnotation, body.Violations[0].Annotation)
+		require.Equal(t, rules[2].UID, body.Violations[1].RuleUID)
+		require.Equal(t, "is required", body.Violations[1].Reason)
+	})
+}
+
+func TestRulesWithChangedAnnotations(t *testing.T) {
+	existing := ngmodels.RulesGroup{
+		ngmodels.AlertRuleGen(ngmodels.WithAnnotations(map[string]string{"summary": "a"}))(),
+		ngmodels.AlertRuleGen(ngmodels.WithAnnotations(map[string]string{"summary": "b"}))(),
+	}
+	unchanged := ngmodels.CopyRule(existing[0])
+	changed := ngmodels.CopyRule(existing[1])
+	changed.Annotations = map[string]string{"summary": "c"}
+	added := ngmodels.AlertRuleGen()()
+	added.UID = ""
+
+	result := rulesWithChangedAnnotations(existing, []*ngmodels.AlertRule{unchanged, changed, added})
+	require.Equal(t, []*ngmodels.AlertRule{changed, added}, result)
+}
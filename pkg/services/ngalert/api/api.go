@@ -5,7 +5,10 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/benbjohnson/clock"
+
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -59,6 +62,15 @@ type API struct {
 	RuleStore            RuleStore
 	AlertingStore        AlertingStore
 	AdminConfigStore     store.AdminConfigurationStore
+	OrgStore             store.OrgStore
+	AdminConfigObserver  AdminConfigObserver
+	LabelPolicyStore     store.LabelPolicyStore
+	PauseWindowStore     store.EvaluationPauseWindowStore
+	PauseWindowObserver  PauseWindowObserver
+	SilenceMetadataStore store.SilenceMetadataStore
+	TemplateVersionStore store.TemplateVersionStore
+	UsageInsightStore    store.UsageInsightStore
+	KVStore              kvstore.KVStore
 	DataProxy            *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	StateManager         *state.Manager
@@ -76,6 +88,9 @@ type API struct {
 	Tracer               tracing.Tracer
 	AppUrl               *url.URL
 	UpgradeService       migration.UpgradeService
+	OrgMetrics           OrgMetricsProvider
+	OrgCleanup           OrgCleanupProgress
+	Scheduler            RuleSchedulingStatusReader
 
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
@@ -89,18 +104,29 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		ac:        api.AccessControl,
 	}
 	ruleAuthzService := accesscontrol.NewRuleService(api.AccessControl)
+	// the ruler and provisioning APIs share the idempotency keys
+	idempotency := newIdempotencyCache(api.Cfg.UnifiedAlerting.IdempotencyKeyTTL, clock.New(), api.KVStore, logger)
 
 	// Register endpoints for proxying to Alertmanager-compatible backends.
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager},
+		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager, cfg: &api.Cfg.UnifiedAlerting, silenceMetadata: api.SilenceMetadataStore},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
 		api.DatasourceCache,
 		NewLotexProm(proxy, logger),
-		&PrometheusSrv{log: logger, manager: api.StateManager, store: api.RuleStore, authz: ruleAuthzService},
+		&PrometheusSrv{
+			log:              logger,
+			manager:          api.StateManager,
+			store:            api.RuleStore,
+			authz:            ruleAuthzService,
+			datasourceCache:  api.DatasourceCache,
+			adminConfigStore: api.AdminConfigStore,
+			cfg:              &api.Cfg.UnifiedAlerting,
+			scheduler:        api.Scheduler,
+		},
 	), m)
 	// Register endpoints for proxying to Cortex Ruler-compatible backends.
 	api.RegisterRulerApiEndpoints(NewForkingRuler(
@@ -115,7 +141,12 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                logger,
 			cfg:                &api.Cfg.UnifiedAlerting,
 			authz:              ruleAuthzService,
+			adminConfigStore:   api.AdminConfigStore,
+			labelPolicyStore:   api.LabelPolicyStore,
+			datasourceCache:    api.DatasourceCache,
+			stateManager:       api.StateManager,
 		},
+		idempotency,
 	), m)
 	api.RegisterTestingApiEndpoints(NewTestingApi(
 		&TestingApiSrv{
@@ -135,8 +166,20 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		&ConfigSrv{
 			datasourceService:    api.DatasourceService,
 			store:                api.AdminConfigStore,
+			ruleStore:            api.RuleStore,
+			labelPolicyStore:     api.LabelPolicyStore,
+			pauseWindowStore:     api.PauseWindowStore,
+			pauseWindowObserver:  api.PauseWindowObserver,
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
+			cfg:                  &api.Cfg.UnifiedAlerting,
+			orgMetrics:           api.OrgMetrics,
+			stateSnapshots:       api.StateManager,
+			orgCleanup:           api.OrgCleanup,
+			adminConfigObserver:  api.AdminConfigObserver,
+			orgStore:             api.OrgStore,
+			quotaService:         api.QuotaService,
+			readOnly:             newReadOnlyModeStore(api.KVStore),
 		},
 	), m)
 
@@ -147,7 +190,14 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
-	}), m)
+		adminConfigStore:    api.AdminConfigStore,
+		datasourceCache:     api.DatasourceCache,
+		stateManager:        api.StateManager,
+		contactPointHealth:  api.MultiOrgAlertmanager,
+		templateVersions:    api.TemplateVersionStore,
+		usageInsights:       api.UsageInsightStore,
+		cfg:                 &api.Cfg.UnifiedAlerting,
+	}, idempotency), m)
 
 	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
 		logger: logger,
@@ -158,6 +208,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		logger:            logger,
 		receiverService:   api.ReceiverService,
 		muteTimingService: api.MuteTimings,
+		mam:               api.MultiOrgAlertmanager,
+		ruleStore:         api.RuleStore,
+		authz:             ruleAuthzService,
 	}), m)
 
 	// Inject upgrade endpoints if legacy alerting is enabled and the feature flag is enabled.
@@ -18,21 +18,28 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	authz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 const (
 	defaultTestReceiversTimeout = 15 * time.Second
 	maxTestReceiversTimeout     = 30 * time.Second
+
+	// maxSilenceReferenceLength is the size of the column in which the reference of a silence is stored.
+	maxSilenceReferenceLength = 190
 )
 
 type AlertmanagerSrv struct {
-	log    log.Logger
-	ac     accesscontrol.AccessControl
-	mam    *notifier.MultiOrgAlertmanager
-	crypto notifier.Crypto
+	log             log.Logger
+	ac              accesscontrol.AccessControl
+	mam             *notifier.MultiOrgAlertmanager
+	crypto          notifier.Crypto
+	cfg             *setting.UnifiedAlertingSettings
+	silenceMetadata store.SilenceMetadataStore
 }
 
 type UnknownReceiverError struct {
@@ -52,12 +59,15 @@ func (srv AlertmanagerSrv) RouteGetAMStatus(c *contextmodel.ReqContext) response
 	return response.JSON(http.StatusOK, am.GetStatus())
 }
 
-func (srv AlertmanagerSrv) RouteCreateSilence(c *contextmodel.ReqContext, postableSilence apimodels.PostableSilence) response.Response {
+func (srv AlertmanagerSrv) RouteCreateSilence(c *contextmodel.ReqContext, postableSilence apimodels.PostableGrafanaSilence) response.Response {
 	err := postableSilence.Validate(strfmt.Default)
 	if err != nil {
 		srv.log.Error("Silence failed validation", "error", err)
 		return ErrResp(http.StatusBadRequest, err, "silence failed validation")
 	}
+	if len(postableSilence.Reference) > maxSilenceReferenceLength {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("reference must not be longer than %d characters", maxSilenceReferenceLength), "silence failed validation")
+	}
 
 	am, errResp := srv.AlertmanagerFor(c.SignedInUser.GetOrgID())
 	if errResp != nil {
@@ -77,7 +87,8 @@ func (srv AlertmanagerSrv) RouteCreateSilence(c *contextmodel.ReqContext, postab
 		return response.Err(authz.NewAuthorizationErrorWithPermissions(fmt.Sprintf("%s silences", errAction), evaluator))
 	}
 
-	silenceID, err := am.CreateSilence(c.Req.Context(), &postableSilence)
+	previousID := postableSilence.ID
+	silenceID, err := am.CreateSilence(c.Req.Context(), &postableSilence.PostableSilence)
 	if err != nil {
 		if errors.Is(err, alertingNotify.ErrSilenceNotFound) {
 			return ErrResp(http.StatusNotFound, err, "")
@@ -89,6 +100,10 @@ func (srv AlertmanagerSrv) RouteCreateSilence(c *contextmodel.ReqContext, postab
 
 		return ErrResp(http.StatusInternalServerError, err, "failed to create silence")
 	}
+	// the silence is in effect at this point, so failing to record its metadata does not fail the request
+	if err := srv.saveSilenceMetadata(c, previousID, silenceID, postableSilence.Reference); err != nil {
+		srv.log.Error("Failed to save the metadata of the silence", "silenceID", silenceID, "error", err)
+	}
 	return response.JSON(http.StatusAccepted, apimodels.PostSilencesOKBody{
 		SilenceID: silenceID,
 	})
@@ -123,6 +138,23 @@ func (srv AlertmanagerSrv) RouteDeleteSilence(c *contextmodel.ReqContext, silenc
 	return response.JSON(http.StatusOK, util.DynMap{"message": "silence deleted"})
 }
 
+// RoutePostPurgeExpiredSilences removes the expired silences of the organization before the end of their retention.
+func (srv AlertmanagerSrv) RoutePostPurgeExpiredSilences(c *contextmodel.ReqContext) response.Response {
+	if _, errResp := srv.AlertmanagerFor(c.SignedInUser.GetOrgID()); errResp != nil {
+		return errResp
+	}
+
+	purged, err := srv.mam.PurgeExpiredSilences(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		if errors.Is(err, notifier.ErrSilencesPurgeNotSupported) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to purge expired silences")
+	}
+	srv.log.Info("Purged expired silences", "user", c.SignedInUser.GetLogin(), "count", purged)
+	return response.JSON(http.StatusOK, apimodels.PurgeExpiredSilencesResult{Purged: purged})
+}
+
 func (srv AlertmanagerSrv) RouteGetAlertingConfig(c *contextmodel.ReqContext) response.Response {
 	config, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
@@ -211,7 +243,11 @@ func (srv AlertmanagerSrv) RouteGetSilence(c *contextmodel.ReqContext, silenceID
 		// any other error here should be an unexpected failure and thus an internal error
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, gettableSilence)
+	result, err := srv.withSilenceMetadata(c.Req.Context(), c.SignedInUser.GetOrgID(), apimodels.GettableSilences{&gettableSilence})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the metadata of the silence")
+	}
+	return response.JSON(http.StatusOK, result[0])
 }
 
 func (srv AlertmanagerSrv) RouteGetSilences(c *contextmodel.ReqContext) response.Response {
@@ -228,7 +264,94 @@ func (srv AlertmanagerSrv) RouteGetSilences(c *contextmodel.ReqContext) response
 		// any other error here should be an unexpected failure and thus an internal error
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, gettableSilences)
+	result, err := srv.withSilenceMetadata(c.Req.Context(), c.SignedInUser.GetOrgID(), gettableSilences)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the metadata of the silences")
+	}
+	if createdBy := c.Query("createdBy"); createdBy != "" {
+		result = filterSilencesByCreator(result, createdBy)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// saveSilenceMetadata records the creator and the reference of a silence. An updated silence keeps its creator,
+// and its reference unless a new one is given, also if the Alertmanager replaced it with a silence with a new ID.
+func (srv AlertmanagerSrv) saveSilenceMetadata(c *contextmodel.ReqContext, previousID, silenceID, reference string) error {
+	orgID := c.SignedInUser.GetOrgID()
+	namespace, id := c.SignedInUser.GetNamespacedID()
+	metadata := &ngmodels.SilenceMetadata{
+		OrgID:          orgID,
+		SilenceID:      silenceID,
+		CreatedBy:      fmt.Sprintf("%s:%s", namespace, id),
+		CreatedByLogin: c.SignedInUser.GetLogin(),
+		Reference:      reference,
+	}
+	if previousID != "" {
+		existing, err := srv.silenceMetadata.GetSilenceMetadata(c.Req.Context(), orgID, []string{previousID})
+		if err != nil {
+			return err
+		}
+		if previous, ok := existing[previousID]; ok {
+			if previous.CreatedBy != "" {
+				metadata.CreatedBy = previous.CreatedBy
+				metadata.CreatedByLogin = previous.CreatedByLogin
+				metadata.Created = previous.Created
+			}
+			if reference == "" {
+				metadata.Reference = previous.Reference
+			}
+		}
+	}
+	return srv.silenceMetadata.SaveSilenceMetadata(c.Req.Context(), metadata)
+}
+
+// withSilenceMetadata adds the metadata recorded by Grafana to the silences.
+func (srv AlertmanagerSrv) withSilenceMetadata(ctx context.Context, orgID int64, silences apimodels.GettableSilences) (apimodels.GettableGrafanaSilences, error) {
+	ids := make([]string, 0, len(silences))
+	for _, s := range silences {
+		if s.ID != nil {
+			ids = append(ids, *s.ID)
+		}
+	}
+	metadata, err := srv.silenceMetadata.GetSilenceMetadata(ctx, orgID, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(apimodels.GettableGrafanaSilences, 0, len(silences))
+	for _, s := range silences {
+		silence := &apimodels.GettableGrafanaSilence{GettableSilence: s}
+		if s.ID != nil {
+			// silences whose expiry was notified before their creator was recorded have no creator
+			if m, ok := metadata[*s.ID]; ok && m.CreatedBy != "" {
+				silence.Metadata = &apimodels.SilenceMetadata{
+					CreatedBy:      m.CreatedBy,
+					CreatedByLogin: m.CreatedByLogin,
+					Reference:      m.Reference,
+					Created:        m.Created,
+				}
+			}
+		}
+		result = append(result, silence)
+	}
+	return result, nil
+}
+
+// filterSilencesByCreator returns the silences that were created by the user with the given login.
+// Silences without metadata are matched by the creator set in the silence itself.
+func filterSilencesByCreator(silences apimodels.GettableGrafanaSilences, login string) apimodels.GettableGrafanaSilences {
+	result := make(apimodels.GettableGrafanaSilences, 0, len(silences))
+	for _, s := range silences {
+		if s.Metadata != nil {
+			if s.Metadata.CreatedByLogin == login {
+				result = append(result, s)
+			}
+			continue
+		}
+		if s.CreatedBy != nil && *s.CreatedBy == login {
+			result = append(result, s)
+		}
+	}
+	return result
 }
 
 func (srv AlertmanagerSrv) RoutePostGrafanaAlertingConfigHistoryActivate(c *contextmodel.ReqContext, id string) response.Response {
@@ -272,9 +395,19 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *contextmodel.ReqContext, b
 			return ErrResp(http.StatusBadRequest, err, "")
 		}
 	}
+	warnings := apimodels.CheckGroupByCardinality(&body.AlertmanagerConfig.Config, srv.cfg.GroupByHighCardinalityLabels)
+	if len(warnings) > 0 && srv.cfg.RejectHighCardinalityGroupBy {
+		return response.JSON(http.StatusBadRequest, apimodels.PostAlertingConfigResult{
+			Message:  "notification policies group notifications by high cardinality labels",
+			Warnings: warnings,
+		})
+	}
 	err = srv.mam.ApplyAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID(), body)
 	if err == nil {
-		return response.JSON(http.StatusAccepted, util.DynMap{"message": "configuration created"})
+		return response.JSON(http.StatusAccepted, apimodels.PostAlertingConfigResult{
+			Message:  "configuration created",
+			Warnings: warnings,
+		})
 	}
 	var unknownReceiverError notifier.UnknownReceiverError
 	if errors.As(err, &unknownReceiverError) {
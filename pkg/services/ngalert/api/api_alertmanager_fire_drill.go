@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	fireDrillDefaultDuration = 5 * time.Minute
+	fireDrillMaxDuration     = time.Hour
+	fireDrillAlertName       = "FireDrill"
+	fireDrillAnnotation      = "fire_drill"
+)
+
+// RoutePostFireDrill injects synthetic alerts into the Alertmanager of the organization.
+// The alerts carry the fire drill label, so they can be told apart from real alerts by notification templates and policies.
+func (srv AlertmanagerSrv) RoutePostFireDrill(c *contextmodel.ReqContext, body apimodels.FireDrillBodyParams) response.Response {
+	if len(body.Alerts) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("at least one alert is required"), "")
+	}
+	duration := time.Duration(body.Duration)
+	if duration == 0 {
+		duration = fireDrillDefaultDuration
+	}
+	if duration < 0 || duration > fireDrillMaxDuration {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("duration must be between 0 and %s", model.Duration(fireDrillMaxDuration)), "")
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.SignedInUser.GetOrgID())
+	if errResp != nil {
+		return errResp
+	}
+
+	now := time.Now()
+	endsAt := now.Add(duration)
+	if body.Resolve {
+		endsAt = now
+	}
+	alerts, err := fireDrillAlerts(body.Alerts, c.SignedInUser.GetLogin(), now, endsAt)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid fire drill alert")
+	}
+
+	if err := am.PutAlerts(c.Req.Context(), alerts); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to inject fire drill alerts")
+	}
+	srv.log.Info("Injected fire drill alerts", "user", c.SignedInUser.GetLogin(), "count", len(alerts.PostableAlerts), "resolve", body.Resolve, "endsAt", endsAt)
+
+	result := apimodels.FireDrillResult{EndsAt: endsAt, Alerts: make([]amv2.LabelSet, 0, len(alerts.PostableAlerts))}
+	for _, alert := range alerts.PostableAlerts {
+		result.Alerts = append(result.Alerts, alert.Labels)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// fireDrillAlerts converts the requested alerts to alerts that can be put into the Alertmanager.
+func fireDrillAlerts(requested []apimodels.FireDrillAlert, user string, startsAt, endsAt time.Time) (apimodels.PostableAlerts, error) {
+	result := apimodels.PostableAlerts{PostableAlerts: make([]amv2.PostableAlert, 0, len(requested))}
+	for _, r := range requested {
+		labels := amv2.LabelSet{model.AlertNameLabel: fireDrillAlertName}
+		for k, v := range r.Labels {
+			labels[k] = v
+		}
+		labels[apimodels.FireDrillLabel] = "true"
+
+		annotations := amv2.LabelSet{}
+		for k, v := range r.Annotations {
+			annotations[k] = v
+		}
+		annotations[fireDrillAnnotation] = fmt.Sprintf("This is a synthetic alert created by %s to rehearse alert notifications.", user)
+
+		alert := amv2.PostableAlert{
+			Alert:       amv2.Alert{Labels: labels},
+			Annotations: annotations,
+			StartsAt:    strfmt.DateTime(startsAt),
+			EndsAt:      strfmt.DateTime(endsAt),
+		}
+		if err := alert.Validate(strfmt.Default); err != nil {
+			return apimodels.PostableAlerts{}, err
+		}
+		for name := range labels {
+			if !model.LabelName(name).IsValid() {
+				return apimodels.PostableAlerts{}, fmt.Errorf("invalid label name %q", name)
+			}
+		}
+		result.PostableAlerts = append(result.PostableAlerts, alert)
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRoutePostFireDrill(t *testing.T) {
+	sut := createSut(t)
+
+	t.Run("assert 400 when no alerts are given", func(t *testing.T) {
+		response := sut.RoutePostFireDrill(createRequestCtxInOrg(1), apimodels.FireDrillBodyParams{})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 400 when duration exceeds the maximum", func(t *testing.T) {
+		response := sut.RoutePostFireDrill(createRequestCtxInOrg(1), apimodels.FireDrillBodyParams{
+			Alerts:   []apimodels.FireDrillAlert{{Labels: map[string]string{"team": "a"}}},
+			Duration: model.Duration(2 * time.Hour),
+		})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 404 when no alertmanager found", func(t *testing.T) {
+		response := sut.RoutePostFireDrill(createRequestCtxInOrg(10), apimodels.FireDrillBodyParams{
+			Alerts: []apimodels.FireDrillAlert{{Labels: map[string]string{"team": "a"}}},
+		})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("assert alerts are injected with the fire drill label", func(t *testing.T) {
+		response := sut.RoutePostFireDrill(createRequestCtxInOrg(1), apimodels.FireDrillBodyParams{
+			Alerts: []apimodels.FireDrillAlert{{Labels: map[string]string{"team": "a", apimodels.FireDrillLabel: "false"}}},
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+
+		am, err := sut.mam.AlertmanagerFor(1)
+		require.NoError(t, err)
+		alerts, err := am.GetAlerts(context.Background(), true, true, true, nil, "")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Equal(t, "true", alerts[0].Labels[apimodels.FireDrillLabel])
+		require.Equal(t, fireDrillAlertName, alerts[0].Labels[model.AlertNameLabel])
+		require.Equal(t, "a", alerts[0].Labels["team"])
+		require.Contains(t, alerts[0].Annotations, fireDrillAnnotation)
+	})
+}
+
+func TestFireDrillAlerts(t *testing.T) {
+	now := time.Now()
+
+	t.Run("alert name can be overridden", func(t *testing.T) {
+		alerts, err := fireDrillAlerts([]apimodels.FireDrillAlert{{Labels: map[string]string{model.AlertNameLabel: "Test"}}}, "admin", now, now)
+		require.NoError(t, err)
+		require.Equal(t, "Test", alerts.PostableAlerts[0].Labels[model.AlertNameLabel])
+		require.Equal(t, "This is a synthetic alert created by admin to rehearse alert notifications.", alerts.PostableAlerts[0].Annotations[fireDrillAnnotation])
+	})
+
+	t.Run("invalid label names are rejected", func(t *testing.T) {
+		_, err := fireDrillAlerts([]apimodels.FireDrillAlert{{Labels: map[string]string{"invalid-name": "a"}}}, "admin", now, now)
+		require.Error(t, err)
+	})
+}
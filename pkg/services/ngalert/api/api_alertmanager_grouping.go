@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RoutePostGroupingPreview returns how the Alertmanager groups the alerts into notifications.
+// Unless specified in the request, the alerts that are currently active and the current notification policy tree are used.
+func (srv AlertmanagerSrv) RoutePostGroupingPreview(c *contextmodel.ReqContext, body apimodels.GroupingPreviewBodyParams) response.Response {
+	am, errResp := srv.AlertmanagerFor(c.SignedInUser.GetOrgID())
+	if errResp != nil {
+		return errResp
+	}
+
+	route := body.Route
+	var namedRoutes []apimodels.NamedRoute
+	if route == nil {
+		cfg, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err != nil {
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "failed to get the Alertmanager configuration")
+		}
+		route = cfg.AlertmanagerConfig.Route
+		namedRoutes = cfg.AlertmanagerConfig.NamedRoutes
+	}
+	if route == nil {
+		return ErrResp(http.StatusBadRequest, errors.New("notification policy tree is empty"), "")
+	}
+	if err := route.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid notification policy tree")
+	}
+	route, err := apimodels.ExpandRoute(route, namedRoutes)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid notification policy tree")
+	}
+
+	var alerts []model.LabelSet
+	if len(body.Alerts) > 0 {
+		for _, alert := range body.Alerts {
+			alerts = append(alerts, amLabelSetToModel(alert.Labels))
+		}
+	} else {
+		// silenced and inhibited alerts do not cause notifications
+		active, err := am.GetAlerts(c.Req.Context(), true, false, false, nil, "")
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get the active alerts")
+		}
+		for _, alert := range active {
+			alerts = append(alerts, amLabelSetToModel(alert.Labels))
+		}
+	}
+
+	return response.JSON(http.StatusOK, apimodels.GroupingPreviewResult{
+		Groups: previewGrouping(route, alerts),
+	})
+}
+
+// previewGrouping groups the alerts the same way the dispatcher of the Alertmanager does.
+func previewGrouping(route *apimodels.Route, alerts []model.LabelSet) []apimodels.GroupingPreviewGroup {
+	root := dispatch.NewRoute(route.AsAMRoute(), nil)
+
+	groups := make(map[string]*apimodels.GroupingPreviewGroup)
+	for _, alert := range alerts {
+		for _, r := range root.Match(alert) {
+			groupLabels := model.LabelSet{}
+			for name, value := range alert {
+				if _, ok := r.RouteOpts.GroupBy[name]; ok || r.RouteOpts.GroupByAll {
+					groupLabels[name] = value
+				}
+			}
+
+			key := fmt.Sprintf("%s:%s", r.Key(), groupLabels)
+			group, ok := groups[key]
+			if !ok {
+				group = &apimodels.GroupingPreviewGroup{
+					GroupKey:       key,
+					Receiver:       r.RouteOpts.Receiver,
+					GroupLabels:    modelLabelSetToAM(groupLabels),
+					GroupBy:        groupByToAPI(r.RouteOpts),
+					GroupWait:      model.Duration(r.RouteOpts.GroupWait),
+					GroupInterval:  model.Duration(r.RouteOpts.GroupInterval),
+					RepeatInterval: model.Duration(r.RouteOpts.RepeatInterval),
+				}
+				groups[key] = group
+			}
+			group.AlertCount++
+			group.Alerts = append(group.Alerts, modelLabelSetToAM(alert))
+		}
+	}
+
+	result := make([]apimodels.GroupingPreviewGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GroupKey < result[j].GroupKey
+	})
+	return result
+}
+
+func groupByToAPI(opts dispatch.RouteOpts) []string {
+	if opts.GroupByAll {
+		return []string{"..."}
+	}
+	result := make([]string, 0, len(opts.GroupBy))
+	for name := range opts.GroupBy {
+		result = append(result, string(name))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func amLabelSetToModel(labels amv2.LabelSet) model.LabelSet {
+	result := make(model.LabelSet, len(labels))
+	for k, v := range labels {
+		result[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return result
+}
+
+func modelLabelSetToAM(labels model.LabelSet) amv2.LabelSet {
+	result := make(amv2.LabelSet, len(labels))
+	for k, v := range labels {
+		result[string(k)] = string(v)
+	}
+	return result
+}
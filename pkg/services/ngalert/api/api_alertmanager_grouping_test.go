@@ -0,0 +1,77 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestPreviewGrouping(t *testing.T) {
+	groupWait := model.Duration(time.Minute)
+	route := &apimodels.Route{
+		Receiver:   "default",
+		GroupByStr: []string{"alertname"},
+		Routes: []*apimodels.Route{
+			{
+				Receiver:       "team-a",
+				GroupByStr:     []string{"alertname", "cluster"},
+				GroupWait:      &groupWait,
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: "team", Value: "a"}},
+			},
+			{
+				Receiver:       "team-b",
+				GroupByStr:     []string{"..."},
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: "team", Value: "b"}},
+			},
+		},
+	}
+	require.NoError(t, route.Validate())
+
+	alerts := []model.LabelSet{
+		{"alertname": "HighCPU", "team": "a", "cluster": "eu", "pod": "1"},
+		{"alertname": "HighCPU", "team": "a", "cluster": "eu", "pod": "2"},
+		{"alertname": "HighCPU", "team": "a", "cluster": "us", "pod": "3"},
+		{"alertname": "HighCPU", "team": "b", "pod": "4"},
+		{"alertname": "DiskFull", "team": "c"},
+		{"alertname": "HighCPU", "team": "c"},
+	}
+
+	groups := previewGrouping(route, alerts)
+	require.Len(t, groups, 5)
+
+	byReceiver := map[string][]apimodels.GroupingPreviewGroup{}
+	for _, g := range groups {
+		byReceiver[g.Receiver] = append(byReceiver[g.Receiver], g)
+	}
+
+	require.Len(t, byReceiver["team-a"], 2)
+	for _, g := range byReceiver["team-a"] {
+		require.Equal(t, []string{"alertname", "cluster"}, g.GroupBy)
+		require.Equal(t, groupWait, g.GroupWait)
+		if g.GroupLabels["cluster"] == "eu" {
+			require.Equal(t, 2, g.AlertCount)
+			require.Len(t, g.Alerts, 2)
+		} else {
+			require.Equal(t, amv2.LabelSet{"alertname": "HighCPU", "cluster": "us"}, g.GroupLabels)
+			require.Equal(t, 1, g.AlertCount)
+		}
+	}
+
+	require.Len(t, byReceiver["team-b"], 1)
+	require.Equal(t, []string{"..."}, byReceiver["team-b"][0].GroupBy)
+	require.Equal(t, amv2.LabelSet{"alertname": "HighCPU", "team": "b", "pod": "4"}, byReceiver["team-b"][0].GroupLabels)
+
+	require.Len(t, byReceiver["default"], 2)
+	for _, g := range byReceiver["default"] {
+		require.Equal(t, 1, g.AlertCount)
+		// timings not set on the policy fall back to the defaults of the Alertmanager
+		require.Equal(t, model.Duration(dispatch.DefaultRouteOpts.GroupWait), g.GroupWait)
+	}
+}
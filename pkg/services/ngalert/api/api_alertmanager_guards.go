@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -39,6 +40,20 @@ func checkRoutes(currentConfig apimodels.GettableUserConfig, newConfig apimodels
 	if !routesEqual && currentConfig.AlertmanagerConfig.Route.Provenance != apimodels.Provenance(ngmodels.ProvenanceNone) {
 		return fmt.Errorf("policies were provisioned and cannot be changed through the UI")
 	}
+	for _, named := range currentConfig.AlertmanagerConfig.NamedRoutes {
+		if named.Provenance == apimodels.Provenance(ngmodels.ProvenanceNone) {
+			continue // we are only interested in non none
+		}
+		idx := slices.IndexFunc(newConfig.AlertmanagerConfig.NamedRoutes, func(n apimodels.NamedRoute) bool {
+			return n.Name == named.Name
+		})
+		if idx < 0 {
+			return fmt.Errorf("cannot delete provisioned named policy '%s'", named.Name)
+		}
+		if !cmp.Equal(named.Route, newConfig.AlertmanagerConfig.NamedRoutes[idx].Route, options...) {
+			return fmt.Errorf("cannot save provisioned named policy '%s'", named.Name)
+		}
+	}
 	return nil
 }
 
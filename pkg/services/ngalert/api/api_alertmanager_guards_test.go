@@ -48,6 +48,42 @@ func TestCheckRoute(t *testing.T) {
 				return cfg
 			}(),
 		},
+		{
+			name:          "editing a non provisioned named route should not fail",
+			shouldErr:     false,
+			currentConfig: withNamedRoute(gettableRoute(t, models.ProvenanceNone), models.ProvenanceNone),
+			newConfig: func() definitions.PostableUserConfig {
+				cfg := postableRoute(t, models.ProvenanceNone)
+				cfg.AlertmanagerConfig.NamedRoutes = []definitions.NamedRoute{{Name: "team-a", Route: &definitions.Route{Receiver: "other"}}}
+				return cfg
+			}(),
+		},
+		{
+			name:          "editing a provisioned named route should fail",
+			shouldErr:     true,
+			currentConfig: withNamedRoute(gettableRoute(t, models.ProvenanceNone), models.ProvenanceAPI),
+			newConfig: func() definitions.PostableUserConfig {
+				cfg := postableRoute(t, models.ProvenanceNone)
+				cfg.AlertmanagerConfig.NamedRoutes = []definitions.NamedRoute{{Name: "team-a", Route: &definitions.Route{Receiver: "other"}}}
+				return cfg
+			}(),
+		},
+		{
+			name:          "deleting a provisioned named route should fail",
+			shouldErr:     true,
+			currentConfig: withNamedRoute(gettableRoute(t, models.ProvenanceNone), models.ProvenanceAPI),
+			newConfig:     postableRoute(t, models.ProvenanceNone),
+		},
+		{
+			name:          "keeping a provisioned named route should not fail",
+			shouldErr:     false,
+			currentConfig: withNamedRoute(gettableRoute(t, models.ProvenanceNone), models.ProvenanceAPI),
+			newConfig: func() definitions.PostableUserConfig {
+				cfg := postableRoute(t, models.ProvenanceNone)
+				cfg.AlertmanagerConfig.NamedRoutes = []definitions.NamedRoute{{Name: "team-a", Route: &definitions.Route{Receiver: "team-a"}}}
+				return cfg
+			}(),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -96,6 +132,15 @@ func gettableRoute(t *testing.T, provenance models.Provenance) definitions.Getta
 	}
 }
 
+func withNamedRoute(cfg definitions.GettableUserConfig, provenance models.Provenance) definitions.GettableUserConfig {
+	cfg.AlertmanagerConfig.NamedRoutes = []definitions.NamedRoute{{
+		Name:       "team-a",
+		Route:      &definitions.Route{Receiver: "team-a"},
+		Provenance: definitions.Provenance(provenance),
+	}}
+	return cfg
+}
+
 func postableRoute(t *testing.T, provenace models.Provenance) definitions.PostableUserConfig {
 	t.Helper()
 	return definitions.PostableUserConfig{
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"math/rand"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,6 +27,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
@@ -200,6 +203,34 @@ func TestAlertmanagerConfig(t *testing.T) {
 		require.Equal(t, 202, response.Status())
 	})
 
+	t.Run("assert warnings when policies group by high cardinality labels", func(t *testing.T) {
+		sut := createSut(t)
+		sut.cfg.GroupByHighCardinalityLabels = map[string]struct{}{"instance": {}}
+		rc := contextmodel.ReqContext{
+			Context: &web.Context{
+				Req: &http.Request{},
+			},
+			SignedInUser: &user.SignedInUser{
+				OrgID: 1,
+			},
+		}
+		request := createAmConfigRequest(t, validConfig)
+		request.AlertmanagerConfig.Route.GroupByStr = []string{"alertname", "instance"}
+
+		response := sut.RoutePostAlertingConfig(&rc, request)
+		require.Equal(t, 202, response.Status())
+		var result apimodels.PostAlertingConfigResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Warnings, 1)
+		require.Equal(t, "route", result.Warnings[0].Route)
+		require.Equal(t, "instance", result.Warnings[0].Label)
+
+		sut.cfg.RejectHighCardinalityGroupBy = true
+		response = sut.RoutePostAlertingConfig(&rc, request)
+		require.Equal(t, 400, response.Status())
+		require.Contains(t, string(response.Body()), "high cardinality")
+	})
+
 	t.Run("assert 202 when alertmanager to configure is not ready", func(t *testing.T) {
 		sut := createSut(t)
 		rc := contextmodel.ReqContext{
@@ -549,10 +580,10 @@ func TestSilenceCreate(t *testing.T) {
 
 			srv := createSut(t)
 
-			resp := srv.RouteCreateSilence(&rc, amv2.PostableSilence{
+			resp := srv.RouteCreateSilence(&rc, apimodels.PostableGrafanaSilence{PostableSilence: amv2.PostableSilence{
 				ID:      "",
 				Silence: cas.silence,
-			})
+			}})
 			require.Equal(t, cas.status, resp.Status())
 		})
 	}
@@ -624,22 +655,141 @@ func TestRouteCreateSilence(t *testing.T) {
 				silence.ID = newID
 			}
 
-			response := sut.RouteCreateSilence(&rc, silence)
+			response := sut.RouteCreateSilence(&rc, apimodels.PostableGrafanaSilence{PostableSilence: silence})
 			require.Equal(t, tesCase.expectedStatus, response.Status())
 		})
 	}
 }
 
+func TestRouteSilenceMetadata(t *testing.T) {
+	sut := createSut(t)
+	newRequest := func(userID int64, login string, query string) *contextmodel.ReqContext {
+		return &contextmodel.ReqContext{
+			Context: &web.Context{
+				Req: &http.Request{URL: &url.URL{RawQuery: query}},
+			},
+			SignedInUser: &user.SignedInUser{
+				UserID: userID,
+				Login:  login,
+				OrgID:  1,
+				Permissions: map[int64]map[string][]string{
+					1: {accesscontrol.ActionAlertingInstanceCreate: {}, accesscontrol.ActionAlertingInstanceUpdate: {}},
+				},
+			},
+		}
+	}
+	createSilence := func(t *testing.T, rc *contextmodel.ReqContext, silence apimodels.PostableGrafanaSilence) string {
+		t.Helper()
+		resp := sut.RouteCreateSilence(rc, silence)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		var body apimodels.PostSilencesOKBody
+		require.NoError(t, json.Unmarshal(resp.Body(), &body))
+		return body.SilenceID
+	}
+	getSilences := func(t *testing.T, query string) map[string]*apimodels.GettableGrafanaSilence {
+		t.Helper()
+		resp := sut.RouteGetSilences(newRequest(1, "alice", query))
+		require.Equal(t, http.StatusOK, resp.Status())
+		var silences apimodels.GettableGrafanaSilences
+		require.NoError(t, json.Unmarshal(resp.Body(), &silences))
+		result := make(map[string]*apimodels.GettableGrafanaSilence, len(silences))
+		for _, s := range silences {
+			result[*s.ID] = s
+		}
+		return result
+	}
+
+	aliceSilence := apimodels.PostableGrafanaSilence{PostableSilence: silenceGen(withEmptyID)(), Reference: "INC-1"}
+	aliceID := createSilence(t, newRequest(1, "alice", ""), aliceSilence)
+	bobID := createSilence(t, newRequest(2, "bob", ""), apimodels.PostableGrafanaSilence{PostableSilence: silenceGen(withEmptyID)()})
+
+	t.Run("should return the creator and reference of silences", func(t *testing.T) {
+		silences := getSilences(t, "")
+		require.Len(t, silences, 2)
+		require.Equal(t, "user:1", silences[aliceID].Metadata.CreatedBy)
+		require.Equal(t, "alice", silences[aliceID].Metadata.CreatedByLogin)
+		require.Equal(t, "INC-1", silences[aliceID].Metadata.Reference)
+		require.Equal(t, "bob", silences[bobID].Metadata.CreatedByLogin)
+		require.Empty(t, silences[bobID].Metadata.Reference)
+
+		resp := sut.RouteGetSilence(newRequest(1, "alice", ""), aliceID)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var silence apimodels.GettableGrafanaSilence
+		require.NoError(t, json.Unmarshal(resp.Body(), &silence))
+		require.Equal(t, aliceID, *silence.ID)
+		require.Equal(t, "alice", silence.Metadata.CreatedByLogin)
+	})
+
+	t.Run("should filter silences by creator", func(t *testing.T) {
+		silences := getSilences(t, "createdBy=bob")
+		require.Len(t, silences, 1)
+		require.Contains(t, silences, bobID)
+		require.Empty(t, getSilences(t, "createdBy=carol"))
+	})
+
+	t.Run("should keep the creator and reference of updated silences", func(t *testing.T) {
+		updated := aliceSilence
+		updated.ID = aliceID
+		updated.Reference = ""
+		comment := "updated by bob"
+		updated.Comment = &comment
+		updatedID := createSilence(t, newRequest(2, "bob", ""), updated)
+
+		silences := getSilences(t, "createdBy=alice")
+		require.Contains(t, silences, updatedID)
+		require.Equal(t, "user:1", silences[updatedID].Metadata.CreatedBy)
+		require.Equal(t, "INC-1", silences[updatedID].Metadata.Reference)
+	})
+
+	t.Run("should reject references that are too long", func(t *testing.T) {
+		silence := apimodels.PostableGrafanaSilence{PostableSilence: silenceGen(withEmptyID)(), Reference: strings.Repeat("a", maxSilenceReferenceLength+1)}
+		resp := sut.RouteCreateSilence(newRequest(1, "alice", ""), silence)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
+
+func TestRoutePostPurgeExpiredSilences(t *testing.T) {
+	sut := createSut(t)
+
+	t.Run("assert 404 when no alertmanager found", func(t *testing.T) {
+		response := sut.RoutePostPurgeExpiredSilences(createRequestCtxInOrg(10))
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("assert expired silences are purged", func(t *testing.T) {
+		am, err := sut.mam.AlertmanagerFor(1)
+		require.NoError(t, err)
+		silence := silenceGen(withEmptyID)()
+		expiredID, err := am.CreateSilence(context.Background(), &silence)
+		require.NoError(t, err)
+		require.NoError(t, am.DeleteSilence(context.Background(), expiredID))
+
+		response := sut.RoutePostPurgeExpiredSilences(createRequestCtxInOrg(1))
+		require.Equal(t, http.StatusOK, response.Status())
+		var result apimodels.PurgeExpiredSilencesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, 1, result.Purged)
+
+		am, err = sut.mam.AlertmanagerFor(1)
+		require.NoError(t, err)
+		silences, err := am.ListSilences(context.Background(), nil)
+		require.NoError(t, err)
+		require.Empty(t, silences)
+	})
+}
+
 func createSut(t *testing.T) AlertmanagerSrv {
 	t.Helper()
 
 	mam := createMultiOrgAlertmanager(t)
 	log := log.NewNopLogger()
 	return AlertmanagerSrv{
-		mam:    mam,
-		crypto: mam.Crypto,
-		ac:     acimpl.ProvideAccessControl(setting.NewCfg()),
-		log:    log,
+		mam:             mam,
+		crypto:          mam.Crypto,
+		ac:              acimpl.ProvideAccessControl(setting.NewCfg()),
+		log:             log,
+		cfg:             &setting.UnifiedAlertingSettings{},
+		silenceMetadata: store.NewFakeSilenceMetadataStore(),
 	}
 }
 
@@ -5,17 +5,26 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/cleanup"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -23,7 +32,46 @@ type ConfigSrv struct {
 	datasourceService    datasources.DataSourceService
 	alertmanagerProvider ExternalAlertmanagerProvider
 	store                store.AdminConfigurationStore
+	ruleStore            RuleStore
+	labelPolicyStore     store.LabelPolicyStore
+	pauseWindowStore     store.EvaluationPauseWindowStore
 	log                  log.Logger
+	cfg                  *setting.UnifiedAlertingSettings
+	orgMetrics           OrgMetricsProvider
+	stateSnapshots       StateSnapshotter
+	adminConfigObserver  AdminConfigObserver
+	pauseWindowObserver  PauseWindowObserver
+	orgCleanup           OrgCleanupProgress
+	orgStore             store.OrgStore
+	quotaService         quota.Service
+	readOnly             *readOnlyModeStore
+}
+
+// AdminConfigObserver is notified when the admin configuration of an organization is changed or deleted.
+type AdminConfigObserver interface {
+	AdminConfigurationChanged(orgID int64)
+}
+
+// PauseWindowObserver is notified when an evaluation pause window of an organization is created or deleted.
+type PauseWindowObserver interface {
+	PauseWindowsChanged(orgID int64)
+}
+
+// OrgMetricsProvider returns the alerting counters of an organization.
+type OrgMetricsProvider interface {
+	GetOrgMetrics(orgID int64) (metrics.OrgMetrics, error)
+}
+
+// OrgCleanupProgress reports the progress of the cleanup of the alerting data of deleted organizations.
+type OrgCleanupProgress interface {
+	Jobs() []cleanup.Job
+}
+
+// StateSnapshotter takes and inspects the snapshots of the state of the alert instances.
+type StateSnapshotter interface {
+	SnapshotInterval() time.Duration
+	Snapshot(ctx context.Context) (*ngmodels.AlertInstanceSnapshot, error)
+	LatestSnapshot(ctx context.Context) (*ngmodels.AlertInstanceSnapshot, error)
 }
 
 func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.Response {
@@ -43,6 +91,88 @@ func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.
 	})
 }
 
+func (srv ConfigSrv) RouteGetOrgMetrics(c *contextmodel.ReqContext) response.Response {
+	m, err := srv.orgMetrics.GetOrgMetrics(c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to gather the metrics of the organization")
+	}
+	return response.JSON(http.StatusOK, apimodels.OrgAlertingMetrics{
+		RuleEvaluations:        m.RuleEvaluations,
+		RuleEvaluationFailures: m.RuleEvaluationFailures,
+		NotificationsSent:      m.NotificationsSent,
+		NotificationsFailed:    m.NotificationsFailed,
+		ActiveSilences:         m.ActiveSilences,
+	})
+}
+
+func (srv ConfigSrv) RouteGetStateSnapshot(c *contextmodel.ReqContext) response.Response {
+	snapshot, err := srv.stateSnapshots.LatestSnapshot(c.Req.Context())
+	if err != nil {
+		if errors.Is(err, state.ErrSnapshotsDisabled) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the latest state snapshot")
+	}
+	if snapshot == nil {
+		return ErrResp(http.StatusNotFound, errors.New("no state snapshot was taken yet"), "")
+	}
+	return response.JSON(http.StatusOK, srv.stateSnapshotToApi(snapshot))
+}
+
+func (srv ConfigSrv) RoutePostStateSnapshot(c *contextmodel.ReqContext) response.Response {
+	snapshot, err := srv.stateSnapshots.Snapshot(c.Req.Context())
+	if err != nil {
+		if errors.Is(err, state.ErrSnapshotsDisabled) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to take a state snapshot")
+	}
+	srv.log.Info("State snapshot taken", "instances", snapshot.Instances, "size", snapshot.Size, "user", c.SignedInUser.GetLogin())
+	return response.JSON(http.StatusCreated, srv.stateSnapshotToApi(snapshot))
+}
+
+func (srv ConfigSrv) stateSnapshotToApi(snapshot *ngmodels.AlertInstanceSnapshot) apimodels.StateSnapshot {
+	return apimodels.StateSnapshot{
+		Created:   snapshot.Created,
+		Instances: snapshot.Instances,
+		Size:      snapshot.Size,
+		Interval:  model.Duration(srv.stateSnapshots.SnapshotInterval()),
+	}
+}
+
+func (srv ConfigSrv) RouteGetOrgCleanupJobs(c *contextmodel.ReqContext) response.Response {
+	jobs := srv.orgCleanup.Jobs()
+	result := make(apimodels.OrgCleanupJobs, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, orgCleanupJobToApi(job))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func orgCleanupJobToApi(job cleanup.Job) apimodels.OrgCleanupJob {
+	result := apimodels.OrgCleanupJob{
+		OrgID:   job.OrgID,
+		State:   string(job.State),
+		Steps:   make([]apimodels.OrgCleanupStep, 0, len(job.Steps)),
+		Created: job.Created,
+		Error:   job.Error,
+	}
+	if !job.Started.IsZero() {
+		result.Started = util.Pointer(job.Started)
+	}
+	if !job.Finished.IsZero() {
+		result.Finished = util.Pointer(job.Finished)
+	}
+	for _, step := range job.Steps {
+		result.Steps = append(result.Steps, apimodels.OrgCleanupStep{
+			Name:      step.Name,
+			Deleted:   step.Deleted,
+			Completed: step.Completed,
+		})
+	}
+	return result
+}
+
 func (srv ConfigSrv) RouteGetNGalertConfig(c *contextmodel.ReqContext) response.Response {
 	if c.SignedInUser.GetOrgRole() != org.RoleAdmin {
 		return accessForbiddenResp()
@@ -60,7 +190,16 @@ func (srv ConfigSrv) RouteGetNGalertConfig(c *contextmodel.ReqContext) response.
 	}
 
 	resp := apimodels.GettableNGalertConfig{
-		AlertmanagersChoice: apimodels.AlertmanagersChoice(cfg.SendAlertsTo.String()),
+		AlertmanagersChoice:       apimodels.AlertmanagersChoice(cfg.SendAlertsTo.String()),
+		DefaultEvaluationInterval: model.Duration(time.Duration(cfg.DefaultEvaluationIntervalSeconds) * time.Second),
+		AnnotationSchema:          annotationSchemaToAPI(cfg.AnnotationSchema),
+		LabelRewriteRules:         labelRewriteRulesToAPI(cfg.LabelRewriteRules),
+
+		MissingSeriesEvalsToResolve:    cfg.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: model.Duration(cfg.MissingSeriesDurationToResolve),
+	}
+	for _, interval := range cfg.AllowedEvaluationIntervalsSeconds {
+		resp.AllowedEvaluationIntervals = append(resp.AllowedEvaluationIntervals, model.Duration(time.Duration(interval)*time.Second))
 	}
 	return response.JSON(http.StatusOK, resp)
 }
@@ -85,8 +224,40 @@ func (srv ConfigSrv) RoutePostNGalertConfig(c *contextmodel.ReqContext, body api
 	}
 
 	cfg := &ngmodels.AdminConfiguration{
-		SendAlertsTo: sendAlertsTo,
-		OrgID:        c.SignedInUser.GetOrgID(),
+		SendAlertsTo:      sendAlertsTo,
+		OrgID:             c.SignedInUser.GetOrgID(),
+		AnnotationSchema:  annotationSchemaFromAPI(body.AnnotationSchema),
+		LabelRewriteRules: labelRewriteRulesFromAPI(body.LabelRewriteRules),
+
+		MissingSeriesEvalsToResolve:    body.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: time.Duration(body.MissingSeriesDurationToResolve),
+	}
+
+	if err := cfg.AnnotationSchema.Validate(); err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid annotation schema", err)
+	}
+
+	if err := cfg.LabelRewriteRules.Validate(); err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid label rewrite rules", err)
+	}
+
+	if err := cfg.StaleSeriesPolicy().Validate(); err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid stale series policy", err)
+	}
+
+	if err := srv.setEvaluationIntervals(cfg, body); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
+	nonConforming, err := srv.nonConformingRuleGroups(c.Req.Context(), cfg)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to fetch the rule groups of the organization")
+	}
+	if len(nonConforming) > 0 {
+		return response.JSON(http.StatusBadRequest, apimodels.NonConformingIntervalsError{
+			Message: fmt.Sprintf("%d rule groups use an evaluation interval that is not allowed", len(nonConforming)),
+			Groups:  nonConforming,
+		})
 	}
 
 	cmd := store.UpdateAdminConfigurationCmd{AdminConfiguration: cfg}
@@ -95,10 +266,105 @@ func (srv ConfigSrv) RoutePostNGalertConfig(c *contextmodel.ReqContext, body api
 		srv.log.Error(msg, "error", err)
 		return ErrResp(http.StatusBadRequest, err, msg)
 	}
+	srv.adminConfigurationChanged(cfg.OrgID)
 
 	return response.JSON(http.StatusCreated, util.DynMap{"message": "admin configuration updated"})
 }
 
+// setEvaluationIntervals validates the evaluation intervals of the request and sets them to the configuration.
+func (srv ConfigSrv) setEvaluationIntervals(cfg *ngmodels.AdminConfiguration, body apimodels.PostableNGalertConfig) error {
+	validate := func(interval model.Duration) (int64, error) {
+		seconds := int64(time.Duration(interval).Seconds())
+		if srv.cfg == nil {
+			if seconds <= 0 {
+				return 0, fmt.Errorf("evaluation interval %s must be positive", interval)
+			}
+			return seconds, nil
+		}
+		if err := ngmodels.ValidateRuleGroupInterval(seconds, int64(srv.cfg.BaseInterval.Seconds())); err != nil {
+			return 0, err
+		}
+		return seconds, nil
+	}
+
+	seen := make(map[int64]struct{}, len(body.AllowedEvaluationIntervals))
+	for _, interval := range body.AllowedEvaluationIntervals {
+		seconds, err := validate(interval)
+		if err != nil {
+			return fmt.Errorf("invalid allowed evaluation interval: %w", err)
+		}
+		if _, ok := seen[seconds]; ok {
+			continue
+		}
+		seen[seconds] = struct{}{}
+		cfg.AllowedEvaluationIntervalsSeconds = append(cfg.AllowedEvaluationIntervalsSeconds, seconds)
+	}
+	sort.Slice(cfg.AllowedEvaluationIntervalsSeconds, func(i, j int) bool {
+		return cfg.AllowedEvaluationIntervalsSeconds[i] < cfg.AllowedEvaluationIntervalsSeconds[j]
+	})
+
+	if body.DefaultEvaluationInterval != 0 {
+		seconds, err := validate(body.DefaultEvaluationInterval)
+		if err != nil {
+			return fmt.Errorf("invalid default evaluation interval: %w", err)
+		}
+		cfg.DefaultEvaluationIntervalSeconds = seconds
+	}
+
+	if srv.cfg != nil {
+		inherited := cfg.EffectiveEvaluationIntervalSeconds(int64(srv.cfg.DefaultRuleEvaluationInterval.Seconds()))
+		if !cfg.IsEvaluationIntervalAllowed(inherited) {
+			return fmt.Errorf("default evaluation interval %s must be one of the allowed intervals %s", time.Duration(inherited)*time.Second, formatIntervals(cfg.AllowedEvaluationIntervalsSeconds))
+		}
+	}
+	return nil
+}
+
+// nonConformingRuleGroups returns the rule groups of the organization that use an evaluation interval the configuration does not allow.
+// Groups that inherit the interval of the organization always conform.
+func (srv ConfigSrv) nonConformingRuleGroups(ctx context.Context, cfg *ngmodels.AdminConfiguration) ([]apimodels.NonConformingRuleGroup, error) {
+	if srv.ruleStore == nil || len(cfg.AllowedEvaluationIntervalsSeconds) == 0 {
+		return nil, nil
+	}
+	rules, err := srv.ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: cfg.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []apimodels.NonConformingRuleGroup
+	seen := make(map[ngmodels.AlertRuleGroupKey]struct{})
+	for _, rule := range rules {
+		key := rule.GetGroupKey()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if rule.InheritsInterval() || cfg.IsEvaluationIntervalAllowed(rule.IntervalSeconds) {
+			continue
+		}
+		result = append(result, apimodels.NonConformingRuleGroup{
+			NamespaceUID: key.NamespaceUID,
+			RuleGroup:    key.RuleGroup,
+			Interval:     model.Duration(time.Duration(rule.IntervalSeconds) * time.Second),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].NamespaceUID != result[j].NamespaceUID {
+			return result[i].NamespaceUID < result[j].NamespaceUID
+		}
+		return result[i].RuleGroup < result[j].RuleGroup
+	})
+	return result, nil
+}
+
+func formatIntervals(intervals []int64) string {
+	formatted := make([]string, 0, len(intervals))
+	for _, interval := range intervals {
+		formatted = append(formatted, model.Duration(time.Duration(interval)*time.Second).String())
+	}
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
+
 func (srv ConfigSrv) RouteDeleteNGalertConfig(c *contextmodel.ReqContext) response.Response {
 	if c.SignedInUser.GetOrgRole() != org.RoleAdmin {
 		return accessForbiddenResp()
@@ -109,10 +375,17 @@ func (srv ConfigSrv) RouteDeleteNGalertConfig(c *contextmodel.ReqContext) respon
 		srv.log.Error("Unable to delete configuration", "error", err)
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
+	srv.adminConfigurationChanged(c.SignedInUser.GetOrgID())
 
 	return response.JSON(http.StatusOK, util.DynMap{"message": "admin configuration deleted"})
 }
 
+func (srv ConfigSrv) adminConfigurationChanged(orgID int64) {
+	if srv.adminConfigObserver != nil {
+		srv.adminConfigObserver.AdminConfigurationChanged(orgID)
+	}
+}
+
 // externalAlertmanagers returns the URL of any external alertmanager that is
 // configured as datasource. The URL does not contain any auth.
 func (srv ConfigSrv) externalAlertmanagers(ctx context.Context, orgID int64) ([]string, error) {
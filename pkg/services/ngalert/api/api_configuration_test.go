@@ -1,18 +1,29 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/cleanup"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func TestExternalAlertmanagerChoice(t *testing.T) {
@@ -92,6 +103,135 @@ func TestExternalAlertmanagerChoice(t *testing.T) {
 	}
 }
 
+func TestRoutePostNGalertConfig_EvaluationIntervals(t *testing.T) {
+	ctx := createRequestCtxInOrg(1)
+	ctx.OrgRole = org.RoleAdmin
+	cfg := &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second, DefaultRuleEvaluationInterval: time.Minute}
+	minutes := func(m int) model.Duration {
+		return model.Duration(time.Duration(m) * time.Minute)
+	}
+
+	newSut := func(t *testing.T, rules ...*ngmodels.AlertRule) (ConfigSrv, *store.FakeAdminConfigStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), rules...)
+		sut := createAPIAdminSut(t, nil)
+		sut.ruleStore = ruleStore
+		sut.cfg = cfg
+		return sut, sut.store.(*store.FakeAdminConfigStore)
+	}
+
+	t.Run("should save the intervals", func(t *testing.T) {
+		sut, configStore := newSut(t)
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{
+			DefaultEvaluationInterval:  minutes(5),
+			AllowedEvaluationIntervals: []model.Duration{minutes(5), minutes(1), minutes(5)},
+		})
+		require.Equal(t, http.StatusCreated, resp.Status())
+		saved := configStore.Configs[1]
+		require.Equal(t, int64(300), saved.DefaultEvaluationIntervalSeconds)
+		require.Equal(t, []int64{60, 300}, saved.AllowedEvaluationIntervalsSeconds)
+	})
+
+	t.Run("should reject intervals that are not multiple of the base interval", func(t *testing.T) {
+		sut, _ := newSut(t)
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{
+			AllowedEvaluationIntervals: []model.Duration{model.Duration(15 * time.Second)},
+		})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should reject default interval that is not allowed", func(t *testing.T) {
+		sut, _ := newSut(t)
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{
+			AllowedEvaluationIntervals: []model.Duration{minutes(5)},
+		})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should list rule groups that use an interval that is not allowed", func(t *testing.T) {
+		groupKey := ngmodels.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "group"}
+		sut, configStore := newSut(t,
+			ngmodels.AlertRuleGen(ngmodels.WithGroupKey(groupKey), ngmodels.WithInterval(2*time.Minute))(),
+			ngmodels.AlertRuleGen(ngmodels.WithGroupKey(ngmodels.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "inherit"}), func(r *ngmodels.AlertRule) { r.IntervalSeconds = ngmodels.InheritedIntervalSeconds })(),
+			ngmodels.AlertRuleGen(ngmodels.WithGroupKey(ngmodels.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "conforming"}), ngmodels.WithInterval(time.Minute))(),
+		)
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{
+			AllowedEvaluationIntervals: []model.Duration{minutes(1)},
+		})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+
+		var body definitions.NonConformingIntervalsError
+		require.NoError(t, json.Unmarshal(resp.Body(), &body))
+		require.Equal(t, []definitions.NonConformingRuleGroup{
+			{NamespaceUID: "folder", RuleGroup: "group", Interval: minutes(2)},
+		}, body.Groups)
+		require.Empty(t, configStore.Configs)
+	})
+}
+
+func TestRoutePostNGalertConfig_LabelRewriteRules(t *testing.T) {
+	ctx := createRequestCtxInOrg(1)
+	ctx.OrgRole = org.RoleAdmin
+
+	t.Run("should save the rules", func(t *testing.T) {
+		sut := createAPIAdminSut(t, nil)
+		rules := []definitions.LabelRewriteRule{
+			{Label: "pod", Action: "drop"},
+			{Label: "instance", Action: "replace", Regex: "(.*):[0-9]+", Replacement: "$1"},
+		}
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{LabelRewriteRules: rules})
+		require.Equal(t, http.StatusCreated, resp.Status())
+		require.Equal(t, ngmodels.LabelRewriteRules{
+			{Label: "pod", Action: ngmodels.LabelRewriteActionDrop},
+			{Label: "instance", Action: ngmodels.LabelRewriteActionReplace, Regex: "(.*):[0-9]+", Replacement: "$1"},
+		}, sut.store.(*store.FakeAdminConfigStore).Configs[1].LabelRewriteRules)
+
+		resp = sut.RouteGetNGalertConfig(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var body definitions.GettableNGalertConfig
+		require.NoError(t, json.Unmarshal(resp.Body(), &body))
+		require.Equal(t, rules, body.LabelRewriteRules)
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		sut := createAPIAdminSut(t, nil)
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{
+			LabelRewriteRules: []definitions.LabelRewriteRule{{Label: "pod", Action: "drop", Regex: "("}},
+		})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Empty(t, sut.store.(*store.FakeAdminConfigStore).Configs)
+	})
+}
+
+func TestRoutePostNGalertConfig_StaleSeriesPolicy(t *testing.T) {
+	ctx := createRequestCtxInOrg(1)
+	ctx.OrgRole = org.RoleAdmin
+
+	t.Run("should save the policy", func(t *testing.T) {
+		sut := createAPIAdminSut(t, nil)
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{MissingSeriesDurationToResolve: model.Duration(time.Hour)})
+		require.Equal(t, http.StatusCreated, resp.Status())
+		require.Equal(t, ngmodels.StaleSeriesPolicy{DurationToResolve: time.Hour}, sut.store.(*store.FakeAdminConfigStore).Configs[1].StaleSeriesPolicy())
+
+		resp = sut.RouteGetNGalertConfig(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var body definitions.GettableNGalertConfig
+		require.NoError(t, json.Unmarshal(resp.Body(), &body))
+		require.Equal(t, model.Duration(time.Hour), body.MissingSeriesDurationToResolve)
+		require.Zero(t, body.MissingSeriesEvalsToResolve)
+	})
+
+	t.Run("should reject a policy that sets both fields", func(t *testing.T) {
+		sut := createAPIAdminSut(t, nil)
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{
+			MissingSeriesEvalsToResolve:    5,
+			MissingSeriesDurationToResolve: model.Duration(time.Hour),
+		})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Empty(t, sut.store.(*store.FakeAdminConfigStore).Configs)
+	})
+}
+
 func createAPIAdminSut(t *testing.T,
 	datasources []*datasources.DataSource) ConfigSrv {
 	return ConfigSrv{
@@ -101,3 +241,85 @@ func createAPIAdminSut(t *testing.T,
 		store: store.NewFakeAdminConfigStore(t),
 	}
 }
+
+func TestRouteGetOrgMetrics(t *testing.T) {
+	m := metrics.NewNGAlert(prometheus.NewRegistry())
+	m.GetSchedulerMetrics().EvalTotal.WithLabelValues("1").Add(10)
+	m.GetSchedulerMetrics().EvalFailures.WithLabelValues("1").Add(2)
+	// counters of other organizations are not included
+	m.GetSchedulerMetrics().EvalTotal.WithLabelValues("2").Add(5)
+
+	reg := m.GetMultiOrgAlertmanagerMetrics().GetOrCreateOrgRegistry(1)
+	notifications := promauto.With(reg).NewCounterVec(prometheus.CounterOpts{Name: "alertmanager_notifications_total"}, []string{"integration"})
+	notifications.WithLabelValues("email").Add(4)
+	notifications.WithLabelValues("slack").Add(3)
+	promauto.With(reg).NewCounterVec(prometheus.CounterOpts{Name: "alertmanager_notifications_failed_total"}, []string{"integration"}).WithLabelValues("slack").Add(1)
+	silences := promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{Name: "alertmanager_silences"}, []string{"state"})
+	silences.WithLabelValues("active").Set(2)
+	silences.WithLabelValues("expired").Set(7)
+
+	sut := createAPIAdminSut(t, nil)
+	sut.orgMetrics = m
+
+	resp := sut.RouteGetOrgMetrics(createRequestCtxInOrg(1))
+	require.Equal(t, http.StatusOK, resp.Status())
+	result := definitions.OrgAlertingMetrics{}
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	require.Equal(t, definitions.OrgAlertingMetrics{
+		RuleEvaluations:        10,
+		RuleEvaluationFailures: 2,
+		NotificationsSent:      6,
+		NotificationsFailed:    1,
+		ActiveSilences:         2,
+	}, result)
+
+	resp = sut.RouteGetOrgMetrics(createRequestCtxInOrg(3))
+	require.Equal(t, http.StatusOK, resp.Status())
+	result = definitions.OrgAlertingMetrics{}
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	require.Equal(t, definitions.OrgAlertingMetrics{}, result)
+}
+
+type fakeOrgCleanup struct {
+	jobs []cleanup.Job
+}
+
+func (f fakeOrgCleanup) Jobs() []cleanup.Job {
+	return f.jobs
+}
+
+func TestRouteGetOrgCleanupJobs(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	sut := createAPIAdminSut(t, nil)
+	sut.orgCleanup = fakeOrgCleanup{jobs: []cleanup.Job{
+		{
+			OrgID:   3,
+			State:   cleanup.JobStateRunning,
+			Created: created,
+			Started: created.Add(time.Second),
+			Steps: []cleanup.StepProgress{
+				{Name: cleanup.StepAlertmanager, Completed: true},
+				{Name: "silences", Deleted: 2000},
+			},
+		},
+		{OrgID: 4, State: cleanup.JobStatePending, Created: created.Add(time.Minute)},
+	}}
+
+	resp := sut.RouteGetOrgCleanupJobs(createRequestCtxInOrg(1))
+	require.Equal(t, http.StatusOK, resp.Status())
+	var result definitions.OrgCleanupJobs
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	require.Equal(t, definitions.OrgCleanupJobs{
+		{
+			OrgID:   3,
+			State:   "running",
+			Created: created,
+			Started: util.Pointer(created.Add(time.Second)),
+			Steps: []definitions.OrgCleanupStep{
+				{Name: "alertmanager", Completed: true},
+				{Name: "silences", Deleted: 2000},
+			},
+		},
+		{OrgID: 4, State: "pending", Created: created.Add(time.Minute), Steps: []definitions.OrgCleanupStep{}},
+	}, result)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func (srv ConfigSrv) RouteGetLabelPolicies(c *contextmodel.ReqContext) response.Response {
+	policies, err := srv.labelPolicyStore.GetLabelPolicies(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get label policies")
+	}
+	result := make(apimodels.LabelPolicies, 0, len(policies))
+	for _, policy := range policies {
+		result = append(result, labelPolicyToAPI(policy))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv ConfigSrv) RoutePutLabelPolicy(c *contextmodel.ReqContext, body apimodels.LabelPolicy) response.Response {
+	policy := labelPolicyFromAPI(c.SignedInUser.GetOrgID(), body)
+	if err := policy.Labels.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid label policy")
+	}
+	if policy.FolderUID != "" && srv.ruleStore != nil {
+		if _, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), policy.FolderUID, c.SignedInUser.GetOrgID(), c.SignedInUser); err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+	}
+	if err := srv.labelPolicyStore.SaveLabelPolicy(c.Req.Context(), policy); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to save label policy")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "label policy saved"})
+}
+
+func (srv ConfigSrv) RouteDeleteLabelPolicy(c *contextmodel.ReqContext) response.Response {
+	if err := srv.labelPolicyStore.DeleteLabelPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), c.Query("folderUid")); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to delete label policy")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "label policy deleted"})
+}
+
+func (srv ConfigSrv) RouteGetLabelPolicyViolations(c *contextmodel.ReqContext) response.Response {
+	violations, err := srv.labelPolicyViolations(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to check alert rules against label policies")
+	}
+	return response.JSON(http.StatusOK, apimodels.LabelPolicyViolations{
+		Violations: labelViolationsToAPI(violations),
+	})
+}
+
+// labelPolicyViolations checks all alert rules of the organization against its label policies.
+func (srv ConfigSrv) labelPolicyViolations(ctx context.Context, orgID int64) ([]ngmodels.LabelViolation, error) {
+	policies, err := srv.labelPolicyStore.GetLabelPolicies(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 || srv.ruleStore == nil {
+		return nil, nil
+	}
+	rules, err := srv.ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	return policies.Check(rules...), nil
+}
+
+// validateLabelPolicies checks the rules against the label policies of the organization.
+// Returns nil if the rules satisfy the policies, or a response that describes all violations.
+func validateLabelPolicies(policies ngmodels.LabelPolicies, rules ...*ngmodels.AlertRule) response.Response {
+	violations := policies.Check(rules...)
+	if len(violations) == 0 {
+		return nil
+	}
+	return response.JSON(http.StatusBadRequest, apimodels.LabelPolicyViolations{
+		Message:    fmt.Sprintf("%d labels do not satisfy the label policies of the organization", len(violations)),
+		Violations: labelViolationsToAPI(violations),
+	})
+}
+
+func labelPolicyFromAPI(orgID int64, policy apimodels.LabelPolicy) *ngmodels.LabelPolicy {
+	labels := make(ngmodels.LabelRequirements, 0, len(policy.Labels))
+	for _, l := range policy.Labels {
+		labels = append(labels, ngmodels.LabelRequirement{
+			Name:      l.Name,
+			Required:  l.Required,
+			Forbidden: l.Forbidden,
+			Pattern:   l.Pattern,
+		})
+	}
+	return &ngmodels.LabelPolicy{
+		OrgID:     orgID,
+		FolderUID: policy.FolderUID,
+		Labels:    labels,
+	}
+}
+
+func labelPolicyToAPI(policy *ngmodels.LabelPolicy) apimodels.LabelPolicy {
+	labels := make([]apimodels.LabelRequirement, 0, len(policy.Labels))
+	for _, l := range policy.Labels {
+		labels = append(labels, apimodels.LabelRequirement{
+			Name:      l.Name,
+			Required:  l.Required,
+			Forbidden: l.Forbidden,
+			Pattern:   l.Pattern,
+		})
+	}
+	return apimodels.LabelPolicy{
+		FolderUID: policy.FolderUID,
+		Labels:    labels,
+	}
+}
+
+func labelViolationsToAPI(violations []ngmodels.LabelViolation) []apimodels.LabelViolation {
+	result := make([]apimodels.LabelViolation, 0, len(violations))
+	for _, v := range violations {
+		result = append(result, apimodels.LabelViolation{
+			RuleUID:      v.RuleUID,
+			RuleTitle:    v.RuleTitle,
+			NamespaceUID: v.NamespaceUID,
+			RuleGroup:    v.RuleGroup,
+			Label:        v.Label,
+			Reason:       v.Reason,
+		})
+	}
+	return result
+}
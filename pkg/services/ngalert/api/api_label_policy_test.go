@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+func TestLabelPolicyRoutes(t *testing.T) {
+	ctx := createRequestCtxInOrg(1)
+	ctx.OrgRole = org.RoleAdmin
+
+	ruleStore := fakes.NewRuleStore(t)
+	compliant := ngmodels.AlertRuleGen(ngmodels.WithOrgID(1), ngmodels.WithLabels(map[string]string{"team": "sre"}))()
+	nonCompliant := ngmodels.AlertRuleGen(ngmodels.WithOrgID(1), ngmodels.WithLabels(map[string]string{"team": "SRE"}))()
+	otherOrg := ngmodels.AlertRuleGen(ngmodels.WithOrgID(2), ngmodels.WithLabels(nil))()
+	ruleStore.PutRule(context.Background(), compliant, nonCompliant, otherOrg)
+
+	policyStore := store.NewFakeLabelPolicyStore()
+	sut := ConfigSrv{
+		ruleStore:        ruleStore,
+		labelPolicyStore: policyStore,
+	}
+
+	t.Run("should reject invalid policy", func(t *testing.T) {
+		resp := sut.RoutePutLabelPolicy(ctx, definitions.LabelPolicy{Labels: []definitions.LabelRequirement{{Name: "team", Pattern: "("}}})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Empty(t, policyStore.Policies)
+	})
+
+	t.Run("should save and return policies", func(t *testing.T) {
+		policy := definitions.LabelPolicy{Labels: []definitions.LabelRequirement{{Name: "team", Required: true, Pattern: "[a-z]+"}}}
+		resp := sut.RoutePutLabelPolicy(ctx, policy)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+
+		resp = sut.RouteGetLabelPolicies(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var policies definitions.LabelPolicies
+		require.NoError(t, json.Unmarshal(resp.Body(), &policies))
+		require.Equal(t, definitions.LabelPolicies{policy}, policies)
+	})
+
+	t.Run("should report violations of existing rules", func(t *testing.T) {
+		resp := sut.RouteGetLabelPolicyViolations(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var report definitions.LabelPolicyViolations
+		require.NoError(t, json.Unmarshal(resp.Body(), &report))
+		require.Len(t, report.Violations, 1)
+		require.Equal(t, nonCompliant.UID, report.Violations[0].RuleUID)
+		require.Equal(t, "team", report.Violations[0].Label)
+	})
+
+	t.Run("should delete policy", func(t *testing.T) {
+		resp := sut.RouteDeleteLabelPolicy(ctx)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		require.Empty(t, policyStore.Policies[1])
+	})
+}
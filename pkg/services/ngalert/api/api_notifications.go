@@ -18,6 +18,9 @@ type NotificationSrv struct {
 	logger            log.Logger
 	receiverService   ReceiverService
 	muteTimingService MuteTimingService // defined in api_provisioning.go
+	mam               *notifier.MultiOrgAlertmanager
+	ruleStore         RuleStore
+	authz             RuleAccessControlService
 }
 
 type ReceiverService interface {
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+// RouteGetReceiverUsage returns the notification policies and the alert rules that send notifications to the receiver,
+// and the number of notifications it recently sent, so it can be told whether the receiver is safe to delete.
+func (srv *NotificationSrv) RouteGetReceiverUsage(c *contextmodel.ReqContext, name string) response.Response {
+	orgID := c.SignedInUser.GetOrgID()
+	// the receiver is fetched to check that it exists and that the user can read it
+	_, err := srv.receiverService.GetReceiver(c.Req.Context(), models.GetReceiverQuery{OrgID: orgID, Name: name}, c.SignedInUser)
+	if err != nil {
+		if errors.Is(err, notifier.ErrNotFound) {
+			return ErrResp(http.StatusNotFound, err, "receiver not found")
+		}
+		if errors.Is(err, notifier.ErrPermissionDenied) {
+			return ErrResp(http.StatusForbidden, err, "permission denied")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get receiver")
+	}
+
+	cfg, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), orgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the Alertmanager configuration")
+	}
+	// named policies are referenced from the tree, so they are expanded to find the policies that route to the receiver
+	var route *definitions.Route
+	if cfg.AlertmanagerConfig.Route != nil {
+		route, err = definitions.ExpandRoute(cfg.AlertmanagerConfig.Route, cfg.AlertmanagerConfig.NamedRoutes)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to expand the named policies of the Alertmanager configuration")
+		}
+	}
+
+	namespaces, err := srv.ruleStore.GetUserVisibleNamespaces(c.Req.Context(), orgID, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	var rules []*models.AlertRule
+	if len(namespaces) > 0 {
+		namespaceUIDs := make([]string, 0, len(namespaces))
+		for uid := range namespaces {
+			namespaceUIDs = append(namespaceUIDs, uid)
+		}
+		ruleList, err := srv.ruleStore.ListAlertRules(c.Req.Context(), &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: namespaceUIDs,
+		})
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+		}
+		groupedRules := make(map[models.AlertRuleGroupKey][]*models.AlertRule)
+		for _, rule := range ruleList {
+			groupedRules[rule.GetGroupKey()] = append(groupedRules[rule.GetGroupKey()], rule)
+		}
+		for _, group := range groupedRules {
+			ok, err := srv.authz.HasAccessToRuleGroup(c.Req.Context(), c.SignedInUser, group)
+			if err != nil {
+				return response.ErrOrFallback(http.StatusInternalServerError, "cannot authorize access to rule group", err)
+			}
+			if ok {
+				rules = append(rules, group...)
+			}
+		}
+	}
+
+	result := definitions.ReceiverUsage{
+		Name:   name,
+		Routes: receiverRoutes(route, name),
+		Rules:  rulesRoutedToReceiver(route, name, rules, namespaces),
+	}
+
+	// the deliveries are counted even if the Alertmanager is not ready yet
+	am, _ := srv.mam.AlertmanagerFor(orgID)
+	if reporter, ok := am.(notifier.ReceiverDeliveryReporter); ok {
+		deliveries := reporter.GetReceiverDeliveries(name)
+		result.Deliveries = &deliveries
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// receiverRoutes returns the policies of the tree that explicitly reference the receiver.
+func receiverRoutes(route *definitions.Route, receiver string) []definitions.ReceiverUsageRoute {
+	result := []definitions.ReceiverUsageRoute{}
+	if route == nil {
+		return result
+	}
+	var walk func(r *definitions.Route, ar *dispatch.Route, path []int)
+	walk = func(r *definitions.Route, ar *dispatch.Route, path []int) {
+		if r.Receiver == receiver {
+			matchers := make([]string, 0, len(ar.Matchers))
+			for _, m := range ar.Matchers {
+				matchers = append(matchers, m.String())
+			}
+			result = append(result, definitions.ReceiverUsageRoute{
+				Path:     append([]int{}, path...),
+				Matchers: matchers,
+			})
+		}
+		for i, child := range r.Routes {
+			walk(child, ar.Routes[i], append(path, i))
+		}
+	}
+	walk(route, dispatch.NewRoute(route.AsAMRoute(), nil), []int{})
+	return result
+}
+
+// rulesRoutedToReceiver returns the rules whose alerts are routed to the receiver by the policy tree.
+// Only the labels known before evaluation are considered, templated labels are matched as they are written.
+func rulesRoutedToReceiver(route *definitions.Route, receiver string, rules []*models.AlertRule, namespaces map[string]*folder.Folder) []definitions.ReceiverUsageRule {
+	result := []definitions.ReceiverUsageRule{}
+	if route == nil {
+		return result
+	}
+	root := dispatch.NewRoute(route.AsAMRoute(), nil)
+	for _, rule := range rules {
+		lbls := make(model.LabelSet, len(rule.Labels)+2)
+		for k, v := range rule.Labels {
+			lbls[model.LabelName(k)] = model.LabelValue(v)
+		}
+		lbls[model.AlertNameLabel] = model.LabelValue(rule.Title)
+		if f, ok := namespaces[rule.NamespaceUID]; ok && f != nil {
+			lbls[models.FolderTitleLabel] = model.LabelValue(f.Title)
+		}
+		for _, r := range root.Match(lbls) {
+			if r.RouteOpts.Receiver == receiver {
+				result = append(result, definitions.ReceiverUsageRule{
+					UID:       rule.UID,
+					Title:     rule.Title,
+					FolderUID: rule.NamespaceUID,
+					RuleGroup: rule.RuleGroup,
+				})
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UID < result[j].UID
+	})
+	return result
+}
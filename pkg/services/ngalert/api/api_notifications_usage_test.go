@@ -0,0 +1,100 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestReceiverUsage(t *testing.T) {
+	route := &apimodels.Route{
+		Receiver: "default",
+		Routes: []*apimodels.Route{
+			{
+				Receiver:       "team-a",
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: "team", Value: "a"}},
+				Routes: []*apimodels.Route{
+					{
+						ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: "severity", Value: "critical"}},
+					},
+				},
+			},
+			{
+				Receiver:       "team-b",
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: ngmodels.FolderTitleLabel, Value: "Team B"}},
+			},
+			{
+				Receiver:       "team-a",
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: "alertname", Value: "DiskFull"}},
+			},
+		},
+	}
+	require.NoError(t, route.Validate())
+
+	t.Run("policies that reference the receiver are returned", func(t *testing.T) {
+		require.Equal(t, []apimodels.ReceiverUsageRoute{
+			{Path: []int{0}, Matchers: []string{`team="a"`}},
+			{Path: []int{2}, Matchers: []string{`alertname="DiskFull"`}},
+		}, receiverRoutes(route, "team-a"))
+		require.Equal(t, []apimodels.ReceiverUsageRoute{{Path: []int{}, Matchers: []string{}}}, receiverRoutes(route, "default"))
+		require.Empty(t, receiverRoutes(route, "unused"))
+	})
+
+	t.Run("rules routed to the receiver are returned", func(t *testing.T) {
+		namespaces := map[string]*folder.Folder{
+			"folder-a": {UID: "folder-a", Title: "Team A"},
+			"folder-b": {UID: "folder-b", Title: "Team B"},
+		}
+		rule := func(uid, title, namespace string, lbls map[string]string) *ngmodels.AlertRule {
+			return &ngmodels.AlertRule{UID: uid, Title: title, NamespaceUID: namespace, RuleGroup: "group", Labels: lbls}
+		}
+		rules := []*ngmodels.AlertRule{
+			rule("1", "HighCPU", "folder-a", map[string]string{"team": "a"}),
+			rule("2", "HighCPU", "folder-a", map[string]string{"team": "a", "severity": "critical"}),
+			rule("3", "DiskFull", "folder-a", nil),
+			rule("4", "HighCPU", "folder-b", nil),
+			rule("5", "HighCPU", "folder-a", nil),
+		}
+
+		teamA := rulesRoutedToReceiver(route, "team-a", rules, namespaces)
+		require.Len(t, teamA, 3)
+		require.Equal(t, "1", teamA[0].UID)
+		// the nested policy inherits the receiver of its parent
+		require.Equal(t, "2", teamA[1].UID)
+		require.Equal(t, apimodels.ReceiverUsageRule{UID: "3", Title: "DiskFull", FolderUID: "folder-a", RuleGroup: "group"}, teamA[2])
+
+		teamB := rulesRoutedToReceiver(route, "team-b", rules, namespaces)
+		require.Len(t, teamB, 1)
+		require.Equal(t, "4", teamB[0].UID)
+
+		defaultRules := rulesRoutedToReceiver(route, "default", rules, namespaces)
+		require.Len(t, defaultRules, 1)
+		require.Equal(t, "5", defaultRules[0].UID)
+	})
+	t.Run("policies of named policies referenced by the tree are returned", func(t *testing.T) {
+		named := []apimodels.NamedRoute{{
+			Name: "escalation",
+			Route: &apimodels.Route{
+				Receiver:       "pager",
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: "severity", Value: "critical"}},
+			},
+		}}
+		tree := &apimodels.Route{Receiver: "default", Routes: []*apimodels.Route{{Ref: "escalation"}}}
+		expanded, err := apimodels.ExpandRoute(tree, named)
+		require.NoError(t, err)
+
+		require.Equal(t, []apimodels.ReceiverUsageRoute{
+			{Path: []int{0}, Matchers: []string{`severity="critical"`}},
+		}, receiverRoutes(expanded, "pager"))
+		pager := rulesRoutedToReceiver(expanded, "pager", []*ngmodels.AlertRule{
+			{UID: "1", Title: "HighCPU", Labels: map[string]string{"severity": "critical"}},
+		}, nil)
+		require.Len(t, pager, 1)
+		require.Equal(t, "1", pager[0].UID)
+	})
+}
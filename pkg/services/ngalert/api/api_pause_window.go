@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func (srv ConfigSrv) RouteGetEvaluationPauseWindows(c *contextmodel.ReqContext) response.Response {
+	windows, err := srv.pauseWindowStore.GetEvaluationPauseWindows(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get evaluation pause windows")
+	}
+	result := make(apimodels.EvaluationPauseWindows, 0, len(windows))
+	for _, w := range windows {
+		result = append(result, pauseWindowToAPI(w))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv ConfigSrv) RoutePostEvaluationPauseWindow(c *contextmodel.ReqContext, body apimodels.EvaluationPauseWindow) response.Response {
+	window := &ngmodels.EvaluationPauseWindow{
+		OrgID:     c.SignedInUser.GetOrgID(),
+		FolderUID: body.FolderUID,
+		StartsAt:  body.StartsAt.UTC(),
+		EndsAt:    body.EndsAt.UTC(),
+		Comment:   body.Comment,
+		CreatedBy: c.SignedInUser.GetLogin(),
+	}
+	if err := window.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid evaluation pause window")
+	}
+	if !window.EndsAt.After(time.Now()) {
+		return ErrResp(http.StatusBadRequest, errors.New("pause window must end in the future"), "invalid evaluation pause window")
+	}
+	if window.FolderUID != "" && srv.ruleStore != nil {
+		if _, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), window.FolderUID, c.SignedInUser.GetOrgID(), c.SignedInUser); err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+	}
+	if err := srv.pauseWindowStore.CreateEvaluationPauseWindow(c.Req.Context(), window); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create evaluation pause window")
+	}
+	srv.pauseWindowsChanged(window.OrgID)
+	srv.log.Info("Created evaluation pause window", "user", window.CreatedBy, "folderUID", window.FolderUID, "startsAt", window.StartsAt, "endsAt", window.EndsAt)
+	return response.JSON(http.StatusCreated, pauseWindowToAPI(window))
+}
+
+func (srv ConfigSrv) RouteDeleteEvaluationPauseWindow(c *contextmodel.ReqContext, id string) response.Response {
+	windowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid evaluation pause window ID")
+	}
+	if err := srv.pauseWindowStore.DeleteEvaluationPauseWindow(c.Req.Context(), c.SignedInUser.GetOrgID(), windowID); err != nil {
+		if errors.Is(err, store.ErrEvaluationPauseWindowNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to delete evaluation pause window")
+	}
+	srv.pauseWindowsChanged(c.SignedInUser.GetOrgID())
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "evaluation pause window deleted"})
+}
+
+func (srv ConfigSrv) pauseWindowsChanged(orgID int64) {
+	if srv.pauseWindowObserver != nil {
+		srv.pauseWindowObserver.PauseWindowsChanged(orgID)
+	}
+}
+
+func pauseWindowToAPI(w *ngmodels.EvaluationPauseWindow) apimodels.EvaluationPauseWindow {
+	return apimodels.EvaluationPauseWindow{
+		ID:        w.ID,
+		FolderUID: w.FolderUID,
+		StartsAt:  w.StartsAt,
+		EndsAt:    w.EndsAt,
+		Comment:   w.Comment,
+		CreatedBy: w.CreatedBy,
+	}
+}
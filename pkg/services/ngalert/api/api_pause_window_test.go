@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+func TestEvaluationPauseWindowRoutes(t *testing.T) {
+	ctx := createRequestCtxInOrg(1)
+	ctx.OrgRole = org.RoleAdmin
+
+	windowStore := store.NewFakeEvaluationPauseWindowStore()
+	sut := ConfigSrv{
+		pauseWindowStore: windowStore,
+		log:              log.NewNopLogger(),
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("should reject invalid windows", func(t *testing.T) {
+		resp := sut.RoutePostEvaluationPauseWindow(ctx, definitions.EvaluationPauseWindow{StartsAt: now, EndsAt: now.Add(-time.Hour)})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+
+		resp = sut.RoutePostEvaluationPauseWindow(ctx, definitions.EvaluationPauseWindow{StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Empty(t, windowStore.Windows)
+	})
+
+	var created definitions.EvaluationPauseWindow
+	t.Run("should create and return windows", func(t *testing.T) {
+		resp := sut.RoutePostEvaluationPauseWindow(ctx, definitions.EvaluationPauseWindow{StartsAt: now, EndsAt: now.Add(time.Hour), Comment: "maintenance"})
+		require.Equal(t, http.StatusCreated, resp.Status())
+		require.NoError(t, json.Unmarshal(resp.Body(), &created))
+		require.NotZero(t, created.ID)
+
+		resp = sut.RouteGetEvaluationPauseWindows(ctx)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var windows definitions.EvaluationPauseWindows
+		require.NoError(t, json.Unmarshal(resp.Body(), &windows))
+		require.Len(t, windows, 1)
+		require.Equal(t, "maintenance", windows[0].Comment)
+		require.True(t, windows[0].EndsAt.Equal(now.Add(time.Hour)))
+	})
+
+	t.Run("should delete windows", func(t *testing.T) {
+		resp := sut.RouteDeleteEvaluationPauseWindow(ctx, "invalid")
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+
+		resp = sut.RouteDeleteEvaluationPauseWindow(ctx, strconv.FormatInt(created.ID, 10))
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		require.Empty(t, windowStore.Windows)
+
+		resp = sut.RouteDeleteEvaluationPauseWindow(ctx, strconv.FormatInt(created.ID, 10))
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
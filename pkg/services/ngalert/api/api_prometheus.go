@@ -16,19 +16,26 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/folder"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 type PrometheusSrv struct {
-	log     log.Logger
-	manager state.AlertInstanceManager
-	store   RuleStore
-	authz   RuleAccessControlService
+	log              log.Logger
+	manager          state.AlertInstanceManager
+	store            RuleStore
+	authz            RuleAccessControlService
+	datasourceCache  datasources.CacheService
+	adminConfigStore store.AdminConfigurationStore
+	cfg              *setting.UnifiedAlertingSettings
+	scheduler        RuleSchedulingStatusReader
 }
 
 const queryIncludeInternalLabels = "includeInternalLabels"
@@ -68,7 +75,7 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *contextmodel.ReqContext) respo
 		})
 	}
 
-	return response.JSON(http.StatusOK, alertResponse)
+	return selectedFieldsJSON(c, http.StatusOK, alertResponse)
 }
 
 func formatValues(alertState *state.State) string {
@@ -106,17 +113,27 @@ func getPanelIDFromRequest(r *http.Request) (int64, error) {
 	return 0, nil
 }
 
-func getMatchersFromRequest(r *http.Request) (labels.Matchers, error) {
+// getMatchersFromRequest returns the matchers of the query parameter. A matcher is either a JSON object,
+// such as {"name":"team","isEqual":true,"value":"payments"}, or a string such as team=payments or team=~pay.*.
+func getMatchersFromRequest(r *http.Request, param string) (labels.Matchers, error) {
 	var matchers labels.Matchers
-	for _, s := range r.URL.Query()["matcher"] {
-		var m labels.Matcher
-		if err := json.Unmarshal([]byte(s), &m); err != nil {
-			return nil, err
+	for _, s := range r.URL.Query()[param] {
+		var m *labels.Matcher
+		if strings.HasPrefix(strings.TrimSpace(s), "{") {
+			m = &labels.Matcher{}
+			if err := json.Unmarshal([]byte(s), m); err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			if m, err = labels.ParseMatcher(s); err != nil {
+				return nil, fmt.Errorf("bad matcher: %w", err)
+			}
 		}
 		if len(m.Name) == 0 {
 			return nil, errors.New("bad matcher: the name cannot be blank")
 		}
-		matchers = append(matchers, &m)
+		matchers = append(matchers, m)
 	}
 	return matchers, nil
 }
@@ -157,7 +174,11 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 	limitGroups := c.QueryInt64WithDefault("limit", -1)
 	limitRulesPerGroup := c.QueryInt64WithDefault("limit_rules", -1)
 	limitAlertsPerRule := c.QueryInt64WithDefault("limit_alerts", -1)
-	matchers, err := getMatchersFromRequest(c.Req)
+	matchers, err := getMatchersFromRequest(c.Req, "matcher")
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	ruleMatchers, err := getMatchersFromRequest(c.Req, "rule_matcher")
 	if err != nil {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
@@ -184,6 +205,13 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		labelOptions = append(labelOptions, ngmodels.WithoutInternalLabels())
 	}
 
+	var costEstimator *ruleCostEstimator
+	if c.QueryBoolWithDefault(queryIncludeCost, false) {
+		costEstimator = newRuleCostEstimator(c.Req.Context(), c.SignedInUser, srv.log, srv.datasourceCache, srv.manager, func() time.Duration {
+			return srv.inheritedInterval(c.SignedInUser.GetOrgID())
+		})
+	}
+
 	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.SignedInUser.GetOrgID(), c.SignedInUser)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
@@ -191,7 +219,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 
 	if len(namespaceMap) == 0 {
 		srv.log.Debug("User does not have access to any namespaces")
-		return response.JSON(http.StatusOK, ruleResponse)
+		return selectedFieldsJSON(c, http.StatusOK, ruleResponse)
 	}
 
 	namespaceUIDs := make([]string, len(namespaceMap))
@@ -212,11 +240,16 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		ruleResponse.DiscoveryBase.ErrorType = apiv1.ErrServer
 		return response.JSON(http.StatusInternalServerError, ruleResponse)
 	}
+	srv.resolveInheritedIntervals(c.SignedInUser.GetOrgID(), ruleList)
 
 	// Group rules together by Namespace and Rule Group. Rules are also grouped by Org ID,
 	// but in this API all rules belong to the same organization.
+	// Rules whose labels do not match the rule matchers are left out, and so are groups that have no rules left.
 	groupedRules := make(map[ngmodels.AlertRuleGroupKey][]*ngmodels.AlertRule)
 	for _, rule := range ruleList {
+		if len(ruleMatchers) > 0 && !matchersMatch(ruleMatchers, rule.GetLabels()) {
+			continue
+		}
 		groupKey := rule.GetGroupKey()
 		ruleGroup := groupedRules[groupKey]
 		ruleGroup = append(ruleGroup, rule)
@@ -242,7 +275,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		if !ok {
 			continue
 		}
-		ruleGroup, totals := srv.toRuleGroup(groupKey, folder, rules, limitAlertsPerRule, withStatesFast, matchers, labelOptions)
+		ruleGroup, totals := srv.toRuleGroup(groupKey, folder, rules, limitAlertsPerRule, withStatesFast, matchers, labelOptions, costEstimator)
 		ruleGroup.Totals = totals
 		for k, v := range totals {
 			rulesTotals[k] += v
@@ -287,7 +320,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		ruleResponse.Data.RuleGroups = ruleResponse.Data.RuleGroups[0:limitGroups]
 	}
 
-	return response.JSON(http.StatusOK, ruleResponse)
+	return selectedFieldsJSON(c, http.StatusOK, ruleResponse)
 }
 
 // This is the same as matchers.Matches but avoids the need to create a LabelSet
@@ -300,7 +333,32 @@ func matchersMatch(matchers []*labels.Matcher, labels map[string]string) bool {
 	return true
 }
 
-func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder *folder.Folder, rules []*ngmodels.AlertRule, limitAlerts int64, withStates map[eval.State]struct{}, matchers labels.Matchers, labelOptions []ngmodels.LabelOption) (*apimodels.RuleGroup, map[string]int64) {
+// inheritedInterval returns the evaluation interval of rule groups of the organization that inherit its interval.
+func (srv PrometheusSrv) inheritedInterval(orgID int64) time.Duration {
+	if srv.cfg == nil {
+		return 0
+	}
+	return inheritedInterval(srv.log, srv.adminConfigStore, srv.cfg, orgID)
+}
+
+// resolveInheritedIntervals replaces the rules that inherit the evaluation interval of the organization with copies
+// that use the effective interval, so that the response does not expose the inheritance sentinel.
+func (srv PrometheusSrv) resolveInheritedIntervals(orgID int64, rules []*ngmodels.AlertRule) {
+	var intervalSeconds int64
+	for i, rule := range rules {
+		if !rule.InheritsInterval() {
+			continue
+		}
+		if intervalSeconds == 0 {
+			intervalSeconds = int64(srv.inheritedInterval(orgID).Seconds())
+		}
+		resolved := *rule
+		resolved.IntervalSeconds = intervalSeconds
+		rules[i] = &resolved
+	}
+}
+
+func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder *folder.Folder, rules []*ngmodels.AlertRule, limitAlerts int64, withStates map[eval.State]struct{}, matchers labels.Matchers, labelOptions []ngmodels.LabelOption, costEstimator *ruleCostEstimator) (*apimodels.RuleGroup, map[string]int64) {
 	newGroup := &apimodels.RuleGroup{
 		Name: groupKey.RuleGroup,
 		// file is what Prometheus uses for provisioning, we replace it with namespace which is the folder in Grafana.
@@ -355,6 +413,7 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 
 			if alertState.LastEvaluationTime.After(newRule.LastEvaluation) {
 				newRule.LastEvaluation = alertState.LastEvaluationTime
+				newRule.LastEvaluationTraceID = alertState.LastEvaluationTraceID
 			}
 
 			newRule.EvaluationTime = alertState.EvaluationDuration.Seconds()
@@ -417,6 +476,9 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 		alertingRule.Rule = newRule
 		alertingRule.Totals = totals
 		alertingRule.TotalsFiltered = totalsFiltered
+		if costEstimator != nil {
+			alertingRule.Cost = costEstimator.Estimate(rule)
+		}
 		newGroup.Rules = append(newGroup.Rules, alertingRule)
 		newGroup.Interval = float64(rule.IntervalSeconds)
 		// TODO yuri. Change that when scheduler will process alerts in groups
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/expr"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	defaultFailingRulesLimit = 10
+
+	ruleHealthOK     = "ok"
+	ruleHealthError  = "error"
+	ruleHealthNoData = "nodata"
+)
+
+// RouteGetRuleHealth returns the health of the rules the user has access to, aggregated by folder and by data source,
+// along with the unhealthy rules with the most failing alert instances.
+func (srv PrometheusSrv) RouteGetRuleHealth(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt64WithDefault("limit", defaultFailingRulesLimit)
+	if limit < 0 {
+		limit = defaultFailingRulesLimit
+	}
+
+	result := apimodels.RuleHealthResponse{
+		Folders:     []apimodels.RuleHealthGroup{},
+		Datasources: []apimodels.RuleHealthGroup{},
+		TopFailing:  []apimodels.FailingRule{},
+	}
+
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	if len(namespaceMap) == 0 {
+		return response.JSON(http.StatusOK, result)
+	}
+	namespaceUIDs := make([]string, 0, len(namespaceMap))
+	for uid := range namespaceMap {
+		namespaceUIDs = append(namespaceUIDs, uid)
+	}
+
+	ruleList, err := srv.store.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		NamespaceUIDs: namespaceUIDs,
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+
+	groupedRules := make(map[ngmodels.AlertRuleGroupKey][]*ngmodels.AlertRule)
+	for _, rule := range ruleList {
+		groupedRules[rule.GetGroupKey()] = append(groupedRules[rule.GetGroupKey()], rule)
+	}
+
+	folders := make(map[string]*apimodels.RuleHealthGroup)
+	datasources := make(map[string]*apimodels.RuleHealthGroup)
+	var failing []apimodels.FailingRule
+	for groupKey, rules := range groupedRules {
+		folder := namespaceMap[groupKey.NamespaceUID]
+		if folder == nil {
+			continue
+		}
+		ok, err := srv.authz.HasAccessToRuleGroup(c.Req.Context(), c.SignedInUser, rules)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "cannot authorize access to rule group", err)
+		}
+		if !ok {
+			continue
+		}
+
+		for _, rule := range rules {
+			health := srv.ruleHealth(rule)
+
+			addRuleHealth(&result.Totals, health.Health)
+			f, ok := folders[rule.NamespaceUID]
+			if !ok {
+				f = &apimodels.RuleHealthGroup{UID: rule.NamespaceUID, Title: folder.Title}
+				folders[rule.NamespaceUID] = f
+			}
+			addRuleHealth(&f.Totals, health.Health)
+			for _, uid := range ruleDatasourceUIDs(rule) {
+				ds, ok := datasources[uid]
+				if !ok {
+					ds = &apimodels.RuleHealthGroup{UID: uid}
+					datasources[uid] = ds
+				}
+				addRuleHealth(&ds.Totals, health.Health)
+			}
+
+			if health.Health != ruleHealthOK {
+				failing = append(failing, health)
+			}
+		}
+	}
+
+	result.Folders = sortedRuleHealthGroups(folders)
+	result.Datasources = sortedRuleHealthGroups(datasources)
+
+	sort.Slice(failing, func(i, j int) bool {
+		if failing[i].FailingInstances != failing[j].FailingInstances {
+			return failing[i].FailingInstances > failing[j].FailingInstances
+		}
+		return failing[i].Title < failing[j].Title
+	})
+	if int64(len(failing)) > limit {
+		failing = failing[:limit]
+	}
+	if len(failing) > 0 {
+		result.TopFailing = failing
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// ruleHealth computes the health of the rule from the current state of its alert instances.
+// A rule is unhealthy if any of its instances is in the error or no data state, error taking precedence.
+func (srv PrometheusSrv) ruleHealth(rule *ngmodels.AlertRule) apimodels.FailingRule {
+	result := apimodels.FailingRule{
+		UID:       rule.UID,
+		Title:     rule.Title,
+		FolderUID: rule.NamespaceUID,
+		RuleGroup: rule.RuleGroup,
+		Health:    ruleHealthOK,
+	}
+	for _, s := range srv.manager.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+		if s.LastEvaluationTime.After(result.LastEvaluation) {
+			result.LastEvaluation = s.LastEvaluationTime
+			result.LastEvaluationTraceID = s.LastEvaluationTraceID
+		}
+		switch {
+		case s.Error != nil || s.State == eval.Error:
+			result.Health = ruleHealthError
+			result.FailingInstances++
+			if s.Error != nil {
+				result.LastError = s.Error.Error()
+			}
+		case s.State == eval.NoData:
+			if result.Health == ruleHealthOK {
+				result.Health = ruleHealthNoData
+			}
+			result.FailingInstances++
+		}
+	}
+	return result
+}
+
+func addRuleHealth(totals *apimodels.RuleHealthTotals, health string) {
+	switch health {
+	case ruleHealthError:
+		totals.Error++
+	case ruleHealthNoData:
+		totals.NoData++
+	default:
+		totals.OK++
+	}
+}
+
+// ruleDatasourceUIDs returns the distinct data sources queried by the rule, excluding expressions.
+func ruleDatasourceUIDs(rule *ngmodels.AlertRule) []string {
+	seen := make(map[string]struct{}, len(rule.Data))
+	result := make([]string, 0, len(rule.Data))
+	for _, q := range rule.Data {
+		if expr.IsDataSource(q.DatasourceUID) {
+			continue
+		}
+		if _, ok := seen[q.DatasourceUID]; ok {
+			continue
+		}
+		seen[q.DatasourceUID] = struct{}{}
+		result = append(result, q.DatasourceUID)
+	}
+	return result
+}
+
+func sortedRuleHealthGroups(groups map[string]*apimodels.RuleHealthGroup) []apimodels.RuleHealthGroup {
+	result := make([]apimodels.RuleHealthGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UID < result[j].UID
+	})
+	return result
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetRuleHealth(t *testing.T) {
+	orgID := int64(1)
+	req, err := http.NewRequest("GET", "/api/v1/rules/health", nil)
+	require.NoError(t, err)
+	c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID}}
+
+	withDatasources := func(uids ...string) func(r *ngmodels.AlertRule) {
+		return func(r *ngmodels.AlertRule) {
+			r.Data = []ngmodels.AlertQuery{{RefID: "Z", DatasourceUID: expr.DatasourceUID}}
+			for _, uid := range uids {
+				r.Data = append(r.Data, ngmodels.AlertQuery{RefID: uid, DatasourceUID: uid})
+			}
+		}
+	}
+
+	ruleStore := fakes.NewRuleStore(t)
+	fakeAIM := NewFakeAlertInstanceManager(t)
+	groupKey := ngmodels.GenerateGroupKey(orgID)
+	failing := ngmodels.AlertRuleGen(withGroupKey(groupKey), withDatasources("ds-1", "ds-2"))()
+	noData := ngmodels.AlertRuleGen(withGroupKey(groupKey), withDatasources("ds-1"))()
+	healthy := ngmodels.AlertRuleGen(withGroupKey(groupKey), withDatasources("ds-2"))()
+	ruleStore.PutRule(context.Background(), failing, noData, healthy)
+
+	fakeAIM.GenerateAlertInstances(orgID, failing.UID, 2, func(s *state.State) *state.State {
+		s.State = eval.Error
+		s.Error = errors.New("query failed")
+		return s
+	})
+	fakeAIM.GenerateAlertInstances(orgID, noData.UID, 1, func(s *state.State) *state.State {
+		s.State = eval.NoData
+		return s
+	})
+	fakeAIM.GenerateAlertInstances(orgID, healthy.UID, 1)
+
+	api := PrometheusSrv{
+		log:     log.NewNopLogger(),
+		manager: fakeAIM,
+		store:   ruleStore,
+		authz:   &fakeRuleAccessControlService{},
+	}
+
+	response := api.RouteGetRuleHealth(c)
+	require.Equal(t, http.StatusOK, response.Status())
+	result := apimodels.RuleHealthResponse{}
+	require.NoError(t, json.Unmarshal(response.Body(), &result))
+
+	require.Equal(t, apimodels.RuleHealthTotals{OK: 1, Error: 1, NoData: 1}, result.Totals)
+
+	require.Len(t, result.Folders, 1)
+	require.Equal(t, groupKey.NamespaceUID, result.Folders[0].UID)
+	require.Equal(t, result.Totals, result.Folders[0].Totals)
+
+	require.Equal(t, []apimodels.RuleHealthGroup{
+		{UID: "ds-1", Totals: apimodels.RuleHealthTotals{Error: 1, NoData: 1}},
+		{UID: "ds-2", Totals: apimodels.RuleHealthTotals{OK: 1, Error: 1}},
+	}, result.Datasources)
+
+	require.Len(t, result.TopFailing, 2)
+	require.Equal(t, failing.UID, result.TopFailing[0].UID)
+	require.Equal(t, "error", result.TopFailing[0].Health)
+	require.Equal(t, "query failed", result.TopFailing[0].LastError)
+	require.EqualValues(t, 2, result.TopFailing[0].FailingInstances)
+	require.Equal(t, noData.UID, result.TopFailing[1].UID)
+	require.Equal(t, "nodata", result.TopFailing[1].Health)
+}
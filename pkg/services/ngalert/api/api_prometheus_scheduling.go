@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RuleSchedulingStatusReader provides the view of the scheduler on alert rules.
+type RuleSchedulingStatusReader interface {
+	RuleSchedulingStatus(ctx context.Context, key ngmodels.AlertRuleKey) ngmodels.RuleSchedulingStatus
+}
+
+// RouteGetRuleScheduling returns the view of the scheduler of this instance on the rules of a folder the user has
+// access to, so that users can verify that a rule is, or is not, evaluated.
+func (srv PrometheusSrv) RouteGetRuleScheduling(c *contextmodel.ReqContext, namespaceUID string) response.Response {
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	rules, err := srv.store.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		NamespaceUIDs: []string{namespace.UID},
+		RuleGroup:     c.Query("ruleGroup"),
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+
+	groupedRules := make(map[ngmodels.AlertRuleGroupKey][]*ngmodels.AlertRule)
+	for _, rule := range rules {
+		groupedRules[rule.GetGroupKey()] = append(groupedRules[rule.GetGroupKey()], rule)
+	}
+
+	result := apimodels.RuleSchedulingResponse{Rules: make([]apimodels.RuleScheduling, 0, len(rules))}
+	for _, group := range groupedRules {
+		ok, err := srv.authz.HasAccessToRuleGroup(c.Req.Context(), c.SignedInUser, group)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "cannot authorize access to rule group", err)
+		}
+		if !ok {
+			continue
+		}
+		for _, rule := range group {
+			result.Rules = append(result.Rules, srv.ruleScheduling(c.Req.Context(), rule))
+		}
+	}
+	sort.Slice(result.Rules, func(i, j int) bool {
+		if result.Rules[i].RuleGroup != result.Rules[j].RuleGroup {
+			return result.Rules[i].RuleGroup < result.Rules[j].RuleGroup
+		}
+		return result.Rules[i].Title < result.Rules[j].Title
+	})
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv PrometheusSrv) ruleScheduling(ctx context.Context, rule *ngmodels.AlertRule) apimodels.RuleScheduling {
+	result := apimodels.RuleScheduling{
+		UID:       rule.UID,
+		Title:     rule.Title,
+		FolderUID: rule.NamespaceUID,
+		RuleGroup: rule.RuleGroup,
+		IsPaused:  rule.IsPaused,
+		Reason:    ngmodels.SchedulingReasonNotScheduled,
+	}
+	if srv.scheduler == nil {
+		return result
+	}
+
+	status := srv.scheduler.RuleSchedulingStatus(ctx, rule.GetKey())
+	result.Scheduled = status.Scheduled
+	result.Reason = status.Reason
+	result.IntervalSeconds = status.IntervalSeconds
+	if !status.NextEvaluation.IsZero() {
+		next := status.NextEvaluation.UTC()
+		result.NextEvaluation = &next
+	}
+	if last := status.LastEvaluation; last != nil {
+		result.LastEvaluation = &apimodels.RuleSchedulingEvaluation{
+			ScheduledAt:    last.ScheduledAt.UTC(),
+			EvaluatedAt:    last.EvaluatedAt.UTC(),
+			EvaluationTime: last.Duration.Seconds(),
+			Attempt:        last.Attempt,
+			Health:         ruleHealthOK,
+			LastError:      last.Error,
+		}
+		if last.Error != "" {
+			result.LastEvaluation.Health = ruleHealthError
+		}
+	}
+	return result
+}
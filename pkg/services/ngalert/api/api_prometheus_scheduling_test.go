@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeSchedulingStatusReader map[ngmodels.AlertRuleKey]ngmodels.RuleSchedulingStatus
+
+func (f fakeSchedulingStatusReader) RuleSchedulingStatus(_ context.Context, key ngmodels.AlertRuleKey) ngmodels.RuleSchedulingStatus {
+	if status, ok := f[key]; ok {
+		return status
+	}
+	return ngmodels.RuleSchedulingStatus{Reason: ngmodels.SchedulingReasonNotScheduled}
+}
+
+func TestRouteGetRuleScheduling(t *testing.T) {
+	orgID := int64(1)
+	req, err := http.NewRequest("GET", "/api/v1/rules/scheduling/folder", nil)
+	require.NoError(t, err)
+	c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID}}
+
+	ruleStore := fakes.NewRuleStore(t)
+	groupKey := ngmodels.GenerateGroupKey(orgID)
+	evaluated := ngmodels.AlertRuleGen(withGroupKey(groupKey), ngmodels.WithTitle("a"))()
+	paused := ngmodels.AlertRuleGen(withGroupKey(groupKey), ngmodels.WithTitle("b"))()
+	notLoaded := ngmodels.AlertRuleGen(withGroupKey(groupKey), ngmodels.WithTitle("c"))()
+	ruleStore.PutRule(context.Background(), evaluated, paused, notLoaded)
+
+	next := time.Unix(120, 0)
+	scheduler := fakeSchedulingStatusReader{
+		evaluated.GetKey(): {
+			Scheduled:       true,
+			IntervalSeconds: 60,
+			NextEvaluation:  next,
+			LastEvaluation: &ngmodels.RuleEvaluationStatus{
+				ScheduledAt: time.Unix(60, 0),
+				EvaluatedAt: time.Unix(61, 0),
+				Duration:    1500 * time.Millisecond,
+				Attempt:     2,
+				Error:       "query failed",
+			},
+		},
+		paused.GetKey(): {IntervalSeconds: 60, Reason: ngmodels.SchedulingReasonPaused},
+	}
+
+	api := PrometheusSrv{
+		log:       log.NewNopLogger(),
+		store:     ruleStore,
+		authz:     &fakeRuleAccessControlService{},
+		scheduler: scheduler,
+	}
+
+	response := api.RouteGetRuleScheduling(c, groupKey.NamespaceUID)
+	require.Equal(t, http.StatusOK, response.Status())
+	result := apimodels.RuleSchedulingResponse{}
+	require.NoError(t, json.Unmarshal(response.Body(), &result))
+	require.Len(t, result.Rules, 3)
+
+	require.Equal(t, evaluated.UID, result.Rules[0].UID)
+	require.True(t, result.Rules[0].Scheduled)
+	require.Equal(t, int64(60), result.Rules[0].IntervalSeconds)
+	require.True(t, next.Equal(*result.Rules[0].NextEvaluation))
+	require.Equal(t, &apimodels.RuleSchedulingEvaluation{
+		ScheduledAt:    time.Unix(60, 0).UTC(),
+		EvaluatedAt:    time.Unix(61, 0).UTC(),
+		EvaluationTime: 1.5,
+		Attempt:        2,
+		Health:         ruleHealthError,
+		LastError:      "query failed",
+	}, result.Rules[0].LastEvaluation)
+
+	require.Equal(t, paused.UID, result.Rules[1].UID)
+	require.False(t, result.Rules[1].Scheduled)
+	require.Equal(t, ngmodels.SchedulingReasonPaused, result.Rules[1].Reason)
+	require.Nil(t, result.Rules[1].NextEvaluation)
+
+	require.Equal(t, notLoaded.UID, result.Rules[2].UID)
+	require.Equal(t, ngmodels.SchedulingReasonNotScheduled, result.Rules[2].Reason)
+	require.Nil(t, result.Rules[2].LastEvaluation)
+}
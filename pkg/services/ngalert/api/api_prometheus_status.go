@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// prometheusBuildDateFormat is the format of the build date of the Prometheus build information.
+const prometheusBuildDateFormat = "20060102-15:04:05"
+
+// alertMetricMetadata is the metadata of the series that describe the alerts of Grafana managed rules,
+// which Grafana writes to a Prometheus-compatible data source if state remote write is enabled.
+var alertMetricMetadata = map[string][]apimodels.MetricMetadata{
+	"ALERTS": {{
+		Type: "gauge",
+		Help: "Alerts of Grafana managed alert rules that are pending or firing, by the alertstate label. The value is always 1.",
+	}},
+	"ALERTS_FOR_STATE": {{
+		Type: "gauge",
+		Help: "Unix time in seconds at which the alerts of Grafana managed alert rules became active.",
+	}},
+}
+
+// RouteGetBuildInfo returns the build information of Grafana in the format of the Prometheus build information API,
+// so that tools that probe it before they use the rules and alerts APIs work with the Grafana managed backend.
+func (srv PrometheusSrv) RouteGetBuildInfo(c *contextmodel.ReqContext) response.Response {
+	info := apimodels.BuildInfo{
+		Application: "Grafana",
+		Version:     setting.BuildVersion,
+		Revision:    setting.BuildCommit,
+		Branch:      setting.BuildBranch,
+		GoVersion:   runtime.Version(),
+		Features: map[string]string{
+			"ruler_config_api":        "true",
+			"alertmanager_config_api": "true",
+		},
+	}
+	if setting.BuildStamp > 0 {
+		info.BuildDate = time.Unix(setting.BuildStamp, 0).UTC().Format(prometheusBuildDateFormat)
+	}
+	return response.JSON(http.StatusOK, apimodels.BuildInfoResponse{
+		DiscoveryBase: apimodels.DiscoveryBase{Status: "success"},
+		Data:          info,
+	})
+}
+
+// RouteGetMetricMetadata returns the metadata of the metrics of the alerts of Grafana managed rules in the format
+// of the Prometheus metadata API. Like Prometheus, it returns no metadata for unknown metrics.
+func (srv PrometheusSrv) RouteGetMetricMetadata(c *contextmodel.ReqContext) response.Response {
+	metric := c.Query("metric")
+	limit := c.QueryInt64WithDefault("limit", -1)
+
+	names := make([]string, 0, len(alertMetricMetadata))
+	for name := range alertMetricMetadata {
+		if metric == "" || metric == name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if limit > 0 && int64(len(names)) > limit {
+		names = names[:limit]
+	}
+
+	data := make(map[string][]apimodels.MetricMetadata, len(names))
+	for _, name := range names {
+		data[name] = alertMetricMetadata[name]
+	}
+	return response.JSON(http.StatusOK, apimodels.MetricMetadataResponse{
+		DiscoveryBase: apimodels.DiscoveryBase{Status: "success"},
+		Data:          data,
+	})
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetBuildInfo(t *testing.T) {
+	version, stamp := setting.BuildVersion, setting.BuildStamp
+	t.Cleanup(func() {
+		setting.BuildVersion, setting.BuildStamp = version, stamp
+	})
+	setting.BuildVersion = "11.0.0"
+	setting.BuildStamp = 1709251200
+
+	req, err := http.NewRequest("GET", "/api/prometheus/grafana/api/v1/status/buildinfo", nil)
+	require.NoError(t, err)
+	c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: 1}}
+	srv := PrometheusSrv{log: log.NewNopLogger()}
+
+	resp := srv.RouteGetBuildInfo(c)
+	require.Equal(t, http.StatusOK, resp.Status())
+	var body apimodels.BuildInfoResponse
+	require.NoError(t, json.Unmarshal(resp.Body(), &body))
+	require.Equal(t, "success", body.Status)
+	require.Equal(t, "11.0.0", body.Data.Version)
+	require.Equal(t, "20240301-00:00:00", body.Data.BuildDate)
+	require.NotEmpty(t, body.Data.GoVersion)
+	require.Equal(t, "true", body.Data.Features["ruler_config_api"])
+}
+
+func TestRouteGetMetricMetadata(t *testing.T) {
+	srv := PrometheusSrv{log: log.NewNopLogger()}
+	getMetadata := func(t *testing.T, query string) apimodels.MetricMetadataResponse {
+		t.Helper()
+		req, err := http.NewRequest("GET", "/api/prometheus/grafana/api/v1/metadata?"+query, nil)
+		require.NoError(t, err)
+		c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: 1}}
+		resp := srv.RouteGetMetricMetadata(c)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var body apimodels.MetricMetadataResponse
+		require.NoError(t, json.Unmarshal(resp.Body(), &body))
+		require.Equal(t, "success", body.Status)
+		return body
+	}
+
+	t.Run("should return the metadata of all metrics", func(t *testing.T) {
+		body := getMetadata(t, "")
+		require.Len(t, body.Data, 2)
+		require.Equal(t, "gauge", body.Data["ALERTS"][0].Type)
+		require.Equal(t, "gauge", body.Data["ALERTS_FOR_STATE"][0].Type)
+	})
+
+	t.Run("should filter by metric and limit the metrics", func(t *testing.T) {
+		require.Len(t, getMetadata(t, "metric=ALERTS_FOR_STATE").Data, 1)
+		require.Empty(t, getMetadata(t, "metric=up").Data)
+
+		body := getMetadata(t, "limit=1")
+		require.Len(t, body.Data, 1)
+		require.Contains(t, body.Data, "ALERTS")
+	})
+}
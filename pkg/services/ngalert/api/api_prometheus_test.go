@@ -16,6 +16,7 @@ import (
 	alertingModels "github.com/grafana/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
@@ -1250,6 +1251,54 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 			require.Equal(t, map[string]int64{"normal": 1}, rg.Rules[0].TotalsFiltered)
 		})
 	})
+
+	t.Run("test with matcher on rule labels", func(t *testing.T) {
+		fakeStore, _, api := setupAPI(t)
+		folder1 := &folder.Folder{Title: "Folder-1"}
+		payments := ngmodels.GenerateAlertRules(1, ngmodels.AlertRuleGen(withOrgID(orgID), withGroup("Rule-Group-1"), withNamespace(folder1), ngmodels.WithLabels(data.Labels{"team": "payments"})))
+		search := ngmodels.GenerateAlertRules(1, ngmodels.AlertRuleGen(withOrgID(orgID), withGroup("Rule-Group-1"), withNamespace(folder1), ngmodels.WithLabels(data.Labels{"team": "search"})))
+		other := ngmodels.GenerateAlertRules(1, ngmodels.AlertRuleGen(withOrgID(orgID), withGroup("Rule-Group-2"), withNamespace(folder1), ngmodels.WithLabels(data.Labels{"team": "search"})))
+		fakeStore.PutRule(context.Background(), payments...)
+		fakeStore.PutRule(context.Background(), search...)
+		fakeStore.PutRule(context.Background(), other...)
+
+		getRules := func(t *testing.T, query string) response.Response {
+			r, err := http.NewRequest("GET", "/api/v1/rules?"+query, nil)
+			require.NoError(t, err)
+			c := &contextmodel.ReqContext{
+				Context: &web.Context{Req: r},
+				SignedInUser: &user.SignedInUser{
+					OrgID:       orgID,
+					Permissions: queryPermissions,
+				},
+			}
+			return api.RouteGetRuleStatuses(c)
+		}
+
+		t.Run("should return the rules whose labels match", func(t *testing.T) {
+			resp := getRules(t, "rule_matcher=team%3Dpayments")
+			require.Equal(t, http.StatusOK, resp.Status())
+			var res apimodels.RuleResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &res))
+			require.Len(t, res.Data.RuleGroups, 1, "groups without matching rules must not be returned")
+			require.Equal(t, "Rule-Group-1", res.Data.RuleGroups[0].Name)
+			require.Len(t, res.Data.RuleGroups[0].Rules, 1)
+			require.Equal(t, payments[0].Title, res.Data.RuleGroups[0].Rules[0].Name)
+		})
+
+		t.Run("should accept matchers in the JSON format", func(t *testing.T) {
+			resp := getRules(t, "rule_matcher={\"name\":\"team\",\"isEqual\":true,\"isRegex\":true,\"value\":\"search|payments\"}")
+			require.Equal(t, http.StatusOK, resp.Status())
+			var res apimodels.RuleResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &res))
+			require.Len(t, res.Data.RuleGroups, 2)
+		})
+
+		t.Run("should return 400 if a matcher is invalid", func(t *testing.T) {
+			resp := getRules(t, "rule_matcher=team")
+			require.Equal(t, http.StatusBadRequest, resp.Status())
+		})
+	})
 }
 
 func setupAPI(t *testing.T) (*fakes.RuleStore, *fakeAlertInstanceManager, PrometheusSrv) {
@@ -2,20 +2,30 @@ package api
 
 import (
 	"context"
+	"crypto/rsa"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -28,6 +38,18 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	adminConfigStore    store.AdminConfigurationStore
+	datasourceCache     datasources.CacheService
+	stateManager        state.AlertInstanceManager
+	contactPointHealth  ContactPointHealthReporter
+	templateVersions    store.TemplateVersionStore
+	usageInsights       store.UsageInsightStore
+	cfg                 *setting.UnifiedAlertingSettings
+}
+
+// ContactPointHealthReporter reports the health of contact points, derived from their recent notifications.
+type ContactPointHealthReporter interface {
+	GetContactPointHealth(orgID int64, uid string) *definitions.ContactPointHealth
 }
 
 type ContactPointService interface {
@@ -39,6 +61,7 @@ type ContactPointService interface {
 
 type TemplateService interface {
 	GetTemplates(ctx context.Context, orgID int64) (map[string]string, error)
+	GetTemplateReferences(ctx context.Context, orgID int64) (map[string][]string, error)
 	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error)
 	DeleteTemplate(ctx context.Context, orgID int64, name string) error
 }
@@ -47,6 +70,10 @@ type NotificationPolicyService interface {
 	GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
 	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p alerting_models.Provenance) error
 	ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
+	GetNamedPolicies(ctx context.Context, orgID int64) ([]definitions.NamedRoute, error)
+	GetNamedPolicy(ctx context.Context, orgID int64, name string) (definitions.NamedRoute, error)
+	UpsertNamedPolicy(ctx context.Context, orgID int64, named definitions.NamedRoute, p alerting_models.Provenance) error
+	DeleteNamedPolicy(ctx context.Context, orgID int64, name string) error
 }
 
 type MuteTimingService interface {
@@ -123,6 +150,40 @@ func (srv *ProvisioningSrv) RouteResetPolicyTree(c *contextmodel.ReqContext) res
 	return response.JSON(http.StatusAccepted, tree)
 }
 
+func (srv *ProvisioningSrv) RouteGetNamedPolicies(c *contextmodel.ReqContext) response.Response {
+	named, err := srv.policies.GetNamedPolicies(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get named policies", err)
+	}
+	return response.JSON(http.StatusOK, named)
+}
+
+func (srv *ProvisioningSrv) RouteGetNamedPolicy(c *contextmodel.ReqContext, name string) response.Response {
+	named, err := srv.policies.GetNamedPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), name)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get named policy", err)
+	}
+	return response.JSON(http.StatusOK, named)
+}
+
+func (srv *ProvisioningSrv) RoutePutNamedPolicy(c *contextmodel.ReqContext, route definitions.Route, name string) response.Response {
+	provenance := determineProvenance(c)
+	named := definitions.NamedRoute{Name: name, Route: &route}
+	err := srv.policies.UpsertNamedPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), named, alerting_models.Provenance(provenance))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to save named policy", err)
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "named policy updated"})
+}
+
+func (srv *ProvisioningSrv) RouteDeleteNamedPolicy(c *contextmodel.ReqContext, name string) response.Response {
+	err := srv.policies.DeleteNamedPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), name)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete named policy", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
 func (srv *ProvisioningSrv) RouteGetContactPoints(c *contextmodel.ReqContext) response.Response {
 	q := provisioning.ContactPointQuery{
 		Name:  c.Query("name"),
@@ -135,15 +196,31 @@ func (srv *ProvisioningSrv) RouteGetContactPoints(c *contextmodel.ReqContext) re
 		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, cps)
+	if srv.contactPointHealth != nil {
+		for i := range cps {
+			cps[i].Health = srv.contactPointHealth.GetContactPointHealth(q.OrgID, cps[i].UID)
+		}
+	}
+	return compressedResponse(response.JSON(http.StatusOK, cps))
 }
 
 func (srv *ProvisioningSrv) RouteGetContactPointsExport(c *contextmodel.ReqContext) response.Response {
 	q := provisioning.ContactPointQuery{
 		Name:    c.Query("name"),
 		OrgID:   c.SignedInUser.GetOrgID(),
+		Types:   c.QueryStrings("type"),
 		Decrypt: c.QueryBoolWithDefault("decrypt", false),
 	}
+	if publicKey := c.Query("publicKey"); publicKey != "" {
+		if q.Decrypt {
+			return ErrResp(http.StatusBadRequest, errors.New("decrypt and publicKey cannot be used together"), "")
+		}
+		key, err := parseExportPublicKey(publicKey)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid public key")
+		}
+		q.EncryptionKey = key
+	}
 	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), q, c.SignedInUser)
 	if err != nil {
 		if errors.Is(err, provisioning.ErrPermissionDenied) {
@@ -201,11 +278,19 @@ func (srv *ProvisioningSrv) RouteGetTemplates(c *contextmodel.ReqContext) respon
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
+	references, err := srv.templates.GetTemplateReferences(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	receiver := c.Query("receiver")
 	result := make([]definitions.NotificationTemplate, 0, len(templates))
 	for k, v := range templates {
-		result = append(result, definitions.NotificationTemplate{Name: k, Template: v})
+		if receiver != "" && !slices.Contains(references[k], receiver) {
+			continue
+		}
+		result = append(result, definitions.NotificationTemplate{Name: k, Template: v, Receivers: references[k]})
 	}
-	return response.JSON(http.StatusOK, result)
+	return compressedResponse(response.JSON(http.StatusOK, result))
 }
 
 func (srv *ProvisioningSrv) RouteGetTemplate(c *contextmodel.ReqContext, name string) response.Response {
@@ -214,15 +299,28 @@ func (srv *ProvisioningSrv) RouteGetTemplate(c *contextmodel.ReqContext, name st
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
 	if tmpl, ok := templates[name]; ok {
-		return response.JSON(http.StatusOK, definitions.NotificationTemplate{Name: name, Template: tmpl})
+		references, err := srv.templates.GetTemplateReferences(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		return response.JSON(http.StatusOK, definitions.NotificationTemplate{Name: name, Template: tmpl, Receivers: references[name]})
 	}
 	return response.Empty(http.StatusNotFound)
 }
 
 func (srv *ProvisioningSrv) RoutePutTemplate(c *contextmodel.ReqContext, body definitions.NotificationTemplateContent, name string) response.Response {
+	return srv.setTemplate(c, name, body.Template)
+}
+
+// setTemplate saves the content of the template and records it as a new version of the template.
+func (srv *ProvisioningSrv) setTemplate(c *contextmodel.ReqContext, name, content string) response.Response {
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
 	tmpl := definitions.NotificationTemplate{
 		Name:       name,
-		Template:   body.Template,
+		Template:   content,
 		Provenance: determineProvenance(c),
 	}
 	modified, err := srv.templates.SetTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), tmpl)
@@ -232,13 +330,146 @@ func (srv *ProvisioningSrv) RoutePutTemplate(c *contextmodel.ReqContext, body de
 		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
+	// The template is saved, failing to record the version must not fail the request.
+	previous, hasPrevious := templates[name]
+	if err := srv.saveTemplateVersion(c, name, previous, hasPrevious, modified.Template); err != nil {
+		srv.log.FromContext(c.Req.Context()).Error("Failed to save template version", "template", name, "error", err)
+	}
 	return response.JSON(http.StatusAccepted, modified)
 }
 
+// saveTemplateVersion records the content as the latest version of the template. The previous content of a template
+// that has no versions yet, because it was created before templates were versioned, is recorded first so that it can
+// be restored.
+func (srv *ProvisioningSrv) saveTemplateVersion(c *contextmodel.ReqContext, name, previous string, hasPrevious bool, content string) error {
+	if srv.templateVersions == nil {
+		return nil
+	}
+	orgID := c.SignedInUser.GetOrgID()
+	if hasPrevious {
+		versions, err := srv.templateVersions.GetTemplateVersions(c.Req.Context(), orgID, name)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			initial := &alerting_models.NotificationTemplateVersion{OrgID: orgID, Name: name, Template: previous}
+			if err := srv.templateVersions.SaveTemplateVersion(c.Req.Context(), initial); err != nil {
+				return err
+			}
+		}
+	}
+	namespace, id := c.SignedInUser.GetNamespacedID()
+	return srv.templateVersions.SaveTemplateVersion(c.Req.Context(), &alerting_models.NotificationTemplateVersion{
+		OrgID:          orgID,
+		Name:           name,
+		Template:       content,
+		CreatedBy:      fmt.Sprintf("%s:%s", namespace, id),
+		CreatedByLogin: c.SignedInUser.GetLogin(),
+	})
+}
+
+func (srv *ProvisioningSrv) RouteGetTemplateVersions(c *contextmodel.ReqContext, name string) response.Response {
+	versions, err := srv.templateVersions.GetTemplateVersions(c.Req.Context(), c.SignedInUser.GetOrgID(), name)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get template versions")
+	}
+	if len(versions) == 0 {
+		// A template that was not saved since templates are versioned has no versions yet.
+		templates, err := srv.templates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		if _, ok := templates[name]; !ok {
+			return response.Empty(http.StatusNotFound)
+		}
+	}
+	result := make(definitions.NotificationTemplateVersions, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, definitions.NotificationTemplateVersion{
+			Version:   v.Version,
+			Template:  v.Template,
+			CreatedBy: v.CreatedByLogin,
+			Created:   v.Created,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetTemplateVersionDiff(c *contextmodel.ReqContext, name, version string) response.Response {
+	v, errResp := srv.getTemplateVersion(c, name, version)
+	if errResp != nil {
+		return errResp
+	}
+	result := definitions.NotificationTemplateVersionDiff{Version: v.Version}
+
+	var other string
+	if compareTo := c.Query("compareTo"); compareTo != "" {
+		o, errResp := srv.getTemplateVersion(c, name, compareTo)
+		if errResp != nil {
+			return errResp
+		}
+		result.CompareTo = o.Version
+		other = o.Template
+	} else {
+		templates, err := srv.templates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		current, ok := templates[name]
+		if !ok {
+			return response.Empty(http.StatusNotFound)
+		}
+		other = current
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(v.Template),
+		B:        difflib.SplitLines(other),
+		FromFile: fmt.Sprintf("version %d", v.Version),
+		ToFile:   templateDiffTarget(result.CompareTo),
+		Context:  3,
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to compare template versions")
+	}
+	result.Diff = diff
+	return response.JSON(http.StatusOK, result)
+}
+
+func templateDiffTarget(version int64) string {
+	if version == 0 {
+		return "current"
+	}
+	return fmt.Sprintf("version %d", version)
+}
+
+func (srv *ProvisioningSrv) RoutePostTemplateVersionRollback(c *contextmodel.ReqContext, name, version string) response.Response {
+	v, errResp := srv.getTemplateVersion(c, name, version)
+	if errResp != nil {
+		return errResp
+	}
+	return srv.setTemplate(c, name, v.Template)
+}
+
+func (srv *ProvisioningSrv) getTemplateVersion(c *contextmodel.ReqContext, name, version string) (alerting_models.NotificationTemplateVersion, response.Response) {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return alerting_models.NotificationTemplateVersion{}, ErrResp(http.StatusBadRequest, err, "invalid template version")
+	}
+	result, err := srv.templateVersions.GetTemplateVersion(c.Req.Context(), c.SignedInUser.GetOrgID(), name, v)
+	if errors.Is(err, store.ErrTemplateVersionNotFound) {
+		return result, ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return result, ErrResp(http.StatusInternalServerError, err, "failed to get template version")
+	}
+	return result, nil
+}
+
 func (srv *ProvisioningSrv) RouteDeleteTemplate(c *contextmodel.ReqContext, name string) response.Response {
 	err := srv.templates.DeleteTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), name)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete template", err)
 	}
 	return response.JSON(http.StatusNoContent, nil)
 }
@@ -270,7 +501,7 @@ func (srv *ProvisioningSrv) RouteGetMuteTimings(c *contextmodel.ReqContext) resp
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timings", err)
 	}
-	return response.JSON(http.StatusOK, timings)
+	return compressedResponse(response.JSON(http.StatusOK, timings))
 }
 
 func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *contextmodel.ReqContext) response.Response {
@@ -314,7 +545,7 @@ func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) respo
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRules(rules, provenances))
+	return compressedResponse(selectedFieldsJSON(c, http.StatusOK, ProvisionedAlertRuleFromAlertRules(rules, provenances)))
 }
 
 func (srv *ProvisioningSrv) RouteRouteGetAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
@@ -334,6 +565,9 @@ func (srv *ProvisioningSrv) RoutePostAlertRule(c *contextmodel.ReqContext, ar de
 	if err != nil {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
+	if resp := srv.validateAnnotationSchema(c.SignedInUser.GetOrgID(), &upstreamModel); resp != nil {
+		return resp
+	}
 	provenance := determineProvenance(c)
 	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
 	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), upstreamModel, alerting_models.Provenance(provenance), userID)
@@ -364,6 +598,9 @@ func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar def
 	}
 	updated.OrgID = c.SignedInUser.GetOrgID()
 	updated.UID = UID
+	if resp := srv.validateAnnotationSchema(updated.OrgID, &updated); resp != nil {
+		return resp
+	}
 	provenance := determineProvenance(c)
 	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), updated, alerting_models.Provenance(provenance))
 	if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
@@ -439,6 +676,7 @@ func (srv *ProvisioningSrv) RouteGetAlertRulesExport(c *contextmodel.ReqContext)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
+	srv.addRuleCosts(c, &e, groupsWithTitle)
 
 	return exportResponse(c, e)
 }
@@ -457,6 +695,7 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleGroupExport(c *contextmodel.ReqCont
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
+	srv.addRuleCosts(c, &e, []alerting_models.AlertRuleGroupWithFolderTitle{g})
 
 	return exportResponse(c, e)
 }
@@ -471,12 +710,14 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext,
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle([]alerting_models.AlertRuleGroupWithFolderTitle{
+	groups := []alerting_models.AlertRuleGroupWithFolderTitle{
 		alerting_models.NewAlertRuleGroupWithFolderTitleFromRulesGroup(rule.AlertRule.GetGroupKey(), alerting_models.RulesGroup{&rule.AlertRule}, rule.FolderTitle),
-	})
+	}
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groups)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
+	srv.addRuleCosts(c, &e, groups)
 
 	return exportResponse(c, e)
 }
@@ -488,6 +729,13 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 	if err != nil {
 		ErrResp(http.StatusBadRequest, err, "")
 	}
+	toValidate := make([]*alerting_models.AlertRule, 0, len(groupModel.Rules))
+	for i := range groupModel.Rules {
+		toValidate = append(toValidate, &groupModel.Rules[i])
+	}
+	if resp := srv.validateAnnotationSchema(c.SignedInUser.GetOrgID(), toValidate...); resp != nil {
+		return resp
+	}
 	provenance := determineProvenance(c)
 
 	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
@@ -507,6 +755,28 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 	return response.JSON(http.StatusOK, ag)
 }
 
+// validateAnnotationSchema checks the rules against the annotation schema of the organization.
+func (srv *ProvisioningSrv) validateAnnotationSchema(orgID int64, rules ...*alerting_models.AlertRule) response.Response {
+	cfg, err := store.FindAdminConfiguration(srv.adminConfigStore, orgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the annotation schema of the organization")
+	}
+	return validateAnnotationSchema(cfg, rules...)
+}
+
+// addRuleCosts sets the cost estimate of each rule of the export if it was requested.
+func (srv *ProvisioningSrv) addRuleCosts(c *contextmodel.ReqContext, export *definitions.AlertingFileExport, groups []alerting_models.AlertRuleGroupWithFolderTitle) {
+	if !c.QueryBoolWithDefault(queryIncludeCost, false) {
+		return
+	}
+	addRuleCosts(export, groups, newRuleCostEstimator(c.Req.Context(), c.SignedInUser, srv.log, srv.datasourceCache, srv.stateManager, func() time.Duration {
+		if srv.cfg == nil {
+			return 0
+		}
+		return inheritedInterval(srv.log, srv.adminConfigStore, srv.cfg, c.SignedInUser.GetOrgID())
+	}))
+}
+
 func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	if _, disabled := ctx.Req.Header[disableProvenanceHeaderName]; disabled {
 		return definitions.Provenance(alerting_models.ProvenanceNone)
@@ -539,25 +809,45 @@ func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryPar
 	return params
 }
 
+// exportResponse encodes the export in the requested format. It is compressed if the client accepts it.
 func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExport) response.Response {
+	return compressedResponse(encodeExport(c, body))
+}
+
+func encodeExport(c *contextmodel.ReqContext, body definitions.AlertingFileExport) response.Response {
 	params := extractExportRequest(c)
+	fields, err := parseFieldSelection(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	if params.Format == "hcl" {
+		if fields != nil {
+			return ErrResp(http.StatusBadRequest, errors.New("fields cannot be selected in the hcl format"), "")
+		}
 		return exportHcl(params.Download, body)
 	}
 
+	var result any = body
+	if fields != nil {
+		result, err = selectFields(body, fields, params.Format)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to select the fields of the export")
+		}
+	}
+
 	if params.Download {
 		r := response.JSONDownload
 		if params.Format == "yaml" {
 			r = response.YAMLDownload
 		}
-		return r(http.StatusOK, body, fmt.Sprintf("export.%s", params.Format))
+		return r(http.StatusOK, result, fmt.Sprintf("export.%s", params.Format))
 	}
 
 	r := response.JSON
 	if params.Format == "yaml" {
 		r = response.YAML
 	}
-	return r(http.StatusOK, body)
+	return r(http.StatusOK, result)
 }
 
 func exportHcl(download bool, body definitions.AlertingFileExport) response.Response {
@@ -620,3 +910,16 @@ func exportHcl(download bool, body definitions.AlertingFileExport) response.Resp
 	}
 	return resp.SetHeader("Content-Type", "text/hcl")
 }
+
+// parseExportPublicKey parses the base64 encoding of a PEM encoded RSA public key.
+func parseExportPublicKey(value string) (*rsa.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		// the padding is often lost in query strings
+		data, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+		if err != nil {
+			return nil, errors.New("public key must be base64 encoded")
+		}
+	}
+	return provisioning.ParseExportPublicKey(data)
+}
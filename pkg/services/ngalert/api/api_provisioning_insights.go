@@ -0,0 +1,146 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// RouteGetAlertRulesInsights returns how the alert rules of the organization are used, the least recently used first,
+// so that the unused ones can be found and cleaned up.
+func (srv *ProvisioningSrv) RouteGetAlertRulesInsights(c *contextmodel.ReqContext) response.Response {
+	cutoff, err := unusedForCutoff(c, time.Now())
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	orgID := c.SignedInUser.GetOrgID()
+	rules, _, err := srv.alertRules.GetAlertRules(c.Req.Context(), orgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+	insights, err := srv.usageInsights.GetUsageInsights(c.Req.Context(), orgID, alerting_models.UsageInsightKindAlertRule)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the usage of alert rules")
+	}
+
+	result := make(definitions.AlertRuleUsageInsights, 0, len(rules))
+	for _, rule := range rules {
+		usage := insights[rule.UID]
+		if usage == nil {
+			usage = &alerting_models.UsageInsight{}
+		}
+		modified := rule.Updated
+		if cutoff != nil && !unusedSince(usage, &modified, *cutoff) {
+			continue
+		}
+		result = append(result, definitions.AlertRuleUsageInsight{
+			UID:           rule.UID,
+			Title:         rule.Title,
+			FolderUID:     rule.NamespaceUID,
+			RuleGroup:     rule.RuleGroup,
+			LastModified:  rule.Updated,
+			LastFired:     usage.LastFired,
+			LastNotified:  usage.LastNotified,
+			Notifications: usage.Notifications,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return leastRecentlyUsed(
+			alerting_models.UsageInsight{LastFired: result[i].LastFired, LastNotified: result[i].LastNotified}, result[i].UID,
+			alerting_models.UsageInsight{LastFired: result[j].LastFired, LastNotified: result[j].LastNotified}, result[j].UID,
+		)
+	})
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetContactPointsInsights returns how the contact points of the organization are used, the least recently used
+// first, so that the unused ones can be found and cleaned up.
+func (srv *ProvisioningSrv) RouteGetContactPointsInsights(c *contextmodel.ReqContext) response.Response {
+	cutoff, err := unusedForCutoff(c, time.Now())
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	orgID := c.SignedInUser.GetOrgID()
+	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), provisioning.ContactPointQuery{OrgID: orgID}, nil)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get contact points")
+	}
+	insights, err := srv.usageInsights.GetUsageInsights(c.Req.Context(), orgID, alerting_models.UsageInsightKindContactPoint)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the usage of contact points")
+	}
+
+	result := make(definitions.ContactPointUsageInsights, 0, len(cps))
+	for _, cp := range cps {
+		usage := insights[cp.UID]
+		if usage == nil {
+			usage = &alerting_models.UsageInsight{}
+		}
+		if cutoff != nil && !unusedSince(usage, usage.LastModified, *cutoff) {
+			continue
+		}
+		result = append(result, definitions.ContactPointUsageInsight{
+			UID:           cp.UID,
+			Name:          cp.Name,
+			Type:          cp.Type,
+			LastModified:  usage.LastModified,
+			LastNotified:  usage.LastNotified,
+			Notifications: usage.Notifications,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return leastRecentlyUsed(
+			alerting_models.UsageInsight{LastNotified: result[i].LastNotified}, result[i].UID,
+			alerting_models.UsageInsight{LastNotified: result[j].LastNotified}, result[j].UID,
+		)
+	})
+	return response.JSON(http.StatusOK, result)
+}
+
+// unusedForCutoff returns the time before which the objects must have been last used and modified to be returned,
+// or nil if the request does not filter the unused objects.
+func unusedForCutoff(c *contextmodel.ReqContext, now time.Time) (*time.Time, error) {
+	unusedFor := c.Query("unusedFor")
+	if unusedFor == "" {
+		return nil, nil
+	}
+	d, err := prommodel.ParseDuration(unusedFor)
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("unusedFor must be a positive duration, such as 30d: %q", unusedFor)
+	}
+	cutoff := now.Add(-time.Duration(d))
+	return &cutoff, nil
+}
+
+// unusedSince returns true if the object was neither used nor modified after the cutoff.
+// An object whose modification is unknown is considered to be modified before the cutoff.
+func unusedSince(usage *alerting_models.UsageInsight, modified *time.Time, cutoff time.Time) bool {
+	if lastUsed := usage.LastUsed(); lastUsed != nil && lastUsed.After(cutoff) {
+		return false
+	}
+	return modified == nil || !modified.After(cutoff)
+}
+
+// leastRecentlyUsed orders the objects that were never used first, then by the last time they were used, then by UID.
+func leastRecentlyUsed(a alerting_models.UsageInsight, aUID string, b alerting_models.UsageInsight, bUID string) bool {
+	aUsed, bUsed := a.LastUsed(), b.LastUsed()
+	switch {
+	case aUsed == nil && bUsed == nil:
+		return aUID < bUID
+	case aUsed == nil || bUsed == nil:
+		return aUsed == nil
+	case !aUsed.Equal(*bUsed):
+		return aUsed.Before(*bUsed)
+	default:
+		return aUID < bUID
+	}
+}
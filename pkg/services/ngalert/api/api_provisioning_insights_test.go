@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestProvisioningApiUsageInsights(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	hoursAgo := func(h int) *time.Time {
+		at := now.Add(-time.Duration(h) * time.Hour)
+		return &at
+	}
+
+	t.Run("alert rules", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		sut.usageInsights = env.store
+		insertRule(t, sut, createTestAlertRule("fired", 1))
+		insertRule(t, sut, createTestAlertRule("idle", 1))
+		require.NoError(t, env.store.AddUsageInsights(context.Background(), []models.UsageInsight{
+			{OrgID: 1, Kind: models.UsageInsightKindAlertRule, UID: "fired", LastFired: hoursAgo(1), LastNotified: hoursAgo(2), Notifications: 3},
+		}))
+
+		t.Run("should return the usage of the rules, the least recently used first", func(t *testing.T) {
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRulesInsights(&rc)
+
+			require.Equal(t, http.StatusOK, response.Status())
+			var insights definitions.AlertRuleUsageInsights
+			require.NoError(t, json.Unmarshal(response.Body(), &insights))
+			require.Len(t, insights, 2)
+			require.Equal(t, "idle", insights[0].UID)
+			require.Nil(t, insights[0].LastFired)
+			require.Zero(t, insights[0].Notifications)
+			require.Equal(t, "fired", insights[1].UID)
+			require.Equal(t, "my-cool-group", insights[1].RuleGroup)
+			require.False(t, insights[1].LastModified.IsZero())
+			require.Equal(t, hoursAgo(1).Unix(), insights[1].LastFired.Unix())
+			require.Equal(t, hoursAgo(2).Unix(), insights[1].LastNotified.Unix())
+			require.Equal(t, int64(3), insights[1].Notifications)
+		})
+
+		t.Run("should not return recently modified rules as unused", func(t *testing.T) {
+			rc := createTestRequestCtx()
+			rc.Req.Form.Set("unusedFor", "1h")
+
+			response := sut.RouteGetAlertRulesInsights(&rc)
+
+			require.Equal(t, http.StatusOK, response.Status())
+			var insights definitions.AlertRuleUsageInsights
+			require.NoError(t, json.Unmarshal(response.Body(), &insights))
+			require.Empty(t, insights)
+		})
+
+		t.Run("should reject an invalid duration", func(t *testing.T) {
+			rc := createTestRequestCtx()
+			rc.Req.Form.Set("unusedFor", "forever")
+
+			response := sut.RouteGetAlertRulesInsights(&rc)
+
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+	})
+
+	t.Run("contact points", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		sut.usageInsights = env.store
+		require.NoError(t, env.store.AddUsageInsights(context.Background(), []models.UsageInsight{
+			{OrgID: 1, Kind: models.UsageInsightKindContactPoint, UID: "email-uid", LastNotified: hoursAgo(2), Notifications: 1},
+		}))
+		// the usage is added to the stored usage
+		require.NoError(t, env.store.AddUsageInsights(context.Background(), []models.UsageInsight{
+			{OrgID: 1, Kind: models.UsageInsightKindContactPoint, UID: "email-uid", LastNotified: hoursAgo(3), Notifications: 4},
+		}))
+		getInsights := func(t *testing.T, unusedFor string) definitions.ContactPointUsageInsights {
+			t.Helper()
+			rc := createTestRequestCtx()
+			rc.Req.Form.Set("unusedFor", unusedFor)
+			response := sut.RouteGetContactPointsInsights(&rc)
+			require.Equal(t, http.StatusOK, response.Status())
+			var insights definitions.ContactPointUsageInsights
+			require.NoError(t, json.Unmarshal(response.Body(), &insights))
+			return insights
+		}
+
+		t.Run("should return the usage of the contact points", func(t *testing.T) {
+			var found bool
+			for _, insight := range getInsights(t, "") {
+				if insight.UID != "email-uid" {
+					continue
+				}
+				found = true
+				require.Equal(t, "email receiver", insight.Name)
+				require.Equal(t, hoursAgo(2).Unix(), insight.LastNotified.Unix())
+				require.Equal(t, int64(5), insight.Notifications)
+				require.Nil(t, insight.LastModified)
+			}
+			require.True(t, found)
+		})
+
+		t.Run("should only return the contact points unused for the duration", func(t *testing.T) {
+			uids := func(insights definitions.ContactPointUsageInsights) []string {
+				result := make([]string, 0, len(insights))
+				for _, i := range insights {
+					result = append(result, i.UID)
+				}
+				return result
+			}
+			require.Contains(t, uids(getInsights(t, "1h")), "email-uid")
+			require.NotContains(t, uids(getInsights(t, "3h")), "email-uid")
+		})
+	})
+}
@@ -182,6 +182,27 @@ func TestProvisioningApi(t *testing.T) {
 
 			require.Equal(t, 404, response.Status())
 		})
+
+		t.Run("GET returns the health of contact points", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			health := &definitions.ContactPointHealth{Status: definitions.ContactPointHealthFailing, Failed: 1, LastError: "invalid token"}
+			sut.contactPointHealth = fakeContactPointHealthReporter{"email-uid": health}
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetContactPoints(&rc)
+
+			require.Equal(t, 200, response.Status())
+			var cps []definitions.EmbeddedContactPoint
+			require.NoError(t, json.Unmarshal(response.Body(), &cps))
+			require.NotEmpty(t, cps)
+			for _, cp := range cps {
+				if cp.UID == "email-uid" {
+					require.Equal(t, health, cp.Health)
+				} else {
+					require.Nil(t, cp.Health)
+				}
+			}
+		})
 	})
 
 	t.Run("templates", func(t *testing.T) {
@@ -198,6 +219,48 @@ func TestProvisioningApi(t *testing.T) {
 				require.Contains(t, string(response.Body()), "template must have content")
 			})
 		})
+
+		t.Run("are versioned", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutTemplate(&rc, definitions.NotificationTemplateContent{Template: "first"}, "versioned")
+			require.Equal(t, 202, response.Status())
+			response = sut.RoutePutTemplate(&rc, definitions.NotificationTemplateContent{Template: "second"}, "versioned")
+			require.Equal(t, 202, response.Status())
+			// saving the same content again does not add a version
+			response = sut.RoutePutTemplate(&rc, definitions.NotificationTemplateContent{Template: "second"}, "versioned")
+			require.Equal(t, 202, response.Status())
+
+			response = sut.RouteGetTemplateVersions(&rc, "versioned")
+			require.Equal(t, 200, response.Status())
+			var versions definitions.NotificationTemplateVersions
+			require.NoError(t, json.Unmarshal(response.Body(), &versions))
+			require.Len(t, versions, 2)
+			require.Equal(t, int64(2), versions[0].Version)
+			require.Equal(t, "second", versions[0].Template)
+
+			response = sut.RouteGetTemplateVersionDiff(&rc, "versioned", "1")
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), "-first")
+			require.Contains(t, string(response.Body()), "+second")
+
+			response = sut.RoutePostTemplateVersionRollback(&rc, "versioned", "1")
+			require.Equal(t, 202, response.Status())
+			response = sut.RouteGetTemplate(&rc, "versioned")
+			require.Contains(t, string(response.Body()), `"template":"first"`)
+
+			response = sut.RouteGetTemplateVersions(&rc, "versioned")
+			require.NoError(t, json.Unmarshal(response.Body(), &versions))
+			require.Len(t, versions, 3)
+
+			response = sut.RoutePostTemplateVersionRollback(&rc, "versioned", "10")
+			require.Equal(t, 404, response.Status())
+			response = sut.RoutePostTemplateVersionRollback(&rc, "versioned", "latest")
+			require.Equal(t, 400, response.Status())
+			response = sut.RouteGetTemplateVersions(&rc, "does not exist")
+			require.Equal(t, 404, response.Status())
+		})
 	})
 
 	t.Run("mute timings", func(t *testing.T) {
@@ -569,6 +632,32 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, expectedResponse, string(response.Body()))
 			})
 
+			t.Run("query param fields, GET returns the selected fields", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule1", 1))
+				insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				rc.Context.Req.Form.Set("fields", "apiVersion,groups.name,groups.rules.uid")
+				expectedResponse := `{"apiVersion":1,"groups":[{"name":"my-cool-group","rules":[{"uid":"rule1"},{"uid":"rule2"}]}]}`
+
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+				require.JSONEq(t, expectedResponse, string(response.Body()))
+
+				t.Run("and format is hcl, GET returns 400", func(t *testing.T) {
+					rc := createTestRequestCtx()
+					rc.Context.Req.Form.Set("format", "hcl")
+					rc.Context.Req.Form.Set("fields", "groups.name")
+
+					response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+					require.Equal(t, 400, response.Status())
+				})
+			})
+
 			t.Run("hcl body content is as expected", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
 				rule1 := createTestAlertRule("rule1", 1)
@@ -590,6 +679,7 @@ func TestProvisioningApi(t *testing.T) {
   interval_seconds = 60
 
   rule {
+    uid       = "rule1"
     name      = "rule1"
     condition = "A"
 
@@ -617,6 +707,7 @@ func TestProvisioningApi(t *testing.T) {
     is_paused = false
   }
   rule {
+    uid       = "rule2"
     name      = "rule2"
     condition = "A"
 
@@ -1363,12 +1454,12 @@ func TestProvisioningApiContactPointExport(t *testing.T) {
 			require.Equal(t, "", rc.Context.Resp.Header().Get("Content-Disposition"))
 		})
 
-		t.Run("decrypt true without alert.provisioning.secrets:read permissions returns 403", func(t *testing.T) {
+		t.Run("decrypt true without alert.notifications.receivers.secrets:read permissions returns 403", func(t *testing.T) {
 			recPermCheck := false
 			env := createTestEnv(t, testConfig)
 			env.ac = &recordingAccessControlFake{
 				Callback: func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
-					if strings.Contains(evaluator.String(), accesscontrol.ActionAlertingProvisioningReadSecrets) {
+					if strings.Contains(evaluator.String(), accesscontrol.ActionAlertingReceiversReadSecrets) {
 						recPermCheck = true
 					}
 					return false, nil
@@ -1391,7 +1482,7 @@ func TestProvisioningApiContactPointExport(t *testing.T) {
 			env := createTestEnv(t, testConfig)
 			env.ac = &recordingAccessControlFake{
 				Callback: func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
-					if strings.Contains(evaluator.String(), accesscontrol.ActionAlertingProvisioningReadSecrets) {
+					if strings.Contains(evaluator.String(), accesscontrol.ActionAlertingReceiversReadSecrets) {
 						recPermCheck = true
 					}
 					return true, nil
@@ -1627,7 +1718,8 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, env.prov, env.xact, receiverSvc, env.log),
 		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.xact, env.log),
-		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.dashboardService, env.quotas, env.xact, 60, 10, env.log),
+		alertRules:          provisioning.NewAlertRuleService(env.store, nil, env.prov, env.dashboardService, env.quotas, env.xact, 60, 10, env.log),
+		templateVersions:    env.store,
 	}
 }
 
@@ -1647,6 +1739,12 @@ func createTestRequestCtx() contextmodel.ReqContext {
 	}
 }
 
+type fakeContactPointHealthReporter map[string]*definitions.ContactPointHealth
+
+func (f fakeContactPointHealthReporter) GetContactPointHealth(_ int64, uid string) *definitions.ContactPointHealth {
+	return f[uid]
+}
+
 type fakeNotificationPolicyService struct {
 	tree definitions.Route
 	prov models.Provenance
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+func (srv ConfigSrv) RouteGetAlertRuleQuotaDistribution(c *contextmodel.ReqContext) response.Response {
+	distribution, err := srv.getAlertRuleQuotaDistribution(c.Req.Context())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get the alert rule quota", err)
+	}
+	return response.JSON(http.StatusOK, distribution)
+}
+
+// RoutePostAlertRuleQuotaRebalance overrides the alert rule quota of every organization so that, together,
+// the organizations cannot have more rules than the limit.
+func (srv ConfigSrv) RoutePostAlertRuleQuotaRebalance(c *contextmodel.ReqContext, body apimodels.PostableAlertRuleQuotaRebalance) response.Response {
+	distribution, err := srv.getAlertRuleQuotaDistribution(c.Req.Context())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get the alert rule quota", err)
+	}
+
+	limit := body.Limit
+	switch {
+	case limit < 0:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("the limit must be positive"), "")
+	case limit == 0 && distribution.Limit < 0:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("the global alert rule quota is unlimited, the limit to distribute must be set"), "")
+	case limit == 0:
+		limit = distribution.Limit
+	case distribution.Limit >= 0 && limit > distribution.Limit:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("the limit %d exceeds the global alert rule quota of %d", limit, distribution.Limit), "")
+	}
+
+	orgs, err := distributeAlertRuleQuota(limit, body.Strategy, distribution.Orgs)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	distribution.Orgs = orgs
+
+	if body.DryRun {
+		return response.JSON(http.StatusOK, distribution)
+	}
+
+	for _, org := range orgs {
+		err := srv.quotaService.Update(c.Req.Context(), &quota.UpdateQuotaCmd{
+			Target: string(ngmodels.QuotaTarget),
+			Limit:  org.Limit,
+			OrgID:  org.OrgID,
+		})
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, fmt.Sprintf("failed to update the alert rule quota of organization %d", org.OrgID), err)
+		}
+	}
+	srv.log.Info("Rebalanced the alert rule quota of the organizations", "limit", limit, "strategy", body.Strategy, "orgs", len(orgs))
+	return response.JSON(http.StatusOK, distribution)
+}
+
+// getAlertRuleQuotaDistribution returns the global alert rule quota, and the quota of every organization.
+func (srv ConfigSrv) getAlertRuleQuotaDistribution(ctx context.Context) (apimodels.AlertRuleQuotaDistribution, error) {
+	global, err := srv.getAlertRuleQuota(ctx, quota.GlobalScope, 0)
+	if err != nil {
+		return apimodels.AlertRuleQuotaDistribution{}, err
+	}
+
+	orgIDs, err := srv.orgStore.GetOrgs(ctx)
+	if err != nil {
+		return apimodels.AlertRuleQuotaDistribution{}, err
+	}
+	sort.Slice(orgIDs, func(i, j int) bool {
+		return orgIDs[i] < orgIDs[j]
+	})
+
+	result := apimodels.AlertRuleQuotaDistribution{
+		Limit: global.Limit,
+		Used:  global.Used,
+		Orgs:  make([]apimodels.OrgAlertRuleQuota, 0, len(orgIDs)),
+	}
+	for _, orgID := range orgIDs {
+		q, err := srv.getAlertRuleQuota(ctx, quota.OrgScope, orgID)
+		if err != nil {
+			return apimodels.AlertRuleQuotaDistribution{}, err
+		}
+		result.Orgs = append(result.Orgs, apimodels.OrgAlertRuleQuota{
+			OrgID: orgID,
+			Limit: q.Limit,
+			Used:  q.Used,
+		})
+	}
+	return result, nil
+}
+
+func (srv ConfigSrv) getAlertRuleQuota(ctx context.Context, scope quota.Scope, id int64) (quota.QuotaDTO, error) {
+	quotas, err := srv.quotaService.GetQuotasByScope(ctx, scope, id)
+	if err != nil {
+		return quota.QuotaDTO{}, err
+	}
+	for _, q := range quotas {
+		if q.Service == string(ngmodels.QuotaTargetSrv) && q.Target == string(ngmodels.QuotaTarget) {
+			return q, nil
+		}
+	}
+	return quota.QuotaDTO{}, fmt.Errorf("the alert rule quota of the %s scope is not registered", scope)
+}
+
+// distributeAlertRuleQuota splits the limit across the organizations according to the strategy.
+// The remainder of the split is given to the organizations with the lowest IDs.
+func distributeAlertRuleQuota(limit int64, strategy apimodels.AlertRuleQuotaRebalanceStrategy, orgs []apimodels.OrgAlertRuleQuota) ([]apimodels.OrgAlertRuleQuota, error) {
+	result := make([]apimodels.OrgAlertRuleQuota, len(orgs))
+	copy(result, orgs)
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	// the part of the limit that is split evenly, on top of the base of every organization
+	toSplit := limit
+	base := make([]int64, len(result))
+	switch strategy {
+	case "", apimodels.AlertRuleQuotaRebalanceEven:
+	case apimodels.AlertRuleQuotaRebalanceUsage:
+		for i, org := range result {
+			base[i] = org.Used
+			toSplit -= org.Used
+		}
+		if toSplit < 0 {
+			return nil, fmt.Errorf("the organizations have %d alert rules, more than the limit of %d", limit-toSplit, limit)
+		}
+	default:
+		return nil, fmt.Errorf("unknown strategy %q, must be one of %s, %s", strategy, apimodels.AlertRuleQuotaRebalanceEven, apimodels.AlertRuleQuotaRebalanceUsage)
+	}
+
+	share, remainder := toSplit/int64(len(result)), toSplit%int64(len(result))
+	for i := range result {
+		result[i].Limit = base[i] + share
+		if int64(i) < remainder {
+			result[i].Limit++
+		}
+	}
+	return result, nil
+}
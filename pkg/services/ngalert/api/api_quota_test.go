@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+)
+
+type fakeAlertRuleQuotas struct {
+	*quotatest.FakeQuotaService
+	limits map[int64]int64
+	used   map[int64]int64
+}
+
+func (f *fakeAlertRuleQuotas) GetQuotasByScope(_ context.Context, scope quota.Scope, id int64) ([]quota.QuotaDTO, error) {
+	return []quota.QuotaDTO{{
+		OrgId:   id,
+		Target:  string(ngmodels.QuotaTarget),
+		Service: string(ngmodels.QuotaTargetSrv),
+		Scope:   string(scope),
+		Limit:   f.limits[id],
+		Used:    f.used[id],
+	}}, nil
+}
+
+func (f *fakeAlertRuleQuotas) Update(_ context.Context, cmd *quota.UpdateQuotaCmd) error {
+	f.limits[cmd.OrgID] = cmd.Limit
+	return nil
+}
+
+type fakeOrgStore []int64
+
+func (f fakeOrgStore) GetOrgs(context.Context) ([]int64, error) {
+	return f, nil
+}
+
+func TestAlertRuleQuotaRoutes(t *testing.T) {
+	newService := func() (ConfigSrv, *fakeAlertRuleQuotas) {
+		quotas := &fakeAlertRuleQuotas{
+			FakeQuotaService: quotatest.New(false, nil),
+			// the global quota is stored with the ID 0
+			limits: map[int64]int64{0: 100, 1: 100, 2: 100, 3: 100},
+			used:   map[int64]int64{0: 33, 1: 30, 2: 3, 3: 0},
+		}
+		return ConfigSrv{orgStore: fakeOrgStore{3, 1, 2}, quotaService: quotas, log: log.NewNopLogger()}, quotas
+	}
+
+	readDistribution := func(t *testing.T, body []byte) definitions.AlertRuleQuotaDistribution {
+		t.Helper()
+		result := definitions.AlertRuleQuotaDistribution{}
+		require.NoError(t, json.Unmarshal(body, &result))
+		return result
+	}
+
+	t.Run("should return the quota of all organizations", func(t *testing.T) {
+		srv, _ := newService()
+		resp := srv.RouteGetAlertRuleQuotaDistribution(createRequestCtxInOrg(1))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, definitions.AlertRuleQuotaDistribution{
+			Limit: 100,
+			Used:  33,
+			Orgs: []definitions.OrgAlertRuleQuota{
+				{OrgID: 1, Limit: 100, Used: 30},
+				{OrgID: 2, Limit: 100, Used: 3},
+				{OrgID: 3, Limit: 100, Used: 0},
+			},
+		}, readDistribution(t, resp.Body()))
+	})
+
+	t.Run("should split the global quota evenly", func(t *testing.T) {
+		srv, quotas := newService()
+		resp := srv.RoutePostAlertRuleQuotaRebalance(createRequestCtxInOrg(1), definitions.PostableAlertRuleQuotaRebalance{})
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, map[int64]int64{0: 100, 1: 34, 2: 33, 3: 33}, quotas.limits)
+	})
+
+	t.Run("should keep the usage of the organizations", func(t *testing.T) {
+		srv, quotas := newService()
+		resp := srv.RoutePostAlertRuleQuotaRebalance(createRequestCtxInOrg(1), definitions.PostableAlertRuleQuotaRebalance{
+			Limit:    60,
+			Strategy: definitions.AlertRuleQuotaRebalanceUsage,
+		})
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, map[int64]int64{0: 100, 1: 39, 2: 12, 3: 9}, quotas.limits)
+	})
+
+	t.Run("should not change the quotas in dry run", func(t *testing.T) {
+		srv, quotas := newService()
+		resp := srv.RoutePostAlertRuleQuotaRebalance(createRequestCtxInOrg(1), definitions.PostableAlertRuleQuotaRebalance{DryRun: true})
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, int64(34), readDistribution(t, resp.Body()).Orgs[0].Limit)
+		require.Equal(t, int64(100), quotas.limits[1])
+	})
+
+	t.Run("should reject invalid limits", func(t *testing.T) {
+		srv, quotas := newService()
+		for _, body := range []definitions.PostableAlertRuleQuotaRebalance{
+			{Limit: -1},
+			{Limit: 101},
+			{Limit: 30, Strategy: definitions.AlertRuleQuotaRebalanceUsage},
+			{Strategy: "random"},
+		} {
+			resp := srv.RoutePostAlertRuleQuotaRebalance(createRequestCtxInOrg(1), body)
+			require.Equal(t, http.StatusBadRequest, resp.Status())
+		}
+		require.Equal(t, int64(100), quotas.limits[1])
+
+		quotas.limits[0] = -1
+		resp := srv.RoutePostAlertRuleQuotaRebalance(createRequestCtxInOrg(1), definitions.PostableAlertRuleQuotaRebalance{})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
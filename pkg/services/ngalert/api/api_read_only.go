@@ -0,0 +1,43 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// maxReadOnlyReasonLength is the maximum length of the reason of the read-only mode, which is returned in every rejected response.
+const maxReadOnlyReasonLength = 500
+
+func (srv ConfigSrv) RouteGetReadOnlyMode(c *contextmodel.ReqContext) response.Response {
+	mode, err := srv.readOnly.Get(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the read-only mode of the organization")
+	}
+	return response.JSON(http.StatusOK, mode)
+}
+
+func (srv ConfigSrv) RoutePutReadOnlyMode(c *contextmodel.ReqContext, body apimodels.ReadOnlyMode) response.Response {
+	if len(body.Reason) > maxReadOnlyReasonLength {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("the reason cannot be longer than %d characters", maxReadOnlyReasonLength), "")
+	}
+
+	mode := apimodels.ReadOnlyMode{
+		Enabled:   body.Enabled,
+		Reason:    body.Reason,
+		Updated:   time.Now().UTC(),
+		UpdatedBy: c.SignedInUser.GetLogin(),
+	}
+	if !mode.Enabled {
+		mode = apimodels.ReadOnlyMode{}
+	}
+	if err := srv.readOnly.Set(c.Req.Context(), c.SignedInUser.GetOrgID(), mode); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to update the read-only mode of the organization")
+	}
+	srv.log.Info("Changed the read-only mode of the organization", "org_id", c.SignedInUser.GetOrgID(), "enabled", mode.Enabled, "reason", mode.Reason, "user", c.SignedInUser.GetLogin())
+	return response.JSON(http.StatusOK, mode)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,11 +17,14 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
@@ -42,6 +46,10 @@ type RulerSrv struct {
 	cfg                *setting.UnifiedAlertingSettings
 	conditionValidator ConditionValidator
 	authz              RuleAccessControlService
+	adminConfigStore   store.AdminConfigurationStore
+	labelPolicyStore   store.LabelPolicyStore
+	datasourceCache    datasources.CacheService
+	stateManager       state.AlertInstanceManager
 }
 
 var (
@@ -160,9 +168,10 @@ func (srv RulerSrv) RouteGetNamespaceRulesConfig(c *contextmodel.ReqContext, nam
 	}
 
 	result := apimodels.NamespaceConfigResponse{}
+	inheritedInterval := srv.inheritedInterval(c.SignedInUser.GetOrgID())
 
 	for groupKey, rules := range ruleGroups {
-		result[namespace.Fullpath] = append(result[namespace.Fullpath], toGettableRuleGroupConfig(groupKey.RuleGroup, rules, provenanceRecords))
+		result[namespace.Fullpath] = append(result[namespace.Fullpath], toGettableRuleGroupConfig(groupKey.RuleGroup, rules, provenanceRecords, inheritedInterval))
 	}
 
 	return response.JSON(http.StatusAccepted, result)
@@ -192,7 +201,7 @@ func (srv RulerSrv) RouteGetRulesGroupConfig(c *contextmodel.ReqContext, namespa
 
 	result := apimodels.RuleGroupConfigResponse{
 		// nolint:staticcheck
-		GettableRuleGroupConfig: toGettableRuleGroupConfig(ruleGroup, rules, provenanceRecords),
+		GettableRuleGroupConfig: toGettableRuleGroupConfig(ruleGroup, rules, provenanceRecords, srv.inheritedInterval(c.SignedInUser.GetOrgID())),
 	}
 	return response.JSON(http.StatusAccepted, result)
 }
@@ -207,7 +216,7 @@ func (srv RulerSrv) RouteGetRulesConfig(c *contextmodel.ReqContext) response.Res
 
 	if len(namespaceMap) == 0 {
 		srv.log.Debug("User has no access to any namespaces")
-		return response.JSON(http.StatusOK, result)
+		return selectedFieldsJSON(c, http.StatusOK, result)
 	}
 
 	namespaceUIDs := make([]string, len(namespaceMap))
@@ -224,6 +233,16 @@ func (srv RulerSrv) RouteGetRulesConfig(c *contextmodel.ReqContext) response.Res
 		return ErrResp(http.StatusBadRequest, errors.New("panel_id must be set with dashboard_uid"), "")
 	}
 
+	limit := c.QueryInt64WithDefault("limit", -1)
+	after, err := decodeContinueToken(c.Query("continue"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	matchers, err := getMatchersFromRequest(c.Req, "matcher")
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
 	configs, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), c, namespaceUIDs, dashboardUID, panelID)
 	if err != nil {
 		return errorToResponse(err)
@@ -233,16 +252,26 @@ func (srv RulerSrv) RouteGetRulesConfig(c *contextmodel.ReqContext) response.Res
 		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
 	}
 
-	for groupKey, rules := range configs {
-		folder, ok := namespaceMap[groupKey.NamespaceUID]
-		if !ok {
+	// the groups of folders that are not visible to the user are not counted in the pages
+	for groupKey := range configs {
+		if _, ok := namespaceMap[groupKey.NamespaceUID]; !ok {
 			userNamespace, id := c.SignedInUser.GetNamespacedID()
 			srv.log.Error("Namespace not visible to the user", "user", id, "userNamespace", userNamespace, "namespace", groupKey.NamespaceUID)
-			continue
+			delete(configs, groupKey)
 		}
-		result[folder.Fullpath] = append(result[folder.Fullpath], toGettableRuleGroupConfig(groupKey.RuleGroup, rules, provenanceRecords))
 	}
-	return response.JSON(http.StatusOK, result)
+	filterRuleGroupsByLabels(configs, matchers)
+	page, next := paginateRuleGroups(configs, limit, after)
+
+	inheritedInterval := srv.inheritedInterval(c.SignedInUser.GetOrgID())
+	for _, groupKey := range page {
+		folder := namespaceMap[groupKey.NamespaceUID]
+		result[folder.Fullpath] = append(result[folder.Fullpath], toGettableRuleGroupConfig(groupKey.RuleGroup, configs[groupKey], provenanceRecords, inheritedInterval))
+	}
+	if next != "" {
+		c.Resp.Header().Set(continueTokenHeader, next)
+	}
+	return selectedFieldsJSON(c, http.StatusOK, result)
 }
 
 func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGroupConfig apimodels.PostableRuleGroupConfig, namespaceUID string) response.Response {
@@ -251,9 +280,9 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGro
 		return toNamespaceErrorResponse(err)
 	}
 
-	rules, err := validateRuleGroup(&ruleGroupConfig, c.SignedInUser.GetOrgID(), namespace, srv.cfg)
-	if err != nil {
-		return ErrResp(http.StatusBadRequest, err, "")
+	rules, resp := srv.validateRuleGroupRequest(c, &ruleGroupConfig, namespace)
+	if resp != nil {
+		return resp
 	}
 
 	groupKey := ngmodels.AlertRuleGroupKey{
@@ -265,121 +294,568 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGro
 	return srv.updateAlertRulesInGroup(c, groupKey, rules)
 }
 
-// updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
-// All operations are performed in a single transaction
-func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
-	var finalChanges *store.GroupDelta
+// RoutePostRulesImport creates or updates the rule groups of multiple folders. The payload maps the UID of a folder to its rule groups.
+// All groups are validated before anything is changed, and all changes are performed in a single transaction.
+func (srv RulerSrv) RoutePostRulesImport(c *contextmodel.ReqContext, body apimodels.PostableRulesImport) response.Response {
+	if len(body) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("no rule groups to import"), "")
+	}
+
+	namespaceUIDs := make([]string, 0, len(body))
+	for namespaceUID := range body {
+		namespaceUIDs = append(namespaceUIDs, namespaceUID)
+	}
+	sort.Strings(namespaceUIDs)
+
+	type groupToImport struct {
+		key   ngmodels.AlertRuleGroupKey
+		rules []*ngmodels.AlertRuleWithOptionals
+	}
+	groups := make([]groupToImport, 0, len(body))
+	for _, namespaceUID := range namespaceUIDs {
+		namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+
+		names := make(map[string]struct{}, len(body[namespaceUID]))
+		for i := range body[namespaceUID] {
+			ruleGroupConfig := &body[namespaceUID][i]
+			if _, ok := names[ruleGroupConfig.Name]; ok {
+				return ErrResp(http.StatusBadRequest, fmt.Errorf("rule group %q is defined more than once in folder %s", ruleGroupConfig.Name, namespaceUID), "")
+			}
+			names[ruleGroupConfig.Name] = struct{}{}
+
+			rules, resp := srv.validateRuleGroupRequest(c, ruleGroupConfig, namespace)
+			if resp != nil {
+				return resp
+			}
+			groups = append(groups, groupToImport{
+				key: ngmodels.AlertRuleGroupKey{
+					OrgID:        c.SignedInUser.GetOrgID(),
+					NamespaceUID: namespace.UID,
+					RuleGroup:    ruleGroupConfig.Name,
+				},
+				rules: rules,
+			})
+		}
+	}
+
+	finalChanges := make([]*store.GroupDelta, 0, len(groups))
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
-		userNamespace, id := c.SignedInUser.GetNamespacedID()
-		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
-			groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
-		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
+		for _, group := range groups {
+			changes, err := srv.applyRuleGroupChanges(tranCtx, c, group.key, group.rules)
+			if err != nil {
+				return fmt.Errorf("failed to import rule group %q of folder %s: %w", group.key.RuleGroup, group.key.NamespaceUID, err)
+			}
+			finalChanges = append(finalChanges, changes)
+		}
+		return nil
+	})
+	if err != nil {
+		return ruleGroupUpdateErrorResponse(err)
+	}
+
+	resp := apimodels.UpdateRuleGroupResponse{
+		Message: "rule groups imported successfully",
+		Created: []string{},
+		Updated: []string{},
+		Deleted: []string{},
+	}
+	empty := true
+	for _, changes := range finalChanges {
+		if changes.IsEmpty() {
+			continue
+		}
+		empty = false
+		groupResp := changesToUpdateRuleGroupResponse(changes)
+		resp.Created = append(resp.Created, groupResp.Created...)
+		resp.Updated = append(resp.Updated, groupResp.Updated...)
+		resp.Deleted = append(resp.Deleted, groupResp.Deleted...)
+	}
+	if empty {
+		resp.Message = "no changes detected in the rule groups"
+	}
+	return response.JSON(http.StatusAccepted, resp)
+}
+
+// errRuleGroupExists is returned when a rule group is renamed to the name of another rule group of the folder.
+var errRuleGroupExists = errors.New("rule group already exists")
+
+// RoutePostRenameRuleGroup renames the rule group by moving its rules to a group with the new name. Unlike deleting
+// the group and creating it again, the rules keep their UIDs, so that their state and history are preserved.
+func (srv RulerSrv) RoutePostRenameRuleGroup(c *contextmodel.ReqContext, body apimodels.PostableRuleGroupRename, namespaceUID string, groupName string) response.Response {
+	newName := strings.TrimSpace(body.Name)
+	switch {
+	case newName == "":
+		return ErrResp(http.StatusBadRequest, errors.New("the new name of the rule group must not be empty"), "")
+	case len(newName) > store.AlertRuleMaxRuleGroupNameLength:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("rule group name is too long. Max length is %d", store.AlertRuleMaxRuleGroupNameLength), "")
+	case newName == groupName:
+		return ErrResp(http.StatusBadRequest, errors.New("the new name of the rule group must be different from the current name"), "")
+	}
+
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	groupKey := ngmodels.AlertRuleGroupKey{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		NamespaceUID: namespace.UID,
+		RuleGroup:    groupName,
+	}
+	newGroupKey := groupKey
+	newGroupKey.RuleGroup = newName
+
+	var finalChanges *store.GroupDelta
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		rules, err := srv.getAuthorizedRuleGroup(tranCtx, c, groupKey)
 		if err != nil {
 			return err
 		}
-
-		if groupChanges.IsEmpty() {
-			finalChanges = groupChanges
-			logger.Info("No changes detected in the request. Do nothing")
-			return nil
+		if len(rules) == 0 {
+			return fmt.Errorf("%w: %s", store.ErrAlertRuleGroupNotFound, groupName)
 		}
 
-		err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+		existing, err := srv.store.ListAlertRules(tranCtx, &ngmodels.ListAlertRulesQuery{
+			OrgID:         newGroupKey.OrgID,
+			NamespaceUIDs: []string{newGroupKey.NamespaceUID},
+			RuleGroup:     newGroupKey.RuleGroup,
+		})
 		if err != nil {
 			return err
 		}
+		if len(existing) > 0 {
+			return fmt.Errorf("%w: %s", errRuleGroupExists, newName)
+		}
+
+		renamed := make([]*ngmodels.AlertRuleWithOptionals, 0, len(rules))
+		for _, rule := range rules {
+			r := ngmodels.CopyRule(rule)
+			r.RuleGroup = newName
+			renamed = append(renamed, &ngmodels.AlertRuleWithOptionals{AlertRule: *r, HasPause: true})
+		}
+		finalChanges, err = srv.applyRuleGroupChanges(tranCtx, c, newGroupKey, renamed)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		} else if errors.Is(err, errRuleGroupExists) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ruleGroupUpdateErrorResponse(err)
+	}
+
+	resp := changesToUpdateRuleGroupResponse(finalChanges)
+	resp.Message = "rule group renamed successfully"
+	return response.JSON(http.StatusAccepted, resp)
+}
+
+// RoutePostCloneRuleGroup copies the rule group into a folder, for example to promote a rule group from a staging
+// folder to a production folder. The copies are created in one transaction, get new UIDs and are not provisioned.
+func (srv RulerSrv) RoutePostCloneRuleGroup(c *contextmodel.ReqContext, body apimodels.PostableRuleGroupClone, namespaceUID string, groupName string) response.Response {
+	newName := strings.TrimSpace(body.Name)
+	if newName == "" {
+		newName = groupName
+	}
+	switch {
+	case body.FolderUID == "":
+		return ErrResp(http.StatusBadRequest, errors.New("the UID of the folder to copy the rule group into must not be empty"), "")
+	case len(newName) > store.AlertRuleMaxRuleGroupNameLength:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("rule group name is too long. Max length is %d", store.AlertRuleMaxRuleGroupNameLength), "")
+	case body.FolderUID == namespaceUID && body.TitlePrefix == "":
+		return ErrResp(http.StatusBadRequest, errors.New("a title prefix is required to copy the rule group into its own folder"), "")
+	}
+
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+	target, err := srv.store.GetNamespaceByUID(c.Req.Context(), body.FolderUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
 
-		if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
+	groupKey := ngmodels.AlertRuleGroupKey{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		NamespaceUID: namespace.UID,
+		RuleGroup:    groupName,
+	}
+	newGroupKey := ngmodels.AlertRuleGroupKey{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		NamespaceUID: target.UID,
+		RuleGroup:    newName,
+	}
+
+	var finalChanges *store.GroupDelta
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		rules, err := srv.getAuthorizedRuleGroup(tranCtx, c, groupKey)
+		if err != nil {
 			return err
 		}
+		if len(rules) == 0 {
+			return fmt.Errorf("%w: %s", store.ErrAlertRuleGroupNotFound, groupName)
+		}
 
-		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
+		existing, err := srv.store.ListAlertRules(tranCtx, &ngmodels.ListAlertRulesQuery{
+			OrgID:         newGroupKey.OrgID,
+			NamespaceUIDs: []string{newGroupKey.NamespaceUID},
+			RuleGroup:     newGroupKey.RuleGroup,
+		})
+		if err != nil {
 			return err
 		}
+		if len(existing) > 0 {
+			return fmt.Errorf("%w: %s", errRuleGroupExists, newName)
+		}
 
-		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
-		logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+		copies := make([]*ngmodels.AlertRuleWithOptionals, 0, len(rules))
+		for _, rule := range rules {
+			r := ngmodels.CopyRule(rule)
+			r.ID = 0
+			r.UID = ""
+			r.Version = 0
+			r.NamespaceUID = newGroupKey.NamespaceUID
+			r.RuleGroup = newGroupKey.RuleGroup
+			r.Title = body.TitlePrefix + r.Title
+			copies = append(copies, &ngmodels.AlertRuleWithOptionals{AlertRule: *r, HasPause: true})
+		}
+		finalChanges, err = srv.applyRuleGroupChanges(tranCtx, c, newGroupKey, copies)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		} else if errors.Is(err, errRuleGroupExists) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ruleGroupUpdateErrorResponse(err)
+	}
 
-		// Delete first as this could prevent future unique constraint violations.
-		if len(finalChanges.Delete) > 0 {
-			UIDs := make([]string, 0, len(finalChanges.Delete))
-			for _, rule := range finalChanges.Delete {
-				UIDs = append(UIDs, rule.UID)
-			}
+	resp := changesToUpdateRuleGroupResponse(finalChanges)
+	resp.Message = "rule group copied successfully"
+	return response.JSON(http.StatusAccepted, resp)
+}
 
-			if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
-				return fmt.Errorf("failed to delete rules: %w", err)
-			}
-		}
+// RoutePostPauseRules pauses or resumes the rules with the UIDs in the body, or the rules of a rule group, for example
+// to silence a whole service during maintenance. The changes to all groups are authorized before any rule is updated,
+// and the rules are updated in one transaction. Pausing does not change the number of rules, so the quota is not checked.
+func (srv RulerSrv) RoutePostPauseRules(c *contextmodel.ReqContext, body apimodels.PostableRulesPause) response.Response {
+	byUID := len(body.RuleUIDs) > 0
+	switch {
+	case byUID && (body.FolderUID != "" || body.RuleGroup != ""):
+		return ErrResp(http.StatusBadRequest, errors.New("either the UIDs of the rules or a folder and a rule group must be set, not both"), "")
+	case !byUID && (body.FolderUID == "" || body.RuleGroup == ""):
+		return ErrResp(http.StatusBadRequest, errors.New("either the UIDs of the rules or a folder and a rule group must be set"), "")
+	}
 
-		if len(finalChanges.Update) > 0 {
-			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
-			for _, update := range finalChanges.Update {
-				logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
-				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
-				})
+	orgID := c.SignedInUser.GetOrgID()
+	userNamespace, id := c.SignedInUser.GetNamespacedID()
+	updatedBy := fmt.Sprintf("%s:%s", userNamespace, id)
+	finalChanges := &store.GroupDelta{}
+	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		// the changes are authorized against all rules of the affected groups, so the groups are fetched as a whole
+		groups := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+		var rules []*ngmodels.AlertRule
+		if byUID {
+			found := make(map[string]*ngmodels.AlertRule)
+			selected := make(map[string]struct{}, len(body.RuleUIDs))
+			for _, uid := range body.RuleUIDs {
+				if _, ok := selected[uid]; ok {
+					continue
+				}
+				selected[uid] = struct{}{}
+				if _, ok := found[uid]; !ok {
+					group, err := srv.store.GetAlertRulesGroupByRuleUID(tranCtx, &ngmodels.GetAlertRulesGroupByRuleUIDQuery{UID: uid, OrgID: orgID})
+					if err != nil {
+						return err
+					}
+					if len(group) == 0 {
+						return fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleNotFound, uid)
+					}
+					groups[group[0].GetGroupKey()] = group
+					for _, rule := range group {
+						found[rule.UID] = rule
+					}
+				}
+				rules = append(rules, found[uid])
 			}
-			err = srv.store.UpdateAlertRules(tranCtx, updates)
+		} else {
+			groupKey := ngmodels.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: body.FolderUID, RuleGroup: body.RuleGroup}
+			group, err := srv.store.ListAlertRules(tranCtx, &ngmodels.ListAlertRulesQuery{
+				OrgID:         orgID,
+				NamespaceUIDs: []string{groupKey.NamespaceUID},
+				RuleGroup:     groupKey.RuleGroup,
+			})
 			if err != nil {
-				return fmt.Errorf("failed to update rules: %w", err)
+				return err
 			}
+			if len(group) == 0 {
+				return fmt.Errorf("%w: %s", store.ErrAlertRuleGroupNotFound, body.RuleGroup)
+			}
+			groups[groupKey] = group
+			rules = group
 		}
 
-		if len(finalChanges.New) > 0 {
-			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
-			for _, rule := range finalChanges.New {
-				inserts = append(inserts, *rule)
-			}
-			added, err := srv.store.InsertAlertRules(tranCtx, inserts)
-			if err != nil {
-				return fmt.Errorf("failed to add rules: %w", err)
+		// the deltas are kept in the order of the rules, so that the changes are applied in the order of the request
+		deltas := make([]*store.GroupDelta, 0, len(groups))
+		deltaByGroup := make(map[ngmodels.AlertRuleGroupKey]*store.GroupDelta, len(groups))
+		for _, rule := range rules {
+			if rule.IsPaused == body.IsPaused {
+				continue
 			}
-			if len(added) != len(finalChanges.New) {
-				logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
-			} else {
-				for i, newRule := range finalChanges.New {
-					newRule.ID = added[i].ID
-					newRule.UID = added[i].UID
+			key := rule.GetGroupKey()
+			delta, ok := deltaByGroup[key]
+			if !ok {
+				delta = &store.GroupDelta{
+					GroupKey:       key,
+					AffectedGroups: map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup{key: groups[key]},
 				}
+				deltaByGroup[key] = delta
+				deltas = append(deltas, delta)
 			}
+			updated := ngmodels.CopyRule(rule)
+			updated.IsPaused = body.IsPaused
+			updated.UpdatedBy = updatedBy
+			delta.Update = append(delta.Update, store.RuleDelta{
+				Existing: rule,
+				New:      updated,
+				Diff:     rule.Diff(updated, store.AlertRuleFieldsToIgnoreInDiff[:]...),
+			})
 		}
 
-		if len(finalChanges.New) > 0 {
-			userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
-			limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
-				OrgID:  c.SignedInUser.GetOrgID(),
-				UserID: userID,
-			}) // alert rule is table name
-			if err != nil {
-				return fmt.Errorf("failed to get alert rules quota: %w", err)
+		for _, delta := range deltas {
+			if err := srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, delta); err != nil {
+				return err
 			}
-			if limitReached {
-				return ngmodels.ErrQuotaReached
+			if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, orgID, delta); err != nil {
+				return err
 			}
+			finalChanges.Update = append(finalChanges.Update, delta.Update...)
+		}
+		if finalChanges.IsEmpty() {
+			return nil
+		}
+
+		updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
+		for _, update := range finalChanges.Update {
+			updates = append(updates, ngmodels.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
+		}
+		if err := srv.store.UpdateAlertRules(tranCtx, updates); err != nil {
+			return fmt.Errorf("failed to update rules: %w", err)
 		}
 		return nil
 	})
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ruleGroupUpdateErrorResponse(err)
+	}
 
+	resp := changesToUpdateRuleGroupResponse(finalChanges)
+	switch {
+	case finalChanges.IsEmpty():
+		resp.Message = "no changes detected in the rules"
+	case body.IsPaused:
+		resp.Message = "rules paused successfully"
+	default:
+		resp.Message = "rules resumed successfully"
+	}
+	return response.JSON(http.StatusAccepted, resp)
+}
+
+// validateRuleGroupRequest converts the rule group to the models and validates it against the configuration of the organization.
+// It returns an error response if the group is not valid.
+func (srv RulerSrv) validateRuleGroupRequest(c *contextmodel.ReqContext, ruleGroupConfig *apimodels.PostableRuleGroupConfig, namespace *folder.Folder) ([]*ngmodels.AlertRuleWithOptionals, response.Response) {
+	rules, err := validateRuleGroup(ruleGroupConfig, c.SignedInUser.GetOrgID(), namespace, srv.cfg)
 	if err != nil {
-		if errors.As(err, &errutil.Error{}) {
-			return response.Err(err)
-		} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-			return ErrResp(http.StatusNotFound, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
-			return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
-		} else if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		return nil, ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	adminConfig, err := srv.getAdminConfiguration(c.SignedInUser.GetOrgID())
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get the admin configuration of the organization")
+	}
+
+	if !ruleGroupConfig.InheritInterval && len(rules) > 0 {
+		if interval := rules[0].IntervalSeconds; !adminConfig.IsEvaluationIntervalAllowed(interval) {
+			return nil, ErrResp(http.StatusBadRequest, fmt.Errorf("rule evaluation interval %s is not allowed in the organization, allowed intervals are %s", time.Duration(interval)*time.Second, formatIntervals(adminConfig.AllowedEvaluationIntervalsSeconds)), "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
+	}
+
+	toValidate := make([]*ngmodels.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		toValidate = append(toValidate, &rule.AlertRule)
+	}
+	existing, err := srv.store.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		NamespaceUIDs: []string{namespace.UID},
+		RuleGroup:     ruleGroupConfig.Name,
+	})
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get the rules of the group")
+	}
+	if resp := validateAnnotationSchema(adminConfig, rulesWithChangedAnnotations(existing, toValidate)...); resp != nil {
+		return nil, resp
+	}
+
+	if srv.labelPolicyStore != nil {
+		policies, err := srv.labelPolicyStore.GetLabelPolicies(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err != nil {
+			return nil, ErrResp(http.StatusInternalServerError, err, "failed to get the label policies of the organization")
+		}
+		if resp := validateLabelPolicies(policies, toValidate...); resp != nil {
+			return nil, resp
+		}
+	}
+	return rules, nil
+}
+
+// updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
+// All operations are performed in a single transaction
+func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
+	var finalChanges *store.GroupDelta
+	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		var err error
+		finalChanges, err = srv.applyRuleGroupChanges(tranCtx, c, groupKey, rules)
+		return err
+	})
+	if err != nil {
+		return ruleGroupUpdateErrorResponse(err)
 	}
 	return changesToResponse(finalChanges)
 }
 
+// applyRuleGroupChanges calculates the changes of the group, verifies that the user is authorized to do them and updates the database.
+// It must be called within a transaction.
+func (srv RulerSrv) applyRuleGroupChanges(tranCtx context.Context, c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) (*store.GroupDelta, error) {
+	userNamespace, id := c.SignedInUser.GetNamespacedID()
+	logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
+		groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
+	groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupChanges.IsEmpty() {
+		logger.Info("No changes detected in the request. Do nothing")
+		return groupChanges, nil
+	}
+
+	err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
+		return nil, err
+	}
+
+	if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
+		return nil, err
+	}
+
+	finalChanges := store.UpdateCalculatedRuleFields(groupChanges)
+	updatedBy := fmt.Sprintf("%s:%s", userNamespace, id)
+	for _, rule := range finalChanges.New {
+		rule.UpdatedBy = updatedBy
+	}
+	for _, update := range finalChanges.Update {
+		if len(update.Diff) > 0 {
+			update.New.UpdatedBy = updatedBy
+		}
+	}
+	logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+
+	// Delete first as this could prevent future unique constraint violations.
+	if len(finalChanges.Delete) > 0 {
+		UIDs := make([]string, 0, len(finalChanges.Delete))
+		for _, rule := range finalChanges.Delete {
+			UIDs = append(UIDs, rule.UID)
+		}
+
+		if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
+			return nil, fmt.Errorf("failed to delete rules: %w", err)
+		}
+	}
+
+	if len(finalChanges.Update) > 0 {
+		updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
+		for _, update := range finalChanges.Update {
+			logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+			updates = append(updates, ngmodels.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
+		}
+		err = srv.store.UpdateAlertRules(tranCtx, updates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update rules: %w", err)
+		}
+	}
+
+	if len(finalChanges.New) > 0 {
+		inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
+		for _, rule := range finalChanges.New {
+			inserts = append(inserts, *rule)
+		}
+		added, err := srv.store.InsertAlertRules(tranCtx, inserts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add rules: %w", err)
+		}
+		if len(added) != len(finalChanges.New) {
+			logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
+		} else {
+			for i, newRule := range finalChanges.New {
+				newRule.ID = added[i].ID
+				newRule.UID = added[i].UID
+			}
+		}
+	}
+
+	if len(finalChanges.New) > 0 {
+		userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+		limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
+			OrgID:  c.SignedInUser.GetOrgID(),
+			UserID: userID,
+		}) // alert rule is table name
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alert rules quota: %w", err)
+		}
+		if limitReached {
+			return nil, ngmodels.ErrQuotaReached
+		}
+	}
+	return finalChanges, nil
+}
+
+// ruleGroupUpdateErrorResponse converts the error of an update of rule groups to a response
+func ruleGroupUpdateErrorResponse(err error) response.Response {
+	if errors.As(err, &errutil.Error{}) {
+		return response.Err(err)
+	} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+		return ErrResp(http.StatusNotFound, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
+		return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	} else if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
+}
+
 func changesToResponse(finalChanges *store.GroupDelta) response.Response {
+	return response.JSON(http.StatusAccepted, changesToUpdateRuleGroupResponse(finalChanges))
+}
+
+func changesToUpdateRuleGroupResponse(finalChanges *store.GroupDelta) apimodels.UpdateRuleGroupResponse {
 	body := apimodels.UpdateRuleGroupResponse{
 		Message: "rule group updated successfully",
 		Created: make([]string, 0, len(finalChanges.New)),
@@ -399,23 +875,50 @@ func changesToResponse(finalChanges *store.GroupDelta) response.Response {
 			body.Deleted = append(body.Deleted, r.UID)
 		}
 	}
-	return response.JSON(http.StatusAccepted, body)
+	return body
+}
+
+// getAdminConfiguration returns the admin configuration of the organization, or nil if the organization has none.
+func (srv RulerSrv) getAdminConfiguration(orgID int64) (*ngmodels.AdminConfiguration, error) {
+	return store.FindAdminConfiguration(srv.adminConfigStore, orgID)
 }
 
-func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, provenanceRecords map[string]ngmodels.Provenance) apimodels.GettableRuleGroupConfig {
+// inheritedInterval returns the evaluation interval of rule groups of the organization that inherit its interval.
+func (srv RulerSrv) inheritedInterval(orgID int64) time.Duration {
+	return inheritedInterval(srv.log, srv.adminConfigStore, srv.cfg, orgID)
+}
+
+// inheritedInterval returns the evaluation interval of rule groups of the organization that inherit its interval.
+func inheritedInterval(logger log.Logger, adminConfigStore store.AdminConfigurationStore, cfg *setting.UnifiedAlertingSettings, orgID int64) time.Duration {
+	return time.Duration(store.InheritedIntervalSeconds(logger, adminConfigStore, orgID, int64(cfg.DefaultRuleEvaluationInterval.Seconds()))) * time.Second
+}
+
+func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, provenanceRecords map[string]ngmodels.Provenance, inheritedInterval time.Duration) apimodels.GettableRuleGroupConfig {
 	rules.SortByGroupIndex()
 	ruleNodes := make([]apimodels.GettableExtendedRuleNode, 0, len(rules))
 	var interval time.Duration
+	var inherit bool
+	var labels map[string]string
 	if len(rules) > 0 {
+		labels = rules[0].GroupLabels
 		interval = time.Duration(rules[0].IntervalSeconds) * time.Second
+		if inherit = rules[0].InheritsInterval(); inherit {
+			interval = inheritedInterval
+		}
 	}
 	for _, r := range rules {
-		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, provenanceRecords))
+		node := toGettableExtendedRuleNode(*r, provenanceRecords)
+		if inherit {
+			node.GrafanaManagedAlert.IntervalSeconds = int64(interval.Seconds())
+		}
+		ruleNodes = append(ruleNodes, node)
 	}
 	return apimodels.GettableRuleGroupConfig{
-		Name:     groupName,
-		Interval: model.Duration(interval),
-		Rules:    ruleNodes,
+		Name:            groupName,
+		Interval:        model.Duration(interval),
+		Labels:          labels,
+		Rules:           ruleNodes,
+		InheritInterval: inherit,
 	}
 }
 
@@ -426,21 +929,26 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 	}
 	gettableExtendedRuleNode := apimodels.GettableExtendedRuleNode{
 		GrafanaManagedAlert: &apimodels.GettableGrafanaRule{
-			ID:              r.ID,
-			OrgID:           r.OrgID,
-			Title:           r.Title,
-			Condition:       r.Condition,
-			Data:            ApiAlertQueriesFromAlertQueries(r.Data),
-			Updated:         r.Updated,
-			IntervalSeconds: r.IntervalSeconds,
-			Version:         r.Version,
-			UID:             r.UID,
-			NamespaceUID:    r.NamespaceUID,
-			RuleGroup:       r.RuleGroup,
-			NoDataState:     apimodels.NoDataState(r.NoDataState),
-			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
-			Provenance:      apimodels.Provenance(provenance),
-			IsPaused:        r.IsPaused,
+			ID:                r.ID,
+			OrgID:             r.OrgID,
+			Title:             r.Title,
+			Condition:         r.Condition,
+			Data:              ApiAlertQueriesFromAlertQueries(r.Data),
+			Updated:           r.Updated,
+			IntervalSeconds:   r.IntervalSeconds,
+			Version:           r.Version,
+			UID:               r.UID,
+			NamespaceUID:      r.NamespaceUID,
+			RuleGroup:         r.RuleGroup,
+			NoDataState:       apimodels.NoDataState(r.NoDataState),
+			ExecErrState:      apimodels.ExecutionErrorState(r.ExecErrState),
+			Provenance:        apimodels.Provenance(provenance),
+			IsPaused:          r.IsPaused,
+			FingerprintLabels: r.FingerprintLabels,
+			PartialResults:    r.PartialResults,
+
+			MissingSeriesEvalsToResolve:    r.MissingSeriesEvalsToResolve,
+			MissingSeriesDurationToResolve: model.Duration(r.MissingSeriesDurationToResolve),
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -0,0 +1,416 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/expr"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	datadogDefaultGroup    = "Datadog"
+	datadogDefaultInterval = time.Minute
+	// datadogPlaceholderExpr is the query of the rules whose Datadog query could not be translated.
+	datadogPlaceholderExpr   = "vector(0)"
+	datadogPlaceholderWindow = 10 * time.Minute
+
+	// datadogMonitorIDAnnotation is the ID of the monitor that the rule was converted from.
+	datadogMonitorIDAnnotation = "datadog_monitor_id"
+	// datadogTodoAnnotation explains what is left to do to complete the conversion of the rule.
+	datadogTodoAnnotation = "TODO"
+
+	datadogQueryRefID     = "A"
+	datadogConditionRefID = "B"
+)
+
+var (
+	// datadogMetricQuery matches the queries of metric monitors, like avg(last_5m):avg:system.cpu.user{env:prod} by {host} > 90
+	datadogMetricQuery = regexp.MustCompile(`^\s*(\w+)\(last_(\d+[smhdw])\):(.+?)\s*(>=|<=|==|>|<)\s*(-?[0-9.]+(?:e[+-]?[0-9]+)?)\s*$`)
+	// datadogSeries matches a single metric aggregated across space, without functions or arithmetic.
+	datadogSeries = regexp.MustCompile(`^(avg|sum|min|max):([\w.]+)\{([^}]*)\}(?:\s+by\s+\{([^}]*)\})?$`)
+
+	datadogConditionalBlock = regexp.MustCompile(`\{\{\s*([#^])\s*(\w+)[^}]*\}\}`)
+	datadogTemplateVariable = regexp.MustCompile(`\{\{\s*([\w.]+)\s*\}\}`)
+	datadogMention          = regexp.MustCompile(`(^|\s)@[\w.+@-]+`)
+
+	promInvalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// datadogTimeAggregations maps the time aggregations of Datadog to the PromQL functions over a range vector.
+var datadogTimeAggregations = map[string]string{
+	"avg":  "avg_over_time",
+	"sum":  "sum_over_time",
+	"min":  "min_over_time",
+	"max":  "max_over_time",
+	"last": "last_over_time",
+}
+
+// RouteConvertDatadogMonitors converts Datadog monitors to a rule group of Grafana-managed alert rules that query a
+// Prometheus data source. The rule group is returned to be reviewed, not saved.
+func (srv RulerSrv) RouteConvertDatadogMonitors(c *contextmodel.ReqContext, monitors apimodels.DatadogMonitors) response.Response {
+	if len(monitors) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("no monitors to convert"), "")
+	}
+
+	dsUID := c.Query("datasourceUid")
+	if dsUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("the datasourceUid parameter is required"), "")
+	}
+	ds, err := srv.datasourceCache.GetDatasourceByUID(c.Req.Context(), dsUID, c.SignedInUser, false)
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the data source")
+	}
+	if ds.Type != datasources.DS_PROMETHEUS {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("data source %s is of type %s, only Prometheus data sources are supported", ds.UID, ds.Type), "")
+	}
+
+	group := c.Query("group")
+	if group == "" {
+		group = datadogDefaultGroup
+	}
+	interval := datadogDefaultInterval
+	if s := c.Query("interval"); s != "" {
+		d, err := model.ParseDuration(s)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid interval: %w", err), "")
+		}
+		interval = time.Duration(d)
+	}
+	if _, err := validateInterval(srv.cfg, interval); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	return response.JSON(http.StatusOK, convertDatadogMonitors(monitors, ds.UID, group, interval))
+}
+
+// convertDatadogMonitors converts the monitors to alert rules of a single group. The rules of the monitors whose
+// query cannot be translated are paused and query a placeholder, with a TODO annotation.
+func convertDatadogMonitors(monitors []apimodels.DatadogMonitor, datasourceUID, group string, interval time.Duration) apimodels.DatadogMonitorsConversion {
+	result := apimodels.DatadogMonitorsConversion{
+		Group: apimodels.PostableRuleGroupConfig{
+			Name:     group,
+			Interval: model.Duration(interval),
+			Rules:    make([]apimodels.PostableExtendedRuleNode, 0, len(monitors)),
+		},
+		Monitors: make([]apimodels.DatadogMonitorConversion, 0, len(monitors)),
+	}
+	titles := make(map[string]struct{}, len(monitors))
+	for _, monitor := range monitors {
+		rule, report := convertDatadogMonitor(monitor, datasourceUID)
+		// titles must be unique in a folder
+		if _, ok := titles[rule.GrafanaManagedAlert.Title]; ok {
+			rule.GrafanaManagedAlert.Title = fmt.Sprintf("%s (%d)", rule.GrafanaManagedAlert.Title, monitor.ID)
+		}
+		titles[rule.GrafanaManagedAlert.Title] = struct{}{}
+		report.Title = rule.GrafanaManagedAlert.Title
+
+		result.Group.Rules = append(result.Group.Rules, rule)
+		result.Monitors = append(result.Monitors, report)
+	}
+	return result
+}
+
+func convertDatadogMonitor(monitor apimodels.DatadogMonitor, datasourceUID string) (apimodels.PostableExtendedRuleNode, apimodels.DatadogMonitorConversion) {
+	report := apimodels.DatadogMonitorConversion{ID: monitor.ID, Translated: true}
+	annotations := map[string]string{
+		datadogMonitorIDAnnotation: strconv.FormatInt(monitor.ID, 10),
+	}
+
+	q, err := translateDatadogQuery(monitor.Type, monitor.Query)
+	if err != nil {
+		report.Translated = false
+		annotations[datadogTodoAnnotation] = fmt.Sprintf("Translate the Datadog query %q to PromQL: %s", monitor.Query, err)
+		q = datadogQuery{expr: datadogPlaceholderExpr, window: datadogPlaceholderWindow, op: ">", threshold: 0}
+	}
+	if t := monitor.Options.Thresholds; t != nil && t.Warning != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("the warning threshold %v is not converted, create another rule for it", *t.Warning))
+	}
+
+	message, warnings := convertDatadogMessage(monitor.Message, q.threshold)
+	report.Warnings = append(report.Warnings, warnings...)
+	if message != "" {
+		annotations["description"] = message
+	}
+
+	labels := make(map[string]string, len(monitor.Tags)+1)
+	for _, tag := range monitor.Tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok || value == "" {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("the tag %q does not have a value and is not converted to a label", tag))
+			continue
+		}
+		labels[promLabelName(key)] = value
+	}
+	if monitor.Priority != nil {
+		labels["priority"] = fmt.Sprintf("P%d", *monitor.Priority)
+	}
+
+	title := strings.TrimSpace(monitor.Name)
+	if title == "" {
+		title = fmt.Sprintf("Datadog monitor %d", monitor.ID)
+	}
+	noDataState := apimodels.OK
+	if monitor.Options.NotifyNoData {
+		noDataState = apimodels.NoData
+	}
+	delay := time.Duration(monitor.Options.EvaluationDelay) * time.Second
+
+	rule := apimodels.PostableExtendedRuleNode{
+		ApiRuleNode: &apimodels.ApiRuleNode{
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+			Title:     title,
+			Condition: datadogConditionRefID,
+			Data: []apimodels.AlertQuery{
+				{
+					RefID:             datadogQueryRefID,
+					DatasourceUID:     datasourceUID,
+					RelativeTimeRange: apimodels.RelativeTimeRange{From: apimodels.Duration(q.window + delay), To: apimodels.Duration(delay)},
+					Model:             datadogPrometheusModel(datasourceUID, q.expr),
+				},
+				{
+					RefID:         datadogConditionRefID,
+					DatasourceUID: expr.DatasourceUID,
+					Model:         datadogConditionModel(q.op, q.threshold),
+				},
+			},
+			NoDataState:  noDataState,
+			ExecErrState: apimodels.ErrorErrState,
+		},
+	}
+	if !report.Translated {
+		paused := true
+		rule.GrafanaManagedAlert.IsPaused = &paused
+	}
+	return rule, report
+}
+
+// datadogQuery is a Datadog query translated to PromQL.
+type datadogQuery struct {
+	expr      string
+	window    time.Duration
+	op        string
+	threshold float64
+}
+
+// translateDatadogQuery translates the queries of metric monitors that aggregate a single metric over time and space.
+func translateDatadogQuery(monitorType, query string) (datadogQuery, error) {
+	if monitorType != "metric alert" && monitorType != "query alert" {
+		return datadogQuery{}, fmt.Errorf("monitors of type %q are not supported", monitorType)
+	}
+	m := datadogMetricQuery.FindStringSubmatch(query)
+	if m == nil {
+		return datadogQuery{}, errors.New("the query is not a metric query with a threshold")
+	}
+	overTime, ok := datadogTimeAggregations[m[1]]
+	if !ok {
+		return datadogQuery{}, fmt.Errorf("the time aggregation %s is not supported", m[1])
+	}
+	window, err := model.ParseDuration(m[2])
+	if err != nil {
+		return datadogQuery{}, fmt.Errorf("invalid evaluation window: %w", err)
+	}
+	threshold, err := strconv.ParseFloat(m[5], 64)
+	if err != nil {
+		return datadogQuery{}, fmt.Errorf("invalid threshold: %w", err)
+	}
+
+	series := datadogSeries.FindStringSubmatch(strings.TrimSpace(m[3]))
+	if series == nil {
+		return datadogQuery{}, errors.New("only queries of a single metric without functions or arithmetic are supported")
+	}
+	selector, err := datadogSelector(series[2], series[3])
+	if err != nil {
+		return datadogQuery{}, err
+	}
+	promExpr := fmt.Sprintf("%s(%s[%s])", overTime, selector, m[2])
+	if by := datadogGroupBy(series[4]); len(by) > 0 {
+		promExpr = fmt.Sprintf("%s by (%s) (%s)", series[1], strings.Join(by, ", "), promExpr)
+	} else {
+		promExpr = fmt.Sprintf("%s(%s)", series[1], promExpr)
+	}
+	return datadogQuery{expr: promExpr, window: time.Duration(window), op: m[4], threshold: threshold}, nil
+}
+
+// datadogSelector translates a metric and its tag filters to a PromQL vector selector.
+func datadogSelector(metric, filters string) (string, error) {
+	matchers := make([]string, 0)
+	for _, filter := range strings.Split(filters, ",") {
+		filter = strings.TrimSpace(filter)
+		if filter == "" || filter == "*" {
+			continue
+		}
+		if strings.ContainsAny(filter, " ()") {
+			return "", fmt.Errorf("the filter %q cannot be translated to label matchers", filter)
+		}
+		negative := strings.HasPrefix(filter, "!")
+		key, value, ok := strings.Cut(strings.TrimPrefix(filter, "!"), ":")
+		if !ok {
+			return "", fmt.Errorf("the tag %q does not have a value and cannot be translated to a label matcher", filter)
+		}
+		op, regexOp := "=", "=~"
+		if negative {
+			op, regexOp = "!=", "!~"
+		}
+		if strings.Contains(value, "*") {
+			op = regexOp
+			value = strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+		}
+		matchers = append(matchers, fmt.Sprintf("%s%s%q", promLabelName(key), op, value))
+	}
+	name := promInvalidNameChars.ReplaceAllString(metric, "_")
+	if len(matchers) == 0 {
+		return name, nil
+	}
+	return fmt.Sprintf("%s{%s}", name, strings.Join(matchers, ", ")), nil
+}
+
+func datadogGroupBy(by string) []string {
+	result := make([]string, 0)
+	for _, tag := range strings.Split(by, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, promLabelName(tag))
+		}
+	}
+	return result
+}
+
+// promLabelName replaces the characters of a Datadog tag key that are not valid in Prometheus label names.
+func promLabelName(key string) string {
+	name := promInvalidNameChars.ReplaceAllString(key, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// convertDatadogMessage converts the message of a monitor to the template of the description annotation. It keeps
+// the text shown when the monitor alerts, translates the template variables of the tags and the value, and removes
+// the notification handles, which are replaced by notification policies in Grafana.
+func convertDatadogMessage(message string, threshold float64) (string, []string) {
+	var warnings []string
+	var b strings.Builder
+	for {
+		loc := datadogConditionalBlock.FindStringSubmatchIndex(message)
+		if loc == nil {
+			b.WriteString(message)
+			break
+		}
+		kind, name := message[loc[2]:loc[3]], message[loc[4]:loc[5]]
+		b.WriteString(message[:loc[0]])
+		message = message[loc[1]:]
+
+		end := regexp.MustCompile(`\{\{\s*/\s*` + regexp.QuoteMeta(name) + `\s*\}\}`).FindStringIndex(message)
+		if end == nil {
+			warnings = append(warnings, fmt.Sprintf("the conditional %s is not closed and is removed", name))
+			continue
+		}
+		content := message[:end[0]]
+		message = message[end[1]:]
+		switch {
+		case kind == "#" && name == "is_alert", kind == "^" && name != "is_alert":
+			b.WriteString(content)
+		case name == "is_match" || name == "is_exact_match":
+			warnings = append(warnings, fmt.Sprintf("the conditional %s is not converted, its content is always shown", name))
+			b.WriteString(content)
+		}
+	}
+
+	unknown := make(map[string]struct{})
+	converted := datadogTemplateVariable.ReplaceAllStringFunc(b.String(), func(s string) string {
+		variable := datadogTemplateVariable.FindStringSubmatch(s)[1]
+		switch {
+		case variable == "value":
+			return fmt.Sprintf("{{ $values.%s.Value }}", datadogQueryRefID)
+		case variable == "threshold":
+			return strconv.FormatFloat(threshold, 'f', -1, 64)
+		case strings.HasSuffix(variable, ".name"):
+			return fmt.Sprintf("{{ $labels.%s }}", promLabelName(strings.TrimSuffix(variable, ".name")))
+		}
+		unknown[variable] = struct{}{}
+		return ""
+	})
+	if len(unknown) > 0 {
+		variables := make([]string, 0, len(unknown))
+		for v := range unknown {
+			variables = append(variables, v)
+		}
+		sort.Strings(variables)
+		warnings = append(warnings, fmt.Sprintf("the template variables %s are not converted", strings.Join(variables, ", ")))
+	}
+
+	var mentions []string
+	converted = datadogMention.ReplaceAllStringFunc(converted, func(s string) string {
+		mentions = append(mentions, strings.TrimSpace(s))
+		if strings.TrimSpace(s) != s {
+			return s[:1]
+		}
+		return ""
+	})
+	if len(mentions) > 0 {
+		warnings = append(warnings, fmt.Sprintf("the notification handles %s are not converted, route the alerts with notification policies", strings.Join(mentions, ", ")))
+	}
+	return strings.TrimSpace(converted), warnings
+}
+
+func datadogPrometheusModel(datasourceUID, promExpr string) json.RawMessage {
+	b, _ := json.Marshal(map[string]any{
+		"refId":   datadogQueryRefID,
+		"expr":    promExpr,
+		"instant": true,
+		"range":   false,
+		"datasource": map[string]string{
+			"type": datasources.DS_PROMETHEUS,
+			"uid":  datasourceUID,
+		},
+	})
+	return b
+}
+
+// datadogConditionModel returns a threshold expression for the strict comparisons, and a math expression for the
+// others.
+func datadogConditionModel(op string, threshold float64) json.RawMessage {
+	m := map[string]any{
+		"refId": datadogConditionRefID,
+		"datasource": map[string]string{
+			"type": expr.DatasourceType,
+			"uid":  expr.DatasourceUID,
+		},
+	}
+	switch op {
+	case ">", "<":
+		evaluator := "gt"
+		if op == "<" {
+			evaluator = "lt"
+		}
+		m["type"] = "threshold"
+		m["expression"] = datadogQueryRefID
+		m["conditions"] = []any{map[string]any{
+			"type":      "query",
+			"evaluator": map[string]any{"type": evaluator, "params": []float64{threshold}},
+		}}
+	default:
+		m["type"] = "math"
+		m["expression"] = fmt.Sprintf("$%s %s %s", datadogQueryRefID, op, strconv.FormatFloat(threshold, 'f', -1, 64))
+	}
+	b, _ := json.Marshal(m)
+	return b
+}
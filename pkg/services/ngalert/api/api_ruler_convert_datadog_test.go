@@ -0,0 +1,216 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestTranslateDatadogQuery(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		expr      string
+		window    time.Duration
+		op        string
+		threshold float64
+		err       string
+	}{
+		{
+			name:      "metric grouped by tags",
+			query:     "avg(last_5m):avg:system.cpu.user{env:prod,service:web-*} by {host,availability-zone} > 90",
+			expr:      `avg by (host, availability_zone) (avg_over_time(system_cpu_user{env="prod", service=~"web-.*"}[5m]))`,
+			window:    5 * time.Minute,
+			op:        ">",
+			threshold: 90,
+		},
+		{
+			name:      "metric of all sources with a negative filter",
+			query:     "max(last_1h):sum:http.requests.errors{!env:staging} >= 0.5",
+			expr:      `sum(max_over_time(http_requests_errors{env!="staging"}[1h]))`,
+			window:    time.Hour,
+			op:        ">=",
+			threshold: 0.5,
+		},
+		{
+			name:      "metric without filters",
+			query:     "last(last_10m):min:disk.free{*} < 1e9",
+			expr:      `min(last_over_time(disk_free[10m]))`,
+			window:    10 * time.Minute,
+			op:        "<",
+			threshold: 1e9,
+		},
+		{
+			name:  "arithmetic",
+			query: "avg(last_5m):avg:errors{*} / avg:requests{*} > 0.1",
+			err:   "only queries of a single metric",
+		},
+		{
+			name:  "unsupported time aggregation",
+			query: "pct_change(last_5m):avg:requests{*} > 10",
+			err:   "time aggregation pct_change is not supported",
+		},
+		{
+			name:  "boolean filters",
+			query: "avg(last_5m):avg:requests{env:prod OR env:staging} > 10",
+			err:   "cannot be translated to label matchers",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := translateDatadogQuery("metric alert", tc.query)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, datadogQuery{expr: tc.expr, window: tc.window, op: tc.op, threshold: tc.threshold}, q)
+		})
+	}
+
+	t.Run("unsupported monitor type", func(t *testing.T) {
+		_, err := translateDatadogQuery("log alert", `logs("status:error").index("*").rollup("count").last("5m") > 10`)
+		require.ErrorContains(t, err, `monitors of type "log alert" are not supported`)
+	})
+}
+
+func TestConvertDatadogMessage(t *testing.T) {
+	message := "{{#is_alert}}CPU is {{value}} on {{host.name}}, above {{threshold}}.{{/is_alert}}" +
+		"{{#is_recovery}}CPU is back to normal.{{/is_recovery}} Started at {{last_triggered_at}}. @slack-ops @oncall@example.com"
+
+	converted, warnings := convertDatadogMessage(message, 90)
+	require.Equal(t, "CPU is {{ $values.A.Value }} on {{ $labels.host }}, above 90. Started at .", converted)
+	require.Equal(t, []string{
+		"the template variables last_triggered_at are not converted",
+		"the notification handles @slack-ops, @oncall@example.com are not converted, route the alerts with notification policies",
+	}, warnings)
+}
+
+func TestConvertDatadogMonitors(t *testing.T) {
+	critical, warning := 90.0, 80.0
+	priority := int64(2)
+	monitors := []apimodels.DatadogMonitor{
+		{
+			ID:       1,
+			Name:     "High CPU",
+			Type:     "metric alert",
+			Query:    "avg(last_5m):avg:system.cpu.user{env:prod} by {host} > 90",
+			Message:  "CPU is high on {{host.name}} @slack-ops",
+			Tags:     []string{"team:infra", "critical"},
+			Priority: &priority,
+			Options: apimodels.DatadogMonitorOptions{
+				Thresholds:      &apimodels.DatadogMonitorThresholds{Critical: &critical, Warning: &warning},
+				NotifyNoData:    true,
+				EvaluationDelay: 60,
+			},
+		},
+		{
+			ID:    2,
+			Name:  "High CPU",
+			Type:  "query alert",
+			Query: "avg(last_5m):anomalies(avg:system.cpu.user{*}, 'basic', 2) >= 1",
+		},
+	}
+
+	result := convertDatadogMonitors(monitors, "prom", "Datadog", time.Minute)
+	require.Equal(t, "Datadog", result.Group.Name)
+	require.Len(t, result.Group.Rules, 2)
+
+	translated := result.Group.Rules[0]
+	assert.Equal(t, "High CPU", translated.GrafanaManagedAlert.Title)
+	assert.Equal(t, "B", translated.GrafanaManagedAlert.Condition)
+	assert.Equal(t, apimodels.NoData, translated.GrafanaManagedAlert.NoDataState)
+	assert.Nil(t, translated.GrafanaManagedAlert.IsPaused)
+	assert.Equal(t, map[string]string{"team": "infra", "priority": "P2"}, translated.Labels)
+	assert.Equal(t, map[string]string{
+		datadogMonitorIDAnnotation: "1",
+		"description":              "CPU is high on {{ $labels.host }}",
+	}, translated.Annotations)
+
+	query := translated.GrafanaManagedAlert.Data[0]
+	assert.Equal(t, "prom", query.DatasourceUID)
+	assert.Equal(t, apimodels.RelativeTimeRange{From: apimodels.Duration(6 * time.Minute), To: apimodels.Duration(time.Minute)}, query.RelativeTimeRange)
+	var model map[string]any
+	require.NoError(t, json.Unmarshal(query.Model, &model))
+	assert.Equal(t, `avg by (host) (avg_over_time(system_cpu_user{env="prod"}[5m]))`, model["expr"])
+	assert.JSONEq(t, `{
+		"refId": "B",
+		"type": "threshold",
+		"expression": "A",
+		"datasource": {"type": "__expr__", "uid": "__expr__"},
+		"conditions": [{"type": "query", "evaluator": {"type": "gt", "params": [90]}}]
+	}`, string(translated.GrafanaManagedAlert.Data[1].Model))
+
+	stubbed := result.Group.Rules[1]
+	assert.Equal(t, "High CPU (2)", stubbed.GrafanaManagedAlert.Title)
+	require.NotNil(t, stubbed.GrafanaManagedAlert.IsPaused)
+	assert.True(t, *stubbed.GrafanaManagedAlert.IsPaused)
+	assert.Contains(t, stubbed.Annotations[datadogTodoAnnotation], "Translate the Datadog query")
+	require.NoError(t, json.Unmarshal(stubbed.GrafanaManagedAlert.Data[0].Model, &model))
+	assert.Equal(t, datadogPlaceholderExpr, model["expr"])
+
+	require.Equal(t, []apimodels.DatadogMonitorConversion{
+		{
+			ID:         1,
+			Title:      "High CPU",
+			Translated: true,
+			Warnings: []string{
+				"the warning threshold 80 is not converted, create another rule for it",
+				"the notification handles @slack-ops are not converted, route the alerts with notification policies",
+				`the tag "critical" does not have a value and is not converted to a label`,
+			},
+		},
+		{ID: 2, Title: "High CPU (2)"},
+	}, result.Monitors)
+}
+
+func TestRouteConvertDatadogMonitors(t *testing.T) {
+	monitors := apimodels.DatadogMonitors{{ID: 1, Name: "High CPU", Type: "metric alert", Query: "avg(last_5m):avg:system.cpu.user{*} > 90"}}
+
+	t.Run("should require a Prometheus data source", func(t *testing.T) {
+		srv := createService(fakes.NewRuleStore(t))
+		srv.datasourceCache = fakeCacheService{datasource: &datasources.DataSource{UID: "loki", Type: datasources.DS_LOKI}}
+
+		rc := createRequestContext(1, nil)
+		require.Equal(t, http.StatusBadRequest, srv.RouteConvertDatadogMonitors(rc, monitors).Status())
+
+		rc = createRequestContext(1, nil)
+		rc.Req.Form.Set("datasourceUid", "loki")
+		require.Equal(t, http.StatusBadRequest, srv.RouteConvertDatadogMonitors(rc, monitors).Status())
+	})
+
+	t.Run("should convert the monitors to a rule group", func(t *testing.T) {
+		srv := createService(fakes.NewRuleStore(t))
+		srv.datasourceCache = fakeCacheService{datasource: &datasources.DataSource{UID: "prom", Type: datasources.DS_PROMETHEUS}}
+
+		rc := createRequestContext(1, nil)
+		rc.Req.Form.Set("datasourceUid", "prom")
+		rc.Req.Form.Set("group", "migrated")
+		rc.Req.Form.Set("interval", "30s")
+		resp := srv.RouteConvertDatadogMonitors(rc, monitors)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result apimodels.DatadogMonitorsConversion
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, "migrated", result.Group.Name)
+		require.Len(t, result.Group.Rules, 1)
+		require.True(t, result.Monitors[0].Translated)
+	})
+}
+
+func TestDatadogMonitorsUnmarshalJSON(t *testing.T) {
+	var monitors apimodels.DatadogMonitors
+	require.NoError(t, json.Unmarshal([]byte(`{"id": 1, "name": "High CPU"}`), &monitors))
+	require.Equal(t, apimodels.DatadogMonitors{{ID: 1, Name: "High CPU"}}, monitors)
+
+	require.NoError(t, json.Unmarshal([]byte(`[{"id": 1}, {"id": 2}]`), &monitors))
+	require.Len(t, monitors, 2)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -34,9 +35,10 @@ func (srv RulerSrv) ExportFromPayload(c *contextmodel.ReqContext, ruleGroupConfi
 		rules = append(rules, optional.AlertRule)
 	}
 
-	groupsWithTitle := ngmodels.NewAlertRuleGroupWithFolderTitle(rules[0].GetGroupKey(), rules, namespace.Title)
+	groupsWithTitle := []ngmodels.AlertRuleGroupWithFolderTitle{ngmodels.NewAlertRuleGroupWithFolderTitle(rules[0].GetGroupKey(), rules, namespace.Title)}
+	srv.resolveInheritedIntervals(c.SignedInUser.GetOrgID(), groupsWithTitle)
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle([]ngmodels.AlertRuleGroupWithFolderTitle{groupsWithTitle})
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groupsWithTitle)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
@@ -90,17 +92,58 @@ func (srv RulerSrv) ExportRules(c *contextmodel.ReqContext) response.Response {
 	if len(groups) == 0 {
 		return response.Empty(http.StatusNotFound)
 	}
+	return srv.exportRuleGroups(c, groups)
+}
 
+// ExportRule exports the alert rule with the given UID within its group, i.e. with the folder and the settings
+// of the group. Other rules of the group are not exported.
+func (srv RulerSrv) ExportRule(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	rulesGroup, err := srv.getRuleWithFolderTitleByRuleUid(c, ruleUID)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return errorToResponse(err)
+	}
+	return srv.exportRuleGroups(c, []ngmodels.AlertRuleGroupWithFolderTitle{rulesGroup})
+}
+
+// exportRuleGroups converts the rule groups to export format and writes them in the format requested by the query parameters.
+func (srv RulerSrv) exportRuleGroups(c *contextmodel.ReqContext, groups []ngmodels.AlertRuleGroupWithFolderTitle) response.Response {
 	// sort result so the response is always stable
 	ngmodels.SortAlertRuleGroupWithFolderTitle(groups)
+	srv.resolveInheritedIntervals(c.SignedInUser.GetOrgID(), groups)
 
 	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groups)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
+	if c.QueryBoolWithDefault(queryIncludeCost, false) {
+		addRuleCosts(&e, groups, newRuleCostEstimator(c.Req.Context(), c.SignedInUser, srv.log, srv.datasourceCache, srv.stateManager, func() time.Duration {
+			return srv.inheritedInterval(c.SignedInUser.GetOrgID())
+		}))
+	}
 	return exportResponse(c, e)
 }
 
+// resolveInheritedIntervals replaces the interval of groups that inherit the evaluation interval of the organization with
+// the effective one, because the export format does not support inheritance.
+func (srv RulerSrv) resolveInheritedIntervals(orgID int64, groups []ngmodels.AlertRuleGroupWithFolderTitle) {
+	var intervalSeconds int64
+	for _, group := range groups {
+		if len(group.Rules) == 0 || !group.Rules[0].InheritsInterval() {
+			continue
+		}
+		if intervalSeconds == 0 {
+			intervalSeconds = int64(srv.inheritedInterval(orgID).Seconds())
+		}
+		group.Interval = intervalSeconds
+		for i := range group.Rules {
+			group.Rules[i].IntervalSeconds = intervalSeconds
+		}
+	}
+}
+
 // getRuleWithFolderTitleByRuleUid calls getAuthorizedRuleByUid and combines its result with folder (aka namespace) title.
 func (srv RulerSrv) getRuleWithFolderTitleByRuleUid(c *contextmodel.ReqContext, ruleUID string) (ngmodels.AlertRuleGroupWithFolderTitle, error) {
 	rule, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID)
@@ -457,3 +457,68 @@ func TestExportRules(t *testing.T) {
 		})
 	}
 }
+
+func TestExportRule(t *testing.T) {
+	orgID := int64(1)
+	f1 := randFolder()
+
+	ruleStore := fakes.NewRuleStore(t)
+	accessQuery := ngmodels.GenerateAlertQuery()
+	hasAccessKey := ngmodels.AlertRuleGroupKey{
+		OrgID:        orgID,
+		NamespaceUID: f1.UID,
+		RuleGroup:    "HAS-ACCESS",
+	}
+	hasAccess := ngmodels.GenerateAlertRules(3, ngmodels.AlertRuleGen(withGroupKey(hasAccessKey), ngmodels.WithQuery(accessQuery)))
+	ruleStore.PutRule(context.Background(), hasAccess...)
+	noAccessRule := ngmodels.AlertRuleGen(withGroupKey(ngmodels.AlertRuleGroupKey{
+		OrgID:        orgID,
+		NamespaceUID: f1.UID,
+		RuleGroup:    "NO-ACCESS",
+	}))()
+	ruleStore.PutRule(context.Background(), noAccessRule)
+	ruleStore.Folders[orgID] = []*folder2.Folder{f1}
+
+	srv := createService(ruleStore)
+
+	newRequestContext := func(params url.Values) *contextmodel.ReqContext {
+		rc := createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				datasources.ActionQuery: []string{datasources.ScopeProvider.GetResourceScopeUID(accessQuery.DatasourceUID)},
+			},
+		}, nil)
+		rc.Req.Form = params
+		return rc
+	}
+
+	t.Run("should export the rule within its group", func(t *testing.T) {
+		rc := newRequestContext(nil)
+		resp := srv.ExportRule(rc, hasAccess[1].UID)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		expected, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle([]ngmodels.AlertRuleGroupWithFolderTitle{
+			ngmodels.NewAlertRuleGroupWithFolderTitle(hasAccessKey, []ngmodels.AlertRule{*hasAccess[1]}, f1.Title),
+		})
+		require.NoError(t, err)
+		require.Equal(t, string(exportResponse(rc, expected).Body()), string(resp.Body()))
+	})
+
+	t.Run("should export the rule in the requested format", func(t *testing.T) {
+		rc := newRequestContext(url.Values{"format": []string{"hcl"}})
+		resp := srv.ExportRule(rc, hasAccess[0].UID)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		resp.WriteTo(rc)
+		require.Equal(t, []string{"text/hcl"}, rc.Resp.Header()["Content-Type"])
+	})
+
+	t.Run("should return 403 if the group of the rule is not accessible", func(t *testing.T) {
+		resp := srv.ExportRule(newRequestContext(nil), noAccessRule.UID)
+		require.Equal(t, http.StatusForbidden, resp.Status())
+	})
+
+	t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+		resp := srv.ExportRule(newRequestContext(nil), "does-not-exist")
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
@@ -9,16 +9,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
@@ -340,6 +345,56 @@ func TestRouteGetRulesConfig(t *testing.T) {
 		})
 	})
 
+	t.Run("should paginate and filter rule groups", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+
+		var expectedGroups []string
+		for _, name := range []string{"c", "a", "d", "b"} {
+			groupKey := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: folder.UID, RuleGroup: name}
+			ruleStore.PutRule(context.Background(), models.AlertRuleGen(withGroupKey(groupKey), models.WithLabels(data.Labels{"team": "alerting"}))())
+			ruleStore.PutRule(context.Background(), models.AlertRuleGen(withGroupKey(groupKey), models.WithLabels(data.Labels{"team": "other"}))())
+			expectedGroups = append(expectedGroups, name)
+		}
+		sort.Strings(expectedGroups)
+
+		getPage := func(t *testing.T, query url.Values) ([]apimodels.GettableRuleGroupConfig, string) {
+			t.Helper()
+			req := createRequestContext(orgID, nil)
+			req.Req.URL.RawQuery = query.Encode()
+			req.Req.Form = query
+			response := createService(ruleStore).RouteGetRulesConfig(req)
+			require.Equal(t, http.StatusOK, response.Status())
+			result := apimodels.NamespaceConfigResponse{}
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			return result[folder.Fullpath], req.Resp.Header().Get(continueTokenHeader)
+		}
+
+		var names []string
+		query := url.Values{"limit": {"3"}, "matcher": {`{"Type":0,"Name":"team","Value":"alerting"}`}}
+		for {
+			groups, next := getPage(t, query)
+			require.LessOrEqual(t, len(groups), 3)
+			for _, group := range groups {
+				require.Len(t, group.Rules, 1)
+				require.Equal(t, "alerting", group.Rules[0].Labels["team"])
+				names = append(names, group.Name)
+			}
+			if next == "" {
+				break
+			}
+			query.Set("continue", next)
+		}
+		require.Equal(t, expectedGroups, names)
+
+		req := createRequestContext(orgID, nil)
+		req.Req.Form = url.Values{"continue": {"invalid"}}
+		response := createService(ruleStore).RouteGetRulesConfig(req)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
 	t.Run("should return rules in group sorted by group index", func(t *testing.T) {
 		orgID := rand.Int63()
 		folder := randFolder()
@@ -671,3 +726,325 @@ func withGroupKey(groupKey models.AlertRuleGroupKey) func(rule *models.AlertRule
 		rule.NamespaceUID = groupKey.NamespaceUID
 	}
 }
+
+func TestRoutePostRulesImport(t *testing.T) {
+	orgID := rand.Int63()
+	folder1 := randFolder()
+	folder2 := randFolder()
+
+	newService := func() (*RulerSrv, *fakes.RuleStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder1, folder2)
+		return createService(ruleStore), ruleStore
+	}
+
+	t.Run("should reject empty payload", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RoutePostRulesImport(createRequestContext(orgID, nil), apimodels.PostableRulesImport{})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should reject groups defined more than once in a folder", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostRulesImport(createRequestContext(orgID, nil), apimodels.PostableRulesImport{
+			folder1.UID: {
+				{Name: "group"},
+				{Name: "group"},
+			},
+		})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Contains(t, string(response.Body()), "is defined more than once")
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			_, ok := cmd.(fakes.GenericRecordedQuery)
+			return cmd, !ok
+		}))
+	})
+
+	t.Run("should not change anything if a group of any folder is invalid", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostRulesImport(createRequestContext(orgID, nil), apimodels.PostableRulesImport{
+			folder1.UID: {{Name: "group"}},
+			folder2.UID: {{Name: ""}},
+		})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			_, ok := cmd.(fakes.GenericRecordedQuery)
+			return cmd, !ok
+		}))
+	})
+
+	t.Run("should import empty groups of all folders", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RoutePostRulesImport(createRequestContext(orgID, nil), apimodels.PostableRulesImport{
+			folder1.UID: {{Name: "group"}},
+			folder2.UID: {{Name: "group"}, {Name: "other"}},
+		})
+		require.Equal(t, http.StatusAccepted, response.Status())
+		result := apimodels.UpdateRuleGroupResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, "no changes detected in the rule groups", result.Message)
+	})
+}
+
+func TestRoutePostRenameRuleGroup(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	rules := models.GenerateAlertRules(3, models.AlertRuleGen(withGroupKey(groupKey), models.WithUniqueGroupIndex()))
+
+	newService := func() (*RulerSrv, *fakes.RuleStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		ruleStore.PutRule(context.Background(), rules...)
+		srv := createService(ruleStore)
+		srv.conditionValidator = &recordingConditionValidator{}
+		return srv, ruleStore
+	}
+	newRequest := func() *contextmodel.ReqContext {
+		return createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				datasources.ActionQuery:     {datasources.ScopeAll},
+				ac.ActionAlertingRuleUpdate: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)},
+			},
+		}, nil)
+	}
+
+	t.Run("should reject invalid names", func(t *testing.T) {
+		srv, _ := newService()
+		for _, name := range []string{"", " ", groupKey.RuleGroup, strings.Repeat("a", store.AlertRuleMaxRuleGroupNameLength+1)} {
+			response := srv.RoutePostRenameRuleGroup(newRequest(), apimodels.PostableRuleGroupRename{Name: name}, folder.UID, groupKey.RuleGroup)
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		}
+	})
+
+	t.Run("should return 404 if the group does not exist", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RoutePostRenameRuleGroup(newRequest(), apimodels.PostableRuleGroupRename{Name: "new-group"}, folder.UID, "missing")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 409 if the folder has a group with the new name", func(t *testing.T) {
+		srv, ruleStore := newService()
+		otherKey := groupKey
+		otherKey.RuleGroup = "other-group"
+		ruleStore.PutRule(context.Background(), models.AlertRuleGen(withGroupKey(otherKey))())
+
+		response := srv.RoutePostRenameRuleGroup(newRequest(), apimodels.PostableRuleGroupRename{Name: otherKey.RuleGroup}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusConflict, response.Status())
+	})
+
+	t.Run("should move the rules to the new group", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostRenameRuleGroup(newRequest(), apimodels.PostableRuleGroupRename{Name: "new-group"}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		result := apimodels.UpdateRuleGroupResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Empty(t, result.Created)
+		require.Empty(t, result.Deleted)
+		require.Len(t, result.Updated, len(rules))
+
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		for _, update := range updates[0].([]models.UpdateRule) {
+			require.Equal(t, groupKey.RuleGroup, update.Existing.RuleGroup)
+			require.Equal(t, "new-group", update.New.RuleGroup)
+			require.Equal(t, update.Existing.UID, update.New.UID)
+			require.Equal(t, update.Existing.RuleGroupIndex, update.New.RuleGroupIndex)
+		}
+	})
+}
+
+func TestRoutePostCloneRuleGroup(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	target := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	rules := models.GenerateAlertRules(3, models.AlertRuleGen(withGroupKey(groupKey), models.WithUniqueGroupIndex()))
+
+	newService := func() (*RulerSrv, *fakes.RuleStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder, target)
+		ruleStore.PutRule(context.Background(), rules...)
+		srv := createService(ruleStore)
+		srv.conditionValidator = &recordingConditionValidator{}
+		return srv, ruleStore
+	}
+	newRequest := func() *contextmodel.ReqContext {
+		return createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				datasources.ActionQuery:     {datasources.ScopeAll},
+				ac.ActionAlertingRuleRead:   {dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)},
+				ac.ActionAlertingRuleCreate: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(target.UID)},
+			},
+		}, nil)
+	}
+
+	t.Run("should require a title prefix to copy into the same folder", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RoutePostCloneRuleGroup(newRequest(), apimodels.PostableRuleGroupClone{FolderUID: folder.UID, Name: "copy"}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 404 if the group does not exist", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RoutePostCloneRuleGroup(newRequest(), apimodels.PostableRuleGroupClone{FolderUID: target.UID}, folder.UID, "missing")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 409 if the folder has a group with the name of the copy", func(t *testing.T) {
+		srv, ruleStore := newService()
+		otherKey := groupKey
+		otherKey.NamespaceUID = target.UID
+		ruleStore.PutRule(context.Background(), models.AlertRuleGen(withGroupKey(otherKey))())
+
+		response := srv.RoutePostCloneRuleGroup(newRequest(), apimodels.PostableRuleGroupClone{FolderUID: target.UID}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusConflict, response.Status())
+	})
+
+	t.Run("should return 403 if the user cannot create rules in the folder", func(t *testing.T) {
+		srv, ruleStore := newService()
+		other := randFolder()
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], other)
+
+		response := srv.RoutePostCloneRuleGroup(newRequest(), apimodels.PostableRuleGroupClone{FolderUID: other.UID}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should create copies of the rules in the folder", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostCloneRuleGroup(newRequest(), apimodels.PostableRuleGroupClone{FolderUID: target.UID, TitlePrefix: "[prod] "}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		inserts := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		})
+		require.Len(t, inserts, 1)
+		copies := inserts[0].([]models.AlertRule)
+		require.Len(t, copies, len(rules))
+		titles := make([]string, 0, len(copies))
+		for _, r := range copies {
+			require.Empty(t, r.UID)
+			require.Equal(t, target.UID, r.NamespaceUID)
+			require.Equal(t, groupKey.RuleGroup, r.RuleGroup)
+			titles = append(titles, r.Title)
+		}
+		for _, r := range rules {
+			require.Contains(t, titles, "[prod] "+r.Title)
+		}
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		}))
+	})
+}
+
+func TestRoutePostPauseRules(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	other := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	otherKey := models.GenerateGroupKey(orgID)
+	otherKey.NamespaceUID = other.UID
+	rules := models.GenerateAlertRules(2, models.AlertRuleGen(withGroupKey(groupKey), models.WithUniqueGroupIndex()))
+	otherRules := models.GenerateAlertRules(2, models.AlertRuleGen(withGroupKey(otherKey), models.WithUniqueGroupIndex()))
+	for _, r := range append(rules, otherRules...) {
+		r.IsPaused = false
+	}
+
+	newService := func() (*RulerSrv, *fakes.RuleStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder, other)
+		ruleStore.PutRule(context.Background(), rules...)
+		ruleStore.PutRule(context.Background(), otherRules...)
+		return createService(ruleStore), ruleStore
+	}
+	newRequest := func(folderUIDs ...string) *contextmodel.ReqContext {
+		scopes := make([]string, 0, len(folderUIDs))
+		for _, uid := range folderUIDs {
+			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(uid))
+		}
+		return createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				datasources.ActionQuery:     {datasources.ScopeAll},
+				ac.ActionAlertingRuleRead:   scopes,
+				ac.ActionAlertingRuleUpdate: scopes,
+			},
+		}, nil)
+	}
+	getUpdates := func(ruleStore *fakes.RuleStore) [][]models.UpdateRule {
+		var result [][]models.UpdateRule
+		for _, cmd := range ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		}) {
+			result = append(result, cmd.([]models.UpdateRule))
+		}
+		return result
+	}
+
+	t.Run("should require either rule UIDs or a rule group", func(t *testing.T) {
+		srv, _ := newService()
+		require.Equal(t, http.StatusBadRequest, srv.RoutePostPauseRules(newRequest(folder.UID), apimodels.PostableRulesPause{IsPaused: true}).Status())
+		require.Equal(t, http.StatusBadRequest, srv.RoutePostPauseRules(newRequest(folder.UID), apimodels.PostableRulesPause{
+			RuleUIDs:  []string{rules[0].UID},
+			FolderUID: folder.UID,
+			RuleGroup: groupKey.RuleGroup,
+			IsPaused:  true,
+		}).Status())
+	})
+
+	t.Run("should return 404 if a rule does not exist", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostPauseRules(newRequest(folder.UID), apimodels.PostableRulesPause{RuleUIDs: []string{rules[0].UID, "missing"}, IsPaused: true})
+		require.Equal(t, http.StatusNotFound, response.Status())
+		require.Empty(t, getUpdates(ruleStore))
+	})
+
+	t.Run("should pause the rules of the rule group", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostPauseRules(newRequest(folder.UID), apimodels.PostableRulesPause{FolderUID: folder.UID, RuleGroup: groupKey.RuleGroup, IsPaused: true})
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		updates := getUpdates(ruleStore)
+		require.Len(t, updates, 1)
+		require.Len(t, updates[0], len(rules))
+		for _, u := range updates[0] {
+			require.True(t, u.New.IsPaused)
+			require.Equal(t, groupKey, u.New.GetGroupKey())
+		}
+	})
+
+	t.Run("should pause the rules of several folders in one update", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostPauseRules(newRequest(folder.UID, other.UID), apimodels.PostableRulesPause{RuleUIDs: []string{rules[0].UID, otherRules[1].UID}, IsPaused: true})
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		updates := getUpdates(ruleStore)
+		require.Len(t, updates, 1)
+		require.Len(t, updates[0], 2)
+		require.Equal(t, rules[0].UID, updates[0][0].New.UID)
+		require.Equal(t, otherRules[1].UID, updates[0][1].New.UID)
+	})
+
+	t.Run("should not update any rule if the user cannot update the rules of a folder", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostPauseRules(newRequest(folder.UID), apimodels.PostableRulesPause{RuleUIDs: []string{rules[0].UID, otherRules[1].UID}, IsPaused: true})
+		require.Equal(t, http.StatusForbidden, response.Status())
+		require.Empty(t, getUpdates(ruleStore))
+	})
+
+	t.Run("should not update rules that are already in the state", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostPauseRules(newRequest(folder.UID), apimodels.PostableRulesPause{FolderUID: folder.UID, RuleGroup: groupKey.RuleGroup, IsPaused: false})
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Empty(t, getUpdates(ruleStore))
+	})
+}
@@ -85,16 +85,21 @@ func validateRuleNode(
 	queries := AlertQueriesFromApiAlertQueries(ruleNode.GrafanaManagedAlert.Data)
 
 	newAlertRule := ngmodels.AlertRule{
-		OrgID:           orgId,
-		Title:           ruleNode.GrafanaManagedAlert.Title,
-		Condition:       ruleNode.GrafanaManagedAlert.Condition,
-		Data:            queries,
-		UID:             ruleNode.GrafanaManagedAlert.UID,
-		IntervalSeconds: intervalSeconds,
-		NamespaceUID:    namespace.UID,
-		RuleGroup:       groupName,
-		NoDataState:     noDataState,
-		ExecErrState:    errorState,
+		OrgID:             orgId,
+		Title:             ruleNode.GrafanaManagedAlert.Title,
+		Condition:         ruleNode.GrafanaManagedAlert.Condition,
+		Data:              queries,
+		UID:               ruleNode.GrafanaManagedAlert.UID,
+		IntervalSeconds:   intervalSeconds,
+		NamespaceUID:      namespace.UID,
+		RuleGroup:         groupName,
+		NoDataState:       noDataState,
+		ExecErrState:      errorState,
+		FingerprintLabels: ruleNode.GrafanaManagedAlert.FingerprintLabels,
+		PartialResults:    ruleNode.GrafanaManagedAlert.PartialResults,
+
+		MissingSeriesEvalsToResolve:    ruleNode.GrafanaManagedAlert.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: time.Duration(ruleNode.GrafanaManagedAlert.MissingSeriesDurationToResolve),
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
@@ -190,8 +195,9 @@ func validateRuleGroup(
 	}
 
 	interval := time.Duration(ruleGroupConfig.Interval)
-	if interval == 0 {
-		// if group interval is 0 (undefined) then we automatically fall back to the default interval
+	if interval == 0 || ruleGroupConfig.InheritInterval {
+		// if group interval is 0 (undefined) then we automatically fall back to the default interval.
+		// Groups that inherit the interval of the organization are validated against it too but the interval is resolved by the scheduler.
 		interval = cfg.DefaultRuleEvaluationInterval
 	}
 
@@ -226,8 +232,12 @@ func validateRuleGroup(
 		}
 
 		ruleWithOptionals := ngmodels.AlertRuleWithOptionals{}
+		if ruleGroupConfig.InheritInterval {
+			rule.IntervalSeconds = ngmodels.InheritedIntervalSeconds
+		}
 		rule.IsPaused = isPaused
 		rule.RuleGroupIndex = idx + 1
+		rule.GroupLabels = ruleGroupConfig.Labels
 		ruleWithOptionals.AlertRule = *rule
 		ruleWithOptionals.HasPause = hasPause
 
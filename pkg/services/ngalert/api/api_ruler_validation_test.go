@@ -213,6 +213,28 @@ func TestValidateRuleGroup(t *testing.T) {
 		}
 	})
 
+	t.Run("should mark rules as inheriting the interval of the organization", func(t *testing.T) {
+		g := validGroup(cfg, rules...)
+		g.Interval = 0
+		g.InheritInterval = true
+		alerts, err := validateRuleGroup(&g, orgId, folder, cfg)
+		require.NoError(t, err)
+		for _, alert := range alerts {
+			require.Equal(t, models.InheritedIntervalSeconds, alert.IntervalSeconds)
+			require.True(t, alert.InheritsInterval())
+		}
+	})
+
+	t.Run("should add the labels of the group to all rules", func(t *testing.T) {
+		g := validGroup(cfg, rules...)
+		g.Labels = map[string]string{"team": "sre"}
+		alerts, err := validateRuleGroup(&g, orgId, folder, cfg)
+		require.NoError(t, err)
+		for _, alert := range alerts {
+			require.Equal(t, g.Labels, alert.GroupLabels)
+		}
+	})
+
 	t.Run("should show the payload has isPaused field", func(t *testing.T) {
 		for _, rule := range rules {
 			isPaused := true
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RouteGetRuleGroupVersions returns the versions of the rules of the rule group, grouped by rule in the order of the
+// group and latest first. Only the versions of the rule with the UID in the query are returned if it is set.
+func (srv RulerSrv) RouteGetRuleGroupVersions(c *contextmodel.ReqContext, namespaceUID string, groupName string) response.Response {
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	rules, err := srv.getAuthorizedRuleGroup(c.Req.Context(), c, ngmodels.AlertRuleGroupKey{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		NamespaceUID: namespace.UID,
+		RuleGroup:    groupName,
+	})
+	if err != nil {
+		return errorToResponse(err)
+	}
+	if len(rules) == 0 {
+		return ErrResp(http.StatusNotFound, fmt.Errorf("%w: %s", store.ErrAlertRuleGroupNotFound, groupName), "")
+	}
+
+	ruleUID := c.Query("ruleUID")
+	result := apimodels.GettableRuleVersions{}
+	found := false
+	for _, rule := range rules {
+		if ruleUID != "" && rule.UID != ruleUID {
+			continue
+		}
+		found = true
+		versions, err := srv.store.GetAlertRuleVersions(c.Req.Context(), rule.OrgID, rule.UID)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get rule versions")
+		}
+		for _, v := range versions {
+			result = append(result, toGettableRuleVersion(v))
+		}
+	}
+	if !found {
+		return ErrResp(http.StatusNotFound, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleNotFound, ruleUID), "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RoutePostRuleVersionRestore replaces the definition of a rule of the rule group with the definition it had in a
+// previous version. The rule keeps its place in the group and its pause state. Like any other update of the group,
+// the restored rule is saved as a new version.
+func (srv RulerSrv) RoutePostRuleVersionRestore(c *contextmodel.ReqContext, body apimodels.PostableRuleVersionRestore, namespaceUID string, groupName string) response.Response {
+	if body.RuleUID == "" || body.Version <= 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("the UID of the rule and a positive version are required"), "")
+	}
+
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	groupKey := ngmodels.AlertRuleGroupKey{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		NamespaceUID: namespace.UID,
+		RuleGroup:    groupName,
+	}
+
+	var finalChanges *store.GroupDelta
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		rules, err := srv.getAuthorizedRuleGroup(tranCtx, c, groupKey)
+		if err != nil {
+			return err
+		}
+
+		restored := make([]*ngmodels.AlertRuleWithOptionals, 0, len(rules))
+		found := false
+		for _, rule := range rules {
+			r := *rule
+			if r.UID == body.RuleUID {
+				found = true
+				version, err := srv.store.GetAlertRuleVersion(tranCtx, groupKey.OrgID, r.UID, body.Version)
+				if err != nil {
+					return err
+				}
+				restoreRuleVersion(&r, version)
+			}
+			restored = append(restored, &ngmodels.AlertRuleWithOptionals{AlertRule: r})
+		}
+		if !found {
+			return fmt.Errorf("%w: rule %s is not in rule group %s", ngmodels.ErrAlertRuleNotFound, body.RuleUID, groupName)
+		}
+		finalChanges, err = srv.applyRuleGroupChanges(tranCtx, c, groupKey, restored)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ruleGroupUpdateErrorResponse(err)
+	}
+
+	resp := changesToUpdateRuleGroupResponse(finalChanges)
+	if !finalChanges.IsEmpty() {
+		resp.Message = "rule restored successfully"
+	}
+	return response.JSON(http.StatusAccepted, resp)
+}
+
+// restoreRuleVersion sets the definition of the rule to the definition it had in the version. The fields that
+// identify the rule and place it in its group, and its pause state, are not changed.
+func restoreRuleVersion(rule *ngmodels.AlertRule, version *ngmodels.AlertRuleVersion) {
+	rule.Title = version.Title
+	rule.Condition = version.Condition
+	rule.Data = version.Data
+	rule.NoDataState = version.NoDataState
+	rule.ExecErrState = version.ExecErrState
+	rule.For = version.For
+	rule.Annotations = version.Annotations
+	rule.Labels = version.Labels
+	rule.FingerprintLabels = version.FingerprintLabels
+	rule.PartialResults = version.PartialResults
+	rule.MissingSeriesEvalsToResolve = version.MissingSeriesEvalsToResolve
+	rule.MissingSeriesDurationToResolve = version.MissingSeriesDurationToResolve
+}
+
+func toGettableRuleVersion(v *ngmodels.AlertRuleVersion) apimodels.GettableRuleVersion {
+	return apimodels.GettableRuleVersion{
+		UID:       v.RuleUID,
+		Version:   v.Version,
+		Title:     v.Title,
+		Condition: v.Condition,
+		Data:      ApiAlertQueriesFromAlertQueries(v.Data),
+		RuleGroup: v.RuleGroup,
+		UpdatedBy: v.UpdatedBy,
+		Updated:   v.Created,
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestRuleGroupVersions(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	rules := models.GenerateAlertRules(2, models.AlertRuleGen(withGroupKey(groupKey), models.WithUniqueGroupIndex()))
+	rule := rules[0]
+
+	newService := func() (*RulerSrv, *fakes.RuleStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		ruleStore.PutRule(context.Background(), rules...)
+		ruleStore.Versions = []*models.AlertRuleVersion{
+			{RuleOrgID: orgID, RuleUID: rule.UID, RuleGroup: groupKey.RuleGroup, Version: 1, Title: "first title", Condition: rule.Condition, Data: rule.Data, UpdatedBy: "user:1"},
+			{RuleOrgID: orgID, RuleUID: rule.UID, RuleGroup: groupKey.RuleGroup, Version: 2, Title: rule.Title, Condition: rule.Condition, Data: rule.Data, UpdatedBy: "user:2"},
+			{RuleOrgID: orgID, RuleUID: rules[1].UID, RuleGroup: groupKey.RuleGroup, Version: 1, Title: rules[1].Title, Condition: rules[1].Condition, Data: rules[1].Data},
+		}
+		srv := createService(ruleStore)
+		srv.conditionValidator = &recordingConditionValidator{}
+		return srv, ruleStore
+	}
+	newRequest := func() *contextmodel.ReqContext {
+		return createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				datasources.ActionQuery:     {datasources.ScopeAll},
+				ac.ActionAlertingRuleRead:   {dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)},
+				ac.ActionAlertingRuleUpdate: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)},
+			},
+		}, nil)
+	}
+
+	t.Run("should return the versions of the rules of the group", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RouteGetRuleGroupVersions(newRequest(), folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		result := apimodels.GettableRuleVersions{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 3)
+
+		req := newRequest()
+		req.Req.Form.Set("ruleUID", rule.UID)
+		response = srv.RouteGetRuleGroupVersions(req, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusOK, response.Status())
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 2)
+		require.Equal(t, int64(2), result[0].Version)
+		require.Equal(t, "user:2", result[0].UpdatedBy)
+		require.Equal(t, "first title", result[1].Title)
+
+		req.Req.Form.Set("ruleUID", "missing")
+		response = srv.RouteGetRuleGroupVersions(req, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 404 if the version does not exist", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RoutePostRuleVersionRestore(newRequest(), apimodels.PostableRuleVersionRestore{RuleUID: rule.UID, Version: 10}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 404 if the rule is not in the group", func(t *testing.T) {
+		srv, _ := newService()
+		response := srv.RoutePostRuleVersionRestore(newRequest(), apimodels.PostableRuleVersionRestore{RuleUID: "missing", Version: 1}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should restore the version of the rule", func(t *testing.T) {
+		srv, ruleStore := newService()
+		response := srv.RoutePostRuleVersionRestore(newRequest(), apimodels.PostableRuleVersionRestore{RuleUID: rule.UID, Version: 1}, folder.UID, groupKey.RuleGroup)
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		result := apimodels.UpdateRuleGroupResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, []string{rule.UID}, result.Updated)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		update := updates[0].([]models.UpdateRule)[0]
+		require.Equal(t, rule.UID, update.New.UID)
+		require.Equal(t, "first title", update.New.Title)
+		require.Equal(t, rule.RuleGroupIndex, update.New.RuleGroupIndex)
+		require.NotEmpty(t, update.New.UpdatedBy)
+	})
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
@@ -30,6 +34,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/template"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -219,36 +224,165 @@ func addOptimizedQueryWarnings(evalResults *backend.QueryDataResponse, optimizat
 	}
 }
 
+// RouteGetTemplateFunctions returns the functions that can be used in the templates of annotations and labels of rules,
+// so that editors can suggest them.
+func (srv TestingApiSrv) RouteGetTemplateFunctions(_ *contextmodel.ReqContext) response.Response {
+	functions := template.Functions()
+	result := make([]apimodels.TemplateFunction, 0, len(functions))
+	for _, f := range functions {
+		result = append(result, apimodels.TemplateFunction{
+			Name:        f.Name,
+			Signature:   f.Signature,
+			Description: f.Description,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
 	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
 		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
 	}
 
+	format := c.Query("format")
+	if errResp := validateBacktestFormat(format); errResp != nil {
+		return errResp
+	}
+
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+	return backtestingResponse(format, result)
+}
+
+// CreateBacktestJob submits a backtesting that runs in the background, so that backtestings of long ranges with short
+// intervals do not time out. Clients poll the job for its progress and get its result when it succeeded.
+func (srv TestingApiSrv) CreateBacktestJob(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	job, err := srv.backtesting.Submit(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		if errors.Is(err, backtesting.ErrTooManyJobs) {
+			return ErrResp(http.StatusTooManyRequests, err, "")
+		}
+		return ErrResp(500, err, "Failed to submit the backtesting job")
+	}
+	return response.JSON(http.StatusAccepted, toBacktestJob(job))
+}
+
+// GetBacktestJob returns the status and the progress of a backtesting job that the user submitted.
+func (srv TestingApiSrv) GetBacktestJob(c *contextmodel.ReqContext, jobID string) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	job, err := srv.backtesting.GetJob(c.SignedInUser, jobID)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrJobNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(500, err, "Failed to get the backtesting job")
+	}
+	return response.JSON(http.StatusOK, toBacktestJob(job))
+}
+
+// DeleteBacktestJob cancels a running backtesting job that the user submitted, or deletes the result of a finished one.
+func (srv TestingApiSrv) DeleteBacktestJob(c *contextmodel.ReqContext, jobID string) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	if err := srv.backtesting.DeleteJob(c.SignedInUser, jobID); err != nil {
+		if errors.Is(err, backtesting.ErrJobNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(500, err, "Failed to delete the backtesting job")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "backtesting job deleted"})
+}
+
+// GetBacktestJobResult returns the result of a backtesting job that the user submitted, in the same formats as
+// BacktestAlertRule.
+func (srv TestingApiSrv) GetBacktestJobResult(c *contextmodel.ReqContext, jobID string) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	format := c.Query("format")
+	if errResp := validateBacktestFormat(format); errResp != nil {
+		return errResp
+	}
+
+	result, err := srv.backtesting.GetJobResult(c.SignedInUser, jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, backtesting.ErrJobNotFound):
+			return ErrResp(http.StatusNotFound, err, "")
+		case errors.Is(err, backtesting.ErrJobNotFinished):
+			return ErrResp(http.StatusConflict, err, "")
+		case errors.Is(err, backtesting.ErrInvalidInputData):
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+	return backtestingResponse(format, result)
+}
+
+func validateBacktestFormat(format string) response.Response {
+	switch format {
+	case "", apimodels.BacktestFormatFrame, apimodels.BacktestFormatCSV, apimodels.BacktestFormatPrometheus:
+		return nil
+	}
+	return ErrResp(400, nil, "Unsupported format %q, supported formats are %s, %s and %s", format,
+		apimodels.BacktestFormatFrame, apimodels.BacktestFormatCSV, apimodels.BacktestFormatPrometheus)
+}
+
+// backtestingRule validates the configuration of a backtesting and returns the rule to test.
+func (srv TestingApiSrv) backtestingRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) (*ngmodels.AlertRule, response.Response) {
 	if cmd.From.After(cmd.To) {
-		return ErrResp(400, nil, "From cannot be greater than To")
+		return nil, ErrResp(400, nil, "From cannot be greater than To")
 	}
 
 	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
 
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 	forInterval := time.Duration(cmd.For)
 	if forInterval < 0 {
-		return ErrResp(400, nil, "Bad For interval")
+		return nil, ErrResp(400, nil, "Bad For interval")
 	}
 
 	intervalSeconds, err := validateInterval(srv.cfg, time.Duration(cmd.Interval))
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 
 	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
 	if err := srv.authz.AuthorizeAccessToRuleGroup(c.Req.Context(), c.SignedInUser, ngmodels.RulesGroup{&ngmodels.AlertRule{Data: queries}}); err != nil {
-		return errorToResponse(err)
+		return nil, errorToResponse(err)
 	}
 
-	rule := &ngmodels.AlertRule{
+	return &ngmodels.AlertRule{
 		// ID:             0,
 		// Updated:        time.Time{},
 		// Version:        0,
@@ -269,14 +403,26 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		For:             forInterval,
 		Annotations:     cmd.Annotations,
 		Labels:          cmd.Labels,
-	}
+	}, nil
+}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
-	if err != nil {
-		if errors.Is(err, backtesting.ErrInvalidInputData) {
-			return ErrResp(400, err, "Failed to evaluate")
+// backtestingResponse returns the result of a backtesting in the format.
+func backtestingResponse(format string, result *data.Frame) response.Response {
+	switch format {
+	case apimodels.BacktestFormatCSV:
+		var buf bytes.Buffer
+		if err := backtesting.WriteCSV(&buf, result); err != nil {
+			return ErrResp(500, err, "Failed to convert frame to CSV")
 		}
-		return ErrResp(500, err, "Failed to evaluate")
+		return response.Respond(http.StatusOK, buf.Bytes()).SetHeader("Content-Type", "text/csv")
+	case apimodels.BacktestFormatPrometheus:
+		return response.JSON(http.StatusOK, apimodels.BacktestMatrixResult{
+			Status: "success",
+			Data: apimodels.BacktestMatrixResultData{
+				ResultType: "matrix",
+				Result:     backtesting.ToMatrix(result),
+			},
+		})
 	}
 
 	body, err := data.FrameToJSON(result, data.IncludeAll)
@@ -285,3 +431,342 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+func toBacktestJob(job backtesting.Job) apimodels.BacktestJob {
+	result := apimodels.BacktestJob{
+		ID:          job.ID,
+		Status:      string(job.Status),
+		Evaluations: job.Evaluations,
+		Total:       job.Total,
+		Started:     job.Started,
+	}
+	if job.Error != nil {
+		result.Error = job.Error.Error()
+	}
+	if !job.Finished.IsZero() {
+		finished := job.Finished
+		result.Finished = &finished
+	}
+	return result
+}
+
+// defaultLoadTestEvaluations is the number of evaluations of a load test if the request does not specify it.
+const defaultLoadTestEvaluations = 10
+
+// LoadTestAlertRule evaluates a rule against synthetic series instead of its data sources, and reports the latency and
+// the memory of the evaluations. It lets users estimate the cost of a rule before it queries production data sources.
+func (srv TestingApiSrv) LoadTestAlertRule(c *contextmodel.ReqContext, cmd apimodels.LoadTestConfig) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	interval := time.Duration(cmd.Interval)
+	if interval == 0 {
+		interval = srv.cfg.BaseInterval
+	}
+	intervalSeconds, err := validateInterval(srv.cfg, interval)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	evaluations := cmd.Evaluations
+	if evaluations == 0 {
+		evaluations = defaultLoadTestEvaluations
+	}
+
+	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &ngmodels.AlertRule{Data: queries}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to data sources", err)
+	}
+
+	rule := &ngmodels.AlertRule{
+		Title: "Load test",
+		// prefix loadtesting- is to distinguish between executions of regular rule and load testing in logs
+		UID:             "loadtesting-" + util.GenerateShortUID(),
+		OrgID:           c.SignedInUser.GetOrgID(),
+		Condition:       cmd.Condition,
+		Data:            queries,
+		IntervalSeconds: intervalSeconds,
+		NoDataState:     ngmodels.NoData,
+		ExecErrState:    ngmodels.ErrorErrState,
+	}
+
+	result, err := srv.backtesting.LoadTest(c.Req.Context(), rule, cmd.Series, evaluations)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "Failed to evaluate")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate")
+	}
+	return response.JSON(http.StatusOK, loadTestResultToApi(result))
+}
+
+// defaultPanelAlertPreviewPoints is the number of evaluations of a panel preview if the request does not specify it.
+const defaultPanelAlertPreviewPoints = 30
+
+// maxPanelAlertPreviewPoints limits the number of evaluations, and therefore of queries, of a panel preview.
+const maxPanelAlertPreviewPoints = 300
+
+// PanelAlertPreview evaluates a condition on the queries of a panel over the time range of the panel. It returns the
+// alerts that a rule created from the panel would produce at the end of the range, and a frame that marks where the
+// condition is breached so it can be drawn over the panel.
+func (srv TestingApiSrv) PanelAlertPreview(c *contextmodel.ReqContext, cmd apimodels.PanelAlertPreviewConfig) response.Response {
+	if len(cmd.Data) == 0 {
+		return ErrResp(http.StatusBadRequest, nil, "At least one query is required")
+	}
+	to := cmd.To
+	if to.IsZero() {
+		to = timeNow()
+	}
+	from := cmd.From
+	if from.IsZero() {
+		from = to.Add(-time.Hour)
+	}
+	if !from.Before(to) {
+		return ErrResp(http.StatusBadRequest, nil, "From must be before To")
+	}
+	points := cmd.Points
+	if points == 0 {
+		points = defaultPanelAlertPreviewPoints
+	}
+	if points < 0 || points > maxPanelAlertPreviewPoints {
+		return ErrResp(http.StatusBadRequest, nil, fmt.Sprintf("Points must be between 1 and %d", maxPanelAlertPreviewPoints))
+	}
+	forInterval := time.Duration(cmd.For)
+	if forInterval < 0 {
+		return ErrResp(http.StatusBadRequest, nil, "Bad For interval")
+	}
+
+	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &ngmodels.AlertRule{Data: queries}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to data sources", err)
+	}
+
+	rule := panelPreviewRule(c.SignedInUser.GetOrgID(), cmd, queries)
+	step := to.Sub(from) / time.Duration(points)
+	rule.IntervalSeconds = int64(math.Max(1, math.Round(step.Seconds())))
+
+	evaluator, err := srv.evaluator.Create(eval.NewContext(c.Req.Context(), c.SignedInUser), rule.GetEvalCondition())
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
+	}
+
+	cfg := state.ManagerCfg{
+		Metrics:       nil,
+		ExternalURL:   srv.appUrl,
+		InstanceStore: nil,
+		Images:        &backtesting.NoopImageService{},
+		Clock:         clock.New(),
+		Historian:     nil,
+		Tracer:        srv.tracer,
+		Log:           log.New("ngalert.state.manager"),
+	}
+	manager := state.NewManager(cfg, state.NewNoopPersister())
+	extraLabels := state.GetRuleExtraLabels(rule, "", false)
+
+	timeField := data.NewField("Time", nil, make([]time.Time, points))
+	breachFields := make([]*data.Field, 0)
+	fieldsByCacheID := make(map[string]*data.Field)
+	var transitions []state.StateTransition
+	for idx := 0; idx < points; idx++ {
+		now := to.Add(-time.Duration(points-idx-1) * step)
+		results, err := evaluator.Evaluate(c.Req.Context(), now)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries")
+		}
+		transitions = manager.ProcessEvalResults(c.Req.Context(), now, rule, results, extraLabels)
+		timeField.Set(idx, now)
+		for _, s := range transitions {
+			field, ok := fieldsByCacheID[s.CacheID]
+			if !ok {
+				field = data.NewField("", s.Labels, make([]*bool, points))
+				fieldsByCacheID[s.CacheID] = field
+				breachFields = append(breachFields, field)
+			}
+			if breach, ok := isPreviewBreach(s.State.State); ok {
+				field.Set(idx, &breach)
+			}
+		}
+	}
+
+	alerts := make([]amv2.PostableAlert, 0, len(transitions))
+	for _, alertState := range transitions {
+		alerts = append(alerts, *state.StateToPostableAlert(alertState, srv.appUrl))
+	}
+
+	return response.JSON(http.StatusOK, apimodels.PanelAlertPreviewResult{
+		Instances: alerts,
+		Frame:     data.NewFrame("Preview", append([]*data.Field{timeField}, breachFields...)...),
+	})
+}
+
+// panelPreviewRule creates the rule that a user would get by creating an alert from the panel.
+func panelPreviewRule(orgID int64, cmd apimodels.PanelAlertPreviewConfig, queries []ngmodels.AlertQuery) *ngmodels.AlertRule {
+	condition := cmd.Condition
+	if condition == "" {
+		condition = queries[len(queries)-1].RefID
+	}
+	title := cmd.Title
+	if title == "" {
+		title = "Panel alert preview"
+	}
+	annotations := make(map[string]string, len(cmd.Annotations)+2)
+	for k, v := range cmd.Annotations {
+		annotations[k] = v
+	}
+	rule := &ngmodels.AlertRule{
+		Title: title,
+		// prefix preview- is to distinguish between executions of regular rule and panel previews in logs
+		UID:          "preview-" + util.GenerateShortUID(),
+		OrgID:        orgID,
+		Condition:    condition,
+		Data:         queries,
+		NoDataState:  ngmodels.NoData,
+		ExecErrState: ngmodels.ErrorErrState,
+		For:          time.Duration(cmd.For),
+		Labels:       cmd.Labels,
+		Annotations:  annotations,
+	}
+	if cmd.DashboardUID != "" {
+		dashboardUID, panelID := cmd.DashboardUID, cmd.PanelID
+		rule.DashboardUID = &dashboardUID
+		rule.PanelID = &panelID
+		annotations[ngmodels.DashboardUIDAnnotation] = dashboardUID
+		annotations[ngmodels.PanelIDAnnotation] = strconv.FormatInt(panelID, 10)
+	}
+	return rule
+}
+
+// isPreviewBreach returns whether the state of an instance breaches the condition. It returns false as second value
+// if the condition could not be evaluated, which leaves a gap in the preview.
+func isPreviewBreach(s eval.State) (bool, bool) {
+	switch s {
+	case eval.Alerting, eval.Pending:
+		return true, true
+	case eval.Normal:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// maxRangePreviewEvaluations limits the number of evaluations, and therefore of queries, of a range preview.
+const maxRangePreviewEvaluations = 1000
+
+// RangePreview evaluates a condition at every interval of a time range, as a rule with the condition would have been
+// evaluated, and returns the result of every series of every evaluation. Unlike backtesting, the results are not
+// processed by the state manager, so they show how the condition behaves rather than the alerts of the rule.
+func (srv TestingApiSrv) RangePreview(c *contextmodel.ReqContext, cmd apimodels.RangePreviewConfig) response.Response {
+	if len(cmd.Data) == 0 {
+		return ErrResp(http.StatusBadRequest, nil, "At least one query is required")
+	}
+	if cmd.From.IsZero() || cmd.To.IsZero() || cmd.From.After(cmd.To) {
+		return ErrResp(http.StatusBadRequest, nil, "From and To are required and From cannot be greater than To")
+	}
+	interval := time.Duration(cmd.Interval)
+	if interval == 0 {
+		interval = srv.cfg.DefaultRuleEvaluationInterval
+	}
+	if _, err := validateInterval(srv.cfg, interval); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	evaluations := int(cmd.To.Sub(cmd.From)/interval) + 1
+	if evaluations > maxRangePreviewEvaluations {
+		return ErrResp(http.StatusBadRequest, nil, fmt.Sprintf("The time range and interval result in %d evaluations, the maximum is %d", evaluations, maxRangePreviewEvaluations))
+	}
+
+	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &ngmodels.AlertRule{Data: queries}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to data sources", err)
+	}
+
+	cond := ngmodels.Condition{
+		Condition: cmd.Condition,
+		Data:      queries,
+	}
+	if cond.Condition == "" {
+		cond.Condition = cond.Data[len(cond.Data)-1].RefID
+	}
+	evaluator, err := srv.evaluator.Create(eval.NewContext(c.Req.Context(), c.SignedInUser), cond)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
+	}
+
+	result := apimodels.RangePreviewResult{
+		Evaluations: make([]apimodels.RangePreviewEvaluation, 0, evaluations),
+	}
+	for idx := 0; idx < evaluations; idx++ {
+		now := cmd.From.Add(time.Duration(idx) * interval)
+		results, err := evaluator.Evaluate(c.Req.Context(), now)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries")
+		}
+		result.Evaluations = append(result.Evaluations, apimodels.RangePreviewEvaluation{
+			Time:    now,
+			Results: rangePreviewSeriesResults(results),
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// rangePreviewSeriesResults converts the results of an evaluation to the results of a range preview.
+func rangePreviewSeriesResults(results eval.Results) []apimodels.RangePreviewSeriesResult {
+	series := make([]apimodels.RangePreviewSeriesResult, 0, len(results))
+	for _, r := range results {
+		s := apimodels.RangePreviewSeriesResult{
+			Labels: r.Instance,
+			State:  r.State.String(),
+		}
+		if s.Labels == nil {
+			s.Labels = map[string]string{}
+		}
+		if len(r.Values) > 0 {
+			s.Values = make(map[string]*float64, len(r.Values))
+			for refID, v := range r.Values {
+				s.Values[refID] = v.Value
+			}
+		}
+		if r.Error != nil {
+			s.Error = r.Error.Error()
+		}
+		series = append(series, s)
+	}
+	return series
+}
+
+func loadTestResultToApi(result *backtesting.LoadTestResult) apimodels.LoadTestResult {
+	latencies := make([]time.Duration, len(result.Latencies))
+	copy(latencies, result.Latencies)
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	var latency apimodels.LoadTestLatency
+	if len(latencies) > 0 {
+		latency = apimodels.LoadTestLatency{
+			Min:  latencies[0].Seconds(),
+			Max:  latencies[len(latencies)-1].Seconds(),
+			Mean: (total / time.Duration(len(latencies))).Seconds(),
+			P95:  latencies[int(math.Ceil(0.95*float64(len(latencies))))-1].Seconds(),
+		}
+	}
+
+	memory := apimodels.LoadTestMemory{
+		AllocatedBytes: result.AllocatedBytes,
+		Allocations:    result.Allocations,
+	}
+	if result.Series > 0 && result.Evaluations > 0 {
+		memory.BytesPerSeries = float64(result.AllocatedBytes) / float64(result.Series*result.Evaluations)
+	}
+
+	return apimodels.LoadTestResult{
+		Series:      result.Series,
+		Evaluations: result.Evaluations,
+		Instances:   result.Instances,
+		Latency:     latency,
+		Memory:      memory,
+	}
+}
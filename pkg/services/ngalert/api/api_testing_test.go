@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -387,6 +390,216 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestPanelAlertPreview(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+
+	t.Run("should return Forbidden if user cannot query a data source", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		data2 := models.GenerateAlertQuery()
+
+		srv := &TestingApiSrv{
+			authz: accesscontrol.NewRuleService(acMock.New().WithPermissions([]ac.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+			})),
+			tracer: tracing.InitializeTracerForTest(),
+		}
+
+		response := srv.PanelAlertPreview(rc, definitions.PanelAlertPreviewConfig{
+			Data: ApiAlertQueriesFromAlertQueries([]models.AlertQuery{data1, data2}),
+		})
+
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should return BadRequest if the time range or points are invalid", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, nil, nil, &featuremgmt.FeatureManager{}, fakes2.NewRuleStore(t))
+		queries := ApiAlertQueriesFromAlertQueries([]models.AlertQuery{models.GenerateAlertQuery()})
+		now := time.Now()
+
+		require.Equal(t, http.StatusBadRequest, srv.PanelAlertPreview(rc, definitions.PanelAlertPreviewConfig{}).Status())
+		require.Equal(t, http.StatusBadRequest, srv.PanelAlertPreview(rc, definitions.PanelAlertPreviewConfig{
+			Data: queries,
+			From: now,
+			To:   now.Add(-time.Hour),
+		}).Status())
+		require.Equal(t, http.StatusBadRequest, srv.PanelAlertPreview(rc, definitions.PanelAlertPreviewConfig{
+			Data:   queries,
+			Points: maxPanelAlertPreviewPoints + 1,
+		}).Status())
+	})
+
+	t.Run("should return the instances and mark the breaches in the frame", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ac := acMock.New().WithPermissions([]ac.Permission{
+			{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+		})
+
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(eval.Results{
+			{Instance: data.Labels{"pod": "a"}, State: eval.Alerting},
+			{Instance: data.Labels{"pod": "b"}, State: eval.Normal},
+		}, nil)
+
+		srv := createTestingApiSrv(t, nil, ac, eval_mocks.NewEvaluatorFactory(evaluator), &featuremgmt.FeatureManager{}, fakes2.NewRuleStore(t))
+
+		to := time.Now()
+		response := srv.PanelAlertPreview(rc, definitions.PanelAlertPreviewConfig{
+			Data:         ApiAlertQueriesFromAlertQueries([]models.AlertQuery{data1}),
+			From:         to.Add(-10 * time.Minute),
+			To:           to,
+			Points:       5,
+			DashboardUID: "dashboard",
+			PanelID:      2,
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+		evaluator.AssertNumberOfCalls(t, "Evaluate", 5)
+		evaluator.AssertCalled(t, "Evaluate", mock.Anything, to)
+
+		var result definitions.PanelAlertPreviewResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Instances, 2)
+		for _, alert := range result.Instances {
+			require.Equal(t, "dashboard", alert.Annotations[models.DashboardUIDAnnotation])
+			require.Equal(t, "2", alert.Annotations[models.PanelIDAnnotation])
+		}
+
+		require.Len(t, result.Frame.Fields, 3)
+		require.Equal(t, 5, result.Frame.Rows())
+		for _, field := range result.Frame.Fields[1:] {
+			breach := field.Labels["pod"] == "a"
+			for i := 0; i < field.Len(); i++ {
+				require.Equal(t, &breach, field.At(i))
+			}
+		}
+	})
+}
+
+func TestRangePreview(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+
+	t.Run("should return BadRequest if the time range or interval is invalid", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, nil, nil, &featuremgmt.FeatureManager{}, fakes2.NewRuleStore(t))
+		queries := ApiAlertQueriesFromAlertQueries([]models.AlertQuery{models.GenerateAlertQuery()})
+		now := time.Now()
+
+		require.Equal(t, http.StatusBadRequest, srv.RangePreview(rc, definitions.RangePreviewConfig{From: now.Add(-time.Hour), To: now}).Status())
+		require.Equal(t, http.StatusBadRequest, srv.RangePreview(rc, definitions.RangePreviewConfig{Data: queries, To: now}).Status())
+		require.Equal(t, http.StatusBadRequest, srv.RangePreview(rc, definitions.RangePreviewConfig{
+			Data: queries,
+			From: now,
+			To:   now.Add(-time.Hour),
+		}).Status())
+		require.Equal(t, http.StatusBadRequest, srv.RangePreview(rc, definitions.RangePreviewConfig{
+			Data:     queries,
+			From:     now.Add(-time.Hour),
+			To:       now,
+			Interval: prommodel.Duration(-time.Minute),
+		}).Status())
+		require.Equal(t, http.StatusBadRequest, srv.RangePreview(rc, definitions.RangePreviewConfig{
+			Data:     queries,
+			From:     now.Add(-time.Duration(maxRangePreviewEvaluations) * srv.cfg.BaseInterval),
+			To:       now,
+			Interval: prommodel.Duration(srv.cfg.BaseInterval),
+		}).Status())
+	})
+
+	t.Run("should return the results of every evaluation in the range", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		ac := acMock.New().WithPermissions([]ac.Permission{
+			{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+		})
+		value := 42.0
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(eval.Results{
+			{Instance: data.Labels{"pod": "a"}, State: eval.Alerting, Values: map[string]eval.NumberValueCapture{"B": {Var: "B", Value: &value}}},
+			{Instance: data.Labels{"pod": "b"}, State: eval.Error, Error: errors.New("query failed")},
+		}, nil)
+
+		srv := createTestingApiSrv(t, nil, ac, eval_mocks.NewEvaluatorFactory(evaluator), &featuremgmt.FeatureManager{}, fakes2.NewRuleStore(t))
+		interval := srv.cfg.BaseInterval
+		from := time.Now().Truncate(time.Second)
+		to := from.Add(2 * interval)
+		response := srv.RangePreview(rc, definitions.RangePreviewConfig{
+			Data:     ApiAlertQueriesFromAlertQueries([]models.AlertQuery{data1}),
+			From:     from,
+			To:       to,
+			Interval: prommodel.Duration(interval),
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+		evaluator.AssertNumberOfCalls(t, "Evaluate", 3)
+
+		var result definitions.RangePreviewResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Evaluations, 3)
+		for i, evaluation := range result.Evaluations {
+			require.True(t, from.Add(time.Duration(i)*interval).Equal(evaluation.Time))
+			require.Equal(t, []definitions.RangePreviewSeriesResult{
+				{Labels: map[string]string{"pod": "a"}, State: "Alerting", Values: map[string]*float64{"B": &value}},
+				{Labels: map[string]string{"pod": "b"}, State: "Error", Error: "query failed"},
+			}, evaluation.Results)
+		}
+	})
+}
+
+func TestLoadTestResultToApi(t *testing.T) {
+	latencies := make([]time.Duration, 0, 20)
+	for i := 20; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	result := loadTestResultToApi(&backtesting.LoadTestResult{
+		Series:         10,
+		Evaluations:    20,
+		Instances:      10,
+		Latencies:      latencies,
+		AllocatedBytes: 2000,
+		Allocations:    100,
+	})
+
+	require.Equal(t, definitions.LoadTestResult{
+		Series:      10,
+		Evaluations: 20,
+		Instances:   10,
+		Latency: definitions.LoadTestLatency{
+			Min:  0.001,
+			Max:  0.02,
+			Mean: 0.0105,
+			P95:  0.019,
+		},
+		Memory: definitions.LoadTestMemory{
+			AllocatedBytes: 2000,
+			Allocations:    100,
+			BytesPerSeries: 10,
+		},
+	}, result)
+}
+
+func TestToBacktestJob(t *testing.T) {
+	started := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	running := toBacktestJob(backtesting.Job{ID: "job", Status: backtesting.JobStatusRunning, Evaluations: 2, Total: 10, Started: started})
+	require.Equal(t, definitions.BacktestJob{ID: "job", Status: "running", Evaluations: 2, Total: 10, Started: started}, running)
+
+	finished := started.Add(time.Minute)
+	failed := toBacktestJob(backtesting.Job{ID: "job", Status: backtesting.JobStatusFailed, Total: 10, Error: errors.New("query failed"), Started: started, Finished: finished})
+	require.Equal(t, "failed", failed.Status)
+	require.Equal(t, "query failed", failed.Error)
+	require.Equal(t, &finished, failed.Finished)
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory, featureManager *featuremgmt.FeatureManager, ruleStore RuleStore) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New()
@@ -402,3 +615,20 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		folderService:   ruleStore,
 	}
 }
+
+func TestRouteGetTemplateFunctions(t *testing.T) {
+	srv := TestingApiSrv{}
+	response := srv.RouteGetTemplateFunctions(&contextmodel.ReqContext{})
+	require.Equal(t, http.StatusOK, response.Status())
+
+	var result []definitions.TemplateFunction
+	require.NoError(t, json.Unmarshal(response.Body(), &result))
+	names := make([]string, 0, len(result))
+	for _, f := range result {
+		require.NotEmpty(t, f.Signature)
+		require.NotEmpty(t, f.Description)
+		names = append(names, f.Name)
+	}
+	require.IsIncreasing(t, names)
+	require.Subset(t, names, []string{"humanize", "humanizeDuration", "humanizePercentage", "toTime", "date", "tz", "now"})
+}
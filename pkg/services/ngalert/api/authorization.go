@@ -6,13 +6,32 @@ import (
 
 	"github.com/grafana/grafana/pkg/middleware"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/web"
 )
 
-//nolint:gocyclo
+// authorize returns the handler that authorizes the requests to the endpoint.
+// Authorized requests that change the alerting resources of an organization in read-only mode are rejected.
 func (api *API) authorize(method, path string) web.Handler {
+	handler := api.authorizeRoute(method, path)
+	if api.KVStore == nil || !isReadOnlyGuarded(method, path) {
+		return handler
+	}
+	guarded, err := readOnlyGuard(handler, newReadOnlyModeStore(api.KVStore))
+	if err != nil {
+		// the requests are rejected rather than allowed to change the resources of an organization in read-only mode
+		err = fmt.Errorf("failed to guard method [%s] of endpoint [%s] in read-only mode: %w", method, path, err)
+		return func(c *contextmodel.ReqContext) {
+			ErrResp(http.StatusInternalServerError, err, "").WriteTo(c)
+		}
+	}
+	return guarded
+}
+
+//nolint:gocyclo
+func (api *API) authorizeRoute(method, path string) web.Handler {
 	authorize := ac.Middleware(api.AccessControl)
 	var eval ac.Evaluator = nil
 
@@ -24,17 +43,41 @@ func (api *API) authorize(method, path string) web.Handler {
 		eval = ac.EvalPermission(ac.ActionAlertingRuleDelete, dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace")))
 	case http.MethodDelete + "/api/ruler/grafana/api/v1/rules/{Namespace}":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleDelete, dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace")))
-	case http.MethodGet + "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}":
+	case http.MethodGet + "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}",
+		http.MethodGet + "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/versions":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace")))
 	case http.MethodGet + "/api/ruler/grafana/api/v1/rules/{Namespace}":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace")))
 	case http.MethodGet + "/api/ruler/grafana/api/v1/rules",
+		http.MethodGet + "/api/ruler/grafana/api/v1/export/rule/{RuleUID}",
 		http.MethodGet + "/api/ruler/grafana/api/v1/export/rules":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/convert/datadog":
+		// the converted rules are not saved
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/clone":
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
+		// the rules are created in the folder in the body, permissions to create them are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead, scope)
 	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/export":
 		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
 		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead, scope)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/rename",
+		http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/versions/restore":
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
+		// the rules of the group are updated, permissions to each rule are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalPermission(ac.ActionAlertingRuleUpdate, scope)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/import":
+		// the folders are in the body, permissions to each folder are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+			ac.EvalPermission(ac.ActionAlertingRuleCreate),
+			ac.EvalPermission(ac.ActionAlertingRuleDelete),
+		)
+	case http.MethodPost + "/api/v1/rules/pause":
+		// the rules are in the body, permissions to update each rule are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalPermission(ac.ActionAlertingRuleUpdate)
 	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}":
 		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
 		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
@@ -57,7 +100,8 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingReceiversRead),
 			ac.EvalPermission(ac.ActionAlertingReceiversReadSecrets),
 		)
-	case http.MethodGet + "/api/v1/notifications/receivers/{Name}":
+	case http.MethodGet + "/api/v1/notifications/receivers/{Name}",
+		http.MethodGet + "/api/v1/notifications/receivers/{Name}/usage":
 		// TODO: scope to :Name
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingReceiversRead),
@@ -83,8 +127,13 @@ func (api *API) authorize(method, path string) web.Handler {
 		return middleware.ReqOrgAdmin
 
 	// Grafana, Prometheus-compatible Paths
-	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
+	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules",
+		http.MethodGet + "/api/prometheus/grafana/api/v1/status/buildinfo",
+		http.MethodGet + "/api/prometheus/grafana/api/v1/metadata",
+		http.MethodGet + "/api/v1/rules/health":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodGet + "/api/v1/rules/scheduling/{Namespace}":
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace")))
 
 	// Grafana Rules Testing Paths
 	case http.MethodPost + "/api/v1/rule/test/grafana":
@@ -94,9 +143,28 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/v1/rule/backtest":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backtest/jobs":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodGet + "/api/v1/rule/backtest/jobs/{JobID}",
+		http.MethodGet + "/api/v1/rule/backtest/jobs/{JobID}/result",
+		http.MethodDelete + "/api/v1/rule/backtest/jobs/{JobID}":
+		// jobs are only returned to the users who submitted them
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/loadtest":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/preview/panel":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/preview/range":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 	case http.MethodPost + "/api/v1/eval":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodGet + "/api/v1/rule/template/functions":
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Lotex Paths
 	case http.MethodDelete + "/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}":
@@ -187,6 +255,12 @@ func (api *API) authorize(method, path string) web.Handler {
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsWrite)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/templates/test":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsWrite)
+	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/grouping/preview":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodPost + "/api/alertmanager/grafana/api/v1/fire_drill":
+		return middleware.ReqOrgAdmin
+	case http.MethodPost + "/api/alertmanager/grafana/api/v1/silences/purge":
+		return middleware.ReqOrgAdmin
 
 	// External Alertmanager Paths
 	case http.MethodDelete + "/api/alertmanager/{DatasourceUID}/config/api/v1/alerts":
@@ -198,7 +272,8 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/alertmanager/{DatasourceUID}/config/api/v1/alerts":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsExternalWrite, datasources.ScopeProvider.GetResourceScopeUID(ac.Parameter(":DatasourceUID")))
 
-	case http.MethodGet + "/api/v1/ngalert":
+	case http.MethodGet + "/api/v1/ngalert",
+		http.MethodGet + "/api/v1/ngalert/metrics":
 		// let user with any alerting permission access this API
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingInstanceRead),
@@ -212,9 +287,32 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodDelete + "/api/v1/ngalert/admin_config",
 		http.MethodGet + "/api/v1/ngalert/admin_config",
 		http.MethodPost + "/api/v1/ngalert/admin_config",
-		http.MethodGet + "/api/v1/ngalert/alertmanagers":
+		http.MethodGet + "/api/v1/ngalert/alertmanagers",
+		http.MethodGet + "/api/v1/ngalert/label_policies",
+		http.MethodPut + "/api/v1/ngalert/label_policies",
+		http.MethodDelete + "/api/v1/ngalert/label_policies",
+		http.MethodGet + "/api/v1/ngalert/label_policies/violations",
+		http.MethodGet + "/api/v1/ngalert/pause_windows",
+		http.MethodPost + "/api/v1/ngalert/pause_windows",
+		http.MethodDelete + "/api/v1/ngalert/pause_windows/{ID}",
+		http.MethodGet + "/api/v1/ngalert/read_only",
+		http.MethodPut + "/api/v1/ngalert/read_only":
 		return middleware.ReqOrgAdmin
 
+	// The state snapshot contains the alert instances of all organizations
+	case http.MethodGet + "/api/v1/ngalert/state/snapshot",
+		http.MethodPost + "/api/v1/ngalert/state/snapshot":
+		return middleware.ReqGrafanaAdmin
+
+	// The organizations that are cleaned up are deleted, so only server admins can see them
+	case http.MethodGet + "/api/v1/ngalert/cleanup/orgs":
+		return middleware.ReqGrafanaAdmin
+
+	// The alert rule quota is distributed across all organizations
+	case http.MethodGet + "/api/v1/ngalert/quota/alert_rule",
+		http.MethodPost + "/api/v1/ngalert/quota/alert_rule/rebalance":
+		return middleware.ReqGrafanaAdmin
+
 	// Grafana-only Provisioning Read Paths
 	case http.MethodGet + "/api/v1/provisioning/policies/export",
 		http.MethodGet + "/api/v1/provisioning/contact-points/export",
@@ -227,14 +325,20 @@ func (api *API) authorize(method, path string) web.Handler {
 		)
 
 	case http.MethodGet + "/api/v1/provisioning/policies",
+		http.MethodGet + "/api/v1/provisioning/policies/named",
+		http.MethodGet + "/api/v1/provisioning/policies/named/{name}",
 		http.MethodGet + "/api/v1/provisioning/contact-points",
+		http.MethodGet + "/api/v1/provisioning/contact-points/insights",
 		http.MethodGet + "/api/v1/provisioning/templates",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
+		http.MethodGet + "/api/v1/provisioning/templates/{name}/versions",
+		http.MethodGet + "/api/v1/provisioning/templates/{name}/versions/{version}/diff",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
 		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
 		http.MethodGet + "/api/v1/provisioning/alert-rules",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/insights",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export",
 		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
 		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export":
@@ -242,11 +346,14 @@ func (api *API) authorize(method, path string) web.Handler {
 
 	case http.MethodPut + "/api/v1/provisioning/policies",
 		http.MethodDelete + "/api/v1/provisioning/policies",
+		http.MethodPut + "/api/v1/provisioning/policies/named/{name}",
+		http.MethodDelete + "/api/v1/provisioning/policies/named/{name}",
 		http.MethodPost + "/api/v1/provisioning/contact-points",
 		http.MethodPut + "/api/v1/provisioning/contact-points/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/contact-points/{UID}",
 		http.MethodPut + "/api/v1/provisioning/templates/{name}",
 		http.MethodDelete + "/api/v1/provisioning/templates/{name}",
+		http.MethodPost + "/api/v1/provisioning/templates/{name}/versions/{version}/rollback",
 		http.MethodPost + "/api/v1/provisioning/mute-timings",
 		http.MethodPut + "/api/v1/provisioning/mute-timings/{name}",
 		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}",
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -9,7 +10,11 @@ import (
 	"github.com/go-openapi/loads"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
 
 func TestAuthorize(t *testing.T) {
@@ -40,7 +45,7 @@ func TestAuthorize(t *testing.T) {
 		}
 		paths[p] = methods
 	}
-	require.Len(t, paths, 64)
+	require.Len(t, paths, 104)
 
 	ac := acmock.New()
 	api := &API{AccessControl: ac}
@@ -61,4 +66,33 @@ func TestAuthorize(t *testing.T) {
 			api.authorize("test", "test")
 		})
 	})
+
+	t.Run("should guard known routes in read-only mode", func(t *testing.T) {
+		kv := kvstore.NewFakeKVStore()
+		require.NoError(t, newReadOnlyModeStore(kv).Set(context.Background(), 1, definitions.ReadOnlyMode{Enabled: true}))
+		api := &API{AccessControl: actest.FakeAccessControl{ExpectedEvaluate: true}, KVStore: kv}
+
+		for path, methods := range paths {
+			path := swaggerSpec.Spec().BasePath + path
+			for _, method := range methods {
+				handler := api.authorize(method, path)
+				_, ok := handler.(func(*contextmodel.ReqContext))
+				require.Truef(t, ok, "unexpected handler %T for method [%s] of endpoint [%s]", handler, method, path)
+			}
+		}
+
+		guarded := api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rules/{Namespace}").(func(*contextmodel.ReqContext))
+		c := createRequestContext(1, nil)
+		guarded(c)
+		require.Equal(t, http.StatusLocked, c.Resp.Status())
+
+		c = createRequestContext(2, nil)
+		guarded(c)
+		require.False(t, c.Resp.Written())
+
+		exempt := api.authorize(http.MethodPost, "/api/v1/rule/test/grafana").(func(*contextmodel.ReqContext))
+		c = createRequestContext(1, nil)
+		exempt(c)
+		require.False(t, c.Resp.Written())
+	})
 }
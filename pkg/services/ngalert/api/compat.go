@@ -15,43 +15,53 @@ import (
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
 	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         AlertQueriesFromApiAlertQueries(a.Data),
-		Updated:      a.Updated,
-		NoDataState:  models.NoDataState(a.NoDataState),          // TODO there must be a validation
-		ExecErrState: models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
-		For:          time.Duration(a.For),
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-		IsPaused:     a.IsPaused,
+		ID:                a.ID,
+		UID:               a.UID,
+		OrgID:             a.OrgID,
+		NamespaceUID:      a.FolderUID,
+		RuleGroup:         a.RuleGroup,
+		Title:             a.Title,
+		Condition:         a.Condition,
+		Data:              AlertQueriesFromApiAlertQueries(a.Data),
+		Updated:           a.Updated,
+		NoDataState:       models.NoDataState(a.NoDataState),          // TODO there must be a validation
+		ExecErrState:      models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
+		For:               time.Duration(a.For),
+		Annotations:       a.Annotations,
+		Labels:            a.Labels,
+		IsPaused:          a.IsPaused,
+		FingerprintLabels: a.FingerprintLabels,
+		PartialResults:    a.PartialResults,
+
+		MissingSeriesEvalsToResolve:    a.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: time.Duration(a.MissingSeriesDurationToResolve),
 	}, nil
 }
 
 // ProvisionedAlertRuleFromAlertRule converts models.AlertRule to definitions.ProvisionedAlertRule and sets provided provenance status
 func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.Provenance) definitions.ProvisionedAlertRule {
 	return definitions.ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         ApiAlertQueriesFromAlertQueries(rule.Data),
-		Updated:      rule.Updated,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
-		IsPaused:     rule.IsPaused,
+		ID:                rule.ID,
+		UID:               rule.UID,
+		OrgID:             rule.OrgID,
+		FolderUID:         rule.NamespaceUID,
+		RuleGroup:         rule.RuleGroup,
+		Title:             rule.Title,
+		For:               model.Duration(rule.For),
+		Condition:         rule.Condition,
+		Data:              ApiAlertQueriesFromAlertQueries(rule.Data),
+		Updated:           rule.Updated,
+		NoDataState:       definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
+		ExecErrState:      definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
+		Annotations:       rule.Annotations,
+		Labels:            rule.Labels,
+		Provenance:        definitions.Provenance(provenance), // TODO validate enum conversion?
+		IsPaused:          rule.IsPaused,
+		FingerprintLabels: rule.FingerprintLabels,
+		PartialResults:    rule.PartialResults,
+
+		MissingSeriesEvalsToResolve:    rule.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: model.Duration(rule.MissingSeriesDurationToResolve),
 	}
 }
 
@@ -105,6 +115,7 @@ func AlertRuleGroupFromApiAlertRuleGroup(a definitions.AlertRuleGroup) (models.A
 		Title:     a.Title,
 		FolderUID: a.FolderUID,
 		Interval:  a.Interval,
+		Labels:    a.Labels,
 	}
 	for i := range a.Rules {
 		converted, err := AlertRuleFromProvisionedAlertRule(a.Rules[i])
@@ -125,6 +136,7 @@ func ApiAlertRuleGroupFromAlertRuleGroup(d models.AlertRuleGroup) definitions.Al
 		Title:     d.Title,
 		FolderUID: d.FolderUID,
 		Interval:  d.Interval,
+		Labels:    d.Labels,
 		Rules:     rules,
 	}
 }
@@ -159,6 +171,7 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle(d models.AlertRuleGro
 		FolderUID:       d.FolderUID,
 		Interval:        model.Duration(time.Duration(d.Interval) * time.Second),
 		IntervalSeconds: d.Interval,
+		Labels:          d.Labels,
 		Rules:           rules,
 	}, nil
 }
@@ -175,16 +188,24 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	}
 
 	result := definitions.AlertRuleExport{
-		UID:          rule.UID,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         data,
-		DashboardUID: rule.DashboardUID,
-		PanelID:      rule.PanelID,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState),
-		IsPaused:     rule.IsPaused,
+		UID:               rule.UID,
+		Title:             rule.Title,
+		For:               model.Duration(rule.For),
+		Condition:         rule.Condition,
+		Data:              data,
+		DashboardUID:      rule.DashboardUID,
+		PanelID:           rule.PanelID,
+		NoDataState:       definitions.NoDataState(rule.NoDataState),
+		ExecErrState:      definitions.ExecutionErrorState(rule.ExecErrState),
+		IsPaused:          rule.IsPaused,
+		FingerprintLabels: rule.FingerprintLabels,
+		PartialResults:    rule.PartialResults,
+
+		MissingSeriesEvalsToResolve:    rule.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: model.Duration(rule.MissingSeriesDurationToResolve),
+	}
+	if rule.UID != "" {
+		result.UIDString = util.Pointer(rule.UID)
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
@@ -312,6 +333,7 @@ func RouteExportFromRoute(route *definitions.Route) *definitions.RouteExport {
 		GroupWait:           toStringIfNotNil(route.GroupWait),
 		GroupInterval:       toStringIfNotNil(route.GroupInterval),
 		RepeatInterval:      toStringIfNotNil(route.RepeatInterval),
+		Description:         route.Description,
 	}
 
 	if len(route.Routes) > 0 {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
@@ -35,3 +36,34 @@ func TestToModel(t *testing.T) {
 		require.Len(t, tm.Rules, 1)
 	})
 }
+
+func TestRouteExportKeepsDescriptions(t *testing.T) {
+	route := definitions.Route{
+		Receiver:    "default",
+		Description: "Root policy, owned by the platform team",
+		Routes: []*definitions.Route{
+			{
+				Receiver:    "team-a",
+				Description: "Escalation of the alerts of team A",
+				Routes: []*definitions.Route{
+					{Receiver: "team-a-pager", Description: "Pages the on-call engineer of team A"},
+				},
+			},
+			{Receiver: "team-b"},
+		},
+	}
+
+	export, err := AlertingFileExportFromRoute(1, route)
+	require.NoError(t, err)
+	data, err := yaml.Marshal(export.Policies[0].RouteExport)
+	require.NoError(t, err)
+
+	var imported definitions.Route
+	require.NoError(t, yaml.Unmarshal(data, &imported))
+	require.Equal(t, route.Description, imported.Description)
+	require.Len(t, imported.Routes, 2)
+	require.Equal(t, route.Routes[0].Description, imported.Routes[0].Description)
+	require.Equal(t, route.Routes[0].Routes[0].Description, imported.Routes[0].Routes[0].Description)
+	require.Empty(t, imported.Routes[1].Description)
+	require.NotContains(t, string(data), "description: \"\"")
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"compress/gzip"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// gzipMinSize is the size of the smallest body that is compressed. Smaller bodies are not worth the overhead.
+const gzipMinSize = 1024
+
+// gzipResponse compresses the response with gzip if the client accepts it. The body is compressed while it is written,
+// so that large exports are not buffered again. It is used by the endpoints that return large bodies,
+// which are transferred uncompressed unless the server wide gzip middleware is enabled.
+type gzipResponse struct {
+	response.Response
+}
+
+// compressedResponse returns the response so that it is compressed if the client accepts it.
+func compressedResponse(resp response.Response) response.Response {
+	return gzipResponse{Response: resp}
+}
+
+func (r gzipResponse) WriteTo(c *contextmodel.ReqContext) {
+	// The response is already compressed by the gzip middleware.
+	if c.Resp.Header().Get("Content-Encoding") != "" || !acceptsGzip(c.Req.Header.Get("Accept-Encoding")) {
+		r.Response.WriteTo(c)
+		return
+	}
+	// The body of streaming responses is not known in advance, they are always compressed.
+	if body := r.Body(); body != nil && len(body) < gzipMinSize {
+		r.Response.WriteTo(c)
+		return
+	}
+
+	resp := c.Resp
+	gw := gzip.NewWriter(resp)
+	header := resp.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+
+	c.Resp = web.NewResponseWriter(c.Req.Method, &gzipResponseWriter{ResponseWriter: resp, w: gw})
+	defer func() {
+		c.Resp = resp
+	}()
+	r.Response.WriteTo(c)
+	if err := gw.Close(); err != nil {
+		c.Logger.Error("Failed to compress the response", "error", err)
+	}
+}
+
+// gzipResponseWriter writes the body to the gzip writer and everything else to the response writer.
+type gzipResponseWriter struct {
+	web.ResponseWriter
+	w *gzip.Writer
+}
+
+func (grw *gzipResponseWriter) WriteHeader(status int) {
+	grw.Header().Del("Content-Length")
+	grw.ResponseWriter.WriteHeader(status)
+}
+
+func (grw *gzipResponseWriter) Write(p []byte) (int, error) {
+	return grw.w.Write(p)
+}
+
+func (grw *gzipResponseWriter) Flush() {
+	if err := grw.w.Flush(); err != nil {
+		return
+	}
+	grw.ResponseWriter.Flush()
+}
+
+// acceptsGzip returns true if the Accept-Encoding header accepts gzip, either explicitly or with a wildcard.
+func acceptsGzip(acceptEncoding string) bool {
+	accepted := false
+	for _, enc := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(enc, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		ok := true
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				ok = false
+			}
+		}
+		// An explicit gzip takes precedence over the wildcard.
+		if name == "gzip" {
+			return ok
+		}
+		accepted = ok
+	}
+	return accepted
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	testCases := map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"deflate, gzip;q=1.0":  true,
+		"br, *":                true,
+		"deflate":              false,
+		"gzip;q=0":             false,
+		"gzip; q=0.000":        false,
+		"*;q=0, gzip":          true,
+		"gzip;q=0, *":          false,
+		"identity, *;q=0":      false,
+		"GZIP":                 true,
+		"x-gzip, gzipped, foo": false,
+	}
+	for header, expected := range testCases {
+		require.Equalf(t, expected, acceptsGzip(header), "Accept-Encoding: %s", header)
+	}
+}
+
+func TestCompressedResponse(t *testing.T) {
+	body := strings.Repeat("rule: test\n", 200)
+
+	createCtx := func(acceptEncoding string) (*contextmodel.ReqContext, *httptest.ResponseRecorder) {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		return &contextmodel.ReqContext{Context: &web.Context{Req: req, Resp: web.NewResponseWriter(http.MethodGet, recorder)}}, recorder
+	}
+
+	t.Run("should compress the body if the client accepts gzip", func(t *testing.T) {
+		c, recorder := createCtx("gzip")
+		compressedResponse(response.YAML(http.StatusOK, body)).WriteTo(c)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+		require.Equal(t, "text/yaml", recorder.Header().Get("Content-Type"))
+		require.Less(t, recorder.Body.Len(), len(body))
+		r, err := gzip.NewReader(recorder.Body)
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, string(response.YAML(http.StatusOK, body).Body()), string(b))
+	})
+
+	t.Run("should compress streaming responses", func(t *testing.T) {
+		c, recorder := createCtx("gzip")
+		compressedResponse(response.JSONStreaming(http.StatusOK, []string{"a"})).WriteTo(c)
+
+		require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+		r, err := gzip.NewReader(recorder.Body)
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.JSONEq(t, `["a"]`, string(b))
+	})
+
+	t.Run("should not compress the body if the client does not accept gzip", func(t *testing.T) {
+		c, recorder := createCtx("")
+		compressedResponse(response.YAML(http.StatusOK, body)).WriteTo(c)
+
+		require.Empty(t, recorder.Header().Get("Content-Encoding"))
+		require.Equal(t, string(response.YAML(http.StatusOK, body).Body()), recorder.Body.String())
+	})
+
+	t.Run("should not compress small bodies", func(t *testing.T) {
+		c, recorder := createCtx("gzip")
+		compressedResponse(response.JSON(http.StatusNotFound, map[string]string{"message": "not found"})).WriteTo(c)
+
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+		require.Empty(t, recorder.Header().Get("Content-Encoding"))
+		require.JSONEq(t, `{"message":"not found"}`, recorder.Body.String())
+	})
+
+	t.Run("should not compress the body again if it is already compressed", func(t *testing.T) {
+		c, recorder := createCtx("gzip")
+		c.Resp.Header().Set("Content-Encoding", "gzip")
+		compressedResponse(response.YAML(http.StatusOK, body)).WriteTo(c)
+
+		require.Equal(t, string(response.YAML(http.StatusOK, body).Body()), recorder.Body.String())
+	})
+
+	t.Run("should restore the response writer", func(t *testing.T) {
+		c, _ := createCtx("gzip")
+		resp := c.Resp
+		compressedResponse(response.YAML(http.StatusOK, body)).WriteTo(c)
+
+		require.Same(t, resp, c.Resp)
+		require.Equal(t, http.StatusOK, c.Resp.Status())
+	})
+}
@@ -36,3 +36,63 @@ func (f *ConfigurationApiHandler) handleRouteDeleteNGalertConfig(c *contextmodel
 func (f *ConfigurationApiHandler) handleRouteGetStatus(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetAlertingStatus(c)
 }
+
+func (f *ConfigurationApiHandler) handleRouteGetOrgMetrics(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetOrgMetrics(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetOrgCleanupJobs(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetOrgCleanupJobs(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetStateSnapshot(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetStateSnapshot(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostStateSnapshot(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RoutePostStateSnapshot(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetAlertRuleQuotaDistribution(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetAlertRuleQuotaDistribution(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostAlertRuleQuotaRebalance(c *contextmodel.ReqContext, body apimodels.PostableAlertRuleQuotaRebalance) response.Response {
+	return f.grafana.RoutePostAlertRuleQuotaRebalance(c, body)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetReadOnlyMode(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetReadOnlyMode(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePutReadOnlyMode(c *contextmodel.ReqContext, body apimodels.ReadOnlyMode) response.Response {
+	return f.grafana.RoutePutReadOnlyMode(c, body)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetLabelPolicies(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetLabelPolicies(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePutLabelPolicy(c *contextmodel.ReqContext, body apimodels.LabelPolicy) response.Response {
+	return f.grafana.RoutePutLabelPolicy(c, body)
+}
+
+func (f *ConfigurationApiHandler) handleRouteDeleteLabelPolicy(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteDeleteLabelPolicy(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetLabelPolicyViolations(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetLabelPolicyViolations(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetEvaluationPauseWindows(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetEvaluationPauseWindows(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostEvaluationPauseWindow(c *contextmodel.ReqContext, body apimodels.EvaluationPauseWindow) response.Response {
+	return f.grafana.RoutePostEvaluationPauseWindow(c, body)
+}
+
+func (f *ConfigurationApiHandler) handleRouteDeleteEvaluationPauseWindow(c *contextmodel.ReqContext, id string) response.Response {
+	return f.grafana.RouteDeleteEvaluationPauseWindow(c, id)
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+// queryFields selects the fields of the responses of endpoints that return large bodies, such as the list of rules.
+// It is a comma separated list of paths of fields, as named in the format of the response, for example data.groups.name,data.groups.rules.state.
+// Arrays are traversed, so a path applies to every element, and the segment * matches any field, which is useful
+// for maps keyed by folder. A selected field is returned with all of its content.
+const queryFields = "fields"
+
+// fieldSelection is a tree of the selected fields of a JSON document.
+type fieldSelection struct {
+	// all is true if the whole value is selected.
+	all      bool
+	children map[string]*fieldSelection
+}
+
+// parseFieldSelection returns the fields selected by the request, or nil if it does not select fields.
+func parseFieldSelection(c *contextmodel.ReqContext) (*fieldSelection, error) {
+	var result *fieldSelection
+	for _, value := range c.QueryStrings(queryFields) {
+		for _, path := range strings.Split(value, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			segments := strings.Split(path, ".")
+			for _, s := range segments {
+				if s == "" {
+					return nil, fmt.Errorf("invalid field %q", path)
+				}
+			}
+			if result == nil {
+				result = &fieldSelection{}
+			}
+			result.add(segments)
+		}
+	}
+	return result, nil
+}
+
+func (s *fieldSelection) add(path []string) {
+	if len(path) == 0 {
+		s.all = true
+		return
+	}
+	if s.children == nil {
+		s.children = make(map[string]*fieldSelection)
+	}
+	child, ok := s.children[path[0]]
+	if !ok {
+		child = &fieldSelection{}
+		s.children[path[0]] = child
+	}
+	child.add(path[1:])
+}
+
+// merge returns the union of the selections, either of which can be nil.
+func (s *fieldSelection) merge(other *fieldSelection) *fieldSelection {
+	if s == nil {
+		return other
+	}
+	if other == nil {
+		return s
+	}
+	result := &fieldSelection{all: s.all || other.all, children: make(map[string]*fieldSelection, len(s.children)+len(other.children))}
+	for k, v := range s.children {
+		result.children[k] = v
+	}
+	for k, v := range other.children {
+		result.children[k] = result.children[k].merge(v)
+	}
+	return result
+}
+
+// apply returns the selected fields of a value decoded from JSON.
+func (s *fieldSelection) apply(value any) any {
+	if s.all {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(s.children))
+		for k, field := range v {
+			child := s.children[k].merge(s.children["*"])
+			if child == nil {
+				continue
+			}
+			result[k] = child.apply(field)
+		}
+		return result
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, e := range v {
+			result = append(result, s.apply(e))
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// selectFields returns the selected fields of the body, as it is encoded in the format, either json or yaml.
+// The field names of both formats can differ, for example in exports.
+func selectFields(body any, fields *fieldSelection, format string) (any, error) {
+	var decoded any
+	if format == "yaml" {
+		b, err := yaml.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(b, &decoded); err != nil {
+			return nil, err
+		}
+		return fields.apply(decoded), nil
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return fields.apply(decoded), nil
+}
+
+// selectedFieldsJSON returns the body as JSON, trimmed to the fields selected by the request.
+func selectedFieldsJSON(c *contextmodel.ReqContext, status int, body any) response.Response {
+	fields, err := parseFieldSelection(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if fields == nil {
+		return response.JSON(status, body)
+	}
+	selected, err := selectFields(body, fields, "json")
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to select the fields of the response")
+	}
+	return response.JSON(status, selected)
+}
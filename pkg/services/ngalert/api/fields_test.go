@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func createRequestCtxWithFields(t *testing.T, fields string) *contextmodel.ReqContext {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "/?fields="+fields, nil)
+	require.NoError(t, err)
+	return &contextmodel.ReqContext{Context: &web.Context{Req: req}}
+}
+
+func TestSelectedFieldsJSON(t *testing.T) {
+	body := map[string]any{
+		"status": "success",
+		"data": map[string]any{
+			"groups": []any{
+				map[string]any{"name": "group", "interval": 60, "rules": []any{
+					map[string]any{"name": "rule", "state": "firing", "query": "up", "labels": map[string]any{"team": "a"}},
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		fields   string
+		expected string
+	}{{
+		name:     "should return the whole body without fields",
+		fields:   "",
+		expected: `{"status":"success","data":{"groups":[{"name":"group","interval":60,"rules":[{"name":"rule","state":"firing","query":"up","labels":{"team":"a"}}]}]}}`,
+	}, {
+		name:     "should traverse arrays",
+		fields:   "status,data.groups.name,data.groups.rules.name,data.groups.rules.state",
+		expected: `{"status":"success","data":{"groups":[{"name":"group","rules":[{"name":"rule","state":"firing"}]}]}}`,
+	}, {
+		name:     "should return the whole content of selected fields",
+		fields:   "data.groups.rules.labels",
+		expected: `{"data":{"groups":[{"rules":[{"labels":{"team":"a"}}]}]}}`,
+	}, {
+		name:     "should match any field with a wildcard",
+		fields:   "*.groups.interval",
+		expected: `{"data":{"groups":[{"interval":60}]}}`,
+	}, {
+		name:     "should ignore unknown fields",
+		fields:   "unknown,data.unknown",
+		expected: `{"data":{}}`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := selectedFieldsJSON(createRequestCtxWithFields(t, tc.fields), http.StatusOK, body)
+			require.Equal(t, http.StatusOK, resp.Status())
+			require.JSONEq(t, tc.expected, string(resp.Body()))
+		})
+	}
+
+	t.Run("should reject empty path segments", func(t *testing.T) {
+		resp := selectedFieldsJSON(createRequestCtxWithFields(t, "data..groups"), http.StatusOK, body)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should keep large integers", func(t *testing.T) {
+		resp := selectedFieldsJSON(createRequestCtxWithFields(t, "id"), http.StatusOK, map[string]int64{"id": 9007199254740993})
+		var result map[string]json.Number
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, json.Number("9007199254740993"), result["id"])
+	})
+}
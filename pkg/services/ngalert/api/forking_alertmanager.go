@@ -139,7 +139,7 @@ func (f *AlertmanagerApiHandler) handleRouteDeleteGrafanaAlertingConfig(ctx *con
 	return f.GrafanaSvc.RouteDeleteAlertingConfig(ctx)
 }
 
-func (f *AlertmanagerApiHandler) handleRouteCreateGrafanaSilence(ctx *contextmodel.ReqContext, body apimodels.PostableSilence) response.Response {
+func (f *AlertmanagerApiHandler) handleRouteCreateGrafanaSilence(ctx *contextmodel.ReqContext, body apimodels.PostableGrafanaSilence) response.Response {
 	return f.GrafanaSvc.RouteCreateSilence(ctx, body)
 }
 
@@ -190,6 +190,18 @@ func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contex
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
 
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaFireDrill(ctx *contextmodel.ReqContext, conf apimodels.FireDrillBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostFireDrill(ctx, conf)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaGroupingPreview(ctx *contextmodel.ReqContext, conf apimodels.GroupingPreviewBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostGroupingPreview(ctx, conf)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaPurgeExpiredSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RoutePostPurgeExpiredSilences(ctx)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *contextmodel.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
 }
@@ -42,6 +42,22 @@ func (f *PrometheusApiHandler) handleRouteGetGrafanaAlertStatuses(ctx *contextmo
 	return f.GrafanaSvc.RouteGetAlertStatuses(ctx)
 }
 
+func (f *PrometheusApiHandler) handleRouteGetGrafanaBuildInfo(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetBuildInfo(ctx)
+}
+
+func (f *PrometheusApiHandler) handleRouteGetGrafanaMetricMetadata(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetMetricMetadata(ctx)
+}
+
+func (f *PrometheusApiHandler) handleRouteGetGrafanaRuleHealth(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetRuleHealth(ctx)
+}
+
+func (f *PrometheusApiHandler) handleRouteGetGrafanaRuleScheduling(ctx *contextmodel.ReqContext, namespace string) response.Response {
+	return f.GrafanaSvc.RouteGetRuleScheduling(ctx, namespace)
+}
+
 func (f *PrometheusApiHandler) handleRouteGetGrafanaRuleStatuses(ctx *contextmodel.ReqContext) response.Response {
 	return f.GrafanaSvc.RouteGetRuleStatuses(ctx)
 }
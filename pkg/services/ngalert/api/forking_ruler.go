@@ -12,13 +12,16 @@ type RulerApiHandler struct {
 	LotexRuler      *LotexRuler
 	GrafanaRuler    *RulerSrv
 	DatasourceCache datasources.CacheService
+
+	idempotency *idempotencyCache
 }
 
-func NewForkingRuler(datasourceCache datasources.CacheService, lotex *LotexRuler, grafana *RulerSrv) *RulerApiHandler {
+func NewForkingRuler(datasourceCache datasources.CacheService, lotex *LotexRuler, grafana *RulerSrv, idempotency *idempotencyCache) *RulerApiHandler {
 	return &RulerApiHandler{
 		LotexRuler:      lotex,
 		GrafanaRuler:    grafana,
 		DatasourceCache: datasourceCache,
+		idempotency:     idempotency,
 	}
 }
 
@@ -74,11 +77,15 @@ func (f *RulerApiHandler) handleRoutePostNameRulesConfig(ctx *contextmodel.ReqCo
 }
 
 func (f *RulerApiHandler) handleRouteDeleteNamespaceGrafanaRulesConfig(ctx *contextmodel.ReqContext, namespace string) response.Response {
-	return f.GrafanaRuler.RouteDeleteAlertRules(ctx, namespace, "")
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.GrafanaRuler.RouteDeleteAlertRules(ctx, namespace, "")
+	})
 }
 
 func (f *RulerApiHandler) handleRouteDeleteGrafanaRuleGroupConfig(ctx *contextmodel.ReqContext, namespace, groupName string) response.Response {
-	return f.GrafanaRuler.RouteDeleteAlertRules(ctx, namespace, groupName)
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.GrafanaRuler.RouteDeleteAlertRules(ctx, namespace, groupName)
+	})
 }
 
 func (f *RulerApiHandler) handleRouteGetNamespaceGrafanaRulesConfig(ctx *contextmodel.ReqContext, namespace string) response.Response {
@@ -98,7 +105,9 @@ func (f *RulerApiHandler) handleRoutePostNameGrafanaRulesConfig(ctx *contextmode
 	if payloadType != apimodels.GrafanaBackend {
 		return errorToResponse(backendTypeDoesNotMatchPayloadTypeError(apimodels.GrafanaBackend, conf.Type().String()))
 	}
-	return f.GrafanaRuler.RoutePostNameRulesConfig(ctx, conf, namespace)
+	return f.idempotency.Do(ctx, conf, func() response.Response {
+		return f.GrafanaRuler.RoutePostNameRulesConfig(ctx, conf, namespace)
+	})
 }
 
 func (f *RulerApiHandler) handleRoutePostRulesGroupForExport(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleGroupConfig, namespace string) response.Response {
@@ -113,6 +122,46 @@ func (f *RulerApiHandler) handleRouteGetRulesForExport(ctx *contextmodel.ReqCont
 	return f.GrafanaRuler.ExportRules(ctx)
 }
 
+func (f *RulerApiHandler) handleRouteGetRuleForExport(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.ExportRule(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostRulesImport(ctx *contextmodel.ReqContext, conf apimodels.PostableRulesImport) response.Response {
+	return f.GrafanaRuler.RoutePostRulesImport(ctx, conf)
+}
+
+func (f *RulerApiHandler) handleRoutePostRenameRuleGroup(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleGroupRename, namespace, groupName string) response.Response {
+	return f.idempotency.Do(ctx, conf, func() response.Response {
+		return f.GrafanaRuler.RoutePostRenameRuleGroup(ctx, conf, namespace, groupName)
+	})
+}
+
+func (f *RulerApiHandler) handleRoutePostCloneRuleGroup(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleGroupClone, namespace, groupName string) response.Response {
+	return f.idempotency.Do(ctx, conf, func() response.Response {
+		return f.GrafanaRuler.RoutePostCloneRuleGroup(ctx, conf, namespace, groupName)
+	})
+}
+
+func (f *RulerApiHandler) handleRoutePostPauseRules(ctx *contextmodel.ReqContext, conf apimodels.PostableRulesPause) response.Response {
+	return f.idempotency.Do(ctx, conf, func() response.Response {
+		return f.GrafanaRuler.RoutePostPauseRules(ctx, conf)
+	})
+}
+
+func (f *RulerApiHandler) handleRouteGetRuleGroupVersions(ctx *contextmodel.ReqContext, namespace, groupName string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleGroupVersions(ctx, namespace, groupName)
+}
+
+func (f *RulerApiHandler) handleRoutePostRuleVersionRestore(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleVersionRestore, namespace, groupName string) response.Response {
+	return f.idempotency.Do(ctx, conf, func() response.Response {
+		return f.GrafanaRuler.RoutePostRuleVersionRestore(ctx, conf, namespace, groupName)
+	})
+}
+
+func (f *RulerApiHandler) handleRouteConvertDatadogMonitors(ctx *contextmodel.ReqContext, conf apimodels.DatadogMonitors) response.Response {
+	return f.GrafanaRuler.RouteConvertDatadogMonitors(ctx, conf)
+}
+
 func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
@@ -20,25 +20,88 @@ import (
 )
 
 type ConfigurationApi interface {
+	RouteDeleteEvaluationPauseWindow(*contextmodel.ReqContext) response.Response
+	RouteDeleteLabelPolicy(*contextmodel.ReqContext) response.Response
 	RouteDeleteNGalertConfig(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleQuotaDistribution(*contextmodel.ReqContext) response.Response
 	RouteGetAlertmanagers(*contextmodel.ReqContext) response.Response
+	RouteGetEvaluationPauseWindows(*contextmodel.ReqContext) response.Response
+	RouteGetLabelPolicies(*contextmodel.ReqContext) response.Response
+	RouteGetLabelPolicyViolations(*contextmodel.ReqContext) response.Response
 	RouteGetNGalertConfig(*contextmodel.ReqContext) response.Response
+	RouteGetOrgCleanupJobs(*contextmodel.ReqContext) response.Response
+	RouteGetOrgMetrics(*contextmodel.ReqContext) response.Response
+	RouteGetReadOnlyMode(*contextmodel.ReqContext) response.Response
+	RouteGetStateSnapshot(*contextmodel.ReqContext) response.Response
 	RouteGetStatus(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleQuotaRebalance(*contextmodel.ReqContext) response.Response
+	RoutePostEvaluationPauseWindow(*contextmodel.ReqContext) response.Response
 	RoutePostNGalertConfig(*contextmodel.ReqContext) response.Response
+	RoutePostStateSnapshot(*contextmodel.ReqContext) response.Response
+	RoutePutLabelPolicy(*contextmodel.ReqContext) response.Response
+	RoutePutReadOnlyMode(*contextmodel.ReqContext) response.Response
 }
 
+func (f *ConfigurationApiHandler) RouteDeleteEvaluationPauseWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	iDParam := web.Params(ctx.Req)[":ID"]
+	return f.handleRouteDeleteEvaluationPauseWindow(ctx, iDParam)
+}
+func (f *ConfigurationApiHandler) RouteDeleteLabelPolicy(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteDeleteLabelPolicy(ctx)
+}
 func (f *ConfigurationApiHandler) RouteDeleteNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteDeleteNGalertConfig(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetAlertRuleQuotaDistribution(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRuleQuotaDistribution(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetAlertmanagers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertmanagers(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetEvaluationPauseWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetEvaluationPauseWindows(ctx)
+}
+func (f *ConfigurationApiHandler) RouteGetLabelPolicies(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetLabelPolicies(ctx)
+}
+func (f *ConfigurationApiHandler) RouteGetLabelPolicyViolations(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetLabelPolicyViolations(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetNGalertConfig(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetOrgCleanupJobs(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetOrgCleanupJobs(ctx)
+}
+func (f *ConfigurationApiHandler) RouteGetOrgMetrics(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetOrgMetrics(ctx)
+}
+func (f *ConfigurationApiHandler) RouteGetReadOnlyMode(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetReadOnlyMode(ctx)
+}
+func (f *ConfigurationApiHandler) RouteGetStateSnapshot(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetStateSnapshot(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetStatus(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetStatus(ctx)
 }
+func (f *ConfigurationApiHandler) RoutePostAlertRuleQuotaRebalance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PostableAlertRuleQuotaRebalance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleQuotaRebalance(ctx, conf)
+}
+func (f *ConfigurationApiHandler) RoutePostEvaluationPauseWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.EvaluationPauseWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostEvaluationPauseWindow(ctx, conf)
+}
 func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.PostableNGalertConfig{}
@@ -47,9 +110,52 @@ func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqCo
 	}
 	return f.handleRoutePostNGalertConfig(ctx, conf)
 }
+func (f *ConfigurationApiHandler) RoutePostStateSnapshot(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRoutePostStateSnapshot(ctx)
+}
+func (f *ConfigurationApiHandler) RoutePutLabelPolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.LabelPolicy{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutLabelPolicy(ctx, conf)
+}
+func (f *ConfigurationApiHandler) RoutePutReadOnlyMode(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ReadOnlyMode{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutReadOnlyMode(ctx, conf)
+}
 
 func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/pause_windows/{ID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/ngalert/pause_windows/{ID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/pause_windows/{ID}",
+				api.Hooks.Wrap(srv.RouteDeleteEvaluationPauseWindow),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/label_policies"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/ngalert/label_policies"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/label_policies",
+				api.Hooks.Wrap(srv.RouteDeleteLabelPolicy),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -62,6 +168,30 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/metrics"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/metrics"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/metrics",
+				api.Hooks.Wrap(srv.RouteGetOrgMetrics),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/cleanup/orgs"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/cleanup/orgs"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/cleanup/orgs",
+				api.Hooks.Wrap(srv.RouteGetOrgCleanupJobs),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/alertmanagers"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -74,6 +204,42 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/pause_windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/pause_windows"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/pause_windows",
+				api.Hooks.Wrap(srv.RouteGetEvaluationPauseWindows),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/label_policies"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/label_policies"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/label_policies",
+				api.Hooks.Wrap(srv.RouteGetLabelPolicies),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/label_policies/violations"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/label_policies/violations"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/label_policies/violations",
+				api.Hooks.Wrap(srv.RouteGetLabelPolicyViolations),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -86,6 +252,30 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/quota/alert_rule"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/quota/alert_rule"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/quota/alert_rule",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleQuotaDistribution),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/read_only"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/read_only"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/read_only",
+				api.Hooks.Wrap(srv.RouteGetReadOnlyMode),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -98,6 +288,30 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/state/snapshot"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/state/snapshot"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/state/snapshot",
+				api.Hooks.Wrap(srv.RouteGetStateSnapshot),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/pause_windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/pause_windows"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/pause_windows",
+				api.Hooks.Wrap(srv.RoutePostEvaluationPauseWindow),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -110,5 +324,53 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/quota/alert_rule/rebalance"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/quota/alert_rule/rebalance"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/quota/alert_rule/rebalance",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleQuotaRebalance),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/state/snapshot"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/state/snapshot"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/state/snapshot",
+				api.Hooks.Wrap(srv.RoutePostStateSnapshot),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/ngalert/label_policies"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/ngalert/label_policies"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/ngalert/label_policies",
+				api.Hooks.Wrap(srv.RoutePutLabelPolicy),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/ngalert/read_only"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/ngalert/read_only"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/ngalert/read_only",
+				api.Hooks.Wrap(srv.RoutePutReadOnlyMode),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -20,6 +20,7 @@ import (
 
 type NotificationsApi interface {
 	RouteGetReceiver(*contextmodel.ReqContext) response.Response
+	RouteGetReceiverUsage(*contextmodel.ReqContext) response.Response
 	RouteGetReceivers(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeInterval(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeIntervals(*contextmodel.ReqContext) response.Response
@@ -30,6 +31,11 @@ func (f *NotificationsApiHandler) RouteGetReceiver(ctx *contextmodel.ReqContext)
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.handleRouteGetReceiver(ctx, nameParam)
 }
+func (f *NotificationsApiHandler) RouteGetReceiverUsage(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteGetReceiverUsage(ctx, nameParam)
+}
 func (f *NotificationsApiHandler) RouteGetReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetReceivers(ctx)
 }
@@ -56,6 +62,18 @@ func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/notifications/receivers/{Name}/usage"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/notifications/receivers/{Name}/usage"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/notifications/receivers/{Name}/usage",
+				api.Hooks.Wrap(srv.RouteGetReceiverUsage),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/notifications/receivers"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -21,6 +21,10 @@ import (
 type PrometheusApi interface {
 	RouteGetAlertStatuses(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaAlertStatuses(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaBuildInfo(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaMetricMetadata(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaRuleHealth(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaRuleScheduling(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaRuleStatuses(*contextmodel.ReqContext) response.Response
 	RouteGetRuleStatuses(*contextmodel.ReqContext) response.Response
 }
@@ -33,6 +37,20 @@ func (f *PrometheusApiHandler) RouteGetAlertStatuses(ctx *contextmodel.ReqContex
 func (f *PrometheusApiHandler) RouteGetGrafanaAlertStatuses(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaAlertStatuses(ctx)
 }
+func (f *PrometheusApiHandler) RouteGetGrafanaBuildInfo(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaBuildInfo(ctx)
+}
+func (f *PrometheusApiHandler) RouteGetGrafanaMetricMetadata(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaMetricMetadata(ctx)
+}
+func (f *PrometheusApiHandler) RouteGetGrafanaRuleHealth(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaRuleHealth(ctx)
+}
+func (f *PrometheusApiHandler) RouteGetGrafanaRuleScheduling(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	namespaceParam := web.Params(ctx.Req)[":Namespace"]
+	return f.handleRouteGetGrafanaRuleScheduling(ctx, namespaceParam)
+}
 func (f *PrometheusApiHandler) RouteGetGrafanaRuleStatuses(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaRuleStatuses(ctx)
 }
@@ -68,6 +86,54 @@ func (api *API) RegisterPrometheusApiEndpoints(srv PrometheusApi, m *metrics.API
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/prometheus/grafana/api/v1/status/buildinfo"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/prometheus/grafana/api/v1/status/buildinfo"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/prometheus/grafana/api/v1/status/buildinfo",
+				api.Hooks.Wrap(srv.RouteGetGrafanaBuildInfo),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/prometheus/grafana/api/v1/metadata"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/prometheus/grafana/api/v1/metadata"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/prometheus/grafana/api/v1/metadata",
+				api.Hooks.Wrap(srv.RouteGetGrafanaMetricMetadata),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/rules/health"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/rules/health"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rules/health",
+				api.Hooks.Wrap(srv.RouteGetGrafanaRuleHealth),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/rules/scheduling/{Namespace}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/rules/scheduling/{Namespace}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rules/scheduling/{Namespace}",
+				api.Hooks.Wrap(srv.RouteGetGrafanaRuleScheduling),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/prometheus/grafana/api/v1/rules"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
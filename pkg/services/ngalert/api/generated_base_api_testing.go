@@ -21,7 +21,15 @@ import (
 
 type TestingApi interface {
 	BacktestConfig(*contextmodel.ReqContext) response.Response
+	LoadTestConfig(*contextmodel.ReqContext) response.Response
+	PanelAlertPreviewConfig(*contextmodel.ReqContext) response.Response
+	RangePreviewConfig(*contextmodel.ReqContext) response.Response
+	RouteCreateBacktestJob(*contextmodel.ReqContext) response.Response
+	RouteDeleteBacktestJob(*contextmodel.ReqContext) response.Response
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
+	RouteGetBacktestJob(*contextmodel.ReqContext) response.Response
+	RouteGetBacktestJobResult(*contextmodel.ReqContext) response.Response
+	RouteGetTemplateFunctions(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
 }
@@ -34,6 +42,43 @@ func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) respons
 	}
 	return f.handleBacktestConfig(ctx, conf)
 }
+func (f *TestingApiHandler) LoadTestConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.LoadTestConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleLoadTestConfig(ctx, conf)
+}
+func (f *TestingApiHandler) PanelAlertPreviewConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PanelAlertPreviewConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handlePanelAlertPreviewConfig(ctx, conf)
+}
+func (f *TestingApiHandler) RangePreviewConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RangePreviewConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRangePreviewConfig(ctx, conf)
+}
+func (f *TestingApiHandler) RouteCreateBacktestJob(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BacktestConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteCreateBacktestJob(ctx, conf)
+}
+func (f *TestingApiHandler) RouteDeleteBacktestJob(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	jobIDParam := web.Params(ctx.Req)[":JobID"]
+	return f.handleRouteDeleteBacktestJob(ctx, jobIDParam)
+}
 func (f *TestingApiHandler) RouteEvalQueries(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EvalQueriesPayload{}
@@ -42,6 +87,19 @@ func (f *TestingApiHandler) RouteEvalQueries(ctx *contextmodel.ReqContext) respo
 	}
 	return f.handleRouteEvalQueries(ctx, conf)
 }
+func (f *TestingApiHandler) RouteGetBacktestJob(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	jobIDParam := web.Params(ctx.Req)[":JobID"]
+	return f.handleRouteGetBacktestJob(ctx, jobIDParam)
+}
+func (f *TestingApiHandler) RouteGetBacktestJobResult(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	jobIDParam := web.Params(ctx.Req)[":JobID"]
+	return f.handleRouteGetBacktestJobResult(ctx, jobIDParam)
+}
+func (f *TestingApiHandler) RouteGetTemplateFunctions(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetTemplateFunctions(ctx)
+}
 func (f *TestingApiHandler) RouteTestRuleConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -75,6 +133,66 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/loadtest"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/loadtest"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/loadtest",
+				api.Hooks.Wrap(srv.LoadTestConfig),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/preview/panel"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/preview/panel"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/preview/panel",
+				api.Hooks.Wrap(srv.PanelAlertPreviewConfig),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/preview/range"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/preview/range"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/preview/range",
+				api.Hooks.Wrap(srv.RangePreviewConfig),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest/jobs"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest/jobs"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest/jobs",
+				api.Hooks.Wrap(srv.RouteCreateBacktestJob),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/rule/backtest/jobs/{JobID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/rule/backtest/jobs/{JobID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/rule/backtest/jobs/{JobID}",
+				api.Hooks.Wrap(srv.RouteDeleteBacktestJob),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/eval"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -87,6 +205,42 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/rule/backtest/jobs/{JobID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/rule/backtest/jobs/{JobID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rule/backtest/jobs/{JobID}",
+				api.Hooks.Wrap(srv.RouteGetBacktestJob),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/rule/backtest/jobs/{JobID}/result"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/rule/backtest/jobs/{JobID}/result"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rule/backtest/jobs/{JobID}/result",
+				api.Hooks.Wrap(srv.RouteGetBacktestJobResult),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/rule/template/functions"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/rule/template/functions"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rule/template/functions",
+				api.Hooks.Wrap(srv.RouteGetTemplateFunctions),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/test/{DatasourceUID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+const (
+	// IdempotencyKeyHeader is the header with which clients make the retries of a write request safe.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses that are replayed for a request with a known idempotency key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotencyEntries is the number of responses that are kept in memory.
+	maxIdempotencyEntries = 10000
+	// idempotencyKVNamespace is the kvstore namespace of the responses shared between servers.
+	idempotencyKVNamespace = "alerting.idempotency"
+)
+
+type idempotentResponse struct {
+	// fingerprint identifies the request, so that a key cannot be reused for a different request.
+	fingerprint [sha256.Size]byte
+	// response is nil while the request is being handled.
+	response *response.NormalResponse
+	expires  time.Time
+}
+
+// storedIdempotentResponse is the form in which responses are shared with other servers through the kvstore.
+type storedIdempotentResponse struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	Expires     time.Time   `json:"expires"`
+}
+
+// idempotencyCache keeps the responses of write requests that have an idempotency key, so that
+// the retries of a request get the same response instead of applying the changes again.
+// Keys are scoped to the organization and the identity that made the request.
+// Responses are kept in memory, up to maxEntries, and in the kvstore if one is set, so that a retry that
+// reaches another server in HA, or the same server after a restart, is replayed too.
+type idempotencyCache struct {
+	ttl        time.Duration
+	maxEntries int
+	clock      clock.Clock
+	kv         kvstore.KVStore
+	log        log.Logger
+
+	mtx       sync.Mutex
+	responses map[string]*idempotentResponse
+	lastPurge time.Time
+}
+
+// newIdempotencyCache returns a cache that keeps the responses for the given duration.
+// It returns nil if the duration is not positive, which makes requests ignore their idempotency key.
+// The kvstore is optional.
+func newIdempotencyCache(ttl time.Duration, clk clock.Clock, kv kvstore.KVStore, logger log.Logger) *idempotencyCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &idempotencyCache{
+		ttl:        ttl,
+		maxEntries: maxIdempotencyEntries,
+		clock:      clk,
+		kv:         kv,
+		log:        logger,
+		responses:  make(map[string]*idempotentResponse),
+		lastPurge:  clk.Now(),
+	}
+}
+
+// Do calls the handler of the request, unless a response to a request with the same idempotency key is known,
+// in which case that response is returned. The body is the parsed body of the request, if any.
+// Server errors are not kept, so that the request can be retried with the same key.
+func (c *idempotencyCache) Do(ctx *contextmodel.ReqContext, body any, handler func() response.Response) response.Response {
+	key := ctx.Req.Header.Get(IdempotencyKeyHeader)
+	if c == nil || key == "" {
+		return handler()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("%s header must not be longer than %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength), "")
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to compute the fingerprint of the request")
+	}
+	orgID := ctx.SignedInUser.GetOrgID()
+	namespace, id := ctx.SignedInUser.GetNamespacedID()
+	cacheKey := fmt.Sprintf("%d/%s:%s/%s", orgID, namespace, id, key)
+	fingerprint := sha256.Sum256([]byte(ctx.Req.Method + " " + ctx.Req.URL.Path + "\n" + string(payload)))
+
+	c.mtx.Lock()
+	now := c.clock.Now()
+	c.evictExpired(now)
+	existing, ok := c.responses[cacheKey]
+	if !ok {
+		// the kvstore is not read with the mutex held, the response is only kept if no request was started meanwhile
+		c.mtx.Unlock()
+		stored := c.load(ctx.Req.Context(), orgID, cacheKey, now)
+		c.mtx.Lock()
+		if existing, ok = c.responses[cacheKey]; !ok && stored != nil {
+			c.responses[cacheKey] = stored
+			existing, ok = stored, true
+		}
+	}
+	if ok {
+		c.mtx.Unlock()
+		if existing.fingerprint != fingerprint {
+			return ErrResp(http.StatusUnprocessableEntity, fmt.Errorf("%s header was already used for a different request", IdempotencyKeyHeader), "")
+		}
+		if existing.response == nil {
+			return ErrResp(http.StatusConflict, fmt.Errorf("a request with the same %s header is in progress", IdempotencyKeyHeader), "")
+		}
+		replayed := response.CreateNormalResponse(existing.response.Header().Clone(), existing.response.Body(), existing.response.Status())
+		return replayed.SetHeader(IdempotentReplayedHeader, "true")
+	}
+	if !c.makeRoom() {
+		// every entry is a request in progress, the request is handled without keeping its response
+		c.mtx.Unlock()
+		c.log.Warn("Too many requests with an idempotency key are in progress, the response is not kept", "limit", c.maxEntries)
+		return handler()
+	}
+	entry := &idempotentResponse{fingerprint: fingerprint, expires: now.Add(c.ttl)}
+	c.responses[cacheKey] = entry
+	c.mtx.Unlock()
+
+	resp := handler()
+
+	c.mtx.Lock()
+	normal, ok := resp.(*response.NormalResponse)
+	if !ok || resp.Status() >= http.StatusInternalServerError {
+		delete(c.responses, cacheKey)
+		c.mtx.Unlock()
+		return resp
+	}
+	entry.response = normal
+	entry.expires = c.clock.Now().Add(c.ttl)
+	c.mtx.Unlock()
+	c.store(ctx.Req.Context(), orgID, cacheKey, entry)
+	return resp
+}
+
+// evictExpired removes the responses that expired. It must be called with the mutex held.
+func (c *idempotencyCache) evictExpired(now time.Time) {
+	for key, entry := range c.responses {
+		// requests in progress are never evicted
+		if entry.response != nil && now.After(entry.expires) {
+			delete(c.responses, key)
+		}
+	}
+	if c.kv != nil && now.Sub(c.lastPurge) >= c.ttl {
+		c.lastPurge = now
+		go c.purgeStored(now)
+	}
+}
+
+// makeRoom evicts the response that expires first if the cache is full. Evicted responses can still be replayed
+// from the kvstore. It returns false if the cache is full of requests in progress. It must be called with the mutex held.
+func (c *idempotencyCache) makeRoom() bool {
+	if len(c.responses) < c.maxEntries {
+		return true
+	}
+	var oldestKey string
+	var oldest *idempotentResponse
+	for key, entry := range c.responses {
+		if entry.response != nil && (oldest == nil || entry.expires.Before(oldest.expires)) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	delete(c.responses, oldestKey)
+	return true
+}
+
+// kvKey returns the key of the response in the kvstore. The cache key is hashed to fit in the key column.
+func kvKey(cacheKey string) string {
+	sum := sha256.Sum256([]byte(cacheKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// load returns the response stored in the kvstore, or nil if there is none or it expired.
+func (c *idempotencyCache) load(ctx context.Context, orgID int64, cacheKey string, now time.Time) *idempotentResponse {
+	if c.kv == nil {
+		return nil
+	}
+	value, ok, err := c.kv.Get(ctx, orgID, idempotencyKVNamespace, kvKey(cacheKey))
+	if err != nil {
+		c.log.Warn("Failed to read the response of an idempotency key", "error", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	var stored storedIdempotentResponse
+	if err := json.Unmarshal([]byte(value), &stored); err != nil || now.After(stored.Expires) {
+		return nil
+	}
+	entry := &idempotentResponse{
+		response: response.CreateNormalResponse(stored.Header, stored.Body, stored.Status),
+		expires:  stored.Expires,
+	}
+	if fp, err := hex.DecodeString(stored.Fingerprint); err == nil {
+		copy(entry.fingerprint[:], fp)
+	}
+	return entry
+}
+
+// store saves the response to the kvstore, so that other servers can replay it.
+func (c *idempotencyCache) store(ctx context.Context, orgID int64, cacheKey string, entry *idempotentResponse) {
+	if c.kv == nil {
+		return
+	}
+	value, err := json.Marshal(storedIdempotentResponse{
+		Fingerprint: hex.EncodeToString(entry.fingerprint[:]),
+		Status:      entry.response.Status(),
+		Header:      entry.response.Header(),
+		Body:        entry.response.Body(),
+		Expires:     entry.expires,
+	})
+	if err == nil {
+		err = c.kv.Set(ctx, orgID, idempotencyKVNamespace, kvKey(cacheKey), string(value))
+	}
+	if err != nil {
+		c.log.Warn("Failed to store the response of an idempotency key", "error", err)
+	}
+}
+
+// purgeStored deletes the responses of all organizations that expired from the kvstore.
+func (c *idempotencyCache) purgeStored(now time.Time) {
+	ctx := context.Background()
+	all, err := c.kv.GetAll(ctx, kvstore.AllOrganizations, idempotencyKVNamespace)
+	if err != nil {
+		c.log.Warn("Failed to list the responses of idempotency keys", "error", err)
+		return
+	}
+	for orgID, values := range all {
+		for key, value := range values {
+			var stored storedIdempotentResponse
+			if err := json.Unmarshal([]byte(value), &stored); err == nil && !now.After(stored.Expires) {
+				continue
+			}
+			if err := c.kv.Del(ctx, orgID, idempotencyKVNamespace, key); err != nil {
+				c.log.Warn("Failed to delete the expired response of an idempotency key", "error", err)
+			}
+		}
+	}
+}
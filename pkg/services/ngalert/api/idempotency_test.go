@@ -0,0 +1,162 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	newRequest := func(orgID int64, key string) *contextmodel.ReqContext {
+		ctx := createRequestContext(orgID, nil)
+		ctx.Req.Method = http.MethodPost
+		ctx.Req.URL.Path = "/api/ruler/grafana/api/v1/rules/folder"
+		if key != "" {
+			ctx.Req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		return ctx
+	}
+	counter := func(status int) (*int, func() response.Response) {
+		calls := 0
+		return &calls, func() response.Response {
+			calls++
+			return response.JSON(status, map[string]int{"call": calls})
+		}
+	}
+	body := map[string]string{"name": "group"}
+
+	t.Run("should replay the response to a request with the same key", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Minute, clock.NewMock(), nil, log.NewNopLogger())
+		calls, handler := counter(http.StatusAccepted)
+
+		first := cache.Do(newRequest(1, "key"), body, handler)
+		second := cache.Do(newRequest(1, "key"), body, handler)
+
+		require.Equal(t, 1, *calls)
+		require.Equal(t, http.StatusAccepted, second.Status())
+		require.Equal(t, first.Body(), second.Body())
+		require.Equal(t, "true", second.(*response.NormalResponse).Header().Get(IdempotentReplayedHeader))
+		require.Empty(t, first.(*response.NormalResponse).Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("should call the handler if there is no key or keys are disabled", func(t *testing.T) {
+		calls, handler := counter(http.StatusAccepted)
+
+		cache := newIdempotencyCache(time.Minute, clock.NewMock(), nil, log.NewNopLogger())
+		cache.Do(newRequest(1, ""), body, handler)
+		cache.Do(newRequest(1, ""), body, handler)
+		require.Equal(t, 2, *calls)
+
+		disabled := newIdempotencyCache(0, clock.NewMock(), nil, log.NewNopLogger())
+		require.Nil(t, disabled)
+		disabled.Do(newRequest(1, "key"), body, handler)
+		disabled.Do(newRequest(1, "key"), body, handler)
+		require.Equal(t, 4, *calls)
+	})
+
+	t.Run("should scope keys to the organization", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Minute, clock.NewMock(), nil, log.NewNopLogger())
+		calls, handler := counter(http.StatusAccepted)
+
+		cache.Do(newRequest(1, "key"), body, handler)
+		cache.Do(newRequest(2, "key"), body, handler)
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("should reject a key that is reused for a different request", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Minute, clock.NewMock(), nil, log.NewNopLogger())
+		calls, handler := counter(http.StatusAccepted)
+
+		cache.Do(newRequest(1, "key"), body, handler)
+		resp := cache.Do(newRequest(1, "key"), map[string]string{"name": "other"}, handler)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Status())
+
+		other := newRequest(1, "key")
+		other.Req.URL.Path = "/api/v1/provisioning/alert-rules"
+		resp = cache.Do(other, body, handler)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Status())
+		require.Equal(t, 1, *calls)
+	})
+
+	t.Run("should not keep server errors", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Minute, clock.NewMock(), nil, log.NewNopLogger())
+		calls, handler := counter(http.StatusInternalServerError)
+
+		cache.Do(newRequest(1, "key"), body, handler)
+		cache.Do(newRequest(1, "key"), body, handler)
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("should forget responses after the TTL", func(t *testing.T) {
+		clk := clock.NewMock()
+		cache := newIdempotencyCache(time.Minute, clk, nil, log.NewNopLogger())
+		calls, handler := counter(http.StatusAccepted)
+
+		cache.Do(newRequest(1, "key"), body, handler)
+		clk.Add(time.Minute + time.Second)
+		cache.Do(newRequest(1, "key"), body, handler)
+		require.Equal(t, 2, *calls)
+		require.Len(t, cache.responses, 1)
+	})
+
+	t.Run("should reject a request while one with the same key is in progress", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Minute, clock.NewMock(), nil, log.NewNopLogger())
+		var nested response.Response
+		cache.Do(newRequest(1, "key"), body, func() response.Response {
+			nested = cache.Do(newRequest(1, "key"), body, func() response.Response {
+				return response.JSON(http.StatusAccepted, nil)
+			})
+			return response.JSON(http.StatusAccepted, nil)
+		})
+		require.Equal(t, http.StatusConflict, nested.Status())
+	})
+	t.Run("should replay the responses of other servers from the kvstore", func(t *testing.T) {
+		kv := kvstore.NewFakeKVStore()
+		clk := clock.NewMock()
+		first := newIdempotencyCache(time.Minute, clk, kv, log.NewNopLogger())
+		second := newIdempotencyCache(time.Minute, clk, kv, log.NewNopLogger())
+		calls, handler := counter(http.StatusAccepted)
+
+		original := first.Do(newRequest(1, "key"), body, handler)
+		replayed := second.Do(newRequest(1, "key"), body, handler)
+		require.Equal(t, 1, *calls)
+		require.Equal(t, original.Body(), replayed.Body())
+		require.Equal(t, "true", replayed.(*response.NormalResponse).Header().Get(IdempotentReplayedHeader))
+
+		resp := second.Do(newRequest(1, "key"), map[string]string{"name": "other"}, handler)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Status())
+
+		clk.Add(time.Minute + time.Second)
+		third := newIdempotencyCache(time.Minute, clk, kv, log.NewNopLogger())
+		third.Do(newRequest(1, "key"), body, handler)
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("should keep at most the maximum number of responses in memory", func(t *testing.T) {
+		clk := clock.NewMock()
+		cache := newIdempotencyCache(time.Minute, clk, nil, log.NewNopLogger())
+		cache.maxEntries = 2
+		calls, handler := counter(http.StatusAccepted)
+
+		cache.Do(newRequest(1, "key-1"), body, handler)
+		clk.Add(time.Second)
+		cache.Do(newRequest(1, "key-2"), body, handler)
+		clk.Add(time.Second)
+		cache.Do(newRequest(1, "key-3"), body, handler)
+		require.Len(t, cache.responses, 2)
+
+		// the response that expires first was evicted
+		cache.Do(newRequest(1, "key-1"), body, handler)
+		require.Equal(t, 4, *calls)
+		cache.Do(newRequest(1, "key-3"), body, handler)
+		require.Equal(t, 4, *calls)
+	})
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func labelRewriteRulesFromAPI(rules []apimodels.LabelRewriteRule) ngmodels.LabelRewriteRules {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make(ngmodels.LabelRewriteRules, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, ngmodels.LabelRewriteRule{
+			Label:       r.Label,
+			Action:      ngmodels.LabelRewriteAction(r.Action),
+			Regex:       r.Regex,
+			Replacement: r.Replacement,
+		})
+	}
+	return result
+}
+
+func labelRewriteRulesToAPI(rules ngmodels.LabelRewriteRules) []apimodels.LabelRewriteRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]apimodels.LabelRewriteRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, apimodels.LabelRewriteRule{
+			Label:       r.Label,
+			Action:      string(r.Action),
+			Regex:       r.Regex,
+			Replacement: r.Replacement,
+		})
+	}
+	return result
+}
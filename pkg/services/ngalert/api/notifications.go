@@ -27,6 +27,10 @@ func (f *NotificationsApiHandler) handleRouteGetReceiver(ctx *contextmodel.ReqCo
 	return f.notificationSrv.RouteGetReceiver(ctx, name)
 }
 
+func (f *NotificationsApiHandler) handleRouteGetReceiverUsage(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.notificationSrv.RouteGetReceiverUsage(ctx, name)
+}
+
 func (f *NotificationsApiHandler) handleRouteGetReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.notificationSrv.RouteGetReceivers(ctx)
 }
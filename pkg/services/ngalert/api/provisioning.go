@@ -7,12 +7,14 @@ import (
 )
 
 type ProvisioningApiHandler struct {
-	svc *ProvisioningSrv
+	svc         *ProvisioningSrv
+	idempotency *idempotencyCache
 }
 
-func NewProvisioningApi(svc *ProvisioningSrv) *ProvisioningApiHandler {
+func NewProvisioningApi(svc *ProvisioningSrv, idempotency *idempotencyCache) *ProvisioningApiHandler {
 	return &ProvisioningApiHandler{
-		svc: svc,
+		svc:         svc,
+		idempotency: idempotency,
 	}
 }
 
@@ -25,7 +27,29 @@ func (f *ProvisioningApiHandler) handleRouteGetPolicyTreeExport(ctx *contextmode
 }
 
 func (f *ProvisioningApiHandler) handleRoutePutPolicyTree(ctx *contextmodel.ReqContext, route apimodels.Route) response.Response {
-	return f.svc.RoutePutPolicyTree(ctx, route)
+	return f.idempotency.Do(ctx, route, func() response.Response {
+		return f.svc.RoutePutPolicyTree(ctx, route)
+	})
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetNamedPolicies(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetNamedPolicies(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetNamedPolicy(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteGetNamedPolicy(ctx, name)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutNamedPolicy(ctx *contextmodel.ReqContext, route apimodels.Route, name string) response.Response {
+	return f.idempotency.Do(ctx, route, func() response.Response {
+		return f.svc.RoutePutNamedPolicy(ctx, route, name)
+	})
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteNamedPolicy(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.svc.RouteDeleteNamedPolicy(ctx, name)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteGetContactpoints(ctx *contextmodel.ReqContext) response.Response {
@@ -36,16 +60,26 @@ func (f *ProvisioningApiHandler) handleRouteGetContactpointsExport(ctx *contextm
 	return f.svc.RouteGetContactPointsExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetContactpointsInsights(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetContactPointsInsights(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostContactpoints(ctx *contextmodel.ReqContext, cp apimodels.EmbeddedContactPoint) response.Response {
-	return f.svc.RoutePostContactPoint(ctx, cp)
+	return f.idempotency.Do(ctx, cp, func() response.Response {
+		return f.svc.RoutePostContactPoint(ctx, cp)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRoutePutContactpoint(ctx *contextmodel.ReqContext, cp apimodels.EmbeddedContactPoint, UID string) response.Response {
-	return f.svc.RoutePutContactPoint(ctx, cp, UID)
+	return f.idempotency.Do(ctx, cp, func() response.Response {
+		return f.svc.RoutePutContactPoint(ctx, cp, UID)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteDeleteContactpoints(ctx *contextmodel.ReqContext, UID string) response.Response {
-	return f.svc.RouteDeleteContactPoint(ctx, UID)
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.svc.RouteDeleteContactPoint(ctx, UID)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteGetTemplates(ctx *contextmodel.ReqContext) response.Response {
@@ -57,11 +91,29 @@ func (f *ProvisioningApiHandler) handleRouteGetTemplate(ctx *contextmodel.ReqCon
 }
 
 func (f *ProvisioningApiHandler) handleRoutePutTemplate(ctx *contextmodel.ReqContext, body apimodels.NotificationTemplateContent, name string) response.Response {
-	return f.svc.RoutePutTemplate(ctx, body, name)
+	return f.idempotency.Do(ctx, body, func() response.Response {
+		return f.svc.RoutePutTemplate(ctx, body, name)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteDeleteTemplate(ctx *contextmodel.ReqContext, name string) response.Response {
-	return f.svc.RouteDeleteTemplate(ctx, name)
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.svc.RouteDeleteTemplate(ctx, name)
+	})
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetTemplateVersions(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteGetTemplateVersions(ctx, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetTemplateVersionDiff(ctx *contextmodel.ReqContext, name, version string) response.Response {
+	return f.svc.RouteGetTemplateVersionDiff(ctx, name, version)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostTemplateVersionRollback(ctx *contextmodel.ReqContext, name, version string) response.Response {
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.svc.RoutePostTemplateVersionRollback(ctx, name, version)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteGetMuteTiming(ctx *contextmodel.ReqContext, name string) response.Response {
@@ -73,15 +125,21 @@ func (f *ProvisioningApiHandler) handleRouteGetMuteTimings(ctx *contextmodel.Req
 }
 
 func (f *ProvisioningApiHandler) handleRoutePostMuteTiming(ctx *contextmodel.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
-	return f.svc.RoutePostMuteTiming(ctx, mt)
+	return f.idempotency.Do(ctx, mt, func() response.Response {
+		return f.svc.RoutePostMuteTiming(ctx, mt)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRoutePutMuteTiming(ctx *contextmodel.ReqContext, mt apimodels.MuteTimeInterval, name string) response.Response {
-	return f.svc.RoutePutMuteTiming(ctx, mt, name)
+	return f.idempotency.Do(ctx, mt, func() response.Response {
+		return f.svc.RoutePutMuteTiming(ctx, mt, name)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteDeleteMuteTiming(ctx *contextmodel.ReqContext, name string) response.Response {
-	return f.svc.RouteDeleteMuteTiming(ctx, name)
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.svc.RouteDeleteMuteTiming(ctx, name)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
@@ -100,20 +158,32 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRulesExport(ctx *contextmode
 	return f.svc.RouteGetAlertRulesExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetAlertRulesInsights(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRulesInsights(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
-	return f.svc.RoutePostAlertRule(ctx, ar)
+	return f.idempotency.Do(ctx, ar, func() response.Response {
+		return f.svc.RoutePostAlertRule(ctx, ar)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRoutePutAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule, UID string) response.Response {
-	return f.svc.RoutePutAlertRule(ctx, ar, UID)
+	return f.idempotency.Do(ctx, ar, func() response.Response {
+		return f.svc.RoutePutAlertRule(ctx, ar, UID)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteDeleteAlertRule(ctx *contextmodel.ReqContext, UID string) response.Response {
-	return f.svc.RouteDeleteAlertRule(ctx, UID)
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.svc.RouteDeleteAlertRule(ctx, UID)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteResetPolicyTree(ctx *contextmodel.ReqContext) response.Response {
-	return f.svc.RouteResetPolicyTree(ctx)
+	return f.idempotency.Do(ctx, nil, func() response.Response {
+		return f.svc.RouteResetPolicyTree(ctx)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroup(ctx *contextmodel.ReqContext, folder, group string) response.Response {
@@ -125,7 +195,9 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroupExport(ctx *context
 }
 
 func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroup(ctx *contextmodel.ReqContext, ag apimodels.AlertRuleGroup, folder, group string) response.Response {
-	return f.svc.RoutePutAlertRuleGroup(ctx, ag, folder, group)
+	return f.idempotency.Do(ctx, ag, func() response.Response {
+		return f.svc.RoutePutAlertRuleGroup(ctx, ag, folder, group)
+	})
 }
 
 func (f *ProvisioningApiHandler) handleRouteExportMuteTiming(ctx *contextmodel.ReqContext, name string) response.Response {
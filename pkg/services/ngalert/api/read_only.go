@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	// readOnlyKVNamespace is the kvstore namespace of the read-only mode of the organizations.
+	readOnlyKVNamespace = "alerting.read_only"
+	readOnlyKVKey       = "mode"
+)
+
+// readOnlyExemptRoutes are the write endpoints that are allowed in read-only mode,
+// because they do not change the alerting resources of the organization.
+var readOnlyExemptRoutes = map[string]struct{}{
+	// the read-only mode must be possible to disable
+	http.MethodPut + "/api/v1/ngalert/read_only": {},
+
+	http.MethodDelete + "/api/v1/rule/backtest/jobs/{JobID}":                     {},
+	http.MethodPost + "/api/alertmanager/grafana/config/api/v1/grouping/preview": {},
+	http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test":   {},
+	http.MethodPost + "/api/alertmanager/grafana/config/api/v1/templates/test":   {},
+	http.MethodPost + "/api/ruler/grafana/api/v1/convert/datadog":                {},
+	http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/export":       {},
+	http.MethodPost + "/api/v1/eval":                                             {},
+	http.MethodPost + "/api/v1/rule/backtest":                                    {},
+	http.MethodPost + "/api/v1/rule/backtest/jobs":                               {},
+	http.MethodPost + "/api/v1/rule/loadtest":                                    {},
+	http.MethodPost + "/api/v1/rule/preview/panel":                               {},
+	http.MethodPost + "/api/v1/rule/test/grafana":                                {},
+	http.MethodPost + "/api/v1/rule/test/{DatasourceUID}":                        {},
+
+	// the endpoints of the server admins are not scoped to an organization
+	http.MethodPost + "/api/v1/ngalert/quota/alert_rule/rebalance": {},
+	http.MethodPost + "/api/v1/ngalert/state/snapshot":             {},
+}
+
+// isReadOnlyGuarded returns true if the requests to the endpoint are rejected when the organization is in read-only mode.
+func isReadOnlyGuarded(method, path string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	_, exempt := readOnlyExemptRoutes[method+path]
+	return !exempt
+}
+
+// readOnlyGuard returns a handler that authorizes the request, and then rejects it with 423 Locked if the organization is in read-only mode.
+// It returns an error if the authorization handler is not a handler of the request context.
+func readOnlyGuard(handler web.Handler, store *readOnlyModeStore) (web.Handler, error) {
+	authorize, ok := handler.(func(*contextmodel.ReqContext))
+	if !ok {
+		return nil, fmt.Errorf("unexpected authorization handler %T", handler)
+	}
+	return func(c *contextmodel.ReqContext) {
+		authorize(c)
+		if c.Resp.Written() {
+			return
+		}
+		mode, err := store.Get(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err != nil {
+			ErrResp(http.StatusInternalServerError, err, "failed to get the read-only mode of the organization").WriteTo(c)
+			return
+		}
+		if mode.Enabled {
+			msg := "alerting of the organization is in read-only mode"
+			if mode.Reason != "" {
+				msg = fmt.Sprintf("%s: %s", msg, mode.Reason)
+			}
+			// the rejection is expected, it is not logged as an error
+			response.Error(http.StatusLocked, msg, nil).WriteTo(c)
+		}
+	}, nil
+}
+
+// readOnlyModeStore keeps the read-only mode of the organizations in the kvstore, so that it is shared between servers.
+type readOnlyModeStore struct {
+	kv kvstore.KVStore
+}
+
+func newReadOnlyModeStore(kv kvstore.KVStore) *readOnlyModeStore {
+	return &readOnlyModeStore{kv: kv}
+}
+
+// Get returns the read-only mode of the organization. Organizations are not in read-only mode by default.
+func (s *readOnlyModeStore) Get(ctx context.Context, orgID int64) (apimodels.ReadOnlyMode, error) {
+	value, ok, err := s.kv.Get(ctx, orgID, readOnlyKVNamespace, readOnlyKVKey)
+	if err != nil || !ok {
+		return apimodels.ReadOnlyMode{}, err
+	}
+	mode := apimodels.ReadOnlyMode{}
+	if err := json.Unmarshal([]byte(value), &mode); err != nil {
+		return apimodels.ReadOnlyMode{}, fmt.Errorf("failed to parse the read-only mode: %w", err)
+	}
+	return mode, nil
+}
+
+// Set saves the read-only mode of the organization, disabling it removes it from the kvstore.
+func (s *readOnlyModeStore) Set(ctx context.Context, orgID int64, mode apimodels.ReadOnlyMode) error {
+	if !mode.Enabled {
+		return s.kv.Del(ctx, orgID, readOnlyKVNamespace, readOnlyKVKey)
+	}
+	value, err := json.Marshal(mode)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, orgID, readOnlyKVNamespace, readOnlyKVKey, string(value))
+}
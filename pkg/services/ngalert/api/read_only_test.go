@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestIsReadOnlyGuarded(t *testing.T) {
+	require.False(t, isReadOnlyGuarded(http.MethodGet, "/api/ruler/grafana/api/v1/rules"))
+	require.False(t, isReadOnlyGuarded(http.MethodPut, "/api/v1/ngalert/read_only"))
+	require.False(t, isReadOnlyGuarded(http.MethodPost, "/api/v1/rule/test/grafana"))
+	require.True(t, isReadOnlyGuarded(http.MethodPost, "/api/ruler/grafana/api/v1/rules/{Namespace}"))
+	require.True(t, isReadOnlyGuarded(http.MethodDelete, "/api/v1/provisioning/contact-points/{UID}"))
+	require.True(t, isReadOnlyGuarded(http.MethodPost, "/api/alertmanager/grafana/api/v2/silences"))
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	kv := kvstore.NewFakeKVStore()
+	srv := ConfigSrv{readOnly: newReadOnlyModeStore(kv), log: log.NewNopLogger()}
+
+	authorized := 0
+	handler, err := readOnlyGuard(func(c *contextmodel.ReqContext) {
+		authorized++
+	}, newReadOnlyModeStore(kv))
+	require.NoError(t, err)
+	guard := handler.(func(*contextmodel.ReqContext))
+
+	t.Run("should fail for unknown authorization handlers", func(t *testing.T) {
+		_, err := readOnlyGuard(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), newReadOnlyModeStore(kv))
+		require.Error(t, err)
+	})
+
+	t.Run("should allow requests by default", func(t *testing.T) {
+		resp := srv.RouteGetReadOnlyMode(createRequestContext(1, nil))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.JSONEq(t, `{"enabled": false, "updated": "0001-01-01T00:00:00Z"}`, string(resp.Body()))
+
+		c := createRequestContext(1, nil)
+		guard(c)
+		require.Equal(t, 1, authorized)
+		require.False(t, c.Resp.Written())
+	})
+
+	t.Run("should reject the requests of the organization in read-only mode", func(t *testing.T) {
+		resp := srv.RoutePutReadOnlyMode(createRequestContext(1, nil), definitions.ReadOnlyMode{Enabled: true, Reason: "migration in progress"})
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		c := createRequestContext(1, nil)
+		guard(c)
+		require.Equal(t, 2, authorized)
+		require.Equal(t, http.StatusLocked, c.Resp.Status())
+
+		c = createRequestContext(2, nil)
+		guard(c)
+		require.False(t, c.Resp.Written())
+	})
+
+	t.Run("should allow requests once disabled", func(t *testing.T) {
+		resp := srv.RoutePutReadOnlyMode(createRequestContext(1, nil), definitions.ReadOnlyMode{Enabled: false, Reason: "ignored"})
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		mode, err := srv.readOnly.Get(createRequestContext(1, nil).Req.Context(), 1)
+		require.NoError(t, err)
+		require.False(t, mode.Enabled)
+
+		c := createRequestContext(1, nil)
+		guard(c)
+		require.False(t, c.Resp.Written())
+	})
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+const queryIncludeCost = "includeCost"
+
+// ruleCostEstimator estimates the cost of evaluating the rules of an organization.
+// It caches the types of the data sources and the interval inherited by rules, so it is meant to be used for a single request.
+type ruleCostEstimator struct {
+	ctx             context.Context
+	user            identity.Requester
+	log             log.Logger
+	datasourceCache datasources.CacheService
+	manager         state.AlertInstanceManager
+	// getInheritedInterval returns the evaluation interval of rules that inherit the interval of the organization.
+	getInheritedInterval func() time.Duration
+
+	inheritedInterval time.Duration
+	datasourceTypes   map[string]string
+}
+
+func newRuleCostEstimator(ctx context.Context, user identity.Requester, logger log.Logger, datasourceCache datasources.CacheService, manager state.AlertInstanceManager, getInheritedInterval func() time.Duration) *ruleCostEstimator {
+	return &ruleCostEstimator{
+		ctx:                  ctx,
+		user:                 user,
+		log:                  logger,
+		datasourceCache:      datasourceCache,
+		manager:              manager,
+		getInheritedInterval: getInheritedInterval,
+		datasourceTypes:      make(map[string]string),
+	}
+}
+
+// Estimate returns the number of queries and the types of the data sources queried by each evaluation of the rule,
+// its evaluation interval and the number of alert instances updated by its last evaluation.
+func (e *ruleCostEstimator) Estimate(rule *ngmodels.AlertRule) *apimodels.RuleCostEstimate {
+	result := &apimodels.RuleCostEstimate{
+		IntervalSeconds: rule.IntervalSeconds,
+	}
+	if rule.InheritsInterval() {
+		result.IntervalSeconds = int64(e.interval().Seconds())
+	}
+
+	types := make(map[string]struct{})
+	for _, uid := range ruleDatasourceUIDs(rule) {
+		if t := e.datasourceType(uid); t != "" {
+			types[t] = struct{}{}
+		}
+	}
+	for t := range types {
+		result.DatasourceTypes = append(result.DatasourceTypes, t)
+	}
+	sort.Strings(result.DatasourceTypes)
+
+	for _, q := range rule.Data {
+		if isExpr, err := q.IsExpression(); err == nil && !isExpr {
+			result.Queries++
+		}
+	}
+	if result.IntervalSeconds > 0 {
+		result.QueriesPerHour = float64(result.Queries) * time.Hour.Seconds() / float64(result.IntervalSeconds)
+	}
+
+	if e.manager != nil {
+		result.Instances = lastEvaluationInstances(e.manager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	}
+	return result
+}
+
+func (e *ruleCostEstimator) interval() time.Duration {
+	if e.inheritedInterval == 0 && e.getInheritedInterval != nil {
+		e.inheritedInterval = e.getInheritedInterval()
+	}
+	return e.inheritedInterval
+}
+
+// datasourceType returns the type of the data source, or an empty string if it cannot be read.
+func (e *ruleCostEstimator) datasourceType(uid string) string {
+	if t, ok := e.datasourceTypes[uid]; ok {
+		return t
+	}
+	var t string
+	if e.datasourceCache != nil {
+		ds, err := e.datasourceCache.GetDatasourceByUID(e.ctx, uid, e.user, false)
+		if err != nil {
+			e.log.Debug("Failed to get the data source to estimate the cost of rules", "datasourceUID", uid, "error", err)
+		} else {
+			t = ds.Type
+		}
+	}
+	e.datasourceTypes[uid] = t
+	return t
+}
+
+// lastEvaluationInstances returns the number of alert instances updated by the last evaluation of the rule.
+// Stale instances that were resolved because their series is missing from the results are not counted.
+func lastEvaluationInstances(states []*state.State) int {
+	var last time.Time
+	count := 0
+	for _, s := range states {
+		switch {
+		case s.LastEvaluationTime.IsZero(), s.StateReason == ngmodels.StateReasonMissingSeries:
+		case s.LastEvaluationTime.After(last):
+			last = s.LastEvaluationTime
+			count = 1
+		case s.LastEvaluationTime.Equal(last):
+			count++
+		}
+	}
+	return count
+}
+
+// addRuleCosts sets the cost estimate of each rule of the export. The export must have been created from the groups.
+func addRuleCosts(export *apimodels.AlertingFileExport, groups []ngmodels.AlertRuleGroupWithFolderTitle, estimator *ruleCostEstimator) {
+	for i := range export.Groups {
+		if i >= len(groups) {
+			return
+		}
+		for j := range export.Groups[i].Rules {
+			if j >= len(groups[i].Rules) {
+				break
+			}
+			export.Groups[i].Rules[j].Cost = estimator.Estimate(&groups[i].Rules[j])
+		}
+	}
+}
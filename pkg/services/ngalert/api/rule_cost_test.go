@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRuleCostEstimator(t *testing.T) {
+	orgID := int64(1)
+	cache := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{
+		{UID: "prom", Type: datasources.DS_PROMETHEUS},
+		{UID: "loki", Type: datasources.DS_LOKI},
+	}}
+	fakeAIM := NewFakeAlertInstanceManager(t)
+
+	rule := ngmodels.AlertRuleGen(ngmodels.WithOrgID(orgID))()
+	rule.IntervalSeconds = 60
+	rule.Data = []ngmodels.AlertQuery{
+		{RefID: "A", DatasourceUID: "prom"},
+		{RefID: "B", DatasourceUID: "loki"},
+		{RefID: "C", DatasourceUID: "prom"},
+		{RefID: "D", DatasourceUID: expr.DatasourceUID, Model: []byte(`{"type":"math","expression":"$A + $B"}`)},
+	}
+	fakeAIM.GenerateAlertInstances(orgID, rule.UID, 3)
+
+	t.Run("should count queries, data source types and instances of the last evaluation", func(t *testing.T) {
+		estimator := newRuleCostEstimator(context.Background(), &user.SignedInUser{OrgID: orgID}, log.NewNopLogger(), cache, fakeAIM, nil)
+		require.Equal(t, &apimodels.RuleCostEstimate{
+			Queries:         3,
+			DatasourceTypes: []string{datasources.DS_LOKI, datasources.DS_PROMETHEUS},
+			IntervalSeconds: 60,
+			QueriesPerHour:  180,
+			Instances:       3,
+		}, estimator.Estimate(rule))
+	})
+
+	t.Run("should use the inherited interval", func(t *testing.T) {
+		inheriting := ngmodels.CopyRule(rule)
+		inheriting.IntervalSeconds = ngmodels.InheritedIntervalSeconds
+		require.True(t, inheriting.InheritsInterval())
+		estimator := newRuleCostEstimator(context.Background(), &user.SignedInUser{OrgID: orgID}, log.NewNopLogger(), cache, fakeAIM, func() time.Duration {
+			return 5 * time.Minute
+		})
+		result := estimator.Estimate(inheriting)
+		require.EqualValues(t, 300, result.IntervalSeconds)
+		require.EqualValues(t, 36, result.QueriesPerHour)
+	})
+
+	t.Run("should ignore data sources that cannot be read", func(t *testing.T) {
+		estimator := newRuleCostEstimator(context.Background(), &user.SignedInUser{OrgID: orgID}, log.NewNopLogger(), &fakes.FakeCacheService{}, nil, nil)
+		result := estimator.Estimate(rule)
+		require.Equal(t, 3, result.Queries)
+		require.Empty(t, result.DatasourceTypes)
+		require.Zero(t, result.Instances)
+	})
+}
+
+func TestLastEvaluationInstances(t *testing.T) {
+	now := time.Now()
+	require.Equal(t, 2, lastEvaluationInstances([]*state.State{
+		{LastEvaluationTime: now.Add(-time.Minute)},
+		{LastEvaluationTime: now},
+		{},
+		{LastEvaluationTime: now},
+		{LastEvaluationTime: now, StateReason: ngmodels.StateReasonMissingSeries},
+	}))
+	require.Zero(t, lastEvaluationInstances(nil))
+}
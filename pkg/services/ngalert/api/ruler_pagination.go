@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// continueTokenHeader is the header of the response that contains the token of the next page of rule groups.
+const continueTokenHeader = "X-Grafana-Continue-Token"
+
+var errInvalidContinueToken = errors.New("invalid continue token")
+
+// ruleGroupsContinueToken identifies the last rule group of a page. The next page starts after it.
+type ruleGroupsContinueToken struct {
+	NamespaceUID string `json:"n"`
+	RuleGroup    string `json:"g"`
+}
+
+func encodeContinueToken(key ngmodels.AlertRuleGroupKey) string {
+	b, _ := json.Marshal(ruleGroupsContinueToken{NamespaceUID: key.NamespaceUID, RuleGroup: key.RuleGroup})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeContinueToken(s string) (*ruleGroupsContinueToken, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidContinueToken
+	}
+	token := &ruleGroupsContinueToken{}
+	if err := json.Unmarshal(b, token); err != nil || token.NamespaceUID == "" {
+		return nil, errInvalidContinueToken
+	}
+	return token, nil
+}
+
+// filterRuleGroupsByLabels removes the rules whose labels do not match all matchers, and the rule groups
+// that have no rules left. The rules keep their order.
+func filterRuleGroupsByLabels(groups map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, matchers labels.Matchers) {
+	if len(matchers) == 0 {
+		return
+	}
+	for key, rules := range groups {
+		filtered := make(ngmodels.RulesGroup, 0, len(rules))
+		for _, rule := range rules {
+			if matchersMatch(matchers, rule.Labels) {
+				filtered = append(filtered, rule)
+			}
+		}
+		if len(filtered) == 0 {
+			delete(groups, key)
+			continue
+		}
+		groups[key] = filtered
+	}
+}
+
+// paginateRuleGroups returns the keys of the rule groups of the page that starts after the token, sorted by the UID
+// of their folder and their name, and the token of the next page. The token is empty if it is the last page.
+// All rule groups after the token are returned if the limit is not positive.
+func paginateRuleGroups(groups map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, limit int64, after *ruleGroupsContinueToken) ([]ngmodels.AlertRuleGroupKey, string) {
+	keys := make([]ngmodels.AlertRuleGroupKey, 0, len(groups))
+	for key := range groups {
+		if after != nil && !isAfterContinueToken(key, after) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].NamespaceUID != keys[j].NamespaceUID {
+			return keys[i].NamespaceUID < keys[j].NamespaceUID
+		}
+		return keys[i].RuleGroup < keys[j].RuleGroup
+	})
+
+	if limit <= 0 || int64(len(keys)) <= limit {
+		return keys, ""
+	}
+	keys = keys[:limit]
+	return keys, encodeContinueToken(keys[len(keys)-1])
+}
+
+func isAfterContinueToken(key ngmodels.AlertRuleGroupKey, token *ruleGroupsContinueToken) bool {
+	if key.NamespaceUID != token.NamespaceUID {
+		return key.NamespaceUID > token.NamespaceUID
+	}
+	return key.RuleGroup > token.RuleGroup
+}
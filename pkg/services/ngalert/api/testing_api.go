@@ -29,6 +29,38 @@ func (f *TestingApiHandler) handleRouteEvalQueries(c *contextmodel.ReqContext, b
 	return f.svc.RouteEvalQueries(c, body)
 }
 
+func (f *TestingApiHandler) handleRouteGetTemplateFunctions(c *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetTemplateFunctions(c)
+}
+
 func (f *TestingApiHandler) handleBacktestConfig(ctx *contextmodel.ReqContext, conf apimodels.BacktestConfig) response.Response {
 	return f.svc.BacktestAlertRule(ctx, conf)
 }
+
+func (f *TestingApiHandler) handleLoadTestConfig(ctx *contextmodel.ReqContext, conf apimodels.LoadTestConfig) response.Response {
+	return f.svc.LoadTestAlertRule(ctx, conf)
+}
+
+func (f *TestingApiHandler) handleRouteCreateBacktestJob(ctx *contextmodel.ReqContext, conf apimodels.BacktestConfig) response.Response {
+	return f.svc.CreateBacktestJob(ctx, conf)
+}
+
+func (f *TestingApiHandler) handleRouteDeleteBacktestJob(ctx *contextmodel.ReqContext, jobID string) response.Response {
+	return f.svc.DeleteBacktestJob(ctx, jobID)
+}
+
+func (f *TestingApiHandler) handleRouteGetBacktestJob(ctx *contextmodel.ReqContext, jobID string) response.Response {
+	return f.svc.GetBacktestJob(ctx, jobID)
+}
+
+func (f *TestingApiHandler) handleRouteGetBacktestJobResult(ctx *contextmodel.ReqContext, jobID string) response.Response {
+	return f.svc.GetBacktestJobResult(ctx, jobID)
+}
+
+func (f *TestingApiHandler) handlePanelAlertPreviewConfig(ctx *contextmodel.ReqContext, conf apimodels.PanelAlertPreviewConfig) response.Response {
+	return f.svc.PanelAlertPreview(ctx, conf)
+}
+
+func (f *TestingApiHandler) handleRangePreviewConfig(ctx *contextmodel.ReqContext, conf apimodels.RangePreviewConfig) response.Response {
+	return f.svc.RangePreview(ctx, conf)
+}
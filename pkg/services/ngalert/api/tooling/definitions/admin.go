@@ -1,7 +1,10 @@
 package definitions
 
 import (
+	"time"
+
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 )
 
 // swagger:route GET /v1/ngalert configuration RouteGetStatus
@@ -24,6 +27,17 @@ import (
 //     Responses:
 //		 200: GettableAlertmanagers
 
+// swagger:route GET /v1/ngalert/metrics configuration RouteGetOrgMetrics
+//
+//  Get the counters of the alert rule evaluations and the notifications of the user's organization since Grafana started.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//		 200: OrgAlertingMetrics
+//		 500: Failure
+
 // swagger:route GET /v1/ngalert/admin_config configuration RouteGetNGalertConfig
 //
 //  Get the NGalert configuration of the user's organization, returns 404 if no configuration is present.
@@ -39,13 +53,14 @@ import (
 // swagger:route POST /v1/ngalert/admin_config configuration RoutePostNGalertConfig
 //
 // Creates or updates the NGalert configuration of the user's organization. If no value is sent for alertmanagersChoice, it defaults to "all".
+// If allowedEvaluationIntervals is set, the request is rejected when existing rule groups use other intervals.
 //
 //     Consumes:
 //     - application/json
 //
 //     Responses:
 //       201: Ack
-//       400: ValidationError
+//       400: NonConformingIntervalsError
 
 // swagger:route DELETE /v1/ngalert/admin_config configuration RouteDeleteNGalertConfig
 //
@@ -58,6 +73,159 @@ import (
 //       200: Ack
 //       500: Failure
 
+// swagger:route GET /v1/ngalert/state/snapshot configuration RouteGetStateSnapshot
+//
+//  Get the latest snapshot of the state of the alert instances of all organizations, without its data.
+//  Requires the Grafana server admin role. Returns 404 if no snapshot was taken yet.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//		 200: StateSnapshot
+//		 400: Failure
+//		 404: Failure
+//		 500: Failure
+
+// swagger:route POST /v1/ngalert/state/snapshot configuration RoutePostStateSnapshot
+//
+//  Take a snapshot of the state of the alert instances of all organizations, replacing the previous one.
+//  Requires the Grafana server admin role, and state snapshots to be enabled.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       201: StateSnapshot
+//       400: Failure
+//       500: Failure
+
+// swagger:route GET /v1/ngalert/cleanup/orgs configuration RouteGetOrgCleanupJobs
+//
+//  Get the progress of the cleanup of the alerting data of deleted organizations.
+//  Requires the Grafana server admin role.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: OrgCleanupJobs
+
+// swagger:route GET /v1/ngalert/quota/alert_rule configuration RouteGetAlertRuleQuotaDistribution
+//
+//  Get the global alert rule quota and how its limit and usage are distributed across the organizations.
+//  Requires the Grafana server admin role.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleQuotaDistribution
+//       400: Failure
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/quota/alert_rule/rebalance configuration RoutePostAlertRuleQuotaRebalance
+//
+//  Distribute the global alert rule quota across the organizations by overriding the alert rule quota of every organization.
+//  Requires the Grafana server admin role.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleQuotaDistribution
+//       400: Failure
+//       500: Failure
+
+// swagger:parameters RoutePostAlertRuleQuotaRebalance
+type AlertRuleQuotaRebalanceParams struct {
+	// in:body
+	Body PostableAlertRuleQuotaRebalance
+}
+
+// swagger:enum AlertRuleQuotaRebalanceStrategy
+type AlertRuleQuotaRebalanceStrategy string
+
+const (
+	// Every organization gets the same limit.
+	AlertRuleQuotaRebalanceEven AlertRuleQuotaRebalanceStrategy = "even"
+	// Every organization keeps the rules it has, the rest of the limit is split evenly.
+	AlertRuleQuotaRebalanceUsage AlertRuleQuotaRebalanceStrategy = "usage"
+)
+
+// swagger:model
+type PostableAlertRuleQuotaRebalance struct {
+	// Limit to distribute across the organizations. Defaults to the global alert rule quota.
+	Limit int64 `json:"limit,omitempty"`
+	// How the limit is distributed. Defaults to even.
+	Strategy AlertRuleQuotaRebalanceStrategy `json:"strategy,omitempty"`
+	// If true, the distribution is returned but the quotas of the organizations are not changed.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// swagger:model
+type AlertRuleQuotaDistribution struct {
+	// Global alert rule quota, -1 means unlimited.
+	Limit int64 `json:"limit"`
+	// Number of alert rules of all organizations.
+	Used int64               `json:"used"`
+	Orgs []OrgAlertRuleQuota `json:"orgs"`
+}
+
+type OrgAlertRuleQuota struct {
+	OrgID int64 `json:"orgId"`
+	// Alert rule quota of the organization, -1 means unlimited.
+	Limit int64 `json:"limit"`
+	Used  int64 `json:"used"`
+}
+
+// swagger:route GET /v1/ngalert/read_only configuration RouteGetReadOnlyMode
+//
+//  Get the read-only mode of the alerting of the user's organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: ReadOnlyMode
+//       500: Failure
+
+// swagger:route PUT /v1/ngalert/read_only configuration RoutePutReadOnlyMode
+//
+//  Enables or disables the read-only mode of the alerting of the user's organization.
+//  In read-only mode, alert rules are still evaluated, but the requests that change alerting resources are rejected with 423 Locked.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: ReadOnlyMode
+//       400: Failure
+//       500: Failure
+
+// swagger:parameters RoutePutReadOnlyMode
+type ReadOnlyModeParams struct {
+	// in:body
+	Body ReadOnlyMode
+}
+
+// swagger:model
+type ReadOnlyMode struct {
+	Enabled bool `json:"enabled"`
+	// Reason returned to the clients whose requests are rejected, such as an ongoing migration.
+	Reason string `json:"reason,omitempty"`
+	// When the mode was last changed. It is set by the server.
+	Updated time.Time `json:"updated,omitempty"`
+	// Login of the user that last changed the mode. It is set by the server.
+	UpdatedBy string `json:"updatedBy,omitempty"`
+}
+
 // swagger:parameters RoutePostNGalertConfig
 type NGalertConfig struct {
 	// in:body
@@ -77,11 +245,80 @@ const (
 // swagger:model
 type PostableNGalertConfig struct {
 	AlertmanagersChoice AlertmanagersChoice `json:"alertmanagersChoice"`
+	// Evaluation interval of rule groups that inherit the interval of the organization.
+	// If not set, the default evaluation interval of the instance is used.
+	DefaultEvaluationInterval model.Duration `json:"defaultEvaluationInterval,omitempty"`
+	// Evaluation intervals rule groups are allowed to use. If empty, any interval is allowed.
+	AllowedEvaluationIntervals []model.Duration `json:"allowedEvaluationIntervals,omitempty"`
+	// Annotations alert rules of the organization must define. Rules that violate the schema are rejected when they are created or updated.
+	AnnotationSchema []AnnotationRequirement `json:"annotationSchema,omitempty"`
+	// Rules that rewrite, in order, the labels data sources return for alert instances, before the instances are routed and recorded in the state history.
+	LabelRewriteRules []LabelRewriteRule `json:"labelRewriteRules,omitempty"`
+	// Number of evaluations in a row a series must be missing from before its alert instance is resolved, for the rules that do not set their own policy.
+	// If neither it nor missingSeriesDurationToResolve is set, alert instances are resolved after 2 missed evaluations.
+	MissingSeriesEvalsToResolve int64 `json:"missingSeriesEvalsToResolve,omitempty"`
+	// How long a series must be missing before its alert instance is resolved, for the rules that do not set their own policy.
+	// It cannot be set with missingSeriesEvalsToResolve.
+	MissingSeriesDurationToResolve model.Duration `json:"missingSeriesDurationToResolve,omitempty"`
 }
 
 // swagger:model
 type GettableNGalertConfig struct {
-	AlertmanagersChoice AlertmanagersChoice `json:"alertmanagersChoice"`
+	AlertmanagersChoice            AlertmanagersChoice     `json:"alertmanagersChoice"`
+	DefaultEvaluationInterval      model.Duration          `json:"defaultEvaluationInterval,omitempty"`
+	AllowedEvaluationIntervals     []model.Duration        `json:"allowedEvaluationIntervals,omitempty"`
+	AnnotationSchema               []AnnotationRequirement `json:"annotationSchema,omitempty"`
+	LabelRewriteRules              []LabelRewriteRule      `json:"labelRewriteRules,omitempty"`
+	MissingSeriesEvalsToResolve    int64                   `json:"missingSeriesEvalsToResolve,omitempty"`
+	MissingSeriesDurationToResolve model.Duration          `json:"missingSeriesDurationToResolve,omitempty"`
+}
+
+type AnnotationRequirement struct {
+	// Name of the annotation.
+	Name string `json:"name"`
+	// If true, every alert rule must define a non-empty annotation.
+	Required bool `json:"required,omitempty"`
+	// If true, the value of the annotation must be an absolute http or https URL. Values that contain templates are accepted.
+	URL bool `json:"url,omitempty"`
+}
+
+type LabelRewriteRule struct {
+	// Regular expression the whole name of the labels must match.
+	// example: pod|container_id
+	Label string `json:"label"`
+	// drop removes the matching labels, replace replaces their value. Labels whose new value is empty are removed.
+	// enum: drop,replace
+	Action string `json:"action"`
+	// Regular expression the whole value of the labels must match. Defaults to any value.
+	Regex string `json:"regex,omitempty"`
+	// New value of the labels if the action is replace. It can refer to the capture groups of regex, such as $1.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// swagger:model
+type AnnotationViolationsError struct {
+	Message    string                `json:"message"`
+	Violations []AnnotationViolation `json:"violations"`
+}
+
+type AnnotationViolation struct {
+	RuleUID    string `json:"ruleUid,omitempty"`
+	RuleTitle  string `json:"ruleTitle"`
+	Annotation string `json:"annotation"`
+	Reason     string `json:"reason"`
+}
+
+// swagger:model
+type NonConformingIntervalsError struct {
+	Message string `json:"message"`
+	// Rule groups whose evaluation interval is not one of the allowed intervals.
+	Groups []NonConformingRuleGroup `json:"groups"`
+}
+
+type NonConformingRuleGroup struct {
+	NamespaceUID string         `json:"namespaceUid"`
+	RuleGroup    string         `json:"ruleGroup"`
+	Interval     model.Duration `json:"interval"`
 }
 
 // swagger:model
@@ -95,3 +332,47 @@ type AlertingStatus struct {
 	AlertmanagersChoice      AlertmanagersChoice `json:"alertmanagersChoice"`
 	NumExternalAlertmanagers int                 `json:"numExternalAlertmanagers"`
 }
+
+// swagger:model
+type OrgAlertingMetrics struct {
+	RuleEvaluations        int64 `json:"ruleEvaluations"`
+	RuleEvaluationFailures int64 `json:"ruleEvaluationFailures"`
+	NotificationsSent      int64 `json:"notificationsSent"`
+	NotificationsFailed    int64 `json:"notificationsFailed"`
+	ActiveSilences         int64 `json:"activeSilences"`
+}
+
+// swagger:model
+type StateSnapshot struct {
+	Created time.Time `json:"created"`
+	// Number of alert instances in the snapshot.
+	Instances int64 `json:"instances"`
+	// Size of the compressed snapshot in bytes.
+	Size int64 `json:"size"`
+	// Interval at which snapshots are taken.
+	Interval model.Duration `json:"interval"`
+}
+
+// swagger:model
+type OrgCleanupJobs []OrgCleanupJob
+
+// OrgCleanupJob is the deletion of the alerting data of a deleted organization, which is done in the background.
+// swagger:model
+type OrgCleanupJob struct {
+	OrgID int64 `json:"orgId"`
+	// enum: pending,running,completed,failed
+	State    string           `json:"state"`
+	Steps    []OrgCleanupStep `json:"steps"`
+	Created  time.Time        `json:"created"`
+	Started  *time.Time       `json:"started,omitempty"`
+	Finished *time.Time       `json:"finished,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// OrgCleanupStep is the deletion of a kind of alerting data of a deleted organization.
+type OrgCleanupStep struct {
+	Name string `json:"name"`
+	// Number of rows deleted so far.
+	Deleted   int64 `json:"deleted"`
+	Completed bool  `json:"completed"`
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -22,8 +24,8 @@ import (
 // sets an Alerting config
 //
 //     Responses:
-//       201: Ack
-//       400: ValidationError
+//       201: PostAlertingConfigResult
+//       400: PostAlertingConfigResult
 
 // swagger:route POST /alertmanager/{DatasourceUID}/config/api/v1/alerts alertmanager RoutePostAlertingConfig
 //
@@ -178,12 +180,55 @@ import (
 //       403: PermissionDenied
 //       409: AlertManagerNotReady
 
+// swagger:route POST /alertmanager/grafana/api/v1/fire_drill alertmanager RoutePostGrafanaFireDrill
+//
+// Inject synthetic alerts into the Grafana Alertmanager to rehearse notification routing, silences and escalation.
+// The alerts are labeled as fire drill alerts and resolve automatically once their duration elapsed.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: FireDrillResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       409: AlertManagerNotReady
+
+// swagger:route POST /alertmanager/grafana/api/v1/silences/purge alertmanager RoutePostGrafanaPurgeExpiredSilences
+//
+// Purge the expired silences of the Grafana Alertmanager, which are otherwise kept until the end of their retention.
+// The Alertmanager is restarted to remove them.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: PurgeExpiredSilencesResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: AlertManagerNotReady
+
+// swagger:route POST /alertmanager/grafana/config/api/v1/grouping/preview alertmanager RoutePostGrafanaGroupingPreview
+//
+// Preview how alerts are grouped into notifications by the notification policy tree.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: GroupingPreviewResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: AlertManagerNotReady
+
 // swagger:route GET /alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
 //
 //     Responses:
-//       200: gettableSilences
+//       200: gettableGrafanaSilences
 //       400: ValidationError
 
 // swagger:route GET /alertmanager/{DatasourceUID}/api/v2/silences alertmanager RouteGetSilences
@@ -217,7 +262,7 @@ import (
 // get silence
 //
 //     Responses:
-//       200: gettableSilence
+//       200: gettableGrafanaSilence
 //       400: ValidationError
 
 // swagger:route GET /alertmanager/{DatasourceUID}/api/v2/silence/{SilenceId} alertmanager RouteGetSilence
@@ -316,6 +361,90 @@ type TestTemplatesConfigBodyParams struct {
 	Name string `json:"name"`
 }
 
+// FireDrillLabel is the label added to all alerts injected by a fire drill.
+const FireDrillLabel = "grafana_fire_drill"
+
+// swagger:parameters RoutePostGrafanaFireDrill
+type FireDrillParams struct {
+	// in:body
+	Body FireDrillBodyParams
+}
+
+type FireDrillBodyParams struct {
+	// Alerts to inject.
+	Alerts []FireDrillAlert `json:"alerts"`
+
+	// Resolve the alerts instead of firing them.
+	Resolve bool `json:"resolve,omitempty"`
+
+	// How long the alerts fire before they resolve automatically. Defaults to 5m, at most 1h.
+	Duration model.Duration `json:"duration,omitempty"`
+}
+
+type FireDrillAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// swagger:model
+type PurgeExpiredSilencesResult struct {
+	// Number of purged silences.
+	Purged int `json:"purged"`
+}
+
+// swagger:model
+type FireDrillResult struct {
+	// Labels of the injected alerts.
+	Alerts []amv2.LabelSet `json:"alerts"`
+
+	// Time the alerts resolve.
+	EndsAt time.Time `json:"endsAt"`
+}
+
+// swagger:parameters RoutePostGrafanaGroupingPreview
+type GroupingPreviewParams struct {
+	// in:body
+	Body GroupingPreviewBodyParams
+}
+
+type GroupingPreviewBodyParams struct {
+	// Alerts to group. If empty, the alerts that are currently active in the Alertmanager are used.
+	Alerts []*amv2.PostableAlert `json:"alerts,omitempty"`
+
+	// Notification policy tree to group the alerts by. If empty, the current policy tree is used.
+	Route *Route `json:"route,omitempty"`
+}
+
+// swagger:model
+type GroupingPreviewResult struct {
+	Groups []GroupingPreviewGroup `json:"groups"`
+}
+
+type GroupingPreviewGroup struct {
+	// Key that identifies the group in the Alertmanager.
+	GroupKey string `json:"groupKey"`
+
+	// Receiver the notifications of the group are sent to.
+	Receiver string `json:"receiver"`
+
+	// Labels the alerts of the group have in common.
+	GroupLabels amv2.LabelSet `json:"groupLabels"`
+
+	// Labels the policy groups the alerts by. Contains "..." if the alerts are grouped by all labels.
+	GroupBy []string `json:"groupBy"`
+
+	// Timing of the notifications of the group.
+	GroupWait      model.Duration `json:"groupWait"`
+	GroupInterval  model.Duration `json:"groupInterval"`
+	RepeatInterval model.Duration `json:"repeatInterval"`
+
+	// Number of alerts in the group.
+	AlertCount int `json:"alertCount"`
+
+	// Labels of the alerts in the group.
+	Alerts []amv2.LabelSet `json:"alerts"`
+}
+
 // swagger:model
 type TestTemplatesResults struct {
 	Results []TestTemplatesResult      `json:"results,omitempty"`
@@ -349,24 +478,39 @@ const (
 	ExecutionError  TemplateErrorKind = "execution_error"
 )
 
-// swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
+// swagger:parameters RouteCreateSilence
 type CreateSilenceParams struct {
 	// in:body
 	Silence PostableSilence
 }
 
+// swagger:parameters RouteCreateGrafanaSilence
+type CreateGrafanaSilenceParams struct {
+	// in:body
+	Silence PostableGrafanaSilence
+}
+
 // swagger:parameters RouteGetSilence RouteDeleteSilence RouteGetGrafanaSilence RouteDeleteGrafanaSilence
 type GetDeleteSilenceParams struct {
 	// in:path
 	SilenceId string
 }
 
-// swagger:parameters RouteGetSilences RouteGetGrafanaSilences
+// swagger:parameters RouteGetSilences
 type GetSilencesParams struct {
 	// in:query
 	Filter []string `json:"filter"`
 }
 
+// swagger:parameters RouteGetGrafanaSilences
+type GetGrafanaSilencesParams struct {
+	// in:query
+	Filter []string `json:"filter"`
+	// Only return the silences created by the user with this login.
+	// in:query
+	CreatedBy string `json:"createdBy"`
+}
+
 // swagger:model
 type GettableStatus struct {
 	// cluster
@@ -455,6 +599,98 @@ type GettableSilences = amv2.GettableSilences
 // swagger:model gettableSilence
 type GettableSilence = amv2.GettableSilence
 
+// swagger:model postableGrafanaSilence
+type PostableGrafanaSilence struct {
+	PostableSilence `json:",inline"`
+	// Reference is an optional link to the reason of the silence, e.g. the key of a ticket or an incident.
+	Reference string `json:"reference,omitempty"`
+}
+
+func (s PostableGrafanaSilence) MarshalJSON() ([]byte, error) {
+	if s.Reference == "" {
+		return json.Marshal(s.PostableSilence)
+	}
+	return mergeJSONFields(s.PostableSilence, map[string]any{"reference": s.Reference})
+}
+
+func (s *PostableGrafanaSilence) UnmarshalJSON(b []byte) error {
+	// PostableSilence implements json.Unmarshaler, so the fields added by Grafana are decoded separately.
+	if err := json.Unmarshal(b, &s.PostableSilence); err != nil {
+		return err
+	}
+	extra := struct {
+		Reference string `json:"reference"`
+	}{}
+	if err := json.Unmarshal(b, &extra); err != nil {
+		return err
+	}
+	s.Reference = extra.Reference
+	return nil
+}
+
+// SilenceMetadata is what Grafana records about a silence in addition to the silence itself.
+// swagger:model
+type SilenceMetadata struct {
+	// Namespaced ID of the identity that created the silence, e.g. user:1.
+	CreatedBy string `json:"createdBy"`
+	// Login of the user that created the silence.
+	CreatedByLogin string `json:"createdByLogin"`
+	// Optional link to the reason of the silence, e.g. the key of a ticket or an incident.
+	Reference string    `json:"reference,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// swagger:model gettableGrafanaSilence
+type GettableGrafanaSilence struct {
+	*GettableSilence `json:",inline"`
+	// Metadata is not set for silences that were created before Grafana recorded it.
+	Metadata *SilenceMetadata `json:"metadata,omitempty"`
+}
+
+func (s GettableGrafanaSilence) MarshalJSON() ([]byte, error) {
+	if s.Metadata == nil {
+		return json.Marshal(s.GettableSilence)
+	}
+	return mergeJSONFields(s.GettableSilence, map[string]any{"metadata": s.Metadata})
+}
+
+func (s *GettableGrafanaSilence) UnmarshalJSON(b []byte) error {
+	// GettableSilence implements json.Unmarshaler, so the fields added by Grafana are decoded separately.
+	s.GettableSilence = &GettableSilence{}
+	if err := json.Unmarshal(b, s.GettableSilence); err != nil {
+		return err
+	}
+	extra := struct {
+		Metadata *SilenceMetadata `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(b, &extra); err != nil {
+		return err
+	}
+	s.Metadata = extra.Metadata
+	return nil
+}
+
+// swagger:model gettableGrafanaSilences
+type GettableGrafanaSilences []*GettableGrafanaSilence
+
+// mergeJSONFields encodes the value, which must encode to a JSON object, with the given fields added to it.
+func mergeJSONFields(v any, fields map[string]any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	merged := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &merged); err != nil {
+		return nil, err
+	}
+	for k, f := range fields {
+		if merged[k], err = json.Marshal(f); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(merged)
+}
+
 // swagger:model gettableAlerts
 type GettableAlerts = amv2.GettableAlerts
 
@@ -544,6 +780,14 @@ type DatasourceUIDReference struct {
 	DatasourceUID string
 }
 
+// PostAlertingConfigResult is the response to saving an Alerting config.
+// swagger:model
+type PostAlertingConfigResult struct {
+	Message string `json:"message"`
+	// Warnings about the routes that group notifications by high cardinality labels.
+	Warnings []GroupByWarning `json:"warnings,omitempty"`
+}
+
 // swagger:model
 type PostableUserConfig struct {
 	TemplateFiles      map[string]string         `yaml:"template_files" json:"template_files"`
@@ -776,13 +1020,29 @@ func (c *GettableApiAlertingConfig) validate() error {
 		return fmt.Errorf("cannot mix Alertmanager & Grafana receiver types")
 	}
 
-	for _, receiver := range AllReceivers(c.Route.AsAMRoute()) {
+	routeReceivers := AllReceivers(c.Route.AsAMRoute())
+	for _, n := range c.NamedRoutes {
+		routeReceivers = append(routeReceivers, AllReceivers(n.Route.AsAMRoute())...)
+	}
+	for _, receiver := range routeReceivers {
 		_, ok := receivers[receiver]
 		if !ok {
 			return fmt.Errorf("unexpected receiver (%s) is undefined", receiver)
 		}
 	}
 
+	for _, l := range c.RateLimits {
+		if _, ok := receivers[l.Receiver]; !ok {
+			return fmt.Errorf("rate limit references undefined receiver (%s)", l.Receiver)
+		}
+	}
+
+	if n := c.SilenceExpiryNotifications; n != nil {
+		if _, ok := receivers[n.Receiver]; !ok {
+			return fmt.Errorf("silence expiry notifications reference undefined receiver (%s)", n.Receiver)
+		}
+	}
+
 	return nil
 }
 
@@ -793,6 +1053,164 @@ type Config struct {
 	InhibitRules      []config.InhibitRule      `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
 	MuteTimeIntervals []config.MuteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
 	Templates         []string                  `yaml:"templates" json:"templates"`
+	// RateLimits limit the number of notifications sent by the integrations of receivers.
+	RateLimits []NotificationRateLimit `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
+	// NamedRoutes are reusable subtrees of the routing tree, which routes reference by name.
+	NamedRoutes []NamedRoute `yaml:"named_routes,omitempty" json:"named_routes,omitempty"`
+	// SilenceExpiryNotifications notify a receiver when long silences are about to expire.
+	SilenceExpiryNotifications *SilenceExpiryNotifications `yaml:"silence_expiry_notifications,omitempty" json:"silence_expiry_notifications,omitempty"`
+	// MaintenanceCalendars are iCalendar feeds whose events are imported as scheduled silences.
+	MaintenanceCalendars []MaintenanceCalendar `yaml:"maintenance_calendars,omitempty" json:"maintenance_calendars,omitempty"`
+}
+
+// SilenceExpiryNotifications configures the notifications that are sent when silences are about to expire,
+// so that the alerts they silence are not a surprise to the people who created them.
+type SilenceExpiryNotifications struct {
+	// Name of the receiver that is notified.
+	Receiver string `yaml:"receiver" json:"receiver"`
+	// Only silences that last at least this long are notified about. Defaults to 24h.
+	MinDuration model.Duration `yaml:"min_duration,omitempty" json:"min_duration,omitempty"`
+	// How long before the end of a silence its expiry is notified. Defaults to 1h.
+	Before model.Duration `yaml:"before,omitempty" json:"before,omitempty"`
+}
+
+const (
+	DefaultSilenceExpiryMinDuration = model.Duration(24 * time.Hour)
+	DefaultSilenceExpiryBefore      = model.Duration(time.Hour)
+)
+
+// Validate returns an error if the notifications are not valid.
+func (n SilenceExpiryNotifications) Validate() error {
+	if n.Receiver == "" {
+		return fmt.Errorf("missing receiver in silence expiry notifications")
+	}
+	if n.MinDuration < 0 {
+		return fmt.Errorf("minimum duration of silence expiry notifications must not be negative")
+	}
+	if n.Before < 0 {
+		return fmt.Errorf("time before expiry of silence expiry notifications must not be negative")
+	}
+	return nil
+}
+
+// GetMinDuration returns the minimum duration of the silences that are notified about, or its default.
+func (n SilenceExpiryNotifications) GetMinDuration() time.Duration {
+	if n.MinDuration == 0 {
+		return time.Duration(DefaultSilenceExpiryMinDuration)
+	}
+	return time.Duration(n.MinDuration)
+}
+
+// GetBefore returns how long before the end of a silence its expiry is notified, or its default.
+func (n SilenceExpiryNotifications) GetBefore() time.Duration {
+	if n.Before == 0 {
+		return time.Duration(DefaultSilenceExpiryBefore)
+	}
+	return time.Duration(n.Before)
+}
+
+// MaintenanceCalendar is an iCalendar feed, such as the export of a change calendar, whose events are imported as
+// silences. The feed is fetched on an interval, and the silences are created, updated and expired as the events
+// of the feed change. Recurring events are not expanded, only their first occurrence is imported. The silences of a
+// calendar that is removed from the configuration are kept until they end.
+type MaintenanceCalendar struct {
+	// Name of the calendar. It identifies the silences that are imported from the calendar.
+	Name string `yaml:"name" json:"name"`
+	// URL of the feed. It must be an absolute http or https URL.
+	URL string `yaml:"url" json:"url"`
+	// Matchers of the alerts the events silence.
+	Matchers config.Matchers `yaml:"matchers" json:"matchers"`
+	// How often the feed is fetched. Defaults to 15m.
+	SyncInterval model.Duration `yaml:"sync_interval,omitempty" json:"sync_interval,omitempty"`
+	// Only events that start within this time are imported. Defaults to 7d.
+	Lookahead model.Duration `yaml:"lookahead,omitempty" json:"lookahead,omitempty"`
+}
+
+const (
+	DefaultMaintenanceCalendarSyncInterval = model.Duration(15 * time.Minute)
+	DefaultMaintenanceCalendarLookahead    = model.Duration(7 * 24 * time.Hour)
+)
+
+// Validate returns an error if the calendar is not valid.
+func (c MaintenanceCalendar) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("missing name in maintenance calendar")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url of maintenance calendar %q must be an absolute http or https URL", c.Name)
+	}
+	if len(c.Matchers) == 0 {
+		return fmt.Errorf("maintenance calendar %q must have at least one matcher", c.Name)
+	}
+	if c.SyncInterval < 0 {
+		return fmt.Errorf("sync interval of maintenance calendar %q must not be negative", c.Name)
+	}
+	if c.Lookahead < 0 {
+		return fmt.Errorf("lookahead of maintenance calendar %q must not be negative", c.Name)
+	}
+	return nil
+}
+
+// GetSyncInterval returns how often the feed is fetched, or its default.
+func (c MaintenanceCalendar) GetSyncInterval() time.Duration {
+	if c.SyncInterval == 0 {
+		return time.Duration(DefaultMaintenanceCalendarSyncInterval)
+	}
+	return time.Duration(c.SyncInterval)
+}
+
+// GetLookahead returns how long in advance events are imported, or its default.
+func (c MaintenanceCalendar) GetLookahead() time.Duration {
+	if c.Lookahead == 0 {
+		return time.Duration(DefaultMaintenanceCalendarLookahead)
+	}
+	return time.Duration(c.Lookahead)
+}
+
+// NotificationOverflow defines what happens to the notifications that exceed a rate limit.
+type NotificationOverflow string
+
+const (
+	// NotificationOverflowDrop drops the notifications that exceed the limit.
+	NotificationOverflowDrop NotificationOverflow = "drop"
+	// NotificationOverflowQueue delays the notifications that exceed the limit until the next interval.
+	NotificationOverflowQueue NotificationOverflow = "queue"
+	// NotificationOverflowSummary holds back the alerts of the notifications that exceed the limit by alert group,
+	// and sends them in one notification per group once the next interval starts.
+	NotificationOverflowSummary NotificationOverflow = "summary"
+)
+
+// NotificationRateLimit limits the number of notifications sent by each integration of a receiver.
+type NotificationRateLimit struct {
+	// Name of the receiver the limit applies to.
+	Receiver string `yaml:"receiver" json:"receiver"`
+	// Type of the integrations the limit applies to, e.g. slack. If empty, the limit applies to all integrations of the receiver.
+	Integration string `yaml:"integration,omitempty" json:"integration,omitempty"`
+	// Maximum number of notifications sent per interval.
+	Limit    int            `yaml:"limit" json:"limit"`
+	Interval model.Duration `yaml:"interval" json:"interval"`
+	// What happens to the notifications that exceed the limit: drop, queue or summary. Defaults to drop.
+	Overflow NotificationOverflow `yaml:"overflow,omitempty" json:"overflow,omitempty"`
+}
+
+// Validate returns an error if the limit is not valid.
+func (l NotificationRateLimit) Validate() error {
+	if l.Receiver == "" {
+		return fmt.Errorf("missing receiver in rate limit")
+	}
+	if l.Limit <= 0 {
+		return fmt.Errorf("limit of the rate limit of receiver %q must be greater than zero", l.Receiver)
+	}
+	if l.Interval <= 0 {
+		return fmt.Errorf("interval of the rate limit of receiver %q must be greater than zero", l.Receiver)
+	}
+	switch l.Overflow {
+	case "", NotificationOverflowDrop, NotificationOverflowQueue, NotificationOverflowSummary:
+	default:
+		return fmt.Errorf("invalid overflow %q of the rate limit of receiver %q, must be one of drop, queue or summary", l.Overflow, l.Receiver)
+	}
+	return nil
 }
 
 // A Route is a node that contains definitions of how to handle alerts. This is modified
@@ -817,9 +1235,35 @@ type Route struct {
 	GroupInterval  *model.Duration `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
 	RepeatInterval *model.Duration `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty"`
 
+	// Ref is the name of a named route that is expanded in place of this route. The settings of this route
+	// take precedence over the settings of the named route, and the child routes of the named route are
+	// added after the child routes of this route.
+	Ref string `yaml:"ref,omitempty" json:"ref,omitempty"`
+
+	// Description is a free-form comment on the route. It has no effect on routing and is kept so that policy
+	// trees managed as code remain readable after an export and import.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
 	Provenance Provenance `yaml:"provenance,omitempty" json:"provenance,omitempty"`
 }
 
+// NamedRoute is a reusable subtree of the notification policy tree, e.g. the escalation policy of a team,
+// which routes reference by name.
+// swagger:model
+type NamedRoute struct {
+	Name       string     `yaml:"name" json:"name"`
+	Route      *Route     `yaml:"route" json:"route"`
+	Provenance Provenance `yaml:"provenance,omitempty" json:"provenance,omitempty"`
+}
+
+func (n *NamedRoute) ResourceType() string {
+	return "namedRoute"
+}
+
+func (n *NamedRoute) ResourceID() string {
+	return n.Name
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface for Route. This is a copy of alertmanager's upstream except it removes validation on the label key.
 func (r *Route) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	type plain Route
@@ -882,6 +1326,97 @@ func AsGrafanaRoute(r *config.Route) *Route {
 	return gRoute
 }
 
+// ExpandRoute returns a copy of the route in which the routes that reference a named route are merged with it.
+// It returns an error if a route references a named route that does not exist, or if the references form a cycle.
+func ExpandRoute(r *Route, named []NamedRoute) (*Route, error) {
+	byName := make(map[string]*Route, len(named))
+	for _, n := range named {
+		byName[n.Name] = n.Route
+	}
+	return expandRoute(r, byName, nil)
+}
+
+// expandRoute expands the references of the route. The path is the list of named routes that are being expanded.
+func expandRoute(r *Route, named map[string]*Route, path []string) (*Route, error) {
+	expanded := *r
+	expanded.Ref = ""
+	expanded.Routes = make([]*Route, 0, len(r.Routes))
+	for _, child := range r.Routes {
+		c, err := expandRoute(child, named, path)
+		if err != nil {
+			return nil, err
+		}
+		expanded.Routes = append(expanded.Routes, c)
+	}
+	if r.Ref == "" {
+		return &expanded, nil
+	}
+
+	path = append(slices.Clone(path), r.Ref)
+	if slices.Index(path, r.Ref) < len(path)-1 {
+		return nil, fmt.Errorf("named routes form a cycle: %s", strings.Join(path, " -> "))
+	}
+	target, ok := named[r.Ref]
+	if !ok || target == nil {
+		return nil, fmt.Errorf("route references named route %q that does not exist", r.Ref)
+	}
+	base, err := expandRoute(target, named, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if expanded.Receiver == "" {
+		expanded.Receiver = base.Receiver
+	}
+	if expanded.GroupByStr == nil {
+		expanded.GroupByStr, expanded.GroupBy, expanded.GroupByAll = base.GroupByStr, base.GroupBy, base.GroupByAll
+	}
+	if len(expanded.Match) == 0 && len(expanded.MatchRE) == 0 && len(expanded.Matchers) == 0 && len(expanded.ObjectMatchers) == 0 {
+		expanded.Match, expanded.MatchRE, expanded.Matchers, expanded.ObjectMatchers = base.Match, base.MatchRE, base.Matchers, base.ObjectMatchers
+	}
+	if expanded.MuteTimeIntervals == nil {
+		expanded.MuteTimeIntervals = base.MuteTimeIntervals
+	}
+	if expanded.GroupWait == nil {
+		expanded.GroupWait = base.GroupWait
+	}
+	if expanded.GroupInterval == nil {
+		expanded.GroupInterval = base.GroupInterval
+	}
+	if expanded.RepeatInterval == nil {
+		expanded.RepeatInterval = base.RepeatInterval
+	}
+	expanded.Routes = append(expanded.Routes, base.Routes...)
+	return &expanded, nil
+}
+
+// ValidateNamedRoutes returns an error if a named route is not valid, if the names are not unique,
+// or if the named routes reference named routes that do not exist or form a cycle.
+func ValidateNamedRoutes(named []NamedRoute) error {
+	names := make(map[string]struct{}, len(named))
+	for _, n := range named {
+		if n.Name == "" {
+			return fmt.Errorf("missing name in named route")
+		}
+		if _, ok := names[n.Name]; ok {
+			return fmt.Errorf("named route %q is not unique", n.Name)
+		}
+		names[n.Name] = struct{}{}
+		if n.Route == nil {
+			return fmt.Errorf("named route %q has no route", n.Name)
+		}
+		if err := n.Route.validateChild(); err != nil {
+			return fmt.Errorf("invalid named route %q: %w", n.Name, err)
+		}
+	}
+	for _, n := range named {
+		if _, err := ExpandRoute(&Route{Ref: n.Name}, named); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *Route) ResourceType() string {
 	return "route"
 }
@@ -933,6 +1468,47 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 		}
 		tiNames[mt.Name] = struct{}{}
 	}
+
+	limits := make(map[string]struct{}, len(c.RateLimits))
+	for _, l := range c.RateLimits {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+		key := l.Receiver + "/" + l.Integration
+		if _, ok := limits[key]; ok {
+			return fmt.Errorf("rate limit of receiver %q and integration %q is not unique", l.Receiver, l.Integration)
+		}
+		limits[key] = struct{}{}
+	}
+
+	if c.SilenceExpiryNotifications != nil {
+		if err := c.SilenceExpiryNotifications.Validate(); err != nil {
+			return err
+		}
+	}
+
+	calendars := make(map[string]struct{}, len(c.MaintenanceCalendars))
+	for _, cal := range c.MaintenanceCalendars {
+		if err := cal.Validate(); err != nil {
+			return err
+		}
+		if _, ok := calendars[cal.Name]; ok {
+			return fmt.Errorf("maintenance calendar %q is not unique", cal.Name)
+		}
+		calendars[cal.Name] = struct{}{}
+	}
+
+	if err := ValidateNamedRoutes(c.NamedRoutes); err != nil {
+		return err
+	}
+	if _, err := ExpandRoute(c.Route, c.NamedRoutes); err != nil {
+		return err
+	}
+	for _, n := range c.NamedRoutes {
+		if err := checkTimeInterval(n.Route, tiNames); err != nil {
+			return err
+		}
+	}
 	return checkTimeInterval(c.Route, tiNames)
 }
 
@@ -1023,6 +1599,12 @@ func (c *PostableApiAlertingConfig) validate() error {
 		}
 	}
 
+	if n := c.SilenceExpiryNotifications; n != nil {
+		if _, ok := receivers[n.Receiver]; !ok {
+			return fmt.Errorf("silence expiry notifications reference undefined receiver (%s)", n.Receiver)
+		}
+	}
+
 	return nil
 }
 
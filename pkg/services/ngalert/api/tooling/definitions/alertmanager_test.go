@@ -6,8 +6,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -141,6 +143,103 @@ func Test_AllReceivers(t *testing.T) {
 	require.Equal(t, empty, AllReceivers(emptyRoute.AsAMRoute()))
 }
 
+func Test_ExpandRoute(t *testing.T) {
+	wait := model.Duration(time.Minute)
+	escalation := NamedRoute{
+		Name: "escalation",
+		Route: &Route{
+			Receiver:   "team",
+			GroupByStr: []string{"alertname"},
+			GroupWait:  &wait,
+			Routes: []*Route{
+				{Receiver: "pager", ObjectMatchers: ObjectMatchers{{Type: labels.MatchEqual, Name: "severity", Value: "critical"}}},
+			},
+		},
+	}
+
+	t.Run("should merge the named route into the routes that reference it", func(t *testing.T) {
+		tree := &Route{
+			Receiver: "default",
+			Routes: []*Route{
+				{
+					Ref:            "escalation",
+					ObjectMatchers: ObjectMatchers{{Type: labels.MatchEqual, Name: "team", Value: "a"}},
+					Routes:         []*Route{{Receiver: "a-chat"}},
+				},
+				{
+					Ref:            "escalation",
+					Receiver:       "team-b",
+					ObjectMatchers: ObjectMatchers{{Type: labels.MatchEqual, Name: "team", Value: "b"}},
+				},
+			},
+		}
+
+		expanded, err := ExpandRoute(tree, []NamedRoute{escalation})
+		require.NoError(t, err)
+		require.Len(t, expanded.Routes, 2)
+
+		a := expanded.Routes[0]
+		require.Empty(t, a.Ref)
+		require.Equal(t, "team", a.Receiver)
+		require.Equal(t, []string{"alertname"}, a.GroupByStr)
+		require.Equal(t, &wait, a.GroupWait)
+		require.Equal(t, "a", a.ObjectMatchers[0].Value)
+		require.Len(t, a.Routes, 2)
+		require.Equal(t, "a-chat", a.Routes[0].Receiver)
+		require.Equal(t, "pager", a.Routes[1].Receiver)
+
+		b := expanded.Routes[1]
+		require.Equal(t, "team-b", b.Receiver)
+		require.Equal(t, "b", b.ObjectMatchers[0].Value)
+		require.Len(t, b.Routes, 1)
+
+		// the tree and the named route are not modified
+		require.Equal(t, "escalation", tree.Routes[0].Ref)
+		require.Len(t, tree.Routes[0].Routes, 1)
+		require.Len(t, escalation.Route.Routes, 1)
+	})
+
+	t.Run("should expand references in named routes", func(t *testing.T) {
+		named := []NamedRoute{escalation, {Name: "team-a", Route: &Route{Ref: "escalation", Receiver: "a"}}}
+		expanded, err := ExpandRoute(&Route{Receiver: "default", Routes: []*Route{{Ref: "team-a"}}}, named)
+		require.NoError(t, err)
+		require.Equal(t, "a", expanded.Routes[0].Receiver)
+		require.Equal(t, "pager", expanded.Routes[0].Routes[0].Receiver)
+	})
+
+	t.Run("should fail if the named route does not exist", func(t *testing.T) {
+		_, err := ExpandRoute(&Route{Receiver: "default", Routes: []*Route{{Ref: "missing"}}}, []NamedRoute{escalation})
+		require.ErrorContains(t, err, `named route "missing" that does not exist`)
+	})
+
+	t.Run("should fail if named routes form a cycle", func(t *testing.T) {
+		named := []NamedRoute{
+			{Name: "a", Route: &Route{Routes: []*Route{{Ref: "b"}}}},
+			{Name: "b", Route: &Route{Routes: []*Route{{Routes: []*Route{{Ref: "a"}}}}}},
+		}
+		_, err := ExpandRoute(&Route{Receiver: "default", Routes: []*Route{{Ref: "a"}}}, named)
+		require.ErrorContains(t, err, "named routes form a cycle: a -> b -> a")
+		require.ErrorContains(t, ValidateNamedRoutes(named), "cycle")
+	})
+
+	t.Run("should allow referencing a named route several times", func(t *testing.T) {
+		_, err := ExpandRoute(&Route{Receiver: "default", Routes: []*Route{{Ref: "escalation", Routes: []*Route{{Ref: "escalation"}}}}}, []NamedRoute{escalation})
+		require.NoError(t, err)
+
+		_, err = ExpandRoute(&Route{Receiver: "default", Routes: []*Route{{Ref: "escalation"}, {Ref: "escalation"}}}, []NamedRoute{escalation})
+		require.NoError(t, err)
+	})
+}
+
+func Test_ValidateNamedRoutes(t *testing.T) {
+	require.NoError(t, ValidateNamedRoutes(nil))
+	require.ErrorContains(t, ValidateNamedRoutes([]NamedRoute{{Route: &Route{}}}), "missing name")
+	require.ErrorContains(t, ValidateNamedRoutes([]NamedRoute{{Name: "a"}}), "has no route")
+	require.ErrorContains(t, ValidateNamedRoutes([]NamedRoute{{Name: "a", Route: &Route{}}, {Name: "a", Route: &Route{}}}), "not unique")
+	require.ErrorContains(t, ValidateNamedRoutes([]NamedRoute{{Name: "a", Route: &Route{Ref: "b"}}}), "does not exist")
+	require.ErrorContains(t, ValidateNamedRoutes([]NamedRoute{{Name: "a", Route: &Route{GroupByStr: []string{"x", "x"}}}}), "duplicated label")
+}
+
 func Test_ApiAlertingConfig_Marshaling(t *testing.T) {
 	for _, tc := range []struct {
 		desc  string
@@ -58,6 +58,60 @@ func (r *Route) validateChild() error {
 	return nil
 }
 
+// GroupByWarning is a warning about a route of the notification policy tree that groups notifications by
+// a high cardinality label, which creates a notification group for each of its values.
+// swagger:model
+type GroupByWarning struct {
+	// Path of the route, e.g. route.routes[0] or named_routes[escalation].routes[1].
+	Route string `json:"route"`
+	// Label is the high cardinality label, or ... if the route groups by all labels.
+	Label   string `json:"label"`
+	Message string `json:"message"`
+}
+
+// CheckGroupByCardinality returns a warning for each route of the tree, and of the named routes, that groups
+// notifications by one of the high cardinality labels or by all labels. It returns nothing if there are no
+// high cardinality labels.
+func CheckGroupByCardinality(c *Config, highCardinalityLabels map[string]struct{}) []GroupByWarning {
+	if len(highCardinalityLabels) == 0 {
+		return nil
+	}
+	var warnings []GroupByWarning
+	if c.Route != nil {
+		warnings = checkGroupByCardinality(c.Route, "route", highCardinalityLabels, warnings)
+	}
+	for _, named := range c.NamedRoutes {
+		if named.Route != nil {
+			warnings = checkGroupByCardinality(named.Route, fmt.Sprintf("named_routes[%s]", named.Name), highCardinalityLabels, warnings)
+		}
+	}
+	return warnings
+}
+
+func checkGroupByCardinality(r *Route, path string, highCardinalityLabels map[string]struct{}, warnings []GroupByWarning) []GroupByWarning {
+	for _, label := range r.GroupByStr {
+		if label == "..." {
+			warnings = append(warnings, GroupByWarning{
+				Route:   path,
+				Label:   label,
+				Message: "grouping by all labels creates a notification group for each distinct set of labels",
+			})
+			continue
+		}
+		if _, ok := highCardinalityLabels[label]; ok {
+			warnings = append(warnings, GroupByWarning{
+				Route:   path,
+				Label:   label,
+				Message: fmt.Sprintf("grouping by high cardinality label %q creates a notification group for each of its values", label),
+			})
+		}
+	}
+	for i, child := range r.Routes {
+		warnings = checkGroupByCardinality(child, fmt.Sprintf("%s.routes[%d]", path, i), highCardinalityLabels, warnings)
+	}
+	return warnings
+}
+
 func (t *NotificationTemplate) Validate() error {
 	if t.Name == "" {
 		return fmt.Errorf("template must have a name")
@@ -110,6 +164,9 @@ func (r *Route) Validate() error {
 	if len(r.MuteTimeIntervals) > 0 {
 		return fmt.Errorf("root route must not have any mute time intervals")
 	}
+	if r.Ref != "" {
+		return fmt.Errorf("root route must not reference a named route")
+	}
 	return r.validateChild()
 }
 
@@ -259,6 +259,33 @@ func TestValidateRoutes(t *testing.T) {
 	})
 }
 
+func TestCheckGroupByCardinality(t *testing.T) {
+	cfg := &Config{
+		Route: &Route{
+			Receiver:   "default",
+			GroupByStr: []string{"alertname"},
+			Routes: []*Route{
+				{Receiver: "a", GroupByStr: []string{"alertname", "pod"}},
+				{Receiver: "b", Routes: []*Route{{Receiver: "c", GroupByStr: []string{"..."}}}},
+			},
+		},
+		NamedRoutes: []NamedRoute{
+			{Name: "escalation", Route: &Route{GroupByStr: []string{"instance"}}},
+		},
+	}
+
+	require.Empty(t, CheckGroupByCardinality(cfg, nil))
+
+	warnings := CheckGroupByCardinality(cfg, map[string]struct{}{"pod": {}, "instance": {}})
+	require.Len(t, warnings, 3)
+	require.Equal(t, "route.routes[0]", warnings[0].Route)
+	require.Equal(t, "pod", warnings[0].Label)
+	require.Equal(t, "route.routes[1].routes[0]", warnings[1].Route)
+	require.Equal(t, "...", warnings[1].Label)
+	require.Equal(t, "named_routes[escalation]", warnings[2].Route)
+	require.Equal(t, "instance", warnings[2].Label)
+}
+
 func TestValidateMuteTimeInterval(t *testing.T) {
 	type testCase struct {
 		desc   string
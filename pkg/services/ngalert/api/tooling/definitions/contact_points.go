@@ -98,6 +98,11 @@ type OnCallIntegration struct {
 	Password                 *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"basic_auth_password"`
 	Title                    *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
 	Message                  *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+
+	EscalationTeamLabel       *string            `json:"escalationTeamLabel,omitempty" yaml:"escalationTeamLabel,omitempty" hcl:"escalation_team_label"`
+	EscalationPriorityLabel   *string            `json:"escalationPriorityLabel,omitempty" yaml:"escalationPriorityLabel,omitempty" hcl:"escalation_priority_label"`
+	EscalationPriorities      *map[string]string `json:"escalationPriorities,omitempty" yaml:"escalationPriorities,omitempty" hcl:"escalation_priorities"`
+	EscalationDefaultPriority *string            `json:"escalationDefaultPriority,omitempty" yaml:"escalationDefaultPriority,omitempty" hcl:"escalation_default_priority"`
 }
 
 type OpsgenieIntegrationResponder struct {
@@ -33,6 +33,15 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Get /ruler/grafana/api/v1/export/rule/{RuleUID} ruler RouteGetRuleForExport
+//
+// Get a rule and its group in provisioning format
+//
+//     Responses:
+//       200: AlertingFileExport
+//       403: ForbiddenError
+//       404: description: Not found.
+
 // swagger:route Get /ruler/{DatasourceUID}/api/v1/rules ruler RouteGetRulesConfig
 //
 // List rule groups
@@ -58,6 +67,87 @@ import (
 //       403: ForbiddenError
 //
 
+// swagger:route POST /ruler/grafana/api/v1/rules/import ruler RoutePostRulesImport
+//
+// Creates or updates the rule groups of multiple folders in a single transaction. Rule groups that are not in the request are not changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route POST /ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/rename ruler RoutePostRenameRuleGroup
+//
+// Renames a rule group. The rules keep their UIDs, so that their state and history are preserved.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+//       409: GenericPublicError
+
+// swagger:route POST /ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/clone ruler RoutePostCloneRuleGroup
+//
+// Copies a rule group into a folder. The copied rules get new UIDs and are not provisioned.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+//       409: GenericPublicError
+
+// swagger:route POST /v1/rules/pause ruler RoutePostPauseRules
+//
+// Pauses or resumes the rules with the UIDs, or the rules of a rule group. The rules are updated in one transaction.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route GET /ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/versions ruler RouteGetRuleGroupVersions
+//
+// Gets the versions of the rules of a rule group, latest first.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRuleVersions
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route POST /ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/versions/restore ruler RoutePostRuleVersionRestore
+//
+// Restores a version of a rule of the rule group. The rule keeps its place in the group and its pause state, and
+// the restored rule is saved as a new version.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+//       409: GenericPublicError
+
 // swagger:route POST /ruler/grafana/api/v1/rules/{Namespace}/export ruler RoutePostRulesGroupForExport
 //
 // Converts submitted rule group to provisioning format
@@ -173,6 +263,135 @@ type NamespaceConfig struct {
 	Body PostableRuleGroupConfig
 }
 
+// swagger:parameters RoutePostRulesImport
+type RulesImportParams struct {
+	// in:body
+	Body PostableRulesImport
+}
+
+// swagger:parameters RouteGetRuleForExport
+type RuleExportParams struct {
+	// UID of the alert rule to export
+	// in: path
+	RuleUID string
+}
+
+// swagger:parameters RoutePostRenameRuleGroup
+type RenameRuleGroupParams struct {
+	// The UID of the rule folder
+	// in: path
+	Namespace string
+	// in: path
+	Groupname string
+	// in:body
+	Body PostableRuleGroupRename
+}
+
+// swagger:model
+type PostableRuleGroupRename struct {
+	// The new name of the rule group. It must not be the name of another rule group of the folder.
+	// required: true
+	Name string `json:"name"`
+}
+
+// swagger:parameters RoutePostCloneRuleGroup
+type CloneRuleGroupParams struct {
+	// The UID of the rule folder
+	// in: path
+	Namespace string
+	// in: path
+	Groupname string
+	// in:body
+	Body PostableRuleGroupClone
+}
+
+// swagger:model
+type PostableRuleGroupClone struct {
+	// The UID of the folder the rule group is copied into. It must not have a rule group with the name of the copy.
+	// required: true
+	FolderUID string `json:"folderUid"`
+	// The name of the copy. Defaults to the name of the rule group.
+	Name string `json:"name,omitempty"`
+	// A prefix that is added to the titles of the copied rules. It is required if the rule group is copied into
+	// its own folder, as the titles of the rules of a folder must be unique.
+	TitlePrefix string `json:"titlePrefix,omitempty"`
+}
+
+// swagger:parameters RoutePostPauseRules
+type PauseRulesParams struct {
+	// in:body
+	Body PostableRulesPause
+}
+
+// swagger:model
+type PostableRulesPause struct {
+	// The UIDs of the rules to pause or resume. Either the UIDs of the rules or a folder and a rule group must be set.
+	RuleUIDs []string `json:"ruleUids,omitempty"`
+	// The UID of the folder of the rule group to pause or resume.
+	FolderUID string `json:"folderUid,omitempty"`
+	// The name of the rule group to pause or resume.
+	RuleGroup string `json:"ruleGroup,omitempty"`
+	// Whether the rules are paused or resumed.
+	// required: true
+	IsPaused bool `json:"isPaused"`
+}
+
+// swagger:parameters RouteGetRuleGroupVersions
+type RuleGroupVersionsParams struct {
+	// The UID of the rule folder
+	// in: path
+	Namespace string
+	// in: path
+	Groupname string
+	// Only return the versions of the rule with this UID
+	// in: query
+	// required: false
+	RuleUID string `json:"ruleUID"`
+}
+
+// swagger:parameters RoutePostRuleVersionRestore
+type RestoreRuleVersionParams struct {
+	// The UID of the rule folder
+	// in: path
+	Namespace string
+	// in: path
+	Groupname string
+	// in:body
+	Body PostableRuleVersionRestore
+}
+
+// swagger:model
+type PostableRuleVersionRestore struct {
+	// The UID of the rule to restore. The rule must be in the rule group.
+	// required: true
+	RuleUID string `json:"ruleUID"`
+	// The version to restore.
+	// required: true
+	Version int64 `json:"version"`
+}
+
+// swagger:model
+type GettableRuleVersion struct {
+	UID       string       `json:"uid"`
+	Version   int64        `json:"version"`
+	Title     string       `json:"title"`
+	Condition string       `json:"condition"`
+	Data      []AlertQuery `json:"data"`
+	// The rule group of the rule in this version.
+	RuleGroup string `json:"ruleGroup"`
+	// The namespaced ID of the identity that saved the version, e.g. user:1. It is empty if the version was
+	// saved without the ruler API, or before the identity was recorded.
+	UpdatedBy string    `json:"updatedBy,omitempty"`
+	Updated   time.Time `json:"updated"`
+}
+
+// swagger:model
+type GettableRuleVersions []GettableRuleVersion
+
+// PostableRulesImport is the rule groups to create or update, keyed by the UID of their folder.
+// swagger:model
+type PostableRulesImport map[string][]PostableRuleGroupConfig
+
 // swagger:parameters RouteGetNamespaceRulesConfig RouteDeleteNamespaceRulesConfig RouteGetNamespaceGrafanaRulesConfig RouteDeleteNamespaceGrafanaRulesConfig
 type PathNamespaceConfig struct {
 	// The UID of the rule folder
@@ -197,6 +416,26 @@ type PathGetRulesParams struct {
 	PanelID int64
 }
 
+// swagger:parameters RouteGetGrafanaRulesConfig
+type RulesPaginationParams struct {
+	// The maximum number of rule groups to return. Rule groups are sorted by the UID of their folder and their name.
+	// If there are more rule groups, the token of the next page is returned in the X-Grafana-Continue-Token header.
+	// All rule groups are returned if it is not set.
+	// in: query
+	// required: false
+	Limit int64 `json:"limit"`
+	// The token of the page to return, as returned by the previous page.
+	// in: query
+	// required: false
+	Continue string `json:"continue"`
+	// Label matchers of the rules to return, in the JSON format of the Prometheus label matchers, for example
+	// {"Type":0,"Name":"team","Value":"alerting"}, or as strings such as team=alerting. Rule groups without matching
+	// rules are not returned.
+	// in: query
+	// required: false
+	Matcher []string `json:"matcher"`
+}
+
 // swagger:model
 type RuleGroupConfigResponse struct {
 	GettableRuleGroupConfig
@@ -205,22 +444,54 @@ type RuleGroupConfigResponse struct {
 // swagger:model
 type NamespaceConfigResponse map[string][]GettableRuleGroupConfig
 
+// IntervalInherit is the interval of a rule group that inherits the default evaluation interval of the organization.
+const IntervalInherit = "inherit"
+
 // swagger:model
 type PostableRuleGroupConfig struct {
 	Name     string                     `yaml:"name" json:"name"`
 	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Labels   map[string]string          `yaml:"labels,omitempty" json:"labels,omitempty"`
 	Rules    []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
+
+	// InheritInterval is true if the interval of the group is "inherit".
+	InheritInterval bool `yaml:"-" json:"-"`
 }
 
 func (c *PostableRuleGroupConfig) UnmarshalJSON(b []byte) error {
 	type plain PostableRuleGroupConfig
-	if err := json.Unmarshal(b, (*plain)(c)); err != nil {
+	aux := struct {
+		*plain
+		Interval string `json:"interval,omitempty"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(b, &aux); err != nil {
 		return err
 	}
 
+	c.Interval = 0
+	c.InheritInterval = aux.Interval == IntervalInherit
+	if aux.Interval != "" && !c.InheritInterval {
+		interval, err := model.ParseDuration(aux.Interval)
+		if err != nil {
+			return err
+		}
+		c.Interval = interval
+	}
+
 	return c.validate()
 }
 
+func (c PostableRuleGroupConfig) MarshalJSON() ([]byte, error) {
+	type plain PostableRuleGroupConfig
+	if !c.InheritInterval {
+		return json.Marshal(plain(c))
+	}
+	return json.Marshal(struct {
+		plain
+		Interval string `json:"interval"`
+	}{plain: plain(c), Interval: IntervalInherit})
+}
+
 // Type requires validate has been called and just checks the first rule type
 func (c *PostableRuleGroupConfig) Type() (backend Backend) {
 	for _, rule := range c.Rules {
@@ -256,7 +527,11 @@ type GettableRuleGroupConfig struct {
 	Name          string                     `yaml:"name" json:"name"`
 	Interval      model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
 	SourceTenants []string                   `yaml:"source_tenants,omitempty" json:"source_tenants,omitempty"`
+	Labels        map[string]string          `yaml:"labels,omitempty" json:"labels,omitempty"`
 	Rules         []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
+	// InheritInterval is true if the group inherits the default evaluation interval of the organization.
+	// Interval is then the effective interval of the group.
+	InheritInterval bool `yaml:"inheritInterval,omitempty" json:"inheritInterval,omitempty"`
 }
 
 func (c *GettableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -415,6 +690,15 @@ type PostableGrafanaRule struct {
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	IsPaused     *bool               `json:"is_paused" yaml:"is_paused"`
+	// Names of the labels that identify the alert instances of the rule. If empty, all labels do.
+	FingerprintLabels []string `json:"fingerprint_labels,omitempty" yaml:"fingerprint_labels,omitempty"`
+	// Evaluate expressions when some, but not all, of their inputs failed.
+	PartialResults bool `json:"partial_results,omitempty" yaml:"partial_results,omitempty"`
+	// Number of evaluations in a row a series must be missing from before its alert instance is resolved.
+	// If neither it nor missing_series_duration_to_resolve is set, the policy of the organization applies.
+	MissingSeriesEvalsToResolve int64 `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	// How long a series must be missing before its alert instance is resolved. It cannot be set with missing_series_evals_to_resolve.
+	MissingSeriesDurationToResolve model.Duration `json:"missing_series_duration_to_resolve,omitempty" yaml:"missing_series_duration_to_resolve,omitempty"`
 }
 
 // swagger:model
@@ -434,6 +718,15 @@ type GettableGrafanaRule struct {
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      Provenance          `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	// Names of the labels that identify the alert instances of the rule. If empty, all labels do.
+	FingerprintLabels []string `json:"fingerprint_labels,omitempty" yaml:"fingerprint_labels,omitempty"`
+	// Evaluate expressions when some, but not all, of their inputs failed.
+	PartialResults bool `json:"partial_results,omitempty" yaml:"partial_results,omitempty"`
+	// Number of evaluations in a row a series must be missing from before its alert instance is resolved.
+	// If neither it nor missing_series_duration_to_resolve is set, the policy of the organization applies.
+	MissingSeriesEvalsToResolve int64 `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	// How long a series must be missing before its alert instance is resolved. It cannot be set with missing_series_evals_to_resolve.
+	MissingSeriesDurationToResolve model.Duration `json:"missing_series_duration_to_resolve,omitempty" yaml:"missing_series_duration_to_resolve,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
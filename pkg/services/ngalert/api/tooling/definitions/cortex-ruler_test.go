@@ -3,6 +3,7 @@ package definitions
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
@@ -267,3 +268,32 @@ simple_rules:
 	require.Nil(t, err)
 	require.Equal(t, data, string(b))
 }
+
+func Test_Rule_Group_Inherit_Interval(t *testing.T) {
+	t.Run("inherit is parsed as flag", func(t *testing.T) {
+		var g PostableRuleGroupConfig
+		require.NoError(t, json.Unmarshal([]byte(`{"name":"group","interval":"inherit","rules":[{"grafana_alert":{}}]}`), &g))
+		require.True(t, g.InheritInterval)
+		require.Equal(t, model.Duration(0), g.Interval)
+
+		b, err := json.Marshal(g)
+		require.NoError(t, err)
+		require.Contains(t, string(b), `"interval":"inherit"`)
+	})
+
+	t.Run("duration is parsed as interval", func(t *testing.T) {
+		var g PostableRuleGroupConfig
+		require.NoError(t, json.Unmarshal([]byte(`{"name":"group","interval":"2m"}`), &g))
+		require.False(t, g.InheritInterval)
+		require.Equal(t, model.Duration(2*time.Minute), g.Interval)
+
+		b, err := json.Marshal(g)
+		require.NoError(t, err)
+		require.Contains(t, string(b), `"interval":"2m"`)
+	})
+
+	t.Run("invalid interval fails", func(t *testing.T) {
+		var g PostableRuleGroupConfig
+		require.Error(t, json.Unmarshal([]byte(`{"name":"group","interval":"often"}`), &g))
+	})
+}
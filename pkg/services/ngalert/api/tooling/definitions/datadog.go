@@ -0,0 +1,107 @@
+package definitions
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// swagger:route POST /ruler/grafana/api/v1/convert/datadog ruler RouteConvertDatadogMonitors
+//
+// Converts Datadog monitors to a rule group of Grafana-managed alert rules that query a Prometheus data source.
+// Queries that cannot be translated are replaced by a placeholder, the rule is paused and a TODO annotation
+// explains what is left to do. The rule group is not saved.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: DatadogMonitorsConversion
+//       400: ValidationError
+//       403: ForbiddenError
+
+// swagger:parameters RouteConvertDatadogMonitors
+type DatadogMonitorsConversionParams struct {
+	// The UID of the Prometheus data source queried by the rules.
+	// in: query
+	// required: true
+	DatasourceUID string `json:"datasourceUid"`
+	// The name of the rule group. Defaults to "Datadog".
+	// in: query
+	Group string `json:"group"`
+	// The evaluation interval of the rule group. Defaults to 1m.
+	// in: query
+	Interval string `json:"interval"`
+	// A monitor or a list of monitors as exported by Datadog.
+	// in: body
+	Body DatadogMonitors
+}
+
+// DatadogMonitors is a list of monitors exported by Datadog. A single monitor is accepted as well.
+//
+// swagger:model
+type DatadogMonitors []DatadogMonitor
+
+func (m *DatadogMonitors) UnmarshalJSON(b []byte) error {
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '{' {
+		var monitor DatadogMonitor
+		if err := json.Unmarshal(b, &monitor); err != nil {
+			return err
+		}
+		*m = DatadogMonitors{monitor}
+		return nil
+	}
+	var monitors []DatadogMonitor
+	if err := json.Unmarshal(b, &monitors); err != nil {
+		return err
+	}
+	*m = monitors
+	return nil
+}
+
+// DatadogMonitor is the subset of a Datadog monitor that is converted to an alert rule.
+//
+// swagger:model
+type DatadogMonitor struct {
+	ID      int64    `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Query   string   `json:"query"`
+	Message string   `json:"message,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	// Priority from 1 (highest) to 5.
+	Priority *int64                `json:"priority,omitempty"`
+	Options  DatadogMonitorOptions `json:"options,omitempty"`
+}
+
+type DatadogMonitorOptions struct {
+	Thresholds   *DatadogMonitorThresholds `json:"thresholds,omitempty"`
+	NotifyNoData bool                      `json:"notify_no_data,omitempty"`
+	// Number of seconds by which the evaluation is delayed.
+	EvaluationDelay int64 `json:"evaluation_delay,omitempty"`
+}
+
+type DatadogMonitorThresholds struct {
+	Critical *float64 `json:"critical,omitempty"`
+	Warning  *float64 `json:"warning,omitempty"`
+}
+
+// swagger:model
+type DatadogMonitorsConversion struct {
+	// Group is the converted rule group. It can be saved with the ruler API once the TODO annotations are addressed.
+	Group PostableRuleGroupConfig `json:"group"`
+	// Monitors reports the conversion of each monitor.
+	Monitors []DatadogMonitorConversion `json:"monitors"`
+}
+
+type DatadogMonitorConversion struct {
+	ID int64 `json:"id"`
+	// Title of the alert rule converted from the monitor.
+	Title string `json:"title"`
+	// Translated is false if the query of the monitor could not be translated and the rule was stubbed.
+	Translated bool `json:"translated"`
+	// Warnings are the parts of the monitor that were not converted.
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,94 @@
+package definitions
+
+// swagger:route GET /v1/ngalert/label_policies configuration RouteGetLabelPolicies
+//
+// Get the label policies of the user's organization and its folders.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: LabelPolicies
+//       500: Failure
+
+// swagger:route PUT /v1/ngalert/label_policies configuration RoutePutLabelPolicy
+//
+// Creates or replaces the label policy of the user's organization or, if folderUid is set, of the folder.
+// Existing alert rules are not validated, use the violations endpoint to list rules that do not satisfy the policies.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+
+// swagger:route DELETE /v1/ngalert/label_policies configuration RouteDeleteLabelPolicy
+//
+// Deletes the label policy of the user's organization or, if folderUid is set, of the folder.
+//
+//     Responses:
+//       202: Ack
+//       500: Failure
+
+// swagger:route GET /v1/ngalert/label_policies/violations configuration RouteGetLabelPolicyViolations
+//
+// Lists the labels of existing alert rules that do not satisfy the label policies.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: LabelPolicyViolations
+//       500: Failure
+
+// swagger:parameters RoutePutLabelPolicy
+type LabelPolicyParams struct {
+	// in:body
+	Body LabelPolicy
+}
+
+// swagger:parameters RouteDeleteLabelPolicy
+type DeleteLabelPolicyParams struct {
+	// UID of the folder whose policy is deleted. If empty, the policy of the organization is deleted.
+	// in:query
+	// required:false
+	FolderUID string `json:"folderUid"`
+}
+
+// swagger:model
+type LabelPolicy struct {
+	// UID of the folder the policy applies to. If empty, the policy applies to all alert rules of the organization.
+	// Requirements of a folder policy take precedence over the requirements of the organization policy for the same label.
+	FolderUID string             `json:"folderUid,omitempty"`
+	Labels    []LabelRequirement `json:"labels"`
+}
+
+type LabelRequirement struct {
+	// Name of the label.
+	Name string `json:"name"`
+	// If true, every alert rule must define the label.
+	Required bool `json:"required,omitempty"`
+	// If true, alert rules must not define the label.
+	Forbidden bool `json:"forbidden,omitempty"`
+	// Regular expression the whole value of the label must match.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// swagger:model
+type LabelPolicies []LabelPolicy
+
+// swagger:model
+type LabelPolicyViolations struct {
+	Message    string           `json:"message,omitempty"`
+	Violations []LabelViolation `json:"violations"`
+}
+
+type LabelViolation struct {
+	RuleUID      string `json:"ruleUid,omitempty"`
+	RuleTitle    string `json:"ruleTitle"`
+	NamespaceUID string `json:"namespaceUid"`
+	RuleGroup    string `json:"ruleGroup"`
+	Label        string `json:"label"`
+	Reason       string `json:"reason"`
+}
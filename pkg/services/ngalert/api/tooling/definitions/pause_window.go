@@ -0,0 +1,66 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /v1/ngalert/pause_windows configuration RouteGetEvaluationPauseWindows
+//
+// Get the evaluation pause windows of the user's organization and its folders that did not end yet.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: EvaluationPauseWindows
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/pause_windows configuration RoutePostEvaluationPauseWindow
+//
+// Creates an evaluation pause window. Alert rules of the organization or, if folderUid is set, of the folder
+// are not evaluated while the window is active, so they neither fire nor record state history.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       201: EvaluationPauseWindow
+//       400: ValidationError
+
+// swagger:route DELETE /v1/ngalert/pause_windows/{ID} configuration RouteDeleteEvaluationPauseWindow
+//
+// Deletes the evaluation pause window, resuming the evaluation of the paused alert rules.
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       404: NotFound
+//       500: Failure
+
+// swagger:parameters RoutePostEvaluationPauseWindow
+type EvaluationPauseWindowParams struct {
+	// in:body
+	Body EvaluationPauseWindow
+}
+
+// swagger:parameters RouteDeleteEvaluationPauseWindow
+type DeleteEvaluationPauseWindowParams struct {
+	// in:path
+	// required:true
+	ID int64
+}
+
+// swagger:model
+type EvaluationPauseWindow struct {
+	ID int64 `json:"id,omitempty"`
+	// UID of the folder whose alert rules are paused. If empty, all alert rules of the organization are paused.
+	FolderUID string    `json:"folderUid,omitempty"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+}
+
+// swagger:model
+type EvaluationPauseWindows []EvaluationPauseWindow
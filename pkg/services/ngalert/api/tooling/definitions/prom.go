@@ -24,6 +24,22 @@ import (
 //       200: RuleResponse
 //       404: NotFound
 
+// swagger:route GET /v1/rules/health prometheus RouteGetGrafanaRuleHealth
+//
+// gets the health of the Grafana managed rules aggregated by folder and by data source
+//
+//     Responses:
+//       200: RuleHealthResponse
+
+// swagger:route GET /v1/rules/scheduling/{Namespace} prometheus RouteGetGrafanaRuleScheduling
+//
+// gets the view of the scheduler on the Grafana managed rules of a folder: their last and next evaluations
+//
+//     Responses:
+//       200: RuleSchedulingResponse
+//       403: ForbiddenError
+//       404: NotFound
+
 // swagger:route GET /prometheus/grafana/api/v1/alerts prometheus RouteGetGrafanaAlertStatuses
 //
 // gets the current alerts
@@ -39,6 +55,20 @@ import (
 //       200: AlertResponse
 //       404: NotFound
 
+// swagger:route GET /prometheus/grafana/api/v1/status/buildinfo prometheus RouteGetGrafanaBuildInfo
+//
+// gets the build information of Grafana in the format of the Prometheus build information API
+//
+//     Responses:
+//       200: BuildInfoResponse
+
+// swagger:route GET /prometheus/grafana/api/v1/metadata prometheus RouteGetGrafanaMetricMetadata
+//
+// gets the metadata of the metrics of the alerts of the Grafana managed rules in the format of the Prometheus metadata API
+//
+//     Responses:
+//       200: MetricMetadataResponse
+
 // swagger:model
 type RuleResponse struct {
 	// in: body
@@ -138,6 +168,8 @@ type AlertingRule struct {
 	Alerts         []Alert          `json:"alerts,omitempty"`
 	Totals         map[string]int64 `json:"totals,omitempty"`
 	TotalsFiltered map[string]int64 `json:"totalsFiltered,omitempty"`
+	// Cost is only set if it was requested with the includeCost parameter.
+	Cost *RuleCostEstimate `json:"cost,omitempty"`
 	Rule
 }
 
@@ -156,6 +188,29 @@ type Rule struct {
 	Type           v1.RuleType `json:"type"`
 	LastEvaluation time.Time   `json:"lastEvaluation"`
 	EvaluationTime float64     `json:"evaluationTime"`
+	// LastEvaluationTraceID is the ID of the trace of the last evaluation of the rule, if it was traced.
+	LastEvaluationTraceID string `json:"lastEvaluationTraceId,omitempty"`
+}
+
+// RuleCostEstimate is an estimate of the cost of evaluating a rule, so that the load that rules put on
+// data sources can be charged back to their owners.
+// swagger:model
+type RuleCostEstimate struct {
+	// Number of data source queries run by each evaluation of the rule. Expressions are not counted.
+	// required: true
+	Queries int `json:"queries" yaml:"queries"`
+	// Types of the data sources queried by the rule.
+	DatasourceTypes []string `json:"datasourceTypes,omitempty" yaml:"datasourceTypes,omitempty"`
+	// Evaluation interval of the rule.
+	// required: true
+	IntervalSeconds int64 `json:"intervalSeconds" yaml:"intervalSeconds"`
+	// Number of data source queries run by the rule per hour.
+	// required: true
+	QueriesPerHour float64 `json:"queriesPerHour" yaml:"queriesPerHour"`
+	// Number of alert instances updated by the last evaluation of the rule. Instances resolved because their series
+	// disappeared are not counted. Series that are collapsed into one instance by the fingerprint labels of the rule are counted once.
+	// required: true
+	Instances int `json:"instances" yaml:"instances"`
 }
 
 // Alert has info for an alert.
@@ -303,4 +358,172 @@ type GetGrafanaRuleStatusesParams struct {
 	// in: query
 	// required: false
 	PanelID int64
+
+	// Include an estimate of the cost of evaluating each rule.
+	// in: query
+	// required: false
+	// default: false
+	IncludeCost bool `json:"includeCost"`
+
+	// Label matchers of the alerts to return, in the JSON format of the Prometheus label matchers or as strings
+	// such as team=payments. Rules are returned even if none of their alerts match.
+	// in: query
+	// required: false
+	Matcher []string `json:"matcher"`
+
+	// Label matchers of the rules to return, in the same formats as matcher. They are evaluated against the labels
+	// of the rules, including the labels of their group. Rule groups without matching rules are not returned.
+	// in: query
+	// required: false
+	RuleMatcher []string `json:"rule_matcher"`
+
+	// States of the rules to return: firing, pending or inactive. Alerts are also filtered by state, and can be
+	// filtered by the nodata and error states as well.
+	// in: query
+	// required: false
+	State []string `json:"state"`
+}
+
+// swagger:model
+type BuildInfoResponse struct {
+	// in: body
+	DiscoveryBase
+	// in: body
+	Data BuildInfo `json:"data"`
+}
+
+// BuildInfo is the build information of Grafana, with the fields of the Prometheus build information.
+type BuildInfo struct {
+	Application string `json:"application"`
+	Version     string `json:"version"`
+	Revision    string `json:"revision"`
+	Branch      string `json:"branch"`
+	BuildUser   string `json:"buildUser"`
+	BuildDate   string `json:"buildDate"`
+	GoVersion   string `json:"goVersion"`
+	// The features of the Prometheus-compatible APIs of Grafana, as returned by Mimir.
+	Features map[string]string `json:"features,omitempty"`
+}
+
+// swagger:parameters RouteGetGrafanaMetricMetadata
+type GetGrafanaMetricMetadataParams struct {
+	// Only return the metadata of this metric.
+	// in: query
+	// required: false
+	Metric string `json:"metric"`
+	// Maximum number of metrics to return. All metrics are returned if it is not positive.
+	// in: query
+	// required: false
+	Limit int64 `json:"limit"`
+}
+
+// swagger:model
+type MetricMetadataResponse struct {
+	// in: body
+	DiscoveryBase
+	// The metadata of each metric by the name of the metric.
+	// in: body
+	Data map[string][]MetricMetadata `json:"data"`
+}
+
+// MetricMetadata is the metadata of a metric, as returned by the Prometheus metadata API.
+type MetricMetadata struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+// swagger:parameters RouteGetGrafanaRuleHealth
+type GetGrafanaRuleHealthParams struct {
+	// Maximum number of failing rules to return.
+	// in: query
+	// required: false
+	// default: 10
+	Limit int64 `json:"limit"`
+}
+
+// swagger:model
+type RuleHealthResponse struct {
+	// Health of all rules the user has access to.
+	Totals RuleHealthTotals `json:"totals"`
+	// Health of the rules of each folder.
+	Folders []RuleHealthGroup `json:"folders"`
+	// Health of the rules that query each data source. A rule that queries several data sources is counted for each of them.
+	Datasources []RuleHealthGroup `json:"datasources"`
+	// The unhealthy rules with the most failing alert instances.
+	TopFailing []FailingRule `json:"topFailing"`
+}
+
+// RuleHealthTotals counts rules by health.
+type RuleHealthTotals struct {
+	OK     int64 `json:"ok"`
+	Error  int64 `json:"error"`
+	NoData int64 `json:"nodata"`
+}
+
+// RuleHealthGroup is the health of the rules of a folder or data source.
+type RuleHealthGroup struct {
+	UID    string           `json:"uid"`
+	Title  string           `json:"title,omitempty"`
+	Totals RuleHealthTotals `json:"totals"`
+}
+
+// FailingRule is a rule whose health is error or nodata.
+type FailingRule struct {
+	UID              string    `json:"uid"`
+	Title            string    `json:"title"`
+	FolderUID        string    `json:"folderUid"`
+	RuleGroup        string    `json:"ruleGroup"`
+	Health           string    `json:"health"`
+	LastError        string    `json:"lastError,omitempty"`
+	LastEvaluation   time.Time `json:"lastEvaluation"`
+	FailingInstances int64     `json:"failingInstances"`
+	// LastEvaluationTraceID is the ID of the trace of the last evaluation of the rule, if it was traced.
+	LastEvaluationTraceID string `json:"lastEvaluationTraceId,omitempty"`
+}
+
+// swagger:parameters RouteGetGrafanaRuleScheduling
+type RuleSchedulingParams struct {
+	// The UID of the rule folder
+	// in: path
+	Namespace string
+	// Only return the rules of this group
+	// in: query
+	RuleGroup string `json:"ruleGroup"`
+}
+
+// swagger:model
+type RuleSchedulingResponse struct {
+	Rules []RuleScheduling `json:"rules"`
+}
+
+// RuleScheduling is the view of the scheduler of this Grafana instance on a rule.
+type RuleScheduling struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	IsPaused  bool   `json:"isPaused"`
+	// Scheduled is true if the next evaluation of the rule is planned.
+	Scheduled bool `json:"scheduled"`
+	// Reason explains why the rule is not scheduled or why its next evaluation is skipped.
+	Reason string `json:"reason,omitempty"`
+	// IntervalSeconds is the interval the rule is evaluated at, once the inherited and minimum intervals are applied.
+	IntervalSeconds int64      `json:"intervalSeconds,omitempty"`
+	NextEvaluation  *time.Time `json:"nextEvaluation,omitempty"`
+	// LastEvaluation is empty if the rule was not evaluated since Grafana started.
+	LastEvaluation *RuleSchedulingEvaluation `json:"lastEvaluation,omitempty"`
+}
+
+// RuleSchedulingEvaluation is the outcome of an evaluation of a rule.
+type RuleSchedulingEvaluation struct {
+	ScheduledAt time.Time `json:"scheduledAt"`
+	EvaluatedAt time.Time `json:"evaluatedAt"`
+	// Duration of the evaluation in seconds.
+	EvaluationTime float64 `json:"evaluationTime"`
+	// Attempt is the number of the attempt, evaluations that fail with retryable errors are retried.
+	Attempt int64 `json:"attempt"`
+	// Health is ok if the evaluation succeeded and error otherwise.
+	Health    string `json:"health"`
+	LastError string `json:"lastError,omitempty"`
 }
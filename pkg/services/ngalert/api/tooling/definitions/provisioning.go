@@ -10,7 +10,7 @@ type AlertingFileExport struct {
 	MuteTimings   []MuteTimeIntervalExport   `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport RouteExportMuteTimings RouteExportMuteTiming
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RouteGetPolicyTreeExport RoutePostRulesGroupForExport RouteGetRuleForExport RouteExportMuteTimings RouteExportMuteTiming
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
@@ -18,13 +18,21 @@ type ExportQueryParams struct {
 	// default: false
 	Download bool `json:"download"`
 
-	// Format of the downloaded file, either yaml or json. Accept header can also be used, but the query parameter will take precedence.
+	// Format of the downloaded file, either yaml, json or hcl. The hcl format contains Terraform resources of the Grafana provider. Accept header can also be used, but the query parameter will take precedence.
 	// in: query
 	// required: false
 	// default: yaml
 	Format string `json:"format"`
 }
 
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport RouteGetContactpointsExport RouteGetContactpointExport RouteGetPolicyTreeExport RoutePostRulesGroupForExport RouteGetRulesForExport RouteGetRuleForExport RouteExportMuteTimings RouteExportMuteTiming RouteGetAlertRules RouteGetGrafanaRuleStatuses RouteGetGrafanaAlertStatuses RouteGetGrafanaRulesConfig
+type FieldsQueryParams struct {
+	// Comma separated list of the fields to return, for example groups.name,groups.rules.title. Arrays are traversed and * matches any field. All fields are returned if it is not set. It cannot be used with the hcl format.
+	// in: query
+	// required: false
+	Fields string `json:"fields"`
+}
+
 // swagger:parameters RouteGetContactpointsExport RouteGetContactpointExport
 type DecryptQueryParams struct {
 	// Whether any contained secure settings should be decrypted or left redacted. Redacted settings will contain RedactedValue instead. Currently, only org admin can view decrypted secure settings.
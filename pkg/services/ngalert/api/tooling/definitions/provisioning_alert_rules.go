@@ -88,6 +88,12 @@ type AlertRulesExportParameters struct {
 	// in:query
 	// required: false
 	RuleUID string `json:"ruleUid"`
+
+	// Include an estimate of the cost of evaluating each rule. The estimate is not exported to HCL, and is ignored when the rules are provisioned.
+	// in:query
+	// required: false
+	// default: false
+	IncludeCost bool `json:"includeCost"`
 }
 
 // swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule RouteGetAlertRuleExport
@@ -156,6 +162,19 @@ type ProvisionedAlertRule struct {
 	Provenance Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// Names of the labels that identify the alert instances of the rule. If empty, all labels do.
+	// example: ["namespace", "deployment"]
+	FingerprintLabels []string `json:"fingerprintLabels,omitempty"`
+	// Evaluate expressions when some, but not all, of their inputs failed.
+	// example: false
+	PartialResults bool `json:"partialResults,omitempty"`
+	// Number of evaluations in a row a series must be missing from before its alert instance is resolved.
+	// If neither it nor missingSeriesDurationToResolve is set, the policy of the organization applies.
+	// example: 5
+	MissingSeriesEvalsToResolve int64 `json:"missingSeriesEvalsToResolve,omitempty"`
+	// How long a series must be missing before its alert instance is resolved. It cannot be set with missingSeriesEvalsToResolve.
+	// example: 1h
+	MissingSeriesDurationToResolve model.Duration `json:"missingSeriesDurationToResolve,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -218,23 +237,29 @@ type AlertRuleGroup struct {
 	Title     string                 `json:"title"`
 	FolderUID string                 `json:"folderUid"`
 	Interval  int64                  `json:"interval"`
+	Labels    map[string]string      `json:"labels,omitempty"`
 	Rules     []ProvisionedAlertRule `json:"rules"`
 }
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
 type AlertRuleGroupExport struct {
-	OrgID           int64             `json:"orgId" yaml:"orgId" hcl:"org_id"`
-	Name            string            `json:"name" yaml:"name" hcl:"name"`
-	Folder          string            `json:"folder" yaml:"folder"`
-	FolderUID       string            `json:"-" yaml:"-" hcl:"folder_uid"`
-	Interval        model.Duration    `json:"interval" yaml:"interval"`
-	IntervalSeconds int64             `json:"-" yaml:"-" hcl:"interval_seconds"`
-	Rules           []AlertRuleExport `json:"rules" yaml:"rules" hcl:"rule,block"`
+	OrgID           int64          `json:"orgId" yaml:"orgId" hcl:"org_id"`
+	Name            string         `json:"name" yaml:"name" hcl:"name"`
+	Folder          string         `json:"folder" yaml:"folder"`
+	FolderUID       string         `json:"-" yaml:"-" hcl:"folder_uid"`
+	Interval        model.Duration `json:"interval" yaml:"interval"`
+	IntervalSeconds int64          `json:"-" yaml:"-" hcl:"interval_seconds"`
+	// Labels is not supported by the Terraform provider yet, and is not exported to HCL.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Rules  []AlertRuleExport `json:"rules" yaml:"rules" hcl:"rule,block"`
 }
 
 // AlertRuleExport is the provisioned file export of models.AlertRule.
 type AlertRuleExport struct {
+	// UIDString is used to only export the uid field for HCL if it is set, so that the Terraform resources
+	// created from the export manage the existing rules instead of creating new ones.
 	UID          string              `json:"uid,omitempty" yaml:"uid,omitempty"`
+	UIDString    *string             `json:"-" yaml:"-" hcl:"uid"`
 	Title        string              `json:"title" yaml:"title" hcl:"name"`
 	Condition    string              `json:"condition" yaml:"condition" hcl:"condition"`
 	Data         []AlertQueryExport  `json:"data" yaml:"data" hcl:"data,block"`
@@ -250,6 +275,16 @@ type AlertRuleExport struct {
 	Annotations *map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
 	Labels      *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
 	IsPaused    bool               `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
+	// FingerprintLabels is not supported by the Terraform provider yet, and is not exported to HCL.
+	FingerprintLabels []string `json:"fingerprintLabels,omitempty" yaml:"fingerprintLabels,omitempty"`
+	// PartialResults is not supported by the Terraform provider yet, and is not exported to HCL.
+	PartialResults bool `json:"partialResults,omitempty" yaml:"partialResults,omitempty"`
+	// MissingSeriesEvalsToResolve and MissingSeriesDurationToResolve are not supported by the Terraform provider yet,
+	// and are not exported to HCL.
+	MissingSeriesEvalsToResolve    int64          `json:"missingSeriesEvalsToResolve,omitempty" yaml:"missingSeriesEvalsToResolve,omitempty"`
+	MissingSeriesDurationToResolve model.Duration `json:"missingSeriesDurationToResolve,omitempty" yaml:"missingSeriesDurationToResolve,omitempty"`
+	// Cost is only exported if it was requested, and is not exported to HCL.
+	Cost *RuleCostEstimate `json:"cost,omitempty" yaml:"cost,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -66,6 +68,18 @@ type ContactPointParams struct {
 	Name string `json:"name"`
 }
 
+// swagger:parameters RouteGetContactpointsExport
+type ContactPointExportParams struct {
+	// Filter by the type of the integrations, such as slack or webhook. Can be repeated.
+	// in: query
+	// required: false
+	Type []string `json:"type"`
+	// Base64 encoded PEM of an RSA public key. If set, the secure settings are encrypted with the key instead of being redacted, so that they can be imported into another organization or instance. Each value is encrypted with a random AES-256-GCM key, which is encrypted with RSA-OAEP and SHA-256, and is the base64 encoding of the encrypted key, the nonce and the encrypted value. Requires the same permissions as decrypt, and cannot be used together with it.
+	// in: query
+	// required: false
+	PublicKey string `json:"publicKey"`
+}
+
 // swagger:parameters RoutePostContactpoints RoutePutContactpoint
 type ContactPointPayload struct {
 	// in:body
@@ -107,6 +121,31 @@ type EmbeddedContactPoint struct {
 	DisableResolveMessage bool `json:"disableResolveMessage"`
 	// readonly: true
 	Provenance string `json:"provenance,omitempty"`
+	// Health of the contact point, derived from its recent notifications. It is not set if the contact point did not
+	// send notifications recently.
+	// readonly: true
+	Health *ContactPointHealth `json:"health,omitempty"`
+}
+
+const (
+	ContactPointHealthOK       = "ok"
+	ContactPointHealthDegraded = "degraded"
+	ContactPointHealthFailing  = "failing"
+)
+
+// ContactPointHealth is the health of a contact point. It is failing if its last notification failed, and degraded
+// if other notifications failed in the window.
+type ContactPointHealth struct {
+	// enum: ok, degraded, failing
+	Status string `json:"status"`
+	// Window is the period the notifications are counted for.
+	Window    string `json:"window"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	// LastAttempt is the time of the last notification.
+	LastAttempt time.Time `json:"lastAttempt"`
+	// LastError is the error of the last failed notification in the window.
+	LastError string `json:"lastError,omitempty"`
 }
 
 // ContactPointExport is the provisioned file export of alerting.ContactPointV1.
@@ -0,0 +1,66 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /v1/provisioning/alert-rules/insights provisioning stable RouteGetAlertRulesInsights
+//
+// Get how the alert rules are used: when they were last modified, last fired and last notified, and how many
+// notifications were sent about their alerts.
+//
+//     Responses:
+//       200: AlertRuleUsageInsights
+//       400: ValidationError
+
+// swagger:route GET /v1/provisioning/contact-points/insights provisioning stable RouteGetContactpointsInsights
+//
+// Get how the contact points are used: when they were last modified and last notified, and how many notifications
+// they sent.
+//
+//     Responses:
+//       200: ContactPointUsageInsights
+//       400: ValidationError
+
+// swagger:parameters RouteGetAlertRulesInsights RouteGetContactpointsInsights
+type UsageInsightsParams struct {
+	// Only return the objects that were neither used nor modified for at least the duration, such as 30d.
+	// in: query
+	// required: false
+	UnusedFor string `json:"unusedFor"`
+}
+
+// swagger:model
+type AlertRuleUsageInsights []AlertRuleUsageInsight
+
+// AlertRuleUsageInsight is how an alert rule is used. The usage is tracked since Grafana started tracking it, and the
+// least recently used rules come first.
+type AlertRuleUsageInsight struct {
+	UID          string    `json:"uid"`
+	Title        string    `json:"title"`
+	FolderUID    string    `json:"folderUid"`
+	RuleGroup    string    `json:"ruleGroup"`
+	LastModified time.Time `json:"lastModified"`
+	// LastFired is the last time the rule was evaluated with firing alerts. It is not set if the rule never fired.
+	LastFired *time.Time `json:"lastFired,omitempty"`
+	// LastNotified is the last time a notification was sent about the alerts of the rule.
+	LastNotified *time.Time `json:"lastNotified,omitempty"`
+	// Notifications is the number of notifications that were sent about the alerts of the rule.
+	Notifications int64 `json:"notifications"`
+}
+
+// swagger:model
+type ContactPointUsageInsights []ContactPointUsageInsight
+
+// ContactPointUsageInsight is how a contact point is used. The usage is tracked since Grafana started tracking it, and
+// the least recently used contact points come first.
+type ContactPointUsageInsight struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// LastModified is the last time the contact point was created or changed. It is not set if it was not changed
+	// since Grafana started tracking it.
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	// LastNotified is the last time the contact point sent a notification.
+	LastNotified *time.Time `json:"lastNotified,omitempty"`
+	// Notifications is the number of notifications the contact point sent successfully.
+	Notifications int64 `json:"notifications"`
+}
@@ -39,8 +39,67 @@ import (
 //
 //     Responses:
 //       200: AlertingFileExport
+//       403: PermissionDenied
 //       404: NotFound
 
+// swagger:route GET /v1/provisioning/policies/named provisioning stable RouteGetNamedPolicies
+//
+// Get all the named notification policies, which routes of the notification policy tree reference by name.
+//
+//     Responses:
+//       200: NamedRoutes
+
+// swagger:route GET /v1/provisioning/policies/named/{name} provisioning stable RouteGetNamedPolicy
+//
+// Get a named notification policy.
+//
+//     Responses:
+//       200: NamedRoute
+//       404: description: Not found.
+
+// swagger:route PUT /v1/provisioning/policies/named/{name} provisioning stable RoutePutNamedPolicy
+//
+// Create or replace a named notification policy.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+
+// swagger:route DELETE /v1/provisioning/policies/named/{name} provisioning stable RouteDeleteNamedPolicy
+//
+// Delete a named notification policy.
+//
+//     Responses:
+//       204: description: The named notification policy was deleted successfully.
+//       409: GenericPublicError
+
+// swagger:model
+type NamedRoutes []NamedRoute
+
+// swagger:parameters RouteGetNamedPolicy RoutePutNamedPolicy RouteDeleteNamedPolicy
+type NamedPolicyParams struct {
+	// Name of the named notification policy.
+	// in:path
+	// required: true
+	Name string `json:"name"`
+}
+
+// swagger:parameters RoutePutNamedPolicy
+type NamedPolicyPayload struct {
+	// The subtree of the notification policy tree.
+	// in:body
+	Body Route
+}
+
+// swagger:parameters RoutePutNamedPolicy
+type NamedPolicyHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
 // swagger:parameters RoutePutPolicyTree
 type Policytree struct {
 	// The new notification routing tree to use
@@ -80,6 +139,8 @@ type RouteExport struct {
 	GroupWait      *string `yaml:"group_wait,omitempty" json:"group_wait,omitempty" hcl:"group_wait,optional"`
 	GroupInterval  *string `yaml:"group_interval,omitempty" json:"group_interval,omitempty" hcl:"group_interval,optional"`
 	RepeatInterval *string `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty" hcl:"repeat_interval,optional"`
+
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 }
 
 type MatcherExport struct {
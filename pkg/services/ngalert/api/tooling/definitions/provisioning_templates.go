@@ -1,5 +1,7 @@
 package definitions
 
+import "time"
+
 // swagger:route GET /v1/provisioning/templates provisioning stable RouteGetTemplates
 //
 // Get all notification templates.
@@ -33,24 +35,99 @@ package definitions
 //
 //     Responses:
 //       204: description: The template was deleted successfully.
+//       409: GenericPublicError
+
+// swagger:route GET /v1/provisioning/templates/{name}/versions provisioning stable RouteGetTemplateVersions
+//
+// Get the versions of a notification template, latest first.
+//
+//     Responses:
+//       200: NotificationTemplateVersions
+//       404: description: Not found.
+
+// swagger:route GET /v1/provisioning/templates/{name}/versions/{version}/diff provisioning stable RouteGetTemplateVersionDiff
+//
+// Get the differences between a version of a notification template and the current template or another version.
+//
+//     Responses:
+//       200: NotificationTemplateVersionDiff
+//       400: ValidationError
+//       404: description: Not found.
 
-// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate
+// swagger:route POST /v1/provisioning/templates/{name}/versions/{version}/rollback provisioning stable RoutePostTemplateVersionRollback
+//
+// Restore a version of a notification template. The restored content is saved as a new version.
+//
+//     Responses:
+//       202: NotificationTemplate
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate RouteGetTemplateVersions RouteGetTemplateVersionDiff RoutePostTemplateVersionRollback
 type RouteGetTemplateParam struct {
 	// Template Name
 	// in:path
 	Name string `json:"name"`
 }
 
+// swagger:parameters RouteGetTemplates
+type RouteGetTemplatesParams struct {
+	// Only return the templates used by the contact point with this name.
+	// in:query
+	// required: false
+	Receiver string `json:"receiver"`
+}
+
 // swagger:model
 type NotificationTemplate struct {
 	Name       string     `json:"name"`
 	Template   string     `json:"template"`
 	Provenance Provenance `json:"provenance,omitempty"`
+	// Names of the contact points that use the template, directly or through other templates.
+	// readonly: true
+	Receivers []string `json:"receivers,omitempty"`
 }
 
 // swagger:model
 type NotificationTemplates []NotificationTemplate
 
+// swagger:parameters RouteGetTemplateVersionDiff RoutePostTemplateVersionRollback
+type RouteTemplateVersionParam struct {
+	// Version of the template
+	// in:path
+	Version int64 `json:"version"`
+}
+
+// swagger:parameters RouteGetTemplateVersionDiff
+type RouteGetTemplateVersionDiffParams struct {
+	// Version to compare the version with. The version is compared with the current template if it is not set.
+	// in:query
+	// required: false
+	CompareTo int64 `json:"compareTo"`
+}
+
+// swagger:model
+type NotificationTemplateVersion struct {
+	Version  int64  `json:"version"`
+	Template string `json:"template"`
+	// Login of the user that saved the version. It is empty if the version is the content the template had before it was first versioned.
+	CreatedBy string    `json:"createdBy,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// swagger:model
+type NotificationTemplateVersions []NotificationTemplateVersion
+
+// swagger:model
+type NotificationTemplateVersionDiff struct {
+	// Version of the template that is compared.
+	Version int64 `json:"version"`
+	// Version it is compared with, or 0 if it is compared with the current template.
+	CompareTo int64 `json:"compareTo,omitempty"`
+	// Unified diff from the version to the compared content. It is empty if they are the same.
+	Diff string `json:"diff"`
+}
+
 type NotificationTemplateContent struct {
 	Template string `json:"template"`
 }
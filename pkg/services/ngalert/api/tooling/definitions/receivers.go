@@ -17,6 +17,15 @@ package definitions
 //      200: GetReceiversResponse
 //      403: PermissionDenied
 
+// swagger:route GET /v1/notifications/receivers/{Name}/usage notifications RouteGetReceiverUsage
+//
+// Get the notification policies and alert rules that send notifications to a receiver, and its recent deliveries.
+//
+//    Responses:
+//      200: GetReceiverUsageResponse
+//      403: PermissionDenied
+//      404: NotFound
+
 // swagger:parameters RouteGetReceiver
 type GetReceiverParams struct {
 	// in:path
@@ -54,3 +63,52 @@ type GetReceiversResponse struct {
 	// in:body
 	Body []GettableApiReceiver
 }
+
+// swagger:parameters RouteGetReceiverUsage
+type GetReceiverUsageParams struct {
+	// in:path
+	// required: true
+	Name string `json:"name"`
+}
+
+// swagger:response GetReceiverUsageResponse
+type GetReceiverUsageResponse struct {
+	// in:body
+	Body ReceiverUsage
+}
+
+// swagger:model
+type ReceiverUsage struct {
+	Name string `json:"name"`
+	// Routes are the notification policies that explicitly reference the receiver.
+	Routes []ReceiverUsageRoute `json:"routes"`
+	// Rules are the alert rules the user has access to whose labels are routed to the receiver by the notification policies.
+	Rules []ReceiverUsageRule `json:"rules"`
+	// Deliveries is omitted if the Alertmanager of the organization does not count the notifications it sends.
+	Deliveries *ReceiverDeliveries `json:"deliveries,omitempty"`
+}
+
+// swagger:model
+type ReceiverUsageRoute struct {
+	// Path is the list of indices of the nested policies that lead to the policy. It is empty for the root policy.
+	Path []int `json:"path"`
+	// Matchers are the matchers of the policy, without the matchers of its parents.
+	Matchers []string `json:"matchers"`
+}
+
+// swagger:model
+type ReceiverUsageRule struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+}
+
+// swagger:model
+type ReceiverDeliveries struct {
+	// Window is the period the notification attempts are counted for.
+	Window    string `json:"window"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	LastError string `json:"lastError,omitempty"`
+}
@@ -64,10 +64,116 @@ import (
 //
 //     Produces:
 //     - application/json
+//     - text/csv
 //
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/backtest/jobs testing RouteCreateBacktestJob
+//
+// Submit a backtesting job that tests a rule in the background
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: BacktestJob
+//       400: ValidationError
+//       404: NotFound
+//       429: GenericPublicError
+
+// swagger:route Get /v1/rule/backtest/jobs/{JobID} testing RouteGetBacktestJob
+//
+// Get the status and the progress of a backtesting job
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestJob
+//       404: NotFound
+
+// swagger:route Delete /v1/rule/backtest/jobs/{JobID} testing RouteDeleteBacktestJob
+//
+// Cancel a running backtesting job, or delete the result of a finished one
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: Ack
+//       404: NotFound
+
+// swagger:route Get /v1/rule/backtest/jobs/{JobID}/result testing RouteGetBacktestJobResult
+//
+// Get the result of a backtesting job
+//
+//     Produces:
+//     - application/json
+//     - text/csv
+//
+//     Responses:
+//       200: BacktestResult
+//       400: ValidationError
+//       404: NotFound
+//       409: GenericPublicError
+
+// swagger:route Post /v1/rule/loadtest testing LoadTestConfig
+//
+// Estimate the cost of a rule by evaluating it against synthetic series
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: LoadTestResult
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route Post /v1/rule/preview/panel testing PanelAlertPreviewConfig
+//
+// Preview the alerts that a rule created from the queries of a panel would produce
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: PanelAlertPreviewResult
+//       400: ValidationError
+
+// swagger:route Post /v1/rule/preview/range testing RangePreviewConfig
+//
+// Preview the results of every evaluation of a condition over a time range, without creating a rule
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RangePreviewResult
+//       400: ValidationError
+
+// swagger:route Get /v1/rule/template/functions testing RouteGetTemplateFunctions
+//
+// Get the functions that can be used in the templates of annotations and labels of rules
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: TemplateFunctionsResponse
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -213,8 +319,22 @@ type ResponseDetails struct {
 type BacktestConfigRequest struct {
 	// in:body
 	Body BacktestConfig
+	// Format of the results. The frame format is a data frame with a field for every alert instance, the csv format
+	// has a row for every evaluation of every alert instance, and the prometheus format has the ALERTS series of the
+	// pending and firing alerts in the format of a Prometheus range query.
+	// in:query
+	// required:false
+	// enum: frame,csv,prometheus
+	// default: frame
+	Format string `json:"format"`
 }
 
+const (
+	BacktestFormatFrame      = "frame"
+	BacktestFormatCSV        = "csv"
+	BacktestFormatPrometheus = "prometheus"
+)
+
 // swagger:model
 type BacktestConfig struct {
 	From     time.Time      `json:"from"`
@@ -234,3 +354,201 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// BacktestMatrixResult is the result of a backtesting in the format of a Prometheus range query.
+// swagger:model
+type BacktestMatrixResult struct {
+	Status string                   `json:"status"`
+	Data   BacktestMatrixResultData `json:"data"`
+}
+
+type BacktestMatrixResultData struct {
+	// enum: matrix
+	ResultType string       `json:"resultType"`
+	Result     model.Matrix `json:"result"`
+}
+
+// swagger:parameters RouteCreateBacktestJob
+type CreateBacktestJobRequest struct {
+	// in:body
+	Body BacktestConfig
+}
+
+// swagger:parameters RouteGetBacktestJob RouteGetBacktestJobResult RouteDeleteBacktestJob
+type BacktestJobParams struct {
+	// in:path
+	JobID string
+}
+
+// swagger:parameters RouteGetBacktestJobResult
+type BacktestJobResultParams struct {
+	// Format of the results, like the format of the results of a backtesting.
+	// in:query
+	// required:false
+	// enum: frame,csv,prometheus
+	// default: frame
+	Format string `json:"format"`
+}
+
+// BacktestJob is a backtesting that runs in the background. Jobs are kept in memory by the Grafana instance that
+// runs them for an hour after they finished, and are only returned to the user who submitted them. Jobs that run
+// longer than 30 minutes fail.
+// swagger:model
+type BacktestJob struct {
+	ID string `json:"id"`
+	// enum: running,succeeded,failed
+	Status string `json:"status"`
+	// Number of evaluations that are done, out of the total number of evaluations.
+	Evaluations int `json:"evaluations"`
+	Total       int `json:"total"`
+	// Error of a failed job.
+	Error    string     `json:"error,omitempty"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// swagger:parameters LoadTestConfig
+type LoadTestConfigRequest struct {
+	// in:body
+	Body LoadTestConfig
+}
+
+// swagger:model
+type LoadTestConfig struct {
+	Condition string       `json:"condition"`
+	Data      []AlertQuery `json:"data"`
+	// Interval between evaluations. Defaults to the base interval of the scheduler.
+	Interval model.Duration `json:"interval,omitempty"`
+
+	// Number of synthetic series returned by each query of the rule.
+	Series int `json:"series"`
+	// Number of evaluations of the rule. Defaults to 10.
+	Evaluations int `json:"evaluations,omitempty"`
+}
+
+// swagger:model
+type LoadTestResult struct {
+	Series      int `json:"series"`
+	Evaluations int `json:"evaluations"`
+	// Number of alert instances produced by the last evaluation.
+	Instances int `json:"instances"`
+
+	Latency LoadTestLatency `json:"latency"`
+	Memory  LoadTestMemory  `json:"memory"`
+}
+
+// LoadTestLatency is the latency of the evaluations in seconds.
+type LoadTestLatency struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+	P95  float64 `json:"p95"`
+}
+
+// LoadTestMemory is the memory allocated by the evaluations. It is measured for the whole process.
+type LoadTestMemory struct {
+	AllocatedBytes uint64 `json:"allocatedBytes"`
+	Allocations    uint64 `json:"allocations"`
+	// Allocated bytes per evaluation and series.
+	BytesPerSeries float64 `json:"bytesPerSeries"`
+}
+
+// swagger:parameters PanelAlertPreviewConfig
+type PanelAlertPreviewConfigRequest struct {
+	// in:body
+	Body PanelAlertPreviewConfig
+}
+
+// swagger:model
+type PanelAlertPreviewConfig struct {
+	// Condition is the RefID of the query or expression that decides whether the rule fires. Defaults to the last query.
+	Condition string `json:"condition"`
+	// Data is the list of queries of the panel plus the expressions of the condition.
+	// required: true
+	Data []AlertQuery   `json:"data"`
+	For  model.Duration `json:"for,omitempty"`
+
+	// Title of the rule. It is the alertname label of the instances.
+	Title       string            `json:"title,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	DashboardUID string `json:"dashboardUid,omitempty"`
+	PanelID      int64  `json:"panelId,omitempty"`
+
+	// From and To are the time range of the panel. Defaults to the last hour.
+	From time.Time `json:"from,omitempty"`
+	To   time.Time `json:"to,omitempty"`
+	// Number of evaluations of the condition in the time range. Defaults to 30.
+	Points int `json:"points,omitempty"`
+}
+
+// swagger:model
+type PanelAlertPreviewResult struct {
+	// Instances are the alerts produced by the evaluation at the end of the time range.
+	Instances []amv2.PostableAlert `json:"instances"`
+	// Frame has a time field and one boolean field per alert instance that is true when the condition is breached.
+	Frame *data.Frame `json:"frame"`
+}
+
+// swagger:parameters RangePreviewConfig
+type RangePreviewConfigRequest struct {
+	// in:body
+	Body RangePreviewConfig
+}
+
+// swagger:model
+type RangePreviewConfig struct {
+	// Condition is the RefID of the query or expression that decides whether the rule fires. Defaults to the last query.
+	Condition string `json:"condition"`
+	// required: true
+	Data []AlertQuery `json:"data"`
+
+	// From and To are the time range of the preview. The condition is evaluated at From and then every interval until To.
+	// required: true
+	From time.Time `json:"from"`
+	// required: true
+	To time.Time `json:"to"`
+	// Interval between evaluations. It must be a multiple of the base interval of the scheduler.
+	// Defaults to the default evaluation interval of rules.
+	Interval model.Duration `json:"interval,omitempty"`
+}
+
+// swagger:model
+type RangePreviewResult struct {
+	// Evaluations are the results of the evaluations of the condition, ordered by time.
+	Evaluations []RangePreviewEvaluation `json:"evaluations"`
+}
+
+// RangePreviewEvaluation is the result of one evaluation of the condition.
+type RangePreviewEvaluation struct {
+	Time time.Time `json:"time"`
+	// Results contains one result for every series of the evaluation.
+	Results []RangePreviewSeriesResult `json:"results"`
+}
+
+// RangePreviewSeriesResult is the result of a series in one evaluation of the condition.
+type RangePreviewSeriesResult struct {
+	Labels map[string]string `json:"labels"`
+	// example: Alerting
+	State string `json:"state"`
+	// Values are the values of the expressions of the condition, by their RefID.
+	Values map[string]*float64 `json:"values,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// swagger:response TemplateFunctionsResponse
+type TemplateFunctionsResponse struct {
+	// in:body
+	Body []TemplateFunction
+}
+
+// swagger:model
+type TemplateFunction struct {
+	// example: humanizeDuration
+	Name string `json:"name"`
+	// example: humanizeDuration(seconds number|string) string
+	Signature string `json:"signature"`
+	// example: Formats a number of seconds as a duration, such as 1h 2m 3s.
+	Description string `json:"description"`
+}
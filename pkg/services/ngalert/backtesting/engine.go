@@ -42,11 +42,15 @@ type stateManager interface {
 type Engine struct {
 	evalFactory        eval.EvaluatorFactory
 	createStateManager func() stateManager
+	tracer             tracing.Tracer
+	jobs               *jobs
 }
 
 func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer) *Engine {
 	return &Engine{
 		evalFactory: evalFactory,
+		tracer:      tracer,
+		jobs:        newJobs(clock.New()),
 		createStateManager: func() stateManager {
 			cfg := state.ManagerCfg{
 				Metrics:       nil,
@@ -64,8 +68,25 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 }
 
 func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	run, err := e.prepare(ctx, user, rule, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return run.run(ctx, nil)
+}
+
+// testRun is a backtesting of a rule whose input is validated, and that is ready to run.
+type testRun struct {
+	rule         *models.AlertRule
+	from, to     time.Time
+	length       int
+	stateManager stateManager
+	evaluator    backtestingEvaluator
+}
+
+// prepare validates the input of a backtesting and creates its evaluator.
+func (e *Engine) prepare(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*testRun, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
-	logger := logger.FromContext(ctx)
 
 	if !from.Before(to) {
 		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
@@ -80,10 +101,26 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition(), &schedule.AlertingResultsFromRuleState{
 		Manager: stateManager,
 		Rule:    rule,
-	})
+	}, e.tracer)
 	if err != nil {
 		return nil, errors.Join(ErrInvalidInputData, err)
 	}
+	return &testRun{
+		rule:         rule,
+		from:         from,
+		to:           to,
+		length:       length,
+		stateManager: stateManager,
+		evaluator:    evaluator,
+	}, nil
+}
+
+// run evaluates the rule at every interval of the backtesting. If progress is not nil, it is called with the index of
+// every evaluation.
+func (r *testRun) run(ctx context.Context, progress func(evaluationIndex int)) (*data.Frame, error) {
+	rule, from, to, length := r.rule, r.from, r.to, r.length
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	logger := logger.FromContext(ctx)
 
 	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", length)
 
@@ -92,12 +129,16 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	tsField := data.NewField("Time", nil, make([]time.Time, length))
 	valueFields := make(map[string]*data.Field)
 
-	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
+	err := r.evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
+		// stop the evaluations of canceled backtestings, even if the evaluator does not use the context
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if idx >= length {
 			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
 			return nil
 		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
+		states := r.stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
 			field, ok := valueFields[s.CacheID]
@@ -114,6 +155,9 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 				continue
 			}
 		}
+		if progress != nil {
+			progress(idx)
+		}
 		return nil
 	})
 	fields := make([]*data.Field, 0, len(valueFields)+1)
@@ -130,30 +174,20 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	return result, nil
 }
 
-func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
+func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader, tracer tracing.Tracer) (backtestingEvaluator, error) {
+	frames := make(map[string]*data.Frame)
 	for _, q := range condition.Data {
-		if q.DatasourceUID == "__data__" || q.QueryType == "__data__" {
-			if len(condition.Data) != 1 {
-				return nil, errors.New("data queries are not supported with other expressions or data queries")
-			}
-			if condition.Condition == "" {
-				return nil, fmt.Errorf("condition must not be empty and be set to the data query %s", q.RefID)
-			}
-			if condition.Condition != q.RefID {
-				return nil, fmt.Errorf("condition must be set to the data query %s", q.RefID)
-			}
-			model := struct {
-				DataFrame *data.Frame `json:"data"`
-			}{}
-			err := json.Unmarshal(q.Model, &model)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse data frame: %w", err)
-			}
-			if model.DataFrame == nil {
-				return nil, errors.New("the data field must not be empty")
-			}
-			return newDataEvaluator(condition.Condition, model.DataFrame)
+		if !isDataQuery(q) {
+			continue
+		}
+		frame, err := parseDataFrame(q)
+		if err != nil {
+			return nil, err
 		}
+		frames[q.RefID] = frame
+	}
+	if len(frames) > 0 {
+		return newDataQueriesEvaluator(condition, frames, tracer)
 	}
 
 	evaluator, err := evalFactory.Create(eval.NewContextWithPreviousResults(ctx, user, reader), condition)
@@ -167,6 +201,47 @@ func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFact
 	}, nil
 }
 
+// newDataQueriesEvaluator creates an evaluator for a condition whose queries return the given frames instead of
+// querying the data sources. The frames are indexed by the RefID of the query.
+func newDataQueriesEvaluator(condition models.Condition, frames map[string]*data.Frame, tracer tracing.Tracer) (backtestingEvaluator, error) {
+	// classic conditions cannot use data queries as input, they are evaluated against the data directly
+	for _, c := range condition.Data {
+		if isClassicCondition(c) {
+			return newClassicConditionEvaluator(condition, frames, tracer)
+		}
+	}
+	if len(condition.Data) != 1 {
+		return nil, errors.New("data queries are not supported with other expressions or data queries")
+	}
+	q := condition.Data[0]
+	if condition.Condition == "" {
+		return nil, fmt.Errorf("condition must not be empty and be set to the data query %s", q.RefID)
+	}
+	if condition.Condition != q.RefID {
+		return nil, fmt.Errorf("condition must be set to the data query %s", q.RefID)
+	}
+	return newDataEvaluator(condition.Condition, frames[q.RefID])
+}
+
+func isDataQuery(q models.AlertQuery) bool {
+	return q.DatasourceUID == "__data__" || q.QueryType == "__data__"
+}
+
+// parseDataFrame returns the data frame of a data query.
+func parseDataFrame(q models.AlertQuery) (*data.Frame, error) {
+	model := struct {
+		DataFrame *data.Frame `json:"data"`
+	}{}
+	err := json.Unmarshal(q.Model, &model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse data frame: %w", err)
+	}
+	if model.DataFrame == nil {
+		return nil, errors.New("the data field must not be empty")
+	}
+	return model.DataFrame, nil
+}
+
 // NoopImageService is a no-op image service.
 type NoopImageService struct{}
 
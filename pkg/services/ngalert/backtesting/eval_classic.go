@@ -0,0 +1,155 @@
+package backtesting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const classicConditionsType = "classic_conditions"
+
+// classicConditionEvaluator evaluates a classic condition against the data provided by data queries.
+// At every evaluation, each input of the classic condition gets the points of the data that are within the time range
+// of its data query, as if the data was returned by a data source for that time range.
+type classicConditionEvaluator struct {
+	refID  string
+	cmd    *classic.ConditionsCmd
+	inputs map[string]classicConditionInput
+	tracer tracing.Tracer
+}
+
+type classicConditionInput struct {
+	series    []mathexp.Series
+	timeRange models.RelativeTimeRange
+}
+
+// isClassicCondition returns true if the query is a classic condition expression.
+func isClassicCondition(q models.AlertQuery) bool {
+	if isExpr, err := q.IsExpression(); err != nil || !isExpr {
+		return false
+	}
+	model := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return false
+	}
+	return model.Type == classicConditionsType
+}
+
+// newClassicConditionEvaluator creates an evaluator for a classic condition whose inputs are the queries that have a frame.
+func newClassicConditionEvaluator(condition models.Condition, frames map[string]*data.Frame, tracer tracing.Tracer) (*classicConditionEvaluator, error) {
+	var cmd *classic.ConditionsCmd
+	dataQueries := make(map[string]models.AlertQuery, len(condition.Data))
+	for _, q := range condition.Data {
+		if _, ok := frames[q.RefID]; ok {
+			dataQueries[q.RefID] = q
+			continue
+		}
+		if q.RefID != condition.Condition || !isClassicCondition(q) {
+			return nil, errors.New("data queries are only supported with a single classic condition that is set as the condition of the rule")
+		}
+		model := make(map[string]any)
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return nil, fmt.Errorf("failed to parse classic condition: %w", err)
+		}
+		c, err := classic.UnmarshalConditionsCmd(model, q.RefID)
+		if err != nil {
+			return nil, err
+		}
+		cmd = c
+	}
+	if cmd == nil {
+		return nil, fmt.Errorf("condition must be set to the classic condition %s", condition.Condition)
+	}
+
+	inputs := make(map[string]classicConditionInput, len(dataQueries))
+	for _, refID := range cmd.NeedsVars() {
+		if _, ok := inputs[refID]; ok {
+			continue
+		}
+		q, ok := dataQueries[refID]
+		if !ok {
+			return nil, fmt.Errorf("classic condition %s uses query %s that is not a data query", cmd.RefID, refID)
+		}
+		series, err := seriesFromFrame(frames[refID])
+		if err != nil {
+			return nil, err
+		}
+		inputs[refID] = classicConditionInput{series: series, timeRange: q.RelativeTimeRange}
+	}
+
+	return &classicConditionEvaluator{
+		refID:  cmd.RefID,
+		cmd:    cmd,
+		inputs: inputs,
+		tracer: tracer,
+	}, nil
+}
+
+func (d *classicConditionEvaluator) Eval(ctx context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error {
+	for idx, now := 0, from; idx < evaluations; idx, now = idx+1, now.Add(interval) {
+		vars := make(mathexp.Vars, len(d.inputs))
+		for refID, input := range d.inputs {
+			vars[refID] = input.window(refID, now)
+		}
+		res, err := d.cmd.Execute(ctx, now, vars, d.tracer)
+		if err != nil {
+			return err
+		}
+
+		result := eval.Result{
+			State:       eval.NoData,
+			EvaluatedAt: now,
+		}
+		if len(res.Values) == 1 {
+			if n, ok := res.Values[0].(mathexp.Number); ok {
+				if value := n.GetFloat64Value(); value != nil {
+					result.State = eval.Normal
+					if *value != 0 {
+						result.State = eval.Alerting
+					}
+					result.Values = map[string]eval.NumberValueCapture{
+						d.refID: {
+							Var:   d.refID,
+							Value: value,
+						},
+					}
+				}
+			}
+		}
+		if err := callback(idx, now, eval.Results{result}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// window returns the points of the series that are in the time range of the query evaluated at the given time.
+func (i classicConditionInput) window(refID string, now time.Time) mathexp.Results {
+	start := now.Add(-time.Duration(i.timeRange.From))
+	end := now.Add(-time.Duration(i.timeRange.To))
+	result := mathexp.Results{}
+	for _, s := range i.series {
+		w := mathexp.NewSeries(refID, s.GetLabels(), 0)
+		for idx := 0; idx < s.Len(); idx++ {
+			t, v := s.GetPoint(idx)
+			if t.Before(start) || t.After(end) {
+				continue
+			}
+			w.AppendPoint(t, v)
+		}
+		result.Values = append(result.Values, w)
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package backtesting
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestClassicConditionEvaluator(t *testing.T) {
+	from := time.Unix(0, 0)
+	times := make([]time.Time, 10)
+	for i := range times {
+		times[i] = from.Add(time.Duration(i) * time.Second)
+	}
+	frame := data.NewFrame("",
+		data.NewField("time", nil, times),
+		data.NewField("value", data.Labels{"host": "a"}, []float64{0, 0, 0, 5, 5, 5, 0, 0, 0, 0}),
+	)
+	frameJSON, err := json.Marshal(struct {
+		Data *data.Frame `json:"data"`
+	}{Data: frame})
+	require.NoError(t, err)
+
+	dataQuery := models.AlertQuery{
+		RefID:             "A",
+		DatasourceUID:     "__data__",
+		RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(time.Second)},
+		Model:             frameJSON,
+	}
+	classicCondition := func(input string) models.AlertQuery {
+		return models.AlertQuery{
+			RefID:         "B",
+			DatasourceUID: expr.DatasourceUID,
+			Model: json.RawMessage(`{"type":"classic_conditions","conditions":[{
+				"evaluator":{"params":[3],"type":"gt"},
+				"operator":{"type":"and"},
+				"query":{"params":["` + input + `"]},
+				"reducer":{"type":"max"}
+			}]}`),
+		}
+	}
+
+	t.Run("evaluates the classic condition against the data in the time range of the query", func(t *testing.T) {
+		condition := models.Condition{Condition: "B", Data: []models.AlertQuery{dataQuery, classicCondition("A")}}
+		e, err := newBacktestingEvaluator(context.Background(), nil, nil, condition, nil, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.IsType(t, &classicConditionEvaluator{}, e)
+
+		var states []eval.State
+		err = e.Eval(context.Background(), from.Add(time.Second), time.Second, 9, func(_ int, _ time.Time, results eval.Results) error {
+			require.Len(t, results, 1)
+			states = append(states, results[0].State)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []eval.State{
+			eval.Normal, eval.Normal, eval.Alerting, eval.Alerting, eval.Alerting, eval.Alerting, eval.Normal, eval.Normal, eval.Normal,
+		}, states)
+	})
+
+	t.Run("fails if the classic condition is not the condition of the rule", func(t *testing.T) {
+		condition := models.Condition{Condition: "A", Data: []models.AlertQuery{dataQuery, classicCondition("A")}}
+		_, err := newBacktestingEvaluator(context.Background(), nil, nil, condition, nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("fails if the classic condition uses a query that is not a data query", func(t *testing.T) {
+		condition := models.Condition{Condition: "B", Data: []models.AlertQuery{dataQuery, classicCondition("C")}}
+		_, err := newBacktestingEvaluator(context.Background(), nil, nil, condition, nil, nil)
+		require.Error(t, err)
+	})
+}
@@ -21,13 +21,10 @@ type dataEvaluator struct {
 }
 
 func newDataEvaluator(refID string, frame *data.Frame) (*dataEvaluator, error) {
-	series, err := expr.WideToMany(frame, nil)
+	series, err := seriesFromFrame(frame)
 	if err != nil {
 		return nil, err
 	}
-	for _, s := range series {
-		s.SortByTime(false)
-	}
 
 	return &dataEvaluator{
 		refID:              refID,
@@ -92,3 +89,15 @@ func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Du
 	}
 	return nil
 }
+
+// seriesFromFrame returns the series of a wide data frame, sorted by time.
+func seriesFromFrame(frame *data.Frame) ([]mathexp.Series, error) {
+	series, err := expr.WideToMany(frame, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range series {
+		s.SortByTime(false)
+	}
+	return series, nil
+}
@@ -0,0 +1,147 @@
+package backtesting
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+)
+
+const (
+	// alertsMetricName is the name of the series that describe the pending and firing alerts,
+	// like the ALERTS series of the Prometheus ruler.
+	alertsMetricName = "ALERTS"
+	alertStateLabel  = "alertstate"
+)
+
+// resultSeries is the state of an alert instance at every evaluation of the backtesting.
+type resultSeries struct {
+	labels data.Labels
+	values []*string
+}
+
+// resultSeriesOf returns the times of the evaluations and the series of the frame returned by Test, ordered by their
+// labels.
+func resultSeriesOf(frame *data.Frame) ([]time.Time, []resultSeries) {
+	var times []time.Time
+	var series []resultSeries
+	for _, field := range frame.Fields {
+		switch field.Type() {
+		case data.FieldTypeTime:
+			times = make([]time.Time, field.Len())
+			for i := range times {
+				times[i] = field.At(i).(time.Time)
+			}
+		case data.FieldTypeNullableString:
+			values := make([]*string, field.Len())
+			for i := range values {
+				values[i] = field.At(i).(*string)
+			}
+			series = append(series, resultSeries{labels: field.Labels, values: values})
+		}
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].labels.String() < series[j].labels.String()
+	})
+	return times, series
+}
+
+// splitState splits a value of the frame returned by Test into the state and the reason of the state.
+func splitState(value string) (string, string) {
+	state, reason, found := strings.Cut(value, " (")
+	if !found {
+		return value, ""
+	}
+	return state, strings.TrimSuffix(reason, ")")
+}
+
+// WriteCSV writes the results of a backtesting in the CSV format, with a row for every evaluation of every alert
+// instance. The columns are the time of the evaluation, a column for each label of the alert instances, the state
+// and the reason of the state. Evaluations without data are omitted.
+func WriteCSV(w io.Writer, frame *data.Frame) error {
+	times, series := resultSeriesOf(frame)
+	keySet := make(map[string]struct{})
+	for _, s := range series {
+		for k := range s.labels {
+			keySet[k] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cw := csv.NewWriter(w)
+	header := make([]string, 0, len(keys)+3)
+	header = append(header, "time")
+	header = append(header, keys...)
+	header = append(header, "state", "reason")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, len(header))
+	for idx, t := range times {
+		for _, s := range series {
+			if s.values[idx] == nil {
+				continue
+			}
+			row[0] = t.UTC().Format(time.RFC3339)
+			for i, k := range keys {
+				row[i+1] = s.labels[k]
+			}
+			row[len(keys)+1], row[len(keys)+2] = splitState(*s.values[idx])
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ToMatrix converts the results of a backtesting to the ALERTS series that the Prometheus ruler would have written,
+// in the format of a Prometheus range query. The series have the labels of the alert instances and the alertstate
+// label, pending or firing, and a sample of value 1 at every evaluation the alert instance was in that state.
+func ToMatrix(frame *data.Frame) model.Matrix {
+	times, series := resultSeriesOf(frame)
+	result := model.Matrix{}
+	for _, s := range series {
+		streams := make(map[string]*model.SampleStream, 2)
+		for idx, t := range times {
+			if s.values[idx] == nil {
+				continue
+			}
+			var alertState string
+			switch state, _ := splitState(*s.values[idx]); state {
+			case eval.Pending.String():
+				alertState = "pending"
+			case eval.Alerting.String():
+				alertState = "firing"
+			default:
+				continue
+			}
+			stream, ok := streams[alertState]
+			if !ok {
+				metric := make(model.Metric, len(s.labels)+2)
+				for k, v := range s.labels {
+					metric[model.LabelName(k)] = model.LabelValue(v)
+				}
+				metric[model.MetricNameLabel] = alertsMetricName
+				metric[alertStateLabel] = model.LabelValue(alertState)
+				stream = &model.SampleStream{Metric: metric}
+				streams[alertState] = stream
+				result = append(result, stream)
+			}
+			stream.Values = append(stream.Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(t.UnixNano()), Value: 1})
+		}
+	}
+	sort.Sort(result)
+	return result
+}
@@ -0,0 +1,62 @@
+package backtesting
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+)
+
+func testResultFrame(start time.Time) *data.Frame {
+	value := func(s string) *string {
+		return &s
+	}
+	times := data.NewField("Time", nil, []time.Time{start, start.Add(time.Minute), start.Add(2 * time.Minute)})
+	b := data.NewField("", data.Labels{"instance": "b"}, []*string{
+		value(eval.Pending.String()), value(eval.Alerting.String()), value(eval.Normal.String() + " (MissingSeries)"),
+	})
+	a := data.NewField("", data.Labels{"instance": "a", "team": "x"}, []*string{
+		value(eval.Normal.String()), nil, value(eval.Alerting.String()),
+	})
+	return data.NewFrame("Testing results", times, b, a)
+}
+
+func TestWriteCSV(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testResultFrame(start)))
+	require.Equal(t, "time,instance,team,state,reason\n"+
+		"2024-03-01T00:00:00Z,a,x,Normal,\n"+
+		"2024-03-01T00:00:00Z,b,,Pending,\n"+
+		"2024-03-01T00:01:00Z,b,,Alerting,\n"+
+		"2024-03-01T00:02:00Z,a,x,Alerting,\n"+
+		"2024-03-01T00:02:00Z,b,,Normal,MissingSeries\n", buf.String())
+}
+
+func TestToMatrix(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) model.Time {
+		return model.TimeFromUnixNano(start.Add(d).UnixNano())
+	}
+
+	require.Equal(t, model.Matrix{
+		{
+			Metric: model.Metric{model.MetricNameLabel: "ALERTS", "alertstate": "firing", "instance": "b"},
+			Values: []model.SamplePair{{Timestamp: ts(time.Minute), Value: 1}},
+		},
+		{
+			Metric: model.Metric{model.MetricNameLabel: "ALERTS", "alertstate": "pending", "instance": "b"},
+			Values: []model.SamplePair{{Timestamp: ts(0), Value: 1}},
+		},
+		{
+			Metric: model.Metric{model.MetricNameLabel: "ALERTS", "alertstate": "firing", "instance": "a", "team": "x"},
+			Values: []model.SamplePair{{Timestamp: ts(2 * time.Minute), Value: 1}},
+		},
+	}, ToMatrix(testResultFrame(start)))
+}
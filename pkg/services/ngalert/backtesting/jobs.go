@@ -0,0 +1,235 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// maxRunningJobs is the maximum number of backtesting jobs that run at the same time.
+	maxRunningJobs = 10
+	// jobRetention is how long the results of finished backtesting jobs are kept.
+	jobRetention = time.Hour
+	// maxJobDuration is how long a backtesting job can run before it is canceled and fails.
+	maxJobDuration = 30 * time.Minute
+)
+
+var (
+	ErrJobNotFound    = errors.New("backtesting job not found")
+	ErrJobNotFinished = errors.New("backtesting job is not finished")
+	ErrTooManyJobs    = fmt.Errorf("too many backtesting jobs are running, the maximum is %d", maxRunningJobs)
+)
+
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Job is a backtesting that runs in the background.
+type Job struct {
+	ID     string
+	OrgID  int64
+	Status JobStatus
+	// Evaluations is the number of evaluations that are done, out of Total.
+	Evaluations int
+	Total       int
+	// Error is the error of a failed job.
+	Error    error
+	Started  time.Time
+	Finished time.Time
+
+	// owner is the identity that submitted the job. Only the owner can get the job, because the results are the
+	// data of the queries of the owner.
+	owner  string
+	result *data.Frame
+	cancel context.CancelFunc
+}
+
+// jobs are the backtesting jobs of an engine. They are kept in memory, so they are lost when Grafana restarts and
+// are only available on the instance that runs them.
+type jobs struct {
+	mtx   sync.Mutex
+	clock clock.Clock
+	byID  map[string]*Job
+	// timeout is the maximum duration of the jobs.
+	timeout time.Duration
+}
+
+func newJobs(clk clock.Clock) *jobs {
+	return &jobs{
+		clock:   clk,
+		byID:    make(map[string]*Job),
+		timeout: maxJobDuration,
+	}
+}
+
+func jobOwner(user identity.Requester) string {
+	if user == nil {
+		return ""
+	}
+	namespace, id := user.GetNamespacedID()
+	return namespace + ":" + id
+}
+
+// start registers a new running job, which is canceled with the function, and removes the jobs that finished longer
+// than the retention ago.
+func (j *jobs) start(user identity.Requester, orgID int64, total int, cancel context.CancelFunc) (*Job, error) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	now := j.clock.Now()
+	running := 0
+	for id, job := range j.byID {
+		if job.Status == JobStatusRunning {
+			running++
+			continue
+		}
+		if now.Sub(job.Finished) > jobRetention {
+			delete(j.byID, id)
+		}
+	}
+	if running >= maxRunningJobs {
+		return nil, ErrTooManyJobs
+	}
+
+	job := &Job{
+		ID:      util.GenerateShortUID(),
+		OrgID:   orgID,
+		Status:  JobStatusRunning,
+		Total:   total,
+		Started: now,
+		owner:   jobOwner(user),
+		cancel:  cancel,
+	}
+	j.byID[job.ID] = job
+	return job, nil
+}
+
+func (j *jobs) progress(job *Job, evaluationIndex int) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	job.Evaluations = evaluationIndex + 1
+}
+
+func (j *jobs) finish(job *Job, result *data.Frame, err error) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	job.Finished = j.clock.Now()
+	if err != nil {
+		job.Status = JobStatusFailed
+		job.Error = err
+		return
+	}
+	job.Status = JobStatusSucceeded
+	job.Evaluations = job.Total
+	job.result = result
+}
+
+// lookup returns the job with the ID, if the user owns it. The caller must hold the lock.
+func (j *jobs) lookup(user identity.Requester, id string) (*Job, bool) {
+	job, ok := j.byID[id]
+	if !ok || job.OrgID != user.GetOrgID() || job.owner != jobOwner(user) {
+		return nil, false
+	}
+	return job, true
+}
+
+// get returns a copy of the job with the ID, if the user owns it.
+func (j *jobs) get(user identity.Requester, id string) (Job, *data.Frame, error) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	job, ok := j.lookup(user, id)
+	if !ok {
+		return Job{}, nil, ErrJobNotFound
+	}
+	return *job, job.result, nil
+}
+
+// remove removes the job with the ID, if the user owns it, and cancels it if it is running.
+func (j *jobs) remove(user identity.Requester, id string) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	job, ok := j.lookup(user, id)
+	if !ok {
+		return ErrJobNotFound
+	}
+	delete(j.byID, id)
+	job.cancel()
+	return nil
+}
+
+// Submit validates the input of a backtesting and runs it in the background, so that backtestings of long ranges do
+// not hold the request that submits them. It returns the job, whose progress and result are returned by GetJob and
+// GetJobResult.
+func (e *Engine) Submit(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (Job, error) {
+	run, err := e.prepare(ctx, user, rule, from, to)
+	if err != nil {
+		return Job{}, err
+	}
+	// the job outlives the request that submits it, but keeps the values of its context, such as the identity
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.jobs.timeout)
+	job, err := e.jobs.start(user, rule.OrgID, run.length, cancel)
+	if err != nil {
+		cancel()
+		return Job{}, err
+	}
+	result := *job
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				logger.FromContext(runCtx).Error("Backtesting job panicked", "job", job.ID, "error", r)
+				e.jobs.finish(job, nil, fmt.Errorf("backtesting panicked: %v", r))
+			}
+		}()
+		frame, err := run.run(runCtx, func(idx int) {
+			e.jobs.progress(job, idx)
+		})
+		if err != nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("backtesting job did not finish within %s: %w", e.jobs.timeout, err)
+		}
+		e.jobs.finish(job, frame, err)
+	}()
+	return result, nil
+}
+
+// GetJob returns the backtesting job with the ID that the user submitted.
+func (e *Engine) GetJob(user identity.Requester, id string) (Job, error) {
+	job, _, err := e.jobs.get(user, id)
+	return job, err
+}
+
+// DeleteJob removes the backtesting job with the ID that the user submitted, and cancels it if it is running.
+func (e *Engine) DeleteJob(user identity.Requester, id string) error {
+	return e.jobs.remove(user, id)
+}
+
+// GetJobResult returns the result of the backtesting job with the ID that the user submitted. It returns
+// ErrJobNotFinished if the job is running, and the error of the job if it failed.
+func (e *Engine) GetJobResult(user identity.Requester, id string) (*data.Frame, error) {
+	job, result, err := e.jobs.get(user, id)
+	if err != nil {
+		return nil, err
+	}
+	switch job.Status {
+	case JobStatusRunning:
+		return nil, ErrJobNotFinished
+	case JobStatusFailed:
+		return nil, job.Error
+	}
+	return result, nil
+}
@@ -0,0 +1,186 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestJobs(t *testing.T) {
+	// useEvaluator makes the jobs evaluate the rule with an evaluator that blocks until release is closed, and then
+	// returns the error
+	useEvaluator := func(release <-chan struct{}, err error) {
+		evaluator := &fakeBacktestingEvaluator{
+			evalCallback: func(now time.Time) (eval.Results, error) {
+				<-release
+				return eval.Results{}, err
+			},
+		}
+		backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader, _ tracing.Tracer) (backtestingEvaluator, error) {
+			return evaluator, nil
+		}
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+	released := make(chan struct{})
+	close(released)
+
+	clk := clock.NewMock()
+	newEngine := func() *Engine {
+		return &Engine{
+			createStateManager: func() stateManager {
+				return &fakeStateManager{stateCallback: func(now time.Time) []state.StateTransition { return nil }}
+			},
+			jobs: newJobs(clk),
+		}
+	}
+	owner := &user.SignedInUser{UserID: 1, OrgID: 1}
+	rule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(time.Second))()
+	from := time.Unix(0, 0)
+	to := from.Add(5 * time.Second)
+
+	waitForStatus := func(t *testing.T, e *Engine, id string, status JobStatus) Job {
+		t.Helper()
+		var job Job
+		require.Eventually(t, func() bool {
+			var err error
+			job, err = e.GetJob(owner, id)
+			require.NoError(t, err)
+			return job.Status == status
+		}, time.Second, 10*time.Millisecond)
+		return job
+	}
+
+	t.Run("should run the backtesting in the background", func(t *testing.T) {
+		release := make(chan struct{})
+		useEvaluator(release, nil)
+		e := newEngine()
+
+		job, err := e.Submit(context.Background(), owner, rule, from, to)
+		require.NoError(t, err)
+		require.Equal(t, JobStatusRunning, job.Status)
+		require.Equal(t, 5, job.Total)
+
+		_, err = e.GetJobResult(owner, job.ID)
+		require.ErrorIs(t, err, ErrJobNotFinished)
+
+		close(release)
+		job = waitForStatus(t, e, job.ID, JobStatusSucceeded)
+		require.Equal(t, 5, job.Evaluations)
+		require.False(t, job.Finished.IsZero())
+
+		frame, err := e.GetJobResult(owner, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, 5, frame.Rows())
+	})
+
+	t.Run("should return the error of a failed job", func(t *testing.T) {
+		evalErr := errors.New("query failed")
+		useEvaluator(released, evalErr)
+		e := newEngine()
+
+		job, err := e.Submit(context.Background(), owner, rule, from, to)
+		require.NoError(t, err)
+		job = waitForStatus(t, e, job.ID, JobStatusFailed)
+		require.ErrorIs(t, job.Error, evalErr)
+
+		_, err = e.GetJobResult(owner, job.ID)
+		require.ErrorIs(t, err, evalErr)
+	})
+
+	t.Run("should validate the input before submitting the job", func(t *testing.T) {
+		useEvaluator(released, nil)
+		e := newEngine()
+		_, err := e.Submit(context.Background(), owner, rule, to, from)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should only return jobs to their owner", func(t *testing.T) {
+		useEvaluator(released, nil)
+		e := newEngine()
+
+		job, err := e.Submit(context.Background(), owner, rule, from, to)
+		require.NoError(t, err)
+
+		_, err = e.GetJob(&user.SignedInUser{UserID: 2, OrgID: 1}, job.ID)
+		require.ErrorIs(t, err, ErrJobNotFound)
+		_, err = e.GetJobResult(&user.SignedInUser{UserID: 1, OrgID: 2}, job.ID)
+		require.ErrorIs(t, err, ErrJobNotFound)
+	})
+
+	t.Run("should limit the running jobs", func(t *testing.T) {
+		release := make(chan struct{})
+		useEvaluator(release, nil)
+		e := newEngine()
+
+		for i := 0; i < maxRunningJobs; i++ {
+			_, err := e.Submit(context.Background(), owner, rule, from, to)
+			require.NoError(t, err)
+		}
+		_, err := e.Submit(context.Background(), owner, rule, from, to)
+		require.ErrorIs(t, err, ErrTooManyJobs)
+		close(release)
+	})
+
+	t.Run("should cancel a running job when it is deleted", func(t *testing.T) {
+		release := make(chan struct{})
+		useEvaluator(release, nil)
+		e := newEngine()
+
+		job, err := e.Submit(context.Background(), owner, rule, from, to)
+		require.NoError(t, err)
+		running := e.jobs.byID[job.ID]
+
+		require.ErrorIs(t, e.DeleteJob(&user.SignedInUser{UserID: 2, OrgID: 1}, job.ID), ErrJobNotFound)
+		require.NoError(t, e.DeleteJob(owner, job.ID))
+		_, err = e.GetJob(owner, job.ID)
+		require.ErrorIs(t, err, ErrJobNotFound)
+
+		close(release)
+		require.Eventually(t, func() bool {
+			e.jobs.mtx.Lock()
+			defer e.jobs.mtx.Unlock()
+			return running.Status == JobStatusFailed
+		}, time.Second, 10*time.Millisecond)
+		require.ErrorIs(t, running.Error, context.Canceled)
+		require.Less(t, running.Evaluations, running.Total)
+	})
+
+	t.Run("should fail jobs that run longer than the maximum duration", func(t *testing.T) {
+		useEvaluator(released, nil)
+		e := newEngine()
+		e.jobs.timeout = time.Nanosecond
+
+		job, err := e.Submit(context.Background(), owner, rule, from, to)
+		require.NoError(t, err)
+		job = waitForStatus(t, e, job.ID, JobStatusFailed)
+		require.ErrorIs(t, job.Error, context.DeadlineExceeded)
+	})
+
+	t.Run("should remove finished jobs after the retention", func(t *testing.T) {
+		useEvaluator(released, nil)
+		e := newEngine()
+
+		job, err := e.Submit(context.Background(), owner, rule, from, to)
+		require.NoError(t, err)
+		waitForStatus(t, e, job.ID, JobStatusSucceeded)
+
+		clk.Add(jobRetention + time.Second)
+		_, err = e.Submit(context.Background(), owner, rule, from, to)
+		require.NoError(t, err)
+		_, err = e.GetJob(owner, job.ID)
+		require.ErrorIs(t, err, ErrJobNotFound)
+	})
+}
@@ -0,0 +1,137 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// MaxLoadTestSeries is the maximum number of synthetic series a query can return in a load test.
+	MaxLoadTestSeries = 50000
+	// MaxLoadTestEvaluations is the maximum number of evaluations of a load test.
+	MaxLoadTestEvaluations = 100
+)
+
+// LoadTestResult is the outcome of a load test of a rule.
+type LoadTestResult struct {
+	// Series is the number of series returned by each query of the rule.
+	Series int
+	// Evaluations is the number of evaluations of the rule.
+	Evaluations int
+	// Instances is the number of alert instances produced by the last evaluation.
+	Instances int
+	// Latencies are the durations of the evaluations, including the processing of the results by the state manager.
+	// The first evaluation includes the preparation of the synthetic data.
+	Latencies []time.Duration
+	// AllocatedBytes is the number of bytes allocated on the heap during the test.
+	AllocatedBytes uint64
+	// Allocations is the number of heap objects allocated during the test.
+	Allocations uint64
+}
+
+// LoadTest evaluates the rule against synthetic data, where each query of the rule returns the given number of series,
+// and measures the latency and the memory of the evaluations. The rule must be supported by the backtesting of data
+// queries, that is, its condition must be either a single query or a classic condition.
+// Memory is measured for the whole process, so the figures include the allocations of concurrent activity.
+func (e *Engine) LoadTest(ctx context.Context, rule *models.AlertRule, series, evaluations int) (*LoadTestResult, error) {
+	if series <= 0 || series > MaxLoadTestSeries {
+		return nil, fmt.Errorf("%w: number of series must be between 1 and %d", ErrInvalidInputData, MaxLoadTestSeries)
+	}
+	if evaluations <= 0 || evaluations > MaxLoadTestEvaluations {
+		return nil, fmt.Errorf("%w: number of evaluations must be between 1 and %d", ErrInvalidInputData, MaxLoadTestEvaluations)
+	}
+	if rule.IntervalSeconds <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive", ErrInvalidInputData)
+	}
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	logger := logger.FromContext(ctx)
+
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	to := time.Now().Truncate(interval)
+	from := to.Add(-time.Duration(evaluations) * interval)
+
+	condition := rule.GetEvalCondition()
+	// the seed is fixed so that the results of the same rule are comparable between runs
+	rnd := rand.New(rand.NewSource(1))
+	frames := make(map[string]*data.Frame)
+	for _, q := range condition.Data {
+		isExpr, err := q.IsExpression()
+		if err != nil {
+			return nil, errors.Join(ErrInvalidInputData, err)
+		}
+		if isExpr {
+			continue
+		}
+		frames[q.RefID] = syntheticFrame(rnd, series, from.Add(-time.Duration(q.RelativeTimeRange.From)), to, interval)
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("%w: rule does not have any queries", ErrInvalidInputData)
+	}
+
+	stateManager := e.createStateManager()
+	result := &LoadTestResult{
+		Series:      series,
+		Evaluations: evaluations,
+		Latencies:   make([]time.Duration, 0, evaluations),
+	}
+
+	logger.Info("Start load testing alert rule", "series", series, "evaluations", evaluations, "interval", interval)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	last := start
+
+	evaluator, err := newDataQueriesEvaluator(condition, frames, e.tracer)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidInputData, err)
+	}
+	err = evaluator.Eval(ruleCtx, from, interval, evaluations, func(_ int, now time.Time, results eval.Results) error {
+		states := stateManager.ProcessEvalResults(ruleCtx, now, rule, results, nil)
+		result.Instances = len(states)
+		current := time.Now()
+		result.Latencies = append(result.Latencies, current.Sub(last))
+		last = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runtime.ReadMemStats(&after)
+	result.AllocatedBytes = after.TotalAlloc - before.TotalAlloc
+	result.Allocations = after.Mallocs - before.Mallocs
+
+	logger.Info("Rule load testing finished successfully", "duration", time.Since(start))
+	return result, nil
+}
+
+// syntheticFrame returns a wide frame with the given number of series that have a point at every interval
+// in the range [from, to]. Each series has a distinct label and random values in the range [0, 100).
+func syntheticFrame(rnd *rand.Rand, series int, from, to time.Time, interval time.Duration) *data.Frame {
+	points := int(to.Sub(from)/interval) + 1
+	times := make([]time.Time, points)
+	for i := range times {
+		times[i] = from.Add(time.Duration(i) * interval)
+	}
+	fields := make(data.Fields, 0, series+1)
+	fields = append(fields, data.NewField("time", nil, times))
+	for i := 0; i < series; i++ {
+		values := make([]float64, points)
+		for j := range values {
+			values[j] = rnd.Float64() * 100
+		}
+		fields = append(fields, data.NewField("value", data.Labels{"series": strconv.Itoa(i)}, values))
+	}
+	return data.NewFrame("", fields...)
+}
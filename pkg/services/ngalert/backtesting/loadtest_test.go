@@ -0,0 +1,85 @@
+package backtesting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestLoadTest(t *testing.T) {
+	engine := NewEngine(nil, nil, tracing.InitializeTracerForTest())
+	query := models.AlertQuery{
+		RefID:             "A",
+		DatasourceUID:     "prometheus",
+		RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(time.Minute)},
+		Model:             json.RawMessage(`{"expr":"up"}`),
+	}
+	classicCondition := models.AlertQuery{
+		RefID:         "B",
+		DatasourceUID: expr.DatasourceUID,
+		Model: json.RawMessage(`{"type":"classic_conditions","conditions":[{
+			"evaluator":{"params":[50],"type":"gt"},
+			"operator":{"type":"and"},
+			"query":{"params":["A"]},
+			"reducer":{"type":"avg"}
+		}]}`),
+	}
+
+	t.Run("evaluates the query against synthetic series", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithInterval(10 * time.Second))()
+		rule.Condition = "A"
+		rule.Data = []models.AlertQuery{query}
+
+		result, err := engine.LoadTest(context.Background(), rule, 100, 5)
+		require.NoError(t, err)
+		require.Equal(t, 100, result.Series)
+		require.Equal(t, 5, result.Evaluations)
+		require.Equal(t, 100, result.Instances)
+		require.Len(t, result.Latencies, 5)
+		require.NotZero(t, result.AllocatedBytes)
+	})
+
+	t.Run("evaluates classic conditions against synthetic series", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithInterval(10 * time.Second))()
+		rule.Condition = "B"
+		rule.Data = []models.AlertQuery{query, classicCondition}
+
+		result, err := engine.LoadTest(context.Background(), rule, 10, 3)
+		require.NoError(t, err)
+		require.Len(t, result.Latencies, 3)
+		// classic conditions produce a single alert instance
+		require.Equal(t, 1, result.Instances)
+	})
+
+	t.Run("fails if the number of series is out of bounds", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithInterval(10 * time.Second))()
+		rule.Condition = "A"
+		rule.Data = []models.AlertQuery{query}
+
+		_, err := engine.LoadTest(context.Background(), rule, 0, 5)
+		require.True(t, errors.Is(err, ErrInvalidInputData))
+		_, err = engine.LoadTest(context.Background(), rule, MaxLoadTestSeries+1, 5)
+		require.True(t, errors.Is(err, ErrInvalidInputData))
+	})
+
+	t.Run("fails if the rule is not supported", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithInterval(10 * time.Second))()
+		rule.Condition = "B"
+		rule.Data = []models.AlertQuery{query, {
+			RefID:         "B",
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(`{"type":"math","expression":"$A > 1"}`),
+		}}
+
+		_, err := engine.LoadTest(context.Background(), rule, 10, 5)
+		require.True(t, errors.Is(err, ErrInvalidInputData))
+	})
+}
@@ -0,0 +1,295 @@
+// Package cleanup deletes the alerting data of deleted organizations in the background.
+//
+// Deleting an organization removes its alert rules, instances, and configuration right away, but the Alertmanager of
+// the organization and the data that only alerting knows about, like the metadata of silences and the history of the
+// configuration, are removed by a cleaner in small batches. The cleaner also finds the organizations that were
+// deleted while it was not running, or on another instance, and reports the progress of its jobs.
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// StepAlertmanager stops the Alertmanager of the organization and removes its silences and notification log.
+	StepAlertmanager = "alertmanager"
+
+	defaultInterval   = 10 * time.Minute
+	defaultBatchSize  = 1000
+	defaultBatchPause = 100 * time.Millisecond
+	// maxFinishedJobs is the number of finished jobs that are kept to report their outcome.
+	maxFinishedJobs = 100
+)
+
+// Store deletes the alerting data of deleted organizations.
+type Store interface {
+	GetDeletedOrgsWithData(ctx context.Context) ([]int64, error)
+	DeleteOrgData(ctx context.Context, orgID int64, kind store.OrgData, limit int) (int64, error)
+}
+
+// AlertmanagerRemover stops and removes the Alertmanager of an organization.
+type AlertmanagerRemover interface {
+	RemoveAlertmanager(ctx context.Context, orgID int64) error
+}
+
+type JobState string
+
+const (
+	JobStatePending   JobState = "pending"
+	JobStateRunning   JobState = "running"
+	JobStateCompleted JobState = "completed"
+	JobStateFailed    JobState = "failed"
+)
+
+// Job is the cleanup of the alerting data of a deleted organization.
+type Job struct {
+	OrgID    int64
+	State    JobState
+	Steps    []StepProgress
+	Created  time.Time
+	Started  time.Time
+	Finished time.Time
+	Error    string
+}
+
+// StepProgress is the progress of the deletion of a kind of alerting data.
+type StepProgress struct {
+	Name      string
+	Deleted   int64
+	Completed bool
+}
+
+type Config struct {
+	// Interval at which the cleaner looks for deleted organizations with alerting data.
+	Interval time.Duration
+	// BatchSize is the maximum number of rows deleted in a transaction.
+	BatchSize int
+	// BatchPause is the pause between batches, so that the cleanup does not compete with the rest of Grafana for
+	// the database.
+	BatchPause time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Interval:   defaultInterval,
+		BatchSize:  defaultBatchSize,
+		BatchPause: defaultBatchPause,
+	}
+}
+
+// Cleaner runs the cleanup jobs of deleted organizations, one at a time.
+type Cleaner struct {
+	cfg           Config
+	store         Store
+	alertmanagers AlertmanagerRemover
+	clock         clock.Clock
+	log           log.Logger
+
+	trigger chan struct{}
+
+	mtx  sync.Mutex
+	jobs map[int64]*Job
+}
+
+func NewCleaner(cfg Config, st Store, alertmanagers AlertmanagerRemover, clk clock.Clock, logger log.Logger) *Cleaner {
+	return &Cleaner{
+		cfg:           cfg,
+		store:         st,
+		alertmanagers: alertmanagers,
+		clock:         clk,
+		log:           logger,
+		trigger:       make(chan struct{}, 1),
+		jobs:          make(map[int64]*Job),
+	}
+}
+
+// Run looks for deleted organizations with alerting data at start, at every interval, and when an organization is
+// deleted, and cleans them up.
+func (c *Cleaner) Run(ctx context.Context) error {
+	ticker := c.clock.Ticker(c.cfg.Interval)
+	defer ticker.Stop()
+	c.sweep(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			c.sweep(ctx)
+		case <-c.trigger:
+			c.sweep(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// OrgDeleted schedules the cleanup of a deleted organization. Its Alertmanager is stopped by the cleanup even if
+// the organization does not have any data left in the database.
+func (c *Cleaner) OrgDeleted(orgID int64) {
+	c.mtx.Lock()
+	if job, ok := c.jobs[orgID]; !ok || job.State == JobStateCompleted || job.State == JobStateFailed {
+		c.jobs[orgID] = c.newJob(orgID)
+	}
+	c.mtx.Unlock()
+
+	select {
+	case c.trigger <- struct{}{}:
+	default:
+		// a sweep is already scheduled
+	}
+}
+
+// Jobs returns the pending and running jobs, and the most recent finished jobs, ordered by creation.
+func (c *Cleaner) Jobs() []Job {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	result := make([]Job, 0, len(c.jobs))
+	for _, job := range c.jobs {
+		j := *job
+		j.Steps = append([]StepProgress(nil), job.Steps...)
+		result = append(result, j)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Created.Equal(result[j].Created) {
+			return result[i].OrgID < result[j].OrgID
+		}
+		return result[i].Created.Before(result[j].Created)
+	})
+	return result
+}
+
+func (c *Cleaner) newJob(orgID int64) *Job {
+	steps := make([]StepProgress, 0, len(store.OrgDataKinds)+1)
+	steps = append(steps, StepProgress{Name: StepAlertmanager})
+	for _, kind := range store.OrgDataKinds {
+		steps = append(steps, StepProgress{Name: string(kind)})
+	}
+	return &Job{OrgID: orgID, State: JobStatePending, Steps: steps, Created: c.clock.Now()}
+}
+
+// sweep schedules the jobs of the deleted organizations that still have data, and runs the pending jobs.
+func (c *Cleaner) sweep(ctx context.Context) {
+	orgs, err := c.store.GetDeletedOrgsWithData(ctx)
+	if err != nil {
+		c.log.Error("Failed to find deleted organizations with alerting data", "error", err)
+	}
+
+	c.mtx.Lock()
+	for _, orgID := range orgs {
+		if job, ok := c.jobs[orgID]; !ok || job.State == JobStateCompleted || job.State == JobStateFailed {
+			c.jobs[orgID] = c.newJob(orgID)
+		}
+	}
+	pending := make([]int64, 0)
+	for orgID, job := range c.jobs {
+		if job.State == JobStatePending {
+			pending = append(pending, orgID)
+		}
+	}
+	c.mtx.Unlock()
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+	for _, orgID := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		c.runJob(ctx, orgID)
+	}
+	c.pruneFinishedJobs()
+}
+
+func (c *Cleaner) runJob(ctx context.Context, orgID int64) {
+	logger := c.log.New("org", orgID)
+	c.updateJob(orgID, func(job *Job) {
+		job.State = JobStateRunning
+		job.Started = c.clock.Now()
+	})
+	logger.Info("Cleaning up the alerting data of deleted organization")
+
+	err := c.runSteps(ctx, orgID)
+	c.updateJob(orgID, func(job *Job) {
+		job.Finished = c.clock.Now()
+		if err != nil {
+			job.State = JobStateFailed
+			job.Error = err.Error()
+			return
+		}
+		job.State = JobStateCompleted
+	})
+	if err != nil {
+		logger.Error("Failed to clean up the alerting data of deleted organization", "error", err)
+		return
+	}
+	logger.Info("Cleaned up the alerting data of deleted organization")
+}
+
+func (c *Cleaner) runSteps(ctx context.Context, orgID int64) error {
+	if err := c.alertmanagers.RemoveAlertmanager(ctx, orgID); err != nil {
+		return fmt.Errorf("failed to remove the Alertmanager: %w", err)
+	}
+	c.updateStep(orgID, StepAlertmanager, 0, true)
+
+	for _, kind := range store.OrgDataKinds {
+		for {
+			deleted, err := c.store.DeleteOrgData(ctx, orgID, kind, c.cfg.BatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to delete %s: %w", kind, err)
+			}
+			c.updateStep(orgID, string(kind), deleted, deleted == 0)
+			if deleted == 0 {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-c.clock.After(c.cfg.BatchPause):
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Cleaner) updateJob(orgID int64, update func(job *Job)) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if job, ok := c.jobs[orgID]; ok {
+		update(job)
+	}
+}
+
+func (c *Cleaner) updateStep(orgID int64, name string, deleted int64, completed bool) {
+	c.updateJob(orgID, func(job *Job) {
+		for i := range job.Steps {
+			if job.Steps[i].Name == name {
+				job.Steps[i].Deleted += deleted
+				job.Steps[i].Completed = completed
+			}
+		}
+	})
+}
+
+// pruneFinishedJobs forgets the oldest finished jobs beyond maxFinishedJobs.
+func (c *Cleaner) pruneFinishedJobs() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	finished := make([]*Job, 0)
+	for _, job := range c.jobs {
+		if job.State == JobStateCompleted || job.State == JobStateFailed {
+			finished = append(finished, job)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].Finished.Before(finished[j].Finished) })
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		delete(c.jobs, job.OrgID)
+	}
+}
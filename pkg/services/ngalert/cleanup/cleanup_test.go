@@ -0,0 +1,119 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type fakeStore struct {
+	// rows of each kind of data of each deleted organization
+	rows      map[int64]map[store.OrgData]int64
+	deleteErr error
+	batches   int
+}
+
+func (f *fakeStore) GetDeletedOrgsWithData(_ context.Context) ([]int64, error) {
+	var orgs []int64
+	for orgID, kinds := range f.rows {
+		for _, n := range kinds {
+			if n > 0 {
+				orgs = append(orgs, orgID)
+				break
+			}
+		}
+	}
+	return orgs, nil
+}
+
+func (f *fakeStore) DeleteOrgData(_ context.Context, orgID int64, kind store.OrgData, limit int) (int64, error) {
+	if f.deleteErr != nil {
+		return 0, f.deleteErr
+	}
+	f.batches++
+	n := min(f.rows[orgID][kind], int64(limit))
+	if n > 0 {
+		f.rows[orgID][kind] -= n
+	}
+	return n, nil
+}
+
+type fakeAlertmanagers struct {
+	removed []int64
+}
+
+func (f *fakeAlertmanagers) RemoveAlertmanager(_ context.Context, orgID int64) error {
+	f.removed = append(f.removed, orgID)
+	return nil
+}
+
+func newTestCleaner(st Store, ams AlertmanagerRemover) *Cleaner {
+	cfg := Config{Interval: defaultInterval, BatchSize: 2}
+	return NewCleaner(cfg, st, ams, clock.NewMock(), log.NewNopLogger())
+}
+
+func TestCleaner(t *testing.T) {
+	t.Run("should delete the data of deleted organizations in batches", func(t *testing.T) {
+		st := &fakeStore{rows: map[int64]map[store.OrgData]int64{
+			3: {store.OrgDataSilences: 5, store.OrgDataConfigurationHistory: 1},
+		}}
+		ams := &fakeAlertmanagers{}
+		c := newTestCleaner(st, ams)
+
+		c.sweep(context.Background())
+
+		require.Equal(t, []int64{3}, ams.removed)
+		jobs := c.Jobs()
+		require.Len(t, jobs, 1)
+		require.Equal(t, int64(3), jobs[0].OrgID)
+		require.Equal(t, JobStateCompleted, jobs[0].State)
+		require.Empty(t, jobs[0].Error)
+
+		deleted := map[string]int64{}
+		for _, step := range jobs[0].Steps {
+			require.True(t, step.Completed, step.Name)
+			deleted[step.Name] = step.Deleted
+		}
+		require.Equal(t, int64(5), deleted[string(store.OrgDataSilences)])
+		require.Equal(t, int64(1), deleted[string(store.OrgDataConfigurationHistory)])
+		// the last batch of each kind of data is empty
+		require.Equal(t, 4+2, st.batches)
+	})
+
+	t.Run("should remove the Alertmanager of organizations without data", func(t *testing.T) {
+		st := &fakeStore{rows: map[int64]map[store.OrgData]int64{}}
+		ams := &fakeAlertmanagers{}
+		c := newTestCleaner(st, ams)
+
+		c.OrgDeleted(7)
+		require.Equal(t, JobStatePending, c.Jobs()[0].State)
+		c.sweep(context.Background())
+
+		require.Equal(t, []int64{7}, ams.removed)
+		require.Equal(t, JobStateCompleted, c.Jobs()[0].State)
+	})
+
+	t.Run("should report failed jobs and retry them", func(t *testing.T) {
+		st := &fakeStore{
+			rows:      map[int64]map[store.OrgData]int64{4: {store.OrgDataSilences: 1}},
+			deleteErr: errors.New("database is locked"),
+		}
+		c := newTestCleaner(st, &fakeAlertmanagers{})
+
+		c.sweep(context.Background())
+		job := c.Jobs()[0]
+		require.Equal(t, JobStateFailed, job.State)
+		require.Contains(t, job.Error, "database is locked")
+
+		st.deleteErr = nil
+		c.sweep(context.Background())
+		require.Equal(t, JobStateCompleted, c.Jobs()[0].State)
+		require.Zero(t, st.rows[4][store.OrgDataSilences])
+	})
+}
@@ -317,9 +317,10 @@ func buildDatasourceHeaders(ctx context.Context) map[string]string {
 // getExprRequest validates the condition, gets the datasource information and creates an expr.Request from it.
 func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheService datasources.CacheService, reader AlertingResultsReader) (*expr.Request, error) {
 	req := &expr.Request{
-		OrgId:   ctx.User.GetOrgID(),
-		Headers: buildDatasourceHeaders(ctx.Ctx),
-		User:    ctx.User,
+		OrgId:          ctx.User.GetOrgID(),
+		Headers:        buildDatasourceHeaders(ctx.Ctx),
+		User:           ctx.User,
+		PartialResults: condition.PartialResults,
 	}
 	datasources := make(map[string]*datasources.DataSource, len(condition.Data))
 
@@ -0,0 +1,174 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/screenshot"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	queryPreviewWidth  = 600
+	queryPreviewHeight = 300
+)
+
+type QueryPreviewService interface {
+	// NewQueryPreview returns a new image of the queries of the alert rule in the window ending at the time.
+	NewQueryPreview(ctx context.Context, r *models.AlertRule, at time.Time) (*models.Image, error)
+}
+
+// RenderQueryPreviewService renders a small chart of the first data source query of the alert rule
+// using the rendering service, and saves the image in the store. Unlike ScreenshotImageService, it does
+// not require the alert rule to be associated with a dashboard panel.
+type RenderQueryPreviewService struct {
+	limiter     screenshot.RateLimiter
+	logger      log.Logger
+	renderLimit int
+	renders     rendering.Service
+	store       store.ImageStore
+	timeout     time.Duration
+	uploads     *UploadingService
+	window      time.Duration
+}
+
+// NewQueryPreviewServiceFromCfg returns a new RenderQueryPreviewService from the configuration,
+// or nil if query previews are disabled.
+func NewQueryPreviewServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, rs rendering.Service, r prometheus.Registerer) (QueryPreviewService, error) {
+	settings := cfg.UnifiedAlerting.Screenshots
+	if !settings.QueryPreview {
+		return nil, nil
+	}
+
+	var uploads *UploadingService
+	if settings.UploadExternalImageStorage {
+		m, err := imguploader.NewImageUploader(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize uploading query preview service: %w", err)
+		}
+		uploads = NewUploadingService(m, r)
+	}
+
+	return &RenderQueryPreviewService{
+		limiter:     screenshot.NewTokenRateLimiter(settings.MaxConcurrentScreenshots),
+		logger:      log.New("ngalert.image.query-preview"),
+		renderLimit: cfg.AlertingRenderLimit,
+		renders:     rs,
+		store:       db,
+		timeout:     settings.CaptureTimeout,
+		uploads:     uploads,
+		window:      settings.QueryPreviewWindow,
+	}, nil
+}
+
+// NewQueryPreview returns a preview of the queries of the alert rule or an error.
+//
+// If none of the queries of the alert rule is a data source query a models.ErrNoDataSourceQuery
+// error is returned.
+func (s *RenderQueryPreviewService) NewQueryPreview(ctx context.Context, r *models.AlertRule, at time.Time) (*models.Image, error) {
+	logger := s.logger.FromContext(ctx).New("rule_uid", r.UID, "org_id", r.OrgID)
+
+	path, err := queryPreviewPath(r, at.Add(-s.window), at)
+	if err != nil {
+		return nil, err
+	}
+
+	previewCtx, cancelFunc := context.WithTimeout(ctx, s.timeout)
+	defer cancelFunc()
+
+	// Previews are rate-limited like screenshots, the options are passed through to the render function.
+	opts := screenshot.ScreenshotOptions{OrgID: r.OrgID, Timeout: s.timeout}
+	result, err := s.limiter.Do(previewCtx, opts, func(ctx context.Context, opts screenshot.ScreenshotOptions) (*screenshot.Screenshot, error) {
+		res, err := s.renders.Render(ctx, rendering.RenderPNG, rendering.Opts{
+			AuthOpts: rendering.AuthOpts{
+				OrgID:   r.OrgID,
+				OrgRole: org.RoleAdmin,
+			},
+			ErrorOpts: rendering.ErrorOpts{
+				ErrorConcurrentLimitReached: true,
+				ErrorRenderUnavailable:      true,
+			},
+			TimeoutOpts: rendering.TimeoutOpts{
+				Timeout: opts.Timeout,
+			},
+			Width:           queryPreviewWidth,
+			Height:          queryPreviewHeight,
+			Theme:           screenshot.DefaultTheme,
+			ConcurrentLimit: s.renderLimit,
+			Path:            path,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &screenshot.Screenshot{Path: res.FilePath}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render query preview: %w", err)
+	}
+
+	logger.Debug("Rendered query preview", "path", result.Path)
+	image := models.Image{Path: result.Path}
+
+	// Uploading images is optional
+	if s.uploads != nil {
+		if image, err = s.uploads.Upload(ctx, image); err != nil {
+			logger.Warn("Failed to upload query preview", "error", err)
+		}
+	}
+
+	if err := s.store.SaveImage(ctx, &image); err != nil {
+		return nil, fmt.Errorf("failed to save query preview: %w", err)
+	}
+	logger.Debug("Saved query preview", "token", image.Token)
+	return &image, nil
+}
+
+// queryPreviewPath returns the path of the Explore page, in kiosk mode, that shows the first data source
+// query of the alert rule in the time range.
+func queryPreviewPath(r *models.AlertRule, from, to time.Time) (string, error) {
+	for _, q := range r.Data {
+		if isExpr, _ := q.IsExpression(); isExpr {
+			continue
+		}
+
+		query := map[string]any{}
+		if err := json.Unmarshal(q.Model, &query); err != nil {
+			return "", fmt.Errorf("failed to parse the model of the query %s: %w", q.RefID, err)
+		}
+		query["refId"] = q.RefID
+		query["datasource"] = map[string]any{"uid": q.DatasourceUID}
+
+		left, err := json.Marshal(map[string]any{
+			"datasource": q.DatasourceUID,
+			"queries":    []any{query},
+			"range": map[string]string{
+				"from": strconv.FormatInt(from.UnixMilli(), 10),
+				"to":   strconv.FormatInt(to.UnixMilli(), 10),
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+
+		u := url.URL{Path: "explore"}
+		p := u.Query()
+		p.Add("orgId", strconv.FormatInt(r.OrgID, 10))
+		p.Add("left", string(left))
+		p.Add("kiosk", "")
+		u.RawQuery = p.Encode()
+		return u.String(), nil
+	}
+	return "", models.ErrNoDataSourceQuery
+}
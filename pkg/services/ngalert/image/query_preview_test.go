@@ -0,0 +1,42 @@
+package image
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestQueryPreviewPath(t *testing.T) {
+	to := time.Unix(1700000000, 0)
+	rule := &models.AlertRule{
+		OrgID: 1,
+		Data: []models.AlertQuery{
+			{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "reduce", "expression": "A"}`)},
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr": "up"}`)},
+		},
+	}
+
+	p, err := queryPreviewPath(rule, to.Add(-time.Hour), to)
+	require.NoError(t, err)
+
+	u, err := url.Parse(p)
+	require.NoError(t, err)
+	require.Equal(t, "explore", u.Path)
+	require.Equal(t, "1", u.Query().Get("orgId"))
+	require.True(t, u.Query().Has("kiosk"))
+	require.JSONEq(t, `{
+		"datasource": "prometheus",
+		"queries": [{"refId": "A", "expr": "up", "datasource": {"uid": "prometheus"}}],
+		"range": {"from": "1699996400000", "to": "1700000000000"}
+	}`, u.Query().Get("left"))
+
+	rule.Data = rule.Data[:1]
+	_, err = queryPreviewPath(rule, to.Add(-time.Hour), to)
+	require.ErrorIs(t, err, models.ErrNoDataSourceQuery)
+}
@@ -0,0 +1,135 @@
+// Package insights tracks how the alerting objects are used, so that the unused ones can be found and cleaned up.
+//
+// The tracker counts the notifications sent by the contact points and about the alerts of the rules, and records
+// when the rules last fired and when the contact points were last changed. The usage is kept in memory and added to
+// the usage in the database at every interval, so that tracking it does not write to the database on every
+// evaluation or notification.
+package insights
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const defaultFlushInterval = time.Minute
+
+// Store adds the usage of the alerting objects to the usage in the database.
+type Store interface {
+	AddUsageInsights(ctx context.Context, insights []models.UsageInsight) error
+}
+
+type objectKey struct {
+	orgID int64
+	kind  models.UsageInsightKind
+	uid   string
+}
+
+// Tracker buffers the usage of the alerting objects and periodically adds it to the store.
+// It implements state.UsageRecorder and notifier.UsageRecorder.
+type Tracker struct {
+	store    Store
+	interval time.Duration
+	clock    clock.Clock
+	log      log.Logger
+
+	mtx     sync.Mutex
+	pending map[objectKey]*models.UsageInsight
+}
+
+func NewTracker(st Store, clk clock.Clock, logger log.Logger) *Tracker {
+	return &Tracker{
+		store:    st,
+		interval: defaultFlushInterval,
+		clock:    clk,
+		log:      logger,
+		pending:  make(map[objectKey]*models.UsageInsight),
+	}
+}
+
+// RuleFired records that the rule was evaluated with firing alerts at the time.
+func (t *Tracker) RuleFired(orgID int64, ruleUID string, at time.Time) {
+	t.add(models.UsageInsight{OrgID: orgID, Kind: models.UsageInsightKindAlertRule, UID: ruleUID, LastFired: &at})
+}
+
+// ContactPointNotified records that the contact point sent a notification about the alerts of the rules at the time.
+func (t *Tracker) ContactPointNotified(orgID int64, uid string, ruleUIDs []string, at time.Time) {
+	t.add(models.UsageInsight{OrgID: orgID, Kind: models.UsageInsightKindContactPoint, UID: uid, LastNotified: &at, Notifications: 1})
+	for _, ruleUID := range ruleUIDs {
+		t.add(models.UsageInsight{OrgID: orgID, Kind: models.UsageInsightKindAlertRule, UID: ruleUID, LastNotified: &at, Notifications: 1})
+	}
+}
+
+// ContactPointModified records that the contact point was created or changed at the time.
+func (t *Tracker) ContactPointModified(orgID int64, uid string, at time.Time) {
+	t.add(models.UsageInsight{OrgID: orgID, Kind: models.UsageInsightKindContactPoint, UID: uid, LastModified: &at})
+}
+
+func (t *Tracker) add(insight models.UsageInsight) {
+	if insight.UID == "" {
+		return
+	}
+	key := objectKey{orgID: insight.OrgID, kind: insight.Kind, uid: insight.UID}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if existing, ok := t.pending[key]; ok {
+		existing.Merge(insight)
+		return
+	}
+	t.pending[key] = &insight
+}
+
+// Run adds the buffered usage to the store at every interval, and once more when the context is done.
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := t.clock.Ticker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.flush(ctx); err != nil {
+				t.log.Error("Failed to save the usage of alerting objects", "error", err)
+			}
+		case <-ctx.Done():
+			// save what is left, but do not block the shutdown for longer than a flush interval
+			flushCtx, cancel := context.WithTimeout(context.Background(), t.interval)
+			defer cancel()
+			if err := t.flush(flushCtx); err != nil {
+				t.log.Error("Failed to save the usage of alerting objects on shutdown", "error", err)
+			}
+			return nil
+		}
+	}
+}
+
+// flush adds the buffered usage to the store. If it cannot be added, it is kept for the next attempt.
+func (t *Tracker) flush(ctx context.Context) error {
+	t.mtx.Lock()
+	pending := t.pending
+	t.pending = make(map[objectKey]*models.UsageInsight)
+	t.mtx.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	insights := make([]models.UsageInsight, 0, len(pending))
+	for _, insight := range pending {
+		insights = append(insights, *insight)
+	}
+	if err := t.store.AddUsageInsights(ctx, insights); err != nil {
+		t.mtx.Lock()
+		for key, insight := range pending {
+			if newer, ok := t.pending[key]; ok {
+				insight.Merge(*newer)
+			}
+			t.pending[key] = insight
+		}
+		t.mtx.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package insights
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeStore struct {
+	added [][]models.UsageInsight
+	err   error
+}
+
+func (f *fakeStore) AddUsageInsights(_ context.Context, insights []models.UsageInsight) error {
+	if f.err != nil {
+		return f.err
+	}
+	sort.Slice(insights, func(i, j int) bool {
+		if insights[i].Kind != insights[j].Kind {
+			return insights[i].Kind < insights[j].Kind
+		}
+		return insights[i].UID < insights[j].UID
+	})
+	f.added = append(f.added, insights)
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		v := start.Add(time.Duration(minutes) * time.Minute)
+		return &v
+	}
+
+	t.Run("should merge the usage of every object until it is flushed", func(t *testing.T) {
+		st := &fakeStore{}
+		tracker := NewTracker(st, clock.NewMock(), log.NewNopLogger())
+
+		tracker.RuleFired(1, "rule", *at(2))
+		tracker.RuleFired(1, "rule", *at(1))
+		tracker.ContactPointNotified(1, "email", []string{"rule", "other"}, *at(3))
+		tracker.ContactPointNotified(1, "email", []string{"rule"}, *at(4))
+		tracker.ContactPointModified(1, "email", *at(0))
+		tracker.ContactPointNotified(1, "", nil, *at(5))
+		require.NoError(t, tracker.flush(context.Background()))
+
+		require.Len(t, st.added, 1)
+		require.Equal(t, []models.UsageInsight{
+			{OrgID: 1, Kind: models.UsageInsightKindAlertRule, UID: "other", LastNotified: at(3), Notifications: 1},
+			{OrgID: 1, Kind: models.UsageInsightKindAlertRule, UID: "rule", LastFired: at(2), LastNotified: at(4), Notifications: 2},
+			{OrgID: 1, Kind: models.UsageInsightKindContactPoint, UID: "email", LastModified: at(0), LastNotified: at(4), Notifications: 2},
+		}, st.added[0])
+
+		require.NoError(t, tracker.flush(context.Background()))
+		require.Len(t, st.added, 1, "nothing must be added if nothing was used")
+	})
+
+	t.Run("should keep the usage if it cannot be added", func(t *testing.T) {
+		st := &fakeStore{err: errors.New("database is locked")}
+		tracker := NewTracker(st, clock.NewMock(), log.NewNopLogger())
+
+		tracker.ContactPointNotified(1, "email", nil, *at(1))
+		require.Error(t, tracker.flush(context.Background()))
+		tracker.ContactPointNotified(1, "email", nil, *at(2))
+
+		st.err = nil
+		require.NoError(t, tracker.flush(context.Background()))
+		require.Equal(t, []models.UsageInsight{
+			{OrgID: 1, Kind: models.UsageInsightKindContactPoint, UID: "email", LastNotified: at(2), Notifications: 2},
+		}, st.added[0])
+	})
+}
@@ -2,6 +2,7 @@ package ngalert
 
 import (
 	"context"
+	"sort"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -10,6 +11,7 @@ import (
 
 type RuleUsageReader interface {
 	Count(ctx context.Context, orgID int64) (int64, error)
+	CountByFolder(ctx context.Context, orgID int64) (map[string]int64, error)
 }
 
 func RegisterQuotas(cfg *setting.Cfg, qs quota.Service, rules RuleUsageReader) error {
@@ -19,9 +21,10 @@ func RegisterQuotas(cfg *setting.Cfg, qs quota.Service, rules RuleUsageReader) e
 	}
 
 	return qs.RegisterQuotaReporter(&quota.NewUsageReporter{
-		TargetSrv:     models.QuotaTargetSrv,
-		DefaultLimits: defaultLimits,
-		Reporter:      UsageReporter(rules),
+		TargetSrv:         models.QuotaTargetSrv,
+		DefaultLimits:     defaultLimits,
+		Reporter:          UsageReporter(rules),
+		BreakdownReporter: UsageBreakdownReporter(rules),
 	})
 }
 
@@ -58,6 +61,39 @@ func UsageReporter(rules RuleUsageReader) quota.UsageReporterFunc {
 	}
 }
 
+// UsageBreakdownReporter reports the number of alert rules stored in each folder of the organization.
+func UsageBreakdownReporter(rules RuleUsageReader) quota.UsageBreakdownReporterFunc {
+	return func(ctx context.Context, scopeParams *quota.ScopeParameters) (map[quota.Tag][]quota.UsageBreakdown, error) {
+		if scopeParams == nil || scopeParams.OrgID == 0 {
+			return nil, nil
+		}
+
+		counts, err := rules.CountByFolder(ctx, scopeParams.OrgID)
+		if err != nil {
+			return nil, err
+		}
+
+		tag, err := quota.NewTag(models.QuotaTargetSrv, models.QuotaTarget, quota.OrgScope)
+		if err != nil {
+			return nil, err
+		}
+
+		breakdown := make([]quota.UsageBreakdown, 0, len(counts))
+		for folderUID, count := range counts {
+			breakdown = append(breakdown, quota.UsageBreakdown{
+				Kind: quota.UsageBreakdownKindFolder,
+				Key:  folderUID,
+				Used: count,
+			})
+		}
+		sort.Slice(breakdown, func(i, j int) bool {
+			return breakdown[i].Key < breakdown[j].Key
+		})
+
+		return map[quota.Tag][]quota.UsageBreakdown{tag: breakdown}, nil
+	}
+}
+
 func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
 	limits := &quota.Map{}
 
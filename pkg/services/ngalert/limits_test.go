@@ -87,8 +87,40 @@ func TestReadQuotaConfig(t *testing.T) {
 	})
 }
 
+func TestUsageBreakdownReporter(t *testing.T) {
+	t.Run("reports org usage per folder", func(t *testing.T) {
+		rules := newFakeUsageReader(map[int64]int64{1: 10, 2: 20})
+		rules.folders = map[int64]map[string]int64{
+			1: {"folder-b": 6, "folder-a": 4},
+			2: {"folder-c": 20},
+		}
+		params := quota.ScopeParameters{
+			OrgID: 1,
+		}
+
+		res, err := UsageBreakdownReporter(rules)(context.Background(), &params)
+
+		require.NoError(t, err)
+		rulesOrg, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTarget, quota.OrgScope)
+		require.Equal(t, []quota.UsageBreakdown{
+			{Kind: quota.UsageBreakdownKindFolder, Key: "folder-a", Used: 4},
+			{Kind: quota.UsageBreakdownKindFolder, Key: "folder-b", Used: 6},
+		}, res[rulesOrg])
+	})
+
+	t.Run("reports nothing if scope params are nil", func(t *testing.T) {
+		rules := newFakeUsageReader(map[int64]int64{1: 10})
+
+		res, err := UsageBreakdownReporter(rules)(context.Background(), nil)
+
+		require.NoError(t, err)
+		require.Empty(t, res)
+	})
+}
+
 type fakeUsageReader struct {
-	usage map[int64]int64 // orgID -> count
+	usage   map[int64]int64            // orgID -> count
+	folders map[int64]map[string]int64 // orgID -> folderUID -> count
 }
 
 func newFakeUsageReader(usage map[int64]int64) fakeUsageReader {
@@ -97,6 +129,10 @@ func newFakeUsageReader(usage map[int64]int64) fakeUsageReader {
 	}
 }
 
+func (f fakeUsageReader) CountByFolder(_ context.Context, orgID int64) (map[string]int64, error) {
+	return f.folders[orgID], nil
+}
+
 func (f fakeUsageReader) Count(_ context.Context, orgID int64) (int64, error) {
 	if orgID == 0 {
 		total := int64(0)
@@ -17,6 +17,7 @@ type MultiOrgAlertmanager struct {
 
 	ActiveConfigurations     prometheus.Gauge
 	DiscoveredConfigurations prometheus.Gauge
+	SilencesPurged           *prometheus.CounterVec
 
 	aggregatedMetrics *AlertmanagerAggregatedMetrics
 }
@@ -38,6 +39,12 @@ func NewMultiOrgAlertmanagerMetrics(r prometheus.Registerer) *MultiOrgAlertmanag
 			Name:      "active_configurations",
 			Help:      "The number of active Alertmanager configurations.",
 		}),
+		SilencesPurged: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "silences_purged_total",
+			Help:      "The number of expired silences purged on demand before the end of their retention.",
+		}, []string{"org"}),
 		aggregatedMetrics: NewAlertmanagerAggregatedMetrics(registries),
 	}
 
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// OrgMetrics are the counters of the alert rules and the Alertmanager of an organization.
+type OrgMetrics struct {
+	RuleEvaluations        int64
+	RuleEvaluationFailures int64
+	NotificationsSent      int64
+	NotificationsFailed    int64
+	ActiveSilences         int64
+}
+
+// GetOrgMetrics returns the counters of the organization, as they are exposed on the metrics endpoint.
+// Counters are reset when Grafana restarts.
+func (ng *NGAlert) GetOrgMetrics(orgID int64) (OrgMetrics, error) {
+	org := strconv.FormatInt(orgID, 10)
+	result := OrgMetrics{
+		RuleEvaluations:        int64(sumWithLabel(ng.schedulerMetrics.EvalTotal, "org", org)),
+		RuleEvaluationFailures: int64(sumWithLabel(ng.schedulerMetrics.EvalFailures, "org", org)),
+	}
+
+	reg := ng.multiOrgAlertmanagerMetrics.registries.GetRegistryForTenant(org)
+	if reg == nil {
+		// the Alertmanager of the organization is not running
+		return result, nil
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		return OrgMetrics{}, err
+	}
+	attempted := int64(sumFamily(families, "alertmanager_notifications_total", "", ""))
+	result.NotificationsFailed = int64(sumFamily(families, "alertmanager_notifications_failed_total", "", ""))
+	// attempted notifications include the failed ones
+	result.NotificationsSent = attempted - result.NotificationsFailed
+	result.ActiveSilences = int64(sumFamily(families, "alertmanager_silences", "state", "active"))
+	return result, nil
+}
+
+// sumWithLabel returns the sum of the values of the series of the collector that have the label.
+func sumWithLabel(c prometheus.Collector, name, value string) float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+	var sum float64
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			continue
+		}
+		if hasLabel(&metric, name, value) {
+			sum += metricValue(&metric)
+		}
+	}
+	return sum
+}
+
+// sumFamily returns the sum of the values of the series of the metric family. If a label name is given,
+// only the series that have the label are summed.
+func sumFamily(families []*dto.MetricFamily, family, name, value string) float64 {
+	var sum float64
+	for _, f := range families {
+		if f.GetName() != family {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if name == "" || hasLabel(m, name, value) {
+				sum += metricValue(m)
+			}
+		}
+	}
+	return sum
+}
+
+func hasLabel(m *dto.Metric, name, value string) bool {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue() == value
+		}
+	}
+	return false
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue()
+	case m.GetGauge() != nil:
+		return m.GetGauge().GetValue()
+	default:
+		return 0
+	}
+}
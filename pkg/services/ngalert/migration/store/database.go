@@ -354,16 +354,13 @@ func batchBy[T any](items []T, batchSize int) (batches [][]T) {
 	return append(batches, items)
 }
 
-// InsertAlertRules inserts alert rules.
+// InsertAlertRules inserts alert rules. The write fails if any of the rules cannot be inserted.
 func (ms *migrationStore) InsertAlertRules(ctx context.Context, rules ...models.AlertRule) error {
-	batches := batchBy(rules, BATCHSIZE)
-	for _, batch := range batches {
-		_, err := ms.alertingStore.InsertAlertRules(ctx, batch)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	_, err := ms.alertingStore.BulkWriteAlertRules(ctx, models.BulkWriteRulesCommand{
+		Insert: rules,
+		Atomic: true,
+	})
+	return err
 }
 
 // DeleteAlertRules deletes alert rules in a given org by their UIDs.
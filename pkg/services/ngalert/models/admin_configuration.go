@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"time"
 )
 
 type AlertmanagersChoice int
@@ -26,10 +27,62 @@ type AdminConfiguration struct {
 	// SendAlertsTo indicates which set of alertmanagers will handle the alert.
 	SendAlertsTo AlertmanagersChoice `xorm:"send_alerts_to"`
 
+	// DefaultEvaluationIntervalSeconds is the evaluation interval of rule groups that inherit the interval of the organization.
+	// If it is zero, the default evaluation interval of the instance is used.
+	DefaultEvaluationIntervalSeconds int64 `xorm:"default_evaluation_interval_seconds"`
+
+	// AllowedEvaluationIntervalsSeconds restricts the evaluation intervals rule groups of the organization can use.
+	// If it is empty, any interval is allowed.
+	AllowedEvaluationIntervalsSeconds []int64 `xorm:"allowed_evaluation_intervals"`
+
+	// AnnotationSchema describes the annotations alert rules of the organization must define.
+	AnnotationSchema AnnotationSchema `xorm:"annotation_schema"`
+
+	// LabelRewriteRules rewrite the labels that data sources return for the alert instances of the organization.
+	LabelRewriteRules LabelRewriteRules `xorm:"label_rewrite_rules"`
+
+	// MissingSeriesEvalsToResolve and MissingSeriesDurationToResolve are the stale series policy of the rules
+	// of the organization that do not set their own.
+	MissingSeriesEvalsToResolve    int64         `xorm:"missing_series_evals_to_resolve"`
+	MissingSeriesDurationToResolve time.Duration `xorm:"missing_series_duration_to_resolve"`
+
 	CreatedAt int64 `xorm:"created"`
 	UpdatedAt int64 `xorm:"updated"`
 }
 
+// EffectiveEvaluationIntervalSeconds returns the evaluation interval of rule groups that inherit the interval of the organization.
+// The given default is returned if the configuration does not define one.
+func (cfg *AdminConfiguration) EffectiveEvaluationIntervalSeconds(defaultIntervalSeconds int64) int64 {
+	if cfg == nil || cfg.DefaultEvaluationIntervalSeconds <= 0 {
+		return defaultIntervalSeconds
+	}
+	return cfg.DefaultEvaluationIntervalSeconds
+}
+
+// IsEvaluationIntervalAllowed returns true if rule groups of the organization can use the interval.
+func (cfg *AdminConfiguration) IsEvaluationIntervalAllowed(intervalSeconds int64) bool {
+	if cfg == nil || len(cfg.AllowedEvaluationIntervalsSeconds) == 0 {
+		return true
+	}
+	for _, allowed := range cfg.AllowedEvaluationIntervalsSeconds {
+		if allowed == intervalSeconds {
+			return true
+		}
+	}
+	return false
+}
+
+// StaleSeriesPolicy returns the stale series policy of the organization. It is zero if the configuration does not set one.
+func (cfg *AdminConfiguration) StaleSeriesPolicy() StaleSeriesPolicy {
+	if cfg == nil {
+		return StaleSeriesPolicy{}
+	}
+	return StaleSeriesPolicy{
+		EvalsToResolve:    cfg.MissingSeriesEvalsToResolve,
+		DurationToResolve: cfg.MissingSeriesDurationToResolve,
+	}
+}
+
 // String implements the Stringer interface
 func (amc AlertmanagersChoice) String() string {
 	return alertmanagersChoiceMap[amc]
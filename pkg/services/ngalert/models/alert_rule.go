@@ -12,7 +12,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/common/model"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -21,6 +23,8 @@ import (
 var (
 	// ErrAlertRuleNotFound is an error for an unknown alert rule.
 	ErrAlertRuleNotFound = fmt.Errorf("could not find alert rule")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
 	// ErrAlertRuleFailedGenerateUniqueUID is an error for failure to generate alert rule UID
 	ErrAlertRuleFailedGenerateUniqueUID = errors.New("failed to generate alert rule UID")
 	// ErrCannotEditNamespace is an error returned if the user does not have permissions to edit the namespace
@@ -36,6 +40,10 @@ var (
 	// ErrNoPanel is returned when the alert rule does not have a PanelID in its
 	// annotations.
 	ErrNoPanel = errors.New("no panel")
+
+	// ErrNoDataSourceQuery is returned when none of the queries of the alert rule
+	// is a data source query that can be previewed.
+	ErrNoDataSourceQuery = errors.New("no data source query")
 )
 
 // swagger:enum NoDataState
@@ -84,6 +92,10 @@ func ErrStateFromString(opt string) (ExecutionErrorState, error) {
 	}
 }
 
+// InheritedIntervalSeconds is the interval of rules whose group inherits the default evaluation interval of the organization.
+// The effective interval of such rules is resolved when they are scheduled.
+const InheritedIntervalSeconds int64 = -1
+
 const (
 	AlertingErrState ExecutionErrorState = "Alerting"
 	ErrorErrState    ExecutionErrorState = "Error"
@@ -95,6 +107,10 @@ const (
 	DashboardUIDAnnotation = "__dashboardUid__"
 	PanelIDAnnotation      = "__panelId__"
 
+	// QueryPreviewTokenAnnotation is the name of the annotation that contains the token of the image
+	// that previews the queries of the alert rule, for the contact points that attach it to notifications.
+	QueryPreviewTokenAnnotation = "__alertQueryPreviewToken__"
+
 	// GrafanaReservedLabelPrefix contains the prefix for Grafana reserved labels. These differ from "__<label>__" labels
 	// in that they are not meant for internal-use only and will be passed-through to AMs and available to users in the same
 	// way as manually configured labels.
@@ -141,14 +157,17 @@ var (
 		DashboardUIDAnnotation:              {},
 		PanelIDAnnotation:                   {},
 		alertingModels.ImageTokenAnnotation: {},
+		QueryPreviewTokenAnnotation:         {},
 	}
 )
 
 // AlertRuleGroup is the base model for a rule group in unified alerting.
 type AlertRuleGroup struct {
-	Title      string
-	FolderUID  string
-	Interval   int64
+	Title     string
+	FolderUID string
+	Interval  int64
+	// Labels are the labels of the group. They are added to the labels of each rule that does not define them.
+	Labels     map[string]string
 	Provenance Provenance
 	Rules      []AlertRule
 }
@@ -160,17 +179,22 @@ type AlertRuleGroupWithFolderTitle struct {
 	FolderTitle string
 }
 
+// NewAlertRuleGroupWithFolderTitle creates a group of rules with the interval of the first rule. Callers resolve
+// the interval of rules that inherit the interval of the organization first, because the group would otherwise use the sentinel.
 func NewAlertRuleGroupWithFolderTitle(groupKey AlertRuleGroupKey, rules []AlertRule, folderTitle string) AlertRuleGroupWithFolderTitle {
 	SortAlertRulesByGroupIndex(rules)
 	var interval int64
+	var labels map[string]string
 	if len(rules) > 0 {
 		interval = rules[0].IntervalSeconds
+		labels = rules[0].GroupLabels
 	}
 	var result = AlertRuleGroupWithFolderTitle{
 		AlertRuleGroup: &AlertRuleGroup{
 			Title:     groupKey.RuleGroup,
 			FolderUID: groupKey.NamespaceUID,
 			Interval:  interval,
+			Labels:    labels,
 			Rules:     rules,
 		},
 		FolderTitle: folderTitle,
@@ -221,6 +245,19 @@ type AlertRule struct {
 	Annotations map[string]string
 	Labels      map[string]string
 	IsPaused    bool
+	// FingerprintLabels are the names of the labels that identify the alert instances of the rule.
+	// If empty, all labels do.
+	FingerprintLabels []string
+	// PartialResults allows the expressions of the rule to be evaluated when some, but not all, of their inputs failed.
+	PartialResults bool
+	// MissingSeriesEvalsToResolve and MissingSeriesDurationToResolve are the stale series policy of the rule.
+	// If neither is set, the policy of the organization applies.
+	MissingSeriesEvalsToResolve    int64         `xorm:"missing_series_evals_to_resolve"`
+	MissingSeriesDurationToResolve time.Duration `xorm:"missing_series_duration_to_resolve"`
+	// GroupLabels are the labels of the rule group. Like the interval, they are the same for all rules of the group.
+	GroupLabels map[string]string
+	// UpdatedBy is the namespaced ID of the identity that last updated the rule through the ruler API, e.g. user:1.
+	UpdatedBy string `xorm:"updated_by"`
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -293,9 +330,24 @@ func WithoutInternalLabels() LabelOption {
 	}
 }
 
-// GetLabels returns the labels specified as part of the alert rule.
+// EffectiveLabels returns the labels of the rule group overridden by the labels of the alert rule.
+func (alertRule *AlertRule) EffectiveLabels() map[string]string {
+	if len(alertRule.GroupLabels) == 0 {
+		return alertRule.Labels
+	}
+	result := make(map[string]string, len(alertRule.GroupLabels)+len(alertRule.Labels))
+	for k, v := range alertRule.GroupLabels {
+		result[k] = v
+	}
+	for k, v := range alertRule.Labels {
+		result[k] = v
+	}
+	return result
+}
+
+// GetLabels returns the labels specified as part of the alert rule, including the labels of its group.
 func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
-	labels := alertRule.Labels
+	labels := alertRule.EffectiveLabels()
 
 	for _, opt := range opts {
 		opt(labels)
@@ -304,10 +356,37 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	return labels
 }
 
+// StaleSeriesPolicy returns the stale series policy of the rule. It is zero if the rule does not set one.
+func (alertRule *AlertRule) StaleSeriesPolicy() StaleSeriesPolicy {
+	return StaleSeriesPolicy{
+		EvalsToResolve:    alertRule.MissingSeriesEvalsToResolve,
+		DurationToResolve: alertRule.MissingSeriesDurationToResolve,
+	}
+}
+
+// GetDatasourceUIDs returns the UIDs of the data sources that the queries of the rule use. Expressions are not
+// included.
+func (alertRule *AlertRule) GetDatasourceUIDs() []string {
+	uids := make([]string, 0, len(alertRule.Data))
+	seen := make(map[string]struct{}, len(alertRule.Data))
+	for _, query := range alertRule.Data {
+		if query.QueryType == expr.DatasourceType || query.DatasourceUID == expr.DatasourceUID || query.DatasourceUID == expr.OldDatasourceUID {
+			continue
+		}
+		if _, ok := seen[query.DatasourceUID]; ok {
+			continue
+		}
+		seen[query.DatasourceUID] = struct{}{}
+		uids = append(uids, query.DatasourceUID)
+	}
+	return uids
+}
+
 func (alertRule *AlertRule) GetEvalCondition() Condition {
 	return Condition{
-		Condition: alertRule.Condition,
-		Data:      alertRule.Data,
+		Condition:      alertRule.Condition,
+		Data:           alertRule.Data,
+		PartialResults: alertRule.PartialResults,
 	}
 }
 
@@ -421,6 +500,11 @@ func (alertRule *AlertRule) GetKey() AlertRuleKey {
 	return AlertRuleKey{OrgID: alertRule.OrgID, UID: alertRule.UID}
 }
 
+// InheritsInterval returns true if the rule group inherits the default evaluation interval of the organization.
+func (alertRule *AlertRule) InheritsInterval() bool {
+	return alertRule.IntervalSeconds == InheritedIntervalSeconds
+}
+
 // GetGroupKey returns the identifier of a group the rule belongs to
 func (alertRule *AlertRule) GetGroupKey() AlertRuleGroupKey {
 	return AlertRuleGroupKey{OrgID: alertRule.OrgID, NamespaceUID: alertRule.NamespaceUID, RuleGroup: alertRule.RuleGroup}
@@ -449,8 +533,10 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		return fmt.Errorf("%w: title is empty", ErrAlertRuleFailedValidation)
 	}
 
-	if err := ValidateRuleGroupInterval(alertRule.IntervalSeconds, int64(cfg.BaseInterval.Seconds())); err != nil {
-		return err
+	if !alertRule.InheritsInterval() {
+		if err := ValidateRuleGroupInterval(alertRule.IntervalSeconds, int64(cfg.BaseInterval.Seconds())); err != nil {
+			return err
+		}
 	}
 
 	if alertRule.OrgID == 0 {
@@ -472,6 +558,21 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
 	}
+
+	seen := make(map[string]struct{}, len(alertRule.FingerprintLabels))
+	for _, name := range alertRule.FingerprintLabels {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("%w: fingerprint label %q is not a valid label name", ErrAlertRuleFailedValidation, name)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("%w: fingerprint label %q is declared more than once", ErrAlertRuleFailedValidation, name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	if err := alertRule.StaleSeriesPolicy().Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrAlertRuleFailedValidation, err)
+	}
 	return nil
 }
 
@@ -519,6 +620,19 @@ type AlertRuleVersion struct {
 	Annotations map[string]string
 	Labels      map[string]string
 	IsPaused    bool
+	// FingerprintLabels are the names of the labels that identify the alert instances of the rule.
+	// If empty, all labels do.
+	FingerprintLabels []string
+	// PartialResults allows the expressions of the rule to be evaluated when some, but not all, of their inputs failed.
+	PartialResults bool
+	// MissingSeriesEvalsToResolve and MissingSeriesDurationToResolve are the stale series policy of the rule.
+	// If neither is set, the policy of the organization applies.
+	MissingSeriesEvalsToResolve    int64         `xorm:"missing_series_evals_to_resolve"`
+	MissingSeriesDurationToResolve time.Duration `xorm:"missing_series_duration_to_resolve"`
+	// GroupLabels are the labels of the rule group. Like the interval, they are the same for all rules of the group.
+	GroupLabels map[string]string
+	// UpdatedBy is the namespaced ID of the identity that saved the version through the ruler API, e.g. user:1.
+	UpdatedBy string `xorm:"updated_by"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -594,6 +708,49 @@ type UpdateRule struct {
 	New      AlertRule
 }
 
+// BulkWriteRulesCommand is the command for creating and updating many alert rules in a single write.
+type BulkWriteRulesCommand struct {
+	Insert []AlertRule
+	Update []UpdateRule
+	// Atomic makes the write fail if any of the rules cannot be written.
+	// Otherwise, such rules are skipped and reported in the result.
+	Atomic bool
+}
+
+// BulkWriteRuleError is the error of an alert rule that was not written by a bulk write.
+type BulkWriteRuleError struct {
+	UID   string
+	Title string
+	Err   error
+}
+
+func (e BulkWriteRuleError) Error() string {
+	return fmt.Sprintf("alert rule %q (UID: %s): %s", e.Title, e.UID, e.Err)
+}
+
+func (e BulkWriteRuleError) Unwrap() error {
+	return e.Err
+}
+
+// BulkWriteRulesResult is the result of a bulk write of alert rules.
+type BulkWriteRulesResult struct {
+	// Inserted are the keys of the created rules in the same order as the input rules.
+	Inserted []AlertRuleKeyWithId
+	// Updated are the keys of the updated rules in the same order as the input rules.
+	Updated []AlertRuleKey
+	// Errors are the errors of the rules that were skipped.
+	Errors []BulkWriteRuleError
+}
+
+// Err returns the errors of the skipped rules joined together, or nil if all rules were written.
+func (r *BulkWriteRulesResult) Err() error {
+	errs := make([]error, 0, len(r.Errors))
+	for _, err := range r.Errors {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
 // Condition contains backend expressions and queries and the RefID
 // of the query or expression that will be evaluated.
 type Condition struct {
@@ -603,6 +760,9 @@ type Condition struct {
 
 	// Data is an array of data source queries and/or server side expressions.
 	Data []AlertQuery `json:"data"`
+
+	// PartialResults allows expressions to be evaluated when some, but not all, of their inputs failed.
+	PartialResults bool `json:"partialResults,omitempty"`
 }
 
 // IsValid checks the condition's validity.
@@ -716,6 +716,24 @@ func TestSortByGroupIndex(t *testing.T) {
 	})
 }
 
+func TestEffectiveLabels(t *testing.T) {
+	t.Run("should return the labels of the rule if the group has no labels", func(t *testing.T) {
+		rule := AlertRule{Labels: map[string]string{"severity": "critical"}}
+		require.Equal(t, map[string]string{"severity": "critical"}, rule.EffectiveLabels())
+	})
+
+	t.Run("should merge the labels of the group and the rule", func(t *testing.T) {
+		rule := AlertRule{
+			Labels:      map[string]string{"severity": "critical", "team": "dba"},
+			GroupLabels: map[string]string{"team": "sre", "service": "api"},
+		}
+		require.Equal(t, map[string]string{"severity": "critical", "team": "dba", "service": "api"}, rule.EffectiveLabels())
+		// the labels of the rule and the group are not modified
+		require.Equal(t, map[string]string{"team": "sre", "service": "api"}, rule.GroupLabels)
+		require.Equal(t, map[string]string{"severity": "critical", "team": "dba"}, rule.Labels)
+	})
+}
+
 func TestTimeRangeYAML(t *testing.T) {
 	yamlRaw := "from: 600\nto: 0\n"
 	var rtr RelativeTimeRange
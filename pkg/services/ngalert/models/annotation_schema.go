@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// RunbookURLAnnotation is the annotation that links an alert rule to its runbook.
+// Its value must be a URL, whether or not the schema of the organization describes it.
+const RunbookURLAnnotation = "runbook_url"
+
+// AnnotationRequirement describes the constraints the annotation of alert rules must satisfy.
+type AnnotationRequirement struct {
+	// Name of the annotation.
+	Name string `json:"name"`
+	// Required is true if every alert rule must define a non-empty annotation.
+	Required bool `json:"required,omitempty"`
+	// URL is true if the value of the annotation must be an absolute http or https URL.
+	// Values that contain templates cannot be validated before they are expanded and are accepted.
+	URL bool `json:"url,omitempty"`
+}
+
+// AnnotationSchema is a set of requirements alert rules of an organization must satisfy.
+type AnnotationSchema []AnnotationRequirement
+
+// AnnotationViolation describes an annotation of an alert rule that does not satisfy the schema.
+type AnnotationViolation struct {
+	RuleUID    string
+	RuleTitle  string
+	Annotation string
+	Reason     string
+}
+
+func (v AnnotationViolation) Error() string {
+	return fmt.Sprintf("annotation %s of rule %q %s", v.Annotation, v.RuleTitle, v.Reason)
+}
+
+// Validate checks the schema itself. Requirements must have a name and each annotation can be described only once.
+func (s AnnotationSchema) Validate() error {
+	seen := make(map[string]struct{}, len(s))
+	for idx, r := range s {
+		if strings.TrimSpace(r.Name) == "" {
+			return fmt.Errorf("annotation requirement at index %d has no name", idx)
+		}
+		if _, ok := seen[r.Name]; ok {
+			return fmt.Errorf("annotation %s is described more than once", r.Name)
+		}
+		seen[r.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Check returns the violations of the schema by the alert rule, sorted by annotation.
+// The runbook URL annotation is always validated as a URL, even if the schema is empty.
+func (s AnnotationSchema) Check(rule *AlertRule) []AnnotationViolation {
+	var result []AnnotationViolation
+	violation := func(annotation, reason string) {
+		result = append(result, AnnotationViolation{
+			RuleUID:    rule.UID,
+			RuleTitle:  rule.Title,
+			Annotation: annotation,
+			Reason:     reason,
+		})
+	}
+	for _, r := range s {
+		value := strings.TrimSpace(rule.Annotations[r.Name])
+		if value == "" {
+			if r.Required {
+				violation(r.Name, "is required")
+			}
+			continue
+		}
+		if r.URL || r.Name == RunbookURLAnnotation {
+			if err := validateAnnotationURL(value); err != nil {
+				violation(r.Name, err.Error())
+			}
+		}
+	}
+	if !s.describes(RunbookURLAnnotation) {
+		if value := strings.TrimSpace(rule.Annotations[RunbookURLAnnotation]); value != "" {
+			if err := validateAnnotationURL(value); err != nil {
+				violation(RunbookURLAnnotation, err.Error())
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Annotation < result[j].Annotation
+	})
+	return result
+}
+
+func (s AnnotationSchema) describes(name string) bool {
+	for _, r := range s {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAnnotationURL(value string) error {
+	if strings.Contains(value, "{{") {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("is not a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("must be an absolute URL with http or https scheme")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("must be an absolute URL with a host")
+	}
+	return nil
+}
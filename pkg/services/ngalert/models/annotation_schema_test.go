@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationSchema_Validate(t *testing.T) {
+	require.NoError(t, AnnotationSchema{{Name: RunbookURLAnnotation, Required: true, URL: true}}.Validate())
+	require.Error(t, AnnotationSchema{{Name: " "}}.Validate())
+	require.Error(t, AnnotationSchema{{Name: "summary"}, {Name: "summary", Required: true}}.Validate())
+}
+
+func TestAnnotationSchema_Check(t *testing.T) {
+	schema := AnnotationSchema{
+		{Name: RunbookURLAnnotation, Required: true, URL: true},
+		{Name: "dashboard_url", URL: true},
+		{Name: "summary", Required: true},
+	}
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]string
+	}{
+		{
+			name: "valid annotations",
+			annotations: map[string]string{
+				RunbookURLAnnotation: "https://runbooks.example.com/high-latency",
+				"summary":            "latency is high",
+			},
+		},
+		{
+			name: "templated URL is accepted",
+			annotations: map[string]string{
+				RunbookURLAnnotation: "https://runbooks.example.com/{{ $labels.service }}",
+				"dashboard_url":      "{{ $labels.dashboard }}",
+				"summary":            "latency is high",
+			},
+		},
+		{
+			name:        "missing required annotations",
+			annotations: map[string]string{"summary": " "},
+			expected: map[string]string{
+				RunbookURLAnnotation: "is required",
+				"summary":            "is required",
+			},
+		},
+		{
+			name: "malformed URLs",
+			annotations: map[string]string{
+				RunbookURLAnnotation: "runbooks/high-latency",
+				"dashboard_url":      "ftp://dashboards.example.com",
+				"summary":            "latency is high",
+			},
+			expected: map[string]string{
+				RunbookURLAnnotation: "must be an absolute URL with http or https scheme",
+				"dashboard_url":      "must be an absolute URL with http or https scheme",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := AlertRuleGen(WithAnnotations(tc.annotations))()
+			violations := schema.Check(rule)
+			actual := make(map[string]string, len(violations))
+			for _, v := range violations {
+				require.Equal(t, rule.UID, v.RuleUID)
+				require.Equal(t, rule.Title, v.RuleTitle)
+				actual[v.Annotation] = v.Reason
+			}
+			if len(tc.expected) == 0 {
+				require.Empty(t, actual)
+				return
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAnnotationSchema_CheckRunbookURL(t *testing.T) {
+	valid := AlertRuleGen(WithAnnotations(map[string]string{RunbookURLAnnotation: "https://runbooks.example.com"}))()
+	invalid := AlertRuleGen(WithAnnotations(map[string]string{RunbookURLAnnotation: "runbooks/high-latency"}))()
+
+	for name, schema := range map[string]AnnotationSchema{
+		"empty schema":              nil,
+		"schema without runbook":    {{Name: "summary"}},
+		"runbook not marked as URL": {{Name: RunbookURLAnnotation}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Empty(t, schema.Check(valid))
+			violations := schema.Check(invalid)
+			require.Len(t, violations, 1)
+			require.Equal(t, RunbookURLAnnotation, violations[0].Annotation)
+		})
+	}
+}
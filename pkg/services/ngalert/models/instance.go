@@ -17,6 +17,20 @@ type AlertInstance struct {
 	ResultFingerprint string
 }
 
+// AlertInstanceSnapshot is a compressed snapshot of the alert instances of all organizations that is used to restore
+// the state of the alert instances at startup.
+type AlertInstanceSnapshot struct {
+	ID int64 `xorm:"pk autoincr 'id'"`
+	// Node is the instance name of the Grafana server that saved the snapshot.
+	Node    string
+	Created time.Time
+	// Instances is the number of alert instances in the snapshot.
+	Instances int64
+	// Size is the size of the compressed data in bytes.
+	Size int64
+	Data []byte
+}
+
 type AlertInstanceKey struct {
 	RuleOrgID  int64  `xorm:"rule_org_id"`
 	RuleUID    string `xorm:"rule_uid"`
@@ -53,6 +67,8 @@ func (i InstanceStateType) IsValid() bool {
 type ListAlertInstancesQuery struct {
 	RuleUID   string
 	RuleOrgID int64 `json:"-"`
+	// EvaluatedAfter, if set, limits the result to the alert instances that were evaluated after it.
+	EvaluatedAfter time.Time `json:"-"`
 }
 
 // ValidateAlertInstance validates that the alert instance contains an alert rule id,
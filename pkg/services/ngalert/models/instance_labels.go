@@ -47,6 +47,22 @@ func (il *InstanceLabels) StringKey() (string, error) {
 	return string(b), nil
 }
 
+// FingerprintKey returns the key of the labels restricted to the given names,
+// so that instances that differ only by other labels share the same key.
+// If no names are given, it returns the same key as StringKey.
+func (il *InstanceLabels) FingerprintKey(names []string) (string, error) {
+	if len(names) == 0 {
+		return il.StringKey()
+	}
+	subset := make(InstanceLabels, len(names))
+	for _, name := range names {
+		if v, ok := (*il)[name]; ok {
+			subset[name] = v
+		}
+	}
+	return subset.StringKey()
+}
+
 // StringAndHash returns a the json representation of the labels as tuples
 // sorted by key. It also returns the a hash of that representation.
 func (il *InstanceLabels) StringAndHash() (string, string, error) {
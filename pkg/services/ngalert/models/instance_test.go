@@ -103,3 +103,21 @@ func TestValidateAlertInstance(t *testing.T) {
 		})
 	}
 }
+
+func TestInstanceLabels_FingerprintKey(t *testing.T) {
+	a := InstanceLabels{"namespace": "default", "pod": "api-1"}
+	b := InstanceLabels{"namespace": "default", "pod": "api-2"}
+
+	keyA, err := a.FingerprintKey(nil)
+	require.NoError(t, err)
+	expected, err := a.StringKey()
+	require.NoError(t, err)
+	require.Equal(t, expected, keyA)
+
+	keyA, err = a.FingerprintKey([]string{"namespace", "missing"})
+	require.NoError(t, err)
+	keyB, err := b.FingerprintKey([]string{"namespace", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, keyA, keyB)
+	require.Equal(t, `[["namespace","default"]]`, keyA)
+}
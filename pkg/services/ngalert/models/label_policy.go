@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LabelPolicy is a set of requirements the labels of alert rules must satisfy.
+// A policy applies either to all rules of the organization or, if FolderUID is set, to the rules of a folder.
+type LabelPolicy struct {
+	ID        int64             `xorm:"pk autoincr 'id'"`
+	OrgID     int64             `xorm:"org_id"`
+	FolderUID string            `xorm:"folder_uid"`
+	Labels    LabelRequirements `xorm:"labels"`
+	Updated   time.Time         `xorm:"updated"`
+}
+
+// LabelRequirement describes the constraints on a single label of alert rules.
+type LabelRequirement struct {
+	// Name of the label.
+	Name string `json:"name"`
+	// Required is true if every alert rule must define the label.
+	Required bool `json:"required,omitempty"`
+	// Forbidden is true if alert rules must not define the label.
+	Forbidden bool `json:"forbidden,omitempty"`
+	// Pattern is a regular expression the whole value of the label must match, if the label is defined.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type LabelRequirements []LabelRequirement
+
+// LabelViolation describes a label of an alert rule that does not satisfy the label policy.
+type LabelViolation struct {
+	RuleUID      string
+	RuleTitle    string
+	NamespaceUID string
+	RuleGroup    string
+	Label        string
+	Reason       string
+}
+
+func (v LabelViolation) Error() string {
+	return fmt.Sprintf("label %s of rule %q %s", v.Label, v.RuleTitle, v.Reason)
+}
+
+// Validate checks the requirements themselves.
+func (r LabelRequirements) Validate() error {
+	seen := make(map[string]struct{}, len(r))
+	for idx, req := range r {
+		if strings.TrimSpace(req.Name) == "" {
+			return fmt.Errorf("label requirement at index %d has no name", idx)
+		}
+		if _, ok := seen[req.Name]; ok {
+			return fmt.Errorf("label %s is described more than once", req.Name)
+		}
+		seen[req.Name] = struct{}{}
+		if req.Required && req.Forbidden {
+			return fmt.Errorf("label %s cannot be both required and forbidden", req.Name)
+		}
+		if req.Pattern != "" {
+			if req.Forbidden {
+				return fmt.Errorf("label %s is forbidden and cannot have a pattern", req.Name)
+			}
+			if _, err := compileLabelPattern(req.Pattern); err != nil {
+				return fmt.Errorf("invalid pattern of label %s: %w", req.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns the violations of the requirements by the alert rule, sorted by label.
+func (r LabelRequirements) Check(rule *AlertRule) []LabelViolation {
+	var result []LabelViolation
+	violation := func(label, reason string) {
+		result = append(result, LabelViolation{
+			RuleUID:      rule.UID,
+			RuleTitle:    rule.Title,
+			NamespaceUID: rule.NamespaceUID,
+			RuleGroup:    rule.RuleGroup,
+			Label:        label,
+			Reason:       reason,
+		})
+	}
+	labels := rule.EffectiveLabels()
+	for _, req := range r {
+		value, ok := labels[req.Name]
+		switch {
+		case !ok:
+			if req.Required {
+				violation(req.Name, "is required")
+			}
+		case req.Forbidden:
+			violation(req.Name, "is forbidden")
+		case req.Pattern != "":
+			re, err := compileLabelPattern(req.Pattern)
+			if err != nil {
+				violation(req.Name, fmt.Sprintf("cannot be validated: %s", err))
+				continue
+			}
+			if !re.MatchString(value) {
+				violation(req.Name, fmt.Sprintf("value %q does not match pattern %q", value, req.Pattern))
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Label < result[j].Label
+	})
+	return result
+}
+
+// compileLabelPattern compiles the pattern anchored, so that it must match the whole value.
+func compileLabelPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// LabelPolicies are the label policies of an organization.
+type LabelPolicies []*LabelPolicy
+
+// RequirementsFor returns the requirements that apply to the rules in the folder.
+// Requirements of the folder policy take precedence over the requirements of the organization policy for the same label.
+func (p LabelPolicies) RequirementsFor(folderUID string) LabelRequirements {
+	var orgPolicy, folderPolicy *LabelPolicy
+	for _, policy := range p {
+		switch policy.FolderUID {
+		case "":
+			orgPolicy = policy
+		case folderUID:
+			folderPolicy = policy
+		}
+	}
+	if folderPolicy == nil {
+		if orgPolicy == nil {
+			return nil
+		}
+		return orgPolicy.Labels
+	}
+	if orgPolicy == nil {
+		return folderPolicy.Labels
+	}
+
+	result := make(LabelRequirements, 0, len(orgPolicy.Labels)+len(folderPolicy.Labels))
+	overridden := make(map[string]struct{}, len(folderPolicy.Labels))
+	for _, req := range folderPolicy.Labels {
+		overridden[req.Name] = struct{}{}
+	}
+	for _, req := range orgPolicy.Labels {
+		if _, ok := overridden[req.Name]; !ok {
+			result = append(result, req)
+		}
+	}
+	return append(result, folderPolicy.Labels...)
+}
+
+// Check returns the violations of the policies by the alert rules.
+func (p LabelPolicies) Check(rules ...*AlertRule) []LabelViolation {
+	if len(p) == 0 {
+		return nil
+	}
+	var result []LabelViolation
+	byFolder := make(map[string]LabelRequirements)
+	for _, rule := range rules {
+		reqs, ok := byFolder[rule.NamespaceUID]
+		if !ok {
+			reqs = p.RequirementsFor(rule.NamespaceUID)
+			byFolder[rule.NamespaceUID] = reqs
+		}
+		result = append(result, reqs.Check(rule)...)
+	}
+	return result
+}
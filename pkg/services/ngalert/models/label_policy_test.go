@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelRequirements_Validate(t *testing.T) {
+	require.NoError(t, LabelRequirements{{Name: "team", Required: true, Pattern: "[a-z]+"}, {Name: "env", Forbidden: true}}.Validate())
+	require.Error(t, LabelRequirements{{Name: ""}}.Validate())
+	require.Error(t, LabelRequirements{{Name: "team"}, {Name: "team"}}.Validate())
+	require.Error(t, LabelRequirements{{Name: "team", Required: true, Forbidden: true}}.Validate())
+	require.Error(t, LabelRequirements{{Name: "team", Forbidden: true, Pattern: "a"}}.Validate())
+	require.Error(t, LabelRequirements{{Name: "team", Pattern: "("}}.Validate())
+}
+
+func TestLabelRequirements_Check(t *testing.T) {
+	reqs := LabelRequirements{
+		{Name: "team", Required: true, Pattern: "[a-z]+"},
+		{Name: "severity", Pattern: "critical|warning"},
+		{Name: "__internal", Forbidden: true},
+	}
+
+	rule := AlertRuleGen(WithLabels(map[string]string{"team": "sre", "severity": "warning"}))()
+	require.Empty(t, reqs.Check(rule))
+
+	rule = AlertRuleGen(WithLabels(map[string]string{"team": "SRE-1", "severity": "critical-ish", "__internal": "x"}))()
+	violations := reqs.Check(rule)
+	require.Len(t, violations, 3)
+	require.Equal(t, "__internal", violations[0].Label)
+	require.Equal(t, "is forbidden", violations[0].Reason)
+	require.Equal(t, "severity", violations[1].Label)
+	require.Equal(t, "team", violations[2].Label)
+	for _, v := range violations {
+		require.Equal(t, rule.UID, v.RuleUID)
+		require.Equal(t, rule.NamespaceUID, v.NamespaceUID)
+		require.Equal(t, rule.RuleGroup, v.RuleGroup)
+	}
+
+	rule = AlertRuleGen(WithLabels(nil))()
+	violations = reqs.Check(rule)
+	require.Len(t, violations, 1)
+	require.Equal(t, "is required", violations[0].Reason)
+}
+
+func TestLabelPolicies_RequirementsFor(t *testing.T) {
+	policies := LabelPolicies{
+		{FolderUID: "", Labels: LabelRequirements{{Name: "team", Required: true}, {Name: "env", Required: true}}},
+		{FolderUID: "sandbox", Labels: LabelRequirements{{Name: "env", Forbidden: true}}},
+	}
+
+	require.Equal(t, policies[0].Labels, policies.RequirementsFor("other"))
+	require.Equal(t, LabelRequirements{{Name: "team", Required: true}, {Name: "env", Forbidden: true}}, policies.RequirementsFor("sandbox"))
+	require.Equal(t, policies[1].Labels, policies[1:].RequirementsFor("sandbox"))
+	require.Nil(t, policies[1:].RequirementsFor("other"))
+
+	sandboxRule := AlertRuleGen(WithLabels(map[string]string{"team": "a", "env": "dev"}))()
+	sandboxRule.NamespaceUID = "sandbox"
+	otherRule := AlertRuleGen(WithLabels(map[string]string{"env": "dev"}))()
+	otherRule.NamespaceUID = "other"
+
+	violations := policies.Check(sandboxRule, otherRule)
+	require.Len(t, violations, 2)
+	require.Equal(t, sandboxRule.UID, violations[0].RuleUID)
+	require.Equal(t, "env", violations[0].Label)
+	require.Equal(t, otherRule.UID, violations[1].RuleUID)
+	require.Equal(t, "team", violations[1].Label)
+}
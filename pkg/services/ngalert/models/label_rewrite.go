@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LabelRewriteAction is what a label rewrite rule does to the labels it matches.
+type LabelRewriteAction string
+
+const (
+	// LabelRewriteActionDrop removes the matching labels.
+	LabelRewriteActionDrop LabelRewriteAction = "drop"
+	// LabelRewriteActionReplace replaces the value of the matching labels. Labels whose new value is empty are removed.
+	LabelRewriteActionReplace LabelRewriteAction = "replace"
+)
+
+// LabelRewriteRule rewrites the labels that the data sources return for alert instances, before the instances are
+// routed and recorded in the state history. Labels of alert rules and reserved labels are not rewritten.
+type LabelRewriteRule struct {
+	// Label is a regular expression the whole name of the labels must match.
+	Label string `json:"label"`
+	// Action is the rewrite applied to the matching labels.
+	Action LabelRewriteAction `json:"action"`
+	// Regex is a regular expression the whole value of the labels must match. Defaults to any value.
+	Regex string `json:"regex,omitempty"`
+	// Replacement is the new value of the labels if the action is replace. It can refer to the capture groups of Regex, such as $1.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// LabelRewriteRules are the label rewrite rules of an organization, applied in order.
+type LabelRewriteRules []LabelRewriteRule
+
+// Validate checks that the rules have a valid action and valid regular expressions.
+func (r LabelRewriteRules) Validate() error {
+	_, err := r.Compile()
+	return err
+}
+
+// Compile returns a LabelRewriter that applies the rules.
+func (r LabelRewriteRules) Compile() (*LabelRewriter, error) {
+	result := &LabelRewriter{rules: make([]compiledLabelRewriteRule, 0, len(r))}
+	for idx, rule := range r {
+		if rule.Label == "" {
+			return nil, fmt.Errorf("label rewrite rule at index %d has no label", idx)
+		}
+		if rule.Action != LabelRewriteActionDrop && rule.Action != LabelRewriteActionReplace {
+			return nil, fmt.Errorf("label rewrite rule at index %d has invalid action %q, must be %q or %q", idx, rule.Action, LabelRewriteActionDrop, LabelRewriteActionReplace)
+		}
+		label, err := regexp.Compile("^(?:" + rule.Label + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("label rewrite rule at index %d has invalid label: %w", idx, err)
+		}
+		pattern := rule.Regex
+		if pattern == "" {
+			pattern = "(.*)"
+		}
+		value, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("label rewrite rule at index %d has invalid regex: %w", idx, err)
+		}
+		result.rules = append(result.rules, compiledLabelRewriteRule{
+			label:       label,
+			value:       value,
+			action:      rule.Action,
+			replacement: rule.Replacement,
+		})
+	}
+	return result, nil
+}
+
+type compiledLabelRewriteRule struct {
+	label       *regexp.Regexp
+	value       *regexp.Regexp
+	action      LabelRewriteAction
+	replacement string
+}
+
+// LabelRewriter applies compiled label rewrite rules. It is safe for concurrent use.
+type LabelRewriter struct {
+	rules []compiledLabelRewriteRule
+}
+
+// IsEmpty returns true if the rewriter has no rules, and therefore never changes labels.
+func (r *LabelRewriter) IsEmpty() bool {
+	return r == nil || len(r.rules) == 0
+}
+
+// Rewrite returns the labels rewritten by the rules. The given labels are not modified.
+// If no rule matches, the given labels are returned.
+func (r *LabelRewriter) Rewrite(labels map[string]string) map[string]string {
+	if r.IsEmpty() || len(labels) == 0 {
+		return labels
+	}
+	var result map[string]string
+	for _, rule := range r.rules {
+		current := labels
+		if result != nil {
+			current = result
+		}
+		for name, value := range current {
+			if !rule.label.MatchString(name) {
+				continue
+			}
+			match := rule.value.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+			if result == nil {
+				result = make(map[string]string, len(labels))
+				for k, v := range labels {
+					result[k] = v
+				}
+			}
+			if rule.action == LabelRewriteActionDrop {
+				delete(result, name)
+				continue
+			}
+			replaced := string(rule.value.ExpandString(nil, rule.replacement, value, match))
+			if replaced == "" {
+				delete(result, name)
+			} else {
+				result[name] = replaced
+			}
+		}
+	}
+	if result == nil {
+		return labels
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelRewriteRulesValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rules LabelRewriteRules
+		err   string
+	}{{
+		name:  "valid rules",
+		rules: LabelRewriteRules{{Label: "pod", Action: LabelRewriteActionDrop}, {Label: "instance", Action: LabelRewriteActionReplace, Regex: "(.*):.*", Replacement: "$1"}},
+	}, {
+		name:  "rule without label",
+		rules: LabelRewriteRules{{Action: LabelRewriteActionDrop}},
+		err:   "label rewrite rule at index 0 has no label",
+	}, {
+		name:  "rule with invalid action",
+		rules: LabelRewriteRules{{Label: "pod", Action: "keep"}},
+		err:   `label rewrite rule at index 0 has invalid action "keep", must be "drop" or "replace"`,
+	}, {
+		name:  "rule with invalid label",
+		rules: LabelRewriteRules{{Label: "(", Action: LabelRewriteActionDrop}},
+		err:   "label rewrite rule at index 0 has invalid label",
+	}, {
+		name:  "rule with invalid regex",
+		rules: LabelRewriteRules{{Label: "pod", Action: LabelRewriteActionDrop, Regex: "("}},
+		err:   "label rewrite rule at index 0 has invalid regex",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rules.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLabelRewriter(t *testing.T) {
+	rewriter, err := LabelRewriteRules{
+		{Label: "pod|container_id", Action: LabelRewriteActionDrop},
+		{Label: "instance", Action: LabelRewriteActionReplace, Regex: "(.*):[0-9]+", Replacement: "$1"},
+		{Label: "token", Action: LabelRewriteActionReplace, Replacement: ""},
+		{Label: "env", Action: LabelRewriteActionDrop, Regex: "dev"},
+	}.Compile()
+	require.NoError(t, err)
+
+	t.Run("should rewrite matching labels", func(t *testing.T) {
+		labels := map[string]string{"pod": "a-1", "container_id": "123", "instance": "host:9090", "token": "secret", "env": "dev", "job": "api"}
+		require.Equal(t, map[string]string{"instance": "host", "job": "api"}, rewriter.Rewrite(labels))
+		require.Len(t, labels, 6, "the given labels must not be modified")
+	})
+
+	t.Run("should keep labels whose value does not match", func(t *testing.T) {
+		labels := map[string]string{"instance": "host", "env": "prod", "podname": "a"}
+		require.Equal(t, labels, rewriter.Rewrite(labels))
+	})
+
+	t.Run("should not change labels without rules", func(t *testing.T) {
+		empty, err := LabelRewriteRules{}.Compile()
+		require.NoError(t, err)
+		require.True(t, empty.IsEmpty())
+		labels := map[string]string{"pod": "a"}
+		require.Equal(t, labels, empty.Rewrite(labels))
+	})
+}
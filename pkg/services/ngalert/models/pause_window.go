@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// EvaluationPauseWindow is a period of time during which the alert rules of an organization or, if FolderUID is set,
+// of a folder are not evaluated. Unlike silences, paused rules produce neither notifications nor state history.
+type EvaluationPauseWindow struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	OrgID     int64     `xorm:"org_id"`
+	FolderUID string    `xorm:"folder_uid"`
+	StartsAt  time.Time `xorm:"starts_at"`
+	EndsAt    time.Time `xorm:"ends_at"`
+	Comment   string    `xorm:"comment"`
+	CreatedBy string    `xorm:"created_by"`
+	Created   time.Time `xorm:"created"`
+}
+
+// Validate returns an error if the window does not end after it starts.
+func (w *EvaluationPauseWindow) Validate() error {
+	if w.StartsAt.IsZero() || w.EndsAt.IsZero() {
+		return errors.New("start and end of the pause window are required")
+	}
+	if !w.EndsAt.After(w.StartsAt) {
+		return errors.New("pause window must end after it starts")
+	}
+	return nil
+}
+
+// ActiveAt returns true if the window covers the given time.
+func (w *EvaluationPauseWindow) ActiveAt(t time.Time) bool {
+	return !t.Before(w.StartsAt) && t.Before(w.EndsAt)
+}
+
+// AppliesTo returns true if the rule belongs to the organization or folder of the window.
+func (w *EvaluationPauseWindow) AppliesTo(rule *AlertRule) bool {
+	return w.OrgID == rule.OrgID && (w.FolderUID == "" || w.FolderUID == rule.NamespaceUID)
+}
+
+type EvaluationPauseWindows []*EvaluationPauseWindow
+
+// Pauses returns true if any of the windows pauses the evaluation of the rule at the given time.
+func (ws EvaluationPauseWindows) Pauses(rule *AlertRule, t time.Time) bool {
+	for _, w := range ws {
+		if w.AppliesTo(rule) && w.ActiveAt(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluationPauseWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	t.Run("Validate", func(t *testing.T) {
+		require.NoError(t, (&EvaluationPauseWindow{StartsAt: start, EndsAt: end}).Validate())
+		require.Error(t, (&EvaluationPauseWindow{EndsAt: end}).Validate())
+		require.Error(t, (&EvaluationPauseWindow{StartsAt: start, EndsAt: start}).Validate())
+		require.Error(t, (&EvaluationPauseWindow{StartsAt: end, EndsAt: start}).Validate())
+	})
+
+	t.Run("ActiveAt", func(t *testing.T) {
+		w := &EvaluationPauseWindow{StartsAt: start, EndsAt: end}
+		require.False(t, w.ActiveAt(start.Add(-time.Second)))
+		require.True(t, w.ActiveAt(start))
+		require.True(t, w.ActiveAt(end.Add(-time.Second)))
+		require.False(t, w.ActiveAt(end))
+	})
+
+	t.Run("Pauses", func(t *testing.T) {
+		rule := &AlertRule{OrgID: 1, NamespaceUID: "folder"}
+		windows := EvaluationPauseWindows{
+			{OrgID: 1, FolderUID: "other", StartsAt: start, EndsAt: end},
+			{OrgID: 2, StartsAt: start, EndsAt: end},
+		}
+		require.False(t, windows.Pauses(rule, start))
+
+		windows = append(windows, &EvaluationPauseWindow{OrgID: 1, FolderUID: "folder", StartsAt: start, EndsAt: end})
+		require.True(t, windows.Pauses(rule, start))
+		require.False(t, windows.Pauses(rule, end))
+
+		orgWindow := EvaluationPauseWindows{{OrgID: 1, StartsAt: start, EndsAt: end}}
+		require.True(t, orgWindow.Pauses(rule, start))
+	})
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Reasons why the scheduler does not evaluate an alert rule.
+const (
+	SchedulingReasonNotScheduled    = "the rule is not scheduled by this Grafana instance"
+	SchedulingReasonPaused          = "the rule is paused"
+	SchedulingReasonInvalidInterval = "the evaluation interval of the rule is not a multiple of the base interval of the scheduler"
+	SchedulingReasonPauseWindow     = "the next evaluation is skipped because of an evaluation pause window"
+)
+
+// RuleSchedulingStatus is the view of the scheduler on an alert rule.
+type RuleSchedulingStatus struct {
+	// Scheduled is true if the next evaluation of the rule is planned.
+	Scheduled bool
+	// Reason explains why the rule is not scheduled or its next evaluation is skipped.
+	Reason string
+	// IntervalSeconds is the interval the rule is evaluated at, after the inherited and minimum intervals are applied.
+	IntervalSeconds int64
+	// NextEvaluation is the tick of the next planned evaluation. It is zero if the rule is not scheduled.
+	NextEvaluation time.Time
+	// LastEvaluation is nil if the rule was not evaluated since Grafana started.
+	LastEvaluation *RuleEvaluationStatus
+}
+
+// RuleEvaluationStatus is the outcome of the last evaluation of an alert rule.
+type RuleEvaluationStatus struct {
+	// ScheduledAt is the tick the evaluation was scheduled at.
+	ScheduledAt time.Time
+	// EvaluatedAt is the time the evaluation started.
+	EvaluatedAt time.Time
+	Duration    time.Duration
+	// Attempt is the number of the attempt, evaluations that fail with retryable errors are retried.
+	Attempt int64
+	// Error is empty if the evaluation succeeded.
+	Error string
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SilenceMetadata is what Grafana records about a silence of its Alertmanager in addition to the silence itself.
+type SilenceMetadata struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	SilenceID string `xorm:"silence_id"`
+	// CreatedBy is the namespaced ID of the identity that created the silence, e.g. user:1.
+	CreatedBy      string `xorm:"created_by"`
+	CreatedByLogin string `xorm:"created_by_login"`
+	// Reference is an optional link to the reason of the silence, e.g. the key of a ticket or an incident.
+	Reference string    `xorm:"reference"`
+	Created   time.Time `xorm:"created"`
+	// ExpiryNotifiedEndsAt is the end of the silence at the time its expiry was notified.
+	// It is compared to the current end of the silence, so that the expiry of an extended silence is notified again.
+	ExpiryNotifiedEndsAt *time.Time `xorm:"expiry_notified_ends_at"`
+}
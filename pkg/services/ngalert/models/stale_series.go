@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultMissingSeriesEvalsToResolve is the number of evaluations in a row a series must be missing from the results
+// of a rule before its alert instance is resolved, if neither the rule nor its organization sets a stale series policy.
+const DefaultMissingSeriesEvalsToResolve = 2
+
+// StaleSeriesPolicy controls when the alert instance of a series that is missing from the results of a rule
+// becomes stale, which resolves it. At most one of the fields is set. The zero value sets no policy.
+type StaleSeriesPolicy struct {
+	// EvalsToResolve is the number of evaluations in a row the series must be missing from.
+	EvalsToResolve int64
+	// DurationToResolve is how long the series must be missing for. The instance is resolved by the first
+	// evaluation after it, which suits sparse series that are missing from most evaluations.
+	DurationToResolve time.Duration
+}
+
+func (p StaleSeriesPolicy) IsZero() bool {
+	return p.EvalsToResolve == 0 && p.DurationToResolve == 0
+}
+
+// Validate checks that the fields are not negative and that at most one of them is set.
+func (p StaleSeriesPolicy) Validate() error {
+	if p.EvalsToResolve < 0 {
+		return errors.New("the number of missed evaluations to resolve a series cannot be negative")
+	}
+	if p.DurationToResolve < 0 {
+		return errors.New("the duration to resolve a missing series cannot be negative")
+	}
+	if p.EvalsToResolve > 0 && p.DurationToResolve > 0 {
+		return errors.New("only one of the number of missed evaluations and the duration to resolve a missing series can be set")
+	}
+	return nil
+}
+
+// IsStale returns true if a series that was last part of the results of the rule at lastEval is stale
+// at evaluatedAt, given the evaluation interval of the rule.
+func (p StaleSeriesPolicy) IsStale(evaluatedAt time.Time, lastEval time.Time, intervalSeconds int64) bool {
+	if p.DurationToResolve > 0 {
+		return !lastEval.Add(p.DurationToResolve).After(evaluatedAt)
+	}
+	evals := p.EvalsToResolve
+	if evals <= 0 {
+		evals = DefaultMissingSeriesEvalsToResolve
+	}
+	return !lastEval.Add(time.Duration(evals) * time.Duration(intervalSeconds) * time.Second).After(evaluatedAt)
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleSeriesPolicy_IsStale(t *testing.T) {
+	now := time.Now()
+	var intervalSeconds int64 = 60
+	interval := time.Minute
+
+	testCases := []struct {
+		name     string
+		policy   StaleSeriesPolicy
+		missing  time.Duration
+		expected bool
+	}{
+		{name: "default policy before 2 intervals", policy: StaleSeriesPolicy{}, missing: 2*interval - time.Second, expected: false},
+		{name: "default policy after 2 intervals", policy: StaleSeriesPolicy{}, missing: 2 * interval, expected: true},
+		{name: "missed evaluations before the limit", policy: StaleSeriesPolicy{EvalsToResolve: 5}, missing: 4 * interval, expected: false},
+		{name: "missed evaluations at the limit", policy: StaleSeriesPolicy{EvalsToResolve: 5}, missing: 5 * interval, expected: true},
+		{name: "duration before the limit", policy: StaleSeriesPolicy{DurationToResolve: time.Hour}, missing: 59 * interval, expected: false},
+		{name: "duration at the limit", policy: StaleSeriesPolicy{DurationToResolve: time.Hour}, missing: time.Hour, expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.policy.IsStale(now, now.Add(-tc.missing), intervalSeconds))
+		})
+	}
+}
+
+func TestStaleSeriesPolicy_Validate(t *testing.T) {
+	require.NoError(t, StaleSeriesPolicy{}.Validate())
+	require.NoError(t, StaleSeriesPolicy{EvalsToResolve: 3}.Validate())
+	require.NoError(t, StaleSeriesPolicy{DurationToResolve: time.Hour}.Validate())
+	require.Error(t, StaleSeriesPolicy{EvalsToResolve: -1}.Validate())
+	require.Error(t, StaleSeriesPolicy{DurationToResolve: -time.Hour}.Validate())
+	require.Error(t, StaleSeriesPolicy{EvalsToResolve: 3, DurationToResolve: time.Hour}.Validate())
+}
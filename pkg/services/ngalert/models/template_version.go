@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// NotificationTemplateVersion is a version of a notification template, recorded when the template is saved through the API.
+type NotificationTemplateVersion struct {
+	ID      int64  `xorm:"pk autoincr 'id'"`
+	OrgID   int64  `xorm:"org_id"`
+	Name    string `xorm:"name"`
+	Version int64  `xorm:"version"`
+	// Template is the content of the template in this version.
+	Template string `xorm:"template"`
+	// CreatedBy is the namespaced ID of the identity that saved the version, e.g. user:1. It is empty if the
+	// version is the content the template had before it was first saved with versioning.
+	CreatedBy      string    `xorm:"created_by"`
+	CreatedByLogin string    `xorm:"created_by_login"`
+	Created        time.Time `xorm:"created"`
+}
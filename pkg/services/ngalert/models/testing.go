@@ -361,20 +361,25 @@ func GenerateGroupKey(orgID int64) AlertRuleGroupKey {
 // CopyRule creates a deep copy of AlertRule
 func CopyRule(r *AlertRule) *AlertRule {
 	result := AlertRule{
-		ID:              r.ID,
-		OrgID:           r.OrgID,
-		Title:           r.Title,
-		Condition:       r.Condition,
-		Updated:         r.Updated,
-		IntervalSeconds: r.IntervalSeconds,
-		Version:         r.Version,
-		UID:             r.UID,
-		NamespaceUID:    r.NamespaceUID,
-		RuleGroup:       r.RuleGroup,
-		RuleGroupIndex:  r.RuleGroupIndex,
-		NoDataState:     r.NoDataState,
-		ExecErrState:    r.ExecErrState,
-		For:             r.For,
+		ID:                             r.ID,
+		OrgID:                          r.OrgID,
+		Title:                          r.Title,
+		Condition:                      r.Condition,
+		Updated:                        r.Updated,
+		IntervalSeconds:                r.IntervalSeconds,
+		Version:                        r.Version,
+		UID:                            r.UID,
+		NamespaceUID:                   r.NamespaceUID,
+		RuleGroup:                      r.RuleGroup,
+		RuleGroupIndex:                 r.RuleGroupIndex,
+		NoDataState:                    r.NoDataState,
+		ExecErrState:                   r.ExecErrState,
+		For:                            r.For,
+		IsPaused:                       r.IsPaused,
+		PartialResults:                 r.PartialResults,
+		UpdatedBy:                      r.UpdatedBy,
+		MissingSeriesEvalsToResolve:    r.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: r.MissingSeriesDurationToResolve,
 	}
 
 	if r.DashboardUID != nil {
@@ -412,6 +417,17 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.FingerprintLabels != nil {
+		result.FingerprintLabels = append([]string(nil), r.FingerprintLabels...)
+	}
+
+	if r.GroupLabels != nil {
+		result.GroupLabels = make(map[string]string, len(r.GroupLabels))
+		for s, s2 := range r.GroupLabels {
+			result.GroupLabels[s] = s2
+		}
+	}
+
 	return &result
 }
 
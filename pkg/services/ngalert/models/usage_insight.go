@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// UsageInsightKind is the kind of alerting object whose usage is tracked.
+type UsageInsightKind string
+
+const (
+	UsageInsightKindAlertRule    UsageInsightKind = "alert_rule"
+	UsageInsightKindContactPoint UsageInsightKind = "contact_point"
+)
+
+// UsageInsight is how an alerting object was used since Grafana started tracking it. The contact points are the
+// integrations of the receivers, identified by their UID.
+type UsageInsight struct {
+	ID    int64            `xorm:"pk autoincr 'id'"`
+	OrgID int64            `xorm:"org_id"`
+	Kind  UsageInsightKind `xorm:"kind"`
+	UID   string           `xorm:"uid"`
+	// LastModified is the last time the contact point was created or changed. It is not tracked for alert rules,
+	// which record when they were updated themselves.
+	LastModified *time.Time `xorm:"last_modified"`
+	// LastFired is the last time the alert rule was evaluated with firing alerts.
+	LastFired *time.Time `xorm:"last_fired"`
+	// LastNotified is the last time a notification was sent by the contact point, or about the alerts of the rule.
+	LastNotified *time.Time `xorm:"last_notified"`
+	// Notifications is the number of notifications sent successfully.
+	Notifications int64 `xorm:"notifications"`
+}
+
+// Merge adds the usage of other to the usage: the latest times are kept and the notifications are summed.
+func (u *UsageInsight) Merge(other UsageInsight) {
+	u.LastModified = latestTime(u.LastModified, other.LastModified)
+	u.LastFired = latestTime(u.LastFired, other.LastFired)
+	u.LastNotified = latestTime(u.LastNotified, other.LastNotified)
+	u.Notifications += other.Notifications
+}
+
+// LastUsed returns the last time the object fired or notified, or nil if it was not used.
+func (u *UsageInsight) LastUsed() *time.Time {
+	return latestTime(u.LastFired, u.LastNotified)
+}
+
+func latestTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
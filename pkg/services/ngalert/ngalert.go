@@ -3,6 +3,7 @@ package ngalert
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -28,8 +30,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/cleanup"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/insights"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/migration"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -39,6 +43,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/alertseries"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
@@ -46,6 +51,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -74,6 +80,8 @@ func ProvideService(
 	tracer tracing.Tracer,
 	ruleStore *store.DBstore,
 	upgradeService migration.UpgradeService,
+	bundleRegistry supportbundles.Service,
+	httpClientProvider httpclient.Provider,
 
 	// This is necessary to ensure the guardian provider is initialized before we run the migration.
 	_ *guardian.Provider,
@@ -104,6 +112,7 @@ func ProvideService(
 		tracer:               tracer,
 		store:                ruleStore,
 		upgradeService:       upgradeService,
+		httpClientProvider:   httpClientProvider,
 	}
 
 	// Migration is called even if UA is disabled. If UA is disabled, this will do nothing except handle logic around
@@ -120,6 +129,7 @@ func ProvideService(
 	if err := ng.init(); err != nil {
 		return nil, err
 	}
+	bundleRegistry.RegisterSupportItemCollector(ng.supportBundleCollector())
 
 	return ng, nil
 }
@@ -144,6 +154,10 @@ type AlertNG struct {
 	ImageService        image.ImageService
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
+	alertSeriesWriter   *alertseries.Writer
+	orgCleaner          *cleanup.Cleaner
+	usageTracker        *insights.Tracker
+	historian           Historian
 	folderService       folder.Service
 	dashboardService    dashboards.DashboardService
 	api                 *api.API
@@ -160,7 +174,8 @@ type AlertNG struct {
 	pluginsStore pluginstore.Store
 	tracer       tracing.Tracer
 
-	upgradeService migration.UpgradeService
+	upgradeService     migration.UpgradeService
+	httpClientProvider httpclient.Provider
 }
 
 func (ng *AlertNG) init() error {
@@ -230,6 +245,13 @@ func (ng *AlertNG) init() error {
 		}
 	}
 
+	overrides = append(overrides, notifier.WithSilenceMetadataStore(ng.store))
+	ng.usageTracker = insights.NewTracker(ng.store, clock.New(), log.New("ngalert.insights"))
+	overrides = append(overrides, notifier.WithUsageRecorder(ng.usageTracker))
+	if ng.httpClientProvider != nil {
+		overrides = append(overrides, notifier.WithHTTPClientProvider(ng.httpClientProvider))
+	}
+
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
 	moa, err := notifier.NewMultiOrgAlertmanager(ng.Cfg, ng.store, ng.store, ng.KVStore, ng.store, decryptFn, multiOrgMetrics, ng.NotificationService, moaLogger, ng.SecretsService, overrides...)
@@ -244,6 +266,11 @@ func (ng *AlertNG) init() error {
 	}
 	ng.ImageService = imageService
 
+	queryPreviews, err := image.NewQueryPreviewServiceFromCfg(ng.Cfg, ng.store, ng.renderService, ng.Metrics.Registerer)
+	if err != nil {
+		return err
+	}
+
 	// Let's make sure we're able to complete an initial sync of Alertmanagers before we start the alerting components.
 	if err := ng.MultiOrgAlertmanager.LoadAndSyncAlertmanagersForOrgs(initCtx); err != nil {
 		return fmt.Errorf("failed to initialize alerting because multiorg alertmanager manager failed to warm up: %w", err)
@@ -257,6 +284,9 @@ func (ng *AlertNG) init() error {
 
 	clk := clock.New()
 
+	ng.orgCleaner = cleanup.NewCleaner(cleanup.DefaultConfig(), ng.store, ng.MultiOrgAlertmanager, clk, log.New("ngalert.cleanup"))
+	subscribeToOrgDeletions(ng.bus, ng.orgCleaner)
+
 	alertsRouter := sender.NewAlertsRouter(ng.MultiOrgAlertmanager, ng.store, clk, appUrl, ng.Cfg.UnifiedAlerting.DisabledOrgs,
 		ng.Cfg.UnifiedAlerting.AdminConfigPollInterval, ng.DataSourceService, ng.SecretsService)
 
@@ -269,19 +299,23 @@ func (ng *AlertNG) init() error {
 
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
 	schedCfg := schedule.SchedulerCfg{
-		MaxAttempts:          ng.Cfg.UnifiedAlerting.MaxAttempts,
-		C:                    clk,
-		BaseInterval:         ng.Cfg.UnifiedAlerting.BaseInterval,
-		MinRuleInterval:      ng.Cfg.UnifiedAlerting.MinInterval,
-		DisableGrafanaFolder: ng.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
-		JitterEvaluations:    schedule.JitterStrategyFrom(ng.FeatureToggles),
-		AppURL:               appUrl,
-		EvaluatorFactory:     evalFactory,
-		RuleStore:            ng.store,
-		Metrics:              ng.Metrics.GetSchedulerMetrics(),
-		AlertSender:          alertsRouter,
-		Tracer:               ng.tracer,
-		Log:                  log.New("ngalert.scheduler"),
+		MaxAttempts:             ng.Cfg.UnifiedAlerting.MaxAttempts,
+		C:                       clk,
+		BaseInterval:            ng.Cfg.UnifiedAlerting.BaseInterval,
+		MinRuleInterval:         ng.Cfg.UnifiedAlerting.MinInterval,
+		DefaultRuleInterval:     ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval,
+		DisableGrafanaFolder:    ng.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
+		JitterEvaluations:       schedule.JitterStrategyFrom(ng.FeatureToggles),
+		AppURL:                  appUrl,
+		EvaluatorFactory:        evalFactory,
+		RuleStore:               ng.store,
+		AdminConfigStore:        ng.store,
+		AdminConfigPollInterval: ng.Cfg.UnifiedAlerting.AdminConfigPollInterval,
+		PauseWindowStore:        ng.store,
+		Metrics:                 ng.Metrics.GetSchedulerMetrics(),
+		AlertSender:             alertsRouter,
+		Tracer:                  ng.tracer,
+		Log:                     log.New("ngalert.scheduler"),
 	}
 
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
@@ -291,6 +325,9 @@ func (ng *AlertNG) init() error {
 	if err != nil {
 		return err
 	}
+	ng.historian = history
+	labelRewriters := state.NewAdminConfigLabelRewriters(ng.store, ng.Cfg.UnifiedAlerting.AdminConfigPollInterval, clk, log.New("ngalert.state.labels"))
+	staleSeriesPolicies := state.NewAdminConfigStaleSeriesPolicies(ng.store, ng.Cfg.UnifiedAlerting.AdminConfigPollInterval, clk, log.New("ngalert.state.stale"))
 	cfg := state.ManagerCfg{
 		Metrics:                        ng.Metrics.GetStateMetrics(),
 		ExternalURL:                    appUrl,
@@ -298,12 +335,27 @@ func (ng *AlertNG) init() error {
 		Images:                         ng.ImageService,
 		Clock:                          clk,
 		Historian:                      history,
+		QueryPreviews:                  queryPreviews,
 		DoNotSaveNormalState:           ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingNoNormalState),
 		ApplyNoDataAndErrorToAllStates: ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingNoDataErrorExecution),
 		MaxStateSaveConcurrency:        ng.Cfg.UnifiedAlerting.MaxStateSaveConcurrency,
+		SnapshotStore:                  ng.store,
+		SnapshotInterval:               ng.Cfg.UnifiedAlerting.StateSnapshotInterval,
+		SnapshotNode:                   ng.Cfg.InstanceName,
+		LabelRewriters:                 labelRewriters,
+		StaleSeriesPolicies:            staleSeriesPolicies,
+		Usage:                          ng.usageTracker,
 		Tracer:                         ng.tracer,
 		Log:                            log.New("ngalert.state.manager"),
 	}
+	if ng.Cfg.UnifiedAlerting.StateRemoteWrite.Enabled {
+		writerCfg, err := alertseries.NewConfig(ng.Cfg.UnifiedAlerting.StateRemoteWrite)
+		if err != nil {
+			return fmt.Errorf("failed to configure the remote-write of alert series: %w", err)
+		}
+		ng.alertSeriesWriter = alertseries.NewWriter(writerCfg, &http.Client{}, log.New("ngalert.state.alertseries"))
+		cfg.AlertSeries = ng.alertSeriesWriter
+	}
 	logger := log.New("ngalert.state.manager.persist")
 	statePersister := state.NewSyncStatePersisiter(logger, cfg)
 	if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
@@ -328,7 +380,7 @@ func (ng *AlertNG) init() error {
 	contactPointService := provisioning.NewContactPointService(ng.store, ng.SecretsService, ng.store, ng.store, receiverService, ng.Log)
 	templateService := provisioning.NewTemplateService(ng.store, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(ng.store, ng.store, ng.store, ng.Log)
-	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
+	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
 
@@ -343,6 +395,15 @@ func (ng *AlertNG) init() error {
 		RuleStore:            ng.store,
 		AlertingStore:        ng.store,
 		AdminConfigStore:     ng.store,
+		OrgStore:             ng.store,
+		AdminConfigObserver:  adminConfigObservers{scheduler, labelRewriters, staleSeriesPolicies},
+		LabelPolicyStore:     ng.store,
+		PauseWindowStore:     ng.store,
+		PauseWindowObserver:  scheduler,
+		SilenceMetadataStore: ng.store,
+		TemplateVersionStore: ng.store,
+		UsageInsightStore:    ng.store,
+		KVStore:              ng.KVStore,
 		ProvenanceStore:      ng.store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
@@ -361,6 +422,9 @@ func (ng *AlertNG) init() error {
 		Hooks:                api.NewHooks(ng.Log),
 		Tracer:               ng.tracer,
 		UpgradeService:       ng.upgradeService,
+		OrgMetrics:           ng.Metrics,
+		OrgCleanup:           ng.orgCleaner,
+		Scheduler:            scheduler,
 	}
 	ng.api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -379,6 +443,15 @@ func (ng *AlertNG) init() error {
 	return DeclareFixedRoles(ng.accesscontrolService)
 }
 
+// adminConfigObservers notifies every observer when the admin configuration of an organization is changed or deleted.
+type adminConfigObservers []api.AdminConfigObserver
+
+func (o adminConfigObservers) AdminConfigurationChanged(orgID int64) {
+	for _, observer := range o {
+		observer.AdminConfigurationChanged(orgID)
+	}
+}
+
 func subscribeToFolderChanges(logger log.Logger, bus bus.Bus, dbStore api.RuleStore) {
 	// if folder title is changed, we update all alert rules in that folder to make sure that all peers (in HA mode) will update folder title and
 	// clean up the current state
@@ -393,6 +466,15 @@ func subscribeToFolderChanges(logger log.Logger, bus bus.Bus, dbStore api.RuleSt
 	})
 }
 
+// subscribeToOrgDeletions starts the cleanup of the alerting data of organizations as soon as they are deleted. The
+// organizations deleted on other instances are found by the cleaner itself.
+func subscribeToOrgDeletions(bus bus.Bus, cleaner *cleanup.Cleaner) {
+	bus.AddEventListener(func(ctx context.Context, evt *events.OrgDeleted) error {
+		cleaner.OrgDeleted(evt.Id)
+		return nil
+	})
+}
+
 // shouldRun determines if AlertNG should init or run anything more than just the migration.
 func (ng *AlertNG) shouldRun() bool {
 	if ng.Cfg.UnifiedAlerting.IsEnabled() {
@@ -422,6 +504,18 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.orgCleaner.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.usageTracker.Run(subCtx)
+	})
+	// backends that write in the background flush their pending writes when the context is done
+	if runner, ok := ng.historian.(historian.Runner); ok {
+		children.Go(func() error {
+			return runner.Run(subCtx)
+		})
+	}
 
 	// We explicitly check that UA is enabled here in case FlagAlertingPreviewUpgrade is enabled but UA is disabled.
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts && ng.Cfg.UnifiedAlerting.IsEnabled() {
@@ -442,6 +536,11 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 		children.Go(func() error {
 			return ng.stateManager.Run(subCtx)
 		})
+		if ng.alertSeriesWriter != nil {
+			children.Go(func() error {
+				return ng.alertSeriesWriter.Run(subCtx)
+			})
+		}
 	}
 	return children.Wait()
 }
@@ -475,6 +574,9 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 
 	met.Info.WithLabelValues(backend.String()).Set(1)
 	if backend == historian.BackendTypeMultiple {
+		if primary, _ := historian.ParseBackendType(cfg.MultiPrimary); primary == historian.BackendTypeWebhook {
+			return nil, fmt.Errorf("multi-backend target \"%s\" cannot be the primary backend, as it does not support queries", cfg.MultiPrimary)
+		}
 		primaryCfg := cfg
 		primaryCfg.Backend = cfg.MultiPrimary
 		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, met, l)
@@ -515,6 +617,13 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		}
 		return backend, nil
 	}
+	if backend == historian.BackendTypeWebhook {
+		wcfg, err := historian.NewWebhookConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook configuration: %w", err)
+		}
+		return historian.NewWebhookBackend(wcfg, historian.NewRequester(), met), nil
+	}
 
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
@@ -102,6 +102,35 @@ func TestConfigureHistorianBackend(t *testing.T) {
 		require.ErrorContains(t, err, "unrecognized")
 	})
 
+	t.Run("fail initialization if webhook is the multi-backend primary", func(t *testing.T) {
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+		logger := log.NewNopLogger()
+		cfg := setting.UnifiedAlertingStateHistorySettings{
+			Enabled:          true,
+			Backend:          "multiple",
+			MultiPrimary:     "webhook",
+			MultiSecondaries: []string{"annotations"},
+			WebhookURL:       "http://localhost/history",
+		}
+
+		_, err := configureHistorianBackend(context.Background(), cfg, nil, nil, nil, met, logger)
+
+		require.ErrorContains(t, err, "does not support queries")
+	})
+
+	t.Run("fail initialization if webhook URL is missing", func(t *testing.T) {
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+		logger := log.NewNopLogger()
+		cfg := setting.UnifiedAlertingStateHistorySettings{
+			Enabled: true,
+			Backend: "webhook",
+		}
+
+		_, err := configureHistorianBackend(context.Background(), cfg, nil, nil, nil, met, logger)
+
+		require.ErrorContains(t, err, "invalid webhook configuration")
+	})
+
 	t.Run("do not fail initialization if pinging Loki fails", func(t *testing.T) {
 		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
 		logger := log.NewNopLogger()
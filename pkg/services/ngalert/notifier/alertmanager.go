@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
@@ -55,8 +56,18 @@ type alertmanager struct {
 	fileStore           *FileStore
 	NotificationService notifications.Service
 
-	decryptFn alertingNotify.GetDecryptedValueFn
-	orgID     int64
+	decryptFn          alertingNotify.GetDecryptedValueFn
+	secrets            *secretResolver
+	stopSecretsRefresh context.CancelFunc
+	enricher           *enricher
+	rateLimits         *notificationRateLimiters
+	deliveries         *deliveryStats
+	orgID              int64
+
+	usage UsageRecorder
+	// integrationFingerprints are the fingerprints of the integrations of the last applied configuration,
+	// to record the contact points that are changed.
+	integrationFingerprints map[string]string
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -99,9 +110,13 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		return nil, err
 	}
 
+	silencesRetention := cfg.UnifiedAlerting.SilencesRetention
+	if silencesRetention <= 0 {
+		silencesRetention = retentionNotificationsAndSilences
+	}
 	silencesOptions := maintenanceOptions{
 		filepath:             silencesFilepath,
-		retention:            retentionNotificationsAndSilences,
+		retention:            silencesRetention,
 		maintenanceFrequency: silenceMaintenanceInterval,
 		maintenanceFunc: func(state alertingNotify.State) (int64, error) {
 			// Detached context here is to make sure that when the service is shut down the persist operation is executed.
@@ -134,6 +149,9 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		return nil, err
 	}
 
+	secrets := newSecretResolver(cfg.UnifiedAlerting.ExternalSecrets, orgID, l)
+	secretsCtx, stopSecretsRefresh := context.WithCancel(context.Background())
+	go secrets.run(secretsCtx)
 	am := &alertmanager{
 		Base:                gam,
 		ConfigMetrics:       m.AlertmanagerConfigMetrics,
@@ -142,7 +160,12 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		NotificationService: ns,
 		orgID:               orgID,
 		decryptFn:           decryptFn,
+		secrets:             secrets,
+		stopSecretsRefresh:  stopSecretsRefresh,
 		fileStore:           fileStore,
+		enricher:            newEnricher(cfg.UnifiedAlerting.Enrichment, l),
+		rateLimits:          newNotificationRateLimiters(l),
+		deliveries:          newDeliveryStats(),
 		logger:              l,
 	}
 
@@ -157,6 +180,7 @@ func (am *alertmanager) Ready() bool {
 }
 
 func (am *alertmanager) StopAndWait() {
+	am.stopSecretsRefresh()
 	am.Base.StopAndWait()
 }
 
@@ -251,6 +275,11 @@ type AggregateMatchersUsage struct {
 func (am *alertmanager) updateConfigMetrics(cfg *apimodels.PostableUserConfig) {
 	var amu AggregateMatchersUsage
 	am.aggregateRouteMatchers(cfg.AlertmanagerConfig.Route, &amu)
+	for _, named := range cfg.AlertmanagerConfig.NamedRoutes {
+		if named.Route != nil {
+			am.aggregateRouteMatchers(named.Route, &amu)
+		}
+	}
 	am.aggregateInhibitMatchers(cfg.AlertmanagerConfig.InhibitRules, &amu)
 	am.ConfigMetrics.Matchers.Set(float64(amu.Matchers))
 	am.ConfigMetrics.MatchRE.Set(float64(amu.MatchRE))
@@ -311,21 +340,35 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 	}
 	cfg.AlertmanagerConfig.Templates = paths
 
-	// If neither the configuration nor templates have changed, we've got nothing to do.
-	if !amConfigChanged && !templatesChanged {
+	// Secrets referenced by contact points might have been rotated, in which case the integrations must be rebuilt.
+	// They are refreshed in the background, so that applying the configuration does not wait for the secret manager.
+	secretsChanged := am.secrets.takeChanged()
+
+	// If neither the configuration, templates nor referenced secrets have changed, we've got nothing to do.
+	if !amConfigChanged && !templatesChanged && !secretsChanged {
 		am.logger.Debug("Neither config nor template have changed, skipping configuration sync.")
 		return false, nil
 	}
 
+	routingTree, err := apimodels.ExpandRoute(cfg.AlertmanagerConfig.Route, cfg.AlertmanagerConfig.NamedRoutes)
+	if err != nil {
+		return false, fmt.Errorf("failed to expand the named routes of the configuration: %w", err)
+	}
+
+	am.secrets.startBuild()
+	am.rateLimits.setLimits(cfg.AlertmanagerConfig.RateLimits)
 	err = am.Base.ApplyConfig(AlertingConfiguration{
 		rawAlertmanagerConfig:    rawConfig,
 		alertmanagerConfig:       cfg.AlertmanagerConfig,
+		routingTree:              routingTree,
 		receivers:                PostableApiAlertingConfigToApiReceivers(cfg.AlertmanagerConfig),
-		receiverIntegrationsFunc: am.buildReceiverIntegrations,
+		receiverIntegrationsFunc: am.buildRateLimitedReceiverIntegrations,
 	})
 	if err != nil {
 		return false, err
 	}
+	am.secrets.finishBuild()
+	am.recordModifiedContactPoints(cfg)
 
 	am.updateConfigMetrics(cfg)
 	return true, nil
@@ -355,7 +398,19 @@ func (am *alertmanager) AppURL() string {
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
-	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
+	var secretErrs []error
+	decryptFn := am.secrets.wrap(am.decryptFn, func(err error) {
+		secretErrs = append(secretErrs, err)
+	})
+	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, decryptFn)
+	if err != nil {
+		return nil, err
+	}
+	// the integrations would be built with empty secrets
+	if len(secretErrs) > 0 {
+		return nil, fmt.Errorf("failed to build the integrations of receiver %s: %w", receiver.Name, errors.Join(secretErrs...))
+	}
+	escalations, err := onCallEscalationSettingsOf(receiver)
 	if err != nil {
 		return nil, err
 	}
@@ -367,6 +422,9 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 		img,
 		LoggerFactory,
 		func(n receivers.Metadata) (receivers.WebhookSender, error) {
+			if settings, ok := escalations[n.UID]; ok && n.Type == onCallIntegrationType {
+				return onCallEscalationSender{WebhookSender: s, settings: settings}, nil
+			}
 			return s, nil
 		},
 		func(n receivers.Metadata) (receivers.EmailSender, error) {
@@ -378,11 +436,34 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
-	return integrations, nil
+	return withQueryPreviews(receiver, integrations)
+}
+
+// buildRateLimitedReceiverIntegrations builds the integrations of a receiver, limited by the rate limits of the configuration.
+// Only the notifications allowed by the rate limits are counted as deliveries of the receiver.
+func (am *alertmanager) buildRateLimitedReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+	integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return am.rateLimits.wrap(receiver.Name, am.deliveries.wrap(receiver.Name, integrationUIDs(receiver), integrations)), nil
 }
 
-// PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not
-func (am *alertmanager) PutAlerts(_ context.Context, postableAlerts apimodels.PostableAlerts) error {
+// GetReceiverDeliveries returns the number of notifications recently sent by the integrations of the receiver.
+func (am *alertmanager) GetReceiverDeliveries(receiver string) apimodels.ReceiverDeliveries {
+	return am.deliveries.get(receiver)
+}
+
+// GetIntegrationHealth returns the health of the integration with the UID, derived from its recent notifications.
+func (am *alertmanager) GetIntegrationHealth(uid string) *apimodels.ContactPointHealth {
+	return am.deliveries.health(uid)
+}
+
+// PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not.
+// If enrichment is enabled, the configured labels and annotations are added to the alerts before they are routed.
+func (am *alertmanager) PutAlerts(ctx context.Context, postableAlerts apimodels.PostableAlerts) error {
+	postableAlerts = am.enricher.Enrich(ctx, postableAlerts)
+
 	alerts := make(alertingNotify.PostableAlerts, 0, len(postableAlerts.PostableAlerts))
 	for _, pa := range postableAlerts.PostableAlerts {
 		alerts = append(alerts, &alertingNotify.PostableAlert{
@@ -242,6 +242,17 @@ func (moa *MultiOrgAlertmanager) mergeProvenance(ctx context.Context, config def
 		config.AlertmanagerConfig.Route.Provenance = definitions.Provenance(provenance)
 	}
 
+	nr := definitions.NamedRoute{}
+	nrProvs, err := moa.ProvStore.GetProvenances(ctx, org, nr.ResourceType())
+	if err != nil {
+		return definitions.GettableUserConfig{}, err
+	}
+	for i, named := range config.AlertmanagerConfig.NamedRoutes {
+		if provenance, exists := nrProvs[named.Name]; exists {
+			config.AlertmanagerConfig.NamedRoutes[i].Provenance = definitions.Provenance(provenance)
+		}
+	}
+
 	cp := definitions.EmbeddedContactPoint{}
 	cpProvs, err := moa.ProvStore.GetProvenances(ctx, org, cp.ResourceType())
 	if err != nil {
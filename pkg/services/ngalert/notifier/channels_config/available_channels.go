@@ -489,6 +489,34 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 					PropertyName: "message",
 					Placeholder:  alertingTemplates.DefaultMessageEmbed,
 				},
+				{
+					Label:        "Escalation team label",
+					Description:  "Label whose value is sent as the team of the escalation. Group the alerts by this label for the team to be set.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "escalationTeamLabel",
+				},
+				{
+					Label:        "Escalation priority label",
+					Description:  "Label whose value is mapped to the priority of the escalation. Group the alerts by this label for the priority to be set.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "escalationPriorityLabel",
+				},
+				{
+					Label:        "Escalation priorities",
+					Description:  "Maps the values of the priority label to the priorities of the escalation. Without mapping, the value of the label is the priority.",
+					Element:      ElementTypeKeyValueMap,
+					InputType:    InputTypeText,
+					PropertyName: "escalationPriorities",
+				},
+				{
+					Label:        "Default escalation priority",
+					Description:  "Priority of the escalation when the priority label is missing or not mapped.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "escalationDefaultPriority",
+				},
 			},
 		},
 		{
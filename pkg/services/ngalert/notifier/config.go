@@ -92,6 +92,8 @@ func Load(rawConfig []byte) (*api.PostableUserConfig, error) {
 type AlertingConfiguration struct {
 	alertmanagerConfig    api.PostableApiAlertingConfig
 	rawAlertmanagerConfig []byte
+	// routingTree is the route of the configuration, in which the references to named routes are expanded.
+	routingTree *api.Route
 
 	receivers                []*alertingNotify.APIReceiver
 	receiverIntegrationsFunc func(r *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error)
@@ -120,7 +122,7 @@ func (a AlertingConfiguration) Receivers() []*alertingNotify.APIReceiver {
 }
 
 func (a AlertingConfiguration) RoutingTree() *alertingNotify.Route {
-	return a.alertmanagerConfig.Route.AsAMRoute()
+	return a.routingTree.AsAMRoute()
 }
 
 func (a AlertingConfiguration) Templates() []string {
@@ -0,0 +1,234 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// deliveryStatsWindow is how long the deliveries of the receivers are counted for.
+const deliveryStatsWindow = 24 * time.Hour
+
+// ReceiverDeliveryReporter is implemented by the Alertmanagers that count the notifications delivered by their receivers.
+type ReceiverDeliveryReporter interface {
+	GetReceiverDeliveries(receiver string) apimodels.ReceiverDeliveries
+}
+
+// IntegrationHealthReporter is implemented by the Alertmanagers that track the health of the integrations of their receivers.
+type IntegrationHealthReporter interface {
+	GetIntegrationHealth(uid string) *apimodels.ContactPointHealth
+}
+
+// GetContactPointHealth returns the health of the integration with the UID in the Alertmanager of the organization,
+// or nil if the integration did not send notifications recently.
+func (moa *MultiOrgAlertmanager) GetContactPointHealth(orgID int64, uid string) *apimodels.ContactPointHealth {
+	// the deliveries are counted even if the Alertmanager is not ready yet
+	am, _ := moa.AlertmanagerFor(orgID)
+	if reporter, ok := am.(IntegrationHealthReporter); ok {
+		return reporter.GetIntegrationHealth(uid)
+	}
+	return nil
+}
+
+// deliveryBucket counts the notifications of a receiver in an hour.
+type deliveryBucket struct {
+	start     time.Time
+	succeeded int
+	failed    int
+}
+
+// integrationDeliveries counts the notifications of an integration and keeps the outcome of the last one.
+type integrationDeliveries struct {
+	buckets     []deliveryBucket
+	lastAttempt time.Time
+	lastFailed  bool
+	lastError   string
+}
+
+// deliveryStats counts the notifications sent by the integrations of the receivers in hourly buckets.
+type deliveryStats struct {
+	now func() time.Time
+
+	mtx          sync.Mutex
+	receivers    map[string][]deliveryBucket
+	lastError    map[string]string
+	integrations map[string]*integrationDeliveries
+
+	// notified is optional. If set, it is called with the alerts of every notification an integration with a UID
+	// sent successfully.
+	notified func(uid string, alerts []*types.Alert)
+}
+
+func newDeliveryStats() *deliveryStats {
+	return &deliveryStats{
+		now:          time.Now,
+		receivers:    make(map[string][]deliveryBucket),
+		lastError:    make(map[string]string),
+		integrations: make(map[string]*integrationDeliveries),
+	}
+}
+
+// record counts a notification sent by the integration of the receiver at the current time.
+// The integration is only tracked if it has a UID.
+func (s *deliveryStats) record(receiver, uid string, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := s.now()
+	s.receivers[receiver] = addDelivery(s.receivers[receiver], now, err)
+	if err != nil {
+		s.lastError[receiver] = err.Error()
+	}
+	if uid != "" {
+		d, ok := s.integrations[uid]
+		if !ok {
+			d = &integrationDeliveries{}
+			s.integrations[uid] = d
+		}
+		d.buckets = addDelivery(d.buckets, now, err)
+		d.lastAttempt = now
+		d.lastFailed = err != nil
+		if err != nil {
+			d.lastError = err.Error()
+		}
+	}
+	s.expire(now)
+}
+
+// addDelivery counts a notification in the bucket of the hour.
+func addDelivery(buckets []deliveryBucket, now time.Time, err error) []deliveryBucket {
+	start := now.Truncate(time.Hour)
+	if len(buckets) == 0 || !buckets[len(buckets)-1].start.Equal(start) {
+		buckets = append(buckets, deliveryBucket{start: start})
+	}
+	if err != nil {
+		buckets[len(buckets)-1].failed++
+	} else {
+		buckets[len(buckets)-1].succeeded++
+	}
+	return buckets
+}
+
+// expireBuckets drops the buckets that are outside the window.
+func expireBuckets(buckets []deliveryBucket, now time.Time) []deliveryBucket {
+	i := 0
+	for i < len(buckets) && now.Sub(buckets[i].start) >= deliveryStatsWindow {
+		i++
+	}
+	return buckets[i:]
+}
+
+// expire drops the buckets that are outside the window.
+func (s *deliveryStats) expire(now time.Time) {
+	for receiver, buckets := range s.receivers {
+		buckets = expireBuckets(buckets, now)
+		if len(buckets) == 0 {
+			delete(s.receivers, receiver)
+			delete(s.lastError, receiver)
+			continue
+		}
+		s.receivers[receiver] = buckets
+	}
+	for uid, d := range s.integrations {
+		d.buckets = expireBuckets(d.buckets, now)
+		if len(d.buckets) == 0 {
+			delete(s.integrations, uid)
+		}
+	}
+}
+
+// get returns the number of notifications sent by the receiver in the window.
+func (s *deliveryStats) get(receiver string) apimodels.ReceiverDeliveries {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.expire(s.now())
+	result := apimodels.ReceiverDeliveries{
+		Window:    deliveryStatsWindow.String(),
+		LastError: s.lastError[receiver],
+	}
+	for _, b := range s.receivers[receiver] {
+		result.Succeeded += b.succeeded
+		result.Failed += b.failed
+	}
+	return result
+}
+
+// health returns the health of the integration with the UID, or nil if it did not send notifications in the window.
+// An integration is failing if its last notification failed, and degraded if other notifications failed in the window.
+func (s *deliveryStats) health(uid string) *apimodels.ContactPointHealth {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.expire(s.now())
+	d, ok := s.integrations[uid]
+	if !ok {
+		return nil
+	}
+	result := &apimodels.ContactPointHealth{
+		Status:      apimodels.ContactPointHealthOK,
+		Window:      deliveryStatsWindow.String(),
+		LastAttempt: d.lastAttempt,
+	}
+	for _, b := range d.buckets {
+		result.Succeeded += b.succeeded
+		result.Failed += b.failed
+	}
+	if result.Failed > 0 {
+		result.Status = apimodels.ContactPointHealthDegraded
+		result.LastError = d.lastError
+	}
+	if d.lastFailed {
+		result.Status = apimodels.ContactPointHealthFailing
+	}
+	return result
+}
+
+// wrap returns the integrations of the receiver, counting the notifications they send.
+// The UIDs are the UIDs of the integrations of the receiver by type, in the order the integrations are built.
+func (s *deliveryStats) wrap(receiver string, uids map[string][]string, integrations []*alertingNotify.Integration) []*alertingNotify.Integration {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, i := range integrations {
+		n := &deliveryCountingNotifier{integration: i, receiver: receiver, stats: s}
+		if typeUIDs := uids[strings.ToLower(i.Name())]; i.Index() < len(typeUIDs) {
+			n.uid = typeUIDs[i.Index()]
+		}
+		result = append(result, alertingNotify.NewIntegration(n, n, i.Name(), i.Index(), receiver))
+	}
+	return result
+}
+
+// integrationUIDs returns the UIDs of the integrations of the receiver by type. The integrations of each type are
+// indexed in the order they are configured in.
+func integrationUIDs(receiver *alertingNotify.APIReceiver) map[string][]string {
+	result := make(map[string][]string)
+	for _, i := range receiver.Integrations {
+		t := strings.ToLower(i.Type)
+		result[t] = append(result[t], i.UID)
+	}
+	return result
+}
+
+// deliveryCountingNotifier records the result of each notification sent by an integration.
+type deliveryCountingNotifier struct {
+	integration *alertingNotify.Integration
+	receiver    string
+	uid         string
+	stats       *deliveryStats
+}
+
+func (n *deliveryCountingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	retry, err := n.integration.Notify(ctx, alerts...)
+	n.stats.record(n.receiver, n.uid, err)
+	if err == nil && n.uid != "" && n.stats.notified != nil {
+		n.stats.notified(n.uid, alerts)
+	}
+	return retry, err
+}
+
+func (n *deliveryCountingNotifier) SendResolved() bool {
+	return n.integration.SendResolved()
+}
@@ -0,0 +1,130 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type failingNotifier struct{}
+
+func (n *failingNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	return true, errors.New("unavailable")
+}
+
+func (n *failingNotifier) SendResolved() bool {
+	return true
+}
+
+func TestDeliveryStats(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	s := newDeliveryStats()
+	s.now = func() time.Time { return now }
+
+	ok := &countingNotifier{}
+	integrations := s.wrap("team-a", nil, []*alertingNotify.Integration{
+		alertingNotify.NewIntegration(ok, ok, "slack", 0, "team-a"),
+		alertingNotify.NewIntegration(&failingNotifier{}, &failingNotifier{}, "webhook", 1, "team-a"),
+	})
+	require.Len(t, integrations, 2)
+
+	_, err := integrations[0].Notify(context.Background(), newRateLimitTestAlert("a"))
+	require.NoError(t, err)
+	now = now.Add(time.Hour)
+	_, err = integrations[0].Notify(context.Background(), newRateLimitTestAlert("a"))
+	require.NoError(t, err)
+	retry, err := integrations[1].Notify(context.Background(), newRateLimitTestAlert("a"))
+	require.Error(t, err)
+	require.True(t, retry)
+
+	require.Len(t, ok.notifications, 2)
+	d := s.get("team-a")
+	require.Equal(t, 2, d.Succeeded)
+	require.Equal(t, 1, d.Failed)
+	require.Equal(t, "unavailable", d.LastError)
+	require.Equal(t, "24h0m0s", d.Window)
+	require.Zero(t, s.get("team-b").Succeeded)
+
+	// notifications sent before the window are no longer counted
+	now = now.Add(23 * time.Hour)
+	d = s.get("team-a")
+	require.Equal(t, 1, d.Succeeded)
+	require.Equal(t, 1, d.Failed)
+
+	now = now.Add(time.Hour)
+	require.Empty(t, s.get("team-a").LastError)
+	require.Empty(t, s.receivers)
+}
+
+func TestIntegrationHealth(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	s := newDeliveryStats()
+	s.now = func() time.Time { return now }
+
+	failing := &failingNotifier{}
+	flaky := &flakyNotifier{}
+	uids := integrationUIDs(&alertingNotify.APIReceiver{GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+		Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+			{UID: "slack-a", Type: "slack"},
+			{UID: "webhook", Type: "webhook"},
+			{UID: "slack-b", Type: "slack"},
+		},
+	}})
+	integrations := s.wrap("team-a", uids, []*alertingNotify.Integration{
+		alertingNotify.NewIntegration(&countingNotifier{}, &countingNotifier{}, "slack", 0, "team-a"),
+		alertingNotify.NewIntegration(failing, failing, "slack", 1, "team-a"),
+		alertingNotify.NewIntegration(flaky, flaky, "webhook", 0, "team-a"),
+	})
+
+	require.Nil(t, s.health("slack-a"))
+	for _, i := range integrations {
+		_, _ = i.Notify(context.Background(), newRateLimitTestAlert("a"))
+	}
+	flaky.succeed = true
+	_, err := integrations[2].Notify(context.Background(), newRateLimitTestAlert("a"))
+	require.NoError(t, err)
+
+	h := s.health("slack-a")
+	require.Equal(t, apimodels.ContactPointHealthOK, h.Status)
+	require.Equal(t, 1, h.Succeeded)
+	require.Equal(t, now, h.LastAttempt)
+	require.Empty(t, h.LastError)
+
+	h = s.health("slack-b")
+	require.Equal(t, apimodels.ContactPointHealthFailing, h.Status)
+	require.Equal(t, 1, h.Failed)
+	require.Equal(t, "unavailable", h.LastError)
+
+	h = s.health("webhook")
+	require.Equal(t, apimodels.ContactPointHealthDegraded, h.Status)
+	require.Equal(t, 1, h.Succeeded)
+	require.Equal(t, 1, h.Failed)
+	require.Equal(t, "temporarily unavailable", h.LastError)
+
+	// the health is unknown once the notifications are outside the window
+	now = now.Add(deliveryStatsWindow + time.Hour)
+	require.Nil(t, s.health("slack-b"))
+	require.Empty(t, s.integrations)
+}
+
+type flakyNotifier struct {
+	succeed bool
+}
+
+func (n *flakyNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	if n.succeed {
+		return false, nil
+	}
+	return true, errors.New("temporarily unavailable")
+}
+
+func (n *flakyNotifier) SendResolved() bool {
+	return true
+}
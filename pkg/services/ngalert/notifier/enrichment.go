@@ -0,0 +1,239 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// maxLookupResponseSize is the maximum size of a response of the enrichment lookup endpoint.
+	maxLookupResponseSize = 1 << 20
+	// maxConcurrentLookups is the maximum number of requests sent to the lookup endpoint at the same time.
+	maxConcurrentLookups = 8
+)
+
+// enrichmentValues are the labels and annotations added to an alert.
+type enrichmentValues struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// cachedEnrichment holds the annotations looked up for an alert. They are refreshed once expired,
+// and kept if the refresh fails so that the notifications of the alert do not flap between values.
+type cachedEnrichment struct {
+	annotations map[string]string
+	expires     time.Time
+}
+
+// enricher adds labels and annotations to alerts before they are routed, so that notifications
+// can carry metadata that is not known when the alert rule is evaluated.
+// Values already defined on an alert are never overwritten. Values of the lookup endpoint are only added
+// as annotations, because labels are part of the identity of an alert and must not depend on the endpoint.
+type enricher struct {
+	static        enrichmentValues
+	lookupURL     string
+	lookupTimeout time.Duration
+	cacheTTL      time.Duration
+	client        *http.Client
+	logger        log.Logger
+	now           func() time.Time
+
+	mtx   sync.Mutex
+	cache map[model.Fingerprint]cachedEnrichment
+}
+
+// newEnricher returns an enricher configured by the settings, or nil if enrichment is disabled.
+func newEnricher(cfg setting.UnifiedAlertingEnrichmentSettings, logger log.Logger) *enricher {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &enricher{
+		static: enrichmentValues{
+			Labels:      cfg.Labels,
+			Annotations: cfg.Annotations,
+		},
+		lookupURL:     cfg.LookupURL,
+		lookupTimeout: cfg.LookupTimeout,
+		cacheTTL:      cfg.LookupCacheTTL,
+		client:        &http.Client{Timeout: cfg.LookupTimeout},
+		logger:        logger,
+		now:           time.Now,
+		cache:         make(map[model.Fingerprint]cachedEnrichment),
+	}
+}
+
+// Enrich returns a copy of the alerts with the configured labels and annotations added.
+// Lookup failures are logged and do not prevent the alerts from being routed.
+func (e *enricher) Enrich(ctx context.Context, alerts apimodels.PostableAlerts) apimodels.PostableAlerts {
+	if e == nil {
+		return alerts
+	}
+	var lookedUp []map[string]string
+	if e.lookupURL != "" {
+		lookedUp = e.lookupAll(ctx, alerts.PostableAlerts)
+	}
+	result := apimodels.PostableAlerts{PostableAlerts: make([]amv2.PostableAlert, 0, len(alerts.PostableAlerts))}
+	for i, a := range alerts.PostableAlerts {
+		alert := a
+		alert.Labels = cloneLabelSet(a.Labels)
+		alert.Annotations = cloneLabelSet(a.Annotations)
+		if lookedUp != nil {
+			applyEnrichment(&alert, enrichmentValues{Annotations: lookedUp[i]})
+		}
+		applyEnrichment(&alert, e.static)
+		result.PostableAlerts = append(result.PostableAlerts, alert)
+	}
+	e.evictExpired()
+	return result
+}
+
+// lookupAll returns the looked up annotations of each alert, from the cache if possible.
+// Alerts with the same labels are looked up once, and the lookups run concurrently with a shared deadline,
+// so that a slow endpoint delays the routing of the alerts by at most the lookup timeout.
+func (e *enricher) lookupAll(ctx context.Context, alerts []amv2.PostableAlert) []map[string]string {
+	now := e.now()
+	fingerprints := make([]model.Fingerprint, len(alerts))
+	pending := make(map[model.Fingerprint]amv2.LabelSet)
+	e.mtx.Lock()
+	for i, a := range alerts {
+		fp := labelSetToModel(a.Labels).Fingerprint()
+		fingerprints[i] = fp
+		if cached, ok := e.cache[fp]; !ok || !now.Before(cached.expires) {
+			pending[fp] = a.Labels
+		}
+	}
+	e.mtx.Unlock()
+
+	if len(pending) > 0 {
+		if e.lookupTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, e.lookupTimeout)
+			defer cancel()
+		}
+		sem := make(chan struct{}, maxConcurrentLookups)
+		var wg sync.WaitGroup
+		for fp, labels := range pending {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(fp model.Fingerprint, labels amv2.LabelSet) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				values, err := e.request(ctx, labels)
+				if err != nil {
+					e.logger.Warn("Failed to look up alert enrichment", "labels", labels, "error", err)
+					return
+				}
+				e.mtx.Lock()
+				e.cache[fp] = cachedEnrichment{annotations: values.Annotations, expires: now.Add(e.cacheTTL)}
+				e.mtx.Unlock()
+			}(fp, labels)
+		}
+		wg.Wait()
+	}
+
+	result := make([]map[string]string, len(alerts))
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	for i, fp := range fingerprints {
+		// if a refresh failed, the previously looked up annotations are used
+		result[i] = e.cache[fp].annotations
+	}
+	return result
+}
+
+func (e *enricher) request(ctx context.Context, labels amv2.LabelSet) (enrichmentValues, error) {
+	body, err := json.Marshal(labels)
+	if err != nil {
+		return enrichmentValues{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.lookupURL, bytes.NewReader(body))
+	if err != nil {
+		return enrichmentValues{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return enrichmentValues{}, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			e.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return enrichmentValues{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var values enrichmentValues
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxLookupResponseSize)).Decode(&values); err != nil {
+		return enrichmentValues{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return values, nil
+}
+
+// evictExpired removes the entries that could not be refreshed for a whole cache TTL after they expired.
+func (e *enricher) evictExpired() {
+	now := e.now()
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	for fp, cached := range e.cache {
+		if !now.Before(cached.expires.Add(e.cacheTTL)) {
+			delete(e.cache, fp)
+		}
+	}
+}
+
+// applyEnrichment adds the values to the alert without overwriting its existing labels and annotations.
+func applyEnrichment(alert *amv2.PostableAlert, values enrichmentValues) {
+	if len(values.Labels) > 0 && alert.Labels == nil {
+		alert.Labels = amv2.LabelSet{}
+	}
+	for k, v := range values.Labels {
+		if _, ok := alert.Labels[k]; !ok {
+			alert.Labels[k] = v
+		}
+	}
+	if len(values.Annotations) > 0 && alert.Annotations == nil {
+		alert.Annotations = amv2.LabelSet{}
+	}
+	for k, v := range values.Annotations {
+		if _, ok := alert.Annotations[k]; !ok {
+			alert.Annotations[k] = v
+		}
+	}
+}
+
+func cloneLabelSet(labels amv2.LabelSet) amv2.LabelSet {
+	if labels == nil {
+		return nil
+	}
+	result := make(amv2.LabelSet, len(labels))
+	for k, v := range labels {
+		result[k] = v
+	}
+	return result
+}
+
+func labelSetToModel(labels amv2.LabelSet) model.LabelSet {
+	result := make(model.LabelSet, len(labels))
+	for k, v := range labels {
+		result[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return result
+}
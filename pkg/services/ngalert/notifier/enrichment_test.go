@@ -0,0 +1,175 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestEnricher(t *testing.T) {
+	newAlerts := func(labels map[string]string) apimodels.PostableAlerts {
+		return apimodels.PostableAlerts{PostableAlerts: []amv2.PostableAlert{{
+			Alert:       amv2.Alert{Labels: labels},
+			Annotations: amv2.LabelSet{"summary": "test"},
+		}}}
+	}
+
+	t.Run("disabled enricher returns the alerts unchanged", func(t *testing.T) {
+		e := newEnricher(setting.UnifiedAlertingEnrichmentSettings{Labels: map[string]string{"team": "a"}}, log.NewNopLogger())
+		require.Nil(t, e)
+		alerts := newAlerts(map[string]string{"alertname": "test"})
+		require.Equal(t, alerts, e.Enrich(context.Background(), alerts))
+	})
+
+	t.Run("static values do not overwrite existing labels and annotations", func(t *testing.T) {
+		e := newEnricher(setting.UnifiedAlertingEnrichmentSettings{
+			Enabled:     true,
+			Labels:      map[string]string{"team": "platform", "alertname": "other"},
+			Annotations: map[string]string{"summary": "other", "runbook_url": "https://example.com"},
+		}, log.NewNopLogger())
+
+		alerts := newAlerts(map[string]string{"alertname": "test"})
+		result := e.Enrich(context.Background(), alerts)
+
+		require.Equal(t, amv2.LabelSet{"alertname": "test", "team": "platform"}, result.PostableAlerts[0].Labels)
+		require.Equal(t, amv2.LabelSet{"summary": "test", "runbook_url": "https://example.com"}, result.PostableAlerts[0].Annotations)
+		// the original alerts are not modified
+		require.Equal(t, amv2.LabelSet{"alertname": "test"}, alerts.PostableAlerts[0].Labels)
+	})
+
+	t.Run("looked up values are added as annotations and cached per label set", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			labels := map[string]string{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&labels))
+			require.NoError(t, json.NewEncoder(w).Encode(enrichmentValues{
+				Labels:      map[string]string{"team": "ignored"},
+				Annotations: map[string]string{"owner": labels["service"] + "-team", "escalation": "pager"},
+			}))
+		}))
+		t.Cleanup(server.Close)
+
+		now := time.Now()
+		e := newEnricher(setting.UnifiedAlertingEnrichmentSettings{
+			Enabled:        true,
+			Labels:         map[string]string{"team": "platform"},
+			LookupURL:      server.URL,
+			LookupTimeout:  time.Second,
+			LookupCacheTTL: time.Minute,
+		}, log.NewNopLogger())
+		e.now = func() time.Time { return now }
+
+		result := e.Enrich(context.Background(), newAlerts(map[string]string{"service": "api"}))
+		require.Equal(t, amv2.LabelSet{"service": "api", "team": "platform"}, result.PostableAlerts[0].Labels)
+		require.Equal(t, amv2.LabelSet{"summary": "test", "owner": "api-team", "escalation": "pager"}, result.PostableAlerts[0].Annotations)
+		require.EqualValues(t, 1, requests.Load())
+
+		e.Enrich(context.Background(), newAlerts(map[string]string{"service": "api"}))
+		require.EqualValues(t, 1, requests.Load())
+
+		e.Enrich(context.Background(), newAlerts(map[string]string{"service": "db"}))
+		require.EqualValues(t, 2, requests.Load())
+
+		now = now.Add(time.Minute)
+		e.Enrich(context.Background(), newAlerts(map[string]string{"service": "api"}))
+		require.EqualValues(t, 3, requests.Load())
+	})
+
+	t.Run("lookups of a batch run concurrently", func(t *testing.T) {
+		const alerts = 3
+		var arrived atomic.Int32
+		all := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if arrived.Add(1) == alerts {
+				close(all)
+			}
+			select {
+			case <-all:
+			case <-r.Context().Done():
+				return
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(enrichmentValues{Annotations: map[string]string{"escalation": "pager"}}))
+		}))
+		t.Cleanup(server.Close)
+
+		e := newEnricher(setting.UnifiedAlertingEnrichmentSettings{
+			Enabled:        true,
+			LookupURL:      server.URL,
+			LookupTimeout:  5 * time.Second,
+			LookupCacheTTL: time.Minute,
+		}, log.NewNopLogger())
+
+		batch := apimodels.PostableAlerts{}
+		for i := 0; i < alerts; i++ {
+			batch.PostableAlerts = append(batch.PostableAlerts, newAlerts(map[string]string{"service": fmt.Sprintf("svc-%d", i)}).PostableAlerts...)
+		}
+		result := e.Enrich(context.Background(), batch)
+		for _, alert := range result.PostableAlerts {
+			require.Equal(t, "pager", alert.Annotations["escalation"])
+		}
+	})
+
+	t.Run("failed refreshes keep the previously looked up values", func(t *testing.T) {
+		fail := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(enrichmentValues{Annotations: map[string]string{"owner": "api-team"}}))
+		}))
+		t.Cleanup(server.Close)
+
+		now := time.Now()
+		e := newEnricher(setting.UnifiedAlertingEnrichmentSettings{
+			Enabled:        true,
+			LookupURL:      server.URL,
+			LookupTimeout:  time.Second,
+			LookupCacheTTL: time.Minute,
+		}, log.NewNopLogger())
+		e.now = func() time.Time { return now }
+
+		e.Enrich(context.Background(), newAlerts(map[string]string{"service": "api"}))
+		fail = true
+		now = now.Add(time.Minute)
+		result := e.Enrich(context.Background(), newAlerts(map[string]string{"service": "api"}))
+		require.Equal(t, "api-team", result.PostableAlerts[0].Annotations["owner"])
+
+		now = now.Add(time.Minute)
+		result = e.Enrich(context.Background(), newAlerts(map[string]string{"service": "api"}))
+		require.Equal(t, "api-team", result.PostableAlerts[0].Annotations["owner"])
+		require.Empty(t, e.cache)
+	})
+
+	t.Run("failed lookups fall back to the static values", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+
+		e := newEnricher(setting.UnifiedAlertingEnrichmentSettings{
+			Enabled:        true,
+			Labels:         map[string]string{"owner": "fallback"},
+			LookupURL:      server.URL,
+			LookupTimeout:  time.Second,
+			LookupCacheTTL: time.Minute,
+		}, log.NewNopLogger())
+
+		result := e.Enrich(context.Background(), newAlerts(map[string]string{"service": "api"}))
+		require.Equal(t, amv2.LabelSet{"service": "api", "owner": "fallback"}, result.PostableAlerts[0].Labels)
+		require.Empty(t, e.cache)
+	})
+}
@@ -0,0 +1,471 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	maintenanceCalendarFetchTimeout = 30 * time.Second
+	// maintenanceCalendarMaxSize is the maximum size of a feed, larger feeds are rejected.
+	maintenanceCalendarMaxSize = 10 << 20
+)
+
+// maintenanceCalendars keeps track of when the maintenance calendars of the organizations were last synced.
+type maintenanceCalendars struct {
+	provider httpclient.Provider
+	// syncing is true while a sync runs, syncedAt is only accessed by the sync.
+	syncing  atomic.Bool
+	syncedAt map[string]time.Time
+}
+
+// WithHTTPClientProvider enables the maintenance calendars. The calendars are fetched with the clients of the
+// provider, so that the requests to the URLs of the calendars are subject to the same restrictions as the other
+// outgoing requests of Grafana.
+func WithHTTPClientProvider(p httpclient.Provider) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.maintenanceCalendars = &maintenanceCalendars{
+			provider: p,
+			syncedAt: map[string]time.Time{},
+		}
+	}
+}
+
+// startMaintenanceCalendarSync syncs the maintenance calendars in the background, so that slow calendars do not
+// delay the sync of the Alertmanagers. A sync is not started while the previous one is running.
+func (moa *MultiOrgAlertmanager) startMaintenanceCalendarSync(ctx context.Context) {
+	if moa.maintenanceCalendars == nil || !moa.maintenanceCalendars.syncing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer moa.maintenanceCalendars.syncing.Store(false)
+		moa.syncMaintenanceCalendars(ctx)
+	}()
+}
+
+// syncMaintenanceCalendars imports the events of the maintenance calendars of each organization whose sync interval
+// elapsed as silences. A calendar that fails to sync is tried again after its sync interval.
+//
+// The silences are replicated to the other replicas of a highly available setup. The IDs of new silences are random,
+// so the replicas would create duplicate silences for the same event, and only the first replica syncs the calendars.
+func (moa *MultiOrgAlertmanager) syncMaintenanceCalendars(ctx context.Context) {
+	if moa.peer.Position() != 0 {
+		return
+	}
+	configs, err := moa.getLatestConfigs(ctx)
+	if err != nil {
+		moa.logger.Error("Failed to load Alertmanager configurations to sync maintenance calendars", "error", err)
+		return
+	}
+	now := time.Now()
+	synced := make(map[string]time.Time, len(moa.maintenanceCalendars.syncedAt))
+	for orgID, dbConfig := range configs {
+		cfg, err := Load([]byte(dbConfig.AlertmanagerConfiguration))
+		// invalid configurations are reported when they are applied
+		if err != nil || len(cfg.AlertmanagerConfig.MaintenanceCalendars) == 0 {
+			continue
+		}
+		am, err := moa.AlertmanagerFor(orgID)
+		if err != nil {
+			continue
+		}
+		for _, cal := range cfg.AlertmanagerConfig.MaintenanceCalendars {
+			key := fmt.Sprintf("%d/%s/%s", orgID, cal.Name, cal.URL)
+			if last, ok := moa.maintenanceCalendars.syncedAt[key]; ok && now.Sub(last) < cal.GetSyncInterval() {
+				synced[key] = last
+				continue
+			}
+			synced[key] = now
+			if err := moa.syncMaintenanceCalendar(ctx, am, cal, now); err != nil {
+				moa.logger.Error("Failed to sync maintenance calendar", "org", orgID, "calendar", cal.Name, "error", err)
+			}
+		}
+	}
+	moa.maintenanceCalendars.syncedAt = synced
+}
+
+func (moa *MultiOrgAlertmanager) syncMaintenanceCalendar(ctx context.Context, am Alertmanager, cal apimodels.MaintenanceCalendar, now time.Time) error {
+	events, err := moa.maintenanceCalendars.fetch(ctx, cal.URL)
+	if err != nil {
+		return err
+	}
+	silences, err := am.ListSilences(ctx, nil)
+	if err != nil {
+		return err
+	}
+	upserts, expire := planMaintenanceSilences(cal, events, silences, now)
+	for _, s := range upserts {
+		if _, err := am.CreateSilence(ctx, s); err != nil {
+			return fmt.Errorf("failed to save the silence of event %s: %w", *s.Comment, err)
+		}
+	}
+	for _, id := range expire {
+		if err := am.DeleteSilence(ctx, id); err != nil {
+			return fmt.Errorf("failed to expire silence %s: %w", id, err)
+		}
+	}
+	if len(upserts) > 0 || len(expire) > 0 {
+		moa.logger.Info("Synced maintenance calendar", "calendar", cal.Name, "saved", len(upserts), "expired", len(expire))
+	}
+	return nil
+}
+
+func (c *maintenanceCalendars) fetch(ctx context.Context, url string) ([]icalEvent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	timeouts := sdkhttpclient.DefaultTimeoutOptions
+	timeouts.Timeout = maintenanceCalendarFetchTimeout
+	client, err := c.provider.New(sdkhttpclient.Options{Timeouts: &timeouts})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maintenanceCalendarMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maintenanceCalendarMaxSize {
+		return nil, fmt.Errorf("the calendar is larger than %d bytes", maintenanceCalendarMaxSize)
+	}
+	return parseICalendar(string(body))
+}
+
+// maintenanceCalendarCreatedBy is the author of the silences imported from the calendar.
+func maintenanceCalendarCreatedBy(name string) string {
+	return "maintenance calendar " + name
+}
+
+// maintenanceSilenceComment returns the comment of the silence of the event. It ends with the UID of the event,
+// which identifies the silence of the event when the calendar is synced again.
+func maintenanceSilenceComment(e icalEvent) string {
+	return fmt.Sprintf("%s [%s]", e.Summary, e.UID)
+}
+
+func eventUIDFromComment(comment string) string {
+	i := strings.LastIndex(comment, " [")
+	if i < 0 || !strings.HasSuffix(comment, "]") {
+		return ""
+	}
+	return comment[i+2 : len(comment)-1]
+}
+
+// planMaintenanceSilences returns the silences to create or update so that the alerts are silenced during the events
+// of the calendar that end after now and start within the lookahead of the calendar, and the IDs of the silences to
+// expire because their event was cancelled or removed from the calendar.
+func planMaintenanceSilences(cal apimodels.MaintenanceCalendar, events []icalEvent, silences apimodels.GettableSilences, now time.Time) ([]*apimodels.PostableSilence, []string) {
+	createdBy := maintenanceCalendarCreatedBy(cal.Name)
+	existing := make(map[string]*amv2.GettableSilence)
+	for _, s := range silences {
+		if s.ID == nil || s.CreatedBy == nil || *s.CreatedBy != createdBy || s.Comment == nil {
+			continue
+		}
+		if s.Status == nil || s.Status.State == nil || *s.Status.State == amv2.SilenceStatusStateExpired {
+			continue
+		}
+		if uid := eventUIDFromComment(*s.Comment); uid != "" {
+			existing[uid] = s
+		}
+	}
+
+	matchers := toSilenceMatchers(cal.Matchers)
+	horizon := now.Add(cal.GetLookahead())
+	seen := make(map[string]struct{}, len(events))
+	var upserts []*apimodels.PostableSilence
+	for _, e := range events {
+		if e.Cancelled || !e.End.After(now) || e.Start.After(horizon) {
+			continue
+		}
+		// only the first occurrence of recurring events is imported
+		if _, ok := seen[e.UID]; ok {
+			continue
+		}
+		seen[e.UID] = struct{}{}
+
+		comment := maintenanceSilenceComment(e)
+		startsAt, endsAt := strfmt.DateTime(e.Start), strfmt.DateTime(e.End)
+		silence := &apimodels.PostableSilence{
+			Silence: amv2.Silence{
+				Comment:   &comment,
+				CreatedBy: &createdBy,
+				StartsAt:  &startsAt,
+				EndsAt:    &endsAt,
+				Matchers:  matchers,
+			},
+		}
+		if s, ok := existing[e.UID]; ok {
+			if silenceMatchesEvent(s, silence) {
+				continue
+			}
+			silence.ID = *s.ID
+		}
+		upserts = append(upserts, silence)
+	}
+
+	var expire []string
+	for uid, s := range existing {
+		if _, ok := seen[uid]; !ok {
+			expire = append(expire, *s.ID)
+		}
+	}
+	sort.Strings(expire)
+	return upserts, expire
+}
+
+// silenceMatchesEvent returns true if the silence already silences the alerts as the silence of the event would.
+// The start of an active silence cannot change, so it is not compared.
+func silenceMatchesEvent(s *amv2.GettableSilence, want *apimodels.PostableSilence) bool {
+	if s.EndsAt == nil || !time.Time(*s.EndsAt).Equal(time.Time(*want.EndsAt)) {
+		return false
+	}
+	if *s.Status.State != amv2.SilenceStatusStateActive && (s.StartsAt == nil || !time.Time(*s.StartsAt).Equal(time.Time(*want.StartsAt))) {
+		return false
+	}
+	return *s.Comment == *want.Comment && matchersKey(s.Matchers) == matchersKey(want.Matchers)
+}
+
+func toSilenceMatchers(matchers []*labels.Matcher) amv2.Matchers {
+	result := make(amv2.Matchers, 0, len(matchers))
+	for _, m := range matchers {
+		name, value := m.Name, m.Value
+		isEqual := m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp
+		isRegex := m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp
+		result = append(result, &amv2.Matcher{Name: &name, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex})
+	}
+	return result
+}
+
+func matchersKey(matchers amv2.Matchers) string {
+	keys := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m == nil || m.Name == nil || m.Value == nil || m.IsRegex == nil {
+			continue
+		}
+		// silences without the equality flag are equality matchers
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		keys = append(keys, fmt.Sprintf("%q/%q/%t/%t", *m.Name, *m.Value, isEqual, *m.IsRegex))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// icalEvent is an event of an iCalendar feed.
+type icalEvent struct {
+	UID       string
+	Summary   string
+	Start     time.Time
+	End       time.Time
+	Cancelled bool
+}
+
+var errInvalidICalendar = errors.New("invalid iCalendar")
+
+// parseICalendar returns the events of the iCalendar (RFC 5545). Events without a UID or a start are skipped.
+// Times without a time zone are in UTC.
+func parseICalendar(s string) ([]icalEvent, error) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	// lines that start with a space or a tab continue the previous line
+	s = strings.ReplaceAll(s, "\n ", "")
+	s = strings.ReplaceAll(s, "\n\t", "")
+
+	var (
+		events    []icalEvent
+		event     *icalEvent
+		duration  time.Duration
+		allDay    bool
+		calendar  bool
+		nested    int
+		scanner   = bufio.NewScanner(strings.NewReader(s))
+		lineCount int
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), maintenanceCalendarMaxSize)
+	for scanner.Scan() {
+		lineCount++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, params, value, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", errInvalidICalendar, lineCount, err)
+		}
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR":
+			calendar = true
+			continue
+		case name == "BEGIN" && value == "VEVENT" && event == nil:
+			event, duration, allDay = &icalEvent{}, 0, false
+			continue
+		case name == "BEGIN":
+			nested++
+			continue
+		case name == "END" && nested > 0:
+			nested--
+			continue
+		case name == "END" && value == "VEVENT" && event != nil:
+			if event.End.IsZero() {
+				switch {
+				case duration > 0:
+					event.End = event.Start.Add(duration)
+				case allDay:
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+			}
+			if event.UID != "" && !event.Start.IsZero() && event.End.After(event.Start) {
+				events = append(events, *event)
+			}
+			event = nil
+			continue
+		}
+		if event == nil || nested > 0 {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			event.UID = value
+		case "SUMMARY":
+			event.Summary = unescapeICalText(value)
+		case "STATUS":
+			event.Cancelled = strings.EqualFold(value, "CANCELLED")
+		case "DTSTART":
+			event.Start, allDay, err = parseICalTime(value, params)
+		case "DTEND":
+			event.End, _, err = parseICalTime(value, params)
+		case "DURATION":
+			duration, err = parseICalDuration(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", errInvalidICalendar, lineCount, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !calendar {
+		return nil, fmt.Errorf("%w: missing VCALENDAR", errInvalidICalendar)
+	}
+	return events, nil
+}
+
+// parseICalLine splits a content line into its upper case name, its parameters and its value.
+func parseICalLine(line string) (string, map[string]string, string, error) {
+	quoted := false
+	sep := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return "", nil, "", fmt.Errorf("missing value in %q", line)
+	}
+	parts := strings.Split(line[:sep], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, line[sep+1:], nil
+}
+
+// parseICalTime parses a date or a date-time, and returns true if it is a date.
+func parseICalTime(value string, params map[string]string) (time.Time, bool, error) {
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseICalDuration parses a positive duration such as P1D or PT1H30M.
+func parseICalDuration(value string) (time.Duration, error) {
+	v := strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(v, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	var (
+		result time.Duration
+		inTime bool
+		number string
+	)
+	for _, r := range v[1:] {
+		if r >= '0' && r <= '9' {
+			number += string(r)
+			continue
+		}
+		if r == 'T' {
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		number = ""
+		switch {
+		case r == 'W' && !inTime:
+			result += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			result += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			result += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			result += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			result += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return result, nil
+}
+
+var icalTextReplacer = strings.NewReplacer(`\\`, `\`, `\,`, `,`, `\;`, `;`, `\n`, " ", `\N`, " ")
+
+func unescapeICalText(s string) string {
+	return icalTextReplacer.Replace(s)
+}
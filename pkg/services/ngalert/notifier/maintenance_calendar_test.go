@@ -0,0 +1,165 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestParseICalendar(t *testing.T) {
+	feed := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTIMEZONE\r\n" +
+		"TZID:Europe/Berlin\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:change-1\r\n" +
+		"SUMMARY:Database upgrade\\, primary\r\n" +
+		"DTSTART:20240301T220000Z\r\n" +
+		"DTEND:20240301T230000Z\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"TRIGGER:-PT15M\r\n" +
+		"DESCRIPTION:Reminder\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:change-2\r\n" +
+		"SUMMARY:Network maintenance in the data \r\n" +
+		" center\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20240302T100000\r\n" +
+		"DURATION:PT1H30M\r\n" +
+		"STATUS:CANCELLED\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:change-3\r\n" +
+		"SUMMARY:Freeze\r\n" +
+		"DTSTART;VALUE=DATE:20240304\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:No UID\r\n" +
+		"DTSTART:20240301T220000Z\r\n" +
+		"DTEND:20240301T230000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := parseICalendar(feed)
+	require.NoError(t, err)
+	for i := range events {
+		events[i].Start, events[i].End = events[i].Start.UTC(), events[i].End.UTC()
+	}
+
+	require.Equal(t, []icalEvent{
+		{
+			UID:     "change-1",
+			Summary: "Database upgrade, primary",
+			Start:   time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC),
+			End:     time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			UID:       "change-2",
+			Summary:   "Network maintenance in the data center",
+			Start:     time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC),
+			End:       time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC),
+			Cancelled: true,
+		},
+		{
+			UID:     "change-3",
+			Summary: "Freeze",
+			Start:   time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+	}, events)
+
+	_, err = parseICalendar("not a calendar")
+	require.ErrorIs(t, err, errInvalidICalendar)
+
+	_, err = parseICalendar("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:1\nDTSTART:yesterday\nEND:VEVENT\nEND:VCALENDAR\n")
+	require.ErrorIs(t, err, errInvalidICalendar)
+}
+
+func TestParseICalDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"PT30M":     30 * time.Minute,
+		"P1D":       24 * time.Hour,
+		"P1DT2H":    26 * time.Hour,
+		"+P1W":      7 * 24 * time.Hour,
+		"PT1H0M10S": time.Hour + 10*time.Second,
+	} {
+		d, err := parseICalDuration(value)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, d, value)
+	}
+	for _, value := range []string{"1H", "PT1D", "P1H", "PT1"} {
+		_, err := parseICalDuration(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestPlanMaintenanceSilences(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cal := apimodels.MaintenanceCalendar{
+		Name:     "changes",
+		URL:      "https://calendar.example.com/changes.ics",
+		Matchers: config.Matchers{{Type: labels.MatchEqual, Name: "env", Value: "prod"}},
+	}
+	event := func(uid string, start time.Time, d time.Duration) icalEvent {
+		return icalEvent{UID: uid, Summary: "Change " + uid, Start: start, End: start.Add(d)}
+	}
+	silence := func(id string, e icalEvent, state string) *amv2.GettableSilence {
+		comment, createdBy := maintenanceSilenceComment(e), maintenanceCalendarCreatedBy(cal.Name)
+		startsAt, endsAt := strfmt.DateTime(e.Start), strfmt.DateTime(e.End)
+		return &amv2.GettableSilence{
+			ID:     &id,
+			Status: &amv2.SilenceStatus{State: &state},
+			Silence: amv2.Silence{
+				Comment:   &comment,
+				CreatedBy: &createdBy,
+				StartsAt:  &startsAt,
+				EndsAt:    &endsAt,
+				Matchers:  toSilenceMatchers(cal.Matchers),
+			},
+		}
+	}
+
+	unchanged := event("unchanged", now.Add(time.Hour), time.Hour)
+	moved := event("moved", now.Add(2*time.Hour), time.Hour)
+	removed := event("removed", now.Add(3*time.Hour), time.Hour)
+	cancelled := event("cancelled", now.Add(4*time.Hour), time.Hour)
+	newEvent := event("new", now.Add(5*time.Hour), time.Hour)
+	past := event("past", now.Add(-2*time.Hour), time.Hour)
+	later := event("later", now.Add(30*24*time.Hour), time.Hour)
+	other := silence("other", removed, amv2.SilenceStatusStatePending)
+	otherCreatedBy := "someone"
+	other.CreatedBy = &otherCreatedBy
+
+	movedNow := moved
+	movedNow.End = movedNow.End.Add(time.Hour)
+	cancelledNow := cancelled
+	cancelledNow.Cancelled = true
+
+	silences := apimodels.GettableSilences{
+		silence("1", unchanged, amv2.SilenceStatusStatePending),
+		silence("2", moved, amv2.SilenceStatusStatePending),
+		silence("3", removed, amv2.SilenceStatusStatePending),
+		silence("4", cancelled, amv2.SilenceStatusStatePending),
+		silence("5", past, amv2.SilenceStatusStateExpired),
+		other,
+	}
+	upserts, expire := planMaintenanceSilences(cal, []icalEvent{unchanged, movedNow, cancelledNow, newEvent, past, later}, silences, now)
+
+	require.Len(t, upserts, 2)
+	require.Equal(t, "2", upserts[0].ID)
+	require.Equal(t, strfmt.DateTime(movedNow.End), *upserts[0].EndsAt)
+	require.Empty(t, upserts[1].ID)
+	require.Equal(t, "Change new [new]", *upserts[1].Comment)
+	require.Equal(t, "maintenance calendar changes", *upserts[1].CreatedBy)
+	require.Len(t, upserts[1].Matchers, 1)
+	require.Equal(t, []string{"3", "4"}, expire)
+}
@@ -83,6 +83,11 @@ type MultiOrgAlertmanager struct {
 
 	metrics *metrics.MultiOrgAlertmanager
 	ns      notifications.Service
+
+	silenceMetadata store.SilenceMetadataStore
+	usage           UsageRecorder
+
+	maintenanceCalendars *maintenanceCalendars
 }
 
 type OrgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
@@ -122,7 +127,12 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 	// Set up the default per tenant Alertmanager factory.
 	moa.factory = func(ctx context.Context, orgID int64) (Alertmanager, error) {
 		m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID))
-		return NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+		am, err := NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+		if err != nil {
+			return nil, err
+		}
+		am.setUsageRecorder(moa.usage)
+		return am, nil
 	}
 
 	for _, opt := range opts {
@@ -208,6 +218,8 @@ func (moa *MultiOrgAlertmanager) Run(ctx context.Context) error {
 			if err := moa.LoadAndSyncAlertmanagersForOrgs(ctx); err != nil {
 				moa.logger.Error("Error while synchronizing Alertmanager orgs", "error", err)
 			}
+			moa.notifyExpiringSilences(ctx)
+			moa.startMaintenanceCalendarSync(ctx)
 		}
 	}
 }
@@ -324,6 +336,39 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 	moa.cleanupOrphanLocalOrgState(ctx, orgsFound)
 }
 
+// RemoveAlertmanager stops the Alertmanager of a deleted organization without waiting for the next sync, and
+// removes its state. The silences and notification log are removed from the key-value store only once the
+// Alertmanager is stopped, as it would otherwise persist them again.
+func (moa *MultiOrgAlertmanager) RemoveAlertmanager(ctx context.Context, orgID int64) error {
+	moa.alertmanagersMtx.Lock()
+	am, found := moa.alertmanagers[orgID]
+	if found {
+		delete(moa.alertmanagers, orgID)
+		moa.metrics.RemoveOrgRegistry(orgID)
+		moa.metrics.ActiveConfigurations.Set(float64(len(moa.alertmanagers)))
+	}
+	moa.alertmanagersMtx.Unlock()
+
+	if found {
+		moa.logger.Info("Stopping Alertmanager of deleted org", "org", orgID)
+		am.StopAndWait()
+		am.CleanUp()
+	}
+
+	for _, fileName := range []string{NotificationLogFilename, SilencesFilename} {
+		keys, err := moa.kvStore.Keys(ctx, orgID, KVNamespace, fileName)
+		if err != nil {
+			return fmt.Errorf("failed to fetch the %s of org %d: %w", fileName, orgID, err)
+		}
+		for _, key := range keys {
+			if err := moa.kvStore.Del(ctx, key.OrgId, key.Namespace, key.Key); err != nil {
+				return fmt.Errorf("failed to delete the %s of org %d: %w", fileName, orgID, err)
+			}
+		}
+	}
+	return nil
+}
+
 // cleanupOrphanLocalOrgState will check if there is any organization on
 // disk that is not part of the active organizations. If this is the case
 // it will delete the local state from disk.
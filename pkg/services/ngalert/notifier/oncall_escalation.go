@@ -0,0 +1,106 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+)
+
+const onCallIntegrationType = "oncall"
+
+// onCallEscalationSettings are the settings of a Grafana OnCall integration that map the labels of the alerts to
+// the escalation metadata of the notifications, so that OnCall can route them without parsing the message.
+type onCallEscalationSettings struct {
+	// TeamLabel is the label whose value is the team of the notification.
+	TeamLabel string `json:"escalationTeamLabel,omitempty"`
+	// PriorityLabel is the label whose value is mapped to the priority of the notification.
+	PriorityLabel string `json:"escalationPriorityLabel,omitempty"`
+	// Priorities maps the values of the priority label to the priorities of OnCall. Without mapping, the value of
+	// the label is the priority.
+	Priorities map[string]string `json:"escalationPriorities,omitempty"`
+	// DefaultPriority is the priority of the notifications whose priority label is missing or not mapped.
+	DefaultPriority string `json:"escalationDefaultPriority,omitempty"`
+}
+
+// onCallEscalation is the escalation metadata added to the payload of the notifications sent to Grafana OnCall.
+type onCallEscalation struct {
+	Team     string `json:"team,omitempty"`
+	Priority string `json:"priority,omitempty"`
+}
+
+func (s onCallEscalationSettings) enabled() bool {
+	return s.TeamLabel != "" || s.PriorityLabel != "" || s.DefaultPriority != ""
+}
+
+// escalation derives the escalation metadata from the labels shared by all the alerts of the notification. Alerts
+// should be grouped by the team and priority labels for the metadata to be set.
+func (s onCallEscalationSettings) escalation(commonLabels map[string]string) onCallEscalation {
+	result := onCallEscalation{Priority: s.DefaultPriority}
+	if s.TeamLabel != "" {
+		result.Team = commonLabels[s.TeamLabel]
+	}
+	if value, ok := commonLabels[s.PriorityLabel]; ok && s.PriorityLabel != "" {
+		if len(s.Priorities) == 0 {
+			result.Priority = value
+		} else if priority, ok := s.Priorities[value]; ok {
+			result.Priority = priority
+		}
+	}
+	return result
+}
+
+// onCallEscalationSettingsOf returns the escalation settings of the Grafana OnCall integrations of the receiver
+// that have them, by UID.
+func onCallEscalationSettingsOf(receiver *alertingNotify.APIReceiver) (map[string]onCallEscalationSettings, error) {
+	result := make(map[string]onCallEscalationSettings)
+	for _, integration := range receiver.Integrations {
+		if integration.Type != onCallIntegrationType || len(integration.Settings) == 0 {
+			continue
+		}
+		var settings onCallEscalationSettings
+		if err := json.Unmarshal(integration.Settings, &settings); err != nil {
+			return nil, fmt.Errorf("invalid escalation settings of integration %q of receiver %s: %w", integration.Name, receiver.Name, err)
+		}
+		if settings.enabled() {
+			result[integration.UID] = settings
+		}
+	}
+	return result, nil
+}
+
+// onCallEscalationSender adds the escalation metadata to the payload of the notifications of a Grafana OnCall
+// integration.
+type onCallEscalationSender struct {
+	receivers.WebhookSender
+	settings onCallEscalationSettings
+}
+
+func (s onCallEscalationSender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSettings) error {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(cmd.Body), &payload); err != nil {
+		return fmt.Errorf("failed to add the escalation metadata to the notification: %w", err)
+	}
+	var commonLabels map[string]string
+	if raw, ok := payload["commonLabels"]; ok {
+		if err := json.Unmarshal(raw, &commonLabels); err != nil {
+			return fmt.Errorf("failed to add the escalation metadata to the notification: %w", err)
+		}
+	}
+
+	escalation, err := json.Marshal(s.settings.escalation(commonLabels))
+	if err != nil {
+		return err
+	}
+	payload["escalation"] = escalation
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	withEscalation := *cmd
+	withEscalation.Body = string(body)
+	return s.WebhookSender.SendWebhook(ctx, &withEscalation)
+}
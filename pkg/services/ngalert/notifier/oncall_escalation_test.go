@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWebhookSender struct {
+	sent []*receivers.SendWebhookSettings
+}
+
+func (f *fakeWebhookSender) SendWebhook(_ context.Context, cmd *receivers.SendWebhookSettings) error {
+	f.sent = append(f.sent, cmd)
+	return nil
+}
+
+func TestOnCallEscalationSettings(t *testing.T) {
+	settings := onCallEscalationSettings{
+		TeamLabel:       "team",
+		PriorityLabel:   "severity",
+		Priorities:      map[string]string{"critical": "P1", "warning": "P3"},
+		DefaultPriority: "P4",
+	}
+
+	testCases := []struct {
+		name     string
+		settings onCallEscalationSettings
+		labels   map[string]string
+		expected onCallEscalation
+	}{
+		{
+			name:     "should map the priority label",
+			settings: settings,
+			labels:   map[string]string{"team": "payments", "severity": "critical"},
+			expected: onCallEscalation{Team: "payments", Priority: "P1"},
+		},
+		{
+			name:     "should use the default priority if the priority label is not mapped",
+			settings: settings,
+			labels:   map[string]string{"team": "payments", "severity": "info"},
+			expected: onCallEscalation{Team: "payments", Priority: "P4"},
+		},
+		{
+			name:     "should use the default priority if the labels are not common to all alerts",
+			settings: settings,
+			labels:   map[string]string{},
+			expected: onCallEscalation{Priority: "P4"},
+		},
+		{
+			name:     "should use the value of the priority label without mapping",
+			settings: onCallEscalationSettings{PriorityLabel: "severity"},
+			labels:   map[string]string{"team": "payments", "severity": "critical"},
+			expected: onCallEscalation{Priority: "critical"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.settings.escalation(tc.labels))
+		})
+	}
+}
+
+func TestOnCallEscalationSettingsOf(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		ConfigReceiver: alertingNotify.ConfigReceiver{Name: "oncall"},
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+			{UID: "with-escalation", Type: "oncall", Settings: json.RawMessage(`{"url": "http://oncall", "escalationTeamLabel": "team"}`)},
+			{UID: "without-escalation", Type: "oncall", Settings: json.RawMessage(`{"url": "http://oncall"}`)},
+			{UID: "webhook", Type: "webhook", Settings: json.RawMessage(`{"url": "http://webhook", "escalationTeamLabel": "team"}`)},
+		}},
+	}
+	settings, err := onCallEscalationSettingsOf(receiver)
+	require.NoError(t, err)
+	require.Equal(t, map[string]onCallEscalationSettings{"with-escalation": {TeamLabel: "team"}}, settings)
+
+	receiver.Integrations[1].Settings = json.RawMessage(`{"url": "http://oncall", "escalationPriorities": "P1"}`)
+	_, err = onCallEscalationSettingsOf(receiver)
+	require.ErrorContains(t, err, "invalid escalation settings")
+}
+
+func TestOnCallEscalationSender(t *testing.T) {
+	next := &fakeWebhookSender{}
+	s := onCallEscalationSender{
+		WebhookSender: next,
+		settings:      onCallEscalationSettings{TeamLabel: "team", PriorityLabel: "severity", Priorities: map[string]string{"critical": "P1"}},
+	}
+	cmd := &receivers.SendWebhookSettings{
+		URL:  "http://oncall",
+		Body: `{"version":"1","title":"[FIRING:1] cpu","commonLabels":{"alertname":"cpu","team":"payments","severity":"critical"}}`,
+	}
+	require.NoError(t, s.SendWebhook(context.Background(), cmd))
+
+	require.Len(t, next.sent, 1)
+	require.Equal(t, "http://oncall", next.sent[0].URL)
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(next.sent[0].Body), &payload))
+	require.Equal(t, map[string]any{"team": "payments", "priority": "P1"}, payload["escalation"])
+	require.Equal(t, "[FIRING:1] cpu", payload["title"])
+	require.NotContains(t, cmd.Body, "escalation", "the settings of the notifier should not be changed")
+}
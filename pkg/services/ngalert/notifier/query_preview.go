@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	alertingModels "github.com/grafana/alerting/models"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// queryPreviewSettings are the settings of an integration that attach the preview of the queries of the alert
+// rule to the notifications, when the alert has no screenshot.
+type queryPreviewSettings struct {
+	QueryPreview bool `json:"queryPreview,omitempty"`
+}
+
+// queryPreviewIntegrationsOf returns the UIDs of the integrations of the receiver that attach query previews.
+func queryPreviewIntegrationsOf(receiver *alertingNotify.APIReceiver) (map[string]struct{}, error) {
+	result := make(map[string]struct{})
+	for _, integration := range receiver.Integrations {
+		if len(integration.Settings) == 0 {
+			continue
+		}
+		var settings queryPreviewSettings
+		if err := json.Unmarshal(integration.Settings, &settings); err != nil {
+			return nil, fmt.Errorf("invalid query preview settings of integration %q of receiver %s: %w", integration.Name, receiver.Name, err)
+		}
+		if settings.QueryPreview {
+			result[integration.UID] = struct{}{}
+		}
+	}
+	return result, nil
+}
+
+// withQueryPreviews wraps the integrations of the receiver that attach query previews to the notifications.
+func withQueryPreviews(receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration) ([]*alertingNotify.Integration, error) {
+	enabled, err := queryPreviewIntegrationsOf(receiver)
+	if err != nil || len(enabled) == 0 {
+		return integrations, err
+	}
+	uids := integrationUIDs(receiver)
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, i := range integrations {
+		typeUIDs := uids[strings.ToLower(i.Name())]
+		if i.Index() >= len(typeUIDs) {
+			result = append(result, i)
+			continue
+		}
+		if _, ok := enabled[typeUIDs[i.Index()]]; !ok {
+			result = append(result, i)
+			continue
+		}
+		n := &queryPreviewNotifier{integration: i}
+		result = append(result, alertingNotify.NewIntegration(n, n, i.Name(), i.Index(), receiver.Name))
+	}
+	return result, nil
+}
+
+// queryPreviewNotifier uses the query preview of the alerts as their image, so that the integration attaches or
+// links it like a screenshot.
+type queryPreviewNotifier struct {
+	integration *alertingNotify.Integration
+}
+
+func (n *queryPreviewNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	return n.integration.Notify(ctx, withQueryPreviewImages(alerts)...)
+}
+
+func (n *queryPreviewNotifier) SendResolved() bool {
+	return n.integration.SendResolved()
+}
+
+// withQueryPreviewImages returns the alerts where the alerts that have a query preview but no image are replaced
+// with copies whose image is the query preview. The alerts are shared with the other integrations and are not modified.
+func withQueryPreviewImages(alerts []*types.Alert) []*types.Alert {
+	result := make([]*types.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		token := alert.Annotations[ngmodels.QueryPreviewTokenAnnotation]
+		_, hasImage := alert.Annotations[alertingModels.ImageTokenAnnotation]
+		if token == "" || hasImage {
+			result = append(result, alert)
+			continue
+		}
+		withPreview := *alert
+		withPreview.Annotations = alert.Annotations.Clone()
+		withPreview.Annotations[alertingModels.ImageTokenAnnotation] = token
+		result = append(result, &withPreview)
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package notifier
+
+import (
+	"encoding/json"
+	"testing"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryPreviewIntegrationsOf(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		ConfigReceiver: alertingNotify.ConfigReceiver{Name: "team"},
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+			{UID: "with-preview", Type: "slack", Settings: json.RawMessage(`{"recipient": "#alerts", "queryPreview": true}`)},
+			{UID: "without-preview", Type: "slack", Settings: json.RawMessage(`{"recipient": "#alerts"}`)},
+			{UID: "disabled-preview", Type: "email", Settings: json.RawMessage(`{"addresses": "team@example.com", "queryPreview": false}`)},
+		}},
+	}
+	enabled, err := queryPreviewIntegrationsOf(receiver)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"with-preview": {}}, enabled)
+
+	receiver.Integrations[1].Settings = json.RawMessage(`{"queryPreview": "yes"}`)
+	_, err = queryPreviewIntegrationsOf(receiver)
+	require.ErrorContains(t, err, "invalid query preview settings")
+}
+
+func TestWithQueryPreviewImages(t *testing.T) {
+	withPreview := &types.Alert{Alert: model.Alert{
+		Labels:      model.LabelSet{"alertname": "WithPreview"},
+		Annotations: model.LabelSet{"__alertQueryPreviewToken__": "preview"},
+	}}
+	withScreenshot := &types.Alert{Alert: model.Alert{
+		Labels:      model.LabelSet{"alertname": "WithScreenshot"},
+		Annotations: model.LabelSet{"__alertQueryPreviewToken__": "preview", "__alertImageToken__": "screenshot"},
+	}}
+	withoutPreview := &types.Alert{Alert: model.Alert{
+		Labels:      model.LabelSet{"alertname": "WithoutPreview"},
+		Annotations: model.LabelSet{"summary": "firing"},
+	}}
+
+	result := withQueryPreviewImages([]*types.Alert{withPreview, withScreenshot, withoutPreview})
+	require.Len(t, result, 3)
+	require.Equal(t, model.LabelValue("preview"), result[0].Annotations["__alertImageToken__"])
+	require.Same(t, withScreenshot, result[1])
+	require.Same(t, withoutPreview, result[2])
+
+	// the alerts are shared with the other integrations of the receiver
+	require.NotContains(t, withPreview.Annotations, model.LabelName("__alertImageToken__"))
+}
@@ -0,0 +1,356 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// maxSummarizedAlerts is the maximum number of alerts held back by a limiter with the summary overflow.
+const maxSummarizedAlerts = 1000
+
+// errNotificationRateLimited is returned for notifications that were not sent because of the rate limit, so that
+// the notification log does not record them as sent.
+var errNotificationRateLimited = errors.New("notification rate limit exceeded")
+
+// heldNotification contains the alerts of the notifications of an alert group that exceeded the limit.
+type heldNotification struct {
+	// ctx is the context of the last held notification, which carries the group key and labels used by the integration.
+	ctx    context.Context
+	alerts map[model.Fingerprint]*types.Alert
+}
+
+// notificationLimiter counts the notifications sent by an integration in fixed windows of time.
+type notificationLimiter struct {
+	limit    int
+	interval time.Duration
+	overflow apimodels.NotificationOverflow
+
+	mtx         sync.Mutex
+	windowStart time.Time
+	count       int
+	// held contains the notifications that exceeded the limit by alert group, if the overflow is summary.
+	held      map[string]*heldNotification
+	heldCount int
+	// flushing is true while a flush of the held notifications is scheduled.
+	flushing bool
+	// flushed contains the alerts sent by a flush by alert group, with whether they were resolved.
+	flushed map[string]map[model.Fingerprint]bool
+}
+
+func newNotificationLimiter(l apimodels.NotificationRateLimit) *notificationLimiter {
+	overflow := l.Overflow
+	if overflow == "" {
+		overflow = apimodels.NotificationOverflowDrop
+	}
+	return &notificationLimiter{
+		limit:    l.Limit,
+		interval: time.Duration(l.Interval),
+		overflow: overflow,
+		held:     make(map[string]*heldNotification),
+		flushed:  make(map[string]map[model.Fingerprint]bool),
+	}
+}
+
+// allow returns true if a notification can be sent at the given time, and counts it.
+// Otherwise, it returns how long to wait until the next window starts.
+func (l *notificationLimiter) allow(now time.Time) (bool, time.Duration) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if now.Sub(l.windowStart) >= l.interval {
+		l.windowStart = now
+		l.count = 0
+	}
+	if l.count < l.limit {
+		l.count++
+		return true, 0
+	}
+	return false, l.windowStart.Add(l.interval).Sub(now)
+}
+
+// hold keeps the alerts of the group until the next notification is allowed. Newer versions of an alert replace older ones.
+// It returns true if the caller must schedule a flush of the held notifications.
+func (l *notificationLimiter) hold(ctx context.Context, groupKey string, alerts []*types.Alert) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	h, ok := l.held[groupKey]
+	if !ok {
+		h = &heldNotification{alerts: make(map[model.Fingerprint]*types.Alert)}
+		l.held[groupKey] = h
+	}
+	h.ctx = context.WithoutCancel(ctx)
+	for _, a := range alerts {
+		fp := a.Fingerprint()
+		if _, ok := h.alerts[fp]; !ok {
+			if l.heldCount >= maxSummarizedAlerts {
+				continue
+			}
+			l.heldCount++
+		}
+		h.alerts[fp] = a
+	}
+	delete(l.flushed, groupKey)
+	scheduleFlush := !l.flushing
+	l.flushing = true
+	return scheduleFlush
+}
+
+// release adds the held alerts of the group that are not part of the notification to it.
+func (l *notificationLimiter) release(groupKey string, alerts []*types.Alert) []*types.Alert {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	h, ok := l.held[groupKey]
+	if !ok {
+		return alerts
+	}
+	delete(l.held, groupKey)
+	l.heldCount -= len(h.alerts)
+	for _, a := range alerts {
+		delete(h.alerts, a.Fingerprint())
+	}
+	result := make([]*types.Alert, 0, len(alerts)+len(h.alerts))
+	result = append(result, alerts...)
+	for _, a := range h.alerts {
+		result = append(result, a)
+	}
+	return result
+}
+
+// takeHeld removes a held notification and returns it, or false if there is none.
+// If there is none, the scheduled flush is over.
+func (l *notificationLimiter) takeHeld() (string, *heldNotification, bool) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for key, h := range l.held {
+		delete(l.held, key)
+		l.heldCount -= len(h.alerts)
+		return key, h, true
+	}
+	l.flushing = false
+	return "", nil, false
+}
+
+// putBack returns a held notification that could not be flushed yet.
+func (l *notificationLimiter) putBack(groupKey string, h *heldNotification) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if current, ok := l.held[groupKey]; ok {
+		// alerts held since the flush started are newer
+		for fp, a := range h.alerts {
+			if _, ok := current.alerts[fp]; !ok {
+				current.alerts[fp] = a
+				l.heldCount++
+			}
+		}
+		return
+	}
+	l.held[groupKey] = h
+	l.heldCount += len(h.alerts)
+}
+
+// markFlushed records the alerts sent by a flush, so that the next notification of the group with the same alerts is not sent again.
+func (l *notificationLimiter) markFlushed(groupKey string, alerts []*types.Alert) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	sent := make(map[model.Fingerprint]bool, len(alerts))
+	for _, a := range alerts {
+		sent[a.Fingerprint()] = a.Resolved()
+	}
+	l.flushed[groupKey] = sent
+}
+
+// wasFlushed returns true if the alerts were already sent by a flush of the group, and forgets the flush.
+func (l *notificationLimiter) wasFlushed(groupKey string, alerts []*types.Alert) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	sent, ok := l.flushed[groupKey]
+	if !ok {
+		return false
+	}
+	delete(l.flushed, groupKey)
+	for _, a := range alerts {
+		if resolved, ok := sent[a.Fingerprint()]; !ok || resolved != a.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// rateLimitedNotifier sends notifications through an integration unless its limiter does not allow it.
+type rateLimitedNotifier struct {
+	integration *alertingNotify.Integration
+	limiter     *notificationLimiter
+	receiver    string
+	logger      log.Logger
+	now         func() time.Time
+	afterFunc   func(time.Duration, func())
+}
+
+func (n *rateLimitedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	groupKey, _ := notify.GroupKey(ctx)
+	if n.limiter.overflow == apimodels.NotificationOverflowSummary && n.limiter.wasFlushed(groupKey, alerts) {
+		// the alerts were sent when the held notifications were flushed, the notification log can record them
+		return false, nil
+	}
+	for {
+		ok, wait := n.limiter.allow(n.now())
+		if ok {
+			break
+		}
+		switch n.limiter.overflow {
+		case apimodels.NotificationOverflowQueue:
+			n.logger.Debug("Notification rate limit exceeded, delaying notification", "receiver", n.receiver, "integration", n.integration.Name(), "wait", wait)
+			select {
+			case <-ctx.Done():
+				return true, fmt.Errorf("notification delayed by the rate limit of the receiver was not sent: %w", ctx.Err())
+			case <-time.After(wait):
+			}
+			continue
+		case apimodels.NotificationOverflowSummary:
+			n.logger.Info("Notification rate limit exceeded, alerts are added to the next notification", "receiver", n.receiver, "integration", n.integration.Name(), "alerts", len(alerts))
+			if n.limiter.hold(ctx, groupKey, alerts) {
+				n.afterFunc(wait, n.flush)
+			}
+			return false, fmt.Errorf("%w: alerts are added to the next notification", errNotificationRateLimited)
+		default:
+			n.logger.Warn("Notification rate limit exceeded, dropping notification", "receiver", n.receiver, "integration", n.integration.Name(), "alerts", len(alerts))
+			return false, fmt.Errorf("%w: notification dropped", errNotificationRateLimited)
+		}
+	}
+	if n.limiter.overflow == apimodels.NotificationOverflowSummary {
+		alerts = n.limiter.release(groupKey, alerts)
+	}
+	return n.integration.Notify(ctx, alerts...)
+}
+
+// flush sends the held notifications once the limit allows it, so that they do not wait for the next notification of their group.
+func (n *rateLimitedNotifier) flush() {
+	for {
+		groupKey, h, ok := n.limiter.takeHeld()
+		if !ok {
+			return
+		}
+		allowed, wait := n.limiter.allow(n.now())
+		if !allowed {
+			n.limiter.putBack(groupKey, h)
+			n.afterFunc(wait, n.flush)
+			return
+		}
+		alerts := make([]*types.Alert, 0, len(h.alerts))
+		for _, a := range h.alerts {
+			alerts = append(alerts, a)
+		}
+		if _, err := n.integration.Notify(h.ctx, alerts...); err != nil {
+			n.logger.Error("Failed to send the notification held by the rate limit", "receiver", n.receiver, "integration", n.integration.Name(), "alerts", len(alerts), "error", err)
+			continue
+		}
+		n.limiter.markFlushed(groupKey, alerts)
+	}
+}
+
+func (n *rateLimitedNotifier) SendResolved() bool {
+	return n.integration.SendResolved()
+}
+
+type limiterKey struct {
+	receiver    string
+	integration string
+	index       int
+}
+
+// notificationRateLimiters keeps the limiters of the integrations of an Alertmanager, so that their state
+// survives configuration changes that do not change the limits.
+type notificationRateLimiters struct {
+	logger    log.Logger
+	now       func() time.Time
+	afterFunc func(time.Duration, func())
+
+	mtx      sync.Mutex
+	limits   []apimodels.NotificationRateLimit
+	limiters map[limiterKey]*notificationLimiter
+}
+
+func newNotificationRateLimiters(logger log.Logger) *notificationRateLimiters {
+	return &notificationRateLimiters{
+		logger: logger,
+		now:    time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		limiters: make(map[limiterKey]*notificationLimiter),
+	}
+}
+
+// setLimits replaces the limits, and drops the state of the limiters whose limit changed or was removed.
+func (r *notificationRateLimiters) setLimits(limits []apimodels.NotificationRateLimit) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.limits = limits
+	for key, limiter := range r.limiters {
+		l, ok := r.limitFor(key.receiver, key.integration)
+		if !ok || l.Limit != limiter.limit || time.Duration(l.Interval) != limiter.interval || newNotificationLimiter(l).overflow != limiter.overflow {
+			delete(r.limiters, key)
+		}
+	}
+}
+
+// limitFor returns the limit of the integration. A limit for the type of the integration takes precedence over the limit for the whole receiver.
+func (r *notificationRateLimiters) limitFor(receiver, integration string) (apimodels.NotificationRateLimit, bool) {
+	var result apimodels.NotificationRateLimit
+	found := false
+	for _, l := range r.limits {
+		if l.Receiver != receiver {
+			continue
+		}
+		if l.Integration == integration {
+			return l, true
+		}
+		if l.Integration == "" {
+			result, found = l, true
+		}
+	}
+	return result, found
+}
+
+// wrap returns the integrations of the receiver, limited by the configured limits.
+func (r *notificationRateLimiters) wrap(receiver string, integrations []*alertingNotify.Integration) []*alertingNotify.Integration {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if len(r.limits) == 0 {
+		return integrations
+	}
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, i := range integrations {
+		l, ok := r.limitFor(receiver, i.Name())
+		if !ok {
+			result = append(result, i)
+			continue
+		}
+		key := limiterKey{receiver: receiver, integration: i.Name(), index: i.Index()}
+		limiter, ok := r.limiters[key]
+		if !ok {
+			limiter = newNotificationLimiter(l)
+			r.limiters[key] = limiter
+		}
+		n := &rateLimitedNotifier{
+			integration: i,
+			limiter:     limiter,
+			receiver:    receiver,
+			logger:      r.logger,
+			now:         r.now,
+			afterFunc:   r.afterFunc,
+		}
+		result = append(result, alertingNotify.NewIntegration(n, n, i.Name(), i.Index(), receiver))
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type countingNotifier struct {
+	notifications [][]*types.Alert
+}
+
+func (n *countingNotifier) Notify(_ context.Context, alerts ...*types.Alert) (bool, error) {
+	n.notifications = append(n.notifications, alerts)
+	return false, nil
+}
+
+func (n *countingNotifier) SendResolved() bool {
+	return true
+}
+
+func newRateLimitTestAlert(name string) *types.Alert {
+	return &types.Alert{Alert: model.Alert{Labels: model.LabelSet{model.AlertNameLabel: model.LabelValue(name)}}}
+}
+
+func TestNotificationRateLimiters(t *testing.T) {
+	now := time.Now()
+	var scheduled []func()
+	setup := func(limits ...apimodels.NotificationRateLimit) (*notificationRateLimiters, *countingNotifier, *alertingNotify.Integration) {
+		scheduled = nil
+		r := newNotificationRateLimiters(log.NewNopLogger())
+		r.now = func() time.Time { return now }
+		r.afterFunc = func(_ time.Duration, f func()) {
+			scheduled = append(scheduled, f)
+		}
+		r.setLimits(limits)
+		n := &countingNotifier{}
+		integrations := r.wrap("team-a", []*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "slack", 0, "team-a")})
+		require.Len(t, integrations, 1)
+		return r, n, integrations[0]
+	}
+
+	t.Run("integrations without limits are not wrapped", func(t *testing.T) {
+		r := newNotificationRateLimiters(log.NewNopLogger())
+		r.setLimits([]apimodels.NotificationRateLimit{{Receiver: "team-b", Limit: 1, Interval: model.Duration(time.Minute)}})
+		n := &countingNotifier{}
+		i := alertingNotify.NewIntegration(n, n, "slack", 0, "team-a")
+		require.Same(t, i, r.wrap("team-a", []*alertingNotify.Integration{i})[0])
+	})
+
+	t.Run("notifications exceeding the limit are dropped", func(t *testing.T) {
+		_, n, i := setup(apimodels.NotificationRateLimit{Receiver: "team-a", Limit: 2, Interval: model.Duration(time.Minute)})
+		for j := 0; j < 2; j++ {
+			_, err := i.Notify(context.Background(), newRateLimitTestAlert("a"))
+			require.NoError(t, err)
+		}
+		// the error keeps the notification log from recording the dropped notification as sent
+		retry, err := i.Notify(context.Background(), newRateLimitTestAlert("a"))
+		require.ErrorIs(t, err, errNotificationRateLimited)
+		require.False(t, retry)
+		require.Len(t, n.notifications, 2)
+
+		now = now.Add(time.Minute)
+		_, err := i.Notify(context.Background(), newRateLimitTestAlert("a"))
+		require.NoError(t, err)
+		require.Len(t, n.notifications, 3)
+	})
+
+	t.Run("alerts of notifications exceeding the limit are added to the next notification of their group", func(t *testing.T) {
+		_, n, i := setup(apimodels.NotificationRateLimit{Receiver: "team-a", Integration: "slack", Limit: 1, Interval: model.Duration(time.Minute), Overflow: apimodels.NotificationOverflowSummary})
+		group1 := notify.WithGroupKey(context.Background(), "group-1")
+		group2 := notify.WithGroupKey(context.Background(), "group-2")
+		_, err := i.Notify(group1, newRateLimitTestAlert("a"))
+		require.NoError(t, err)
+		_, err = i.Notify(group1, newRateLimitTestAlert("b"), newRateLimitTestAlert("c"))
+		require.ErrorIs(t, err, errNotificationRateLimited)
+		_, err = i.Notify(group2, newRateLimitTestAlert("x"))
+		require.ErrorIs(t, err, errNotificationRateLimited)
+		require.Len(t, n.notifications, 1)
+		require.Len(t, scheduled, 1, "a single flush is scheduled")
+
+		now = now.Add(time.Minute)
+		_, err = i.Notify(group1, newRateLimitTestAlert("c"), newRateLimitTestAlert("d"))
+		require.NoError(t, err)
+		require.Len(t, n.notifications, 2)
+		require.Len(t, n.notifications[1], 3, "only the alerts held for the group are added")
+	})
+
+	t.Run("held notifications are flushed once the limit allows it", func(t *testing.T) {
+		_, n, i := setup(apimodels.NotificationRateLimit{Receiver: "team-a", Integration: "slack", Limit: 1, Interval: model.Duration(time.Minute), Overflow: apimodels.NotificationOverflowSummary})
+		group1 := notify.WithGroupKey(context.Background(), "group-1")
+		group2 := notify.WithGroupKey(context.Background(), "group-2")
+		_, err := i.Notify(group1, newRateLimitTestAlert("a"))
+		require.NoError(t, err)
+		_, err = i.Notify(group1, newRateLimitTestAlert("b"))
+		require.ErrorIs(t, err, errNotificationRateLimited)
+		_, err = i.Notify(group2, newRateLimitTestAlert("x"))
+		require.ErrorIs(t, err, errNotificationRateLimited)
+		require.Len(t, scheduled, 1)
+
+		// one group is flushed, the other one waits for the next interval
+		now = now.Add(time.Minute)
+		scheduled[0]()
+		require.Len(t, n.notifications, 2)
+		require.Len(t, scheduled, 2)
+
+		now = now.Add(time.Minute)
+		scheduled[1]()
+		require.Len(t, n.notifications, 3)
+		require.Len(t, scheduled, 2)
+
+		// the next notification of a group with the flushed alerts is not sent again
+		now = now.Add(time.Minute)
+		_, err = i.Notify(group2, newRateLimitTestAlert("x"))
+		require.NoError(t, err)
+		_, err = i.Notify(group1, newRateLimitTestAlert("b"))
+		require.NoError(t, err)
+		require.Len(t, n.notifications, 3)
+	})
+
+	t.Run("queued notifications are not sent if the context is done", func(t *testing.T) {
+		_, n, i := setup(apimodels.NotificationRateLimit{Receiver: "team-a", Limit: 1, Interval: model.Duration(time.Hour), Overflow: apimodels.NotificationOverflowQueue})
+		_, err := i.Notify(context.Background(), newRateLimitTestAlert("a"))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		retry, err := i.Notify(ctx, newRateLimitTestAlert("b"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.True(t, retry)
+		require.Len(t, n.notifications, 1)
+	})
+
+	t.Run("state is kept unless the limit changes", func(t *testing.T) {
+		limit := apimodels.NotificationRateLimit{Receiver: "team-a", Limit: 1, Interval: model.Duration(time.Minute)}
+		r, _, _ := setup(limit)
+		require.Len(t, r.limiters, 1)
+
+		r.setLimits([]apimodels.NotificationRateLimit{limit})
+		require.Len(t, r.limiters, 1)
+
+		limit.Limit = 2
+		r.setLimits([]apimodels.NotificationRateLimit{limit})
+		require.Empty(t, r.limiters)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"slices"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -22,6 +23,14 @@ var (
 	ErrNotFound = errors.New("not found") // TODO: convert to errutil
 )
 
+var (
+	// readReceiversEval is the evaluator of the access to receivers with redacted secure settings.
+	readReceiversEval = accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversList)
+	// readReceiverSecretsEval is the evaluator of the access to the decrypted secure settings of receivers.
+	// It is deliberately separate from the read access so exposing secrets can be granted on its own.
+	readReceiverSecretsEval = accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversReadSecrets)
+)
+
 // ReceiverService is the service for managing alertmanager receivers.
 type ReceiverService struct {
 	ac                accesscontrol.AccessControl
@@ -63,23 +72,35 @@ func NewReceiverService(
 	}
 }
 
-func (rs *ReceiverService) shouldDecrypt(ctx context.Context, user identity.Requester, name string, reqDecrypt bool) (bool, error) {
-	// TODO: migrate to new permission
-	eval := accesscontrol.EvalAny(
-		accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversReadSecrets),
-		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadSecrets),
-	)
+// shouldDecrypt returns true if the secure settings of the receiver must be decrypted, and the action that grants it.
+func (rs *ReceiverService) shouldDecrypt(ctx context.Context, user identity.Requester, orgID int64, name string, reqDecrypt bool) (bool, string, error) {
+	if !reqDecrypt {
+		return false, "", nil
+	}
 
-	decryptAccess, err := rs.ac.Evaluate(ctx, user, eval)
+	decryptAccess, err := rs.ac.Evaluate(ctx, user, readReceiverSecretsEval)
 	if err != nil {
-		return false, err
+		return false, "", err
 	}
-
-	if reqDecrypt && !decryptAccess {
-		return false, ErrPermissionDenied
+	if decryptAccess {
+		return true, accesscontrol.ActionAlertingReceiversReadSecrets, nil
 	}
 
-	return decryptAccess && reqDecrypt, nil
+	rs.audit(ctx, user, orgID, "denied", "", name)
+	return false, "", ErrPermissionDenied
+}
+
+// audit records every attempt to read the decrypted secure settings of receivers in the request log and in the usage metrics.
+func (rs *ReceiverService) audit(ctx context.Context, user identity.Requester, orgID int64, result string, action string, names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	metrics.MAlertingReceiverSecretsReads.WithLabelValues(result, action).Add(float64(len(names)))
+	if user == nil {
+		return
+	}
+	namespace, id := user.GetNamespacedID()
+	rs.log.FromContext(ctx).Info("Read of decrypted receiver secure settings", "result", result, "action", action, "orgID", orgID, "namespace", namespace, "id", id, "login", user.GetLogin(), "receivers", names)
 }
 
 // GetReceiver returns a receiver by name.
@@ -108,13 +129,20 @@ func (rs *ReceiverService) GetReceiver(ctx context.Context, q models.GetReceiver
 	receivers := cfg.AlertmanagerConfig.Receivers
 	for _, r := range receivers {
 		if r.Name == q.Name {
-			decrypt, err := rs.shouldDecrypt(ctx, user, q.Name, q.Decrypt)
+			decrypt, action, err := rs.shouldDecrypt(ctx, user, q.OrgID, q.Name, q.Decrypt)
 			if err != nil {
 				return definitions.GettableApiReceiver{}, err
 			}
 			decryptFn := rs.decryptOrRedact(ctx, decrypt, q.Name, "")
 
-			return PostableToGettableApiReceiver(r, provenances, decryptFn, false)
+			res, err := PostableToGettableApiReceiver(r, provenances, decryptFn, false)
+			if err != nil {
+				return definitions.GettableApiReceiver{}, err
+			}
+			if decrypt {
+				rs.audit(ctx, user, q.OrgID, "granted", action, q.Name)
+			}
+			return res, nil
 		}
 	}
 
@@ -144,20 +172,21 @@ func (rs *ReceiverService) GetReceivers(ctx context.Context, q models.GetReceive
 		return nil, err
 	}
 
-	eval := accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversList)
-	listAccess, err := rs.ac.Evaluate(ctx, user, eval)
+	listAccess, err := rs.ac.Evaluate(ctx, user, readReceiversEval)
 	if err != nil {
 		return nil, err
 	}
 
 	var output []definitions.GettableApiReceiver
+	var decrypted []string
+	var decryptAction string
 	for i := q.Offset; i < len(cfg.AlertmanagerConfig.Receivers); i++ {
 		r := cfg.AlertmanagerConfig.Receivers[i]
 		if len(q.Names) > 0 && !slices.Contains(q.Names, r.Name) {
 			continue
 		}
 
-		decrypt, err := rs.shouldDecrypt(ctx, user, r.Name, q.Decrypt)
+		decrypt, action, err := rs.shouldDecrypt(ctx, user, q.OrgID, r.Name, q.Decrypt)
 		if err != nil {
 			return nil, err
 		}
@@ -171,12 +200,17 @@ func (rs *ReceiverService) GetReceivers(ctx context.Context, q models.GetReceive
 		}
 
 		output = append(output, res)
+		if decrypt {
+			decrypted = append(decrypted, r.Name)
+			decryptAction = action
+		}
 		// stop if we have reached the limit or we have found all the requested receivers
 		if (len(output) == q.Limit && q.Limit > 0) || (len(output) == len(q.Names)) {
 			break
 		}
 	}
 
+	rs.audit(ctx, user, q.OrgID, "granted", decryptAction, decrypted...)
 	return output, nil
 }
 
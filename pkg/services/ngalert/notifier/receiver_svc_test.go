@@ -84,6 +84,16 @@ func TestReceiverService_DecryptRedact(t *testing.T) {
 	}
 
 	secretUser := &user.SignedInUser{
+		OrgID: 1,
+		Permissions: map[int64]map[string][]string{
+			1: {
+				accesscontrol.ActionAlertingProvisioningRead:     nil,
+				accesscontrol.ActionAlertingReceiversReadSecrets: nil,
+			},
+		},
+	}
+
+	provisioningSecretUser := &user.SignedInUser{
 		OrgID: 1,
 		Permissions: map[int64]map[string][]string{
 			1: {
@@ -111,6 +121,12 @@ func TestReceiverService_DecryptRedact(t *testing.T) {
 			user:    readUser,
 			err:     ErrPermissionDenied,
 		},
+		{
+			name:    "service returns error when trying to decrypt with the provisioning secrets permission",
+			decrypt: true,
+			user:    provisioningSecretUser,
+			err:     ErrPermissionDenied,
+		},
 		{
 			name:    "service returns error if user is nil and decrypt is true",
 			decrypt: true,
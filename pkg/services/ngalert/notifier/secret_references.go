@@ -0,0 +1,348 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	secretProviderVault      = "vault"
+	secretProviderKubernetes = "kubernetes"
+
+	maxSecretResponseSize = 1 << 20
+
+	// orgIDPlaceholder is replaced by the ID of the organization in the allowed path prefixes.
+	orgIDPlaceholder = "{org_id}"
+)
+
+// secretReferenceRegexp matches secure settings that reference an external secret, e.g. $__vault{secret/data/slack:url}.
+var secretReferenceRegexp = regexp.MustCompile(`^\$__(vault|kubernetes)\{([^:{}]+):([^:{}]+)\}$`)
+
+type secretReference struct {
+	provider string
+	path     string
+	key      string
+}
+
+// parseSecretReference returns the reference described by the value, if it is one.
+func parseSecretReference(value string) (secretReference, bool) {
+	m := secretReferenceRegexp.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return secretReference{}, false
+	}
+	return secretReference{provider: m[1], path: strings.TrimSpace(m[2]), key: strings.TrimSpace(m[3])}, true
+}
+
+func (r secretReference) String() string {
+	return fmt.Sprintf("$__%s{%s:%s}", r.provider, r.path, r.key)
+}
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// secretResolver resolves secure settings of contact points that reference secrets stored in an external secret manager.
+// Only references below one of the allowed path prefixes of the organization are resolved.
+// Resolved secrets are cached, and re-resolved in the background once their TTL elapsed, so that rotated secrets are picked up.
+type secretResolver struct {
+	vaultAddress        string
+	vaultToken          string
+	kubernetesPath      string
+	allowedPathPrefixes []string
+	cacheTTL            time.Duration
+	client              *http.Client
+	logger              log.Logger
+	now                 func() time.Time
+
+	mtx   sync.Mutex
+	cache map[secretReference]cachedSecret
+	// used contains the references resolved since the last call of startBuild.
+	used map[secretReference]struct{}
+	// changed is true if a refresh changed the value of a secret since the last call of takeChanged.
+	changed bool
+}
+
+// newSecretResolver returns a resolver of the secret references of the organization, or nil if external secrets are disabled.
+func newSecretResolver(cfg setting.UnifiedAlertingExternalSecretsSettings, orgID int64, logger log.Logger) *secretResolver {
+	if !cfg.Enabled {
+		return nil
+	}
+	prefixes := make([]string, 0, len(cfg.AllowedPathPrefixes))
+	for _, prefix := range cfg.AllowedPathPrefixes {
+		prefixes = append(prefixes, strings.TrimPrefix(strings.ReplaceAll(prefix, orgIDPlaceholder, strconv.FormatInt(orgID, 10)), "/"))
+	}
+	return &secretResolver{
+		vaultAddress:        strings.TrimSuffix(cfg.VaultAddress, "/"),
+		vaultToken:          cfg.VaultToken,
+		kubernetesPath:      cfg.KubernetesSecretsPath,
+		allowedPathPrefixes: prefixes,
+		cacheTTL:            cfg.CacheTTL,
+		client:              &http.Client{Timeout: cfg.Timeout},
+		logger:              logger,
+		now:                 time.Now,
+		cache:               make(map[secretReference]cachedSecret),
+		used:                make(map[secretReference]struct{}),
+	}
+}
+
+// wrap returns a function that decrypts secure settings with decryptFn, and resolves the values that reference external secrets.
+// If a secret cannot be resolved, the last resolved value is used. If there is none, onError is called and an empty string is returned,
+// so that the caller can fail instead of using an empty secret.
+func (r *secretResolver) wrap(decryptFn alertingNotify.GetDecryptedValueFn, onError func(error)) alertingNotify.GetDecryptedValueFn {
+	if r == nil {
+		return decryptFn
+	}
+	return func(ctx context.Context, sjd map[string][]byte, key string, fallback string) string {
+		value := decryptFn(ctx, sjd, key, fallback)
+		ref, ok := parseSecretReference(value)
+		if !ok {
+			return value
+		}
+		resolved, err := r.resolve(ctx, ref)
+		if err != nil {
+			r.logger.Error("Failed to resolve secret reference", "reference", ref.String(), "setting", key, "error", err)
+			onError(fmt.Errorf("failed to resolve secret reference %s of setting %s: %w", ref.String(), key, err))
+		}
+		return resolved
+	}
+}
+
+// resolve returns the value of the referenced secret, from the cache if possible.
+// If the secret cannot be read but was resolved before, the cached value is returned.
+func (r *secretResolver) resolve(ctx context.Context, ref secretReference) (string, error) {
+	if err := r.validate(ref); err != nil {
+		return "", err
+	}
+	r.mtx.Lock()
+	r.used[ref] = struct{}{}
+	cached, ok := r.cache[ref]
+	r.mtx.Unlock()
+	if ok {
+		return cached.value, nil
+	}
+
+	value, err := r.read(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	r.mtx.Lock()
+	r.cache[ref] = cachedSecret{value: value, expires: r.now().Add(r.cacheTTL)}
+	r.mtx.Unlock()
+	return value, nil
+}
+
+// validate checks that the reference does not escape the paths the organization is allowed to read.
+func (r *secretResolver) validate(ref secretReference) error {
+	if strings.ContainsAny(ref.path, `?#%\`) || strings.ContainsAny(ref.key, `?#%/\`) {
+		return fmt.Errorf("invalid secret reference %s", ref.String())
+	}
+	for _, segment := range strings.Split(ref.path, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("invalid secret reference %s", ref.String())
+		}
+	}
+	if ref.key == "." || ref.key == ".." {
+		return fmt.Errorf("invalid secret reference %s", ref.String())
+	}
+	path := strings.TrimPrefix(ref.path, "/")
+	for _, prefix := range r.allowedPathPrefixes {
+		if hasPathPrefix(path, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("secret reference %s is not below an allowed path prefix of the organization", ref.String())
+}
+
+// hasPathPrefix returns true if the path starts with the prefix. A prefix that does not end with a separator only
+// matches whole path segments, so that the prefix secret/data/org-1 of an organization does not match the paths
+// secret/data/org-10 of other organizations.
+func hasPathPrefix(path, prefix string) bool {
+	if prefix == "" || strings.ContainsAny(prefix[len(prefix)-1:], "/-_.") {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// run refreshes the cached secrets every cache TTL until the context is canceled.
+func (r *secretResolver) run(ctx context.Context) {
+	if r == nil || r.cacheTTL <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.cacheTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.refresh(ctx) {
+				r.mtx.Lock()
+				r.changed = true
+				r.mtx.Unlock()
+			}
+		}
+	}
+}
+
+// takeChanged returns true if the value of a referenced secret changed since the last call, in which case the integrations must be rebuilt.
+func (r *secretResolver) takeChanged() bool {
+	if r == nil {
+		return false
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	changed := r.changed
+	r.changed = false
+	return changed
+}
+
+// refresh re-resolves the cached secrets whose TTL elapsed, and returns true if the value of any of them changed.
+func (r *secretResolver) refresh(ctx context.Context) bool {
+	r.mtx.Lock()
+	expired := make(map[secretReference]cachedSecret)
+	now := r.now()
+	for ref, cached := range r.cache {
+		if !now.Before(cached.expires) {
+			expired[ref] = cached
+		}
+	}
+	r.mtx.Unlock()
+
+	changed := false
+	for ref, cached := range expired {
+		value, err := r.read(ctx, ref)
+		if err != nil {
+			r.logger.Warn("Failed to refresh secret reference, keeping the previous value", "reference", ref.String(), "error", err)
+			continue
+		}
+		if value != cached.value {
+			r.logger.Info("Referenced secret changed", "reference", ref.String())
+			changed = true
+		}
+		r.mtx.Lock()
+		r.cache[ref] = cachedSecret{value: value, expires: r.now().Add(r.cacheTTL)}
+		r.mtx.Unlock()
+	}
+	return changed
+}
+
+// startBuild starts tracking the references used by a new configuration.
+func (r *secretResolver) startBuild() {
+	if r == nil {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.used = make(map[secretReference]struct{})
+}
+
+// finishBuild drops the cached secrets that are not used by the configuration anymore.
+func (r *secretResolver) finishBuild() {
+	if r == nil {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for ref := range r.cache {
+		if _, ok := r.used[ref]; !ok {
+			delete(r.cache, ref)
+		}
+	}
+}
+
+func (r *secretResolver) read(ctx context.Context, ref secretReference) (string, error) {
+	switch ref.provider {
+	case secretProviderVault:
+		return r.readVault(ctx, ref)
+	case secretProviderKubernetes:
+		return r.readKubernetes(ref)
+	default:
+		return "", fmt.Errorf("unknown secret provider %q", ref.provider)
+	}
+}
+
+// readVault reads the key of a secret of the Vault KV secrets engine. Both version 1 and 2 of the engine are supported.
+func (r *secretResolver) readVault(ctx context.Context, ref secretReference) (string, error) {
+	if r.vaultAddress == "" {
+		return "", errors.New("vault address is not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s", r.vaultAddress, strings.TrimPrefix(ref.path, "/")), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", r.vaultToken)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			r.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSecretResponseSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	data := body.Data
+	// version 2 of the KV secrets engine nests the secret in data.data
+	if nested, ok := data["data"]; ok {
+		var v2 map[string]json.RawMessage
+		if err := json.Unmarshal(nested, &v2); err == nil {
+			if _, ok := data[ref.key]; !ok {
+				data = v2
+			}
+		}
+	}
+	raw, ok := data[ref.key]
+	if !ok {
+		return "", fmt.Errorf("secret does not contain the key %q", ref.key)
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("value of the key %q is not a string", ref.key)
+	}
+	return value, nil
+}
+
+// readKubernetes reads the key of a Kubernetes secret mounted as a volume.
+func (r *secretResolver) readKubernetes(ref secretReference) (string, error) {
+	if r.kubernetesPath == "" {
+		return "", errors.New("kubernetes secrets path is not configured")
+	}
+	if strings.ContainsAny(ref.path, `/\`) {
+		return "", fmt.Errorf("invalid secret reference %s", ref.String())
+	}
+	// nolint:gosec
+	// The path is restricted to the configured directory by validate.
+	content, err := os.ReadFile(filepath.Join(r.kubernetesPath, ref.path, ref.key))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
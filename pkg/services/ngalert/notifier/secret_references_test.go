@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	ref, ok := parseSecretReference("$__vault{secret/data/slack:url}")
+	require.True(t, ok)
+	require.Equal(t, secretReference{provider: secretProviderVault, path: "secret/data/slack", key: "url"}, ref)
+
+	ref, ok = parseSecretReference(" $__kubernetes{slack:token} ")
+	require.True(t, ok)
+	require.Equal(t, secretReference{provider: secretProviderKubernetes, path: "slack", key: "token"}, ref)
+
+	for _, value := range []string{"https://hooks.slack.com", "$__vault{secret}", "$__env{TOKEN:x}", "prefix $__vault{a:b}"} {
+		_, ok := parseSecretReference(value)
+		require.False(t, ok, value)
+	}
+}
+
+func TestSecretResolver(t *testing.T) {
+	decrypt := func(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+		if v, ok := sjd[key]; ok {
+			return string(v)
+		}
+		return fallback
+	}
+
+	t.Run("vault secrets are resolved and cached", func(t *testing.T) {
+		value := "first"
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			require.Equal(t, "token", r.Header.Get("X-Vault-Token"))
+			switch r.URL.Path {
+			case "/v1/secret/data/slack":
+				_, _ = w.Write([]byte(`{"data":{"data":{"url":"` + value + `"},"metadata":{"version":1}}}`))
+			case "/v1/kv/slack":
+				_, _ = w.Write([]byte(`{"data":{"url":"v1"}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		now := time.Now()
+		r := newSecretResolver(setting.UnifiedAlertingExternalSecretsSettings{
+			Enabled:             true,
+			VaultAddress:        server.URL + "/",
+			VaultToken:          "token",
+			AllowedPathPrefixes: []string{"secret/data/", "kv/"},
+			Timeout:             time.Second,
+			CacheTTL:            time.Minute,
+		}, 1, log.NewNopLogger())
+		r.now = func() time.Time { return now }
+		var errs []error
+		decryptFn := r.wrap(decrypt, func(err error) {
+			errs = append(errs, err)
+		})
+
+		sjd := map[string][]byte{
+			"url":     []byte("$__vault{secret/data/slack:url}"),
+			"v1":      []byte("$__vault{kv/slack:url}"),
+			"missing": []byte("$__vault{secret/data/missing:url}"),
+			"plain":   []byte("https://example.com"),
+		}
+		require.Equal(t, "first", decryptFn(context.Background(), sjd, "url", ""))
+		require.Equal(t, "first", decryptFn(context.Background(), sjd, "url", ""))
+		require.Equal(t, 1, requests)
+		require.Equal(t, "v1", decryptFn(context.Background(), sjd, "v1", ""))
+		require.Equal(t, "https://example.com", decryptFn(context.Background(), sjd, "plain", ""))
+		require.Empty(t, errs)
+		require.Equal(t, "", decryptFn(context.Background(), sjd, "missing", ""))
+		require.Len(t, errs, 1)
+
+		require.False(t, r.refresh(context.Background()), "no secret expired")
+
+		now = now.Add(time.Minute)
+		require.False(t, r.refresh(context.Background()), "secrets did not change")
+
+		value = "second"
+		now = now.Add(time.Minute)
+		require.True(t, r.refresh(context.Background()))
+		require.Equal(t, "second", decryptFn(context.Background(), sjd, "url", ""))
+
+		// the cached value is used while the secret manager is unavailable
+		server.Close()
+		now = now.Add(time.Minute)
+		require.False(t, r.refresh(context.Background()))
+		require.Equal(t, "second", decryptFn(context.Background(), sjd, "url", ""))
+		require.Len(t, errs, 1)
+	})
+
+	t.Run("references must be below an allowed path prefix of the organization", func(t *testing.T) {
+		r := newSecretResolver(setting.UnifiedAlertingExternalSecretsSettings{
+			Enabled:             true,
+			AllowedPathPrefixes: []string{"secret/data/grafana/{org_id}/"},
+		}, 2, log.NewNopLogger())
+
+		require.NoError(t, r.validate(secretReference{provider: secretProviderVault, path: "secret/data/grafana/2/slack", key: "url"}))
+		for _, path := range []string{
+			"secret/data/grafana/1/slack",
+			"secret/data/grafana/2/../1/slack",
+			"secret/data/grafana/2/slack?version=1",
+			"secret/data/grafana/2/slack#url",
+			"secret/data/grafana/2/%2e%2e/1/slack",
+			"secret/data/other",
+		} {
+			_, err := r.resolve(context.Background(), secretReference{provider: secretProviderVault, path: path, key: "url"})
+			require.Error(t, err, path)
+		}
+	})
+
+	t.Run("prefixes without a trailing separator only match whole path segments", func(t *testing.T) {
+		r := newSecretResolver(setting.UnifiedAlertingExternalSecretsSettings{
+			Enabled:             true,
+			AllowedPathPrefixes: []string{"secret/data/org-{org_id}", "grafana-{org_id}-"},
+		}, 1, log.NewNopLogger())
+
+		for _, path := range []string{"secret/data/org-1", "secret/data/org-1/slack", "grafana-1-slack"} {
+			require.NoError(t, r.validate(secretReference{provider: secretProviderVault, path: path, key: "url"}), path)
+		}
+		for _, path := range []string{"secret/data/org-10", "secret/data/org-10/slack", "secret/data/org-1x/slack", "grafana-10-slack"} {
+			require.Error(t, r.validate(secretReference{provider: secretProviderVault, path: path, key: "url"}), path)
+		}
+	})
+
+	t.Run("references are used as they are if external secrets are disabled", func(t *testing.T) {
+		r := newSecretResolver(setting.UnifiedAlertingExternalSecretsSettings{}, 1, log.NewNopLogger())
+		require.Nil(t, r)
+		decryptFn := r.wrap(decrypt, func(err error) {
+			require.NoError(t, err)
+		})
+		sjd := map[string][]byte{"url": []byte("$__vault{secret/data/slack:url}")}
+		require.Equal(t, "$__vault{secret/data/slack:url}", decryptFn(context.Background(), sjd, "url", ""))
+		require.False(t, r.takeChanged())
+	})
+
+	t.Run("kubernetes secrets are read from the mounted directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "slack"), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "slack", "token"), []byte("secret-token\n"), 0o600))
+
+		r := newSecretResolver(setting.UnifiedAlertingExternalSecretsSettings{Enabled: true, KubernetesSecretsPath: dir, AllowedPathPrefixes: []string{"slack"}, CacheTTL: time.Minute}, 1, log.NewNopLogger())
+		value, err := r.resolve(context.Background(), secretReference{provider: secretProviderKubernetes, path: "slack", key: "token"})
+		require.NoError(t, err)
+		require.Equal(t, "secret-token", value)
+
+		_, err = r.resolve(context.Background(), secretReference{provider: secretProviderKubernetes, path: "..", key: "token"})
+		require.Error(t, err)
+	})
+
+	t.Run("unused secrets are dropped after a build", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "slack"), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "slack", "a"), []byte("a"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "slack", "b"), []byte("b"), 0o600))
+
+		r := newSecretResolver(setting.UnifiedAlertingExternalSecretsSettings{Enabled: true, KubernetesSecretsPath: dir, AllowedPathPrefixes: []string{"slack"}, CacheTTL: time.Minute}, 1, log.NewNopLogger())
+		a := secretReference{provider: secretProviderKubernetes, path: "slack", key: "a"}
+		b := secretReference{provider: secretProviderKubernetes, path: "slack", key: "b"}
+		_, err := r.resolve(context.Background(), a)
+		require.NoError(t, err)
+
+		r.startBuild()
+		_, err = r.resolve(context.Background(), b)
+		require.NoError(t, err)
+		r.finishBuild()
+
+		require.NotContains(t, r.cache, a)
+		require.Contains(t, r.cache, b)
+	})
+}
@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	silenceExpiryAlertName = "SilenceExpiring"
+	silenceIDLabel         = "silence_id"
+)
+
+// WithSilenceMetadataStore enables the notifications about silences that are about to expire,
+// which the store keeps track of so that every expiry is notified once.
+func WithSilenceMetadataStore(s store.SilenceMetadataStore) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.silenceMetadata = s
+	}
+}
+
+// notifyExpiringSilences notifies the receivers configured in the silence expiry notifications of each organization
+// about the long silences that are about to expire. Every expiry is notified once, also if several Grafana instances
+// run, and again if the silence is extended afterwards.
+func (moa *MultiOrgAlertmanager) notifyExpiringSilences(ctx context.Context) {
+	if moa.silenceMetadata == nil {
+		return
+	}
+	configs, err := moa.getLatestConfigs(ctx)
+	if err != nil {
+		moa.logger.Error("Failed to load Alertmanager configurations to notify about expiring silences", "error", err)
+		return
+	}
+	now := time.Now()
+	for orgID, dbConfig := range configs {
+		cfg, err := Load([]byte(dbConfig.AlertmanagerConfiguration))
+		// invalid configurations are reported when they are applied
+		if err != nil || cfg.AlertmanagerConfig.SilenceExpiryNotifications == nil {
+			continue
+		}
+		am, err := moa.AlertmanagerFor(orgID)
+		if err != nil {
+			continue
+		}
+		if err := moa.notifyExpiringSilencesForOrg(ctx, orgID, am, cfg, now); err != nil {
+			moa.logger.Error("Failed to notify about expiring silences", "org", orgID, "error", err)
+		}
+	}
+}
+
+func (moa *MultiOrgAlertmanager) notifyExpiringSilencesForOrg(ctx context.Context, orgID int64, am Alertmanager, cfg *apimodels.PostableUserConfig, now time.Time) error {
+	settings := cfg.AlertmanagerConfig.SilenceExpiryNotifications
+	var receiver *apimodels.PostableApiReceiver
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		if r.Name == settings.Receiver {
+			receiver = r
+			break
+		}
+	}
+	if receiver == nil {
+		return fmt.Errorf("receiver %q does not exist", settings.Receiver)
+	}
+
+	silences, err := am.ListSilences(ctx, nil)
+	if err != nil {
+		return err
+	}
+	expiring := expiringSilences(silences, *settings, now)
+	if len(expiring) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(expiring))
+	for _, s := range expiring {
+		ids = append(ids, *s.ID)
+	}
+	metadata, err := moa.silenceMetadata.GetSilenceMetadata(ctx, orgID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range expiring {
+		marked, err := moa.silenceMetadata.MarkSilenceExpiryNotified(ctx, orgID, *s.ID, time.Time(*s.EndsAt))
+		if err != nil {
+			return err
+		}
+		if !marked {
+			continue
+		}
+		alert := &apimodels.TestReceiversConfigAlertParams{
+			Labels: model.LabelSet{
+				model.AlertNameLabel: silenceExpiryAlertName,
+				silenceIDLabel:       model.LabelValue(*s.ID),
+			},
+			Annotations: model.LabelSet{
+				"summary": model.LabelValue(fmt.Sprintf("Silence %s expires at %s", *s.ID, time.Time(*s.EndsAt).UTC().Format(time.RFC3339))),
+			},
+		}
+		if s.Comment != nil {
+			alert.Annotations["description"] = model.LabelValue(*s.Comment)
+		}
+		if s.CreatedBy != nil {
+			alert.Labels["created_by"] = model.LabelValue(*s.CreatedBy)
+		}
+		if m, ok := metadata[*s.ID]; ok {
+			if m.CreatedByLogin != "" {
+				alert.Labels["created_by"] = model.LabelValue(m.CreatedByLogin)
+			}
+			if m.Reference != "" {
+				alert.Annotations["reference"] = model.LabelValue(m.Reference)
+			}
+		}
+
+		// the expiry is not notified again if this fails, as the notification could have been sent by some integrations
+		result, err := am.TestReceivers(ctx, apimodels.TestReceiversConfigBodyParams{
+			Alert:     alert,
+			Receivers: []*apimodels.PostableApiReceiver{receiver},
+		})
+		if err != nil {
+			moa.logger.Warn("Failed to notify about expiring silence", "org", orgID, "silenceID", *s.ID, "error", err)
+			continue
+		}
+		for _, r := range result.Receivers {
+			for _, c := range r.Configs {
+				if c.Error != nil {
+					moa.logger.Warn("Failed to notify about expiring silence", "org", orgID, "silenceID", *s.ID, "integration", c.Name, "error", c.Error)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// expiringSilences returns the active silences that last at least the minimum duration and expire soon.
+func expiringSilences(silences apimodels.GettableSilences, settings apimodels.SilenceExpiryNotifications, now time.Time) []*amv2.GettableSilence {
+	var result []*amv2.GettableSilence
+	for _, s := range silences {
+		if s.ID == nil || s.Status == nil || s.Status.State == nil || *s.Status.State != amv2.SilenceStatusStateActive {
+			continue
+		}
+		if s.StartsAt == nil || s.EndsAt == nil {
+			continue
+		}
+		startsAt, endsAt := time.Time(*s.StartsAt), time.Time(*s.EndsAt)
+		if endsAt.Sub(startsAt) < settings.GetMinDuration() || endsAt.Sub(now) > settings.GetBefore() {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
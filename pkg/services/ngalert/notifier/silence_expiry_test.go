@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type fakeSilencesAlertmanager struct {
+	Alertmanager
+	silences apimodels.GettableSilences
+	tested   []apimodels.TestReceiversConfigBodyParams
+}
+
+func (f *fakeSilencesAlertmanager) Ready() bool {
+	return true
+}
+
+func (f *fakeSilencesAlertmanager) ListSilences(context.Context, []string) (apimodels.GettableSilences, error) {
+	return f.silences, nil
+}
+
+func (f *fakeSilencesAlertmanager) TestReceivers(_ context.Context, c apimodels.TestReceiversConfigBodyParams) (*TestReceiversResult, error) {
+	f.tested = append(f.tested, c)
+	return &TestReceiversResult{}, nil
+}
+
+func TestNotifyExpiringSilences(t *testing.T) {
+	config := `{"alertmanager_config":{"route":{"receiver":"default"},"receivers":[{"name":"default","grafana_managed_receiver_configs":[{"uid":"","name":"email receiver","type":"email","settings":{"addresses":"<example@email.com>"}}]},{"name":"silences","grafana_managed_receiver_configs":[{"uid":"","name":"email receiver","type":"email","settings":{"addresses":"<silences@email.com>"}}]}],"silence_expiry_notifications":{"receiver":"silences","min_duration":"24h","before":"1h"}}}`
+	now := time.Now()
+	silence := func(id string, startsAt, endsAt time.Time, state string) *amv2.GettableSilence {
+		createdBy, comment := "someone", "maintenance"
+		starts, ends := strfmt.DateTime(startsAt), strfmt.DateTime(endsAt)
+		return &amv2.GettableSilence{
+			ID:     &id,
+			Status: &amv2.SilenceStatus{State: &state},
+			Silence: amv2.Silence{
+				CreatedBy: &createdBy,
+				Comment:   &comment,
+				StartsAt:  &starts,
+				EndsAt:    &ends,
+			},
+		}
+	}
+	am := &fakeSilencesAlertmanager{
+		silences: apimodels.GettableSilences{
+			silence("expiring", now.Add(-48*time.Hour), now.Add(30*time.Minute), amv2.SilenceStatusStateActive),
+			silence("short", now.Add(-time.Hour), now.Add(30*time.Minute), amv2.SilenceStatusStateActive),
+			silence("later", now.Add(-48*time.Hour), now.Add(2*time.Hour), amv2.SilenceStatusStateActive),
+			silence("expired", now.Add(-48*time.Hour), now.Add(-time.Minute), amv2.SilenceStatusStateExpired),
+		},
+	}
+	metadataStore := store.NewFakeSilenceMetadataStore()
+	require.NoError(t, metadataStore.SaveSilenceMetadata(context.Background(), &models.SilenceMetadata{
+		OrgID:          1,
+		SilenceID:      "expiring",
+		CreatedBy:      "user:1",
+		CreatedByLogin: "alice",
+		Reference:      "INC-1",
+	}))
+	moa := &MultiOrgAlertmanager{
+		logger:          log.NewNopLogger(),
+		configStore:     NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{1: {AlertmanagerConfiguration: config, OrgID: 1}}),
+		alertmanagers:   map[int64]Alertmanager{1: am},
+		silenceMetadata: metadataStore,
+	}
+
+	moa.notifyExpiringSilences(context.Background())
+	require.Len(t, am.tested, 1)
+	notification := am.tested[0]
+	require.Len(t, notification.Receivers, 1)
+	require.Equal(t, "silences", notification.Receivers[0].Name)
+	require.Equal(t, model.LabelValue("expiring"), notification.Alert.Labels[silenceIDLabel])
+	require.Equal(t, model.LabelValue("alice"), notification.Alert.Labels["created_by"])
+	require.Equal(t, model.LabelValue("INC-1"), notification.Alert.Annotations["reference"])
+
+	t.Run("should notify once", func(t *testing.T) {
+		moa.notifyExpiringSilences(context.Background())
+		require.Len(t, am.tested, 1)
+	})
+
+	t.Run("should notify again if the silence is extended", func(t *testing.T) {
+		am.silences[0] = silence("expiring", now.Add(-48*time.Hour), now.Add(45*time.Minute), amv2.SilenceStatusStateActive)
+		moa.notifyExpiringSilences(context.Background())
+		require.Len(t, am.tested, 2)
+	})
+}
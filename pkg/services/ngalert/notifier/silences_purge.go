@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+var ErrSilencesPurgeNotSupported = errors.New("purging expired silences is not supported by the Alertmanager of the organization")
+
+// PurgeExpiredSilences removes the expired silences of the organization, which the Alertmanager otherwise keeps until
+// the end of their retention. The Alertmanager does not support removing silences, so it is stopped, the expired
+// silences are removed from its persisted state, and it is started again with the latest configuration.
+// It returns the number of purged silences.
+//
+// In high availability setups, the other instances propagate the purged silences back until their own retention ends.
+func (moa *MultiOrgAlertmanager) PurgeExpiredSilences(ctx context.Context, orgID int64) (int, error) {
+	// The lock is held for the whole restart, so that requests do not use the stopped Alertmanager.
+	moa.alertmanagersMtx.Lock()
+	defer moa.alertmanagersMtx.Unlock()
+
+	orgAM, found := moa.alertmanagers[orgID]
+	if !found {
+		return 0, ErrNoAlertmanagerForOrg
+	}
+	am, ok := orgAM.(*alertmanager)
+	if !ok {
+		return 0, ErrSilencesPurgeNotSupported
+	}
+	dbConfig, err := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, orgID)
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return 0, err
+	}
+
+	// Stopping the Alertmanager persists its silences.
+	am.StopAndWait()
+	purged, purgeErr := am.fileStore.PurgeExpiredSilences(ctx, time.Now())
+
+	delete(moa.alertmanagers, orgID)
+	moa.metrics.RemoveOrgRegistry(orgID)
+	restarted, err := moa.factory(ctx, orgID)
+	if err != nil {
+		return purged, errors.Join(purgeErr, fmt.Errorf("failed to restart the Alertmanager, it is restarted at the next synchronization: %w", err))
+	}
+	moa.alertmanagers[orgID] = restarted
+	if dbConfig == nil {
+		err = restarted.SaveAndApplyDefaultConfig(ctx)
+	} else {
+		err = restarted.ApplyConfig(ctx, dbConfig)
+	}
+	if err != nil {
+		return purged, errors.Join(purgeErr, fmt.Errorf("failed to apply the configuration of the restarted Alertmanager: %w", err))
+	}
+	if purgeErr != nil {
+		return 0, purgeErr
+	}
+
+	moa.metrics.SilencesPurged.WithLabelValues(strconv.FormatInt(orgID, 10)).Add(float64(purged))
+	moa.logger.Info("Purged expired silences", "org", orgID, "count", purged)
+	return purged, nil
+}
+
+// PurgeExpiredSilences removes the silences that ended before now from the persisted silences. It must not be called
+// while the Alertmanager runs, as the Alertmanager would persist them again.
+func (fileStore *FileStore) PurgeExpiredSilences(ctx context.Context, now time.Time) (int, error) {
+	content, exists, err := fileStore.kv.Get(ctx, SilencesFilename)
+	if err != nil {
+		return 0, fmt.Errorf("error reading file '%s' from database: %w", SilencesFilename, err)
+	}
+	if !exists {
+		return 0, nil
+	}
+	b, err := decode(content)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding file '%s': %w", SilencesFilename, err)
+	}
+
+	var kept bytes.Buffer
+	purged := 0
+	r := bytes.NewReader(b)
+	for {
+		var s pb.MeshSilence
+		_, err := pbutil.ReadDelimited(r, &s)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error decoding silences: %w", err)
+		}
+		if s.Silence != nil && !s.Silence.EndsAt.After(now) {
+			purged++
+			continue
+		}
+		if _, err := pbutil.WriteDelimited(&kept, &s); err != nil {
+			return 0, err
+		}
+	}
+	if purged == 0 {
+		return 0, nil
+	}
+
+	if err := fileStore.kv.Set(ctx, SilencesFilename, encode(kept.Bytes())); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
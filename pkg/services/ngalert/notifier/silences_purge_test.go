@@ -0,0 +1,122 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	pb "github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestFileStore_PurgeExpiredSilences(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fileStore := NewFileStore(1, ngfakes.NewFakeKVStore(t), t.TempDir())
+
+	t.Run("should do nothing if there are no silences", func(t *testing.T) {
+		purged, err := fileStore.PurgeExpiredSilences(ctx, now)
+		require.NoError(t, err)
+		require.Zero(t, purged)
+	})
+
+	var buf bytes.Buffer
+	for id, endsAt := range map[string]time.Time{"expired": now.Add(-time.Hour), "active": now.Add(time.Hour)} {
+		_, err := pbutil.WriteDelimited(&buf, &pb.MeshSilence{
+			Silence:   &pb.Silence{Id: id, StartsAt: now.Add(-2 * time.Hour), EndsAt: endsAt},
+			ExpiresAt: endsAt.Add(retentionNotificationsAndSilences),
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, fileStore.kv.Set(ctx, SilencesFilename, encode(buf.Bytes())))
+
+	purged, err := fileStore.PurgeExpiredSilences(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	content, _, err := fileStore.kv.Get(ctx, SilencesFilename)
+	require.NoError(t, err)
+	b, err := decode(content)
+	require.NoError(t, err)
+	var s pb.MeshSilence
+	r := bytes.NewReader(b)
+	_, err = pbutil.ReadDelimited(r, &s)
+	require.NoError(t, err)
+	require.Equal(t, "active", s.Silence.Id)
+	require.Zero(t, r.Len())
+}
+
+func TestMultiOrgAlertmanager_PurgeExpiredSilences(t *testing.T) {
+	ctx := context.Background()
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	reg := prometheus.NewPedanticRegistry()
+	m := metrics.NewNGAlert(reg)
+	cfg := &setting.Cfg{
+		DataPath: t.TempDir(),
+		UnifiedAlerting: setting.UnifiedAlertingSettings{
+			AlertmanagerConfigPollInterval: 3 * time.Minute,
+			DefaultConfiguration:           setting.GetAlertmanagerDefaultConfiguration(),
+		}, // do not poll in tests.
+	}
+	mam, err := NewMultiOrgAlertmanager(cfg, NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{}), &FakeOrgStore{orgs: []int64{1}},
+		ngfakes.NewFakeKVStore(t), ngfakes.NewFakeProvisioningStore(), secretsService.GetDecryptedValue, m.GetMultiOrgAlertmanagerMetrics(),
+		nil, log.New("testlogger"), secretsService)
+	require.NoError(t, err)
+	require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+
+	am, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	silence := func() *apimodels.PostableSilence {
+		name, value, isRegex, createdBy, comment := "alertname", "test", false, "someone", "test"
+		startsAt, endsAt := strfmt.DateTime(time.Now()), strfmt.DateTime(time.Now().Add(time.Hour))
+		return &apimodels.PostableSilence{Silence: amv2.Silence{
+			Matchers:  amv2.Matchers{{Name: &name, Value: &value, IsRegex: &isRegex}},
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+			CreatedBy: &createdBy,
+			Comment:   &comment,
+		}}
+	}
+	expiredID, err := am.CreateSilence(ctx, silence())
+	require.NoError(t, err)
+	activeID, err := am.CreateSilence(ctx, silence())
+	require.NoError(t, err)
+	require.NoError(t, am.DeleteSilence(ctx, expiredID))
+
+	purged, err := mam.PurgeExpiredSilences(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	am, err = mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	silences, err := am.ListSilences(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	require.Equal(t, activeID, *silences[0].ID)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, bytes.NewBufferString(`
+# HELP grafana_alerting_silences_purged_total The number of expired silences purged on demand before the end of their retention.
+# TYPE grafana_alerting_silences_purged_total counter
+grafana_alerting_silences_purged_total{org="1"} 1
+`), "grafana_alerting_silences_purged_total"))
+
+	t.Run("should fail if the organization has no Alertmanager", func(t *testing.T) {
+		_, err := mam.PurgeExpiredSilences(ctx, 2)
+		require.ErrorIs(t, err, ErrNoAlertmanagerForOrg)
+	})
+}
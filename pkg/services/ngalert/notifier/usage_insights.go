@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// UsageRecorder records the use of contact points, for the usage insights of alerting objects.
+// The contact points are identified by the UID of their integration. The recorder must not block.
+type UsageRecorder interface {
+	// ContactPointNotified records that the contact point sent a notification about alerts of the rules at the time.
+	ContactPointNotified(orgID int64, uid string, ruleUIDs []string, at time.Time)
+	// ContactPointModified records that the contact point was created or changed at the time.
+	ContactPointModified(orgID int64, uid string, at time.Time)
+}
+
+// WithUsageRecorder records the notifications sent by the contact points, and when they are created or changed.
+func WithUsageRecorder(r UsageRecorder) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.usage = r
+	}
+}
+
+// setUsageRecorder records the successful notifications of the integrations of the Alertmanager to the recorder.
+func (am *alertmanager) setUsageRecorder(r UsageRecorder) {
+	if r == nil {
+		return
+	}
+	am.usage = r
+	am.deliveries.notified = func(uid string, alerts []*types.Alert) {
+		r.ContactPointNotified(am.orgID, uid, alertRuleUIDs(alerts), time.Now())
+	}
+}
+
+// recordModifiedContactPoints records the integrations that were created or changed since the configuration was last
+// applied. Nothing is recorded when the first configuration is applied, because it is not known what changed before.
+// It must be called with the lock of the Alertmanager held.
+func (am *alertmanager) recordModifiedContactPoints(cfg *apimodels.PostableUserConfig) {
+	if am.usage == nil {
+		return
+	}
+	fingerprints := integrationFingerprints(cfg)
+	if am.integrationFingerprints != nil {
+		now := time.Now()
+		for uid, fingerprint := range fingerprints {
+			if previous, ok := am.integrationFingerprints[uid]; !ok || previous != fingerprint {
+				am.usage.ContactPointModified(am.orgID, uid, now)
+			}
+		}
+	}
+	am.integrationFingerprints = fingerprints
+}
+
+// integrationFingerprints returns the fingerprints of the integrations of the configuration by UID, which change when
+// the integration or its receiver is changed.
+func integrationFingerprints(cfg *apimodels.PostableUserConfig) map[string]string {
+	result := make(map[string]string)
+	for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+		for _, integration := range receiver.GrafanaManagedReceivers {
+			if integration == nil || integration.UID == "" {
+				continue
+			}
+			b, err := json.Marshal(struct {
+				Receiver    string
+				Integration *apimodels.PostableGrafanaReceiver
+			}{receiver.Name, integration})
+			if err != nil {
+				continue
+			}
+			result[integration.UID] = fmt.Sprintf("%x", md5.Sum(b))
+		}
+	}
+	return result
+}
+
+// alertRuleUIDs returns the UIDs of the rules of the alerts, without duplicates.
+func alertRuleUIDs(alerts []*types.Alert) []string {
+	var result []string
+	seen := make(map[model.LabelValue]struct{}, len(alerts))
+	for _, a := range alerts {
+		uid, ok := a.Labels[alertingModels.RuleUIDLabel]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[uid]; ok {
+			continue
+		}
+		seen[uid] = struct{}{}
+		result = append(result, string(uid))
+	}
+	return result
+}
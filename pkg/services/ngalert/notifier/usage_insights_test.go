@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type fakeUsageRecorder struct {
+	notified map[string][]string
+	modified []string
+}
+
+func (f *fakeUsageRecorder) ContactPointNotified(_ int64, uid string, ruleUIDs []string, _ time.Time) {
+	if f.notified == nil {
+		f.notified = make(map[string][]string)
+	}
+	f.notified[uid] = append(f.notified[uid], ruleUIDs...)
+}
+
+func (f *fakeUsageRecorder) ContactPointModified(_ int64, uid string, _ time.Time) {
+	f.modified = append(f.modified, uid)
+}
+
+func TestUsageRecorderNotifications(t *testing.T) {
+	recorder := &fakeUsageRecorder{}
+	am := &alertmanager{orgID: 1, deliveries: newDeliveryStats()}
+	am.setUsageRecorder(recorder)
+
+	uids := map[string][]string{"slack": {"slack-uid"}, "webhook": {"webhook-uid"}}
+	integrations := am.deliveries.wrap("team-a", uids, []*alertingNotify.Integration{
+		alertingNotify.NewIntegration(&countingNotifier{}, &countingNotifier{}, "slack", 0, "team-a"),
+		alertingNotify.NewIntegration(&failingNotifier{}, &failingNotifier{}, "webhook", 0, "team-a"),
+	})
+	alert := func(ruleUID string) *types.Alert {
+		return &types.Alert{Alert: model.Alert{Labels: model.LabelSet{alertingModels.RuleUIDLabel: model.LabelValue(ruleUID)}}}
+	}
+
+	_, err := integrations[0].Notify(context.Background(), alert("rule-a"), alert("rule-b"), alert("rule-a"), newRateLimitTestAlert("external"))
+	require.NoError(t, err)
+	_, err = integrations[1].Notify(context.Background(), alert("rule-a"))
+	require.Error(t, err)
+
+	// failed notifications are not counted
+	require.Equal(t, map[string][]string{"slack-uid": {"rule-a", "rule-b"}}, recorder.notified)
+}
+
+func TestRecordModifiedContactPoints(t *testing.T) {
+	newConfig := func(url string, uids ...string) *apimodels.PostableUserConfig {
+		integrations := make([]*apimodels.PostableGrafanaReceiver, 0, len(uids))
+		for _, uid := range uids {
+			integrations = append(integrations, &apimodels.PostableGrafanaReceiver{
+				UID:      uid,
+				Type:     "webhook",
+				Settings: apimodels.RawMessage(fmt.Sprintf(`{"url":%q}`, url)),
+			})
+		}
+		cfg := &apimodels.PostableUserConfig{}
+		cfg.AlertmanagerConfig.Receivers = []*apimodels.PostableApiReceiver{{
+			Receiver:                 config.Receiver{Name: "team-a"},
+			PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{GrafanaManagedReceivers: integrations},
+		}}
+		return cfg
+	}
+	recorder := &fakeUsageRecorder{}
+	am := &alertmanager{orgID: 1, deliveries: newDeliveryStats()}
+	am.setUsageRecorder(recorder)
+
+	am.recordModifiedContactPoints(newConfig("http://a", "a", "b"))
+	require.Empty(t, recorder.modified, "the first configuration must not be recorded")
+
+	am.recordModifiedContactPoints(newConfig("http://a", "a", "b"))
+	require.Empty(t, recorder.modified)
+
+	am.recordModifiedContactPoints(newConfig("http://a", "a", "b", "c"))
+	require.Equal(t, []string{"c"}, recorder.modified)
+
+	recorder.modified = nil
+	am.recordModifiedContactPoints(newConfig("http://b", "a"))
+	require.Equal(t, []string{"a"}, recorder.modified)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -18,6 +19,7 @@ type AlertRuleService struct {
 	defaultIntervalSeconds int64
 	baseIntervalSeconds    int64
 	ruleStore              RuleStore
+	adminConfigStore       AdminConfigStore
 	provenanceStore        ProvisioningStore
 	dashboardService       dashboards.DashboardService
 	quotas                 QuotaChecker
@@ -26,6 +28,7 @@ type AlertRuleService struct {
 }
 
 func NewAlertRuleService(ruleStore RuleStore,
+	adminConfigStore AdminConfigStore,
 	provenanceStore ProvisioningStore,
 	dashboardService dashboards.DashboardService,
 	quotas QuotaChecker,
@@ -37,6 +40,7 @@ func NewAlertRuleService(ruleStore RuleStore,
 		defaultIntervalSeconds: defaultIntervalSeconds,
 		baseIntervalSeconds:    baseIntervalSeconds,
 		ruleStore:              ruleStore,
+		adminConfigStore:       adminConfigStore,
 		provenanceStore:        provenanceStore,
 		dashboardService:       dashboardService,
 		quotas:                 quotas,
@@ -53,6 +57,7 @@ func (service *AlertRuleService) GetAlertRules(ctx context.Context, orgID int64)
 	if err != nil {
 		return nil, nil, err
 	}
+	service.resolveIntervals(orgID, rules...)
 	provenances := make(map[string]models.Provenance)
 	if len(rules) > 0 {
 		resourceType := rules[0].ResourceType()
@@ -65,6 +70,16 @@ func (service *AlertRuleService) GetAlertRules(ctx context.Context, orgID int64)
 }
 
 func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (models.AlertRule, models.Provenance, error) {
+	rule, provenance, err := service.getAlertRule(ctx, orgID, ruleUID)
+	if err != nil {
+		return models.AlertRule{}, models.ProvenanceNone, err
+	}
+	service.resolveIntervals(orgID, &rule)
+	return rule, provenance, nil
+}
+
+// getAlertRule returns the alert rule as it is stored, without resolving an inherited interval.
+func (service *AlertRuleService) getAlertRule(ctx context.Context, orgID int64, ruleUID string) (models.AlertRule, models.Provenance, error) {
 	query := &models.GetAlertRuleByUIDQuery{
 		OrgID: orgID,
 		UID:   ruleUID,
@@ -95,6 +110,7 @@ func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context
 	if err != nil {
 		return AlertRuleWithFolderTitle{}, err
 	}
+	service.resolveIntervals(orgID, rule)
 
 	dq := dashboards.GetDashboardQuery{
 		OrgID: orgID,
@@ -113,8 +129,8 @@ func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context
 }
 
 // CreateAlertRule creates a new alert rule. This function will ignore any
-// interval that is set in the rule struct and use the already existing group
-// interval or the default one.
+// interval and group labels that are set in the rule struct and use the ones
+// of the already existing group, or the default interval.
 func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance, userID int64) (models.AlertRule, error) {
 	if rule.UID == "" {
 		rule.UID = util.GenerateShortUID()
@@ -122,13 +138,17 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 		return models.AlertRule{}, errors.Join(models.ErrAlertRuleFailedValidation, fmt.Errorf("cannot create rule with UID '%s': %w", rule.UID, err))
 	}
 	interval, err := service.ruleStore.GetRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
-	// if the alert group does not exists we just use the default interval
+	// if the alert group does not exists we just use the default interval of the organization
 	if err != nil && errors.Is(err, store.ErrAlertRuleGroupNotFound) {
-		interval = service.defaultIntervalSeconds
+		interval = service.inheritedIntervalSeconds(rule.OrgID)
 	} else if err != nil {
 		return models.AlertRule{}, err
 	}
 	rule.IntervalSeconds = interval
+	rule.GroupLabels, err = service.ruleGroupLabels(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -162,6 +182,7 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	service.resolveIntervals(rule.OrgID, &rule)
 	return rule, nil
 }
 
@@ -178,10 +199,12 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 	if len(ruleList) == 0 {
 		return models.AlertRuleGroup{}, store.ErrAlertRuleGroupNotFound
 	}
+	service.resolveIntervals(orgID, ruleList...)
 	res := models.AlertRuleGroup{
 		Title:     ruleList[0].RuleGroup,
 		FolderUID: ruleList[0].NamespaceUID,
 		Interval:  ruleList[0].IntervalSeconds,
+		Labels:    ruleList[0].GroupLabels,
 		Rules:     []models.AlertRule{},
 	}
 	for _, r := range ruleList {
@@ -194,7 +217,7 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 
 // UpdateRuleGroup will update the interval for all rules in the group.
 func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
-	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
+	if err := service.validateInterval(orgID, intervalSeconds); err != nil {
 		return err
 	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
@@ -223,8 +246,36 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int6
 	})
 }
 
+// UpdateRuleGroupLabels will update the group labels for all rules in the group.
+func (service *AlertRuleService) UpdateRuleGroupLabels(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, labels map[string]string) error {
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespaceUID},
+			RuleGroup:     ruleGroup,
+		}
+		ruleList, err := service.ruleStore.ListAlertRules(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		updateRules := make([]models.UpdateRule, 0, len(ruleList))
+		for _, rule := range ruleList {
+			if maps.Equal(rule.GroupLabels, labels) {
+				continue
+			}
+			newRule := *rule
+			newRule.GroupLabels = labels
+			updateRules = append(updateRules, models.UpdateRule{
+				Existing: rule,
+				New:      newRule,
+			})
+		}
+		return service.ruleStore.UpdateAlertRules(ctx, updateRules)
+	})
+}
+
 func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, userID int64, provenance models.Provenance) error {
-	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
+	if err := service.validateInterval(orgID, group.Interval); err != nil {
 		return err
 	}
 
@@ -291,41 +342,39 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 			}
 		}
 
-		if len(delta.Update) > 0 {
-			updates := make([]models.UpdateRule, 0, len(delta.Update))
-			for _, update := range delta.Update {
-				// check that provenance is not changed in an invalid way
-				storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.New, orgID)
-				if err != nil {
-					return err
-				}
-				if canUpdate := canUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
-					return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
-				}
-				updates = append(updates, models.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
-				})
-			}
-			if err = service.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
-				return fmt.Errorf("failed to update alert rules: %w", err)
+		updates := make([]models.UpdateRule, 0, len(delta.Update))
+		for _, update := range delta.Update {
+			// check that provenance is not changed in an invalid way
+			storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.New, orgID)
+			if err != nil {
+				return err
 			}
-			for _, update := range delta.Update {
-				if err := service.provenanceStore.SetProvenance(ctx, update.New, orgID, provenance); err != nil {
-					return err
-				}
+			if canUpdate := canUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
+				return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
 			}
+			updates = append(updates, models.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
 		}
 
-		if len(delta.New) > 0 {
-			uids, err := service.ruleStore.InsertAlertRules(ctx, withoutNilAlertRules(delta.New))
-			if err != nil {
-				return fmt.Errorf("failed to insert alert rules: %w", err)
+		// the group is replaced as a whole, so the write fails if any of its rules cannot be written
+		result, err := service.ruleStore.BulkWriteAlertRules(ctx, models.BulkWriteRulesCommand{
+			Insert: withoutNilAlertRules(delta.New),
+			Update: updates,
+			Atomic: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write alert rules: %w", err)
+		}
+		for _, key := range result.Updated {
+			if err := service.provenanceStore.SetProvenance(ctx, &models.AlertRule{UID: key.UID}, orgID, provenance); err != nil {
+				return err
 			}
-			for _, key := range uids {
-				if err := service.provenanceStore.SetProvenance(ctx, &models.AlertRule{UID: key.UID}, orgID, provenance); err != nil {
-					return err
-				}
+		}
+		for _, key := range result.Inserted {
+			if err := service.provenanceStore.SetProvenance(ctx, &models.AlertRule{UID: key.UID}, orgID, provenance); err != nil {
+				return err
 			}
 		}
 
@@ -339,7 +388,8 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 
 // UpdateAlertRule updates an alert rule.
 func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
-	storedRule, storedProvenance, err := service.GetAlertRule(ctx, rule.OrgID, rule.UID)
+	// the stored rule is not resolved, so that the rule keeps inheriting the interval of the organization
+	storedRule, storedProvenance, err := service.getAlertRule(ctx, rule.OrgID, rule.UID)
 	if err != nil {
 		return models.AlertRule{}, err
 	}
@@ -349,6 +399,7 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	rule.Updated = time.Now()
 	rule.ID = storedRule.ID
 	rule.IntervalSeconds = storedRule.IntervalSeconds
+	rule.GroupLabels = storedRule.GroupLabels
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -368,6 +419,7 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	service.resolveIntervals(rule.OrgID, &rule)
 	return rule, err
 }
 
@@ -438,6 +490,7 @@ func (service *AlertRuleService) GetAlertRuleGroupWithFolderTitle(ctx context.Co
 	if len(ruleList) == 0 {
 		return models.AlertRuleGroupWithFolderTitle{}, store.ErrAlertRuleGroupNotFound
 	}
+	service.resolveIntervals(orgID, ruleList...)
 
 	dq := dashboards.GetDashboardQuery{
 		OrgID: orgID,
@@ -466,6 +519,7 @@ func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Conte
 	if err != nil {
 		return nil, err
 	}
+	service.resolveIntervals(orgID, ruleList...)
 
 	groups := make(map[models.AlertRuleGroupKey][]models.AlertRule)
 	namespaces := make(map[string][]*models.AlertRuleGroupKey)
@@ -513,10 +567,68 @@ func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Conte
 	return result, nil
 }
 
+// getAdminConfiguration returns the admin configuration of the organization, or nil if the store is not set or the organization has none.
+func (service *AlertRuleService) getAdminConfiguration(orgID int64) (*models.AdminConfiguration, error) {
+	return store.FindAdminConfiguration(service.adminConfigStore, orgID)
+}
+
+// validateInterval checks that rule groups of the organization can be evaluated with the interval.
+// Groups that inherit the interval of the organization always pass.
+func (service *AlertRuleService) validateInterval(orgID int64, intervalSeconds int64) error {
+	if intervalSeconds == models.InheritedIntervalSeconds {
+		return nil
+	}
+	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
+		return err
+	}
+	cfg, err := service.getAdminConfiguration(orgID)
+	if err != nil {
+		return fmt.Errorf("failed to get the admin configuration of the organization: %w", err)
+	}
+	if !cfg.IsEvaluationIntervalAllowed(intervalSeconds) {
+		return fmt.Errorf("%w: interval (%v) is not allowed in the organization", models.ErrAlertRuleFailedValidation, time.Duration(intervalSeconds)*time.Second)
+	}
+	return nil
+}
+
+// inheritedIntervalSeconds returns the evaluation interval of rule groups of the organization that inherit its interval.
+func (service *AlertRuleService) inheritedIntervalSeconds(orgID int64) int64 {
+	return store.InheritedIntervalSeconds(service.log, service.adminConfigStore, orgID, service.defaultIntervalSeconds)
+}
+
+// resolveIntervals replaces the interval of rules that inherit the evaluation interval of the organization with
+// the effective one, because the provisioning API and its file format do not support inheritance.
+func (service *AlertRuleService) resolveIntervals(orgID int64, rules ...*models.AlertRule) {
+	var intervalSeconds int64
+	for _, rule := range rules {
+		if rule == nil || !rule.InheritsInterval() {
+			continue
+		}
+		if intervalSeconds == 0 {
+			intervalSeconds = service.inheritedIntervalSeconds(orgID)
+		}
+		rule.IntervalSeconds = intervalSeconds
+	}
+}
+
+// ruleGroupLabels returns the labels of the rule group, or nil if the group does not exist.
+func (service *AlertRuleService) ruleGroupLabels(ctx context.Context, orgID int64, namespaceUID, ruleGroup string) (map[string]string, error) {
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespaceUID},
+		RuleGroup:     ruleGroup,
+	})
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	return rules[0].GroupLabels, nil
+}
+
 // syncRuleGroupFields synchronizes calculated fields across multiple rules in a group.
 func syncGroupRuleFields(group *models.AlertRuleGroup, orgID int64) *models.AlertRuleGroup {
 	for i := range group.Rules {
 		group.Rules[i].IntervalSeconds = group.Interval
+		group.Rules[i].GroupLabels = group.Labels
 		group.Rules[i].RuleGroup = group.Title
 		group.Rules[i].NamespaceUID = group.FolderUID
 		group.Rules[i].OrgID = orgID
@@ -100,6 +100,50 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, interval, rule.IntervalSeconds)
 	})
 
+	t.Run("should use the evaluation intervals of the organization", func(t *testing.T) {
+		const orgID int64 = 50
+		err := ruleService.adminConfigStore.(*store.DBstore).UpdateAdminConfiguration(store.UpdateAdminConfigurationCmd{
+			AdminConfiguration: &models.AdminConfiguration{
+				OrgID:                             orgID,
+				DefaultEvaluationIntervalSeconds:  300,
+				AllowedEvaluationIntervalsSeconds: []int64{60, 300},
+			},
+		})
+		require.NoError(t, err)
+
+		rule := dummyRule("test#5", orgID)
+		rule.RuleGroup = "inherit"
+		rule, err = ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), rule.IntervalSeconds)
+
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		group := createDummyGroup("not-allowed", orgID)
+		group.Interval = 120
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, models.InheritedIntervalSeconds)
+		require.NoError(t, err)
+
+		readRule, _, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), readRule.IntervalSeconds)
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), readGroup.Interval)
+
+		readRule.Title = "test#5 updated"
+		updated, err := ruleService.UpdateAlertRule(context.Background(), readRule, models.ProvenanceNone)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), updated.IntervalSeconds)
+		stored, _, err := ruleService.getAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.True(t, stored.InheritsInterval())
+	})
+
 	t.Run("updating a rule group's top level fields should bump the version number", func(t *testing.T) {
 		const (
 			orgID              = 123
@@ -572,6 +616,7 @@ func createAlertRuleService(t *testing.T) AlertRuleService {
 	quotas.EXPECT().LimitOK()
 	return AlertRuleService{
 		ruleStore:              store,
+		adminConfigStore:       &store,
 		provenanceStore:        store,
 		quotas:                 &quotas,
 		xact:                   sqlStore,
@@ -2,9 +2,11 @@ package provisioning
 
 import (
 	"context"
+	"crypto/rsa"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -53,15 +55,20 @@ type ContactPointQuery struct {
 	// Optionally filter by name.
 	Name  string
 	OrgID int64
+	// Optionally filter by the types of the integrations.
+	Types []string
 	// Optionally decrypt secure settings, requires OrgAdmin.
 	Decrypt bool
+	// Optionally encrypt secure settings with the public key, see EncryptExportValue. It requires the same permissions as Decrypt.
+	EncryptionKey *rsa.PublicKey
 }
 
 // GetContactPoints returns contact points. If q.Decrypt is true and the user is an OrgAdmin, decrypted secure settings are included instead of redacted ones.
+// If q.EncryptionKey is set, the secure settings are decrypted and then encrypted with the key.
 func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactPointQuery, u identity.Requester) ([]apimodels.EmbeddedContactPoint, error) {
 	receiverQuery := models.GetReceiversQuery{
 		OrgID:   q.OrgID,
-		Decrypt: q.Decrypt,
+		Decrypt: q.Decrypt || q.EncryptionKey != nil,
 	}
 	if q.Name != "" {
 		receiverQuery.Names = []string{q.Name}
@@ -82,10 +89,22 @@ func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactP
 
 	var contactPoints []apimodels.EmbeddedContactPoint
 	for _, gr := range grafanaReceivers {
+		if len(q.Types) > 0 && !slices.ContainsFunc(q.Types, func(t string) bool { return strings.EqualFold(t, gr.Type) }) {
+			continue
+		}
 		contactPoint, err := GettableGrafanaReceiverToEmbeddedContactPoint(gr)
 		if err != nil {
 			return nil, err
 		}
+		if q.EncryptionKey != nil {
+			for k := range gr.SecureFields {
+				encrypted, err := EncryptExportValue(q.EncryptionKey, contactPoint.Settings.Get(k).MustString())
+				if err != nil {
+					return nil, fmt.Errorf("failed to encrypt secure setting %s of contact point %s: %w", k, gr.Name, err)
+				}
+				contactPoint.Settings.Set(k, encrypted)
+			}
+		}
 		contactPoints = append(contactPoints, contactPoint)
 	}
 
@@ -320,7 +339,7 @@ func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID in
 			}
 		}
 	}
-	if fullRemoval && isContactPointInUse(name, []*apimodels.Route{revision.cfg.AlertmanagerConfig.Route}) {
+	if fullRemoval && isContactPointInUse(name, policyRoutes(revision.cfg.AlertmanagerConfig)) {
 		return fmt.Errorf("contact point '%s' is currently used by a notification policy", name)
 	}
 
@@ -408,7 +427,7 @@ groupLoop:
 				// If we're renaming, we'll need to fix up the macro receiver group for consistency.
 				// Firstly, if we're the only receiver in the group, simply rename the group to match. Done!
 				if len(receiverGroup.GrafanaManagedReceivers) == 1 {
-					replaceReferences(receiverGroup.Name, target.Name, cfg.AlertmanagerConfig.Route)
+					replaceReferences(receiverGroup.Name, target.Name, policyRoutes(cfg.AlertmanagerConfig)...)
 					receiverGroup.Name = target.Name
 					receiverGroup.GrafanaManagedReceivers[i] = target
 				}
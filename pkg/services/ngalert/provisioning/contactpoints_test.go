@@ -2,6 +2,8 @@ package provisioning
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -52,6 +54,18 @@ func TestContactPointService(t *testing.T) {
 		require.Equal(t, "slack receiver", cps[0].Name)
 	})
 
+	t.Run("service filters contact points by integration type", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+
+		q := cpsQuery(1)
+		q.Types = []string{"Slack", "webhook"}
+		cps, err := sut.GetContactPoints(context.Background(), q, nil)
+		require.NoError(t, err)
+
+		require.Len(t, cps, 1)
+		require.Equal(t, "slack receiver", cps[0].Name)
+	})
+
 	t.Run("service stitches contact point into org's AM config", func(t *testing.T) {
 		sut := createContactPointServiceSut(t, secretsService)
 		newCp := createTestContactPoint()
@@ -300,7 +314,7 @@ func TestContactPointServiceDecryptRedact(t *testing.T) {
 		q.Decrypt = true
 		cps, err := sut.GetContactPoints(context.Background(), q, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{
 			1: {
-				accesscontrol.ActionAlertingProvisioningReadSecrets: nil,
+				accesscontrol.ActionAlertingReceiversReadSecrets: nil,
 			},
 		}})
 		require.NoError(t, err)
@@ -309,6 +323,41 @@ func TestContactPointServiceDecryptRedact(t *testing.T) {
 		require.Equal(t, expectedName, cps[0].Name)
 		require.Equal(t, "secure url", cps[0].Settings.Get("url").MustString())
 	})
+
+	t.Run("GetContactPoints errors when EncryptionKey is set and user does not have permissions", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.receiverService = receiverServiceWithAC(sut)
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		q := cpsQuery(1)
+		q.EncryptionKey = &key.PublicKey
+		_, err = sut.GetContactPoints(context.Background(), q, &user.SignedInUser{OrgID: 1})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+
+	t.Run("GetContactPoints encrypts secure settings when EncryptionKey is set and user has permissions", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.receiverService = receiverServiceWithAC(sut)
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		q := cpsQueryWithName(1, "slack receiver")
+		q.EncryptionKey = &key.PublicKey
+		cps, err := sut.GetContactPoints(context.Background(), q, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{
+			1: {
+				accesscontrol.ActionAlertingReceiversReadSecrets: nil,
+			},
+		}})
+		require.NoError(t, err)
+
+		require.Len(t, cps, 1)
+		encrypted := cps[0].Settings.Get("url").MustString()
+		require.NotEqual(t, "secure url", encrypted)
+		decrypted, err := DecryptExportValue(key, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "secure url", decrypted)
+	})
 }
 
 func TestContactPointInUse(t *testing.T) {
@@ -3,6 +3,7 @@ package provisioning
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
@@ -19,6 +20,12 @@ var (
 	ErrTimeIntervalExists   = errutil.BadRequest("alerting.notifications.time-intervals.nameExists", errutil.WithPublicMessage("Time interval with this name already exists. Use a different name or update existing one."))
 	ErrTimeIntervalInvalid  = errutil.BadRequest("alerting.notifications.time-intervals.invalidFormat").MustTemplate("Invalid format of the submitted time interval", errutil.WithPublic("Time interval is in invalid format. Correct the payload and try again."))
 	ErrTimeIntervalInUse    = errutil.Conflict("alerting.notifications.time-intervals.used", errutil.WithPublicMessage("Time interval is used by one or many notification policies"))
+
+	ErrNamedRouteNotFound = errutil.NotFound("alerting.notifications.named-routes.notFound", errutil.WithPublicMessage("Named notification policy not found"))
+	ErrNamedRouteInvalid  = errutil.BadRequest("alerting.notifications.named-routes.invalid").MustTemplate("Invalid named notification policy", errutil.WithPublic("Named notification policy is invalid: {{ .Public.Error }}"))
+	ErrNamedRouteInUse    = errutil.Conflict("alerting.notifications.named-routes.used", errutil.WithPublicMessage("Named notification policy is referenced by the notification policy tree or by other named policies"))
+
+	ErrTemplateInUse = errutil.Conflict("alerting.notifications.templates.used").MustTemplate("Template is used by contact points {{ .Public.Receivers }}", errutil.WithPublic("Template is used by contact points {{ .Public.Receivers }}. Remove the references to the template and try again."))
 )
 
 func makeErrBadAlertmanagerConfiguration(err error) error {
@@ -42,3 +49,25 @@ func MakeErrTimeIntervalInvalid(err error) error {
 
 	return ErrTimeIntervalInvalid.Build(data)
 }
+
+// MakeErrNamedRouteInvalid creates an error with the ErrNamedRouteInvalid template
+func MakeErrNamedRouteInvalid(err error) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Error": err.Error(),
+		},
+		Error: err,
+	}
+
+	return ErrNamedRouteInvalid.Build(data)
+}
+
+// MakeErrTemplateInUse creates an error with the ErrTemplateInUse template that names the contact points using the template.
+func MakeErrTemplateInUse(receivers []string) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Receivers": strings.Join(receivers, ", "),
+		},
+	}
+	return ErrTemplateInUse.Build(data)
+}
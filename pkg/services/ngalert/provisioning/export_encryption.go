@@ -0,0 +1,106 @@
+package provisioning
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// exportKeySize is the size in bytes of the AES key that encrypts a secure setting.
+const exportKeySize = 32
+
+// ParseExportPublicKey parses a PEM encoded RSA public key, in either PKIX or PKCS #1 form,
+// with which the secure settings of an export are encrypted.
+func ParseExportPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key must be an RSA key, got %T", key)
+		}
+		return rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// EncryptExportValue encrypts a secure setting for the owner of the private key that matches the public key.
+// The value is encrypted with a random AES-256-GCM key, which is itself encrypted with RSA-OAEP and SHA-256,
+// so that values of any length can be encrypted. The result is the base64 encoding of the encrypted key,
+// followed by the nonce and the encrypted value.
+func EncryptExportValue(key *rsa.PublicKey, value string) (string, error) {
+	aesKey := make([]byte, exportKeySize)
+	if _, err := rand.Read(aesKey); err != nil {
+		return "", err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, aesKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt the value key: %w", err)
+	}
+	gcm, err := newExportCipher(aesKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	out := make([]byte, 0, len(encryptedKey)+len(nonce)+len(value)+gcm.Overhead())
+	out = append(out, encryptedKey...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, []byte(value), nil)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// DecryptExportValue decrypts a secure setting that was encrypted by EncryptExportValue.
+func DecryptExportValue(key *rsa.PrivateKey, value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < key.Size() {
+		return "", errors.New("encrypted value is too short")
+	}
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, data[:key.Size()], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the value key: %w", err)
+	}
+	gcm, err := newExportCipher(aesKey)
+	if err != nil {
+		return "", err
+	}
+	data = data[key.Size():]
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func newExportCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,56 @@
+package provisioning
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportEncryption(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("parses PKIX and PKCS #1 public keys", func(t *testing.T) {
+		pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		require.NoError(t, err)
+		parsed, err := ParseExportPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}))
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(parsed))
+
+		pkcs1 := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+		parsed, err = ParseExportPublicKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkcs1}))
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(parsed))
+	})
+
+	t.Run("rejects keys that are not RSA public keys", func(t *testing.T) {
+		_, err := ParseExportPublicKey([]byte("not a key"))
+		require.Error(t, err)
+
+		private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		_, err = ParseExportPublicKey(private)
+		require.Error(t, err)
+	})
+
+	t.Run("encrypted values can be decrypted with the private key", func(t *testing.T) {
+		// longer than what RSA-OAEP can encrypt with a 2048 bits key
+		value := strings.Repeat("secret", 100)
+		encrypted, err := EncryptExportValue(&key.PublicKey, value)
+		require.NoError(t, err)
+		require.NotContains(t, encrypted, "secret")
+
+		decrypted, err := DecryptExportValue(key, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, value, decrypted)
+
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = DecryptExportValue(other, encrypted)
+		require.Error(t, err)
+	})
+}
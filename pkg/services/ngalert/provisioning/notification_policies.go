@@ -3,6 +3,7 @@ package provisioning
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -70,7 +71,13 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 		return err
 	}
 
-	err = tree.ValidateReceivers(receivers)
+	// the routes that reference named routes are validated with the settings they get from them
+	expanded, err := definitions.ExpandRoute(&tree, revision.cfg.AlertmanagerConfig.NamedRoutes)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	err = expanded.ValidateReceivers(receivers)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
@@ -79,7 +86,7 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 	for _, mt := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		muteTimes[mt.Name] = struct{}{}
 	}
-	err = tree.ValidateMuteTimes(muteTimes)
+	err = expanded.ValidateMuteTimes(muteTimes)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
@@ -126,6 +133,153 @@ func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID
 	return *route, nil
 }
 
+// GetNamedPolicies returns the named routes, which are the subtrees of the notification policy tree that routes reference by name.
+func (nps *NotificationPolicyService) GetNamedPolicies(ctx context.Context, orgID int64) ([]definitions.NamedRoute, error) {
+	rev, err := nps.configStore.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, (&definitions.NamedRoute{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]definitions.NamedRoute, 0, len(rev.cfg.AlertmanagerConfig.NamedRoutes))
+	for _, named := range rev.cfg.AlertmanagerConfig.NamedRoutes {
+		named.Provenance = definitions.Provenance(provenances[named.ResourceID()])
+		result = append(result, named)
+	}
+	return result, nil
+}
+
+// GetNamedPolicy returns the named route with the given name. If it does not exist, ErrNamedRouteNotFound is returned.
+func (nps *NotificationPolicyService) GetNamedPolicy(ctx context.Context, orgID int64, name string) (definitions.NamedRoute, error) {
+	rev, err := nps.configStore.Get(ctx, orgID)
+	if err != nil {
+		return definitions.NamedRoute{}, err
+	}
+
+	idx := getNamedRouteIndex(rev, name)
+	if idx < 0 {
+		return definitions.NamedRoute{}, ErrNamedRouteNotFound.Errorf("")
+	}
+	result := rev.cfg.AlertmanagerConfig.NamedRoutes[idx]
+
+	provenance, err := nps.provenanceStore.GetProvenance(ctx, &result, orgID)
+	if err != nil {
+		return definitions.NamedRoute{}, err
+	}
+	result.Provenance = definitions.Provenance(provenance)
+	return result, nil
+}
+
+// UpsertNamedPolicy creates the named route, or replaces it if a named route with the same name exists.
+// The routes of the policy tree that reference the named route use the new subtree once the configuration is applied.
+func (nps *NotificationPolicyService) UpsertNamedPolicy(ctx context.Context, orgID int64, named definitions.NamedRoute, p models.Provenance) error {
+	if named.Route == nil {
+		return MakeErrNamedRouteInvalid(fmt.Errorf("named route %q has no route", named.Name))
+	}
+
+	revision, err := nps.configStore.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	named.Provenance = ""
+	namedRoutes := slices.Clone(revision.cfg.AlertmanagerConfig.NamedRoutes)
+	if idx := getNamedRouteIndex(revision, named.Name); idx >= 0 {
+		namedRoutes[idx] = named
+	} else {
+		namedRoutes = append(namedRoutes, named)
+	}
+	if err := definitions.ValidateNamedRoutes(namedRoutes); err != nil {
+		return MakeErrNamedRouteInvalid(err)
+	}
+
+	// the receiver of a named route can be left to the routes that reference it
+	receivers, err := nps.receiversToMap(revision.cfg.AlertmanagerConfig.Receivers)
+	if err != nil {
+		return err
+	}
+	for _, receiver := range definitions.AllReceivers(named.Route.AsAMRoute()) {
+		if _, ok := receivers[receiver]; !ok {
+			return MakeErrNamedRouteInvalid(fmt.Errorf("receiver '%s' does not exist", receiver))
+		}
+	}
+	muteTimes := map[string]struct{}{}
+	for _, mt := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		muteTimes[mt.Name] = struct{}{}
+	}
+	if err := named.Route.ValidateMuteTimes(muteTimes); err != nil {
+		return MakeErrNamedRouteInvalid(err)
+	}
+
+	revision.cfg.AlertmanagerConfig.NamedRoutes = namedRoutes
+	return nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := nps.configStore.Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		return nps.provenanceStore.SetProvenance(ctx, &named, orgID, p)
+	})
+}
+
+// DeleteNamedPolicy deletes the named route with the given name. It returns ErrNamedRouteInUse if the named route is
+// referenced by the policy tree or by another named route.
+func (nps *NotificationPolicyService) DeleteNamedPolicy(ctx context.Context, orgID int64, name string) error {
+	revision, err := nps.configStore.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	idx := getNamedRouteIndex(revision, name)
+	if idx < 0 {
+		return nil
+	}
+	if isNamedRouteInUse(name, policyRoutes(revision.cfg.AlertmanagerConfig)) {
+		return ErrNamedRouteInUse.Errorf("")
+	}
+	named := revision.cfg.AlertmanagerConfig.NamedRoutes[idx]
+	revision.cfg.AlertmanagerConfig.NamedRoutes = slices.Delete(revision.cfg.AlertmanagerConfig.NamedRoutes, idx, idx+1)
+
+	return nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := nps.configStore.Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		return nps.provenanceStore.DeleteProvenance(ctx, &named, orgID)
+	})
+}
+
+func getNamedRouteIndex(rev *cfgRevision, name string) int {
+	return slices.IndexFunc(rev.cfg.AlertmanagerConfig.NamedRoutes, func(named definitions.NamedRoute) bool {
+		return named.Name == name
+	})
+}
+
+func isNamedRouteInUse(name string, routes []*definitions.Route) bool {
+	for _, route := range routes {
+		if route.Ref == name || isNamedRouteInUse(name, route.Routes) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyRoutes returns the root of the policy tree and the roots of the named routes of the configuration,
+// so that all routes can be walked when looking for the usages of a receiver or mute timing.
+func policyRoutes(cfg definitions.PostableApiAlertingConfig) []*definitions.Route {
+	routes := make([]*definitions.Route, 0, len(cfg.NamedRoutes)+1)
+	if cfg.Route != nil {
+		routes = append(routes, cfg.Route)
+	}
+	for _, named := range cfg.NamedRoutes {
+		if named.Route != nil {
+			routes = append(routes, named.Route)
+		}
+	}
+	return routes
+}
+
 func (nps *NotificationPolicyService) receiversToMap(records []*definitions.PostableApiReceiver) (map[string]struct{}, error) {
 	receivers := map[string]struct{}{}
 	for _, receiver := range records {
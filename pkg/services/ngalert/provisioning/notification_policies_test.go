@@ -215,6 +215,77 @@ func TestNotificationPolicyService(t *testing.T) {
 	})
 }
 
+func TestNotificationPolicyServiceNamedPolicies(t *testing.T) {
+	escalation := definitions.NamedRoute{
+		Name: "escalation",
+		Route: &definitions.Route{
+			GroupByStr: []string{"alertname"},
+			Routes:     []*definitions.Route{{Receiver: "grafana-default-email"}},
+		},
+	}
+
+	t.Run("named policies can be created, referenced and deleted", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		err := sut.UpsertNamedPolicy(context.Background(), 1, escalation, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		named, err := sut.GetNamedPolicy(context.Background(), 1, "escalation")
+		require.NoError(t, err)
+		require.Equal(t, []string{"alertname"}, named.Route.GroupByStr)
+		require.Equal(t, definitions.Provenance(models.ProvenanceAPI), named.Provenance)
+
+		tree := definitions.Route{
+			Receiver: "grafana-default-email",
+			Routes:   []*definitions.Route{{Ref: "escalation", Receiver: "grafana-default-email"}},
+		}
+		err = sut.UpdatePolicyTree(context.Background(), 1, tree, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		err = sut.DeleteNamedPolicy(context.Background(), 1, "escalation")
+		require.Truef(t, ErrNamedRouteInUse.Is(err), "expected ErrNamedRouteInUse but got %s", err)
+
+		tree.Routes = nil
+		err = sut.UpdatePolicyTree(context.Background(), 1, tree, models.ProvenanceNone)
+		require.NoError(t, err)
+		err = sut.DeleteNamedPolicy(context.Background(), 1, "escalation")
+		require.NoError(t, err)
+
+		_, err = sut.GetNamedPolicy(context.Background(), 1, "escalation")
+		require.Truef(t, ErrNamedRouteNotFound.Is(err), "expected ErrNamedRouteNotFound but got %s", err)
+		all, err := sut.GetNamedPolicies(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, all)
+	})
+
+	t.Run("policy tree cannot reference a named policy that does not exist", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		tree := definitions.Route{
+			Receiver: "grafana-default-email",
+			Routes:   []*definitions.Route{{Ref: "missing", Receiver: "grafana-default-email"}},
+		}
+		err := sut.UpdatePolicyTree(context.Background(), 1, tree, models.ProvenanceNone)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("named policies are validated", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		invalid := definitions.NamedRoute{Name: "invalid", Route: &definitions.Route{Receiver: "missing"}}
+		err := sut.UpsertNamedPolicy(context.Background(), 1, invalid, models.ProvenanceNone)
+		require.Truef(t, ErrNamedRouteInvalid.Base.Is(err), "expected ErrNamedRouteInvalid but got %s", err)
+
+		require.NoError(t, sut.UpsertNamedPolicy(context.Background(), 1, escalation, models.ProvenanceNone))
+		cycle := definitions.NamedRoute{
+			Name:  "escalation",
+			Route: &definitions.Route{Routes: []*definitions.Route{{Ref: "escalation"}}},
+		}
+		err = sut.UpsertNamedPolicy(context.Background(), 1, cycle, models.ProvenanceNone)
+		require.Truef(t, ErrNamedRouteInvalid.Base.Is(err), "expected ErrNamedRouteInvalid but got %s", err)
+	})
+}
+
 func createNotificationPolicyServiceSut() *NotificationPolicyService {
 	return &NotificationPolicyService{
 		configStore:     &alertmanagerConfigStoreImpl{store: fakes.NewFakeAlertmanagerConfigStore(defaultAlertmanagerConfigJSON)},
@@ -40,10 +40,16 @@ type RuleStore interface {
 	GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error)
 	InsertAlertRules(ctx context.Context, rule []models.AlertRule) ([]models.AlertRuleKeyWithId, error)
 	UpdateAlertRules(ctx context.Context, rule []models.UpdateRule) error
+	BulkWriteAlertRules(ctx context.Context, cmd models.BulkWriteRulesCommand) (*models.BulkWriteRulesResult, error)
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
 }
 
+// AdminConfigStore provides the admin configuration of an organization, which defines the evaluation intervals its rule groups can use.
+type AdminConfigStore interface {
+	GetAdminConfiguration(orgID int64) (*models.AdminConfiguration, error)
+}
+
 // QuotaChecker represents the ability to evaluate whether quotas are met.
 //
 //go:generate mockery --name QuotaChecker --structname MockQuotaChecker --inpackage --filename quota_checker_mock.go --with-expecter
@@ -2,13 +2,21 @@ package provisioning
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
+var (
+	templateDefineRegexp = regexp.MustCompile(`\{\{-?\s*define\s+"([^"]+)"`)
+	templateUseRegexp    = regexp.MustCompile(`\{\{-?\s*template\s+"([^"]+)"`)
+)
+
 type TemplateService struct {
 	configStore     *alertmanagerConfigStoreImpl
 	provenanceStore ProvisioningStore
@@ -38,6 +46,15 @@ func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) (map[st
 	return revision.cfg.TemplateFiles, nil
 }
 
+// GetTemplateReferences returns the names of the contact points that use each template.
+func (t *TemplateService) GetTemplateReferences(ctx context.Context, orgID int64) (map[string][]string, error) {
+	revision, err := t.configStore.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return templateReferences(revision.cfg), nil
+}
+
 func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error) {
 	err := tmpl.Validate()
 	if err != nil {
@@ -78,6 +95,10 @@ func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name
 		return err
 	}
 
+	if receivers := templateReferences(revision.cfg)[name]; len(receivers) > 0 {
+		return MakeErrTemplateInUse(receivers)
+	}
+
 	delete(revision.cfg.TemplateFiles, name)
 
 	return t.xact.InTransaction(ctx, func(ctx context.Context) error {
@@ -90,3 +111,74 @@ func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name
 		return t.provenanceStore.DeleteProvenance(ctx, &tgt, orgID)
 	})
 }
+
+// templateReferences returns the sorted names of the contact points that use each template, keyed by template name.
+// A contact point uses a template if its settings execute a named template defined in it, directly or through other templates.
+func templateReferences(cfg *definitions.PostableUserConfig) map[string][]string {
+	definedIn := make(map[string]string)
+	uses := make(map[string][]string)
+	for name, content := range cfg.TemplateFiles {
+		for _, m := range templateDefineRegexp.FindAllStringSubmatch(content, -1) {
+			definedIn[m[1]] = name
+		}
+		for _, m := range templateUseRegexp.FindAllStringSubmatch(content, -1) {
+			uses[name] = append(uses[name], m[1])
+		}
+	}
+
+	result := make(map[string][]string)
+	for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+		var queue []string
+		for _, integration := range receiver.GrafanaManagedReceivers {
+			queue = append(queue, usedTemplates(integration.Settings)...)
+		}
+		files := make(map[string]struct{})
+		for len(queue) > 0 {
+			file, ok := definedIn[queue[0]]
+			queue = queue[1:]
+			if !ok {
+				continue
+			}
+			if _, ok := files[file]; ok {
+				continue
+			}
+			files[file] = struct{}{}
+			queue = append(queue, uses[file]...)
+		}
+		for file := range files {
+			result[file] = append(result[file], receiver.Name)
+		}
+	}
+	for _, receivers := range result {
+		sort.Strings(receivers)
+	}
+	return result
+}
+
+// usedTemplates returns the names of the templates executed by the settings of an integration.
+func usedTemplates(settings definitions.RawMessage) []string {
+	var values any
+	if err := json.Unmarshal([]byte(settings), &values); err != nil {
+		return nil
+	}
+	var result []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case string:
+			for _, m := range templateUseRegexp.FindAllStringSubmatch(v, -1) {
+				result = append(result, m[1])
+			}
+		case map[string]any:
+			for _, e := range v {
+				walk(e)
+			}
+		case []any:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(values)
+	return result
+}
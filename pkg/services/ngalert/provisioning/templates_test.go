@@ -377,6 +377,35 @@ func TestTemplateService(t *testing.T) {
 
 			require.NoError(t, err)
 		})
+
+		t.Run("rejects deleting templates used by contact points", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.configStore.store.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithUsedTemplates,
+				})
+
+			err := sut.DeleteTemplate(context.Background(), 1, "common")
+
+			require.Truef(t, ErrTemplateInUse.Base.Is(err), "expected ErrTemplateInUse but got %s", err)
+			require.ErrorContains(t, err, "slack-receiver")
+		})
+	})
+
+	t.Run("template references", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		sut.configStore.store.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithUsedTemplates,
+			})
+
+		result, err := sut.GetTemplateReferences(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{
+			"slack":  {"slack-receiver"},
+			"common": {"slack-receiver"},
+		}, result)
 	})
 }
 
@@ -423,6 +452,43 @@ var configWithTemplates = `
 }
 `
 
+var configWithUsedTemplates = `
+{
+	"template_files": {
+		"slack": "{{ define \"slack.title\" }}{{ template \"common.title\" . }}{{ end }}",
+		"common": "{{ define \"common.title\" }}{{ .Status }}{{ end }}",
+		"unused": "{{ define \"unused\" }}{{ end }}"
+	},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "slack-receiver"
+		},
+		"receivers": [{
+			"name": "slack-receiver",
+			"grafana_managed_receiver_configs": [{
+				"uid": "",
+				"name": "slack receiver",
+				"type": "slack",
+				"settings": {
+					"recipient": "#alerts",
+					"title": "{{ template \"slack.title\" . }}"
+				}
+			}]
+		}, {
+			"name": "email-receiver",
+			"grafana_managed_receiver_configs": [{
+				"uid": "",
+				"name": "email receiver",
+				"type": "email",
+				"settings": {
+					"addresses": "<example@email.com>"
+				}
+			}]
+		}]
+	}
+}
+`
+
 var brokenConfig = `
 	"alertmanager_config": {
 		"route": {
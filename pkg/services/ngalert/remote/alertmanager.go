@@ -3,6 +3,7 @@ package remote
 import (
 	"context"
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -176,11 +177,15 @@ func (am *Alertmanager) checkReadiness(ctx context.Context) error {
 // CompareAndSendConfiguration checks whether a given configuration is being used by the remote Alertmanager.
 // If not, it sends the configuration to the remote Alertmanager.
 func (am *Alertmanager) CompareAndSendConfiguration(ctx context.Context, config *models.AlertConfiguration) error {
-	if am.shouldSendConfig(ctx, config) {
+	cfg, err := expandNamedRoutes(config.AlertmanagerConfiguration)
+	if err != nil {
+		return err
+	}
+	if am.shouldSendConfig(ctx, cfg) {
 		am.metrics.ConfigSyncsTotal.Inc()
 		if err := am.mimirClient.CreateGrafanaAlertmanagerConfig(
 			ctx,
-			config.AlertmanagerConfiguration,
+			cfg,
 			config.ConfigurationHash,
 			config.ID,
 			config.CreatedAt,
@@ -375,7 +380,7 @@ func (am *Alertmanager) CleanUp() {}
 
 // shouldSendConfig compares the remote Alertmanager configuration with our local one.
 // It returns true if the configurations are different.
-func (am *Alertmanager) shouldSendConfig(ctx context.Context, config *models.AlertConfiguration) bool {
+func (am *Alertmanager) shouldSendConfig(ctx context.Context, config string) bool {
 	rc, err := am.mimirClient.GetGrafanaAlertmanagerConfig(ctx)
 	if err != nil {
 		// Log the error and return true so we try to upload our config anyway.
@@ -383,7 +388,32 @@ func (am *Alertmanager) shouldSendConfig(ctx context.Context, config *models.Ale
 		return true
 	}
 
-	return md5.Sum([]byte(rc.GrafanaAlertmanagerConfig)) != md5.Sum([]byte(config.AlertmanagerConfiguration))
+	return md5.Sum([]byte(rc.GrafanaAlertmanagerConfig)) != md5.Sum([]byte(config))
+}
+
+// expandNamedRoutes returns the configuration in which the routes that reference named routes are expanded, because
+// the remote Alertmanager does not know about named routes. Configurations without named routes are returned unchanged.
+func expandNamedRoutes(rawConfig string) (string, error) {
+	cfg, err := notifier.Load([]byte(rawConfig))
+	if err != nil {
+		return "", err
+	}
+	if len(cfg.AlertmanagerConfig.NamedRoutes) == 0 {
+		return rawConfig, nil
+	}
+	if cfg.AlertmanagerConfig.Route != nil {
+		route, err := apimodels.ExpandRoute(cfg.AlertmanagerConfig.Route, cfg.AlertmanagerConfig.NamedRoutes)
+		if err != nil {
+			return "", fmt.Errorf("failed to expand the named routes of the configuration: %w", err)
+		}
+		cfg.AlertmanagerConfig.Route = route
+	}
+	cfg.AlertmanagerConfig.NamedRoutes = nil
+	expanded, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	return string(expanded), nil
 }
 
 // shouldSendState compares the remote Alertmanager state with our local one.
@@ -128,6 +128,29 @@ func TestApplyConfig(t *testing.T) {
 	require.True(t, am.Ready())
 }
 
+func TestExpandNamedRoutes(t *testing.T) {
+	t.Run("configuration without named routes is not changed", func(t *testing.T) {
+		cfg, err := expandNamedRoutes(testGrafanaConfig)
+		require.NoError(t, err)
+		require.Equal(t, testGrafanaConfig, cfg)
+	})
+
+	t.Run("routes that reference named routes are expanded", func(t *testing.T) {
+		const withNamedRoutes = `{"alertmanager_config":{"route":{"receiver":"grafana-default-email","routes":[{"ref":"team-a","object_matchers":[["team","=","a"]]}]},"named_routes":[{"name":"team-a","route":{"receiver":"team-a","group_wait":"10s"}}],"receivers":[{"name":"grafana-default-email","grafana_managed_receiver_configs":[{"uid":"","name":"email","type":"email","settings":{"addresses":"a@example.com"}}]},{"name":"team-a","grafana_managed_receiver_configs":[{"uid":"","name":"team-a","type":"email","settings":{"addresses":"team-a@example.com"}}]}]}}`
+		raw, err := expandNamedRoutes(withNamedRoutes)
+		require.NoError(t, err)
+
+		cfg, err := notifier.Load([]byte(raw))
+		require.NoError(t, err)
+		require.Empty(t, cfg.AlertmanagerConfig.NamedRoutes)
+		require.Len(t, cfg.AlertmanagerConfig.Route.Routes, 1)
+		route := cfg.AlertmanagerConfig.Route.Routes[0]
+		require.Empty(t, route.Ref)
+		require.Equal(t, "team-a", route.Receiver)
+		require.Equal(t, "10s", route.GroupWait.String())
+	})
+}
+
 func TestIntegrationRemoteAlertmanagerApplyConfigOnlyUploadsOnce(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// AdminConfigStore provides the admin configuration of organizations, which defines the evaluation interval
+// of rule groups that inherit the interval of their organization.
+type AdminConfigStore interface {
+	GetAdminConfigurations() ([]*ngmodels.AdminConfiguration, error)
+}
+
+// adminConfigCache keeps the admin configurations of all organizations between ticks.
+// The configurations are fetched again once they are older than the poll interval, or after they are invalidated.
+type adminConfigCache struct {
+	mtx       sync.Mutex
+	configs   map[int64]*ngmodels.AdminConfiguration
+	fetchedAt time.Time
+}
+
+// AdminConfigurationChanged drops the cached admin configurations, so that the next tick uses the
+// current evaluation interval of the organization.
+func (sch *schedule) AdminConfigurationChanged(orgID int64) {
+	sch.adminConfigs.mtx.Lock()
+	defer sch.adminConfigs.mtx.Unlock()
+	sch.adminConfigs.configs = nil
+	sch.log.Debug("Admin configuration changed, evaluation intervals will be refreshed", "org", orgID)
+}
+
+// getAdminConfigurations returns the cached admin configurations, fetching them if they are missing or expired.
+// If they cannot be fetched, nil is returned and they are fetched again on the next call.
+func (sch *schedule) getAdminConfigurations() map[int64]*ngmodels.AdminConfiguration {
+	if sch.adminConfigStore == nil {
+		return nil
+	}
+	sch.adminConfigs.mtx.Lock()
+	defer sch.adminConfigs.mtx.Unlock()
+	now := sch.clock.Now()
+	if sch.adminConfigs.configs != nil && now.Sub(sch.adminConfigs.fetchedAt) < sch.adminConfigPollInterval {
+		return sch.adminConfigs.configs
+	}
+	cfgs, err := sch.adminConfigStore.GetAdminConfigurations()
+	if err != nil {
+		sch.log.Error("Failed to fetch admin configurations, rules that inherit the interval of their organization use the default interval", "defaultInterval", sch.defaultRuleInterval, "error", err)
+		return nil
+	}
+	configs := make(map[int64]*ngmodels.AdminConfiguration, len(cfgs))
+	for _, cfg := range cfgs {
+		configs[cfg.OrgID] = cfg
+	}
+	sch.adminConfigs.configs = configs
+	sch.adminConfigs.fetchedAt = now
+	return configs
+}
+
+// inheritedIntervals returns a function that resolves the evaluation interval of rules that inherit the interval of their organization.
+// The admin configurations are read lazily from the cache, at most once per call of inheritedIntervals.
+func (sch *schedule) inheritedIntervals() func(orgID int64) int64 {
+	defaultIntervalSeconds := int64(sch.defaultRuleInterval.Seconds())
+	var configs map[int64]*ngmodels.AdminConfiguration
+	var loaded bool
+	return func(orgID int64) int64 {
+		if !loaded {
+			configs = sch.getAdminConfigurations()
+			loaded = true
+		}
+		return configs[orgID].EffectiveEvaluationIntervalSeconds(defaultIntervalSeconds)
+	}
+}
+
+// withInterval returns a copy of the rule that is evaluated with the given interval.
+// The rule is copied because the rules stored by the scheduler must keep their inherited interval.
+func withInterval(rule *ngmodels.AlertRule, intervalSeconds int64) *ngmodels.AlertRule {
+	result := *rule
+	result.IntervalSeconds = intervalSeconds
+	return &result
+}
+
+// effectiveDefaultRuleInterval returns the interval rules that inherit the interval of their organization fall back to.
+func effectiveDefaultRuleInterval(defaultInterval, baseInterval time.Duration) time.Duration {
+	if defaultInterval <= 0 {
+		return baseInterval
+	}
+	return defaultInterval
+}
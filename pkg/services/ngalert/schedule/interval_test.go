@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeAdminConfigStore struct {
+	configs []*models.AdminConfiguration
+	err     error
+	calls   int
+}
+
+func (f *fakeAdminConfigStore) GetAdminConfigurations() ([]*models.AdminConfiguration, error) {
+	f.calls++
+	return f.configs, f.err
+}
+
+func TestSchedule_inheritedIntervals(t *testing.T) {
+	t.Run("should resolve the interval of the organization", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		sch.defaultRuleInterval = time.Minute
+		configStore := &fakeAdminConfigStore{configs: []*models.AdminConfiguration{
+			{OrgID: 1, DefaultEvaluationIntervalSeconds: 300},
+			{OrgID: 2},
+		}}
+		sch.adminConfigStore = configStore
+
+		resolve := sch.inheritedIntervals()
+		require.Equal(t, int64(300), resolve(1))
+		require.Equal(t, int64(60), resolve(2))
+		require.Equal(t, int64(60), resolve(3))
+		require.Equal(t, 1, configStore.calls)
+	})
+
+	t.Run("should fall back to the default interval if configurations cannot be fetched", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		sch.defaultRuleInterval = time.Minute
+		sch.adminConfigStore = &fakeAdminConfigStore{err: errors.New("failed")}
+
+		require.Equal(t, int64(60), sch.inheritedIntervals()(1))
+	})
+
+	t.Run("should cache configurations until they expire or change", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		sch.adminConfigPollInterval = time.Minute
+		configStore := &fakeAdminConfigStore{configs: []*models.AdminConfiguration{
+			{OrgID: 1, DefaultEvaluationIntervalSeconds: 300},
+		}}
+		sch.adminConfigStore = configStore
+
+		require.Equal(t, int64(300), sch.inheritedIntervals()(1))
+		require.Equal(t, int64(300), sch.inheritedIntervals()(1))
+		require.Equal(t, 1, configStore.calls)
+
+		sch.clock.(*clock.Mock).Add(time.Minute)
+		require.Equal(t, int64(300), sch.inheritedIntervals()(1))
+		require.Equal(t, 2, configStore.calls)
+
+		configStore.configs[0].DefaultEvaluationIntervalSeconds = 600
+		sch.AdminConfigurationChanged(1)
+		require.Equal(t, int64(600), sch.inheritedIntervals()(1))
+		require.Equal(t, 3, configStore.calls)
+	})
+
+	t.Run("should not cache configurations that cannot be fetched", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		sch.adminConfigPollInterval = time.Minute
+		configStore := &fakeAdminConfigStore{err: errors.New("failed")}
+		sch.adminConfigStore = configStore
+
+		sch.inheritedIntervals()(1)
+		sch.inheritedIntervals()(1)
+		require.Equal(t, 2, configStore.calls)
+	})
+
+	t.Run("should not fetch configurations if no rule inherits the interval", func(t *testing.T) {
+		ruleStore := newFakeRulesStore()
+		ruleStore.PutRule(context.Background(), models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(time.Second))())
+		sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+		configStore := &fakeAdminConfigStore{}
+		sch.adminConfigStore = configStore
+
+		dispatcherGroup, ctx := errgroup.WithContext(context.Background())
+		sch.processTick(ctx, dispatcherGroup, time.Unix(1, 0))
+		require.Zero(t, configStore.calls)
+	})
+}
+
+func TestProcessTicks_InheritedInterval(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	rule := models.AlertRuleGen(models.WithOrgID(1), func(r *models.AlertRule) {
+		r.IntervalSeconds = models.InheritedIntervalSeconds
+	})()
+	ruleStore.PutRule(context.Background(), rule)
+
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sch.jitterEvaluations = JitterNever
+	sch.adminConfigStore = &fakeAdminConfigStore{configs: []*models.AdminConfiguration{
+		{OrgID: 1, DefaultEvaluationIntervalSeconds: 2},
+	}}
+
+	dispatcherGroup, ctx := errgroup.WithContext(context.Background())
+
+	scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, time.Unix(2, 0))
+	require.Len(t, scheduled, 1)
+	require.Equal(t, int64(2), scheduled[0].rule.IntervalSeconds)
+
+	scheduled, _, _ = sch.processTick(ctx, dispatcherGroup, time.Unix(3, 0))
+	require.Empty(t, scheduled)
+
+	// the rule stored by the scheduler still inherits the interval
+	rules, _ := sch.Rules()
+	require.Len(t, rules, 1)
+	require.True(t, rules[0].InheritsInterval())
+}
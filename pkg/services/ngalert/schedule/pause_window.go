@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// PauseWindowStore provides the evaluation pause windows of organizations and folders.
+type PauseWindowStore interface {
+	GetEvaluationPauseWindowsEndingAfter(ctx context.Context, t time.Time) (ngmodels.EvaluationPauseWindows, error)
+}
+
+// pauseWindowCache keeps the pause windows that did not end between ticks, including the ones that did not start yet.
+// The windows are fetched again once they are older than the poll interval, or after they are invalidated.
+type pauseWindowCache struct {
+	mtx       sync.Mutex
+	windows   ngmodels.EvaluationPauseWindows
+	valid     bool
+	fetchedAt time.Time
+}
+
+// PauseWindowsChanged drops the cached pause windows, so that the next tick uses the current windows.
+func (sch *schedule) PauseWindowsChanged(orgID int64) {
+	sch.pauseWindows.mtx.Lock()
+	defer sch.pauseWindows.mtx.Unlock()
+	sch.pauseWindows.valid = false
+	sch.log.Debug("Evaluation pause windows changed, they will be refreshed", "org", orgID)
+}
+
+// currentPauseWindows returns the pause windows that did not end at the tick, from the cache if it is not expired.
+// If they cannot be fetched, no rule is paused, so that a failing database does not silently stop the evaluation of all rules.
+func (sch *schedule) currentPauseWindows(ctx context.Context, tick time.Time) ngmodels.EvaluationPauseWindows {
+	if sch.pauseWindowStore == nil {
+		return nil
+	}
+	sch.pauseWindows.mtx.Lock()
+	defer sch.pauseWindows.mtx.Unlock()
+	now := sch.clock.Now()
+	if sch.pauseWindows.valid && now.Sub(sch.pauseWindows.fetchedAt) < sch.adminConfigPollInterval {
+		return sch.pauseWindows.windows
+	}
+	// the windows are fetched from the earliest of the tick and now, so that the cache serves late ticks as well
+	from := tick
+	if now.Before(from) {
+		from = now
+	}
+	windows, err := sch.pauseWindowStore.GetEvaluationPauseWindowsEndingAfter(ctx, from)
+	if err != nil {
+		sch.log.Error("Failed to fetch evaluation pause windows, rules are evaluated as if there were none", "error", err)
+		return nil
+	}
+	sch.pauseWindows.windows = windows
+	sch.pauseWindows.valid = true
+	sch.pauseWindows.fetchedAt = now
+	return windows
+}
@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakePauseWindowStore struct {
+	windows models.EvaluationPauseWindows
+	err     error
+	calls   int
+}
+
+func (f *fakePauseWindowStore) GetEvaluationPauseWindowsEndingAfter(_ context.Context, t time.Time) (models.EvaluationPauseWindows, error) {
+	f.calls++
+	var result models.EvaluationPauseWindows
+	for _, w := range f.windows {
+		if w.EndsAt.After(t) {
+			result = append(result, w)
+		}
+	}
+	return result, f.err
+}
+
+func TestProcessTicks_PauseWindows(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	folderRule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(time.Second), models.WithNamespace(&folder.Folder{UID: "paused"}))()
+	otherRule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(time.Second), models.WithNamespace(&folder.Folder{UID: "other"}))()
+	otherOrgRule := models.AlertRuleGen(models.WithOrgID(2), models.WithInterval(time.Second))()
+	ruleStore.PutRule(context.Background(), folderRule, otherRule, otherOrgRule)
+
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sch.jitterEvaluations = JitterNever
+	pauseStore := &fakePauseWindowStore{windows: models.EvaluationPauseWindows{
+		{OrgID: 1, FolderUID: "paused", StartsAt: time.Unix(2, 0), EndsAt: time.Unix(4, 0)},
+		{OrgID: 2, StartsAt: time.Unix(3, 0), EndsAt: time.Unix(4, 0)},
+	}}
+	sch.pauseWindowStore = pauseStore
+
+	scheduledUIDs := func(tick time.Time) []string {
+		dispatcherGroup, ctx := errgroup.WithContext(context.Background())
+		scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		var uids []string
+		for _, s := range scheduled {
+			uids = append(uids, s.rule.UID)
+		}
+		return uids
+	}
+
+	require.ElementsMatch(t, []string{folderRule.UID, otherRule.UID, otherOrgRule.UID}, scheduledUIDs(time.Unix(1, 0)))
+	require.ElementsMatch(t, []string{otherRule.UID, otherOrgRule.UID}, scheduledUIDs(time.Unix(2, 0)))
+	require.ElementsMatch(t, []string{otherRule.UID}, scheduledUIDs(time.Unix(3, 0)))
+	require.ElementsMatch(t, []string{folderRule.UID, otherRule.UID, otherOrgRule.UID}, scheduledUIDs(time.Unix(4, 0)))
+	require.Equal(t, 1, pauseStore.calls, "pause windows should be fetched once per poll interval")
+
+	t.Run("pause windows are fetched again when they change", func(t *testing.T) {
+		pauseStore.windows = append(pauseStore.windows, &models.EvaluationPauseWindow{OrgID: 1, StartsAt: time.Unix(5, 0), EndsAt: time.Unix(6, 0)})
+		require.ElementsMatch(t, []string{folderRule.UID, otherRule.UID, otherOrgRule.UID}, scheduledUIDs(time.Unix(5, 0)))
+
+		sch.PauseWindowsChanged(1)
+		require.ElementsMatch(t, []string{otherOrgRule.UID}, scheduledUIDs(time.Unix(5, 0)))
+		require.Equal(t, 2, pauseStore.calls)
+	})
+
+	t.Run("rules are evaluated if pause windows cannot be fetched", func(t *testing.T) {
+		pauseStore.err = errors.New("failed")
+		sch.PauseWindowsChanged(1)
+		require.ElementsMatch(t, []string{folderRule.UID, otherRule.UID, otherOrgRule.UID}, scheduledUIDs(time.Unix(3, 0)))
+	})
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
-	"math"
 	"sort"
 	"sync"
 	"time"
@@ -248,7 +247,8 @@ type ruleWithFolder struct {
 	folderTitle string
 }
 
-// fingerprint calculates a fingerprint that includes all fields except rule's Version and Update timestamp.
+// fingerprint calculates a fingerprint that includes all fields except rule's Version, Update timestamp and group.
+// The group is excluded so that renaming the group or reordering its rules does not reset the state of the rule.
 func (r ruleWithFolder) Fingerprint() fingerprint {
 	rule := r.rule
 
@@ -275,7 +275,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 
 	// allocate a slice that will be used for sorting keys, so we allocate it only once
 	var keys []string
-	maxLen := int(math.Max(math.Max(float64(len(rule.Annotations)), float64(len(rule.Labels))), float64(len(rule.Data))))
+	maxLen := max(len(rule.Annotations), len(rule.Labels), len(rule.GroupLabels), len(rule.Data))
 	if maxLen > 0 {
 		keys = make([]string, maxLen)
 	}
@@ -327,8 +327,19 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeString(rule.NamespaceUID)
 	writeString(r.folderTitle)
 	writeLabels(rule.Labels)
+	writeLabels(rule.GroupLabels)
 	writeString(rule.Condition)
 	writeQuery()
+	for _, name := range rule.FingerprintLabels {
+		writeString(name)
+	}
+	if rule.PartialResults {
+		writeInt(1)
+	} else {
+		writeInt(0)
+	}
+	writeInt(rule.MissingSeriesEvalsToResolve)
+	writeInt(int64(rule.MissingSeriesDurationToResolve))
 
 	if rule.IsPaused {
 		writeInt(1)
@@ -349,8 +360,6 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	if rule.PanelID != nil {
 		writeInt(*rule.PanelID)
 	}
-	writeString(rule.RuleGroup)
-	writeInt(int64(rule.RuleGroupIndex))
 	writeString(string(rule.NoDataState))
 	writeString(string(rule.ExecErrState))
 	return fingerprint(sum.Sum64())
@@ -377,6 +377,14 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 		f2 := ruleWithFolder{rule: cp, folderTitle: title}.Fingerprint()
 		require.Equal(t, f, f2)
 	})
+	t.Run("group should be excluded from fingerprint", func(t *testing.T) {
+		cp := models.CopyRule(rule)
+		cp.RuleGroup = uuid.NewString()
+		cp.RuleGroupIndex++
+
+		f2 := ruleWithFolder{rule: cp, folderTitle: title}.Fingerprint()
+		require.Equal(t, f, f2)
+	})
 
 	t.Run("all other fields should be considered", func(t *testing.T) {
 		r1 := &models.AlertRule{
@@ -414,7 +422,12 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			Labels: map[string]string{
 				"key-label": "value-label",
 			},
-			IsPaused: false,
+			IsPaused:          false,
+			FingerprintLabels: []string{"key-label"},
+			PartialResults:    false,
+			GroupLabels: map[string]string{
+				"key-group-label": "value-group-label",
+			},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -449,13 +462,23 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			Labels: map[string]string{
 				"key-label": "value-label23",
 			},
-			IsPaused: true,
+			IsPaused:          true,
+			FingerprintLabels: []string{"key-label", "instance"},
+			PartialResults:    true,
+			GroupLabels: map[string]string{
+				"key-group-label": "value-group-label-2",
+			},
 		}
 
 		excludedFields := map[string]struct{}{
 			"Version": {},
 			"Updated": {},
 		}
+		// the group does not change the state, the rule keeps its state when the group is renamed
+		fingerprintExcludedFields := map[string]struct{}{
+			"RuleGroup":      {},
+			"RuleGroupIndex": {},
+		}
 
 		tp := reflect.TypeOf(rule).Elem()
 		var nonDiffFields []string
@@ -475,6 +498,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 		require.Emptyf(t, nonDiffFields, "cannot generate completely unique alert rule. Some fields are not randomized")
 
 		r2v := reflect.ValueOf(r2).Elem()
+		f1 := ruleWithFolder{rule: r1, folderTitle: title}.Fingerprint()
 		for i := 0; i < tp.NumField(); i++ {
 			if _, ok := excludedFields[tp.Field(i).Name]; ok {
 				continue
@@ -484,6 +508,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			vf := v.Field(i)
 			vf.Set(r2v.Field(i))
 			f2 := ruleWithFolder{rule: cp, folderTitle: title}.Fingerprint()
+			if _, ok := fingerprintExcludedFields[tp.Field(i).Name]; ok {
+				require.Equalf(t, f1, f2, "Field %s should not be used in fingerprint", tp.Field(i).Name)
+				continue
+			}
 			if f2 == f {
 				t.Fatalf("Field %s does not seem to be used in fingerprint. Diff: %s", tp.Field(i).Name, r1.Diff(cp))
 			}
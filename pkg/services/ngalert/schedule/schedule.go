@@ -88,30 +88,48 @@ type schedule struct {
 	alertsSender    AlertsSender
 	minRuleInterval time.Duration
 
+	// defaultRuleInterval is the evaluation interval of rules whose organization does not define one, if they inherit it.
+	defaultRuleInterval time.Duration
+	adminConfigStore    AdminConfigStore
+	// adminConfigPollInterval is how long the admin configurations are cached before they are fetched again.
+	adminConfigPollInterval time.Duration
+	adminConfigs            adminConfigCache
+
+	// pauseWindowStore provides the windows during which the rules of an organization or folder are not evaluated.
+	pauseWindowStore PauseWindowStore
+	pauseWindows     pauseWindowCache
+
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
 	// current tick depends on its evaluation interval and when it was
 	// last evaluated.
 	schedulableAlertRules alertRulesRegistry
 
+	// evaluations contains the outcome of the last evaluation of the rules, to report it with their next evaluation.
+	evaluations ruleEvaluations
+
 	tracer tracing.Tracer
 }
 
 // SchedulerCfg is the scheduler configuration.
 type SchedulerCfg struct {
-	MaxAttempts          int64
-	BaseInterval         time.Duration
-	C                    clock.Clock
-	MinRuleInterval      time.Duration
-	DisableGrafanaFolder bool
-	AppURL               *url.URL
-	JitterEvaluations    JitterStrategy
-	EvaluatorFactory     eval.EvaluatorFactory
-	RuleStore            RulesStore
-	Metrics              *metrics.Scheduler
-	AlertSender          AlertsSender
-	Tracer               tracing.Tracer
-	Log                  log.Logger
+	MaxAttempts             int64
+	BaseInterval            time.Duration
+	C                       clock.Clock
+	MinRuleInterval         time.Duration
+	DefaultRuleInterval     time.Duration
+	DisableGrafanaFolder    bool
+	AppURL                  *url.URL
+	JitterEvaluations       JitterStrategy
+	EvaluatorFactory        eval.EvaluatorFactory
+	RuleStore               RulesStore
+	AdminConfigStore        AdminConfigStore
+	AdminConfigPollInterval time.Duration
+	PauseWindowStore        PauseWindowStore
+	Metrics                 *metrics.Scheduler
+	AlertSender             AlertsSender
+	Tracer                  tracing.Tracer
+	Log                     log.Logger
 }
 
 // NewScheduler returns a new schedule.
@@ -123,22 +141,26 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 	}
 
 	sch := schedule{
-		registry:              alertRuleInfoRegistry{alertRuleInfo: make(map[ngmodels.AlertRuleKey]*alertRuleInfo)},
-		maxAttempts:           cfg.MaxAttempts,
-		clock:                 cfg.C,
-		baseInterval:          cfg.BaseInterval,
-		log:                   cfg.Log,
-		evaluatorFactory:      cfg.EvaluatorFactory,
-		ruleStore:             cfg.RuleStore,
-		metrics:               cfg.Metrics,
-		appURL:                cfg.AppURL,
-		disableGrafanaFolder:  cfg.DisableGrafanaFolder,
-		jitterEvaluations:     cfg.JitterEvaluations,
-		stateManager:          stateManager,
-		minRuleInterval:       cfg.MinRuleInterval,
-		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
-		alertsSender:          cfg.AlertSender,
-		tracer:                cfg.Tracer,
+		registry:                alertRuleInfoRegistry{alertRuleInfo: make(map[ngmodels.AlertRuleKey]*alertRuleInfo)},
+		maxAttempts:             cfg.MaxAttempts,
+		clock:                   cfg.C,
+		baseInterval:            cfg.BaseInterval,
+		log:                     cfg.Log,
+		evaluatorFactory:        cfg.EvaluatorFactory,
+		ruleStore:               cfg.RuleStore,
+		metrics:                 cfg.Metrics,
+		appURL:                  cfg.AppURL,
+		disableGrafanaFolder:    cfg.DisableGrafanaFolder,
+		jitterEvaluations:       cfg.JitterEvaluations,
+		stateManager:            stateManager,
+		minRuleInterval:         cfg.MinRuleInterval,
+		defaultRuleInterval:     effectiveDefaultRuleInterval(cfg.DefaultRuleInterval, cfg.BaseInterval),
+		adminConfigStore:        cfg.AdminConfigStore,
+		adminConfigPollInterval: cfg.AdminConfigPollInterval,
+		pauseWindowStore:        cfg.PauseWindowStore,
+		schedulableAlertRules:   alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
+		alertsSender:            cfg.AlertSender,
+		tracer:                  cfg.Tracer,
 	}
 
 	return &sch
@@ -179,6 +201,7 @@ func (sch *schedule) deleteAlertRule(keys ...ngmodels.AlertRuleKey) {
 		}
 		// stop rule evaluation
 		ruleInfo.stop(errRuleDeleted)
+		sch.evaluations.del(key)
 	}
 	// Our best bet at this point is that we update the metrics with what we hope to schedule in the next tick.
 	alertRules, _ := sch.schedulableAlertRules.all()
@@ -277,10 +300,16 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	missingFolder := make(map[string][]string)
+	inheritedInterval := sch.inheritedIntervals()
+	pauseWindows := sch.currentPauseWindows(ctx, tick)
 	for _, item := range alertRules {
 		key := item.GetKey()
 		ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
+		if item.InheritsInterval() {
+			item = withInterval(item, inheritedInterval(item.OrgID))
+		}
+
 		// enforce minimum evaluation interval
 		if item.IntervalSeconds < int64(sch.minRuleInterval.Seconds()) {
 			sch.log.Debug("Interval adjusted", append(key.LogContext(), "originalInterval", item.IntervalSeconds, "adjustedInterval", sch.minRuleInterval.Seconds())...)
@@ -316,6 +345,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 			}
 		}
 
+		if isReadyToRun && pauseWindows.Pauses(item, tick) {
+			sch.log.Debug("Rule evaluation is paused by an evaluation pause window", append(key.LogContext(), "tick", tickNum)...)
+			isReadyToRun = false
+		}
+
 		if isReadyToRun {
 			sch.log.Debug("Rule is ready to run on the current tick", "uid", item.UID, "tick", tickNum, "frequency", itemFrequency, "offset", offset)
 			readyToRun = append(readyToRun, readyToRunItem{ruleInfo: ruleInfo, evaluation: evaluation{
@@ -434,6 +468,14 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		evalTotal.Inc()
 		evalDuration.Observe(dur.Seconds())
 
+		status := ngmodels.RuleEvaluationStatus{ScheduledAt: e.scheduledAt, EvaluatedAt: start, Duration: dur, Attempt: attempt}
+		if err != nil {
+			status.Error = err.Error()
+		} else if resultsErr := results.Error(); resultsErr != nil {
+			status.Error = resultsErr.Error()
+		}
+		sch.evaluations.set(key, status)
+
 		if ctx.Err() != nil { // check if the context is not cancelled. The evaluation can be a long-running task.
 			span.SetStatus(codes.Error, "rule evaluation cancelled")
 			logger.Debug("Skip updating the state because the context has been cancelled")
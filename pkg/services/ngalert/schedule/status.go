@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleEvaluations keeps the outcome of the last evaluation of each rule evaluated by the scheduler.
+type ruleEvaluations struct {
+	mu   sync.Mutex
+	last map[ngmodels.AlertRuleKey]ngmodels.RuleEvaluationStatus
+}
+
+func (r *ruleEvaluations) set(key ngmodels.AlertRuleKey, status ngmodels.RuleEvaluationStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil {
+		r.last = make(map[ngmodels.AlertRuleKey]ngmodels.RuleEvaluationStatus)
+	}
+	r.last[key] = status
+}
+
+func (r *ruleEvaluations) get(key ngmodels.AlertRuleKey) (ngmodels.RuleEvaluationStatus, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status, ok := r.last[key]
+	return status, ok
+}
+
+func (r *ruleEvaluations) del(key ngmodels.AlertRuleKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.last, key)
+}
+
+// RuleSchedulingStatus returns the view of the scheduler on the rule: the outcome of its last evaluation and when it
+// is evaluated next, or why it is not. It applies the same interval, jitter and pause rules as the scheduling loop.
+func (sch *schedule) RuleSchedulingStatus(ctx context.Context, key ngmodels.AlertRuleKey) ngmodels.RuleSchedulingStatus {
+	var result ngmodels.RuleSchedulingStatus
+	if last, ok := sch.evaluations.get(key); ok {
+		result.LastEvaluation = &last
+	}
+
+	rule := sch.schedulableAlertRules.get(key)
+	if rule == nil || !sch.registry.exists(key) {
+		result.Reason = ngmodels.SchedulingReasonNotScheduled
+		return result
+	}
+	if rule.InheritsInterval() {
+		rule = withInterval(rule, sch.inheritedIntervals()(rule.OrgID))
+	}
+	if rule.IntervalSeconds < int64(sch.minRuleInterval.Seconds()) {
+		rule = withInterval(rule, int64(sch.minRuleInterval.Seconds()))
+	}
+	result.IntervalSeconds = rule.IntervalSeconds
+
+	if rule.IntervalSeconds == 0 || rule.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0 {
+		result.Reason = ngmodels.SchedulingReasonInvalidInterval
+		return result
+	}
+	if rule.IsPaused {
+		result.Reason = ngmodels.SchedulingReasonPaused
+		return result
+	}
+
+	next := sch.nextEvaluation(rule, sch.clock.Now())
+	result.Scheduled = true
+	result.NextEvaluation = next
+	if sch.currentPauseWindows(ctx, next).Pauses(rule, next) {
+		result.Reason = ngmodels.SchedulingReasonPauseWindow
+	}
+	return result
+}
+
+// nextEvaluation returns the first tick after now at which the rule is ready to run.
+func (sch *schedule) nextEvaluation(rule *ngmodels.AlertRule, now time.Time) time.Time {
+	baseSeconds := int64(sch.baseInterval.Seconds())
+	frequency := rule.IntervalSeconds / baseSeconds
+	offset := jitterOffsetInTicks(rule, sch.baseInterval, sch.jitterEvaluations)
+
+	tickNum := now.Unix()/baseSeconds + 1
+	tickNum += ((offset-tickNum%frequency)%frequency + frequency) % frequency
+	return time.Unix(tickNum*baseSeconds, 0)
+}
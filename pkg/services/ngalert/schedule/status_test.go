@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRuleSchedulingStatus(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	rule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(10*time.Second), models.WithNamespace(&folder.Folder{UID: "folder"}))()
+	rule.IsPaused = false
+	pausedRule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(10*time.Second), models.WithNamespace(&folder.Folder{UID: "folder"}))()
+	pausedRule.IsPaused = true
+	windowRule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(10*time.Second), models.WithNamespace(&folder.Folder{UID: "paused"}))()
+	windowRule.IsPaused = false
+	ruleStore.PutRule(context.Background(), rule, pausedRule, windowRule)
+
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sch.jitterEvaluations = JitterNever
+	sch.pauseWindowStore = &fakePauseWindowStore{windows: models.EvaluationPauseWindows{
+		{OrgID: 1, FolderUID: "paused", StartsAt: time.Unix(15, 0), EndsAt: time.Unix(25, 0)},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	sch.processTick(ctx, dispatcherGroup, time.Unix(1, 0))
+	sch.clock.(*clock.Mock).Set(time.Unix(12, 0))
+
+	t.Run("should report the next evaluation of scheduled rules", func(t *testing.T) {
+		status := sch.RuleSchedulingStatus(ctx, rule.GetKey())
+		require.True(t, status.Scheduled)
+		require.Empty(t, status.Reason)
+		require.Equal(t, int64(10), status.IntervalSeconds)
+		require.True(t, status.NextEvaluation.Equal(time.Unix(20, 0)), status.NextEvaluation)
+		require.Nil(t, status.LastEvaluation)
+	})
+
+	t.Run("should report the last evaluation of rules", func(t *testing.T) {
+		last := models.RuleEvaluationStatus{ScheduledAt: time.Unix(10, 0), EvaluatedAt: time.Unix(10, 0), Duration: time.Second, Attempt: 1, Error: "failed"}
+		sch.evaluations.set(rule.GetKey(), last)
+		status := sch.RuleSchedulingStatus(ctx, rule.GetKey())
+		require.Equal(t, &last, status.LastEvaluation)
+	})
+
+	t.Run("should report why rules are not evaluated", func(t *testing.T) {
+		status := sch.RuleSchedulingStatus(ctx, pausedRule.GetKey())
+		require.False(t, status.Scheduled)
+		require.Equal(t, models.SchedulingReasonPaused, status.Reason)
+
+		status = sch.RuleSchedulingStatus(ctx, windowRule.GetKey())
+		require.True(t, status.Scheduled)
+		require.Equal(t, models.SchedulingReasonPauseWindow, status.Reason)
+
+		status = sch.RuleSchedulingStatus(ctx, models.AlertRuleKey{OrgID: 1, UID: "unknown"})
+		require.False(t, status.Scheduled)
+		require.Equal(t, models.SchedulingReasonNotScheduled, status.Reason)
+	})
+
+	t.Run("should forget the last evaluation of deleted rules", func(t *testing.T) {
+		sch.deleteAlertRule(rule.GetKey())
+		status := sch.RuleSchedulingStatus(ctx, rule.GetKey())
+		require.Equal(t, models.SchedulingReasonNotScheduled, status.Reason)
+		require.Nil(t, status.LastEvaluation)
+	})
+}
+
+func TestNextEvaluation(t *testing.T) {
+	sch := &schedule{baseInterval: 10 * time.Second, jitterEvaluations: JitterNever}
+	rule := &models.AlertRule{IntervalSeconds: 60}
+
+	require.Equal(t, int64(60), sch.nextEvaluation(rule, time.Unix(0, 0)).Unix())
+	require.Equal(t, int64(60), sch.nextEvaluation(rule, time.Unix(59, 0)).Unix())
+	require.Equal(t, int64(120), sch.nextEvaluation(rule, time.Unix(60, 0)).Unix())
+	require.Equal(t, int64(120), sch.nextEvaluation(rule, time.Unix(61, 0)).Unix())
+}
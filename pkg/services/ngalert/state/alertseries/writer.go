@@ -0,0 +1,265 @@
+package alertseries
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/client"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	alertsMetricName         = "ALERTS"
+	alertsForStateMetricName = "ALERTS_FOR_STATE"
+	alertStateLabel          = "alertstate"
+
+	alertStatePending = "pending"
+	alertStateFiring  = "firing"
+
+	// maxBufferedSeries is the maximum number of series kept while the endpoint cannot be reached.
+	// The oldest series are dropped first.
+	maxBufferedSeries = 100_000
+)
+
+// Config is the configuration of the remote-write endpoint the series are written to.
+type Config struct {
+	WriteURL          *url.URL
+	TenantID          string
+	BasicAuthUser     string
+	BasicAuthPassword string
+	Interval          time.Duration
+	Timeout           time.Duration
+}
+
+func NewConfig(cfg setting.UnifiedAlertingStateRemoteWriteSettings) (Config, error) {
+	if cfg.URL == "" {
+		return Config{}, fmt.Errorf("remote-write URL must be provided")
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse remote-write URL: %w", err)
+	}
+	return Config{
+		WriteURL:          u,
+		TenantID:          cfg.TenantID,
+		BasicAuthUser:     cfg.BasicAuthUsername,
+		BasicAuthPassword: cfg.BasicAuthPassword,
+		Interval:          cfg.Interval,
+		Timeout:           cfg.Timeout,
+	}, nil
+}
+
+// Writer buffers the ALERTS and ALERTS_FOR_STATE series of evaluated alerts, and periodically sends them to a Prometheus
+// remote-write endpoint. It implements state.AlertSeriesWriter.
+type Writer struct {
+	cfg    Config
+	client client.Requester
+	log    log.Logger
+
+	mtx    sync.Mutex
+	buffer []prompb.TimeSeries
+}
+
+func NewWriter(cfg Config, req client.Requester, logger log.Logger) *Writer {
+	return &Writer{
+		cfg:    cfg,
+		client: req,
+		log:    logger,
+	}
+}
+
+// Write adds the series of the states to the buffer.
+func (w *Writer) Write(_ context.Context, evaluatedAt time.Time, states []state.StateTransition) {
+	var series []prompb.TimeSeries
+	for _, s := range states {
+		series = append(series, seriesFor(evaluatedAt, s)...)
+	}
+	if len(series) == 0 {
+		return
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.buffer = append(w.buffer, series...)
+	w.trim()
+}
+
+// trim drops the oldest series if the buffer is full. It must be called with the lock held.
+func (w *Writer) trim() {
+	if overflow := len(w.buffer) - maxBufferedSeries; overflow > 0 {
+		w.log.Warn("Too many alert series are waiting to be written, dropping the oldest ones", "dropped", overflow)
+		w.buffer = w.buffer[overflow:]
+	}
+}
+
+// Run sends the buffered series to the endpoint at every interval, until the context is done.
+func (w *Writer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.flush(ctx); err != nil {
+				w.log.Error("Failed to write alert series", "error", err)
+			}
+		case <-ctx.Done():
+			// send what is left, but do not block the shutdown for longer than a request
+			flushCtx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+			defer cancel()
+			if err := w.flush(flushCtx); err != nil {
+				w.log.Error("Failed to write alert series on shutdown", "error", err)
+			}
+			return nil
+		}
+	}
+}
+
+// flush sends the buffered series. If they cannot be sent, they are kept for the next attempt.
+func (w *Writer) flush(ctx context.Context) error {
+	w.mtx.Lock()
+	series := w.buffer
+	w.buffer = nil
+	w.mtx.Unlock()
+	if len(series) == 0 {
+		return nil
+	}
+
+	if err := w.send(ctx, series); err != nil {
+		w.mtx.Lock()
+		w.buffer = append(series, w.buffer...)
+		w.trim()
+		w.mtx.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (w *Writer) send(ctx context.Context, series []prompb.TimeSeries) error {
+	raw, err := proto.Marshal(&prompb.WriteRequest{Timeseries: series})
+	if err != nil {
+		return fmt.Errorf("failed to encode series: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.WriteURL.String(), bytes.NewReader(snappy.Encode(nil, raw)))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.cfg.BasicAuthUser != "" || w.cfg.BasicAuthPassword != "" {
+		req.SetBasicAuth(w.cfg.BasicAuthUser, w.cfg.BasicAuthPassword)
+	}
+	if w.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", w.cfg.TenantID)
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			w.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("remote-write endpoint returned status code %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// seriesFor returns the samples of the ALERTS and ALERTS_FOR_STATE series of the state at the time it was evaluated.
+// The series of an alert that is no longer pending or firing are ended with a staleness marker, like the Prometheus ruler does.
+func seriesFor(evaluatedAt time.Time, s state.StateTransition) []prompb.TimeSeries {
+	current := alertState(s.State.State)
+	previous := alertState(s.PreviousState)
+	if current == "" && previous == "" {
+		return nil
+	}
+
+	ts := evaluatedAt.UnixMilli()
+	labels := promLabels(s.Labels)
+	staleNaN := math.Float64frombits(value.StaleNaN)
+
+	var result []prompb.TimeSeries
+	if current != "" {
+		result = append(result,
+			newSeries(alertsMetricName, labels, current, 1, ts),
+			newSeries(alertsForStateMetricName, labels, "", float64(s.StartsAt.Unix()), ts),
+		)
+	}
+	if previous != "" && previous != current {
+		result = append(result, newSeries(alertsMetricName, labels, previous, staleNaN, ts))
+		if current == "" {
+			result = append(result, newSeries(alertsForStateMetricName, labels, "", staleNaN, ts))
+		}
+	}
+	return result
+}
+
+func alertState(s eval.State) string {
+	switch s {
+	case eval.Pending:
+		return alertStatePending
+	case eval.Alerting:
+		return alertStateFiring
+	default:
+		return ""
+	}
+}
+
+// promLabels returns the labels of the alert that are valid Prometheus label names.
+// Labels reserved for internal use, that start with a double underscore, are dropped.
+func promLabels(lbls map[string]string) []prompb.Label {
+	result := make([]prompb.Label, 0, len(lbls))
+	for name, v := range lbls {
+		if strings.HasPrefix(name, "__") || !model.LabelName(name).IsValid() {
+			continue
+		}
+		result = append(result, prompb.Label{Name: name, Value: v})
+	}
+	return result
+}
+
+func newSeries(metric string, labels []prompb.Label, alertstate string, v float64, ts int64) prompb.TimeSeries {
+	lbls := make([]prompb.Label, 0, len(labels)+2)
+	lbls = append(lbls, prompb.Label{Name: model.MetricNameLabel, Value: metric})
+	if alertstate != "" {
+		lbls = append(lbls, prompb.Label{Name: alertStateLabel, Value: alertstate})
+	}
+	for _, l := range labels {
+		if l.Name == alertStateLabel {
+			continue
+		}
+		lbls = append(lbls, l)
+	}
+	// remote-write requires the labels to be sorted by name
+	sort.Slice(lbls, func(i, j int) bool {
+		return lbls[i].Name < lbls[j].Name
+	})
+	return prompb.TimeSeries{
+		Labels:  lbls,
+		Samples: []prompb.Sample{{Value: v, Timestamp: ts}},
+	}
+}
@@ -0,0 +1,121 @@
+package alertseries
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestSeriesFor(t *testing.T) {
+	evaluatedAt := time.Unix(1000, 0)
+	startsAt := time.Unix(900, 0)
+	transition := func(current, previous eval.State) state.StateTransition {
+		return state.StateTransition{
+			State: &state.State{
+				State:    current,
+				StartsAt: startsAt,
+				Labels:   data.Labels{"alertname": "HighCPU", "__alert_rule_uid__": "uid", "team": "a", "invalid name": "x"},
+			},
+			PreviousState: previous,
+		}
+	}
+	labels := func(metric, alertstate string) []prompb.Label {
+		result := []prompb.Label{{Name: "__name__", Value: metric}, {Name: "alertname", Value: "HighCPU"}}
+		if alertstate != "" {
+			result = append(result, prompb.Label{Name: "alertstate", Value: alertstate})
+		}
+		return append(result, prompb.Label{Name: "team", Value: "a"})
+	}
+
+	t.Run("normal alerts have no series", func(t *testing.T) {
+		require.Empty(t, seriesFor(evaluatedAt, transition(eval.Normal, eval.Normal)))
+	})
+
+	t.Run("firing alerts have ALERTS and ALERTS_FOR_STATE series", func(t *testing.T) {
+		series := seriesFor(evaluatedAt, transition(eval.Alerting, eval.Alerting))
+		require.Equal(t, []prompb.TimeSeries{
+			{Labels: labels("ALERTS", "firing"), Samples: []prompb.Sample{{Value: 1, Timestamp: 1000_000}}},
+			{Labels: labels("ALERTS_FOR_STATE", ""), Samples: []prompb.Sample{{Value: 900, Timestamp: 1000_000}}},
+		}, series)
+	})
+
+	t.Run("series of the previous state are ended", func(t *testing.T) {
+		series := seriesFor(evaluatedAt, transition(eval.Alerting, eval.Pending))
+		require.Len(t, series, 3)
+		require.Equal(t, labels("ALERTS", "pending"), series[2].Labels)
+		require.True(t, value.IsStaleNaN(series[2].Samples[0].Value))
+
+		series = seriesFor(evaluatedAt, transition(eval.Normal, eval.Alerting))
+		require.Len(t, series, 2)
+		require.Equal(t, labels("ALERTS", "firing"), series[0].Labels)
+		require.True(t, value.IsStaleNaN(series[0].Samples[0].Value))
+		require.Equal(t, labels("ALERTS_FOR_STATE", ""), series[1].Labels)
+		require.True(t, value.IsStaleNaN(series[1].Samples[0].Value))
+	})
+}
+
+func TestWriter(t *testing.T) {
+	var received []prompb.TimeSeries
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		require.Equal(t, "tenant", r.Header.Get("X-Scope-OrgID"))
+		user, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "password", password)
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			return
+		}
+
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		raw, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		req := prompb.WriteRequest{}
+		require.NoError(t, proto.Unmarshal(raw, &req))
+		received = append(received, req.Timeseries...)
+	}))
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	w := NewWriter(Config{
+		WriteURL:          u,
+		TenantID:          "tenant",
+		BasicAuthUser:     "user",
+		BasicAuthPassword: "password",
+		Interval:          time.Minute,
+		Timeout:           time.Second,
+	}, &http.Client{}, log.NewNopLogger())
+
+	w.Write(context.Background(), time.Now(), []state.StateTransition{
+		{State: &state.State{State: eval.Alerting, Labels: data.Labels{"alertname": "a"}}, PreviousState: eval.Alerting},
+		{State: &state.State{State: eval.Normal, Labels: data.Labels{"alertname": "b"}}, PreviousState: eval.Normal},
+	})
+
+	// series are kept if they could not be written
+	require.Error(t, w.flush(context.Background()))
+	require.Len(t, w.buffer, 2)
+
+	status = http.StatusOK
+	require.NoError(t, w.flush(context.Background()))
+	require.Empty(t, w.buffer)
+	require.Len(t, received, 2)
+}
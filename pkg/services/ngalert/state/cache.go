@@ -3,8 +3,10 @@ package state
 import (
 	"context"
 	"errors"
+	"maps"
 	"math"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +23,8 @@ import (
 
 type ruleStates struct {
 	states map[string]*State
+	// fingerprintLabels are the fingerprint labels of the rule the states are keyed by.
+	fingerprintLabels []string
 }
 
 type cache struct {
@@ -77,7 +81,14 @@ func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngMo
 	// Instead of just calculating ID we create an entire state - a candidate. If rule states already hold a state with this ID, this candidate will be discarded and the existing one will be returned.
 	// Otherwise, this candidate will be added to the rule states and returned.
 	stateCandidate := calculateState(ctx, log, alertRule, result, extraLabels, externalURL)
+	state, _ := c.getOrAdd(stateCandidate)
+	return state
+}
 
+// getOrAdd returns the state of the cache that has the same ID as the candidate, or adds the candidate to the cache.
+// If the cached state has different labels than the candidate, the candidate replaces it and the replaced state is
+// returned as well, see ruleStates.getOrAdd.
+func (c *cache) getOrAdd(stateCandidate State) (*State, *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
 
@@ -95,12 +106,92 @@ func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngMo
 	return states.getOrAdd(stateCandidate)
 }
 
-func (rs *ruleStates) getOrAdd(stateCandidate State) *State {
+// migrateFingerprintLabels re-keys the states of the rule if they were created with fingerprint labels
+// other than the current ones of the rule. States that collide under the new fingerprint labels are merged,
+// the worst of them is kept. It returns the states that were dropped by the merge.
+func (c *cache) migrateFingerprintLabels(log log.Logger, alertRule *ngModels.AlertRule) []*State {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	orgStates, ok := c.states[alertRule.OrgID]
+	if !ok {
+		orgStates = make(map[string]*ruleStates)
+		c.states[alertRule.OrgID] = orgStates
+	}
+	rs, ok := orgStates[alertRule.UID]
+	if !ok {
+		orgStates[alertRule.UID] = &ruleStates{
+			states:            make(map[string]*State),
+			fingerprintLabels: alertRule.FingerprintLabels,
+		}
+		return nil
+	}
+	if slices.Equal(rs.fingerprintLabels, alertRule.FingerprintLabels) {
+		return nil
+	}
+	rs.fingerprintLabels = alertRule.FingerprintLabels
+	migrated := make(map[string]*State, len(rs.states))
+	var dropped []*State
+	for _, s := range rs.states {
+		id, err := ngModels.InstanceLabels(s.Labels).FingerprintKey(alertRule.FingerprintLabels)
+		if err != nil {
+			log.Error("Error getting cacheId for entry", "error", err)
+			continue
+		}
+		s.CacheID = id
+		existing, ok := migrated[id]
+		if !ok {
+			migrated[id] = s
+			continue
+		}
+		if preferState(s, existing) {
+			migrated[id] = s
+			dropped = append(dropped, existing)
+		} else {
+			dropped = append(dropped, s)
+		}
+	}
+	rs.states = migrated
+	if len(dropped) > 0 {
+		log.Info("Merged alert instances that collide on the new fingerprint labels of the rule", "count", len(dropped))
+	}
+	return dropped
+}
+
+// preferState reports whether the state a should be kept over the state b when both share the same ID.
+// The worse state wins, then the most recently evaluated one. Remaining ties are broken by labels
+// so that the outcome does not depend on the order of the states.
+func preferState(a, b *State) bool {
+	if sa, sb := stateSeverity(a.State), stateSeverity(b.State); sa != sb {
+		return sa > sb
+	}
+	if !a.LastEvaluationTime.Equal(b.LastEvaluationTime) {
+		return a.LastEvaluationTime.After(b.LastEvaluationTime)
+	}
+	return a.Labels.String() < b.Labels.String()
+}
+
+// stateSeverity orders the evaluation states from the least to the most severe.
+func stateSeverity(s eval.State) int {
+	switch s {
+	case eval.Error:
+		return 4
+	case eval.Alerting:
+		return 3
+	case eval.NoData:
+		return 2
+	case eval.Pending:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (rs *ruleStates) getOrAdd(stateCandidate State) (*State, *State) {
 	state, ok := rs.states[stateCandidate.CacheID]
 	// Check if the state with this ID already exists.
 	if !ok {
 		rs.states[stateCandidate.CacheID] = &stateCandidate
-		return &stateCandidate
+		return &stateCandidate, nil
 	}
 
 	// Annotations can change over time, however we also want to maintain
@@ -114,10 +205,28 @@ func (rs *ruleStates) getOrAdd(stateCandidate State) *State {
 			}
 		}
 	}
+	// The labels that describe a query error are kept while the state stays in Error, see resultError.
+	if state.State == eval.Error {
+		for _, k := range []string{"ref_id", "datasource_uid"} {
+			if v, ok := state.Labels[k]; ok {
+				if _, ok := stateCandidate.Labels[k]; !ok {
+					stateCandidate.Labels[k] = v
+				}
+			}
+		}
+	}
+	// Labels outside the fingerprint labels of the rule do not identify the state in the cache, but the alert instance
+	// in the database and the alert in the Alertmanager are identified by all labels. Therefore, a change of the labels
+	// starts a new state, and the replaced one is returned so that it can be resolved and deleted.
+	if !maps.Equal(state.Labels, stateCandidate.Labels) {
+		rs.states[stateCandidate.CacheID] = &stateCandidate
+		return &stateCandidate, state
+	}
 	state.Annotations = stateCandidate.Annotations
 	state.Values = stateCandidate.Values
+	state.ResultFingerprint = stateCandidate.ResultFingerprint
 	rs.states[stateCandidate.CacheID] = state
-	return state
+	return state, nil
 }
 
 func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) State {
@@ -127,7 +236,7 @@ func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.Ale
 
 	// For now, do nothing with these errors as they are already logged in expand.
 	// In the future, we want to show these errors to the user somehow.
-	labels, _ := expand(ctx, log, alertRule.Title, alertRule.Labels, templateData, externalURL, result.EvaluatedAt)
+	labels, _ := expand(ctx, log, alertRule.Title, alertRule.EffectiveLabels(), templateData, externalURL, result.EvaluatedAt)
 	annotations, _ := expand(ctx, log, alertRule.Title, alertRule.Annotations, templateData, externalURL, result.EvaluatedAt)
 
 	values := make(map[string]float64)
@@ -172,8 +281,9 @@ func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.Ale
 		log.Warn("Evaluation result contains either reserved labels or labels declared in the rules. Those labels from the result will be ignored", "labels", dupes)
 	}
 
+	// Instances are identified only by the fingerprint labels of the rule, if it declares any.
 	il := ngModels.InstanceLabels(lbs)
-	id, err := il.StringKey()
+	id, err := il.FingerprintKey(alertRule.FingerprintLabels)
 	if err != nil {
 		log.Error("Error getting cacheId for entry", "error", err)
 	}
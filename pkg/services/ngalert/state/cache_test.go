@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"net/url"
 	"testing"
 	"time"
@@ -263,6 +264,89 @@ func Test_getOrCreate(t *testing.T) {
 		state := c.getOrCreate(context.Background(), l, rule, result, nil, url)
 		assert.Equal(t, map[string]float64{"B0": 1, "B1": 2}, state.Values)
 	})
+
+	t.Run("instances that differ only by labels other than the fingerprint labels share the state ID", func(t *testing.T) {
+		rule := generateRule()
+		rule.FingerprintLabels = []string{"namespace"}
+
+		first := c.getOrCreate(context.Background(), l, rule, eval.Result{Instance: data.Labels{"namespace": "a", "pod": "a-1"}}, nil, url)
+		same := c.getOrCreate(context.Background(), l, rule, eval.Result{Instance: data.Labels{"namespace": "a", "pod": "a-1"}}, nil, url)
+		other := c.getOrCreate(context.Background(), l, rule, eval.Result{Instance: data.Labels{"namespace": "b", "pod": "a-2"}}, nil, url)
+
+		assert.Same(t, first, same)
+		assert.NotEqual(t, first.CacheID, other.CacheID)
+
+		// the labels identify the alert instance, so the state with new labels replaces the previous one
+		candidate := calculateState(context.Background(), l, rule, eval.Result{Instance: data.Labels{"namespace": "a", "pod": "a-2"}}, nil, url)
+		second, replaced := c.getOrAdd(candidate)
+		assert.NotSame(t, first, second)
+		assert.Same(t, first, replaced)
+		assert.Equal(t, first.CacheID, second.CacheID)
+		assert.Equal(t, "a-2", second.Labels["pod"])
+		assert.Equal(t, "a-1", replaced.Labels["pod"])
+		assert.Same(t, second, c.get(rule.OrgID, rule.UID, second.CacheID))
+	})
+}
+
+func Test_migrateFingerprintLabels(t *testing.T) {
+	l := log.NewNopLogger()
+	url := &url.URL{Scheme: "http", Host: "localhost:3000", Path: "/test"}
+	rule := models.AlertRuleGen()()
+	rule.Labels = nil
+	rule.Annotations = nil
+	rule.FingerprintLabels = nil
+
+	c := newCache()
+	now := time.Now()
+	a1 := c.getOrCreate(context.Background(), l, rule, eval.Result{Instance: data.Labels{"namespace": "a", "pod": "a-1"}}, nil, url)
+	a1.State = eval.Normal
+	a1.LastEvaluationTime = now
+	a2 := c.getOrCreate(context.Background(), l, rule, eval.Result{Instance: data.Labels{"namespace": "a", "pod": "a-2"}}, nil, url)
+	a2.State = eval.Alerting
+	a2.LastEvaluationTime = now.Add(-time.Minute)
+	b1 := c.getOrCreate(context.Background(), l, rule, eval.Result{Instance: data.Labels{"namespace": "b", "pod": "b-1"}}, nil, url)
+	require.Len(t, c.getStatesForRuleUID(rule.OrgID, rule.UID, false), 3)
+
+	rule.FingerprintLabels = []string{"namespace"}
+	merged := c.migrateFingerprintLabels(l, rule)
+	require.Equal(t, []*State{a1}, merged)
+
+	states := c.getStatesForRuleUID(rule.OrgID, rule.UID, false)
+	require.Len(t, states, 2)
+	require.Same(t, a2, c.get(rule.OrgID, rule.UID, a2.CacheID))
+	require.Same(t, b1, c.get(rule.OrgID, rule.UID, b1.CacheID))
+
+	t.Run("does nothing if the fingerprint labels did not change", func(t *testing.T) {
+		require.Empty(t, c.migrateFingerprintLabels(l, rule))
+		require.Len(t, c.getStatesForRuleUID(rule.OrgID, rule.UID, false), 2)
+	})
+}
+
+func Test_mergeCandidates(t *testing.T) {
+	l := log.NewNopLogger()
+	rule := models.AlertRuleGen()()
+	rule.Labels = nil
+	rule.Annotations = nil
+	rule.FingerprintLabels = []string{"namespace"}
+
+	results := eval.Results{
+		{Instance: data.Labels{"namespace": "a", "pod": "a-2"}, State: eval.Normal},
+		{Instance: data.Labels{"namespace": "a", "pod": "a-3"}, State: eval.Alerting},
+		{Instance: data.Labels{"namespace": "a", "pod": "a-1"}, State: eval.Alerting},
+		{Instance: data.Labels{"namespace": "b", "pod": "b-1"}, State: eval.Normal},
+	}
+	for i := 0; i < 5; i++ {
+		rand.Shuffle(len(results), func(i, j int) { results[i], results[j] = results[j], results[i] })
+		candidates := mergeCandidates(context.Background(), l, rule, results, nil, nil)
+		require.Len(t, candidates, 2)
+		byNamespace := make(map[string]resultCandidate, len(candidates))
+		for _, c := range candidates {
+			byNamespace[c.state.Labels["namespace"]] = c
+		}
+		require.Equal(t, eval.Alerting, byNamespace["a"].result.State)
+		require.Equal(t, "a-1", byNamespace["a"].state.Labels["pod"])
+		require.Equal(t, "b-1", byNamespace["b"].state.Labels["pod"])
+	}
 }
 
 func Test_mergeLabels(t *testing.T) {
@@ -53,6 +53,10 @@ func StateToPostableAlert(transition StateTransition, appURL *url.URL) *models.P
 		nA[alertingModels.ImageTokenAnnotation] = alertState.Image.Token
 	}
 
+	if alertState.QueryPreview != nil && alertState.QueryPreview.Token != "" {
+		nA[ngModels.QueryPreviewTokenAnnotation] = alertState.QueryPreview.Token
+	}
+
 	if alertState.StateReason != "" {
 		nA[alertingModels.StateReasonAnnotation] = alertState.StateReason
 	}
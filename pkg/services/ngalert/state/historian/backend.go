@@ -18,6 +18,7 @@ const (
 	BackendTypeLoki        BackendType = "loki"
 	BackendTypeMultiple    BackendType = "multiple"
 	BackendTypeNoop        BackendType = "noop"
+	BackendTypeWebhook     BackendType = "webhook"
 )
 
 func ParseBackendType(s string) (BackendType, error) {
@@ -28,6 +29,7 @@ func ParseBackendType(s string) (BackendType, error) {
 		BackendTypeLoki:        {},
 		BackendTypeMultiple:    {},
 		BackendTypeNoop:        {},
+		BackendTypeWebhook:     {},
 	}
 	p := BackendType(norm)
 	if _, ok := types[p]; !ok {
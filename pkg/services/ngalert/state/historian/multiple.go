@@ -2,6 +2,7 @@ package historian
 
 import (
 	"context"
+	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -14,6 +15,12 @@ type Backend interface {
 	Query(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error)
 }
 
+// Runner is implemented by the backends that write in the background. Run returns once the context is done
+// and the pending writes are done.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
 // MultipleBackend is a state.Historian that records history to multiple backends at once.
 // Only one backend is used for reads. The backend selected for read traffic is called the primary and all others are called secondaries.
 type MultipleBackend struct {
@@ -53,6 +60,25 @@ func (h *MultipleBackend) Query(ctx context.Context, query ngmodels.HistoryQuery
 	return h.primary.Query(ctx, query)
 }
 
+// Run runs the backends that write in the background until the context is done.
+func (h *MultipleBackend) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(h.secondaries)+1)
+	for i, b := range append([]Backend{h.primary}, h.secondaries...) {
+		runner, ok := b.(Runner)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = runner.Run(ctx)
+		}(i)
+	}
+	wg.Wait()
+	return Join(errs...)
+}
+
 // TODO: This is vendored verbatim from the Go standard library.
 // TODO: The grafana project doesn't support go 1.20 yet, so we can't use errors.Join() directly.
 // TODO: Remove this and replace calls with "errors.Join(...)" when go 1.20 becomes the minimum supported version.
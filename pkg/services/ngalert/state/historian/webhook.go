@@ -0,0 +1,347 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/client"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// WebhookSignatureHeader is the header with the HMAC-SHA256 signature of the body of the requests to the webhook,
+	// in the form sha256=<hex encoded signature>. It is only set if a secret is configured.
+	WebhookSignatureHeader = "X-Grafana-Alerting-Signature"
+
+	defaultWebhookBatchSize     = 100
+	defaultWebhookFlushInterval = 5 * time.Second
+	defaultWebhookMaxRetries    = 3
+	defaultWebhookQueueSize     = 10
+	webhookRetryBackoff         = time.Second
+)
+
+var (
+	errWebhookQueryUnsupported = errors.New("the webhook state history backend does not support queries")
+	errWebhookQueueFull        = errors.New("the queue of the webhook state history backend is full")
+	errWebhookStopped          = errors.New("the webhook state history backend is stopped")
+)
+
+type WebhookConfig struct {
+	URL            *url.URL
+	Secret         string
+	BatchSize      int
+	FlushInterval  time.Duration
+	MaxRetries     int
+	QueueSize      int
+	ExternalLabels map[string]string
+}
+
+func NewWebhookConfig(cfg setting.UnifiedAlertingStateHistorySettings) (WebhookConfig, error) {
+	if cfg.WebhookURL == "" {
+		return WebhookConfig{}, fmt.Errorf("webhook URL must be provided")
+	}
+	u, err := url.Parse(cfg.WebhookURL)
+	if err != nil {
+		return WebhookConfig{}, fmt.Errorf("failed to parse webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return WebhookConfig{}, fmt.Errorf("webhook URL must be an http or https URL")
+	}
+	result := WebhookConfig{
+		URL:            u,
+		Secret:         cfg.WebhookSecret,
+		BatchSize:      cfg.WebhookBatchSize,
+		FlushInterval:  cfg.WebhookFlushInterval,
+		MaxRetries:     cfg.WebhookMaxRetries,
+		QueueSize:      cfg.WebhookQueueSize,
+		ExternalLabels: cfg.ExternalLabels,
+	}
+	if result.BatchSize <= 0 {
+		result.BatchSize = defaultWebhookBatchSize
+	}
+	if result.FlushInterval <= 0 {
+		result.FlushInterval = defaultWebhookFlushInterval
+	}
+	if result.MaxRetries < 0 {
+		result.MaxRetries = defaultWebhookMaxRetries
+	}
+	if result.QueueSize <= 0 {
+		result.QueueSize = defaultWebhookQueueSize
+	}
+	return result, nil
+}
+
+// WebhookTransition is a state transition as it is sent to the webhook.
+// It has the fields of the log lines written to Loki, and the fields that Loki keeps as labels of the stream.
+type WebhookTransition struct {
+	LokiEntry
+	OrgID     int64     `json:"orgID"`
+	Group     string    `json:"group"`
+	FolderUID string    `json:"folderUID"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookPayload is the body of the requests to the webhook.
+type WebhookPayload struct {
+	ExternalLabels map[string]string   `json:"externalLabels,omitempty"`
+	Transitions    []WebhookTransition `json:"transitions"`
+}
+
+type webhookBatch struct {
+	transitions []WebhookTransition
+	// waiting are the channels of the calls to Record whose transitions are in the batch.
+	waiting []chan error
+	timer   *clock.Timer
+}
+
+// WebhookBackend is a state.Historian that sends state transitions to a webhook, so that external systems
+// can follow the lifecycle of alerts. Transitions are sent in batches, which are sent when they are full
+// or when the flush interval passed, and failed requests are retried. It does not support queries.
+// Batches are sent one at a time, in the order they were created, by Run.
+type WebhookBackend struct {
+	cfg          WebhookConfig
+	client       client.Requester
+	clock        clock.Clock
+	metrics      *metrics.Historian
+	log          log.Logger
+	retryBackoff time.Duration
+
+	queue chan *webhookBatch
+
+	mtx     sync.Mutex
+	pending *webhookBatch
+	stopped bool
+}
+
+func NewWebhookBackend(cfg WebhookConfig, req client.Requester, metrics *metrics.Historian) *WebhookBackend {
+	return &WebhookBackend{
+		cfg:          cfg,
+		client:       client.NewTimedClient(req, metrics.WriteDuration),
+		clock:        clock.New(),
+		metrics:      metrics,
+		log:          log.New("ngalert.state.historian", "backend", "webhook"),
+		retryBackoff: webhookRetryBackoff,
+		queue:        make(chan *webhookBatch, cfg.QueueSize),
+	}
+}
+
+// Run sends the queued batches until the context is done. Then, the pending batch and the queued ones are sent
+// before it returns, and the transitions that are recorded afterwards are dropped.
+func (h *WebhookBackend) Run(ctx context.Context) error {
+	for {
+		select {
+		case batch := <-h.queue:
+			h.send(batch)
+		case <-ctx.Done():
+			h.mtx.Lock()
+			h.stopped = true
+			pending := h.pending
+			h.pending = nil
+			h.mtx.Unlock()
+			// nothing is queued once the backend is stopped
+			for len(h.queue) > 0 {
+				h.send(<-h.queue)
+			}
+			if pending != nil {
+				pending.timer.Stop()
+				h.send(pending)
+			}
+			return nil
+		}
+	}
+}
+
+// Record adds the state transitions to the next batch sent to the webhook.
+// The returned channel receives an error if the batch could not be sent, and is closed once it is sent.
+func (h *WebhookBackend) Record(_ context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	errCh := make(chan error, 1)
+	transitions := make([]WebhookTransition, 0, len(states))
+	for _, s := range states {
+		if !shouldRecord(s) {
+			continue
+		}
+		transitions = append(transitions, WebhookTransition{
+			LokiEntry: newLokiEntry(rule, s),
+			OrgID:     rule.OrgID,
+			Group:     rule.Group,
+			FolderUID: rule.NamespaceUID,
+			Timestamp: s.State.LastEvaluationTime,
+		})
+	}
+	if len(transitions) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.stopped {
+		errCh <- errWebhookStopped
+		close(errCh)
+		return errCh
+	}
+	if h.pending == nil {
+		batch := &webhookBatch{}
+		batch.timer = h.clock.AfterFunc(h.cfg.FlushInterval, func() { h.flush(batch) })
+		h.pending = batch
+	}
+	h.pending.transitions = append(h.pending.transitions, transitions...)
+	h.pending.waiting = append(h.pending.waiting, errCh)
+	if len(h.pending.transitions) >= h.cfg.BatchSize {
+		batch := h.pending
+		h.pending = nil
+		batch.timer.Stop()
+		h.enqueue(batch)
+	}
+	return errCh
+}
+
+// Query is not supported, as the webhook only receives state transitions.
+func (h *WebhookBackend) Query(context.Context, models.HistoryQuery) (*data.Frame, error) {
+	return nil, errWebhookQueryUnsupported
+}
+
+// flush sends the batch when the flush interval passed, unless it was sent already because it was full.
+func (h *WebhookBackend) flush(batch *webhookBatch) {
+	h.mtx.Lock()
+	if h.pending != batch {
+		h.mtx.Unlock()
+		return
+	}
+	h.pending = nil
+	h.enqueue(batch)
+	h.mtx.Unlock()
+}
+
+// enqueue queues the batch to be sent by Run, or drops it if the queue is full. It must be called with the mutex held,
+// so that batches are queued in the order they were created.
+func (h *WebhookBackend) enqueue(batch *webhookBatch) {
+	select {
+	case h.queue <- batch:
+	default:
+		h.log.Warn("Dropping alert state history batch because the queue is full", "transitions", len(batch.transitions), "queueSize", cap(h.queue))
+		h.fail(batch, countByOrg(batch.transitions), errWebhookQueueFull)
+	}
+}
+
+// countByOrg returns the number of transitions of every organization.
+func countByOrg(transitions []WebhookTransition) map[string]int {
+	orgs := make(map[string]int)
+	for _, t := range transitions {
+		orgs[fmt.Sprint(t.OrgID)]++
+	}
+	return orgs
+}
+
+// fail counts the batch as failed, and reports the error to the calls to Record whose transitions are in the batch.
+func (h *WebhookBackend) fail(batch *webhookBatch, orgs map[string]int, err error) {
+	for org, count := range orgs {
+		h.metrics.WritesFailed.WithLabelValues(org, BackendTypeWebhook.String()).Inc()
+		h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(count))
+	}
+	for _, ch := range batch.waiting {
+		ch <- err
+		close(ch)
+	}
+}
+
+func (h *WebhookBackend) send(batch *webhookBatch) {
+	// As with Loki, the batch is sent with a new context, so that shutdowns do not interrupt it.
+	ctx, cancel := context.WithTimeout(context.Background(), StateHistoryWriteTimeout)
+	defer cancel()
+
+	orgs := countByOrg(batch.transitions)
+	for org, count := range orgs {
+		h.metrics.WritesTotal.WithLabelValues(org, BackendTypeWebhook.String()).Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(count))
+	}
+
+	err := h.post(ctx, WebhookPayload{ExternalLabels: h.cfg.ExternalLabels, Transitions: batch.transitions})
+	if err != nil {
+		h.log.Error("Failed to send alert state history batch", "transitions", len(batch.transitions), "error", err)
+		h.fail(batch, orgs, fmt.Errorf("failed to send alert state history batch: %w", err))
+		return
+	}
+	for _, ch := range batch.waiting {
+		close(ch)
+	}
+}
+
+// post sends the payload to the webhook, and retries with an exponential backoff if the request fails
+// because of a network error or a response with status 429 or 5xx.
+func (h *WebhookBackend) post(ctx context.Context, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode state transitions: %w", err)
+	}
+	backoff := h.retryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := h.doPost(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= h.cfg.MaxRetries {
+			return err
+		}
+		h.log.Debug("Retrying to send alert state history batch", "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-h.clock.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// doPost sends the body to the webhook, and returns whether the request can be retried if it failed.
+func (h *WebhookBackend) doPost(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.cfg.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.cfg.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(h.cfg.Secret, body))
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("error sending request: %w", err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			h.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	err = fmt.Errorf("received a non-200 response from the webhook, status: %d, body: %s", res.StatusCode, msg)
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500, err
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 signature of the body with the secret.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,198 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type webhookRequest struct {
+	signature string
+	body      []byte
+}
+
+// newTestWebhook starts a webhook that responds with the given statuses, and then with 200.
+func newTestWebhook(t *testing.T, statuses ...int) (*httptest.Server, func() []webhookRequest) {
+	t.Helper()
+	var mtx sync.Mutex
+	var requests []webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mtx.Lock()
+		defer mtx.Unlock()
+		requests = append(requests, webhookRequest{signature: r.Header.Get(WebhookSignatureHeader), body: body})
+		if len(requests) <= len(statuses) {
+			w.WriteHeader(statuses[len(requests)-1])
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []webhookRequest {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]webhookRequest(nil), requests...)
+	}
+}
+
+func createTestWebhookBackend(t *testing.T, server *httptest.Server, batchSize int) *WebhookBackend {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+	backend := NewWebhookBackend(WebhookConfig{
+		URL:            u,
+		Secret:         "secret",
+		BatchSize:      batchSize,
+		FlushInterval:  time.Minute,
+		MaxRetries:     3,
+		QueueSize:      1,
+		ExternalLabels: map[string]string{"cluster": "eu"},
+	}, server.Client(), met)
+	backend.retryBackoff = time.Millisecond
+	return backend
+}
+
+// runTestWebhookBackend runs the backend until the test ends.
+func runTestWebhookBackend(t *testing.T, backend *WebhookBackend) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = backend.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestWebhookBackend(t *testing.T) {
+	rule := createTestRule()
+	transitions := []state.StateTransition{
+		{PreviousState: eval.Normal, State: &state.State{State: eval.Alerting, Labels: data.Labels{"a": "b"}}},
+		{PreviousState: eval.Normal, State: &state.State{State: eval.Pending, Labels: data.Labels{"a": "c"}}},
+	}
+
+	t.Run("should send a signed batch when it is full", func(t *testing.T) {
+		server, requests := newTestWebhook(t)
+		backend := createTestWebhookBackend(t, server, 2)
+		runTestWebhookBackend(t, backend)
+
+		require.NoError(t, <-backend.Record(context.Background(), rule, transitions))
+
+		sent := requests()
+		require.Len(t, sent, 1)
+		require.Equal(t, "sha256="+SignWebhookPayload("secret", sent[0].body), sent[0].signature)
+		var payload WebhookPayload
+		require.NoError(t, json.Unmarshal(sent[0].body, &payload))
+		require.Equal(t, map[string]string{"cluster": "eu"}, payload.ExternalLabels)
+		require.Len(t, payload.Transitions, 2)
+		require.Equal(t, rule.UID, payload.Transitions[0].RuleUID)
+		require.Equal(t, rule.OrgID, payload.Transitions[0].OrgID)
+		require.Equal(t, rule.Group, payload.Transitions[0].Group)
+		require.Equal(t, rule.NamespaceUID, payload.Transitions[0].FolderUID)
+		require.Equal(t, "Alerting", payload.Transitions[0].Current)
+		require.Equal(t, map[string]string{"a": "c"}, payload.Transitions[1].InstanceLabels)
+	})
+
+	t.Run("should send a batch after the flush interval", func(t *testing.T) {
+		server, requests := newTestWebhook(t)
+		backend := createTestWebhookBackend(t, server, 100)
+		clk := clock.NewMock()
+		backend.clock = clk
+		runTestWebhookBackend(t, backend)
+
+		first := backend.Record(context.Background(), rule, transitions[:1])
+		second := backend.Record(context.Background(), rule, transitions[1:])
+		require.Empty(t, requests())
+
+		clk.Add(time.Minute)
+		require.NoError(t, <-first)
+		require.NoError(t, <-second)
+		sent := requests()
+		require.Len(t, sent, 1)
+		var payload WebhookPayload
+		require.NoError(t, json.Unmarshal(sent[0].body, &payload))
+		require.Len(t, payload.Transitions, 2)
+	})
+
+	t.Run("should retry server errors", func(t *testing.T) {
+		server, requests := newTestWebhook(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+		backend := createTestWebhookBackend(t, server, 1)
+		runTestWebhookBackend(t, backend)
+
+		require.NoError(t, <-backend.Record(context.Background(), rule, transitions[:1]))
+		require.Len(t, requests(), 3)
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		server, requests := newTestWebhook(t, http.StatusBadRequest)
+		backend := createTestWebhookBackend(t, server, 1)
+		runTestWebhookBackend(t, backend)
+
+		require.ErrorContains(t, <-backend.Record(context.Background(), rule, transitions[:1]), "status: 400")
+		require.Len(t, requests(), 1)
+	})
+
+	t.Run("should give up after the maximum number of retries", func(t *testing.T) {
+		server, requests := newTestWebhook(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		backend := createTestWebhookBackend(t, server, 1)
+		runTestWebhookBackend(t, backend)
+
+		require.Error(t, <-backend.Record(context.Background(), rule, transitions[:1]))
+		require.Len(t, requests(), 4)
+	})
+
+	t.Run("should drop batches when the queue is full", func(t *testing.T) {
+		server, requests := newTestWebhook(t)
+		backend := createTestWebhookBackend(t, server, 1)
+
+		queued := backend.Record(context.Background(), rule, transitions[:1])
+		require.ErrorIs(t, <-backend.Record(context.Background(), rule, transitions[1:]), errWebhookQueueFull)
+
+		runTestWebhookBackend(t, backend)
+		require.NoError(t, <-queued)
+		require.Len(t, requests(), 1)
+	})
+
+	t.Run("should send the pending and queued batches when it is stopped", func(t *testing.T) {
+		server, requests := newTestWebhook(t)
+		backend := createTestWebhookBackend(t, server, 2)
+		queued := backend.Record(context.Background(), rule, transitions)
+		pending := backend.Record(context.Background(), rule, transitions[:1])
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NoError(t, backend.Run(ctx))
+		require.NoError(t, <-queued)
+		require.NoError(t, <-pending)
+		require.Len(t, requests(), 2)
+
+		require.ErrorIs(t, <-backend.Record(context.Background(), rule, transitions[:1]), errWebhookStopped)
+	})
+
+	t.Run("should not send transitions that are not recorded", func(t *testing.T) {
+		server, requests := newTestWebhook(t)
+		backend := createTestWebhookBackend(t, server, 1)
+		runTestWebhookBackend(t, backend)
+
+		require.NoError(t, <-backend.Record(context.Background(), rule, singleFromNormal(&state.State{State: eval.Normal})))
+		require.Empty(t, requests())
+	})
+}
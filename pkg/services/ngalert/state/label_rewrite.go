@@ -0,0 +1,137 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// LabelRewriters provides the label rewrite rules of organizations.
+type LabelRewriters interface {
+	// LabelRewriter returns the rewriter of the organization, or nil if it has no rules.
+	LabelRewriter(orgID int64) *ngModels.LabelRewriter
+}
+
+// AdminConfigReader provides the admin configurations of all organizations.
+type AdminConfigReader interface {
+	GetAdminConfigurations() ([]*ngModels.AdminConfiguration, error)
+}
+
+// AdminConfigLabelRewriters provides the label rewrite rules of the admin configurations of organizations.
+// The compiled rules are cached and fetched again once they are older than the poll interval, or after they are invalidated.
+type AdminConfigLabelRewriters struct {
+	store        AdminConfigReader
+	pollInterval time.Duration
+	clock        clock.Clock
+	log          log.Logger
+
+	mtx       sync.Mutex
+	rewriters map[int64]*ngModels.LabelRewriter
+	fetchedAt time.Time
+	// invalidated is true if the rules changed since they were fetched.
+	invalidated bool
+	// fetching is true while the rules are fetched, so that the other evaluations use the cached rules meanwhile.
+	fetching bool
+}
+
+func NewAdminConfigLabelRewriters(store AdminConfigReader, pollInterval time.Duration, clk clock.Clock, logger log.Logger) *AdminConfigLabelRewriters {
+	return &AdminConfigLabelRewriters{
+		store:        store,
+		pollInterval: pollInterval,
+		clock:        clk,
+		log:          logger,
+	}
+}
+
+// LabelRewriter returns the rewriter of the organization. The admin configurations are fetched without holding the
+// lock, and only by one caller at a time. If they cannot be fetched, the previously fetched rules are used, and the
+// fetch is retried after the poll interval.
+func (r *AdminConfigLabelRewriters) LabelRewriter(orgID int64) *ngModels.LabelRewriter {
+	r.mtx.Lock()
+	now := r.clock.Now()
+	fresh := !r.fetchedAt.IsZero() && !r.invalidated && now.Sub(r.fetchedAt) < r.pollInterval
+	if fresh || r.fetching {
+		defer r.mtx.Unlock()
+		return r.rewriters[orgID]
+	}
+	r.fetching = true
+	r.invalidated = false
+	r.mtx.Unlock()
+
+	rewriters, err := r.fetch()
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.fetching = false
+	r.fetchedAt = now
+	if err != nil {
+		r.log.Error("Failed to fetch admin configurations, the previous label rewrite rules are used", "error", err)
+		return r.rewriters[orgID]
+	}
+	r.rewriters = rewriters
+	return rewriters[orgID]
+}
+
+func (r *AdminConfigLabelRewriters) fetch() (map[int64]*ngModels.LabelRewriter, error) {
+	cfgs, err := r.store.GetAdminConfigurations()
+	if err != nil {
+		return nil, err
+	}
+	rewriters := make(map[int64]*ngModels.LabelRewriter, len(cfgs))
+	for _, cfg := range cfgs {
+		if len(cfg.LabelRewriteRules) == 0 {
+			continue
+		}
+		rewriter, err := cfg.LabelRewriteRules.Compile()
+		if err != nil {
+			r.log.Error("Invalid label rewrite rules, labels of the organization are not rewritten", "org", cfg.OrgID, "error", err)
+			continue
+		}
+		rewriters[cfg.OrgID] = rewriter
+	}
+	return rewriters, nil
+}
+
+// AdminConfigurationChanged drops the cached rules, so that the next evaluation uses the current rules of the organization.
+func (r *AdminConfigLabelRewriters) AdminConfigurationChanged(orgID int64) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.invalidated = true
+	r.log.Debug("Admin configuration changed, label rewrite rules will be refreshed", "org", orgID)
+}
+
+// rewriteLabels applies the label rewrite rules of the organization to the labels the data sources returned.
+// The results are copied if any rule applies, as they are shared with the caller. Results whose labels are the same
+// after the rewrite are merged into one state, see mergeCandidates, which is logged because it hides series.
+func (st *Manager) rewriteLabels(logger log.Logger, orgID int64, results eval.Results) eval.Results {
+	if st.labelRewriters == nil {
+		return results
+	}
+	rewriter := st.labelRewriters.LabelRewriter(orgID)
+	if rewriter.IsEmpty() {
+		return results
+	}
+	rewritten := make(eval.Results, len(results))
+	seen := make(map[data.Fingerprint]struct{}, len(results))
+	collisions := 0
+	for i, result := range results {
+		result.Instance = rewriter.Rewrite(result.Instance)
+		rewritten[i] = result
+		fp := result.Instance.Fingerprint()
+		if _, ok := seen[fp]; ok {
+			collisions++
+			continue
+		}
+		seen[fp] = struct{}{}
+	}
+	if collisions > 0 {
+		logger.Warn("Label rewrite rules of the organization made results share the same labels, they are merged", "collisions", collisions)
+	}
+	return rewritten
+}
@@ -0,0 +1,112 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type fakeAdminConfigReader struct {
+	configs []*models.AdminConfiguration
+	err     error
+	calls   int
+}
+
+func (f *fakeAdminConfigReader) GetAdminConfigurations() ([]*models.AdminConfiguration, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.configs, nil
+}
+
+func TestAdminConfigLabelRewriters(t *testing.T) {
+	reader := &fakeAdminConfigReader{configs: []*models.AdminConfiguration{
+		{OrgID: 1, LabelRewriteRules: models.LabelRewriteRules{{Label: "pod", Action: models.LabelRewriteActionDrop}}},
+		{OrgID: 2},
+		{OrgID: 3, LabelRewriteRules: models.LabelRewriteRules{{Label: "(", Action: models.LabelRewriteActionDrop}}},
+	}}
+	clk := clock.NewMock()
+	rewriters := state.NewAdminConfigLabelRewriters(reader, time.Minute, clk, log.NewNopLogger())
+
+	require.False(t, rewriters.LabelRewriter(1).IsEmpty())
+	require.True(t, rewriters.LabelRewriter(2).IsEmpty())
+	require.True(t, rewriters.LabelRewriter(3).IsEmpty(), "invalid rules must be ignored")
+	require.Equal(t, 1, reader.calls)
+
+	rewriters.AdminConfigurationChanged(1)
+	rewriters.LabelRewriter(1)
+	require.Equal(t, 2, reader.calls)
+
+	clk.Add(time.Minute)
+	rewriters.LabelRewriter(1)
+	require.Equal(t, 3, reader.calls)
+
+	// a failed fetch keeps the previous rules and is only retried after the poll interval
+	reader.err = errors.New("database is locked")
+	clk.Add(time.Minute)
+	require.False(t, rewriters.LabelRewriter(1).IsEmpty())
+	require.Equal(t, 4, reader.calls)
+	require.False(t, rewriters.LabelRewriter(1).IsEmpty())
+	require.Equal(t, 4, reader.calls)
+	clk.Add(time.Minute)
+	rewriters.LabelRewriter(1)
+	require.Equal(t, 5, reader.calls)
+}
+
+func TestProcessEvalResults_LabelRewrite(t *testing.T) {
+	rewriters := state.NewAdminConfigLabelRewriters(&fakeAdminConfigReader{configs: []*models.AdminConfiguration{
+		{OrgID: 1, LabelRewriteRules: models.LabelRewriteRules{
+			{Label: "pod", Action: models.LabelRewriteActionDrop},
+			{Label: "instance", Action: models.LabelRewriteActionReplace, Regex: "(.*):[0-9]+", Replacement: "$1"},
+		}},
+	}}, time.Minute, clock.NewMock(), log.NewNopLogger())
+	cfg := state.ManagerCfg{
+		Metrics:        metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		Images:         &state.NoopImageService{},
+		Clock:          clock.NewMock(),
+		Historian:      &state.FakeHistorian{},
+		LabelRewriters: rewriters,
+		Tracer:         tracing.InitializeTracerForTest(),
+		Log:            log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	evaluatedAt := time.Unix(60, 0)
+	results := eval.Results{
+		{Instance: data.Labels{"pod": "a-1", "instance": "host:9090"}, State: eval.Alerting, EvaluatedAt: evaluatedAt},
+		{Instance: data.Labels{"pod": "a-2", "instance": "host:9091"}, State: eval.Normal, EvaluatedAt: evaluatedAt},
+	}
+
+	t.Run("should rewrite the labels of the organization", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(1), models.WithLabels(data.Labels{}))()
+		transitions := st.ProcessEvalResults(context.Background(), evaluatedAt, rule, results, nil)
+		require.Len(t, transitions, 1, "results that only differ by dropped labels must be merged")
+		require.Equal(t, "host", transitions[0].Labels["instance"])
+		require.NotContains(t, transitions[0].Labels, "pod")
+		require.Equal(t, eval.Alerting, transitions[0].State.State)
+		require.Equal(t, "a-1", results[0].Instance["pod"], "the results must not be modified")
+	})
+
+	t.Run("should not rewrite the labels of other organizations", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(2), models.WithLabels(data.Labels{}))()
+		transitions := st.ProcessEvalResults(context.Background(), evaluatedAt, rule, results, nil)
+		require.Len(t, transitions, 2)
+		for _, tr := range transitions {
+			require.Contains(t, tr.Labels, "pod")
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -45,13 +46,24 @@ type Manager struct {
 
 	instanceStore InstanceStore
 	images        ImageCapturer
+	queryPreviews QueryPreviewCapturer
 	historian     Historian
+	alertSeries   AlertSeriesWriter
+	usage         UsageRecorder
 	externalURL   *url.URL
 
+	labelRewriters      LabelRewriters
+	staleSeriesPolicies StaleSeriesPolicies
+
 	doNotSaveNormalState           bool
 	applyNoDataAndErrorToAllStates bool
 
 	persister StatePersister
+
+	snapshotStore    SnapshotStore
+	snapshotInterval time.Duration
+	snapshotNode     string
+	snapshotMtx      sync.Mutex
 }
 
 type ManagerCfg struct {
@@ -61,6 +73,12 @@ type ManagerCfg struct {
 	Images        ImageCapturer
 	Clock         clock.Clock
 	Historian     Historian
+	// QueryPreviews is optional. If set, a preview of the queries of the rule is captured when an alert starts firing.
+	QueryPreviews QueryPreviewCapturer
+	// AlertSeries is optional. If set, the series describing the state of the alerts are written to it.
+	AlertSeries AlertSeriesWriter
+	// Usage is optional. If set, the evaluations of rules with firing alerts are recorded to it.
+	Usage UsageRecorder
 	// DoNotSaveNormalState controls whether eval.Normal state is persisted to the database and returned by get methods
 	DoNotSaveNormalState bool
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
@@ -68,6 +86,18 @@ type ManagerCfg struct {
 	// ApplyNoDataAndErrorToAllStates makes state manager to apply exceptional results (NoData and Error)
 	// to all states when corresponding execution in the rule definition is set to either `Alerting` or `OK`
 	ApplyNoDataAndErrorToAllStates bool
+	// SnapshotStore is optional. If set, and SnapshotInterval is positive, a compressed snapshot of the state is saved
+	// to it at every interval, and the state is restored from the latest snapshot at startup.
+	SnapshotStore    SnapshotStore
+	SnapshotInterval time.Duration
+	// SnapshotNode identifies the snapshots of this server, so that servers in HA do not replace each other's snapshots.
+	SnapshotNode string
+	// LabelRewriters is optional. If set, the label rewrite rules of the organization are applied to the labels
+	// of the evaluation results before alert instances are created.
+	LabelRewriters LabelRewriters
+	// StaleSeriesPolicies is optional. If set, the stale series policy of the organization applies to the rules
+	// that do not set their own. Otherwise, the default policy does.
+	StaleSeriesPolicies StaleSeriesPolicies
 
 	Tracer tracing.Tracer
 	Log    log.Logger
@@ -87,13 +117,21 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		metrics:                        cfg.Metrics,
 		instanceStore:                  cfg.InstanceStore,
 		images:                         cfg.Images,
+		queryPreviews:                  cfg.QueryPreviews,
 		historian:                      cfg.Historian,
+		alertSeries:                    cfg.AlertSeries,
+		usage:                          cfg.Usage,
 		clock:                          cfg.Clock,
 		externalURL:                    cfg.ExternalURL,
 		doNotSaveNormalState:           cfg.DoNotSaveNormalState,
 		applyNoDataAndErrorToAllStates: cfg.ApplyNoDataAndErrorToAllStates,
 		persister:                      statePersister,
 		tracer:                         cfg.Tracer,
+		snapshotStore:                  cfg.SnapshotStore,
+		snapshotInterval:               cfg.SnapshotInterval,
+		snapshotNode:                   cfg.SnapshotNode,
+		labelRewriters:                 cfg.LabelRewriters,
+		staleSeriesPolicies:            cfg.StaleSeriesPolicies,
 	}
 
 	if m.applyNoDataAndErrorToAllStates {
@@ -104,6 +142,15 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 }
 
 func (st *Manager) Run(ctx context.Context) error {
+	if st.SnapshotsEnabled() {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			st.runSnapshots(ctx)
+		}()
+		defer wg.Wait()
+	}
 	st.persister.Async(ctx, st.cache)
 	return nil
 }
@@ -116,13 +163,30 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	startTime := time.Now()
 	st.log.Info("Warming state cache for startup")
 
-	orgIds, err := st.instanceStore.FetchOrgIds(ctx)
-	if err != nil {
-		st.log.Error("Unable to fetch orgIds", "error", err)
+	// If snapshots are enabled, the alert instances are read from the latest snapshot instead of the database.
+	var snapshot map[int64][]*ngModels.AlertInstance
+	fromSnapshot := false
+	if st.SnapshotsEnabled() {
+		snapshot, fromSnapshot = st.loadSnapshot(ctx)
+	}
+
+	var orgIds []int64
+	if fromSnapshot {
+		orgIds = make([]int64, 0, len(snapshot))
+		for orgId := range snapshot {
+			orgIds = append(orgIds, orgId)
+		}
+	} else {
+		var err error
+		orgIds, err = st.instanceStore.FetchOrgIds(ctx)
+		if err != nil {
+			st.log.Error("Unable to fetch orgIds", "error", err)
+		}
 	}
 
 	statesCount := 0
 	states := make(map[int64]map[string]*ruleStates, len(orgIds))
+	var duplicates []ngModels.AlertInstanceKey
 	for _, orgId := range orgIds {
 		// Get Rules
 		ruleCmd := ngModels.ListAlertRulesQuery{
@@ -142,12 +206,17 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 		states[orgId] = orgStates
 
 		// Get Instances
-		cmd := ngModels.ListAlertInstancesQuery{
-			RuleOrgID: orgId,
-		}
-		alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &cmd)
-		if err != nil {
-			st.log.Error("Unable to fetch previous state", "error", err)
+		var alertInstances []*ngModels.AlertInstance
+		if fromSnapshot {
+			alertInstances = snapshot[orgId]
+		} else {
+			cmd := ngModels.ListAlertInstancesQuery{
+				RuleOrgID: orgId,
+			}
+			alertInstances, err = st.instanceStore.ListAlertInstances(ctx, &cmd)
+			if err != nil {
+				st.log.Error("Unable to fetch previous state", "error", err)
+			}
 		}
 
 		for _, entry := range alertInstances {
@@ -159,12 +228,12 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 
 			rulesStates, ok := orgStates[entry.RuleUID]
 			if !ok {
-				rulesStates = &ruleStates{states: make(map[string]*State)}
+				rulesStates = &ruleStates{states: make(map[string]*State), fingerprintLabels: ruleForEntry.FingerprintLabels}
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
 			lbs := map[string]string(entry.Labels)
-			cacheID, err := entry.Labels.StringKey()
+			cacheID, err := entry.Labels.FingerprintKey(ruleForEntry.FingerprintLabels)
 			if err != nil {
 				st.log.Error("Error getting cacheId for entry", "error", err)
 			}
@@ -176,7 +245,7 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				}
 				resultFp = data.Fingerprint(fp)
 			}
-			rulesStates.states[cacheID] = &State{
+			restored := &State{
 				AlertRuleUID:         entry.RuleUID,
 				OrgID:                entry.RuleOrgID,
 				CacheID:              cacheID,
@@ -190,11 +259,31 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				Annotations:          ruleForEntry.Annotations,
 				ResultFingerprint:    resultFp,
 			}
+			// Instances saved before the rule declared its fingerprint labels can collide.
+			// Keep the one that wins the merge, and delete the others.
+			if existing, ok := rulesStates.states[cacheID]; ok {
+				if !preferState(restored, existing) {
+					duplicates = append(duplicates, entry.AlertInstanceKey)
+					continue
+				}
+				if key, err := existing.GetAlertInstanceKey(); err == nil {
+					duplicates = append(duplicates, key)
+				}
+				statesCount--
+			}
+			rulesStates.states[cacheID] = restored
 			statesCount++
 		}
 	}
 	st.cache.setAllStates(states)
-	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
+	st.log.Info("State cache has been initialized", "states", statesCount, "fromSnapshot", fromSnapshot, "duration", time.Since(startTime))
+
+	if len(duplicates) > 0 {
+		st.log.Info("Deleting alert instances that are duplicates according to the fingerprint labels of their rules", "count", len(duplicates))
+		if err := st.instanceStore.DeleteAlertInstances(ctx, duplicates...); err != nil {
+			st.log.Error("Failed to delete duplicate alert instances", "error", err)
+		}
+	}
 }
 
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
@@ -244,6 +333,10 @@ func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 	}
 	logger.Info("Rules state was reset", "states", len(states))
 
+	if st.alertSeries != nil {
+		st.alertSeries.Write(ctx, now, transitions)
+	}
+
 	return transitions
 }
 
@@ -282,43 +375,123 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 
 	logger := st.log.FromContext(tracingCtx)
 	logger.Debug("State manager processing evaluation results", "resultCount", len(results))
-	states := st.setNextStateForRule(tracingCtx, alertRule, results, extraLabels, logger)
+	if merged := st.cache.migrateFingerprintLabels(logger, alertRule); len(merged) > 0 {
+		st.deleteMergedStates(tracingCtx, logger, merged)
+	}
+	results = st.rewriteLabels(logger, alertRule.OrgID, results)
+	states, replaced := st.setNextStateForRule(tracingCtx, alertRule, results, extraLabels, logger)
 	span.AddEvent("results processed", trace.WithAttributes(
 		attribute.Int64("state_transitions", int64(len(states))),
 	))
 
 	staleStates := st.deleteStaleStatesFromCache(ctx, logger, evaluatedAt, alertRule)
+	for _, s := range replaced {
+		logger.Info("Detected state entry whose labels changed", "cacheID", s.CacheID, "state", s.State, "reason", s.StateReason)
+		staleStates = append(staleStates, st.resolveStaleState(ctx, logger, evaluatedAt, alertRule, s))
+	}
 	st.persister.Sync(tracingCtx, span, states, staleStates)
 
 	allChanges := append(states, staleStates...)
 	if st.historian != nil {
 		st.historian.Record(tracingCtx, history_model.NewRuleMeta(alertRule, logger), allChanges)
 	}
+	if st.alertSeries != nil {
+		st.alertSeries.Write(tracingCtx, evaluatedAt, allChanges)
+	}
+	if st.usage != nil {
+		for _, s := range states {
+			if s.State.State == eval.Alerting {
+				st.usage.RuleFired(alertRule.OrgID, alertRule.UID, evaluatedAt)
+				break
+			}
+		}
+	}
 	return allChanges
 }
 
-func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger) []StateTransition {
+// setNextStateForRule calculates the next states of the rule. It also returns the states that were replaced by states
+// with the same ID but different labels, which must be resolved like stale states.
+func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger) ([]StateTransition, []*State) {
 	if st.applyNoDataAndErrorToAllStates && results.IsNoData() && (alertRule.NoDataState == ngModels.Alerting || alertRule.NoDataState == ngModels.OK) { // If it is no data, check the mapping and switch all results to the new state
 		// TODO aggregate UID of datasources that returned NoData into one and provide as auxiliary info, probably annotation
 		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
 		if len(transitions) > 0 {
-			return transitions // if there are no current states for the rule. Create ones for each result
+			return transitions, nil // if there are no current states for the rule. Create ones for each result
 		}
 	}
 	if st.applyNoDataAndErrorToAllStates && results.IsError() && (alertRule.ExecErrState == ngModels.AlertingErrState || alertRule.ExecErrState == ngModels.OkErrState) {
 		// TODO squash all errors into one, and provide as annotation
 		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
 		if len(transitions) > 0 {
-			return transitions // if there are no current states for the rule. Create ones for each result
+			return transitions, nil // if there are no current states for the rule. Create ones for each result
 		}
 	}
-	transitions := make([]StateTransition, 0, len(results))
-	for _, result := range results {
-		currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL)
-		s := st.setNextState(ctx, alertRule, currentState, result, logger)
+	candidates := mergeCandidates(ctx, logger, alertRule, results, extraLabels, st.externalURL)
+	transitions := make([]StateTransition, 0, len(candidates))
+	var replaced []*State
+	for _, c := range candidates {
+		currentState, previous := st.cache.getOrAdd(c.state)
+		if previous != nil {
+			replaced = append(replaced, previous)
+		}
+		s := st.setNextState(ctx, alertRule, currentState, c.result, logger)
 		transitions = append(transitions, s)
 	}
-	return transitions
+	return transitions, replaced
+}
+
+type resultCandidate struct {
+	state  State
+	result eval.Result
+}
+
+// mergeCandidates calculates the state candidates of the results. Results that share the state ID because they
+// differ only by labels other than the fingerprint labels of the rule are merged into one, the worst of them wins.
+func mergeCandidates(ctx context.Context, logger log.Logger, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, externalURL *url.URL) []resultCandidate {
+	candidates := make([]resultCandidate, 0, len(results))
+	byID := make(map[string]int, len(results))
+	for _, result := range results {
+		c := resultCandidate{
+			state:  calculateState(ctx, logger, alertRule, result, extraLabels, externalURL),
+			result: result,
+		}
+		idx, ok := byID[c.state.CacheID]
+		if !ok {
+			byID[c.state.CacheID] = len(candidates)
+			candidates = append(candidates, c)
+			continue
+		}
+		logger.Debug("Merging results that share the fingerprint labels", "labels", c.state.Labels, "other", candidates[idx].state.Labels)
+		if c.preferredOver(candidates[idx]) {
+			candidates[idx] = c
+		}
+	}
+	return candidates
+}
+
+// preferredOver reports whether the candidate should be kept over the other one with the same state ID.
+// The worse result wins, ties are broken by labels so that the outcome does not depend on the order of the results.
+func (c resultCandidate) preferredOver(other resultCandidate) bool {
+	if sc, so := stateSeverity(c.result.State), stateSeverity(other.result.State); sc != so {
+		return sc > so
+	}
+	return c.state.Labels.String() < other.state.Labels.String()
+}
+
+// deleteMergedStates deletes the instances of the states that were merged into others from the database.
+func (st *Manager) deleteMergedStates(ctx context.Context, logger log.Logger, merged []*State) {
+	if st.instanceStore == nil {
+		return
+	}
+	keys := make([]ngModels.AlertInstanceKey, 0, len(merged))
+	for _, s := range merged {
+		if key, err := s.GetAlertInstanceKey(); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	if err := st.instanceStore.DeleteAlertInstances(ctx, keys...); err != nil {
+		logger.Error("Failed to delete alert instances merged by the fingerprint labels of the rule", "error", err)
+	}
 }
 
 func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, logger log.Logger) []StateTransition {
@@ -336,6 +509,7 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	start := st.clock.Now()
 	currentState.LastEvaluationTime = result.EvaluatedAt
 	currentState.EvaluationDuration = result.EvaluationDuration
+	currentState.LastEvaluationTraceID = tracing.TraceIDFromContext(ctx, true)
 	currentState.Results = append(currentState.Results, Evaluation{
 		EvaluationTime:  result.EvaluatedAt,
 		EvaluationState: result.State,
@@ -413,6 +587,15 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		}
 	}
 
+	if st.queryPreviews != nil && shouldTakeQueryPreview(currentState.State, oldState, currentState.QueryPreview) {
+		preview, err := takeQueryPreview(ctx, st.queryPreviews, alertRule, result.EvaluatedAt)
+		if err != nil {
+			logger.Warn("Failed to take a query preview", "error", err)
+		} else if preview != nil {
+			currentState.QueryPreview = preview
+		}
+	}
+
 	st.cache.set(currentState)
 
 	nextState := StateTransition{
@@ -462,44 +645,50 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 func (st *Manager) deleteStaleStatesFromCache(ctx context.Context, logger log.Logger, evaluatedAt time.Time, alertRule *ngModels.AlertRule) []StateTransition {
 	// If we are removing two or more stale series it makes sense to share the resolved image as the alert rule is the same.
 	// TODO: We will need to change this when we support images without screenshots as each series will have a different image
+	policy := st.staleSeriesPolicy(alertRule)
 	staleStates := st.cache.deleteRuleStates(alertRule.GetKey(), func(s *State) bool {
-		return stateIsStale(evaluatedAt, s.LastEvaluationTime, alertRule.IntervalSeconds)
+		return stateIsStale(policy, evaluatedAt, s.LastEvaluationTime, alertRule.IntervalSeconds)
 	})
 	resolvedStates := make([]StateTransition, 0, len(staleStates))
 
 	for _, s := range staleStates {
 		logger.Info("Detected stale state entry", "cacheID", s.CacheID, "state", s.State, "reason", s.StateReason)
-		oldState := s.State
-		oldReason := s.StateReason
+		resolvedStates = append(resolvedStates, st.resolveStaleState(ctx, logger, evaluatedAt, alertRule, s))
+	}
+	return resolvedStates
+}
 
-		s.State = eval.Normal
-		s.StateReason = ngModels.StateReasonMissingSeries
-		s.EndsAt = evaluatedAt
-		s.LastEvaluationTime = evaluatedAt
+// resolveStaleState resolves a state that was removed from the cache because its series is missing.
+func (st *Manager) resolveStaleState(ctx context.Context, logger log.Logger, evaluatedAt time.Time, alertRule *ngModels.AlertRule, s *State) StateTransition {
+	oldState := s.State
+	oldReason := s.StateReason
 
-		if oldState == eval.Alerting {
-			s.Resolved = true
-			image, err := takeImage(ctx, st.images, alertRule)
-			if err != nil {
-				logger.Warn("Failed to take an image",
-					"dashboard", alertRule.GetDashboardUID(),
-					"panel", alertRule.GetPanelID(),
-					"error", err)
-			} else if image != nil {
-				s.Image = image
-			}
-		}
+	s.State = eval.Normal
+	s.StateReason = ngModels.StateReasonMissingSeries
+	s.EndsAt = evaluatedAt
+	s.LastEvaluationTime = evaluatedAt
+	s.LastEvaluationTraceID = tracing.TraceIDFromContext(ctx, true)
 
-		record := StateTransition{
-			State:               s,
-			PreviousState:       oldState,
-			PreviousStateReason: oldReason,
+	if oldState == eval.Alerting {
+		s.Resolved = true
+		image, err := takeImage(ctx, st.images, alertRule)
+		if err != nil {
+			logger.Warn("Failed to take an image",
+				"dashboard", alertRule.GetDashboardUID(),
+				"panel", alertRule.GetPanelID(),
+				"error", err)
+		} else if image != nil {
+			s.Image = image
 		}
-		resolvedStates = append(resolvedStates, record)
 	}
-	return resolvedStates
+
+	return StateTransition{
+		State:               s,
+		PreviousState:       oldState,
+		PreviousStateReason: oldReason,
+	}
 }
 
-func stateIsStale(evaluatedAt time.Time, lastEval time.Time, intervalSeconds int64) bool {
-	return !lastEval.Add(2 * time.Duration(intervalSeconds) * time.Second).After(evaluatedAt)
+func stateIsStale(policy ngModels.StaleSeriesPolicy, evaluatedAt time.Time, lastEval time.Time, intervalSeconds int64) bool {
+	return policy.IsStale(evaluatedAt, lastEval, intervalSeconds)
 }
@@ -74,7 +74,7 @@ func TestStateIsStale(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			require.Equal(t, tc.expectedResult, stateIsStale(now, tc.lastEvaluation, intervalSeconds))
+			require.Equal(t, tc.expectedResult, stateIsStale(ngmodels.StaleSeriesPolicy{}, now, tc.lastEvaluation, intervalSeconds))
 		})
 	}
 }
@@ -1676,6 +1676,99 @@ func TestStaleResults(t *testing.T) {
 	})
 }
 
+func TestProcessEvalResultsRecordsTraceID(t *testing.T) {
+	clk := clock.NewMock()
+	tracer := tracing.InitializeTracerForTest()
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracer,
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+	rule := models.AlertRuleGen(models.WithFor(0))()
+	results := eval.Results{eval.ResultGen(eval.WithEvaluatedAt(clk.Now()))()}
+
+	st.ProcessEvalResults(context.Background(), clk.Now(), rule, results, nil)
+	states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Empty(t, states[0].LastEvaluationTraceID)
+
+	ctx, span := tracer.Start(context.Background(), "alert rule execution")
+	defer span.End()
+	clk.Add(time.Duration(rule.IntervalSeconds) * time.Second)
+	results[0].EvaluatedAt = clk.Now()
+	st.ProcessEvalResults(ctx, clk.Now(), rule, results, nil)
+	states = st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, span.SpanContext().TraceID().String(), states[0].LastEvaluationTraceID)
+}
+
+func TestProcessEvalResultsLabelsChange(t *testing.T) {
+	clk := clock.NewMock()
+	instanceStore := &state.FakeInstanceStore{}
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: instanceStore,
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewSyncStatePersisiter(log.New("ngalert.state.manager.persist"), cfg))
+	rule := models.AlertRuleGen(models.WithFor(0))()
+	rule.FingerprintLabels = []string{"namespace"}
+
+	process := func(pod string) []state.StateTransition {
+		results := eval.Results{eval.ResultGen(
+			eval.WithEvaluatedAt(clk.Now()),
+			eval.WithState(eval.Alerting),
+			eval.WithLabels(data.Labels{"namespace": "a", "pod": pod}),
+		)()}
+		return st.ProcessEvalResults(context.Background(), clk.Now(), rule, results, nil)
+	}
+
+	first := process("a-1")
+	require.Len(t, first, 1)
+	require.Equal(t, eval.Alerting, first[0].State.State)
+	oldKey, err := first[0].State.GetAlertInstanceKey()
+	require.NoError(t, err)
+
+	// the labels of the series changed, but not the fingerprint labels of the rule
+	instanceStore.RecordedOps = nil
+	clk.Add(time.Duration(rule.IntervalSeconds) * time.Second)
+	transitions := process("a-2")
+	require.Len(t, transitions, 2)
+
+	current, replaced := transitions[0], transitions[1]
+	require.Equal(t, "a-2", current.State.Labels["pod"])
+	require.Equal(t, eval.Alerting, current.State.State)
+	require.Equal(t, eval.Normal, current.PreviousState)
+
+	require.Equal(t, "a-1", replaced.State.Labels["pod"])
+	require.Equal(t, eval.Normal, replaced.State.State)
+	require.Equal(t, eval.Alerting, replaced.PreviousState)
+	require.Equal(t, models.StateReasonMissingSeries, replaced.State.StateReason)
+	require.True(t, replaced.State.Resolved)
+
+	// the instance of the previous labels is deleted from the database
+	var deleted []models.AlertInstanceKey
+	for _, op := range instanceStore.RecordedOps {
+		if op, ok := op.(state.FakeInstanceStoreOp); ok && op.Name == "DeleteAlertInstances" {
+			deleted = append(deleted, op.Args[1].([]models.AlertInstanceKey)...)
+		}
+	}
+	require.Equal(t, []models.AlertInstanceKey{oldKey}, deleted)
+
+	states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, "a-2", states[0].Labels["pod"])
+}
+
 func TestDeleteStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
@@ -17,6 +18,13 @@ type InstanceStore interface {
 	FullSync(ctx context.Context, instances []models.AlertInstance) error
 }
 
+// SnapshotStore represents the ability to persist compressed snapshots of the alert instances of all organizations.
+type SnapshotStore interface {
+	SaveAlertInstanceSnapshot(ctx context.Context, snapshot *models.AlertInstanceSnapshot) error
+	GetLatestAlertInstanceSnapshot(ctx context.Context, node string, withData bool) (*models.AlertInstanceSnapshot, error)
+	ListAlertInstanceKeys(ctx context.Context) ([]models.AlertInstanceKey, error)
+}
+
 // RuleReader represents the ability to fetch alert rules.
 type RuleReader interface {
 	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
@@ -30,9 +38,26 @@ type Historian interface {
 	Record(ctx context.Context, rule history_model.RuleMeta, states []StateTransition) <-chan error
 }
 
+// AlertSeriesWriter writes series that describe the state of alerts, like the ALERTS and ALERTS_FOR_STATE series of the Prometheus ruler.
+type AlertSeriesWriter interface {
+	// Write writes the series of the states of a rule at the time they were evaluated. The writer must not block.
+	Write(ctx context.Context, evaluatedAt time.Time, states []StateTransition)
+}
+
+// UsageRecorder records the use of alert rules, for the usage insights of alerting objects.
+type UsageRecorder interface {
+	// RuleFired records that the rule was evaluated with firing alerts at the time. The recorder must not block.
+	RuleFired(orgID int64, ruleUID string, at time.Time)
+}
+
 // ImageCapturer captures images.
 //
 //go:generate mockgen -destination=image_mock.go -package=state github.com/grafana/grafana/pkg/services/ngalert/state ImageCapturer
 type ImageCapturer interface {
 	NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error)
 }
+
+// QueryPreviewCapturer captures previews of the queries of alert rules.
+type QueryPreviewCapturer interface {
+	NewQueryPreview(ctx context.Context, r *models.AlertRule, at time.Time) (*models.Image, error)
+}
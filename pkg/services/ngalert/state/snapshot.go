@@ -0,0 +1,180 @@
+package state
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ErrSnapshotsDisabled is returned when snapshots of the state are requested but not configured.
+var ErrSnapshotsDisabled = errors.New("state snapshots are disabled")
+
+// SnapshotsEnabled returns true if the state is periodically saved to snapshots, and restored from them at startup.
+func (st *Manager) SnapshotsEnabled() bool {
+	return st.snapshotStore != nil && st.snapshotInterval > 0
+}
+
+// SnapshotInterval returns the interval at which snapshots of the state are taken.
+func (st *Manager) SnapshotInterval() time.Duration {
+	return st.snapshotInterval
+}
+
+// Snapshot saves a compressed snapshot of the alert instances of all organizations, and returns it without its data.
+func (st *Manager) Snapshot(ctx context.Context) (*ngModels.AlertInstanceSnapshot, error) {
+	if !st.SnapshotsEnabled() {
+		return nil, ErrSnapshotsDisabled
+	}
+	st.snapshotMtx.Lock()
+	defer st.snapshotMtx.Unlock()
+
+	// the snapshot is created when the state is read, so that the instances written while it is saved are replayed
+	startTime := time.Now()
+	instances := st.cache.asInstances(false)
+	data, err := encodeSnapshot(instances)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the state snapshot: %w", err)
+	}
+	snapshot := &ngModels.AlertInstanceSnapshot{
+		Node:      st.snapshotNode,
+		Created:   startTime.UTC(),
+		Instances: int64(len(instances)),
+		Data:      data,
+	}
+	if err := st.snapshotStore.SaveAlertInstanceSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save the state snapshot: %w", err)
+	}
+	snapshot.Data = nil
+	st.log.Debug("State snapshot saved", "instances", snapshot.Instances, "size", snapshot.Size, "duration", time.Since(startTime))
+	return snapshot, nil
+}
+
+// LatestSnapshot returns the latest snapshot of the state saved by this server without its data, or nil if there is none.
+func (st *Manager) LatestSnapshot(ctx context.Context) (*ngModels.AlertInstanceSnapshot, error) {
+	if !st.SnapshotsEnabled() {
+		return nil, ErrSnapshotsDisabled
+	}
+	return st.snapshotStore.GetLatestAlertInstanceSnapshot(ctx, st.snapshotNode, false)
+}
+
+// runSnapshots saves a snapshot of the state at every snapshot interval, and a final one when the context is done.
+func (st *Manager) runSnapshots(ctx context.Context) {
+	ticker := st.clock.Ticker(st.snapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := st.Snapshot(ctx); err != nil {
+				st.log.Error("Failed to save state snapshot", "error", err)
+			}
+		case <-ctx.Done():
+			st.log.Info("Scheduler is shutting down, saving a final state snapshot")
+			if _, err := st.Snapshot(context.Background()); err != nil {
+				st.log.Error("Failed to save state snapshot", "error", err)
+			}
+			return
+		}
+	}
+}
+
+// loadSnapshot returns the alert instances of the latest snapshot grouped by organization. It returns false if there is
+// no snapshot, or if it cannot be read, in which case the state must be restored from the alert instances in the database.
+// The alert instances that were written to the database after the snapshot was taken replace the ones of the snapshot,
+// and the ones that were deleted from the database since are dropped.
+func (st *Manager) loadSnapshot(ctx context.Context) (map[int64][]*ngModels.AlertInstance, bool) {
+	snapshot, err := st.snapshotStore.GetLatestAlertInstanceSnapshot(ctx, st.snapshotNode, true)
+	if err != nil {
+		st.log.Error("Unable to fetch the state snapshot, falling back to the alert instances", "error", err)
+		return nil, false
+	}
+	if snapshot == nil {
+		st.log.Info("No state snapshot found, falling back to the alert instances")
+		return nil, false
+	}
+	instances, err := decodeSnapshot(snapshot.Data)
+	if err != nil {
+		st.log.Error("Unable to decode the state snapshot, falling back to the alert instances", "error", err)
+		return nil, false
+	}
+	newer, err := st.instancesEvaluatedAfter(ctx, snapshot.Created)
+	if err != nil {
+		st.log.Error("Unable to fetch the alert instances written after the state snapshot, falling back to the alert instances", "error", err)
+		return nil, false
+	}
+	keys, err := st.snapshotStore.ListAlertInstanceKeys(ctx)
+	if err != nil {
+		st.log.Error("Unable to fetch the keys of the alert instances, falling back to the alert instances", "error", err)
+		return nil, false
+	}
+	existing := make(map[ngModels.AlertInstanceKey]struct{}, len(keys))
+	for _, key := range keys {
+		existing[key] = struct{}{}
+	}
+	st.log.Info("Restoring state from snapshot", "created", snapshot.Created, "instances", len(instances), "newerInstances", len(newer))
+
+	byKey := make(map[ngModels.AlertInstanceKey]*ngModels.AlertInstance, len(instances)+len(newer))
+	for i := range instances {
+		if _, ok := existing[instances[i].AlertInstanceKey]; !ok {
+			continue
+		}
+		byKey[instances[i].AlertInstanceKey] = &instances[i]
+	}
+	for _, instance := range newer {
+		byKey[instance.AlertInstanceKey] = instance
+	}
+	result := make(map[int64][]*ngModels.AlertInstance)
+	for _, instance := range byKey {
+		result[instance.RuleOrgID] = append(result[instance.RuleOrgID], instance)
+	}
+	return result, true
+}
+
+// instancesEvaluatedAfter returns the alert instances of all organizations that were evaluated after the given time.
+func (st *Manager) instancesEvaluatedAfter(ctx context.Context, after time.Time) ([]*ngModels.AlertInstance, error) {
+	orgIDs, err := st.instanceStore.FetchOrgIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []*ngModels.AlertInstance
+	for _, orgID := range orgIDs {
+		instances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+			RuleOrgID:      orgID,
+			EvaluatedAfter: after,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, instances...)
+	}
+	return result, nil
+}
+
+func encodeSnapshot(instances []ngModels.AlertInstance) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(instances); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshot(data []byte) ([]ngModels.AlertInstance, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	var instances []ngModels.AlertInstance
+	if err := json.NewDecoder(r).Decode(&instances); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
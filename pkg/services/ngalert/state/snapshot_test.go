@@ -0,0 +1,205 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestStateSnapshots(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+
+	for _, instance := range []struct {
+		labels models.InstanceLabels
+		state  models.InstanceStateType
+	}{
+		{labels: models.InstanceLabels{"test1": "testValue1"}, state: models.InstanceStateNormal},
+		{labels: models.InstanceLabels{"test2": "testValue2"}, state: models.InstanceStateFiring},
+	} {
+		_, hash, _ := instance.labels.StringAndHash()
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  rule.OrgID,
+				RuleUID:    rule.UID,
+				LabelsHash: hash,
+			},
+			CurrentState:      instance.state,
+			LastEvalTime:      evaluationTime,
+			CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
+			CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+			Labels:            instance.labels,
+			ResultFingerprint: data.Fingerprint(1).String(),
+		}))
+	}
+
+	newNodeManager := func(node string, interval time.Duration) *state.Manager {
+		cfg := state.ManagerCfg{
+			Metrics:          metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore:    dbstore,
+			Images:           &state.NoopImageService{},
+			Clock:            clock.NewMock(),
+			Historian:        &state.FakeHistorian{},
+			SnapshotStore:    dbstore,
+			SnapshotInterval: interval,
+			SnapshotNode:     node,
+			Tracer:           tracing.InitializeTracerForTest(),
+			Log:              log.New("ngalert.state.manager"),
+		}
+		return state.NewManager(cfg, state.NewNoopPersister())
+	}
+	newManager := func(interval time.Duration) *state.Manager {
+		return newNodeManager("node-1", interval)
+	}
+
+	t.Run("snapshots fail if they are disabled", func(t *testing.T) {
+		st := newManager(0)
+		_, err := st.Snapshot(ctx)
+		require.ErrorIs(t, err, state.ErrSnapshotsDisabled)
+		_, err = st.LatestSnapshot(ctx)
+		require.ErrorIs(t, err, state.ErrSnapshotsDisabled)
+	})
+
+	t.Run("state is restored from the alert instances if there is no snapshot", func(t *testing.T) {
+		st := newManager(time.Minute)
+		latest, err := st.LatestSnapshot(ctx)
+		require.NoError(t, err)
+		require.Nil(t, latest)
+
+		st.Warm(ctx, dbstore)
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), 2)
+	})
+
+	t.Run("state is restored from the latest snapshot", func(t *testing.T) {
+		st := newManager(time.Minute)
+		st.Warm(ctx, dbstore)
+
+		snapshot, err := st.Snapshot(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, snapshot.Instances)
+		require.Positive(t, snapshot.Size)
+		require.Nil(t, snapshot.Data)
+
+		latest, err := st.LatestSnapshot(ctx)
+		require.NoError(t, err)
+		require.Equal(t, snapshot.ID, latest.ID)
+		require.Nil(t, latest.Data)
+
+		// the alert instance is overwritten with a state that was evaluated before the snapshot, so that the state can
+		// only come from the snapshot
+		firing := models.InstanceLabels{"test2": "testValue2"}
+		_, hash, _ := firing.StringAndHash()
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+			AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash},
+			CurrentState:      models.InstanceStateNormal,
+			LastEvalTime:      evaluationTime,
+			CurrentStateSince: evaluationTime,
+			CurrentStateEnd:   evaluationTime,
+			Labels:            firing,
+		}))
+
+		restored := newManager(time.Minute)
+		restored.Warm(ctx, dbstore)
+		states := restored.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 2)
+		byState := make(map[eval.State]*state.State, len(states))
+		for _, s := range states {
+			byState[s.State] = s
+		}
+		require.Equal(t, data.Labels(firing), data.Labels(byState[eval.Alerting].Labels))
+		require.Equal(t, evaluationTime, byState[eval.Alerting].LastEvaluationTime.UTC())
+		require.Equal(t, data.Fingerprint(1), byState[eval.Normal].ResultFingerprint)
+	})
+
+	t.Run("alert instances deleted after the snapshot are not restored", func(t *testing.T) {
+		st := newManager(time.Minute)
+		st.Warm(ctx, dbstore)
+		_, err := st.Snapshot(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, dbstore.DeleteAlertInstancesByRule(ctx, rule.GetKey()))
+
+		restored := newManager(time.Minute)
+		restored.Warm(ctx, dbstore)
+		require.Empty(t, restored.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	})
+
+	t.Run("only the latest snapshot is kept", func(t *testing.T) {
+		st := newManager(time.Minute)
+		first, err := st.Snapshot(ctx)
+		require.NoError(t, err)
+		second, err := st.Snapshot(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, first.ID, second.ID)
+
+		latest, err := st.LatestSnapshot(ctx)
+		require.NoError(t, err)
+		require.Equal(t, second.ID, latest.ID)
+		require.EqualValues(t, 0, latest.Instances)
+	})
+
+	t.Run("snapshots of other nodes are kept", func(t *testing.T) {
+		own, err := newManager(time.Minute).Snapshot(ctx)
+		require.NoError(t, err)
+		other, err := newNodeManager("node-2", time.Minute).Snapshot(ctx)
+		require.NoError(t, err)
+
+		latest, err := newManager(time.Minute).LatestSnapshot(ctx)
+		require.NoError(t, err)
+		require.Equal(t, own.ID, latest.ID)
+		latest, err = newNodeManager("node-2", time.Minute).LatestSnapshot(ctx)
+		require.NoError(t, err)
+		require.Equal(t, other.ID, latest.ID)
+	})
+
+	t.Run("alert instances written after the snapshot replace the ones of the snapshot", func(t *testing.T) {
+		labels := models.InstanceLabels{"test3": "testValue3"}
+		saveInstance := func(state models.InstanceStateType, evaluatedAt time.Time) {
+			_, hash, _ := labels.StringAndHash()
+			require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+				AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash},
+				CurrentState:      state,
+				LastEvalTime:      evaluatedAt,
+				CurrentStateSince: evaluatedAt,
+				CurrentStateEnd:   evaluatedAt.Add(time.Minute),
+				Labels:            labels,
+			}))
+		}
+		saveInstance(models.InstanceStateNormal, time.Now().Add(-time.Hour))
+		st := newManager(time.Minute)
+		st.Warm(ctx, dbstore)
+		_, err := st.Snapshot(ctx)
+		require.NoError(t, err)
+
+		saveInstance(models.InstanceStateFiring, time.Now().Add(time.Hour))
+
+		restored := newManager(time.Minute)
+		restored.Warm(ctx, dbstore)
+		var found *state.State
+		for _, s := range restored.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+			if s.Labels["test3"] == "testValue3" {
+				found = s
+			}
+		}
+		require.NotNil(t, found)
+		require.Equal(t, eval.Alerting, found.State)
+	})
+}
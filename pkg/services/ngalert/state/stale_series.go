@@ -0,0 +1,88 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// StaleSeriesPolicies provides the stale series policies of organizations.
+type StaleSeriesPolicies interface {
+	// StaleSeriesPolicy returns the policy of the organization. It is zero if the organization does not set one.
+	StaleSeriesPolicy(orgID int64) ngModels.StaleSeriesPolicy
+}
+
+// AdminConfigStaleSeriesPolicies provides the stale series policies of the admin configurations of organizations.
+// The policies are cached and fetched again once they are older than the poll interval, or after they are invalidated.
+type AdminConfigStaleSeriesPolicies struct {
+	store        AdminConfigReader
+	pollInterval time.Duration
+	clock        clock.Clock
+	log          log.Logger
+
+	mtx       sync.Mutex
+	policies  map[int64]ngModels.StaleSeriesPolicy
+	fetchedAt time.Time
+}
+
+func NewAdminConfigStaleSeriesPolicies(store AdminConfigReader, pollInterval time.Duration, clk clock.Clock, logger log.Logger) *AdminConfigStaleSeriesPolicies {
+	return &AdminConfigStaleSeriesPolicies{
+		store:        store,
+		pollInterval: pollInterval,
+		clock:        clk,
+		log:          logger,
+	}
+}
+
+// StaleSeriesPolicy returns the policy of the organization. If the admin configurations cannot be fetched,
+// the default policy applies until they are fetched successfully.
+func (p *AdminConfigStaleSeriesPolicies) StaleSeriesPolicy(orgID int64) ngModels.StaleSeriesPolicy {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	now := p.clock.Now()
+	if p.policies != nil && now.Sub(p.fetchedAt) < p.pollInterval {
+		return p.policies[orgID]
+	}
+	cfgs, err := p.store.GetAdminConfigurations()
+	if err != nil {
+		p.log.Error("Failed to fetch admin configurations, the default stale series policy applies", "error", err)
+		return ngModels.StaleSeriesPolicy{}
+	}
+	policies := make(map[int64]ngModels.StaleSeriesPolicy, len(cfgs))
+	for _, cfg := range cfgs {
+		policy := cfg.StaleSeriesPolicy()
+		if policy.IsZero() {
+			continue
+		}
+		if err := policy.Validate(); err != nil {
+			p.log.Error("Invalid stale series policy, the default policy applies to the organization", "org", cfg.OrgID, "error", err)
+			continue
+		}
+		policies[cfg.OrgID] = policy
+	}
+	p.policies = policies
+	p.fetchedAt = now
+	return policies[orgID]
+}
+
+// AdminConfigurationChanged drops the cached policies, so that the next evaluation uses the current policy of the organization.
+func (p *AdminConfigStaleSeriesPolicies) AdminConfigurationChanged(orgID int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.policies = nil
+	p.log.Debug("Admin configuration changed, stale series policies will be refreshed", "org", orgID)
+}
+
+// staleSeriesPolicy returns the policy of the rule, or the policy of its organization if the rule does not set one.
+// The default policy applies if neither does.
+func (st *Manager) staleSeriesPolicy(alertRule *ngModels.AlertRule) ngModels.StaleSeriesPolicy {
+	policy := alertRule.StaleSeriesPolicy()
+	if policy.IsZero() && st.staleSeriesPolicies != nil {
+		policy = st.staleSeriesPolicies.StaleSeriesPolicy(alertRule.OrgID)
+	}
+	return policy
+}
@@ -0,0 +1,101 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestAdminConfigStaleSeriesPolicies(t *testing.T) {
+	reader := &fakeAdminConfigReader{configs: []*models.AdminConfiguration{
+		{OrgID: 1, MissingSeriesDurationToResolve: time.Hour},
+		{OrgID: 2},
+		{OrgID: 3, MissingSeriesEvalsToResolve: 3, MissingSeriesDurationToResolve: time.Hour},
+	}}
+	clk := clock.NewMock()
+	policies := state.NewAdminConfigStaleSeriesPolicies(reader, time.Minute, clk, log.NewNopLogger())
+
+	require.Equal(t, models.StaleSeriesPolicy{DurationToResolve: time.Hour}, policies.StaleSeriesPolicy(1))
+	require.True(t, policies.StaleSeriesPolicy(2).IsZero())
+	require.True(t, policies.StaleSeriesPolicy(3).IsZero(), "invalid policies must be ignored")
+	require.Equal(t, 1, reader.calls)
+
+	policies.AdminConfigurationChanged(1)
+	policies.StaleSeriesPolicy(1)
+	require.Equal(t, 2, reader.calls)
+
+	clk.Add(time.Minute)
+	policies.StaleSeriesPolicy(1)
+	require.Equal(t, 3, reader.calls)
+}
+
+func TestProcessEvalResults_StaleSeriesPolicy(t *testing.T) {
+	policies := state.NewAdminConfigStaleSeriesPolicies(&fakeAdminConfigReader{configs: []*models.AdminConfiguration{
+		{OrgID: 1, MissingSeriesDurationToResolve: 10 * time.Minute},
+	}}, time.Minute, clock.NewMock(), log.NewNopLogger())
+	cfg := state.ManagerCfg{
+		Metrics:             metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		Images:              &state.NoopImageService{},
+		Clock:               clock.NewMock(),
+		Historian:           &state.FakeHistorian{},
+		StaleSeriesPolicies: policies,
+		Tracer:              tracing.InitializeTracerForTest(),
+		Log:                 log.New("ngalert.state.manager"),
+	}
+
+	// resolvedAfter evaluates the rule with two series, then with only one of them every minute, and returns
+	// how long the missing series was missing when its alert instance was resolved.
+	resolvedAfter := func(t *testing.T, rule *models.AlertRule) time.Duration {
+		t.Helper()
+		st := state.NewManager(cfg, state.NewNoopPersister())
+		start := time.Unix(0, 0)
+		results := func(at time.Time, labels ...data.Labels) eval.Results {
+			result := make(eval.Results, 0, len(labels))
+			for _, l := range labels {
+				result = append(result, eval.Result{Instance: l, State: eval.Alerting, EvaluatedAt: at})
+			}
+			return result
+		}
+		st.ProcessEvalResults(context.Background(), start, rule, results(start, data.Labels{"series": "a"}, data.Labels{"series": "sparse"}), nil)
+		for i := 1; i <= 60; i++ {
+			at := start.Add(time.Duration(i) * time.Minute)
+			transitions := st.ProcessEvalResults(context.Background(), at, rule, results(at, data.Labels{"series": "a"}), nil)
+			for _, tr := range transitions {
+				if tr.StateReason == models.StateReasonMissingSeries {
+					require.True(t, tr.Resolved)
+					return at.Sub(start)
+				}
+			}
+		}
+		require.Fail(t, "the missing series was not resolved")
+		return 0
+	}
+
+	t.Run("should apply the policy of the organization", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(1), models.WithFor(0), models.WithInterval(time.Minute))()
+		require.Equal(t, 10*time.Minute, resolvedAfter(t, rule))
+	})
+
+	t.Run("should apply the policy of the rule over the policy of the organization", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(1), models.WithFor(0), models.WithInterval(time.Minute))()
+		rule.MissingSeriesEvalsToResolve = 4
+		require.Equal(t, 4*time.Minute, resolvedAfter(t, rule))
+	})
+
+	t.Run("should apply the default policy if neither sets one", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(2), models.WithFor(0), models.WithInterval(time.Minute))()
+		require.Equal(t, 2*time.Minute, resolvedAfter(t, rule))
+	})
+}
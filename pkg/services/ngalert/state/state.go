@@ -52,6 +52,10 @@ type State struct {
 	// as a visualization to show why the alert fired.
 	Image *models.Image
 
+	// QueryPreview contains an optional image of the queries of the alert rule around the time the
+	// alert started firing. It is included only in the notifications of the contact points that opt in.
+	QueryPreview *models.Image
+
 	// Annotations contains the annotations from the alert rule. If an annotation is templated
 	// then the template is first evaluated to derive the final annotation.
 	Annotations map[string]string
@@ -70,6 +74,10 @@ type State struct {
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
 	EvaluationDuration   time.Duration
+
+	// LastEvaluationTraceID is the ID of the trace of the evaluation that last updated the state.
+	// It is empty if the evaluation was not traced or the trace was not sampled.
+	LastEvaluationTraceID string
 }
 
 func (a *State) GetRuleKey() models.AlertRuleKey {
@@ -474,6 +482,25 @@ func takeImage(ctx context.Context, s ImageCapturer, r *models.AlertRule) (*mode
 	return img, nil
 }
 
+// shouldTakeQueryPreview returns true if the state just has transitioned to alerting from another state,
+// or transitioned to alerting in a previous evaluation but does not have a query preview.
+func shouldTakeQueryPreview(state, previousState eval.State, previousPreview *models.Image) bool {
+	return state == eval.Alerting && (previousState != eval.Alerting || previousPreview == nil)
+}
+
+// takeQueryPreview takes a preview of the queries of the alert rule. It returns nil if none of the queries
+// can be previewed.
+func takeQueryPreview(ctx context.Context, s QueryPreviewCapturer, r *models.AlertRule, at time.Time) (*models.Image, error) {
+	img, err := s.NewQueryPreview(ctx, r, at)
+	if err != nil {
+		if errors.Is(err, models.ErrNoDataSourceQuery) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return img, nil
+}
+
 func FormatStateAndReason(state eval.State, reason string) string {
 	s := fmt.Sprintf("%v", state)
 	if len(reason) > 0 {
@@ -6,8 +6,10 @@ import (
 	"net/url"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 )
 
 type query struct {
@@ -23,6 +25,10 @@ const (
 	RemoveLabelsReFuncName   = "removeLabelsRe"
 	TableLinkFuncName        = "tableLink"
 	MergeLabelValuesFuncName = "mergeLabelValues"
+	DateFuncName             = "date"
+	NowFuncName              = "now"
+	ToDurationFuncName       = "toDuration"
+	TzFuncName               = "tz"
 )
 
 var (
@@ -34,6 +40,9 @@ var (
 		RemoveLabelsReFuncName:   removeLabelsReFunc,
 		TableLinkFuncName:        tableLinkFunc,
 		MergeLabelValuesFuncName: mergeLabelValuesFunc,
+		DateFuncName:             dateFunc,
+		ToDurationFuncName:       toDurationFunc,
+		TzFuncName:               tzFunc,
 	}
 )
 
@@ -131,3 +140,54 @@ func mergeLabelValuesFunc(values map[string]Value) Labels {
 	}
 	return res
 }
+
+// dateFunc formats the time with the layout, as in Go.
+func dateFunc(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+// nowFunc returns a function that returns the time of the evaluation in seconds since the Unix epoch.
+func nowFunc(evaluatedAt time.Time) func() float64 {
+	return func() float64 {
+		return float64(evaluatedAt.UnixNano()) / float64(time.Second)
+	}
+}
+
+// toDurationFunc converts a number of seconds, or a string containing a number of seconds, to a duration.
+func toDurationFunc(i any) (*time.Duration, error) {
+	v, err := toFloat64(i)
+	if err != nil {
+		return nil, err
+	}
+	d := time.Duration(v * float64(time.Second))
+	return &d, nil
+}
+
+// tzFunc converts the time to the location, such as "Europe/Paris".
+func tzFunc(name string, t time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(loc), nil
+}
+
+// toFloat64 converts the value to a number in the same way as the functions of Prometheus, such as humanize.
+func toFloat64(i any) (float64, error) {
+	switch v := i.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case int:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("can't convert %T to float", v)
+	}
+}
@@ -2,8 +2,10 @@ package template
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFilterLabelsFunc(t *testing.T) {
@@ -49,3 +51,40 @@ func TestDeduplicateLabelsFuncNoDuplicates(t *testing.T) {
 	}
 	assert.Equal(t, Labels{"foo": "bar", "bar": "baz"}, mergeLabelValuesFunc(v))
 }
+
+func TestDateFunc(t *testing.T) {
+	assert.Equal(t, "2015-06-23 13:19", dateFunc("2006-01-02 15:04", time.Unix(1435065584, 0).UTC()))
+}
+
+func TestToDurationFunc(t *testing.T) {
+	d, err := toDurationFunc(90.5)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second+500*time.Millisecond, *d)
+
+	d, err = toDurationFunc("60")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, *d)
+
+	_, err = toDurationFunc("invalid")
+	require.Error(t, err)
+}
+
+func TestTzFunc(t *testing.T) {
+	v, err := tzFunc("Asia/Tokyo", time.Unix(1435065584, 0).UTC())
+	require.NoError(t, err)
+	assert.Equal(t, "2015-06-23 22:19:44 +0900 JST", v.String())
+
+	_, err = tzFunc("invalid", time.Now())
+	require.Error(t, err)
+}
+
+func TestFunctions(t *testing.T) {
+	names := make(map[string]struct{})
+	for _, f := range Functions() {
+		names[f.Name] = struct{}{}
+	}
+	for name := range defaultFuncs {
+		assert.Contains(t, names, name, "function %s is not documented", name)
+	}
+	assert.Contains(t, names, NowFuncName)
+}
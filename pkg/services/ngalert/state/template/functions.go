@@ -0,0 +1,54 @@
+package template
+
+import "sort"
+
+// Function describes a function that can be used in the templates of annotations and labels.
+type Function struct {
+	Name        string
+	Signature   string
+	Description string
+}
+
+// functions contains the functions inherited from Prometheus and the functions in defaultFuncs.
+// Functions that need a query, such as query, first and sortByLabel, are not listed as they are not supported.
+var functions = []Function{
+	{Name: "args", Signature: "args(values ...any) map[string]any", Description: "Converts a list of values to a map with the keys arg0, arg1, and so on, to pass several values to a template."},
+	{Name: DateFuncName, Signature: "date(layout string, t time.Time) string", Description: "Formats the time with a Go layout, such as \"2006-01-02 15:04:05\"."},
+	{Name: "externalURL", Signature: "externalURL() string", Description: "Returns the URL of the Grafana server."},
+	{Name: FilterLabelFuncName, Signature: "filterLabels(labels Labels, name string) Labels", Description: "Returns the labels with the name."},
+	{Name: FilterLabelReFuncName, Signature: "filterLabelsRe(labels Labels, pattern string) Labels", Description: "Returns the labels with a name that matches the regular expression."},
+	{Name: GraphLinkFuncName, Signature: "graphLink(query string) string", Description: "Returns a link to the graphical view of a query in Explore, from a query in JSON such as {\"expr\": \"up\", \"datasource\": \"prometheus\"}."},
+	{Name: "humanize", Signature: "humanize(value number|string) string", Description: "Formats a number with metric prefixes, such as 1.2k or 3.4M."},
+	{Name: "humanize1024", Signature: "humanize1024(value number|string) string", Description: "Formats a number with binary prefixes, such as 1.2ki or 3.4Mi."},
+	{Name: "humanizeDuration", Signature: "humanizeDuration(seconds number|string) string", Description: "Formats a number of seconds as a duration, such as 1h 2m 3s."},
+	{Name: "humanizePercentage", Signature: "humanizePercentage(ratio number|string) string", Description: "Formats a ratio as a percentage, such as 0.1234 as 12.34%."},
+	{Name: "humanizeTimestamp", Signature: "humanizeTimestamp(seconds number|string) string", Description: "Formats a Unix timestamp in seconds as a time in UTC."},
+	{Name: "match", Signature: "match(pattern string, text string) bool", Description: "Returns true if the text matches the regular expression."},
+	{Name: MergeLabelValuesFuncName, Signature: "mergeLabelValues(values map[string]Value) Labels", Description: "Returns the labels of all values, with the distinct values of each label joined with commas."},
+	{Name: NowFuncName, Signature: "now() float64", Description: "Returns the time of the evaluation as a Unix timestamp in seconds."},
+	{Name: "parseDuration", Signature: "parseDuration(duration string) float64", Description: "Returns the number of seconds of a duration, such as 1h30m."},
+	{Name: "pathPrefix", Signature: "pathPrefix() string", Description: "Returns the path of the URL of the Grafana server."},
+	{Name: "reReplaceAll", Signature: "reReplaceAll(pattern string, replacement string, text string) string", Description: "Replaces the matches of the regular expression in the text. The replacement can refer to capture groups, such as $1."},
+	{Name: RemoveLabelsFuncName, Signature: "removeLabels(labels Labels, name string) Labels", Description: "Returns the labels without the name."},
+	{Name: RemoveLabelsReFuncName, Signature: "removeLabelsRe(labels Labels, pattern string) Labels", Description: "Returns the labels without the names that match the regular expression."},
+	{Name: "safeHtml", Signature: "safeHtml(text string) string", Description: "Marks the text as HTML that must not be escaped."},
+	{Name: "stripDomain", Signature: "stripDomain(host string) string", Description: "Removes the domain and the port from a host name, such as example.com:8080 as example."},
+	{Name: "stripPort", Signature: "stripPort(host string) string", Description: "Removes the port from a host, such as example.com:8080 as example.com."},
+	{Name: TableLinkFuncName, Signature: "tableLink(query string) string", Description: "Returns a link to the tabular view of a query in Explore, from a query in JSON such as {\"expr\": \"up\", \"datasource\": \"prometheus\"}."},
+	{Name: "title", Signature: "title(text string) string", Description: "Capitalizes the first letter of each word."},
+	{Name: ToDurationFuncName, Signature: "toDuration(seconds number|string) time.Duration", Description: "Converts a number of seconds to a duration, which can be formatted or compared."},
+	{Name: "toLower", Signature: "toLower(text string) string", Description: "Converts the text to lower case."},
+	{Name: "toTime", Signature: "toTime(seconds number|string) time.Time", Description: "Converts a Unix timestamp in seconds to a time, which can be formatted with date."},
+	{Name: "toUpper", Signature: "toUpper(text string) string", Description: "Converts the text to upper case."},
+	{Name: TzFuncName, Signature: "tz(location string, t time.Time) time.Time", Description: "Converts the time to a location of the IANA time zone database, such as Europe/Paris."},
+}
+
+// Functions returns the functions that can be used in the templates of annotations and labels, sorted by name.
+func Functions() []Function {
+	result := make([]Function, len(functions))
+	copy(result, functions)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
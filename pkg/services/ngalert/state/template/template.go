@@ -114,6 +114,7 @@ func Expand(ctx context.Context, name, tmpl string, data Data, externalURL *url.
 
 	expander := template.NewTemplateExpander(ctx, tmpl, name, data, tm, queryFunc, externalURL, options)
 	expander.Funcs(defaultFuncs)
+	expander.Funcs(map[string]any{NowFuncName: nowFunc(evaluatedAt)})
 
 	result, err := expander.Expand()
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
@@ -347,6 +348,25 @@ func TestExpandTemplate(t *testing.T) {
 		name:     "humanizeTimestamp - string",
 		text:     `{{ "1435065584.128" | humanizeTimestamp }}`,
 		expected: "2015-06-23 13:19:44.128 +0000 UTC",
+	}, {
+		name:     "toTime and date",
+		text:     `{{ 1435065584 | toTime | date "2006-01-02 15:04:05" }}`,
+		expected: "2015-06-23 13:19:44",
+	}, {
+		name:     "toTime, tz and date",
+		text:     `{{ 1435065584 | toTime | tz "Asia/Tokyo" | date "15:04 MST" }}`,
+		expected: "22:19 JST",
+	}, {
+		name:     "toDuration",
+		text:     `{{ 5400 | toDuration }}`,
+		expected: "1h30m0s",
+	}, {
+		name: "now",
+		text: `{{ now | humanizeTimestamp }}`,
+		alertInstance: eval.Result{
+			EvaluatedAt: time.Unix(1435065584, 0),
+		},
+		expected: "2015-06-23 13:19:44 +0000 UTC",
 	}, {
 		name:     "title",
 		text:     `{{ "aa bb CC" | title }}`,
@@ -0,0 +1,57 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type fakeUsageRecorder struct {
+	fired []time.Time
+}
+
+func (f *fakeUsageRecorder) RuleFired(_ int64, _ string, at time.Time) {
+	f.fired = append(f.fired, at)
+}
+
+func TestProcessEvalResults_RecordsFiredRules(t *testing.T) {
+	usage := &fakeUsageRecorder{}
+	cfg := state.ManagerCfg{
+		Metrics:   metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		Images:    &state.NoopImageService{},
+		Clock:     clock.NewMock(),
+		Historian: &state.FakeHistorian{},
+		Usage:     usage,
+		Tracer:    tracing.InitializeTracerForTest(),
+		Log:       log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+	rule := models.AlertRuleGen(models.WithFor(0))()
+	evaluate := func(at time.Time, s eval.State) {
+		st.ProcessEvalResults(context.Background(), at, rule, eval.Results{
+			{Instance: data.Labels{"series": "a"}, State: s, EvaluatedAt: at},
+			{Instance: data.Labels{"series": "b"}, State: s, EvaluatedAt: at},
+		}, nil)
+	}
+
+	start := time.Unix(0, 0)
+	evaluate(start, eval.Normal)
+	evaluate(start.Add(time.Minute), eval.Alerting)
+	evaluate(start.Add(2*time.Minute), eval.Alerting)
+	evaluate(start.Add(3*time.Minute), eval.Normal)
+
+	// the rule is recorded once per evaluation with firing alerts
+	require.Equal(t, []time.Time{start.Add(time.Minute), start.Add(2 * time.Minute)}, usage.fired)
+}
@@ -2,9 +2,11 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -13,6 +15,34 @@ var (
 	ErrNoAdminConfiguration = fmt.Errorf("no admin configuration available")
 )
 
+// AdminConfigurationGetter reads the admin configuration of an organization.
+type AdminConfigurationGetter interface {
+	GetAdminConfiguration(orgID int64) (*ngmodels.AdminConfiguration, error)
+}
+
+// FindAdminConfiguration returns the admin configuration of the organization, or nil if the store is not set or the
+// organization has none.
+func FindAdminConfiguration(st AdminConfigurationGetter, orgID int64) (*ngmodels.AdminConfiguration, error) {
+	if st == nil {
+		return nil, nil
+	}
+	cfg, err := st.GetAdminConfiguration(orgID)
+	if errors.Is(err, ErrNoAdminConfiguration) {
+		return nil, nil
+	}
+	return cfg, err
+}
+
+// InheritedIntervalSeconds returns the evaluation interval of rule groups of the organization that inherit its
+// interval, falling back to the default evaluation interval if the admin configuration cannot be read.
+func InheritedIntervalSeconds(logger log.Logger, st AdminConfigurationGetter, orgID int64, defaultIntervalSeconds int64) int64 {
+	cfg, err := FindAdminConfiguration(st, orgID)
+	if err != nil {
+		logger.Warn("Failed to get the admin configuration, using the default evaluation interval", "org", orgID, "error", err)
+	}
+	return cfg.EffectiveEvaluationIntervalSeconds(defaultIntervalSeconds)
+}
+
 type UpdateAdminConfigurationCmd struct {
 	AdminConfiguration *ngmodels.AdminConfiguration
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/google/uuid"
@@ -13,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -140,24 +142,7 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				return err
 			}
 			newRules = append(newRules, r)
-			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleUID:          r.UID,
-				RuleOrgID:        r.OrgID,
-				RuleNamespaceUID: r.NamespaceUID,
-				RuleGroup:        r.RuleGroup,
-				ParentVersion:    0,
-				Version:          r.Version,
-				Created:          r.Updated,
-				Condition:        r.Condition,
-				Title:            r.Title,
-				Data:             r.Data,
-				IntervalSeconds:  r.IntervalSeconds,
-				NoDataState:      r.NoDataState,
-				ExecErrState:     r.ExecErrState,
-				For:              r.For,
-				Annotations:      r.Annotations,
-				Labels:           r.Labels,
-			})
+			ruleVersions = append(ruleVersions, newAlertRuleVersion(r, 0, r.Version))
 		}
 		if len(newRules) > 0 {
 			// we have to insert the rules one by one as otherwise we are
@@ -195,7 +180,6 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 
 		ruleVersions := make([]ngmodels.AlertRuleVersion, 0, len(rules))
 		for _, r := range rules {
-			var parentVersion int64
 			r.New.ID = r.Existing.ID
 			r.New.Version = r.Existing.Version // xorm will take care of increasing it (see https://xorm.io/docs/chapter-06/1.lock/)
 			if err := st.validateAlertRule(r.New); err != nil {
@@ -214,26 +198,7 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				}
 				return fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
 			}
-			parentVersion = r.Existing.Version
-			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleOrgID:        r.New.OrgID,
-				RuleUID:          r.New.UID,
-				RuleNamespaceUID: r.New.NamespaceUID,
-				RuleGroup:        r.New.RuleGroup,
-				RuleGroupIndex:   r.New.RuleGroupIndex,
-				ParentVersion:    parentVersion,
-				Version:          r.New.Version + 1,
-				Created:          r.New.Updated,
-				Condition:        r.New.Condition,
-				Title:            r.New.Title,
-				Data:             r.New.Data,
-				IntervalSeconds:  r.New.IntervalSeconds,
-				NoDataState:      r.New.NoDataState,
-				ExecErrState:     r.New.ExecErrState,
-				For:              r.New.For,
-				Annotations:      r.New.Annotations,
-				Labels:           r.New.Labels,
-			})
+			ruleVersions = append(ruleVersions, newAlertRuleVersion(r.New, r.Existing.Version, r.New.Version+1))
 		}
 		if len(ruleVersions) > 0 {
 			if _, err := sess.Insert(&ruleVersions); err != nil {
@@ -335,6 +300,115 @@ func (st DBstore) CountInFolders(ctx context.Context, orgID int64, folderUIDs []
 	return count, err
 }
 
+// ListInFolders returns a page of the alert rules in the given folders, ordered by folder and title. Like the ruler
+// API, it only returns the rules of the rule groups whose queries the user can run.
+func (st DBstore) ListInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester, limit, page int64) ([]folder.RegistryItem, error) {
+	result := make([]folder.RegistryItem, 0)
+	if len(folderUIDs) == 0 {
+		return result, nil
+	}
+	rules, err := st.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: folderUIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+	for _, rule := range rules {
+		key := rule.GetGroupKey()
+		groups[key] = append(groups[key], rule)
+	}
+	for _, group := range groups {
+		ok, err := st.canQueryRuleGroup(ctx, user, group)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		for _, rule := range group {
+			result = append(result, folder.RegistryItem{UID: rule.UID, Title: rule.Title, FolderUID: rule.NamespaceUID})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FolderUID != result[j].FolderUID {
+			return result[i].FolderUID < result[j].FolderUID
+		}
+		if result[i].Title != result[j].Title {
+			return result[i].Title < result[j].Title
+		}
+		return result[i].UID < result[j].UID
+	})
+
+	if limit <= 0 {
+		return result, nil
+	}
+	offset := int64(0)
+	if page > 0 {
+		offset = limit * (page - 1)
+	}
+	if offset >= int64(len(result)) {
+		return []folder.RegistryItem{}, nil
+	}
+	return result[offset:min(offset+limit, int64(len(result)))], nil
+}
+
+// canQueryRuleGroup returns true if the user can query all the data sources that the rules of the group use, which is
+// required to read the group in the ruler API.
+func (st DBstore) canQueryRuleGroup(ctx context.Context, user identity.Requester, group ngmodels.RulesGroup) (bool, error) {
+	evals := make([]accesscontrol.Evaluator, 0, 1)
+	added := make(map[string]struct{})
+	for _, rule := range group {
+		for _, uid := range rule.GetDatasourceUIDs() {
+			if _, ok := added[uid]; ok {
+				continue
+			}
+			added[uid] = struct{}{}
+			evals = append(evals, accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid)))
+		}
+	}
+	if len(evals) == 0 {
+		return true, nil
+	}
+	return st.AccessControl.Evaluate(ctx, user, accesscontrol.EvalAll(evals...))
+}
+
+// ListGroupsInFolders returns the rule groups in the given folders with the number of their rules, ordered by folder
+// and group name.
+func (st DBstore) ListGroupsInFolders(ctx context.Context, orgID int64, folderUIDs []string, _ identity.Requester) ([]folder.RegistryGroup, error) {
+	result := make([]folder.RegistryGroup, 0)
+	if len(folderUIDs) == 0 {
+		return result, nil
+	}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		args := make([]any, 0, len(folderUIDs))
+		for _, folderUID := range folderUIDs {
+			args = append(args, folderUID)
+		}
+		var groups []struct {
+			NamespaceUID string `xorm:"namespace_uid"`
+			RuleGroup    string `xorm:"rule_group"`
+			Count        int64  `xorm:"count"`
+		}
+		err := sess.Table("alert_rule").Select("namespace_uid, rule_group, COUNT(*) AS count").
+			Where("org_id = ?", orgID).
+			Where(fmt.Sprintf("namespace_uid IN (%s)", strings.Repeat("?,", len(folderUIDs)-1)+"?"), args...).
+			GroupBy("namespace_uid, rule_group").
+			OrderBy("namespace_uid, rule_group").
+			Find(&groups)
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			result = append(result, folder.RegistryGroup{FolderUID: g.NamespaceUID, Name: g.RuleGroup, Count: g.Count})
+		}
+		return nil
+	})
+	return result, err
+}
+
 // ListAlertRules is a handler for retrieving alert rules of specific organisation.
 func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (result ngmodels.RulesGroup, err error) {
 	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -417,6 +491,28 @@ func (st DBstore) Count(ctx context.Context, orgID int64) (int64, error) {
 	return r.Count, err
 }
 
+// CountByFolder returns the number of alert rules of the organization grouped by the namespace (folder) UID.
+func (st DBstore) CountByFolder(ctx context.Context, orgID int64) (map[string]int64, error) {
+	type result struct {
+		NamespaceUID string `xorm:"namespace_uid"`
+		Count        int64
+	}
+
+	rows := make([]result, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.SQL("SELECT namespace_uid, COUNT(*) as count FROM alert_rule WHERE org_id=? GROUP BY namespace_uid", orgID).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		counts[r.NamespaceUID] = r.Count
+	}
+	return counts, nil
+}
+
 func (st DBstore) GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error) {
 	var interval int64 = 0
 	return interval, st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -648,3 +744,32 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 
 	return nil
 }
+
+// newAlertRuleVersion returns the version record of the given alert rule.
+func newAlertRuleVersion(rule ngmodels.AlertRule, parentVersion, version int64) ngmodels.AlertRuleVersion {
+	return ngmodels.AlertRuleVersion{
+		RuleOrgID:                      rule.OrgID,
+		RuleUID:                        rule.UID,
+		RuleNamespaceUID:               rule.NamespaceUID,
+		RuleGroup:                      rule.RuleGroup,
+		RuleGroupIndex:                 rule.RuleGroupIndex,
+		ParentVersion:                  parentVersion,
+		Version:                        version,
+		Created:                        rule.Updated,
+		Condition:                      rule.Condition,
+		Title:                          rule.Title,
+		Data:                           rule.Data,
+		IntervalSeconds:                rule.IntervalSeconds,
+		NoDataState:                    rule.NoDataState,
+		ExecErrState:                   rule.ExecErrState,
+		For:                            rule.For,
+		Annotations:                    rule.Annotations,
+		Labels:                         rule.Labels,
+		FingerprintLabels:              rule.FingerprintLabels,
+		PartialResults:                 rule.PartialResults,
+		GroupLabels:                    rule.GroupLabels,
+		UpdatedBy:                      rule.UpdatedBy,
+		MissingSeriesEvalsToResolve:    rule.MissingSeriesEvalsToResolve,
+		MissingSeriesDurationToResolve: rule.MissingSeriesDurationToResolve,
+	}
+}
@@ -0,0 +1,294 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// bulkWriteBatchSize is the number of rows that are inserted by a single statement of a bulk write.
+// Rules have large columns, so it is kept well below the placeholder and packet size limits of the databases.
+const bulkWriteBatchSize = 100
+
+// bulkWriteLookupSize is the number of values of a single IN clause when the existing rules are looked up.
+const bulkWriteLookupSize = 500
+
+// bulkWriteState tracks the UIDs and titles of the rules of an organization during a bulk write,
+// so that conflicts are detected before the rules are written instead of failing the transaction.
+// Only the rules of the folders and UIDs that are written are tracked.
+type bulkWriteState struct {
+	uids map[string]struct{}
+	// titles maps the folder and title of rules to their UIDs.
+	titles map[string]string
+	// caseInsensitiveTitles is true if the database compares titles without case, as MySQL does with its default collation.
+	caseInsensitiveTitles bool
+}
+
+func (s *bulkWriteState) titleKey(namespaceUID, title string) string {
+	if s.caseInsensitiveTitles {
+		title = strings.ToLower(title)
+	}
+	return namespaceUID + "/" + title
+}
+
+// BulkWriteAlertRules creates and updates alert rules in a single transaction.
+// Unlike InsertAlertRules and UpdateAlertRules, the rules are validated and checked for conflicts before anything is
+// written, and the rules that fail are skipped and reported in the result, unless the command is atomic.
+// New rules are inserted in batches and the versions of all rules are inserted in batches after all rules are written.
+func (st DBstore) BulkWriteAlertRules(ctx context.Context, cmd ngmodels.BulkWriteRulesCommand) (*ngmodels.BulkWriteRulesResult, error) {
+	result := &ngmodels.BulkWriteRulesResult{
+		Inserted: make([]ngmodels.AlertRuleKeyWithId, 0, len(cmd.Insert)),
+		Updated:  make([]ngmodels.AlertRuleKey, 0, len(cmd.Update)),
+	}
+	if len(cmd.Insert) == 0 && len(cmd.Update) == 0 {
+		return result, nil
+	}
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		orgs, err := st.loadBulkWriteState(sess, cmd)
+		if err != nil {
+			return err
+		}
+
+		reject := func(rule ngmodels.AlertRule, err error) {
+			result.Errors = append(result.Errors, ngmodels.BulkWriteRuleError{UID: rule.UID, Title: rule.Title, Err: err})
+		}
+
+		// the titles of the updated rules are released first, so that rules of the write can swap titles
+		for _, r := range cmd.Update {
+			if r.Existing == nil {
+				continue
+			}
+			org := orgs[r.New.OrgID]
+			if oldTitle := org.titleKey(r.Existing.NamespaceUID, r.Existing.Title); org.titles[oldTitle] == r.New.UID {
+				delete(org.titles, oldTitle)
+			}
+		}
+
+		updates := make([]ngmodels.UpdateRule, 0, len(cmd.Update))
+		for _, r := range cmd.Update {
+			if r.Existing == nil {
+				reject(r.New, ngmodels.ErrAlertRuleNotFound)
+				continue
+			}
+			// a rule that is not updated keeps its title
+			rejectUpdate := func(err error) {
+				reject(r.New, err)
+				if oldTitle := orgs[r.New.OrgID].titleKey(r.Existing.NamespaceUID, r.Existing.Title); orgs[r.New.OrgID].titles[oldTitle] == "" {
+					orgs[r.New.OrgID].titles[oldTitle] = r.New.UID
+				}
+			}
+			r.New.ID = r.Existing.ID
+			r.New.Version = r.Existing.Version // xorm will take care of increasing it (see https://xorm.io/docs/chapter-06/1.lock/)
+			if err := st.validateAlertRule(r.New); err != nil {
+				rejectUpdate(err)
+				continue
+			}
+			if err := (&r.New).PreSave(TimeNow); err != nil {
+				rejectUpdate(err)
+				continue
+			}
+			org := orgs[r.New.OrgID]
+			newTitle := org.titleKey(r.New.NamespaceUID, r.New.Title)
+			if uid, ok := org.titles[newTitle]; ok && uid != r.New.UID {
+				rejectUpdate(ngmodels.ErrAlertRuleConflict(r.New, ngmodels.ErrAlertRuleUniqueConstraintViolation))
+				continue
+			}
+			org.titles[newTitle] = r.New.UID
+			updates = append(updates, r)
+		}
+
+		inserts := make([]ngmodels.AlertRule, 0, len(cmd.Insert))
+		for _, r := range cmd.Insert {
+			org := orgs[r.OrgID]
+			if r.UID == "" {
+				r.UID = util.GenerateShortUID()
+				for _, ok := org.uids[r.UID]; ok; _, ok = org.uids[r.UID] {
+					r.UID = util.GenerateShortUID()
+				}
+			} else if _, ok := org.uids[r.UID]; ok {
+				reject(r, ngmodels.ErrAlertRuleConflict(r, errors.New("rule UID under the same organisation should be unique")))
+				continue
+			}
+			r.Version = 1
+			if err := st.validateAlertRule(r); err != nil {
+				reject(r, err)
+				continue
+			}
+			if err := (&r).PreSave(TimeNow); err != nil {
+				reject(r, err)
+				continue
+			}
+			title := org.titleKey(r.NamespaceUID, r.Title)
+			if _, ok := org.titles[title]; ok {
+				reject(r, ngmodels.ErrAlertRuleConflict(r, ngmodels.ErrAlertRuleUniqueConstraintViolation))
+				continue
+			}
+			org.uids[r.UID] = struct{}{}
+			org.titles[title] = r.UID
+			inserts = append(inserts, r)
+		}
+
+		if cmd.Atomic && len(result.Errors) > 0 {
+			return result.Err()
+		}
+
+		ruleVersions := make([]ngmodels.AlertRuleVersion, 0, len(updates)+len(inserts))
+		if len(updates) > 0 {
+			if err := st.preventIntermediateUniqueConstraintViolations(sess, updates); err != nil {
+				return fmt.Errorf("failed when preventing intermediate unique constraint violation: %w", err)
+			}
+		}
+		for _, r := range updates {
+			updated, err := sess.ID(r.Existing.ID).AllCols().Update(r.New)
+			if err != nil {
+				if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+					return ngmodels.ErrAlertRuleConflict(r.New, ngmodels.ErrAlertRuleUniqueConstraintViolation)
+				}
+				return fmt.Errorf("failed to update rule [%s] %s: %w", r.New.UID, r.New.Title, err)
+			}
+			if updated == 0 {
+				err := fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
+				if cmd.Atomic {
+					return err
+				}
+				reject(r.New, err)
+				continue
+			}
+			result.Updated = append(result.Updated, r.New.GetKey())
+			ruleVersions = append(ruleVersions, newAlertRuleVersion(r.New, r.Existing.Version, r.New.Version+1))
+		}
+
+		ids, err := st.insertAlertRulesInBatches(sess, inserts)
+		if err != nil {
+			return err
+		}
+		for _, r := range inserts {
+			key := r.GetKey()
+			result.Inserted = append(result.Inserted, ngmodels.AlertRuleKeyWithId{AlertRuleKey: key, ID: ids[key]})
+			ruleVersions = append(ruleVersions, newAlertRuleVersion(r, 0, r.Version))
+		}
+
+		for start := 0; start < len(ruleVersions); start += bulkWriteBatchSize {
+			batch := ruleVersions[start:min(start+bulkWriteBatchSize, len(ruleVersions))]
+			if _, err := sess.Insert(&batch); err != nil {
+				return fmt.Errorf("failed to create new rule versions: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Errors) > 0 {
+		st.Logger.Warn("Some alert rules were not written", "inserted", len(result.Inserted), "updated", len(result.Updated), "failed", len(result.Errors))
+	}
+	return result, nil
+}
+
+// loadBulkWriteState fetches the UIDs and titles of the rules that can conflict with the rules of the command,
+// that is the rules of the folders the rules are written to, and the rules that have the UIDs of the command.
+// Generated UIDs are only checked against these rules, the unique index of the table rejects the unlikely others.
+func (st DBstore) loadBulkWriteState(sess *db.Session, cmd ngmodels.BulkWriteRulesCommand) (map[int64]*bulkWriteState, error) {
+	type orgLookup struct {
+		namespaces map[string]struct{}
+		uids       map[string]struct{}
+	}
+	lookups := make(map[int64]*orgLookup)
+	lookup := func(orgID int64) *orgLookup {
+		l, ok := lookups[orgID]
+		if !ok {
+			l = &orgLookup{namespaces: map[string]struct{}{}, uids: map[string]struct{}{}}
+			lookups[orgID] = l
+		}
+		return l
+	}
+	for _, r := range cmd.Insert {
+		l := lookup(r.OrgID)
+		l.namespaces[r.NamespaceUID] = struct{}{}
+		if r.UID != "" {
+			l.uids[r.UID] = struct{}{}
+		}
+	}
+	for _, r := range cmd.Update {
+		l := lookup(r.New.OrgID)
+		l.namespaces[r.New.NamespaceUID] = struct{}{}
+		if r.Existing != nil {
+			l.namespaces[r.Existing.NamespaceUID] = struct{}{}
+		}
+	}
+
+	caseInsensitive := st.SQLStore.GetDialect().DriverName() == migrator.MySQL
+	orgs := make(map[int64]*bulkWriteState, len(lookups))
+	for orgID, l := range lookups {
+		state := &bulkWriteState{
+			uids:                  make(map[string]struct{}),
+			titles:                make(map[string]string),
+			caseInsensitiveTitles: caseInsensitive,
+		}
+		add := func(column string, values map[string]struct{}) error {
+			keys := make([]string, 0, len(values))
+			for v := range values {
+				keys = append(keys, v)
+			}
+			for start := 0; start < len(keys); start += bulkWriteLookupSize {
+				batch := keys[start:min(start+bulkWriteLookupSize, len(keys))]
+				var rules []ngmodels.AlertRule
+				if err := sess.Table(ngmodels.AlertRule{}).Cols("uid", "namespace_uid", "title").Where("org_id = ?", orgID).In(column, batch).Find(&rules); err != nil {
+					return fmt.Errorf("failed to fetch the alert rules of organization %d: %w", orgID, err)
+				}
+				for _, r := range rules {
+					state.uids[r.UID] = struct{}{}
+					state.titles[state.titleKey(r.NamespaceUID, r.Title)] = r.UID
+				}
+			}
+			return nil
+		}
+		if err := add("namespace_uid", l.namespaces); err != nil {
+			return nil, err
+		}
+		if err := add("uid", l.uids); err != nil {
+			return nil, err
+		}
+		orgs[orgID] = state
+	}
+	return orgs, nil
+}
+
+// insertAlertRulesInBatches inserts the rules with multi-row statements and returns the IDs of the created rules.
+// The IDs are fetched afterwards as xorm does not set them when several rows are inserted at once.
+func (st DBstore) insertAlertRulesInBatches(sess *db.Session, rules []ngmodels.AlertRule) (map[ngmodels.AlertRuleKey]int64, error) {
+	ids := make(map[ngmodels.AlertRuleKey]int64, len(rules))
+	byOrg := make(map[int64][]ngmodels.AlertRule)
+	for _, r := range rules {
+		byOrg[r.OrgID] = append(byOrg[r.OrgID], r)
+	}
+	for orgID, orgRules := range byOrg {
+		for start := 0; start < len(orgRules); start += bulkWriteBatchSize {
+			batch := orgRules[start:min(start+bulkWriteBatchSize, len(orgRules))]
+			if _, err := sess.Insert(&batch); err != nil {
+				if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+					return nil, fmt.Errorf("failed to create new rules: %w", ngmodels.ErrAlertRuleUniqueConstraintViolation)
+				}
+				return nil, fmt.Errorf("failed to create new rules: %w", err)
+			}
+			uids := make([]string, 0, len(batch))
+			for _, r := range batch {
+				uids = append(uids, r.UID)
+			}
+			var inserted []ngmodels.AlertRule
+			if err := sess.Table(ngmodels.AlertRule{}).Cols("id", "org_id", "uid").Where("org_id = ?", orgID).In("uid", uids).Find(&inserted); err != nil {
+				return nil, fmt.Errorf("failed to fetch the IDs of new rules: %w", err)
+			}
+			for _, r := range inserted {
+				ids[r.GetKey()] = r.ID
+			}
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationBulkWriteAlertRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+	gen := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))
+	generate := func(count int) []models.AlertRule {
+		rules := make([]models.AlertRule, 0, count)
+		for _, rule := range models.GenerateAlertRules(count, gen) {
+			rules = append(rules, *rule)
+		}
+		return rules
+	}
+	countVersions := func(t *testing.T) int64 {
+		var count int64
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			count, err = sess.Table(models.AlertRuleVersion{}).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("should insert rules in batches", func(t *testing.T) {
+		rules := generate(2*bulkWriteBatchSize + 1)
+		rules[0].UID = ""
+
+		result, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{Insert: rules})
+		require.NoError(t, err)
+		require.Empty(t, result.Errors)
+		require.Len(t, result.Inserted, len(rules))
+		require.NotEmpty(t, result.Inserted[0].UID)
+
+		for idx, key := range result.Inserted {
+			rule, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: key.OrgID, UID: key.UID})
+			require.NoError(t, err)
+			require.Equal(t, key.ID, rule.ID)
+			require.Equal(t, rules[idx].Title, rule.Title)
+			require.EqualValues(t, 1, rule.Version)
+		}
+		require.EqualValues(t, len(rules), countVersions(t))
+	})
+
+	t.Run("should skip rules that cannot be written", func(t *testing.T) {
+		existing := generate(2)
+		_, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{Insert: existing, Atomic: true})
+		require.NoError(t, err)
+		versions := countVersions(t)
+
+		stored, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: existing[0].UID})
+		require.NoError(t, err)
+		updated := models.CopyRule(stored)
+		updated.Title = "updated-" + stored.Title
+
+		rules := generate(3)
+		rules[0].UID = existing[1].UID // duplicate UID
+		rules[1].Title = ""            // invalid rule
+		result, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{
+			Insert: rules,
+			Update: []models.UpdateRule{{Existing: stored, New: *updated}},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Errors, 2)
+		require.Equal(t, rules[0].UID, result.Errors[0].UID)
+		require.ErrorIs(t, result.Errors[1], models.ErrAlertRuleFailedValidation)
+		require.Len(t, result.Inserted, 1)
+		require.Equal(t, rules[2].UID, result.Inserted[0].UID)
+		require.Equal(t, []models.AlertRuleKey{stored.GetKey()}, result.Updated)
+		require.Equal(t, versions+2, countVersions(t))
+
+		actual, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: stored.UID})
+		require.NoError(t, err)
+		require.Equal(t, updated.Title, actual.Title)
+		require.Equal(t, stored.Version+1, actual.Version)
+	})
+
+	t.Run("should detect conflicting titles within the write", func(t *testing.T) {
+		rules := generate(2)
+		rules[1].NamespaceUID = rules[0].NamespaceUID
+		rules[1].Title = rules[0].Title
+
+		result, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{Insert: rules})
+		require.NoError(t, err)
+		require.Len(t, result.Inserted, 1)
+		require.Len(t, result.Errors, 1)
+		require.ErrorIs(t, result.Errors[0], models.ErrAlertRuleUniqueConstraintViolation)
+	})
+
+	t.Run("should detect titles that conflict with existing rules of the folder", func(t *testing.T) {
+		existing := generate(1)
+		_, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{Insert: existing, Atomic: true})
+		require.NoError(t, err)
+
+		rules := generate(1)
+		rules[0].NamespaceUID = existing[0].NamespaceUID
+		rules[0].Title = existing[0].Title
+		result, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{Insert: rules})
+		require.NoError(t, err)
+		require.Empty(t, result.Inserted)
+		require.Len(t, result.Errors, 1)
+		require.ErrorIs(t, result.Errors[0], models.ErrAlertRuleUniqueConstraintViolation)
+	})
+
+	t.Run("should compare titles as the database does", func(t *testing.T) {
+		rules := generate(2)
+		rules[0].Title = "disk usage"
+		rules[1].NamespaceUID = rules[0].NamespaceUID
+		rules[1].Title = "Disk Usage"
+
+		result, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{Insert: rules})
+		require.NoError(t, err)
+		if store.SQLStore.GetDialect().DriverName() == migrator.MySQL {
+			require.Len(t, result.Inserted, 1)
+			require.Len(t, result.Errors, 1)
+			return
+		}
+		require.Len(t, result.Inserted, 2)
+		require.Empty(t, result.Errors)
+	})
+
+	t.Run("should not write anything if atomic and a rule cannot be written", func(t *testing.T) {
+		versions := countVersions(t)
+		rules := generate(3)
+		rules[2].Title = ""
+
+		_, err := store.BulkWriteAlertRules(context.Background(), models.BulkWriteRulesCommand{Insert: rules, Atomic: true})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		for _, rule := range rules[:2] {
+			_, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: rule.UID})
+			require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+		}
+		require.Equal(t, versions, countVersions(t))
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
@@ -511,6 +512,75 @@ func TestIntegration_CountAlertRules(t *testing.T) {
 	}
 }
 
+func TestIntegration_ListGroupsInFolders(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	store := &DBstore{SQLStore: sqlStore, Cfg: cfg.UnifiedAlerting, FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())}
+
+	parent := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "parent", RuleGroup: "group-a"}
+	sub := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "sub", RuleGroup: "group-b"}
+	other := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "other", RuleGroup: "group-c"}
+	for _, key := range []models.AlertRuleGroupKey{parent, parent, sub, other} {
+		createRule(t, store, models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithGroupKey(key)))
+	}
+
+	groups, err := store.ListGroupsInFolders(context.Background(), 1, []string{"parent", "sub"}, &user.SignedInUser{})
+	require.NoError(t, err)
+	require.Equal(t, []folder.RegistryGroup{
+		{FolderUID: "parent", Name: "group-a", Count: 2},
+		{FolderUID: "sub", Name: "group-b", Count: 1},
+	}, groups)
+
+	groups, err = store.ListGroupsInFolders(context.Background(), 2, []string{"parent"}, &user.SignedInUser{})
+	require.NoError(t, err)
+	require.Empty(t, groups)
+}
+
+func TestIntegration_ListInFolders(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	store := &DBstore{SQLStore: sqlStore, Cfg: cfg.UnifiedAlerting, FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())}
+
+	query := func(dsUID string) models.AlertQuery {
+		q := models.GenerateAlertQuery()
+		q.DatasourceUID = dsUID
+		return q
+	}
+	allowed := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "allowed"}
+	denied := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "denied"}
+	a := createRule(t, store, models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithGroupKey(allowed), models.WithTitle("a"), models.WithQuery(query("ds-1"))))
+	b := createRule(t, store, models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithGroupKey(allowed), models.WithTitle("b"), models.WithQuery(query("ds-1"))))
+	// the user cannot query one of the data sources of the group, so none of its rules are listed
+	createRule(t, store, models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithGroupKey(denied), models.WithQuery(query("ds-1"))))
+	createRule(t, store, models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithGroupKey(denied), models.WithQuery(query("ds-2"))))
+
+	store.AccessControl = acmock.New().WithPermissions([]accesscontrol.Permission{
+		{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("ds-1")},
+	})
+	items, err := store.ListInFolders(context.Background(), 1, []string{"folder"}, &user.SignedInUser{}, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, []folder.RegistryItem{
+		{UID: a.UID, Title: "a", FolderUID: "folder"},
+		{UID: b.UID, Title: "b", FolderUID: "folder"},
+	}, items)
+
+	items, err = store.ListInFolders(context.Background(), 1, []string{"folder"}, &user.SignedInUser{}, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, []folder.RegistryItem{{UID: b.UID, Title: "b", FolderUID: "folder"}}, items)
+
+	items, err = store.ListInFolders(context.Background(), 1, []string{"folder"}, &user.SignedInUser{}, 1, 3)
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
 func TestIntegration_DeleteInFolder(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
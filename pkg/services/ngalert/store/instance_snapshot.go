@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// SaveAlertInstanceSnapshot stores the snapshot and deletes the previous ones of the same node, so that only the latest
+// snapshot of every node is kept. The creation time of the snapshot is set by the caller to the time the alert instances
+// were read, and defaults to the current time.
+func (st DBstore) SaveAlertInstanceSnapshot(ctx context.Context, snapshot *models.AlertInstanceSnapshot) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if snapshot.Created.IsZero() {
+			snapshot.Created = time.Now().UTC()
+		}
+		snapshot.Size = int64(len(snapshot.Data))
+		if _, err := sess.Table("alert_instance_snapshot").Insert(snapshot); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM alert_instance_snapshot WHERE node = ? AND id <> ?", snapshot.Node, snapshot.ID)
+		return err
+	})
+}
+
+// GetLatestAlertInstanceSnapshot returns the latest snapshot saved by the node, or nil if there is none.
+// The data of the snapshot is only read if withData is true.
+func (st DBstore) GetLatestAlertInstanceSnapshot(ctx context.Context, node string, withData bool) (*models.AlertInstanceSnapshot, error) {
+	var result *models.AlertInstanceSnapshot
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table("alert_instance_snapshot").Where("node = ?", node).Desc("id")
+		if !withData {
+			q = q.Cols("id", "node", "created", "instances", "size")
+		}
+		snapshot := models.AlertInstanceSnapshot{}
+		has, err := q.Get(&snapshot)
+		if err != nil || !has {
+			return err
+		}
+		result = &snapshot
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListAlertInstanceKeys returns the keys of the alert instances of all organizations.
+func (st DBstore) ListAlertInstanceKeys(ctx context.Context) ([]models.AlertInstanceKey, error) {
+	var result []models.AlertInstanceKey
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_instance").Cols("rule_org_id", "rule_uid", "labels_hash").Find(&result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
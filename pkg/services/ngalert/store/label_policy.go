@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// LabelPolicyStore persists the label policies alert rules must satisfy.
+type LabelPolicyStore interface {
+	GetLabelPolicies(ctx context.Context, orgID int64) (ngmodels.LabelPolicies, error)
+	SaveLabelPolicy(ctx context.Context, policy *ngmodels.LabelPolicy) error
+	DeleteLabelPolicy(ctx context.Context, orgID int64, folderUID string) error
+}
+
+// GetLabelPolicies returns the label policies of the organization, including the policies of its folders.
+func (st DBstore) GetLabelPolicies(ctx context.Context, orgID int64) (ngmodels.LabelPolicies, error) {
+	var result ngmodels.LabelPolicies
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_rule_label_policy").Where("org_id = ?", orgID).Asc("folder_uid").Find(&result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SaveLabelPolicy creates or replaces the label policy of the organization or folder.
+func (st DBstore) SaveLabelPolicy(ctx context.Context, policy *ngmodels.LabelPolicy) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		policy.Updated = time.Now().UTC()
+		existing := ngmodels.LabelPolicy{}
+		has, err := sess.Table("alert_rule_label_policy").Where("org_id = ? AND folder_uid = ?", policy.OrgID, policy.FolderUID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = sess.Table("alert_rule_label_policy").Insert(policy)
+			return err
+		}
+		policy.ID = existing.ID
+		_, err = sess.Table("alert_rule_label_policy").ID(existing.ID).AllCols().Update(policy)
+		return err
+	})
+}
+
+// DeleteLabelPolicy deletes the label policy of the organization or, if folderUID is not empty, of the folder.
+func (st DBstore) DeleteLabelPolicy(ctx context.Context, orgID int64, folderUID string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_rule_label_policy WHERE org_id = ? AND folder_uid = ?", orgID, folderUID)
+		return err
+	})
+}
@@ -2,6 +2,9 @@ package store
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 )
@@ -24,3 +27,89 @@ func (st DBstore) GetOrgs(ctx context.Context) ([]int64, error) {
 	}
 	return orgs, nil
 }
+
+// OrgData is a kind of alerting data that is not deleted along with its organization, but in the background once the
+// organization is deleted.
+type OrgData string
+
+const (
+	OrgDataSilences             OrgData = "silences"
+	OrgDataConfigurationHistory OrgData = "configuration_history"
+)
+
+// OrgDataKinds are the kinds of alerting data of deleted organizations, in the order they are deleted.
+var OrgDataKinds = []OrgData{
+	OrgDataSilences,
+	OrgDataConfigurationHistory,
+}
+
+type orgDataTable struct {
+	name      string
+	orgColumn string
+	// keyColumn identifies the rows that are deleted in a batch.
+	keyColumn string
+}
+
+var orgDataTables = map[OrgData]orgDataTable{
+	OrgDataSilences:             {name: "alert_silence_metadata", orgColumn: "org_id", keyColumn: "id"},
+	OrgDataConfigurationHistory: {name: "alert_configuration_history", orgColumn: "org_id", keyColumn: "id"},
+}
+
+// GetDeletedOrgsWithData returns the IDs of the deleted organizations that still have alerting data, ordered by ID.
+func (st DBstore) GetDeletedOrgsWithData(ctx context.Context) ([]int64, error) {
+	orgs := make([]int64, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, kind := range OrgDataKinds {
+			t := orgDataTables[kind]
+			q := fmt.Sprintf("SELECT DISTINCT %[1]s FROM %[2]s WHERE %[1]s NOT IN (SELECT id FROM org)", t.orgColumn, t.name)
+			var ids []int64
+			if err := sess.SQL(q).Find(&ids); err != nil {
+				return fmt.Errorf("failed to find the deleted organizations with %s: %w", kind, err)
+			}
+			for _, id := range ids {
+				if !slices.Contains(orgs, id) {
+					orgs = append(orgs, id)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(orgs)
+	return orgs, nil
+}
+
+// DeleteOrgData deletes a batch of the alerting data of an organization. At most limit rows are deleted. It returns the
+// number of deleted rows, zero once all the data is deleted.
+func (st DBstore) DeleteOrgData(ctx context.Context, orgID int64, kind OrgData, limit int) (int64, error) {
+	t, ok := orgDataTables[kind]
+	if !ok {
+		return 0, fmt.Errorf("unknown kind of organization data %q", kind)
+	}
+
+	var deleted int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var ids []int64
+		if err := sess.Table(t.name).Where(t.orgColumn+" = ?", orgID).Cols(t.keyColumn).Limit(limit).Find(&ids); err != nil {
+			return err
+		}
+		keys := make([]any, 0, len(ids))
+		for _, id := range ids {
+			keys = append(keys, id)
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		sql := fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND %s IN (%s)", t.name, t.orgColumn, t.keyColumn, strings.Repeat("?,", len(keys)-1)+"?")
+		res, err := sess.Exec(append([]any{sql, orgID}, keys...)...)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
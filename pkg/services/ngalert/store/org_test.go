@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIntegrationDeleteOrgData(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{SQLStore: sqlStore}
+	ctx := context.Background()
+
+	const existingOrg, deletedOrg = int64(50), int64(51)
+	err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		now := time.Now()
+		_, err := sess.Exec("INSERT INTO org (id, version, name, created, updated) VALUES (?, 0, ?, ?, ?)", existingOrg, "existing", now, now)
+		return err
+	})
+	require.NoError(t, err)
+	for i, orgID := range []int64{existingOrg, deletedOrg, deletedOrg, deletedOrg} {
+		require.NoError(t, store.SaveSilenceMetadata(ctx, &models.SilenceMetadata{
+			OrgID:     orgID,
+			SilenceID: fmt.Sprintf("silence-%d", i),
+			CreatedBy: "user:1",
+		}))
+	}
+
+	orgs, err := store.GetDeletedOrgsWithData(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{deletedOrg}, orgs)
+
+	var batches []int64
+	for {
+		deleted, err := store.DeleteOrgData(ctx, deletedOrg, OrgDataSilences, 2)
+		require.NoError(t, err)
+		batches = append(batches, deleted)
+		if deleted == 0 {
+			break
+		}
+	}
+	require.Equal(t, []int64{2, 1, 0}, batches)
+
+	orgs, err = store.GetDeletedOrgsWithData(ctx)
+	require.NoError(t, err)
+	require.Empty(t, orgs)
+
+	metadata, err := store.GetSilenceMetadata(ctx, existingOrg, []string{"silence-0"})
+	require.NoError(t, err)
+	require.Len(t, metadata, 1, "the data of existing organizations is not deleted")
+
+	_, err = store.DeleteOrgData(ctx, deletedOrg, OrgData("unknown"), 2)
+	require.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ErrEvaluationPauseWindowNotFound is returned when the pause window does not exist in the organization.
+var ErrEvaluationPauseWindowNotFound = errors.New("evaluation pause window not found")
+
+// EvaluationPauseWindowStore persists the periods of time during which alert rules are not evaluated.
+type EvaluationPauseWindowStore interface {
+	GetEvaluationPauseWindows(ctx context.Context, orgID int64) (ngmodels.EvaluationPauseWindows, error)
+	GetEvaluationPauseWindowsEndingAfter(ctx context.Context, t time.Time) (ngmodels.EvaluationPauseWindows, error)
+	CreateEvaluationPauseWindow(ctx context.Context, window *ngmodels.EvaluationPauseWindow) error
+	DeleteEvaluationPauseWindow(ctx context.Context, orgID int64, id int64) error
+}
+
+// GetEvaluationPauseWindows returns the pause windows of the organization and its folders that did not end yet.
+func (st DBstore) GetEvaluationPauseWindows(ctx context.Context, orgID int64) (ngmodels.EvaluationPauseWindows, error) {
+	var result ngmodels.EvaluationPauseWindows
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_rule_pause_window").Where("org_id = ? AND ends_at > ?", orgID, time.Now().UTC()).Asc("starts_at").Find(&result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetEvaluationPauseWindowsEndingAfter returns the pause windows of all organizations that end after the given time,
+// including the ones that did not start yet.
+func (st DBstore) GetEvaluationPauseWindowsEndingAfter(ctx context.Context, t time.Time) (ngmodels.EvaluationPauseWindows, error) {
+	var result ngmodels.EvaluationPauseWindows
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_rule_pause_window").Where("ends_at > ?", t.UTC()).Find(&result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateEvaluationPauseWindow stores a new pause window and sets its ID.
+func (st DBstore) CreateEvaluationPauseWindow(ctx context.Context, window *ngmodels.EvaluationPauseWindow) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		window.Created = time.Now().UTC()
+		_, err := sess.Table("alert_rule_pause_window").Insert(window)
+		return err
+	})
+}
+
+// DeleteEvaluationPauseWindow deletes the pause window, ending it if it is active. It returns
+// ErrEvaluationPauseWindowNotFound if the organization has no pause window with the ID.
+func (st DBstore) DeleteEvaluationPauseWindow(ctx context.Context, orgID int64, id int64) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM alert_rule_pause_window WHERE org_id = ? AND id = ?", orgID, id)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrEvaluationPauseWindowNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// SilenceMetadataStore persists what Grafana records about the silences of its Alertmanagers.
+type SilenceMetadataStore interface {
+	GetSilenceMetadata(ctx context.Context, orgID int64, silenceIDs []string) (map[string]*ngmodels.SilenceMetadata, error)
+	SaveSilenceMetadata(ctx context.Context, metadata *ngmodels.SilenceMetadata) error
+	MarkSilenceExpiryNotified(ctx context.Context, orgID int64, silenceID string, endsAt time.Time) (bool, error)
+}
+
+// GetSilenceMetadata returns the metadata of the given silences, keyed by the ID of the silence.
+// Silences without metadata are not in the result.
+func (st DBstore) GetSilenceMetadata(ctx context.Context, orgID int64, silenceIDs []string) (map[string]*ngmodels.SilenceMetadata, error) {
+	result := make(map[string]*ngmodels.SilenceMetadata, len(silenceIDs))
+	if len(silenceIDs) == 0 {
+		return result, nil
+	}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for start := 0; start < len(silenceIDs); start += bulkWriteBatchSize {
+			var batch []*ngmodels.SilenceMetadata
+			ids := silenceIDs[start:min(start+bulkWriteBatchSize, len(silenceIDs))]
+			if err := sess.Table("alert_silence_metadata").Where("org_id = ?", orgID).In("silence_id", ids).Find(&batch); err != nil {
+				return err
+			}
+			for _, m := range batch {
+				result[m.SilenceID] = m
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SaveSilenceMetadata stores the metadata of a silence, replacing the creator and reference of existing metadata.
+func (st DBstore) SaveSilenceMetadata(ctx context.Context, metadata *ngmodels.SilenceMetadata) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if metadata.Created.IsZero() {
+			metadata.Created = time.Now().UTC()
+		}
+		updated, err := sess.Table("alert_silence_metadata").
+			Where("org_id = ? AND silence_id = ?", metadata.OrgID, metadata.SilenceID).
+			Cols("created_by", "created_by_login", "reference", "created").
+			Update(metadata)
+		if err != nil || updated > 0 {
+			return err
+		}
+		_, err = sess.Table("alert_silence_metadata").Insert(metadata)
+		return err
+	})
+}
+
+// MarkSilenceExpiryNotified records that the expiry of the silence that ends at the given time was notified.
+// It returns false if it was already notified, so that only one Grafana instance sends the notification.
+func (st DBstore) MarkSilenceExpiryNotified(ctx context.Context, orgID int64, silenceID string, endsAt time.Time) (bool, error) {
+	// databases differ in the precision of their date and time columns
+	endsAt = endsAt.UTC().Truncate(time.Second)
+	marked := false
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE alert_silence_metadata SET expiry_notified_ends_at = ? WHERE org_id = ? AND silence_id = ? AND (expiry_notified_ends_at IS NULL OR expiry_notified_ends_at <> ?)",
+			endsAt, orgID, silenceID, endsAt)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected > 0 {
+			marked = affected > 0
+			return err
+		}
+		exists, err := sess.Table("alert_silence_metadata").Where("org_id = ? AND silence_id = ?", orgID, silenceID).Exist()
+		if err != nil || exists {
+			return err
+		}
+		// silences that were created before their creator was recorded have no metadata
+		_, err = sess.Table("alert_silence_metadata").Insert(&ngmodels.SilenceMetadata{
+			OrgID:                orgID,
+			SilenceID:            silenceID,
+			Created:              time.Now().UTC(),
+			ExpiryNotifiedEndsAt: &endsAt,
+		})
+		if err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return nil
+			}
+			return err
+		}
+		marked = true
+		return nil
+	})
+	return marked, err
+}
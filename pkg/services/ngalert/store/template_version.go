@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// templateVersionsLimit is the number of versions kept per notification template. Older versions are deleted.
+const templateVersionsLimit = 50
+
+var ErrTemplateVersionNotFound = errors.New("template version not found")
+
+// TemplateVersionStore persists the versions of notification templates.
+type TemplateVersionStore interface {
+	GetTemplateVersions(ctx context.Context, orgID int64, name string) ([]ngmodels.NotificationTemplateVersion, error)
+	GetTemplateVersion(ctx context.Context, orgID int64, name string, version int64) (ngmodels.NotificationTemplateVersion, error)
+	SaveTemplateVersion(ctx context.Context, version *ngmodels.NotificationTemplateVersion) error
+}
+
+// GetTemplateVersions returns the versions of the template, latest first.
+func (st DBstore) GetTemplateVersions(ctx context.Context, orgID int64, name string) ([]ngmodels.NotificationTemplateVersion, error) {
+	var result []ngmodels.NotificationTemplateVersion
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_notification_template_version").Where("org_id = ? AND name = ?", orgID, name).Desc("version").Find(&result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetTemplateVersion returns a version of the template or ErrTemplateVersionNotFound.
+func (st DBstore) GetTemplateVersion(ctx context.Context, orgID int64, name string, version int64) (ngmodels.NotificationTemplateVersion, error) {
+	result := ngmodels.NotificationTemplateVersion{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Table("alert_notification_template_version").Where("org_id = ? AND name = ? AND version = ?", orgID, name, version).Get(&result)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrTemplateVersionNotFound
+		}
+		return nil
+	})
+	return result, err
+}
+
+// SaveTemplateVersion stores the version as the latest version of the template, unless the content of the template
+// did not change since the latest version. The number of the version is set by the store, and versions older than
+// the limit are deleted.
+func (st DBstore) SaveTemplateVersion(ctx context.Context, version *ngmodels.NotificationTemplateVersion) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		latest := ngmodels.NotificationTemplateVersion{}
+		has, err := sess.Table("alert_notification_template_version").Where("org_id = ? AND name = ?", version.OrgID, version.Name).Desc("version").Limit(1).Get(&latest)
+		if err != nil {
+			return err
+		}
+		if has && latest.Template == version.Template {
+			*version = latest
+			return nil
+		}
+
+		version.ID = 0
+		version.Version = latest.Version + 1
+		if version.Created.IsZero() {
+			version.Created = time.Now().UTC()
+		}
+		if _, err := sess.Table("alert_notification_template_version").Insert(version); err != nil {
+			return err
+		}
+		_, err = sess.Exec("DELETE FROM alert_notification_template_version WHERE org_id = ? AND name = ? AND version <= ?",
+			version.OrgID, version.Name, version.Version-templateVersionsLimit)
+		return err
+	})
+}
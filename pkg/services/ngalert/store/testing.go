@@ -2,9 +2,11 @@ package store
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
@@ -128,3 +130,162 @@ func (f *FakeAdminConfigStore) UpdateAdminConfiguration(cmd UpdateAdminConfigura
 
 	return nil
 }
+
+type FakeLabelPolicyStore struct {
+	mtx      sync.Mutex
+	Policies map[int64]map[string]*models.LabelPolicy
+}
+
+func NewFakeLabelPolicyStore() *FakeLabelPolicyStore {
+	return &FakeLabelPolicyStore{Policies: map[int64]map[string]*models.LabelPolicy{}}
+}
+
+func (f *FakeLabelPolicyStore) GetLabelPolicies(_ context.Context, orgID int64) (models.LabelPolicies, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make(models.LabelPolicies, 0, len(f.Policies[orgID]))
+	for _, p := range f.Policies[orgID] {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FolderUID < result[j].FolderUID
+	})
+	return result, nil
+}
+
+func (f *FakeLabelPolicyStore) SaveLabelPolicy(_ context.Context, policy *models.LabelPolicy) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if _, ok := f.Policies[policy.OrgID]; !ok {
+		f.Policies[policy.OrgID] = map[string]*models.LabelPolicy{}
+	}
+	f.Policies[policy.OrgID][policy.FolderUID] = policy
+	return nil
+}
+
+func (f *FakeLabelPolicyStore) DeleteLabelPolicy(_ context.Context, orgID int64, folderUID string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.Policies[orgID], folderUID)
+	return nil
+}
+
+type FakeEvaluationPauseWindowStore struct {
+	mtx     sync.Mutex
+	lastID  int64
+	Windows []*models.EvaluationPauseWindow
+}
+
+func NewFakeEvaluationPauseWindowStore() *FakeEvaluationPauseWindowStore {
+	return &FakeEvaluationPauseWindowStore{}
+}
+
+func (f *FakeEvaluationPauseWindowStore) GetEvaluationPauseWindows(_ context.Context, orgID int64) (models.EvaluationPauseWindows, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	now := time.Now()
+	var result models.EvaluationPauseWindows
+	for _, w := range f.Windows {
+		if w.OrgID == orgID && w.EndsAt.After(now) {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeEvaluationPauseWindowStore) GetEvaluationPauseWindowsEndingAfter(_ context.Context, t time.Time) (models.EvaluationPauseWindows, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var result models.EvaluationPauseWindows
+	for _, w := range f.Windows {
+		if w.EndsAt.After(t) {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeEvaluationPauseWindowStore) CreateEvaluationPauseWindow(_ context.Context, window *models.EvaluationPauseWindow) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.lastID++
+	window.ID = f.lastID
+	f.Windows = append(f.Windows, window)
+	return nil
+}
+
+func (f *FakeEvaluationPauseWindowStore) DeleteEvaluationPauseWindow(_ context.Context, orgID int64, id int64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for i, w := range f.Windows {
+		if w.OrgID == orgID && w.ID == id {
+			f.Windows = append(f.Windows[:i], f.Windows[i+1:]...)
+			return nil
+		}
+	}
+	return ErrEvaluationPauseWindowNotFound
+}
+
+type FakeSilenceMetadataStore struct {
+	mtx      sync.Mutex
+	lastID   int64
+	Metadata map[int64]map[string]*models.SilenceMetadata
+}
+
+func NewFakeSilenceMetadataStore() *FakeSilenceMetadataStore {
+	return &FakeSilenceMetadataStore{Metadata: map[int64]map[string]*models.SilenceMetadata{}}
+}
+
+func (f *FakeSilenceMetadataStore) GetSilenceMetadata(_ context.Context, orgID int64, silenceIDs []string) (map[string]*models.SilenceMetadata, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make(map[string]*models.SilenceMetadata, len(silenceIDs))
+	for _, id := range silenceIDs {
+		if m, ok := f.Metadata[orgID][id]; ok {
+			cpy := *m
+			result[id] = &cpy
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeSilenceMetadataStore) SaveSilenceMetadata(_ context.Context, metadata *models.SilenceMetadata) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.Metadata[metadata.OrgID] == nil {
+		f.Metadata[metadata.OrgID] = map[string]*models.SilenceMetadata{}
+	}
+	if metadata.Created.IsZero() {
+		metadata.Created = time.Now().UTC()
+	}
+	cpy := *metadata
+	if existing, ok := f.Metadata[metadata.OrgID][metadata.SilenceID]; ok {
+		cpy.ID = existing.ID
+		cpy.ExpiryNotifiedEndsAt = existing.ExpiryNotifiedEndsAt
+	} else {
+		f.lastID++
+		cpy.ID = f.lastID
+	}
+	f.Metadata[metadata.OrgID][metadata.SilenceID] = &cpy
+	return nil
+}
+
+func (f *FakeSilenceMetadataStore) MarkSilenceExpiryNotified(_ context.Context, orgID int64, silenceID string, endsAt time.Time) (bool, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	endsAt = endsAt.UTC().Truncate(time.Second)
+	if f.Metadata[orgID] == nil {
+		f.Metadata[orgID] = map[string]*models.SilenceMetadata{}
+	}
+	m, ok := f.Metadata[orgID][silenceID]
+	if !ok {
+		f.lastID++
+		m = &models.SilenceMetadata{ID: f.lastID, OrgID: orgID, SilenceID: silenceID, Created: time.Now().UTC()}
+		f.Metadata[orgID][silenceID] = m
+	}
+	if m.ExpiryNotifiedEndsAt != nil && m.ExpiryNotifiedEndsAt.Equal(endsAt) {
+		return false, nil
+	}
+	m.ExpiryNotifiedEndsAt = &endsAt
+	return true, nil
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// UsageInsightStore persists how the alerting objects are used.
+type UsageInsightStore interface {
+	GetUsageInsights(ctx context.Context, orgID int64, kind ngmodels.UsageInsightKind) (map[string]*ngmodels.UsageInsight, error)
+	AddUsageInsights(ctx context.Context, insights []ngmodels.UsageInsight) error
+}
+
+// GetUsageInsights returns the usage of the objects of the kind in the organization, keyed by their UID.
+// Objects that were never used are not in the result.
+func (st DBstore) GetUsageInsights(ctx context.Context, orgID int64, kind ngmodels.UsageInsightKind) (map[string]*ngmodels.UsageInsight, error) {
+	var insights []*ngmodels.UsageInsight
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_usage_insight").Where("org_id = ? AND kind = ?", orgID, kind).Find(&insights)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*ngmodels.UsageInsight, len(insights))
+	for _, i := range insights {
+		result[i.UID] = i
+	}
+	return result, nil
+}
+
+// AddUsageInsights merges the usage into the stored usage of the objects: the latest times are kept
+// and the notifications are summed.
+func (st DBstore) AddUsageInsights(ctx context.Context, insights []ngmodels.UsageInsight) error {
+	if len(insights) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, insight := range insights {
+			// databases differ in the precision of their date and time columns
+			truncateUsageTimes(&insight)
+			existing := ngmodels.UsageInsight{}
+			ok, err := sess.Table("alert_usage_insight").
+				Where("org_id = ? AND kind = ? AND uid = ?", insight.OrgID, insight.Kind, insight.UID).
+				Get(&existing)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				insight.ID = 0
+				if _, err := sess.Table("alert_usage_insight").Insert(&insight); err != nil {
+					return err
+				}
+				continue
+			}
+			existing.Merge(insight)
+			if _, err := sess.Table("alert_usage_insight").ID(existing.ID).
+				Cols("last_modified", "last_fired", "last_notified", "notifications").
+				Update(&existing); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func truncateUsageTimes(insight *ngmodels.UsageInsight) {
+	for _, t := range []**time.Time{&insight.LastModified, &insight.LastFired, &insight.LastNotified} {
+		if *t != nil {
+			truncated := (*t).UTC().Truncate(time.Second)
+			*t = &truncated
+		}
+	}
+}
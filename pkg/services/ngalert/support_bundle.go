@@ -0,0 +1,206 @@
+package ngalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// maxSupportBundleEvaluationErrors is the number of the most recent evaluation errors included in the support bundle.
+const maxSupportBundleEvaluationErrors = 100
+
+// tableCellReplacer escapes the text of a cell of a Markdown table.
+var tableCellReplacer = strings.NewReplacer("|", "\\|", "\n", " ")
+
+type supportBundleRuleCounter interface {
+	Count(ctx context.Context, orgID int64) (int64, error)
+}
+
+type supportBundleAlertmanagerConfigs interface {
+	GetAlertmanagerConfiguration(ctx context.Context, org int64) (apimodels.GettableUserConfig, error)
+}
+
+type supportBundleStates interface {
+	GetAll(orgID int64) []*state.State
+}
+
+// supportBundleCollector collects the information that is needed to troubleshoot alerting: the Alertmanager
+// configuration and the number of rules of every organization, a snapshot of the scheduler metrics, the status of the
+// state history backend, and the most recent evaluation errors. The Alertmanager configurations are the same as
+// returned by the API, so the secure settings of contact points are not included.
+func (ng *AlertNG) supportBundleCollector() supportbundles.Collector {
+	return supportbundles.Collector{
+		UID:               "alerting",
+		DisplayName:       "Alerting",
+		Description:       "Alertmanager configurations, rule counts, scheduler metrics, state history backend and recent evaluation errors",
+		IncludedByDefault: false,
+		Default:           true,
+		Fn: func(ctx context.Context) (*supportbundles.SupportItem, error) {
+			orgIDs, err := ng.store.GetOrgs(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch organizations: %w", err)
+			}
+			sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+
+			bWriter := bytes.NewBuffer(nil)
+			bWriter.WriteString("# Alerting information\n\n")
+			writeRuleCounts(ctx, bWriter, orgIDs, ng.store)
+			writeAlertmanagerConfigs(ctx, bWriter, orgIDs, ng.MultiOrgAlertmanager)
+			writeSchedulerMetrics(bWriter, ng.Metrics.GetSchedulerMetrics())
+			writeStateHistoryStatus(ctx, bWriter, ng.Cfg.UnifiedAlerting.StateHistory, ng.historian)
+			writeRecentEvaluationErrors(bWriter, orgIDs, ng.stateManager, maxSupportBundleEvaluationErrors)
+
+			return &supportbundles.SupportItem{
+				Filename:  "alerting.md",
+				FileBytes: bWriter.Bytes(),
+			}, nil
+		},
+	}
+}
+
+func writeRuleCounts(ctx context.Context, bWriter *bytes.Buffer, orgIDs []int64, rules supportBundleRuleCounter) {
+	bWriter.WriteString("## Alert rules\n\n")
+	bWriter.WriteString("| Organization | Rules |\n")
+	bWriter.WriteString("| --- | --- |\n")
+	for _, orgID := range orgIDs {
+		count, err := rules.Count(ctx, orgID)
+		if err != nil {
+			bWriter.WriteString(fmt.Sprintf("| %d | Unable to count the rules: %s |\n", orgID, err))
+			continue
+		}
+		bWriter.WriteString(fmt.Sprintf("| %d | %d |\n", orgID, count))
+	}
+	bWriter.WriteString("\n")
+}
+
+func writeAlertmanagerConfigs(ctx context.Context, bWriter *bytes.Buffer, orgIDs []int64, configs supportBundleAlertmanagerConfigs) {
+	bWriter.WriteString("## Alertmanager configurations\n\n")
+	for _, orgID := range orgIDs {
+		bWriter.WriteString(fmt.Sprintf("### Organization %d\n\n", orgID))
+		cfg, err := configs.GetAlertmanagerConfiguration(ctx, orgID)
+		if err != nil {
+			bWriter.WriteString(fmt.Sprintf("Unable to get the Alertmanager configuration  \n Err: %s\n\n", err))
+			continue
+		}
+		b, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			bWriter.WriteString(fmt.Sprintf("Unable to encode the Alertmanager configuration  \n Err: %s\n\n", err))
+			continue
+		}
+		bWriter.WriteString("```json\n")
+		bWriter.Write(b)
+		bWriter.WriteString("\n```\n\n")
+	}
+}
+
+// writeSchedulerMetrics writes the current values of the scheduler metrics in the Prometheus text format.
+func writeSchedulerMetrics(bWriter *bytes.Buffer, m *metrics.Scheduler) {
+	bWriter.WriteString("## Scheduler metrics\n\n")
+	registry := prometheus.NewRegistry()
+	for _, c := range []prometheus.Collector{
+		m.BehindSeconds,
+		m.EvalTotal,
+		m.EvalFailures,
+		m.EvalDuration,
+		m.ProcessDuration,
+		m.SendDuration,
+		m.GroupRules,
+		m.Groups,
+		m.SchedulePeriodicDuration,
+		m.SchedulableAlertRules,
+		m.SchedulableAlertRulesHash,
+		m.UpdateSchedulableAlertRulesDuration,
+		m.EvaluationMissed,
+	} {
+		if err := registry.Register(c); err != nil {
+			bWriter.WriteString(fmt.Sprintf("Unable to collect the scheduler metrics  \n Err: %s\n\n", err))
+			return
+		}
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		bWriter.WriteString(fmt.Sprintf("Unable to collect the scheduler metrics  \n Err: %s\n\n", err))
+		return
+	}
+	bWriter.WriteString("```\n")
+	for _, mf := range families {
+		if _, err := expfmt.MetricFamilyToText(bWriter, mf); err != nil {
+			bWriter.WriteString(fmt.Sprintf("Unable to encode %s: %s\n", mf.GetName(), err))
+		}
+	}
+	bWriter.WriteString("```\n\n")
+}
+
+// writeStateHistoryStatus writes the configuration of the state history backend, without its credentials,
+// and the result of a connection test if the backend supports one.
+func writeStateHistoryStatus(ctx context.Context, bWriter *bytes.Buffer, cfg setting.UnifiedAlertingStateHistorySettings, history Historian) {
+	bWriter.WriteString("## State history\n\n")
+	bWriter.WriteString(fmt.Sprintf("- Enabled: %t\n", cfg.Enabled))
+	if !cfg.Enabled {
+		bWriter.WriteString("\n")
+		return
+	}
+	bWriter.WriteString(fmt.Sprintf("- Backend: %s\n", cfg.Backend))
+	if cfg.MultiPrimary != "" {
+		bWriter.WriteString(fmt.Sprintf("- Primary backend: %s\n", cfg.MultiPrimary))
+		bWriter.WriteString(fmt.Sprintf("- Secondary backends: %s\n", strings.Join(cfg.MultiSecondaries, ", ")))
+	}
+
+	tester, ok := history.(interface {
+		TestConnection(ctx context.Context) error
+	})
+	if !ok {
+		bWriter.WriteString("\n")
+		return
+	}
+	if err := tester.TestConnection(ctx); err != nil {
+		bWriter.WriteString(fmt.Sprintf("- Connection: failed: %s\n\n", err))
+		return
+	}
+	bWriter.WriteString("- Connection: ok\n\n")
+}
+
+// writeRecentEvaluationErrors writes the most recent evaluation errors of the alert instances of all organizations,
+// up to the limit.
+func writeRecentEvaluationErrors(bWriter *bytes.Buffer, orgIDs []int64, states supportBundleStates, limit int) {
+	bWriter.WriteString("## Recent evaluation errors\n\n")
+	var errored []*state.State
+	for _, orgID := range orgIDs {
+		for _, s := range states.GetAll(orgID) {
+			if s.Error != nil {
+				errored = append(errored, s)
+			}
+		}
+	}
+	if len(errored) == 0 {
+		bWriter.WriteString("No evaluation errors\n\n")
+		return
+	}
+	sort.Slice(errored, func(i, j int) bool {
+		return errored[i].LastEvaluationTime.After(errored[j].LastEvaluationTime)
+	})
+	if len(errored) > limit {
+		errored = errored[:limit]
+	}
+	bWriter.WriteString("| Evaluated at | Organization | Rule UID | Labels | Error |\n")
+	bWriter.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, s := range errored {
+		bWriter.WriteString(fmt.Sprintf("| %s | %d | %s | %s | %s |\n",
+			s.LastEvaluationTime.UTC().Format(time.RFC3339), s.OrgID, s.AlertRuleUID, s.Labels.String(),
+			tableCellReplacer.Replace(s.Error.Error())))
+	}
+	bWriter.WriteString("\n")
+}
@@ -0,0 +1,91 @@
+package ngalert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeSupportBundleStates map[int64][]*state.State
+
+func (f fakeSupportBundleStates) GetAll(orgID int64) []*state.State {
+	return f[orgID]
+}
+
+type fakeConnectionTester struct {
+	Historian
+	err error
+}
+
+func (f fakeConnectionTester) TestConnection(context.Context) error {
+	return f.err
+}
+
+func TestWriteRecentEvaluationErrors(t *testing.T) {
+	now := time.Now()
+	states := fakeSupportBundleStates{
+		1: {
+			{OrgID: 1, AlertRuleUID: "old", State: eval.Error, Error: errors.New("old error"), LastEvaluationTime: now.Add(-time.Hour)},
+			{OrgID: 1, AlertRuleUID: "ok", State: eval.Normal, LastEvaluationTime: now},
+		},
+		2: {
+			{OrgID: 2, AlertRuleUID: "new", State: eval.Error, Error: errors.New("query | failed\nbadly"), Labels: data.Labels{"team": "a"}, LastEvaluationTime: now},
+		},
+	}
+
+	t.Run("should write the most recent errors first", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		writeRecentEvaluationErrors(b, []int64{1, 2}, states, 10)
+		out := b.String()
+		require.NotContains(t, out, "| ok |")
+		require.Contains(t, out, `query \| failed badly`)
+		require.Less(t, bytes.Index(b.Bytes(), []byte("| new |")), bytes.Index(b.Bytes(), []byte("| old |")))
+	})
+
+	t.Run("should write at most the limit", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		writeRecentEvaluationErrors(b, []int64{1, 2}, states, 1)
+		require.Contains(t, b.String(), "| new |")
+		require.NotContains(t, b.String(), "| old |")
+	})
+
+	t.Run("should write that there are no errors", func(t *testing.T) {
+		b := bytes.NewBuffer(nil)
+		writeRecentEvaluationErrors(b, []int64{3}, states, 10)
+		require.Contains(t, b.String(), "No evaluation errors")
+	})
+}
+
+func TestWriteSchedulerMetrics(t *testing.T) {
+	m := metrics.NewSchedulerMetrics(prometheus.NewRegistry())
+	m.EvalFailures.WithLabelValues("1").Add(3)
+
+	b := bytes.NewBuffer(nil)
+	writeSchedulerMetrics(b, m)
+	require.Contains(t, b.String(), `grafana_alerting_rule_evaluation_failures_total{org="1"} 3`)
+}
+
+func TestWriteStateHistoryStatus(t *testing.T) {
+	cfg := setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "loki", LokiBasicAuthPassword: "secret"}
+
+	b := bytes.NewBuffer(nil)
+	writeStateHistoryStatus(context.Background(), b, cfg, fakeConnectionTester{err: errors.New("connection refused")})
+	require.Contains(t, b.String(), "- Backend: loki")
+	require.Contains(t, b.String(), "- Connection: failed: connection refused")
+	require.NotContains(t, b.String(), "secret")
+
+	b.Reset()
+	writeStateHistoryStatus(context.Background(), b, cfg, fakeConnectionTester{})
+	require.Contains(t, b.String(), "- Connection: ok")
+}
@@ -3,7 +3,6 @@ package alerting
 import (
 	"context"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -39,22 +38,34 @@ func (cr *rulesConfigReader) readConfig(ctx context.Context, path string) ([]*Al
 			cr.log.Warn(fmt.Sprintf("file has invalid suffix '%s' (.yaml,.yml,.json accepted), skipping", file.Name()))
 			continue
 		}
-		alertFileV1, err := cr.parseConfig(path, file)
+		alertFile, err := cr.readFile(path, file.Name())
 		if err != nil {
-			return nil, fmt.Errorf("failure to parse file %s: %w", file.Name(), err)
+			return nil, err
 		}
-		if alertFileV1 != nil {
-			alertFileV1.Filename = file.Name()
-			alertFile, err := alertFileV1.MapToModel()
-			if err != nil {
-				return nil, fmt.Errorf("failure to map file %s: %w", alertFileV1.Filename, err)
-			}
-			alertFiles = append(alertFiles, &alertFile)
+		if alertFile != nil {
+			alertFiles = append(alertFiles, alertFile)
 		}
 	}
 	return alertFiles, nil
 }
 
+// readFile parses a single provisioning file. It returns nil if the file is empty.
+func (cr *rulesConfigReader) readFile(path string, name string) (*AlertingFile, error) {
+	alertFileV1, err := cr.parseConfig(path, name)
+	if err != nil {
+		return nil, fmt.Errorf("failure to parse file %s: %w", name, err)
+	}
+	if alertFileV1 == nil {
+		return nil, nil
+	}
+	alertFileV1.Filename = name
+	alertFile, err := alertFileV1.MapToModel()
+	if err != nil {
+		return nil, fmt.Errorf("failure to map file %s: %w", alertFileV1.Filename, err)
+	}
+	return &alertFile, nil
+}
+
 func (cr *rulesConfigReader) isYAML(file string) bool {
 	return strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml")
 }
@@ -63,8 +74,8 @@ func (cr *rulesConfigReader) isJSON(file string) bool {
 	return strings.HasSuffix(file, ".json")
 }
 
-func (cr *rulesConfigReader) parseConfig(path string, file fs.DirEntry) (*AlertingFileV1, error) {
-	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+func (cr *rulesConfigReader) parseConfig(path string, name string) (*AlertingFileV1, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, name))
 	// nolint:gosec
 	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
 	yamlFile, err := os.ReadFile(filename)
@@ -16,6 +16,7 @@ func TestNotificationPolicy(t *testing.T) {
 
 	data := `orgId: 123
 receiver: test
+description: Root policy
 continue: true
 repeat_interval: ${NOTIFIER_EMAIL_REMINDER_FREQUENCY}
 `
@@ -27,6 +28,7 @@ repeat_interval: ${NOTIFIER_EMAIL_REMINDER_FREQUENCY}
 	require.NoError(t, err)
 	require.Equal(t, int64(123), np.OrgID)
 	require.Equal(t, "test", np.Policy.Receiver)
+	require.Equal(t, "Root policy", np.Policy.Description)
 	require.True(t, np.Policy.Continue)
 	require.Equal(t, envValue, np.Policy.RepeatInterval.String())
 }
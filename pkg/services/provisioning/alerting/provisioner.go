@@ -29,12 +29,22 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	}
 	logger.Info("starting to provision alerting")
 	logger.Debug("read all alerting files", "file_count", len(files))
+	err = provisionFiles(ctx, logger, cfg, files)
+	if err != nil {
+		return err
+	}
+	logger.Info("finished to provision alerting")
+	return nil
+}
+
+// provisionFiles applies the content of the provisioning files.
+func provisionFiles(ctx context.Context, logger log.Logger, cfg ProvisionerConfig, files []*AlertingFile) error {
 	ruleProvisioner := NewAlertRuleProvisioner(
 		logger,
 		cfg.DashboardService,
 		cfg.DashboardProvService,
 		cfg.RuleService)
-	err = ruleProvisioner.Provision(ctx, files)
+	err := ruleProvisioner.Provision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("alert rules: %w", err)
 	}
@@ -74,6 +84,5 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
 	}
-	logger.Info("finished to provision alerting")
 	return nil
 }
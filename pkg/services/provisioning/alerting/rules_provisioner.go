@@ -63,6 +63,10 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 			if err != nil {
 				return err
 			}
+			err = prov.ruleService.UpdateRuleGroupLabels(ctx, group.OrgID, folderUID, group.Title, group.Labels)
+			if err != nil {
+				return err
+			}
 		}
 		for _, deleteRule := range file.DeleteRules {
 			err := prov.ruleService.DeleteAlertRule(ctx, deleteRule.OrgID,
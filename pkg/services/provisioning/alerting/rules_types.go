@@ -24,11 +24,12 @@ type RuleDeleteV1 struct {
 }
 
 type AlertRuleGroupV1 struct {
-	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
-	Name     values.StringValue `json:"name" yaml:"name"`
-	Folder   values.StringValue `json:"folder" yaml:"folder"`
-	Interval values.StringValue `json:"interval" yaml:"interval"`
-	Rules    []AlertRuleV1      `json:"rules" yaml:"rules"`
+	OrgID    values.Int64Value     `json:"orgId" yaml:"orgId"`
+	Name     values.StringValue    `json:"name" yaml:"name"`
+	Folder   values.StringValue    `json:"folder" yaml:"folder"`
+	Interval values.StringValue    `json:"interval" yaml:"interval"`
+	Labels   values.StringMapValue `json:"labels" yaml:"labels"`
+	Rules    []AlertRuleV1         `json:"rules" yaml:"rules"`
 }
 
 func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFolderTitle, error) {
@@ -46,6 +47,7 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 		return models.AlertRuleGroupWithFolderTitle{}, err
 	}
 	ruleGroup.Interval = int64(time.Duration(interval).Seconds())
+	ruleGroup.Labels = ruleGroupV1.Labels.Value()
 	ruleGroup.FolderTitle = ruleGroupV1.Folder.Value()
 	if strings.TrimSpace(ruleGroup.FolderTitle) == "" {
 		return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group has no folder set")
@@ -73,6 +75,13 @@ type AlertRuleV1 struct {
 	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
 	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
 	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	// FingerprintLabels are the names of the labels that identify the alert instances of the rule.
+	FingerprintLabels []values.StringValue `json:"fingerprintLabels" yaml:"fingerprintLabels"`
+	// PartialResults allows the expressions of the rule to be evaluated when some, but not all, of their inputs failed.
+	PartialResults values.BoolValue `json:"partialResults" yaml:"partialResults"`
+	// MissingSeriesEvalsToResolve and MissingSeriesDurationToResolve are the stale series policy of the rule.
+	MissingSeriesEvalsToResolve    values.Int64Value  `json:"missingSeriesEvalsToResolve" yaml:"missingSeriesEvalsToResolve"`
+	MissingSeriesDurationToResolve values.StringValue `json:"missingSeriesDurationToResolve" yaml:"missingSeriesDurationToResolve"`
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -130,6 +139,18 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	for _, name := range rule.FingerprintLabels {
+		alertRule.FingerprintLabels = append(alertRule.FingerprintLabels, name.Value())
+	}
+	alertRule.PartialResults = rule.PartialResults.Value()
+	alertRule.MissingSeriesEvalsToResolve = rule.MissingSeriesEvalsToResolve.Value()
+	if d := rule.MissingSeriesDurationToResolve.Value(); d != "" {
+		duration, err := model.ParseDuration(d)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.MissingSeriesDurationToResolve = time.Duration(duration)
+	}
 	return alertRule, nil
 }
 
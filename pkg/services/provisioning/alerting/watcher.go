@@ -0,0 +1,210 @@
+package alerting
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	FileStatusApplied = "applied"
+	FileStatusError   = "error"
+)
+
+// FileStatus is the result of the last reconciliation of a provisioning file.
+type FileStatus struct {
+	Filename string `json:"filename"`
+	// Checksum is the SHA-256 checksum of the content of the file that was last reconciled.
+	Checksum string `json:"checksum"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	// LastReconciled is the time the file was last parsed and applied.
+	LastReconciled time.Time `json:"lastReconciled"`
+	// LastApplied is the time the file was last applied successfully.
+	LastApplied *time.Time `json:"lastApplied,omitempty"`
+}
+
+// Watcher watches the alerting provisioning directory and applies its files again whenever one of them is added or changed.
+// Files that cannot be parsed or applied are reported in their status and do not prevent the other files from being applied.
+// Removing a file does not delete what it provisioned, the resources must be deleted explicitly, like at startup.
+type Watcher struct {
+	cfg      ProvisionerConfig
+	interval time.Duration
+	logger   log.Logger
+	reader   rulesConfigReader
+	now      func() time.Time
+	// provision applies the files, it is replaced in tests.
+	provision func(ctx context.Context, files []*AlertingFile) error
+
+	mtx   sync.RWMutex
+	files map[string]FileStatus
+}
+
+func NewWatcher(cfg ProvisionerConfig, interval time.Duration) *Watcher {
+	logger := log.New("provisioning.alerting.watcher")
+	w := &Watcher{
+		cfg:      cfg,
+		interval: interval,
+		logger:   logger,
+		reader:   newRulesConfigReader(logger),
+		now:      time.Now,
+		files:    make(map[string]FileStatus),
+	}
+	w.provision = func(ctx context.Context, files []*AlertingFile) error {
+		return provisionFiles(ctx, logger, cfg, files)
+	}
+	return w
+}
+
+// Run reconciles the provisioning files at every interval, until the context is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.logger.Info("Watching alerting provisioning files for changes", "path", w.cfg.Path, "interval", w.interval)
+	w.reconcile(ctx)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.reconcile(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Status returns the status of the provisioning files, sorted by name.
+func (w *Watcher) Status() []FileStatus {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	result := make([]FileStatus, 0, len(w.files))
+	for _, s := range w.files {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Filename < result[j].Filename
+	})
+	return result
+}
+
+// reconcile applies all the provisioning files if any of them was added or changed since the last reconciliation.
+// Files are applied together, so that they can reference each other. If that fails, they are applied one by one
+// to find out which of them cannot be applied.
+func (w *Watcher) reconcile(ctx context.Context) {
+	checksums, err := w.checksums()
+	if err != nil {
+		w.logger.Error("Failed to read alerting provisioning directory", "path", w.cfg.Path, "error", err)
+		return
+	}
+	if !w.changed(checksums) {
+		return
+	}
+
+	now := w.now()
+	statuses := make(map[string]FileStatus, len(checksums))
+	var files []*AlertingFile
+	for name, checksum := range checksums {
+		status := w.previous(name)
+		status.Filename = name
+		status.Checksum = checksum
+		status.LastReconciled = now
+		status.Status = FileStatusApplied
+		status.Error = ""
+		file, err := w.reader.readFile(w.cfg.Path, name)
+		if err != nil {
+			status.Status = FileStatusError
+			status.Error = err.Error()
+		} else if file != nil {
+			files = append(files, file)
+		}
+		statuses[name] = status
+	}
+	// apply the files in the same order as at startup
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Filename < files[j].Filename
+	})
+
+	w.logger.Info("Alerting provisioning files changed, applying them", "file_count", len(files))
+	applyErrs := make(map[string]error)
+	if err := w.provision(ctx, files); err != nil {
+		w.logger.Warn("Failed to apply alerting provisioning files, applying them one by one", "error", err)
+		for _, file := range files {
+			if err := w.provision(ctx, []*AlertingFile{file}); err != nil {
+				applyErrs[file.Filename] = err
+			}
+		}
+	}
+
+	for name, status := range statuses {
+		if status.Status == FileStatusError {
+			w.logger.Error("Failed to parse alerting provisioning file", "file", name, "error", status.Error)
+			continue
+		}
+		if err, ok := applyErrs[name]; ok {
+			w.logger.Error("Failed to apply alerting provisioning file", "file", name, "error", err)
+			status.Status = FileStatusError
+			status.Error = err.Error()
+		} else {
+			appliedAt := now
+			status.LastApplied = &appliedAt
+		}
+		statuses[name] = status
+	}
+
+	w.mtx.Lock()
+	w.files = statuses
+	w.mtx.Unlock()
+}
+
+// checksums returns the checksum of the content of every provisioning file in the directory.
+func (w *Watcher) checksums() (map[string]string, error) {
+	entries, err := os.ReadDir(w.cfg.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	result := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || (!w.reader.isYAML(entry.Name()) && !w.reader.isJSON(entry.Name())) {
+			continue
+		}
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because the path comes from the provisioning path
+		content, err := os.ReadFile(filepath.Join(w.cfg.Path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		result[entry.Name()] = hex.EncodeToString(sum[:])
+	}
+	return result, nil
+}
+
+// changed returns true if a file was added, changed or removed since the last reconciliation.
+func (w *Watcher) changed(checksums map[string]string) bool {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	if len(checksums) != len(w.files) {
+		return true
+	}
+	for name, checksum := range checksums {
+		if s, ok := w.files[name]; !ok || s.Checksum != checksum {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) previous(name string) FileStatus {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	return w.files[name]
+}
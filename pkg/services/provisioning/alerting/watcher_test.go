@@ -0,0 +1,96 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	const templateFile = `
+apiVersion: 1
+templates:
+  - orgId: 1
+    name: welcome
+    template: Hello`
+
+	var applied [][]string
+	failing := map[string]bool{}
+	w := NewWatcher(ProvisionerConfig{Path: dir}, 0)
+	w.provision = func(ctx context.Context, files []*AlertingFile) error {
+		var names []string
+		for _, f := range files {
+			if failing[f.Filename] {
+				return errors.New("cannot apply")
+			}
+			names = append(names, f.Filename)
+		}
+		applied = append(applied, names)
+		return nil
+	}
+
+	writeFile("a.yaml", templateFile)
+	writeFile("b.yaml", templateFile)
+	writeFile("ignored.txt", "not provisioning")
+
+	w.reconcile(context.Background())
+	require.Equal(t, [][]string{{"a.yaml", "b.yaml"}}, applied)
+	status := w.Status()
+	require.Len(t, status, 2)
+	for _, s := range status {
+		require.Equal(t, FileStatusApplied, s.Status)
+		require.NotNil(t, s.LastApplied)
+	}
+
+	t.Run("unchanged files are not applied again", func(t *testing.T) {
+		applied = nil
+		w.reconcile(context.Background())
+		require.Empty(t, applied)
+	})
+
+	t.Run("files that cannot be parsed are reported and the others are applied", func(t *testing.T) {
+		applied = nil
+		writeFile("b.yaml", "{{{")
+		w.reconcile(context.Background())
+		require.Equal(t, [][]string{{"a.yaml"}}, applied)
+
+		status := w.Status()
+		require.Equal(t, "a.yaml", status[0].Filename)
+		require.Equal(t, FileStatusApplied, status[0].Status)
+		require.Equal(t, "b.yaml", status[1].Filename)
+		require.Equal(t, FileStatusError, status[1].Status)
+		require.Contains(t, status[1].Error, "failure to parse file b.yaml")
+		// the time the file was last applied successfully is kept
+		require.NotNil(t, status[1].LastApplied)
+	})
+
+	t.Run("files that cannot be applied are reported and the others are applied", func(t *testing.T) {
+		applied = nil
+		failing["a.yaml"] = true
+		writeFile("b.yaml", templateFile)
+		w.reconcile(context.Background())
+		require.Equal(t, [][]string{{"b.yaml"}}, applied)
+
+		status := w.Status()
+		require.Equal(t, FileStatusError, status[0].Status)
+		require.Equal(t, "cannot apply", status[0].Error)
+		require.Equal(t, FileStatusApplied, status[1].Status)
+		require.Empty(t, status[1].Error)
+	})
+
+	t.Run("removed files are no longer reported", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.yaml")))
+		w.reconcile(context.Background())
+		status := w.Status()
+		require.Len(t, status, 1)
+		require.Equal(t, "b.yaml", status[0].Filename)
+	})
+}
@@ -78,6 +78,9 @@ func ProvideService(
 		orgService:                   orgService,
 		folderService:                folderService,
 	}
+	if cfg.UnifiedAlerting.Provisioning.Watch {
+		s.alertingWatcher = prov_alerting.NewWatcher(s.alertingProvisionerConfig(), cfg.UnifiedAlerting.Provisioning.WatchInterval)
+	}
 	return s, nil
 }
 
@@ -91,6 +94,9 @@ type ProvisioningService interface {
 	ProvisionAlerting(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	// GetAlertingProvisioningStatus returns the status of the alerting provisioning files,
+	// and false if they are not watched for changes.
+	GetAlertingProvisioningStatus() ([]prov_alerting.FileStatus, bool)
 }
 
 // Add a public constructor for overriding service to be able to instantiate OSS as fallback
@@ -137,6 +143,7 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	alertingWatcher              *prov_alerting.Watcher
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -187,6 +194,13 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 	if ps.dashboardProvisioner.HasDashboardSources() {
 		ps.searchService.TriggerReIndex()
 	}
+	if ps.alertingWatcher != nil {
+		go func() {
+			if err := ps.alertingWatcher.Run(ctx); err != nil {
+				ps.log.Error("Failed to watch alerting provisioning files", "error", err)
+			}
+		}()
+	}
 
 	for {
 		// Wait for unlock. This is tied to new dashboardProvisioner to be instantiated before we start polling.
@@ -264,6 +278,10 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
+	return ps.provisionAlerting(ctx, ps.alertingProvisionerConfig())
+}
+
+func (ps *ProvisioningServiceImpl) alertingProvisionerConfig() prov_alerting.ProvisionerConfig {
 	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
 	st := store.DBstore{
 		Cfg:              ps.Cfg.UnifiedAlerting,
@@ -274,6 +292,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	}
 	ruleService := provisioning.NewAlertRuleService(
 		st,
+		&st,
 		st,
 		ps.dashboardService,
 		ps.quotaService,
@@ -288,7 +307,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
 	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
-	cfg := prov_alerting.ProvisionerConfig{
+	return prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
 		DashboardService:           ps.dashboardService,
@@ -298,7 +317,6 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
 	}
-	return ps.provisionAlerting(ctx, cfg)
 }
 
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
@@ -309,6 +327,13 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+func (ps *ProvisioningServiceImpl) GetAlertingProvisioningStatus() ([]prov_alerting.FileStatus, bool) {
+	if ps.alertingWatcher == nil {
+		return nil, false
+	}
+	return ps.alertingWatcher.Status(), true
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
@@ -1,6 +1,10 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+
+	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []any
@@ -11,6 +15,7 @@ type Calls struct {
 	ProvisionAlerting                   []any
 	GetDashboardProvisionerResolvedPath []any
 	GetAllowUIUpdatesFromConfig         []any
+	GetAlertingProvisioningStatus       []any
 	Run                                 []any
 }
 
@@ -23,6 +28,7 @@ type ProvisioningServiceMock struct {
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	GetAlertingProvisioningStatusFunc       func() ([]prov_alerting.FileStatus, bool)
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -93,6 +99,14 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) GetAlertingProvisioningStatus() ([]prov_alerting.FileStatus, bool) {
+	mock.Calls.GetAlertingProvisioningStatus = append(mock.Calls.GetAlertingProvisioningStatus, nil)
+	if mock.GetAlertingProvisioningStatusFunc != nil {
+		return mock.GetAlertingProvisioningStatusFunc()
+	}
+	return nil, false
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
@@ -201,7 +201,8 @@ func buildErrorResponses(err error, queries []*simplejson.Json) splitResponse {
 // handleExpressions handles POST /api/ds/query when there is an expression.
 func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Requester, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	exprReq := expr.Request{
-		Queries: []expr.Query{},
+		Queries:        []expr.Query{},
+		PartialResults: parsedReq.partialResults,
 	}
 
 	if user != nil { // for passthrough authentication, SSE does not authenticate
@@ -279,9 +280,10 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user identity.Requ
 
 	timeRange := legacydata.NewDataTimeRange(reqDTO.From, reqDTO.To)
 	req := &parsedRequest{
-		hasExpression: false,
-		parsedQueries: make(map[string][]parsedQuery),
-		dsTypes:       make(map[string]bool),
+		hasExpression:  false,
+		parsedQueries:  make(map[string][]parsedQuery),
+		dsTypes:        make(map[string]bool),
+		partialResults: reqDTO.PartialResults,
 	}
 
 	// Parse the queries and store them by datasource
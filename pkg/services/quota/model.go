@@ -198,8 +198,26 @@ type QuotaDTO struct {
 	Used    int64  `json:"used"`
 	Service string `json:"-"`
 	Scope   string `json:"-"`
+	// SoftLimit is the usage after which a warning is raised, it is omitted when no soft limit is configured
+	SoftLimit int64 `json:"soft_limit,omitempty"`
+	// Warning is set when the usage has reached the soft limit
+	Warning string `json:"warning,omitempty"`
+	// Breakdown is only populated when explicitly requested
+	Breakdown []UsageBreakdown `json:"breakdown,omitempty"`
 }
 
+// UsageBreakdown describes the part of a quota usage that is attributed to a single resource,
+// for example the number of alert rules stored in a folder.
+type UsageBreakdown struct {
+	// Kind is the type of the resource the usage is attributed to, e.g. "folder".
+	Kind string `json:"kind"`
+	// Key identifies the resource, e.g. the folder UID.
+	Key  string `json:"key"`
+	Used int64  `json:"used"`
+}
+
+const UsageBreakdownKindFolder = "folder"
+
 func (dto QuotaDTO) Tag() (Tag, error) {
 	return NewTag(TargetSrv(dto.Service), Target(dto.Target), Scope(dto.Scope))
 }
@@ -215,4 +233,6 @@ type NewUsageReporter struct {
 	TargetSrv     TargetSrv
 	DefaultLimits *Map
 	Reporter      UsageReporterFunc
+	// BreakdownReporter is optional
+	BreakdownReporter UsageBreakdownReporterFunc
 }
@@ -11,6 +11,9 @@ type Service interface {
 	// If the scope is organization, the ID is expected to be the organisation ID.
 	// If the scope is user, the id is expected to be the user ID.
 	GetQuotasByScope(ctx context.Context, scope Scope, ID int64) ([]QuotaDTO, error)
+	// GetQuotasByScopeWithBreakdown is like GetQuotasByScope but additionally includes, for every target
+	// whose service registered a breakdown reporter, how the usage is distributed (for example per folder).
+	GetQuotasByScopeWithBreakdown(ctx context.Context, scope Scope, ID int64) ([]QuotaDTO, error)
 	// Update overrides the quota for a specific scope (global, organization, user).
 	// If the cmd.OrgID is set, then the organization quota are updated.
 	// If the cmd.UseID is set, then the user quota are updated.
@@ -28,3 +31,6 @@ type Service interface {
 }
 
 type UsageReporterFunc func(ctx context.Context, scopeParams *ScopeParameters) (*Map, error)
+
+// UsageBreakdownReporterFunc returns, for each tag, a detailed breakdown of what is consuming the quota.
+type UsageBreakdownReporterFunc func(ctx context.Context, scopeParams *ScopeParameters) (map[Tag][]UsageBreakdown, error)
@@ -24,6 +24,10 @@ func (s *serviceDisabled) GetQuotasByScope(ctx context.Context, scope quota.Scop
 	return nil, quota.ErrDisabled
 }
 
+func (s *serviceDisabled) GetQuotasByScopeWithBreakdown(ctx context.Context, scope quota.Scope, id int64) ([]quota.QuotaDTO, error) {
+	return nil, quota.ErrDisabled
+}
+
 func (s *serviceDisabled) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd) error {
 	return quota.ErrDisabled
 }
@@ -45,23 +49,28 @@ type service struct {
 	Cfg    *setting.Cfg
 	Logger log.Logger
 
-	mutex     sync.RWMutex
-	reporters map[quota.TargetSrv]quota.UsageReporterFunc
+	mutex              sync.RWMutex
+	reporters          map[quota.TargetSrv]quota.UsageReporterFunc
+	breakdownReporters map[quota.TargetSrv]quota.UsageBreakdownReporterFunc
 
 	defaultLimits *quota.Map
 
 	targetToSrv *quota.TargetToSrv
+
+	softLimits *softLimitNotifier
 }
 
 func ProvideService(db db.DB, cfg *setting.Cfg) quota.Service {
 	logger := log.New("quota_service")
 	s := service{
-		store:         &sqlStore{db: db, logger: logger},
-		Cfg:           cfg,
-		Logger:        logger,
-		reporters:     make(map[quota.TargetSrv]quota.UsageReporterFunc),
-		defaultLimits: &quota.Map{},
-		targetToSrv:   quota.NewTargetToSrv(),
+		store:              &sqlStore{db: db, logger: logger},
+		Cfg:                cfg,
+		Logger:             logger,
+		reporters:          make(map[quota.TargetSrv]quota.UsageReporterFunc),
+		breakdownReporters: make(map[quota.TargetSrv]quota.UsageBreakdownReporterFunc),
+		defaultLimits:      &quota.Map{},
+		targetToSrv:        quota.NewTargetToSrv(),
+		softLimits:         newSoftLimitNotifier(cfg.Quota.SoftLimits, logger),
 	}
 
 	if s.IsDisabled() {
@@ -145,7 +154,7 @@ func (s *service) GetQuotasByScope(ctx context.Context, scope quota.Scope, id in
 		}
 
 		used, _ := u.Get(item.Tag)
-		q = append(q, quota.QuotaDTO{
+		dto := quota.QuotaDTO{
 			Target:  string(target),
 			Limit:   limit,
 			OrgId:   scopeParams.OrgID,
@@ -153,7 +162,45 @@ func (s *service) GetQuotasByScope(ctx context.Context, scope quota.Scope, id in
 			Used:    used,
 			Service: string(srv),
 			Scope:   string(scope),
-		})
+		}
+		if soft, warning := s.softLimits.warning(target, used, limit); soft >= 0 {
+			dto.SoftLimit = soft
+			dto.Warning = warning
+		}
+		q = append(q, dto)
+	}
+
+	return q, nil
+}
+
+// GetQuotasByScopeWithBreakdown returns the same quotas as GetQuotasByScope and attaches the usage breakdown
+// reported by the services that support it.
+func (s *service) GetQuotasByScopeWithBreakdown(ctx context.Context, scope quota.Scope, id int64) ([]quota.QuotaDTO, error) {
+	q, err := s.GetQuotasByScope(ctx, scope, id)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeParams := quota.ScopeParameters{}
+	if scope == quota.OrgScope {
+		scopeParams.OrgID = id
+	} else if scope == quota.UserScope {
+		scopeParams.UserID = id
+	}
+
+	breakdown, err := s.getUsageBreakdown(ctx, &scopeParams)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range q {
+		tag, err := q[i].Tag()
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := breakdown[tag]; ok {
+			q[i].Breakdown = b
+		}
 	}
 
 	return q, nil
@@ -224,6 +271,7 @@ func (s *service) CheckQuotaReached(ctx context.Context, targetSrv quota.TargetS
 			if !ok {
 				return false, quota.ErrUsageFoundForTarget.Errorf("no usage for target:%s", t)
 			}
+			s.softLimits.observe(t, scopeParams, u, limit)
 			if u >= limit {
 				return true, nil
 			}
@@ -250,6 +298,9 @@ func (s *service) RegisterQuotaReporter(e *quota.NewUsageReporter) error {
 	}
 
 	s.reporters[e.TargetSrv] = e.Reporter
+	if e.BreakdownReporter != nil {
+		s.breakdownReporters[e.TargetSrv] = e.BreakdownReporter
+	}
 
 	for item := range e.DefaultLimits.Iter() {
 		target, err := item.Tag.GetTarget()
@@ -353,6 +404,40 @@ func (s *service) getUsage(ctx context.Context, scopeParams *quota.ScopeParamete
 	return usage, nil
 }
 
+func (s *service) getUsageBreakdown(ctx context.Context, scopeParams *quota.ScopeParameters) (map[quota.Tag][]quota.UsageBreakdown, error) {
+	s.mutex.RLock()
+	reporters := make([]quota.UsageBreakdownReporterFunc, 0, len(s.breakdownReporters))
+	for _, r := range s.breakdownReporters {
+		reporters = append(reporters, r)
+	}
+	s.mutex.RUnlock()
+
+	var mtx sync.Mutex
+	breakdown := make(map[quota.Tag][]quota.UsageBreakdown)
+	g, ctx := errgroup.WithContext(ctx)
+	for _, r := range reporters {
+		r := r
+		g.Go(func() error {
+			b, err := r(ctx, scopeParams)
+			if err != nil {
+				return err
+			}
+			mtx.Lock()
+			defer mtx.Unlock()
+			for tag, items := range b {
+				breakdown[tag] = append(breakdown[tag], items...)
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
 func (s *service) getContext(ctx context.Context) (quota.Context, error) {
 	return quota.FromContext(ctx, s.targetToSrv), nil
 }
@@ -60,6 +60,66 @@ func TestQuotaService(t *testing.T) {
 	})
 }
 
+func TestQuotaServiceBreakdown(t *testing.T) {
+	quotaService := service{
+		store:              &emptyQuotaStore{},
+		reporters:          make(map[quota.TargetSrv]quota.UsageReporterFunc),
+		breakdownReporters: make(map[quota.TargetSrv]quota.UsageBreakdownReporterFunc),
+		defaultLimits:      &quota.Map{},
+		targetToSrv:        quota.NewTargetToSrv(),
+	}
+
+	orgTag, err := quota.NewTag("srv", "target", quota.OrgScope)
+	require.NoError(t, err)
+	limits := &quota.Map{}
+	limits.Set(orgTag, 10)
+
+	breakdown := []quota.UsageBreakdown{{Kind: quota.UsageBreakdownKindFolder, Key: "folder", Used: 3}}
+	err = quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     "srv",
+		DefaultLimits: limits,
+		Reporter: func(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+			u := &quota.Map{}
+			u.Set(orgTag, 3)
+			return u, nil
+		},
+		BreakdownReporter: func(ctx context.Context, scopeParams *quota.ScopeParameters) (map[quota.Tag][]quota.UsageBreakdown, error) {
+			require.Equal(t, int64(1), scopeParams.OrgID)
+			return map[quota.Tag][]quota.UsageBreakdown{orgTag: breakdown}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("breakdown is not included by default", func(t *testing.T) {
+		q, err := quotaService.GetQuotasByScope(context.Background(), quota.OrgScope, 1)
+		require.NoError(t, err)
+		require.Len(t, q, 1)
+		require.Nil(t, q[0].Breakdown)
+	})
+
+	t.Run("breakdown is included when requested", func(t *testing.T) {
+		q, err := quotaService.GetQuotasByScopeWithBreakdown(context.Background(), quota.OrgScope, 1)
+		require.NoError(t, err)
+		require.Len(t, q, 1)
+		require.Equal(t, int64(3), q[0].Used)
+		require.Equal(t, breakdown, q[0].Breakdown)
+	})
+}
+
+type emptyQuotaStore struct{}
+
+func (s *emptyQuotaStore) DeleteByUser(ctx quota.Context, userID int64) error {
+	return nil
+}
+
+func (s *emptyQuotaStore) Get(ctx quota.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	return &quota.Map{}, nil
+}
+
+func (s *emptyQuotaStore) Update(ctx quota.Context, cmd *quota.UpdateQuotaCmd) error {
+	return nil
+}
+
 func TestIntegrationQuotaCommandsAndQueries(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -488,7 +548,7 @@ func setupEnv(t *testing.T, sqlStore *sqlstore.SQLStore, b bus.Bus, quotaService
 	_, err = ngalert.ProvideService(
 		sqlStore.Cfg, featuremgmt.WithFeatures(), nil, nil, routing.NewRouteRegister(), sqlStore, nil, nil, nil, quotaService,
 		secretsService, nil, m, &foldertest.FakeService{}, &acmock.Mock{}, &dashboards.FakeDashboardService{}, nil, b, &acmock.Mock{},
-		annotationstest.NewFakeAnnotationsRepo(), &pluginstore.FakePluginStore{}, tracer, ruleStore, migration.NewFakeMigrationService(t), nil,
+		annotationstest.NewFakeAnnotationsRepo(), &pluginstore.FakePluginStore{}, tracer, ruleStore, migration.NewFakeMigrationService(t), supportbundlestest.NewFakeBundleService(), nil, nil,
 	)
 	require.NoError(t, err)
 	_, err = storesrv.ProvideService(sqlStore, featuremgmt.WithFeatures(), sqlStore.Cfg, quotaService, storesrv.ProvideSystemUsersService())
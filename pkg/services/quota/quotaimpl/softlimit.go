@@ -0,0 +1,164 @@
+package quotaimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const softLimitWebhookTimeout = 10 * time.Second
+
+// SoftLimitEvent is the payload sent to the soft limit webhook when the usage of a quota target crosses its soft limit.
+type SoftLimitEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Service   string    `json:"service"`
+	Target    string    `json:"target"`
+	Scope     string    `json:"scope"`
+	OrgID     int64     `json:"org_id,omitempty"`
+	UserID    int64     `json:"user_id,omitempty"`
+	Used      int64     `json:"used"`
+	SoftLimit int64     `json:"soft_limit"`
+	Limit     int64     `json:"limit"`
+}
+
+// softLimitNotifier keeps track of the quota targets whose soft limit has been crossed
+// so that each crossing is reported only once until the usage goes back below the soft limit.
+type softLimitNotifier struct {
+	cfg    setting.SoftLimitSettings
+	client *http.Client
+	logger log.Logger
+
+	mtx     sync.Mutex
+	crossed map[string]struct{}
+}
+
+func newSoftLimitNotifier(cfg setting.SoftLimitSettings, logger log.Logger) *softLimitNotifier {
+	return &softLimitNotifier{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: softLimitWebhookTimeout},
+		logger:  logger,
+		crossed: make(map[string]struct{}),
+	}
+}
+
+// softLimit returns the soft limit of the target for the given hard limit, or -1 if no soft limit applies.
+func (n *softLimitNotifier) softLimit(target quota.Target, limit int64) int64 {
+	if n == nil || limit <= 0 {
+		return -1
+	}
+	threshold := n.cfg.ThresholdFor(string(target))
+	if threshold <= 0 || threshold >= 100 {
+		return -1
+	}
+	return int64(math.Ceil(float64(limit) * threshold / 100))
+}
+
+// warning returns the warning to be shown to users if the usage crossed the soft limit.
+func (n *softLimitNotifier) warning(target quota.Target, used, limit int64) (int64, string) {
+	soft := n.softLimit(target, limit)
+	if soft < 0 || used < soft {
+		return soft, ""
+	}
+	return soft, fmt.Sprintf("usage of %s (%d) has reached the soft limit of %d, the quota limit is %d", target, used, soft, limit)
+}
+
+// observe records the usage of a target and sends a notification when it crosses the soft limit.
+func (n *softLimitNotifier) observe(tag quota.Tag, scopeParams *quota.ScopeParameters, used, limit int64) {
+	if n == nil {
+		return
+	}
+	target, err := tag.GetTarget()
+	if err != nil {
+		return
+	}
+	soft := n.softLimit(target, limit)
+	if soft < 0 {
+		return
+	}
+
+	key := string(tag)
+	if scopeParams != nil {
+		key = fmt.Sprintf("%s/%d/%d", tag, scopeParams.OrgID, scopeParams.UserID)
+	}
+
+	n.mtx.Lock()
+	_, alreadyCrossed := n.crossed[key]
+	if used < soft {
+		delete(n.crossed, key)
+		n.mtx.Unlock()
+		return
+	}
+	n.crossed[key] = struct{}{}
+	n.mtx.Unlock()
+
+	if alreadyCrossed {
+		return
+	}
+
+	srv, _ := tag.GetSrv()
+	scope, _ := tag.GetScope()
+	evt := SoftLimitEvent{
+		Timestamp: time.Now(),
+		Service:   string(srv),
+		Target:    string(target),
+		Scope:     string(scope),
+		Used:      used,
+		SoftLimit: soft,
+		Limit:     limit,
+	}
+	if scopeParams != nil {
+		if scope == quota.OrgScope {
+			evt.OrgID = scopeParams.OrgID
+		}
+		if scope == quota.UserScope {
+			evt.UserID = scopeParams.UserID
+		}
+	}
+
+	n.logger.Warn("Quota soft limit reached", "target", evt.Target, "scope", evt.Scope, "orgID", evt.OrgID, "userID", evt.UserID, "used", used, "softLimit", soft, "limit", limit)
+	if n.cfg.WebhookURL == "" {
+		return
+	}
+	go func() {
+		if err := n.send(context.Background(), evt); err != nil {
+			n.logger.Error("Failed to send quota soft limit notification", "target", evt.Target, "error", err)
+		}
+	}()
+}
+
+func (n *softLimitNotifier) send(ctx context.Context, evt SoftLimitEvent) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, softLimitWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Grafana")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
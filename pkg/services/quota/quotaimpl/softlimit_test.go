@@ -0,0 +1,90 @@
+package quotaimpl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSoftLimitNotifier(t *testing.T) {
+	t.Run("soft limit is computed from the threshold of the target", func(t *testing.T) {
+		n := newSoftLimitNotifier(setting.SoftLimitSettings{
+			Threshold:        80,
+			TargetThresholds: map[string]float64{"alert_rule": 50},
+		}, log.NewNopLogger())
+
+		require.Equal(t, int64(8), n.softLimit("dashboard", 10))
+		require.Equal(t, int64(5), n.softLimit("alert_rule", 10))
+		require.Equal(t, int64(-1), n.softLimit("dashboard", -1))
+	})
+
+	t.Run("no soft limit if threshold is disabled", func(t *testing.T) {
+		n := newSoftLimitNotifier(setting.SoftLimitSettings{}, log.NewNopLogger())
+
+		soft, warning := n.warning("dashboard", 10, 10)
+		require.Equal(t, int64(-1), soft)
+		require.Empty(t, warning)
+	})
+
+	t.Run("warning is set when usage reaches the soft limit", func(t *testing.T) {
+		n := newSoftLimitNotifier(setting.SoftLimitSettings{Threshold: 80}, log.NewNopLogger())
+
+		soft, warning := n.warning("dashboard", 7, 10)
+		require.Equal(t, int64(8), soft)
+		require.Empty(t, warning)
+
+		soft, warning = n.warning("dashboard", 8, 10)
+		require.Equal(t, int64(8), soft)
+		require.NotEmpty(t, warning)
+	})
+
+	t.Run("webhook is called once per crossing", func(t *testing.T) {
+		events := make(chan SoftLimitEvent, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var evt SoftLimitEvent
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&evt))
+			events <- evt
+		}))
+		t.Cleanup(srv.Close)
+
+		n := newSoftLimitNotifier(setting.SoftLimitSettings{Threshold: 80, WebhookURL: srv.URL}, log.NewNopLogger())
+		tag, err := quota.NewTag("srv", "dashboard", quota.OrgScope)
+		require.NoError(t, err)
+		params := &quota.ScopeParameters{OrgID: 2}
+
+		n.observe(tag, params, 7, 10)
+		n.observe(tag, params, 8, 10)
+		n.observe(tag, params, 9, 10)
+
+		select {
+		case evt := <-events:
+			require.Equal(t, "dashboard", evt.Target)
+			require.Equal(t, int64(2), evt.OrgID)
+			require.Equal(t, int64(8), evt.Used)
+			require.Equal(t, int64(8), evt.SoftLimit)
+			require.Equal(t, int64(10), evt.Limit)
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not called")
+		}
+
+		// going below the soft limit re-arms the notification
+		n.observe(tag, params, 5, 10)
+		n.observe(tag, params, 8, 10)
+
+		select {
+		case evt := <-events:
+			require.Equal(t, int64(8), evt.Used)
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not called after the soft limit was crossed again")
+		}
+		require.Empty(t, events)
+	})
+}
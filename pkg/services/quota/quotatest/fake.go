@@ -20,6 +20,10 @@ func (f *FakeQuotaService) GetQuotasByScope(ctx context.Context, scope quota.Sco
 	return []quota.QuotaDTO{}, nil
 }
 
+func (f *FakeQuotaService) GetQuotasByScopeWithBreakdown(ctx context.Context, scope quota.Scope, id int64) ([]quota.QuotaDTO, error) {
+	return []quota.QuotaDTO{}, nil
+}
+
 func (f *FakeQuotaService) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd) error {
 	return nil
 }
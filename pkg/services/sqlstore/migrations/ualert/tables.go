@@ -24,6 +24,70 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add last_applied column to alert_configuration_history", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_configuration_history"}, &migrator.Column{
 		Name: "last_applied", Type: migrator.DB_Int, Nullable: false, Default: "0",
 	}))
+
+	mg.AddMigration("add default_evaluation_interval_seconds column to ngalert_configuration", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name: "default_evaluation_interval_seconds", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add allowed_evaluation_intervals column to ngalert_configuration", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name: "allowed_evaluation_intervals", Type: migrator.DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("add annotation_schema column to ngalert_configuration", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name: "annotation_schema", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	addAlertRuleLabelPolicyMigrations(mg)
+
+	addAlertRulePauseWindowMigrations(mg)
+
+	mg.AddMigration("add fingerprint_labels column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "fingerprint_labels", Type: migrator.DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("add fingerprint_labels column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "fingerprint_labels", Type: migrator.DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("add partial_results column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "partial_results", Type: migrator.DB_Bool, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add partial_results column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "partial_results", Type: migrator.DB_Bool, Nullable: false, Default: "0",
+	}))
+	addAlertInstanceSnapshotMigrations(mg)
+	addSilenceMetadataMigrations(mg)
+	mg.AddMigration("add label_rewrite_rules column to ngalert_configuration", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name: "label_rewrite_rules", Type: migrator.DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("add group_labels column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "group_labels", Type: migrator.DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("add group_labels column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "group_labels", Type: migrator.DB_Text, Nullable: true,
+	}))
+	addNotificationTemplateVersionMigrations(mg)
+	mg.AddMigration("add updated_by column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "updated_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: true,
+	}))
+	mg.AddMigration("add updated_by column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "updated_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: true,
+	}))
+	mg.AddMigration("add missing_series_evals_to_resolve column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "missing_series_evals_to_resolve", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add missing_series_duration_to_resolve column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "missing_series_duration_to_resolve", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add missing_series_evals_to_resolve column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "missing_series_evals_to_resolve", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add missing_series_duration_to_resolve column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "missing_series_duration_to_resolve", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add missing_series_evals_to_resolve column to ngalert_configuration", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name: "missing_series_evals_to_resolve", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add missing_series_duration_to_resolve column to ngalert_configuration", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name: "missing_series_duration_to_resolve", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	addUsageInsightMigrations(mg)
 	// End of migration log, add new migrations above this line.
 }
 
@@ -569,3 +633,134 @@ func (c extractAlertmanagerConfigurationHistory) Exec(sess *xorm.Session, migrat
 	}
 	return nil
 }
+
+func addAlertRuleLabelPolicyMigrations(mg *migrator.Migrator) {
+	labelPolicy := migrator.Table{
+		Name: "alert_rule_label_policy",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "folder_uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_label_policy table", migrator.NewAddTableMigration(labelPolicy))
+	mg.AddMigration("add unique index in alert_rule_label_policy on org_id and folder_uid columns", migrator.NewAddIndexMigration(labelPolicy, labelPolicy.Indices[0]))
+}
+
+func addAlertRulePauseWindowMigrations(mg *migrator.Migrator) {
+	pauseWindow := migrator.Table{
+		Name: "alert_rule_pause_window",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "starts_at", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "ends_at", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "comment", Type: migrator.DB_Text, Nullable: true},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "ends_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_pause_window table", migrator.NewAddTableMigration(pauseWindow))
+	mg.AddMigration("add index in alert_rule_pause_window on org_id and ends_at columns", migrator.NewAddIndexMigration(pauseWindow, pauseWindow.Indices[0]))
+}
+
+func addAlertInstanceSnapshotMigrations(mg *migrator.Migrator) {
+	snapshot := migrator.Table{
+		Name: "alert_instance_snapshot",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "instances", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "size", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "data", Type: migrator.DB_LongBlob, Nullable: false},
+		},
+	}
+
+	mg.AddMigration("create alert_instance_snapshot table", migrator.NewAddTableMigration(snapshot))
+
+	mg.AddMigration("add node column to alert_instance_snapshot table", migrator.NewAddColumnMigration(snapshot, &migrator.Column{
+		Name: "node", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("add index in alert_instance_snapshot on node column", migrator.NewAddIndexMigration(snapshot, &migrator.Index{
+		Cols: []string{"node"}, Type: migrator.IndexType,
+	}))
+}
+
+func addSilenceMetadataMigrations(mg *migrator.Migrator) {
+	silenceMetadata := migrator.Table{
+		Name: "alert_silence_metadata",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "silence_id", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "created_by_login", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "reference", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "expiry_notified_ends_at", Type: migrator.DB_DateTime, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "silence_id"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "created_by_login"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_silence_metadata table", migrator.NewAddTableMigration(silenceMetadata))
+	mg.AddMigration("add unique index in alert_silence_metadata on org_id and silence_id columns", migrator.NewAddIndexMigration(silenceMetadata, silenceMetadata.Indices[0]))
+	mg.AddMigration("add index in alert_silence_metadata on org_id and created_by_login columns", migrator.NewAddIndexMigration(silenceMetadata, silenceMetadata.Indices[1]))
+}
+
+func addNotificationTemplateVersionMigrations(mg *migrator.Migrator) {
+	templateVersion := migrator.Table{
+		Name: "alert_notification_template_version",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "template", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "created_by_login", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "name", "version"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_template_version table", migrator.NewAddTableMigration(templateVersion))
+	mg.AddMigration("add unique index in alert_notification_template_version on org_id, name and version columns", migrator.NewAddIndexMigration(templateVersion, templateVersion.Indices[0]))
+}
+
+func addUsageInsightMigrations(mg *migrator.Migrator) {
+	usageInsight := migrator.Table{
+		Name: "alert_usage_insight",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "kind", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "last_modified", Type: migrator.DB_DateTime, Nullable: true},
+			{Name: "last_fired", Type: migrator.DB_DateTime, Nullable: true},
+			{Name: "last_notified", Type: migrator.DB_DateTime, Nullable: true},
+			{Name: "notifications", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "kind", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_usage_insight table", migrator.NewAddTableMigration(usageInsight))
+	mg.AddMigration("add unique index in alert_usage_insight on org_id, kind and uid columns", migrator.NewAddIndexMigration(usageInsight, usageInsight.Indices[0]))
+}
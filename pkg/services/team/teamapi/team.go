@@ -127,6 +127,9 @@ func (tapi *TeamAPI) deleteTeamByID(c *contextmodel.ReqContext) response.Respons
 		}
 		return response.Error(http.StatusInternalServerError, "Failed to delete Team", err)
 	}
+
+	// Members of the team lose the permissions granted to the team
+	tapi.ac.ClearOrgPermissionCache(orgID)
 	return response.Success("Team deleted")
 }
 
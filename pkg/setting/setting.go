@@ -308,6 +308,10 @@ type Cfg struct {
 	ResponseLimit                  int64
 	DataProxyRowLimit              int64
 	DataProxyUserAgent             string
+	DataProxyRateLimit             float64
+	DataProxyRateLimitBurst        int
+	DataProxyBreakerThreshold      int
+	DataProxyBreakerOpenTimeout    time.Duration
 
 	// DistributedCache
 	RemoteCacheOptions *RemoteCacheOptions
@@ -2,6 +2,8 @@ package setting
 
 import (
 	"fmt"
+	"math"
+	"time"
 
 	"gopkg.in/ini.v1"
 )
@@ -23,6 +25,10 @@ func readDataProxySettings(iniFile *ini.File, cfg *Cfg) error {
 	cfg.ResponseLimit = dataproxy.Key("response_limit").MustInt64(0)
 	cfg.DataProxyRowLimit = dataproxy.Key("row_limit").MustInt64(defaultDataProxyRowLimit)
 	cfg.DataProxyUserAgent = dataproxy.Key("user_agent").String()
+	cfg.DataProxyRateLimit = dataproxy.Key("rate_limit").MustFloat64(0)
+	cfg.DataProxyRateLimitBurst = dataproxy.Key("rate_limit_burst").MustInt(0)
+	cfg.DataProxyBreakerThreshold = dataproxy.Key("circuit_breaker_failure_threshold").MustInt(0)
+	cfg.DataProxyBreakerOpenTimeout = dataproxy.Key("circuit_breaker_open_timeout").MustDuration(30 * time.Second)
 
 	if cfg.DataProxyUserAgent == "" {
 		cfg.DataProxyUserAgent = fmt.Sprintf("Grafana/%s", BuildVersion)
@@ -32,5 +38,15 @@ func readDataProxySettings(iniFile *ini.File, cfg *Cfg) error {
 		cfg.DataProxyRowLimit = defaultDataProxyRowLimit
 	}
 
+	if cfg.DataProxyRateLimit < 0 {
+		cfg.DataProxyRateLimit = 0
+	}
+	if cfg.DataProxyRateLimitBurst <= 0 {
+		cfg.DataProxyRateLimitBurst = int(math.Ceil(cfg.DataProxyRateLimit))
+	}
+	if cfg.DataProxyBreakerOpenTimeout <= 0 {
+		cfg.DataProxyBreakerOpenTimeout = 30 * time.Second
+	}
+
 	return nil
 }
@@ -1,5 +1,12 @@
 package setting
 
+import (
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
 type OrgQuota struct {
 	User       int64 `target:"org_user"`
 	DataSource int64 `target:"data_source"`
@@ -29,6 +36,26 @@ type QuotaSettings struct {
 	Org     OrgQuota
 	User    UserQuota
 	Global  GlobalQuota
+
+	SoftLimits SoftLimitSettings
+}
+
+// SoftLimitSettings configures the warnings emitted before a quota is reached.
+type SoftLimitSettings struct {
+	// Threshold is the default percentage (0-100) of a limit after which a warning is raised. 0 disables warnings.
+	Threshold float64
+	// TargetThresholds overrides Threshold for specific quota targets.
+	TargetThresholds map[string]float64
+	// WebhookURL, if set, receives a POST request every time a soft limit is crossed.
+	WebhookURL string
+}
+
+// ThresholdFor returns the soft limit percentage configured for the target.
+func (s SoftLimitSettings) ThresholdFor(target string) float64 {
+	if t, ok := s.TargetThresholds[target]; ok {
+		return t
+	}
+	return s.Threshold
 }
 
 func (cfg *Cfg) readQuotaSettings() {
@@ -62,4 +89,23 @@ func (cfg *Cfg) readQuotaSettings() {
 		AlertRule:    quota.Key("global_alert_rule").MustInt64(-1),
 		Correlations: quota.Key("global_correlations").MustInt64(-1),
 	}
+
+	cfg.Quota.SoftLimits = SoftLimitSettings{
+		Threshold:        quota.Key("soft_limit_threshold").MustFloat64(0),
+		TargetThresholds: make(map[string]float64),
+		WebhookURL:       quota.Key("soft_limit_webhook_url").MustString(""),
+	}
+	for _, pair := range util.SplitString(quota.Key("soft_limit_target_thresholds").MustString("")) {
+		target, value, found := strings.Cut(pair, ":")
+		if !found {
+			cfg.Logger.Warn("Invalid quota soft limit threshold, expected <target>:<percentage>", "value", pair)
+			continue
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			cfg.Logger.Warn("Invalid quota soft limit threshold", "target", target, "value", value, "error", err)
+			continue
+		}
+		cfg.Quota.SoftLimits.TargetThresholds[strings.TrimSpace(target)] = threshold
+	}
 }
@@ -20,6 +20,7 @@ const (
 	alertmanagerDefaultGossipInterval     = alertingCluster.DefaultGossipInterval
 	alertmanagerDefaultPushPullInterval   = alertingCluster.DefaultPushPullInterval
 	alertmanagerDefaultConfigPollInterval = time.Minute
+	alertmanagerDefaultSilencesRetention  = 5 * 24 * time.Hour
 	alertmanagerRedisDefaultMaxConns      = 5
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
@@ -54,6 +55,8 @@ const (
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	screenshotsDefaultQueryPreview          = false
+	screenshotsDefaultQueryPreviewWindow    = time.Hour
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -66,6 +69,7 @@ const (
 type UnifiedAlertingSettings struct {
 	AdminConfigPollInterval        time.Duration
 	AlertmanagerConfigPollInterval time.Duration
+	SilencesRetention              time.Duration
 	HAListenAddr                   string
 	HAAdvertiseAddr                string
 	HAPeers                        []string
@@ -97,9 +101,25 @@ type UnifiedAlertingSettings struct {
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	Upgrade                       UnifiedAlertingUpgradeSettings
+	Enrichment                    UnifiedAlertingEnrichmentSettings
+	ExternalSecrets               UnifiedAlertingExternalSecretsSettings
+	StateRemoteWrite              UnifiedAlertingStateRemoteWriteSettings
+	Provisioning                  UnifiedAlertingProvisioningSettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency   int
 	StatePeriodicSaveInterval time.Duration
+	// StateSnapshotInterval is the interval at which a compressed snapshot of the state of all alert instances is saved.
+	// If positive, the state is restored from the latest snapshot at startup. Zero disables snapshots.
+	StateSnapshotInterval time.Duration
+	// IdempotencyKeyTTL is how long the response of a write request with an idempotency key is replayed to
+	// requests with the same key. Zero disables idempotency keys.
+	IdempotencyKeyTTL time.Duration
+	// GroupByHighCardinalityLabels are the labels that notification policies should not group notifications by,
+	// because each of their values creates a notification group.
+	GroupByHighCardinalityLabels map[string]struct{}
+	// RejectHighCardinalityGroupBy makes the Alertmanager configuration API reject the configurations that group
+	// notifications by high cardinality labels, instead of saving them with warnings.
+	RejectHighCardinalityGroupBy bool
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -117,6 +137,10 @@ type UnifiedAlertingScreenshotSettings struct {
 	CaptureTimeout             time.Duration
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	// QueryPreview enables the rendering of a chart of the queries of the alert rule around the time it started
+	// firing, which contact points can opt into attaching to their notifications.
+	QueryPreview       bool
+	QueryPreviewWindow time.Duration
 }
 
 type UnifiedAlertingReservedLabelSettings struct {
@@ -137,6 +161,63 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// WebhookURL is the URL to which the webhook backend sends state transitions.
+	WebhookURL string
+	// WebhookSecret signs the requests of the webhook backend, if it is set.
+	WebhookSecret        string
+	WebhookBatchSize     int
+	WebhookFlushInterval time.Duration
+	WebhookMaxRetries    int
+	WebhookQueueSize     int
+}
+
+// UnifiedAlertingEnrichmentSettings configures the labels and annotations added to alerts
+// before they are routed by the Alertmanager.
+type UnifiedAlertingEnrichmentSettings struct {
+	Enabled bool
+	// Labels and Annotations are static values added to every alert that does not define them yet.
+	Labels      map[string]string
+	Annotations map[string]string
+	// LookupURL is an optional HTTP endpoint queried with the labels of an alert that returns additional annotations.
+	LookupURL      string
+	LookupTimeout  time.Duration
+	LookupCacheTTL time.Duration
+}
+
+// UnifiedAlertingExternalSecretsSettings configures the secret managers that secure settings of contact points can reference.
+type UnifiedAlertingExternalSecretsSettings struct {
+	// Enabled allows secure settings of contact points to reference external secrets.
+	Enabled      bool
+	VaultAddress string
+	VaultToken   string
+	// KubernetesSecretsPath is the directory Kubernetes secrets are mounted in, one sub-directory per secret.
+	KubernetesSecretsPath string
+	// AllowedPathPrefixes are the prefixes of the paths an organization can reference. The {org_id} placeholder
+	// is replaced by the ID of the organization, so that organizations cannot read the secrets of each other.
+	AllowedPathPrefixes []string
+	Timeout             time.Duration
+	CacheTTL            time.Duration
+}
+
+// UnifiedAlertingStateRemoteWriteSettings configures the Prometheus remote-write endpoint the ALERTS and ALERTS_FOR_STATE
+// series of Grafana-managed alerts are written to.
+type UnifiedAlertingStateRemoteWriteSettings struct {
+	Enabled           bool
+	URL               string
+	TenantID          string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	// Interval is how often the buffered series are sent to the endpoint.
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// UnifiedAlertingProvisioningSettings configures how the alerting provisioning files are applied.
+type UnifiedAlertingProvisioningSettings struct {
+	// Watch enables reconciling the provisioning files whenever they change, instead of only applying them at startup.
+	Watch bool
+	// WatchInterval is how often the provisioning directory is checked for changes.
+	WatchInterval time.Duration
 }
 
 type UnifiedAlertingUpgradeSettings struct {
@@ -239,6 +320,13 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	if err != nil {
 		return err
 	}
+	uaCfg.SilencesRetention, err = gtime.ParseDuration(valueAsString(ua, "silences_retention", (alertmanagerDefaultSilencesRetention).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.SilencesRetention <= 0 {
+		return fmt.Errorf("value of setting 'silences_retention' must be positive")
+	}
 	uaCfg.HAPeerTimeout, err = gtime.ParseDuration(valueAsString(ua, "ha_peer_timeout", (alertmanagerDefaultPeerTimeout).String()))
 	if err != nil {
 		return err
@@ -374,6 +462,11 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+	uaCfgScreenshots.QueryPreview = screenshots.Key("capture_query_preview").MustBool(screenshotsDefaultQueryPreview)
+	uaCfgScreenshots.QueryPreviewWindow = screenshots.Key("query_preview_window").MustDuration(screenshotsDefaultQueryPreviewWindow)
+	if uaCfgScreenshots.QueryPreviewWindow <= 0 {
+		return fmt.Errorf("value of setting 'query_preview_window' must be greater than 0")
+	}
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -399,6 +492,15 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		WebhookURL:            stateHistory.Key("webhook_url").MustString(""),
+		WebhookSecret:         stateHistory.Key("webhook_secret").MustString(""),
+		WebhookBatchSize:      stateHistory.Key("webhook_batch_size").MustInt(100),
+		WebhookMaxRetries:     stateHistory.Key("webhook_max_retries").MustInt(3),
+		WebhookQueueSize:      stateHistory.Key("webhook_queue_size").MustInt(10),
+	}
+	uaCfgStateHistory.WebhookFlushInterval, err = gtime.ParseDuration(valueAsString(stateHistory, "webhook_flush_interval", (5 * time.Second).String()))
+	if err != nil {
+		return err
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
@@ -409,6 +511,106 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		return err
 	}
 
+	uaCfg.StateSnapshotInterval, err = gtime.ParseDuration(valueAsString(ua, "state_snapshot_interval", "0s"))
+	if err != nil {
+		return err
+	}
+	if uaCfg.StateSnapshotInterval < 0 {
+		return fmt.Errorf("value of setting 'state_snapshot_interval' must not be negative")
+	}
+
+	uaCfg.IdempotencyKeyTTL, err = gtime.ParseDuration(valueAsString(ua, "idempotency_key_ttl", "10m"))
+	if err != nil {
+		return err
+	}
+	if uaCfg.IdempotencyKeyTTL < 0 {
+		return fmt.Errorf("value of setting 'idempotency_key_ttl' must not be negative")
+	}
+
+	uaCfg.GroupByHighCardinalityLabels = make(map[string]struct{})
+	for _, label := range util.SplitString(ua.Key("group_by_high_cardinality_labels").MustString("")) {
+		uaCfg.GroupByHighCardinalityLabels[label] = struct{}{}
+	}
+	switch action := valueAsString(ua, "group_by_high_cardinality_action", "warn"); action {
+	case "warn":
+	case "reject":
+		uaCfg.RejectHighCardinalityGroupBy = true
+	default:
+		return fmt.Errorf("invalid value %q of setting 'group_by_high_cardinality_action', must be either warn or reject", action)
+	}
+
+	enrichment := iniFile.Section("unified_alerting.enrichment")
+	uaCfgEnrichment := UnifiedAlertingEnrichmentSettings{
+		Enabled:     enrichment.Key("enabled").MustBool(false),
+		Labels:      iniFile.Section("unified_alerting.enrichment.labels").KeysHash(),
+		Annotations: iniFile.Section("unified_alerting.enrichment.annotations").KeysHash(),
+		LookupURL:   enrichment.Key("lookup_url").MustString(""),
+	}
+	uaCfgEnrichment.LookupTimeout, err = gtime.ParseDuration(valueAsString(enrichment, "lookup_timeout", (time.Second * 5).String()))
+	if err != nil {
+		return err
+	}
+	uaCfgEnrichment.LookupCacheTTL, err = gtime.ParseDuration(valueAsString(enrichment, "lookup_cache_ttl", (time.Minute * 5).String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.Enrichment = uaCfgEnrichment
+
+	externalSecrets := iniFile.Section("unified_alerting.external_secrets")
+	uaCfgExternalSecrets := UnifiedAlertingExternalSecretsSettings{
+		Enabled:               externalSecrets.Key("enabled").MustBool(false),
+		VaultAddress:          externalSecrets.Key("vault_address").MustString(""),
+		VaultToken:            externalSecrets.Key("vault_token").MustString(""),
+		KubernetesSecretsPath: externalSecrets.Key("kubernetes_secrets_path").MustString(""),
+		AllowedPathPrefixes:   util.SplitString(externalSecrets.Key("allowed_path_prefixes").MustString("")),
+	}
+	uaCfgExternalSecrets.Timeout, err = gtime.ParseDuration(valueAsString(externalSecrets, "timeout", (time.Second * 5).String()))
+	if err != nil {
+		return err
+	}
+	uaCfgExternalSecrets.CacheTTL, err = gtime.ParseDuration(valueAsString(externalSecrets, "cache_ttl", (time.Minute * 5).String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.ExternalSecrets = uaCfgExternalSecrets
+
+	stateRemoteWrite := iniFile.Section("unified_alerting.state_remote_write")
+	uaCfgStateRemoteWrite := UnifiedAlertingStateRemoteWriteSettings{
+		Enabled:           stateRemoteWrite.Key("enabled").MustBool(false),
+		URL:               stateRemoteWrite.Key("url").MustString(""),
+		TenantID:          stateRemoteWrite.Key("tenant_id").MustString(""),
+		BasicAuthUsername: stateRemoteWrite.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: stateRemoteWrite.Key("basic_auth_password").MustString(""),
+	}
+	uaCfgStateRemoteWrite.Interval, err = gtime.ParseDuration(valueAsString(stateRemoteWrite, "interval", (time.Second * 15).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgStateRemoteWrite.Interval <= 0 {
+		return fmt.Errorf("value of setting 'interval' in section 'unified_alerting.state_remote_write' must be greater than 0")
+	}
+	uaCfgStateRemoteWrite.Timeout, err = gtime.ParseDuration(valueAsString(stateRemoteWrite, "timeout", (time.Second * 10).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgStateRemoteWrite.Enabled && uaCfgStateRemoteWrite.URL == "" {
+		return fmt.Errorf("setting 'url' in section 'unified_alerting.state_remote_write' is required when it is enabled")
+	}
+	uaCfg.StateRemoteWrite = uaCfgStateRemoteWrite
+
+	provisioning := iniFile.Section("unified_alerting.provisioning")
+	uaCfgProvisioning := UnifiedAlertingProvisioningSettings{
+		Watch: provisioning.Key("watch").MustBool(false),
+	}
+	uaCfgProvisioning.WatchInterval, err = gtime.ParseDuration(valueAsString(provisioning, "watch_interval", (time.Second * 30).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgProvisioning.WatchInterval <= 0 {
+		return fmt.Errorf("value of setting 'watch_interval' in section 'unified_alerting.provisioning' must be greater than 0")
+	}
+	uaCfg.Provisioning = uaCfgProvisioning
+
 	upgrade := iniFile.Section("unified_alerting.upgrade")
 	uaCfgUpgrade := UnifiedAlertingUpgradeSettings{
 		CleanUpgrade: upgrade.Key("clean_upgrade").MustBool(false),
@@ -19,6 +19,7 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 	{
 		require.Equal(t, time.Minute, cfg.UnifiedAlerting.AdminConfigPollInterval)
 		require.Equal(t, time.Minute, cfg.UnifiedAlerting.AlertmanagerConfigPollInterval)
+		require.Equal(t, 5*24*time.Hour, cfg.UnifiedAlerting.SilencesRetention)
 		require.Equal(t, 15*time.Second, cfg.UnifiedAlerting.HAPeerTimeout)
 		require.Equal(t, "0.0.0.0:9094", cfg.UnifiedAlerting.HAListenAddr)
 		require.Equal(t, "", cfg.UnifiedAlerting.HAAdvertiseAddr)
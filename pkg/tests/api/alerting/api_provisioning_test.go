@@ -259,6 +259,20 @@ func TestIntegrationProvisioning(t *testing.T) {
 
 			require.Equal(t, 202, resp.StatusCode)
 		})
+
+		t.Run("admin export should be valid in all formats", func(t *testing.T) {
+			adminClient := newAlertingApiClient(grafanaListedAddr, "admin", "admin")
+			for _, format := range exportFormats {
+				t.Run(format, func(t *testing.T) {
+					status, body := adminClient.ExportContactPointsWithStatus(t, format)
+					requireStatusCode(t, http.StatusOK, status, body)
+					export := requireValidExport(t, format, body)
+					if format != "hcl" {
+						require.NotEmpty(t, export.ContactPoints)
+					}
+				})
+			}
+		})
 	})
 
 	t.Run("when provisioning templates", func(t *testing.T) {
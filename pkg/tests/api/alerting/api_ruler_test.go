@@ -236,6 +236,21 @@ func TestIntegrationAlertRulePermissions(t *testing.T) {
 			require.Len(t, export.Groups, 1)
 			require.Equal(t, expected, export.Groups[0])
 		})
+
+		t.Run("Export in all formats", func(t *testing.T) {
+			for _, format := range exportFormats {
+				t.Run(format, func(t *testing.T) {
+					status, exportRaw := apiClient.ExportRulesWithStatus(t, &apimodels.AlertRulesExportParameters{
+						ExportQueryParams: apimodels.ExportQueryParams{Format: format},
+					})
+					requireStatusCode(t, http.StatusOK, status, exportRaw)
+					export := requireValidExport(t, format, exportRaw)
+					if format != "hcl" {
+						require.Len(t, export.Groups, 2)
+					}
+				})
+			}
+		})
 	})
 
 	t.Run("when permissions for folder2 removed", func(t *testing.T) {
@@ -796,6 +811,71 @@ func TestAlertRulePostExport(t *testing.T) {
 	})
 }
 
+func TestIntegrationAlertRuleFolderPermissionsOfTeamsAndUsers(t *testing.T) {
+	testinfra.SQLiteIntegrationTest(t)
+
+	// Setup Grafana and its Database
+	dir, p := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		DisableLegacyAlerting: true,
+		EnableUnifiedAlerting: true,
+		DisableAnonymous:      true,
+		AppModeProduction:     true,
+	})
+
+	grafanaListedAddr, store := testinfra.StartGrafana(t, dir, p)
+
+	createUser(t, store, user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       "admin",
+		Login:          "admin",
+		IsAdmin:        true,
+	})
+	adminClient := newAlertingApiClient(grafanaListedAddr, "admin", "admin")
+
+	adminClient.CreateFolder(t, "folder1", "folder1")
+	postGroupRaw, err := testData.ReadFile(path.Join("test-data", "rulegroup-1-post.json"))
+	require.NoError(t, err)
+	var group apimodels.PostableRuleGroupConfig
+	require.NoError(t, json.Unmarshal(postGroupRaw, &group))
+	_, status, response := adminClient.PostRulesGroupWithStatus(t, "folder1", &group)
+	require.Equalf(t, http.StatusAccepted, status, response)
+
+	// Only the permissions of teams and users grant access to the folder
+	adminClient.SetFolderPermissionForRole(t, "folder1", org.RoleViewer, folderPermissionNone)
+	adminClient.SetFolderPermissionForRole(t, "folder1", org.RoleEditor, folderPermissionNone)
+
+	viewerID := adminClient.CreateUser(t, "viewer", org.RoleViewer)
+	teamID := adminClient.CreateTeam(t, "team", viewerID)
+	viewerClient := newAlertingApiClient(grafanaListedAddr, "viewer", "viewer")
+
+	t.Run("should not see rules of the folder without permissions", func(t *testing.T) {
+		viewerClient.ReloadCachedPermissions(t)
+		rules, status, body := viewerClient.GetAllRulesWithStatus(t)
+		requireStatusCode(t, http.StatusOK, status, string(body))
+		require.NotContains(t, rules, "folder1")
+	})
+
+	t.Run("should see rules of the folder with the permission of the team", func(t *testing.T) {
+		adminClient.SetFolderPermissionForTeam(t, "folder1", teamID, folderPermissionView)
+		viewerClient.ReloadCachedPermissions(t)
+		rules, status, body := viewerClient.GetAllRulesWithStatus(t)
+		requireStatusCode(t, http.StatusOK, status, string(body))
+		require.Contains(t, rules, "folder1")
+
+		_, status, response := viewerClient.PostRulesGroupWithStatus(t, "folder1", &group)
+		require.Equalf(t, http.StatusForbidden, status, response)
+	})
+
+	t.Run("should update rules of the folder with the permission of the user", func(t *testing.T) {
+		adminClient.SetFolderPermissionForUser(t, "folder1", viewerID, folderPermissionEdit)
+		viewerClient.ReloadCachedPermissions(t)
+		current := viewerClient.GetRulesGroup(t, "folder1", group.Name)
+		postable := convertGettableRuleGroupToPostable(current.GettableRuleGroupConfig)
+		_, status, response := viewerClient.PostRulesGroupWithStatus(t, "folder1", &postable)
+		require.Equalf(t, http.StatusAccepted, status, response)
+	})
+}
+
 func TestIntegrationAlertRuleConflictingTitle(t *testing.T) {
 	testinfra.SQLiteIntegrationTest(t)
 
@@ -14,15 +14,19 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/api"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/folder"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -288,6 +292,104 @@ func (a apiClient) CreateFolder(t *testing.T, uID string, title string, parentUI
 	a.ReloadCachedPermissions(t)
 }
 
+// Folder permissions of the access control API
+const (
+	folderPermissionView  = "View"
+	folderPermissionEdit  = "Edit"
+	folderPermissionAdmin = "Admin"
+	// folderPermissionNone removes the permission
+	folderPermissionNone = ""
+)
+
+// SetFolderPermissionForRole sets the permission of a basic role, such as org.RoleViewer, on the folder.
+// The client must be allowed to change folder permissions, and the cache of other users is refreshed on their next request with ReloadCachedPermissions.
+func (a apiClient) SetFolderPermissionForRole(t *testing.T, folderUID string, role org.RoleType, permission string) {
+	t.Helper()
+	a.setFolderPermission(t, folderUID, fmt.Sprintf("builtInRoles/%s", role), permission)
+}
+
+// SetFolderPermissionForUser sets the permission of the user on the folder
+func (a apiClient) SetFolderPermissionForUser(t *testing.T, folderUID string, userID int64, permission string) {
+	t.Helper()
+	a.setFolderPermission(t, folderUID, fmt.Sprintf("users/%d", userID), permission)
+}
+
+// SetFolderPermissionForTeam sets the permission of the team on the folder
+func (a apiClient) SetFolderPermissionForTeam(t *testing.T, folderUID string, teamID int64, permission string) {
+	t.Helper()
+	a.setFolderPermission(t, folderUID, fmt.Sprintf("teams/%d", teamID), permission)
+}
+
+func (a apiClient) setFolderPermission(t *testing.T, folderUID string, target string, permission string) {
+	t.Helper()
+	blob, err := json.Marshal(map[string]string{"permission": permission})
+	require.NoError(t, err)
+
+	u := fmt.Sprintf("%s/api/access-control/folders/%s/%s", a.url, folderUID, target)
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(blob))
+	require.NoError(t, err)
+	req.Header.Add("Content-Type", "application/json")
+	_, status, body := sendRequest[any](t, req, http.StatusOK)
+	requireStatusCode(t, http.StatusOK, status, body)
+	a.ReloadCachedPermissions(t)
+}
+
+// CreateUser creates a user with the role in the main organization, and returns its ID. The password of the user is its login.
+// The client must be a server admin.
+func (a apiClient) CreateUser(t *testing.T, login string, role org.RoleType) int64 {
+	t.Helper()
+	blob, err := json.Marshal(map[string]any{
+		"login":    login,
+		"email":    fmt.Sprintf("%s@localhost", login),
+		"password": login,
+		"orgId":    1,
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/admin/users", a.url), bytes.NewReader(blob))
+	require.NoError(t, err)
+	req.Header.Add("Content-Type", "application/json")
+	created, status, body := sendRequest[struct {
+		ID int64 `json:"id"`
+	}](t, req, http.StatusOK)
+	requireStatusCode(t, http.StatusOK, status, body)
+
+	blob, err = json.Marshal(map[string]any{"role": role})
+	require.NoError(t, err)
+	req, err = http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/orgs/1/users/%d", a.url, created.ID), bytes.NewReader(blob))
+	require.NoError(t, err)
+	req.Header.Add("Content-Type", "application/json")
+	_, status, body = sendRequest[any](t, req, http.StatusOK)
+	requireStatusCode(t, http.StatusOK, status, body)
+	return created.ID
+}
+
+// CreateTeam creates a team with the members in the organization of the client, and returns its ID
+func (a apiClient) CreateTeam(t *testing.T, name string, memberIDs ...int64) int64 {
+	t.Helper()
+	blob, err := json.Marshal(map[string]any{"name": name})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/teams", a.url), bytes.NewReader(blob))
+	require.NoError(t, err)
+	req.Header.Add("Content-Type", "application/json")
+	created, status, body := sendRequest[struct {
+		TeamID int64 `json:"teamId"`
+	}](t, req, http.StatusOK)
+	requireStatusCode(t, http.StatusOK, status, body)
+
+	for _, userID := range memberIDs {
+		blob, err := json.Marshal(map[string]any{"userId": userID})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/teams/%d/members", a.url, created.TeamID), bytes.NewReader(blob))
+		require.NoError(t, err)
+		req.Header.Add("Content-Type", "application/json")
+		_, status, body := sendRequest[any](t, req, http.StatusOK)
+		requireStatusCode(t, http.StatusOK, status, body)
+	}
+	return created.TeamID
+}
+
 func (a apiClient) GetOrgQuotaLimits(t *testing.T, orgID int64) (int64, int64) {
 	t.Helper()
 
@@ -594,6 +696,57 @@ func (a apiClient) ExportRulesWithStatus(t *testing.T, params *apimodels.AlertRu
 	return resp.StatusCode, string(b)
 }
 
+// ExportContactPointsWithStatus exports all contact points in the format
+func (a apiClient) ExportContactPointsWithStatus(t *testing.T, format string) (int, string) {
+	t.Helper()
+	u, err := url.Parse(fmt.Sprintf("%s/api/v1/provisioning/contact-points/export", a.url))
+	require.NoError(t, err)
+	u.RawQuery = url.Values{"format": []string{format}}.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	require.NoError(t, err)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(b)
+}
+
+// exportFormats are the formats supported by the export endpoints. Tests of exports iterate over them,
+// so that a new format is covered by adding it here and to requireValidExport.
+var exportFormats = []string{"json", "yaml", "hcl"}
+
+// requireValidExport checks that the export is syntactically valid in the format. Exports in json and yaml
+// must also match the schema of the file provisioning, and are returned decoded.
+func requireValidExport(t *testing.T, format string, export string) apimodels.AlertingFileExport {
+	t.Helper()
+	var result apimodels.AlertingFileExport
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(strings.NewReader(export))
+		decoder.DisallowUnknownFields()
+		require.NoErrorf(t, decoder.Decode(&result), "invalid json export: %s", export)
+	case "yaml":
+		decoder := yaml.NewDecoder(strings.NewReader(export))
+		decoder.KnownFields(true)
+		require.NoErrorf(t, decoder.Decode(&result), "invalid yaml export: %s", export)
+	case "hcl":
+		_, diags := hclsyntax.ParseConfig([]byte(export), "export.tf", hcl.InitialPos)
+		require.Falsef(t, diags.HasErrors(), "invalid hcl export: %s\n%s", diags.Error(), export)
+		return result
+	default:
+		require.Failf(t, "unknown export format", "format %s", format)
+	}
+	require.NotZero(t, result.APIVersion, "export must have an API version")
+	return result
+}
+
 func (a apiClient) SubmitRuleForBacktesting(t *testing.T, config apimodels.BacktestConfig) (int, string) {
 	t.Helper()
 	buf := bytes.Buffer{}
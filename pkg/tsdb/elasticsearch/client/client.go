@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/tsdb/elasticsearch/instrumentation"
 )
 
 // Used in logging to mark a stage
@@ -39,6 +41,11 @@ type DatasourceInfo struct {
 	MaxConcurrentShardRequests int64
 	IncludeFrozen              bool
 	XPack                      bool
+	EnableScriptedMetric       bool
+	// MaxResponseSize is the maximum size of the body of a multi search response in bytes, or 0 if it is not limited.
+	MaxResponseSize int64
+	// QueryCacheTTL is how long the responses of queries are cached, or 0 if they are not cached.
+	QueryCacheTTL time.Duration
 }
 
 type ConfiguredFields struct {
@@ -50,7 +57,9 @@ type ConfiguredFields struct {
 // Client represents a client which can interact with elasticsearch api
 type Client interface {
 	GetConfiguredFields() ConfiguredFields
+	ScriptedMetricEnabled() bool
 	ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error)
+	TranslateMultisearch(r *MultiSearchRequest) (*TranslatedMultiSearchRequest, error)
 	MultiSearch() *MultiSearchRequestBuilder
 }
 
@@ -95,6 +104,11 @@ func (c *baseClientImpl) GetConfiguredFields() ConfiguredFields {
 	return c.configuredFields
 }
 
+// ScriptedMetricEnabled returns whether the data source allows scripted metric aggregations.
+func (c *baseClientImpl) ScriptedMetricEnabled() bool {
+	return c.ds.EnableScriptedMetric
+}
+
 type multiRequest struct {
 	header   map[string]any
 	body     any
@@ -133,7 +147,8 @@ func (c *baseClientImpl) encodeBatchRequests(requests []*multiRequest) ([]byte,
 	}
 
 	elapsed := time.Since(start)
-	c.logger.Debug("Completed encoding of batch requests to json", "duration", elapsed)
+	instrumentation.UpdateClientStageDurationSeconds(c.ctx, instrumentation.StageEncode, elapsed, "ok")
+	c.logger.Debug("Completed encoding of batch requests to json", "duration", elapsed, "size", payload.Len())
 
 	return payload.Bytes(), nil
 }
@@ -183,6 +198,8 @@ func (c *baseClientImpl) ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearch
 		span.End()
 	}()
 
+	indices := strings.Join(c.indices, ",")
+	queryTypes := searchQueryTypes(r.Requests)
 	start := time.Now()
 	clientRes, err := c.executeBatchRequest("_msearch", queryParams, multiRequests)
 	if err != nil {
@@ -190,13 +207,15 @@ func (c *baseClientImpl) ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearch
 		if errors.Is(err, context.Canceled) {
 			status = "cancelled"
 		}
-		lp := []any{"error", err, "status", status, "duration", time.Since(start), "stage", StageDatabaseRequest}
+		instrumentation.UpdateClientStageDurationSeconds(c.ctx, instrumentation.StageNetwork, time.Since(start), status)
+		lp := []any{"error", err, "status", status, "duration", time.Since(start), "stage", StageDatabaseRequest, "indices", indices, "queryTypes", queryTypes}
 		if clientRes != nil {
 			lp = append(lp, "statusCode", clientRes.StatusCode)
 		}
 		c.logger.Error("Error received from Elasticsearch", lp...)
 		return nil, err
 	}
+	instrumentation.UpdateClientStageDurationSeconds(c.ctx, instrumentation.StageNetwork, time.Since(start), "ok")
 	res := clientRes
 	defer func() {
 		if err := res.Body.Close(); err != nil {
@@ -204,11 +223,10 @@ func (c *baseClientImpl) ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearch
 		}
 	}()
 
-	c.logger.Info("Response received from Elasticsearch", "status", "ok", "statusCode", res.StatusCode, "contentLength", res.ContentLength, "duration", time.Since(start), "stage", StageDatabaseRequest)
+	c.logger.Info("Response received from Elasticsearch", "status", "ok", "statusCode", res.StatusCode, "contentLength", res.ContentLength, "duration", time.Since(start), "stage", StageDatabaseRequest, "indices", indices, "queryTypes", queryTypes)
 
 	start = time.Now()
 	var msr MultiSearchResponse
-	dec := json.NewDecoder(res.Body)
 	_, resSpan := c.tracer.Start(c.ctx, "datasource.elasticsearch.queryData.executeMultisearch.decodeResponse")
 	defer func() {
 		if err != nil {
@@ -217,19 +235,97 @@ func (c *baseClientImpl) ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearch
 		}
 		resSpan.End()
 	}()
-	err = dec.Decode(&msr)
+	err = decodeMultiSearchResponse(newMaxSizeReader(res.Body, c.ds.MaxResponseSize), &msr)
 	if err != nil {
-		c.logger.Error("Failed to decode response from Elasticsearch", "error", err, "duration", time.Since(start))
+		instrumentation.UpdateClientStageDurationSeconds(c.ctx, instrumentation.StageDecode, time.Since(start), "error")
+		c.logger.Error("Failed to decode response from Elasticsearch", "error", err, "duration", time.Since(start), "stage", StageParseResponse)
 		return nil, err
 	}
+	instrumentation.UpdateClientStageDurationSeconds(c.ctx, instrumentation.StageDecode, time.Since(start), "ok")
 
-	c.logger.Debug("Completed decoding of response from Elasticsearch", "duration", time.Since(start))
+	c.logger.Debug("Completed decoding of response from Elasticsearch", "duration", time.Since(start), "stage", StageParseResponse)
+	for i, sr := range msr.Responses {
+		if i >= len(r.Requests) {
+			break
+		}
+		c.logger.Debug("Search executed by Elasticsearch", "index", indices, "queryType", r.Requests[i].QueryType, "took", time.Duration(sr.Took)*time.Millisecond, "failed", sr.Error != nil)
+	}
 
 	msr.Status = res.StatusCode
+	msr.Warnings = parseWarningHeaders(res.Header.Values("Warning"))
+	if len(msr.Warnings) > 0 {
+		c.logger.Warn("Elasticsearch returned warnings", "warnings", msr.Warnings)
+	}
 
 	return &msr, nil
 }
 
+// searchQueryTypes returns the query types of the search requests, e.g. "logs,time_series".
+func searchQueryTypes(requests []*SearchRequest) string {
+	types := make([]string, 0, len(requests))
+	for _, r := range requests {
+		if r.QueryType != "" && !slices.Contains(types, r.QueryType) {
+			types = append(types, r.QueryType)
+		}
+	}
+	return strings.Join(types, ",")
+}
+
+// TranslateMultisearch returns the HTTP request that ExecuteMultisearch would send, with the intervals interpolated.
+func (c *baseClientImpl) TranslateMultisearch(r *MultiSearchRequest) (*TranslatedMultiSearchRequest, error) {
+	body, err := c.encodeBatchRequests(c.createMultiSearchRequests(r.Requests))
+	if err != nil {
+		return nil, err
+	}
+	return &TranslatedMultiSearchRequest{
+		Method: http.MethodPost,
+		Path:   "_msearch?" + c.getMultiSearchQueryParameters(),
+		Body:   string(body),
+	}, nil
+}
+
+// parseWarningHeaders returns the messages of Warning headers. Elasticsearch uses the format defined in RFC 7234,
+// e.g. `299 Elasticsearch-7.17.0-abc "[types removal] Specifying types in search requests is deprecated."`.
+// Duplicate messages are returned once.
+func parseWarningHeaders(headers []string) []string {
+	var warnings []string
+	seen := make(map[string]struct{})
+	for _, h := range headers {
+		msg := h
+		if start := strings.IndexByte(h, '"'); start >= 0 {
+			msg = unquoteWarning(h[start+1:])
+		}
+		if msg == "" {
+			continue
+		}
+		if _, ok := seen[msg]; ok {
+			continue
+		}
+		seen[msg] = struct{}{}
+		warnings = append(warnings, msg)
+	}
+	return warnings
+}
+
+// unquoteWarning returns the text up to the closing quote, with escaped characters unescaped.
+func unquoteWarning(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
 func (c *baseClientImpl) createMultiSearchRequests(searchRequests []*SearchRequest) []*multiRequest {
 	multiRequests := []*multiRequest{}
 
@@ -36,6 +36,7 @@ func TestClient_ExecuteMultisearch(t *testing.T) {
 				"responses": [
 					{
 						"hits": {	"hits": [], "max_score": 0,	"total": { "value": 4656, "relation": "eq"}	},
+						"took": 12,
 						"status": 200
 					}
 				]
@@ -108,9 +109,36 @@ func TestClient_ExecuteMultisearch(t *testing.T) {
 
 		assert.Equal(t, "15s", jBody.GetPath("aggs", "2", "date_histogram", "fixed_interval").MustString())
 
+		assert.Empty(t, jBody.Get("QueryType").Interface(), "the query type must not be sent")
+
 		assert.Equal(t, 200, res.Status)
 		require.Len(t, res.Responses, 1)
+		assert.Equal(t, int64(12), res.Responses[0].Took)
+	})
+}
+
+func TestSearchQueryTypes(t *testing.T) {
+	require.Equal(t, "logs,time_series", searchQueryTypes([]*SearchRequest{
+		{QueryType: "logs"},
+		{QueryType: "time_series"},
+		{},
+		{QueryType: "logs"},
+	}))
+	require.Empty(t, searchQueryTypes(nil))
+}
+
+func TestParseWarningHeaders(t *testing.T) {
+	warnings := parseWarningHeaders([]string{
+		`299 Elasticsearch-7.17.0-abc "[types removal] Specifying types in search requests is deprecated."`,
+		`299 Elasticsearch-7.17.0-abc "field \"old\" is deprecated" "Mon, 01 Jan 2024 00:00:00 GMT"`,
+		`299 Elasticsearch-7.17.0-abc "[types removal] Specifying types in search requests is deprecated."`,
+		`no quoted text`,
 	})
+	require.Equal(t, []string{
+		"[types removal] Specifying types in search requests is deprecated.",
+		`field "old" is deprecated`,
+		"no quoted text",
+	}, warnings)
 }
 
 func TestClient_Index(t *testing.T) {
@@ -222,6 +250,7 @@ func createMultisearchForTest(t *testing.T, c Client) (*MultiSearchRequest, erro
 
 	msb := c.MultiSearch()
 	s := msb.Search(15 * time.Second)
+	s.QueryType("time_series")
 	s.Agg().DateHistogram("2", "@timestamp", func(a *DateHistogramAgg, ab AggBuilder) {
 		a.FixedInterval = "$__interval"
 
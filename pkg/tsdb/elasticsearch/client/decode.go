@@ -0,0 +1,114 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+)
+
+// maxSizeReader returns an error once more than max bytes are read, so that a huge response of Elasticsearch fails
+// the query instead of exhausting the memory of Grafana.
+type maxSizeReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func newMaxSizeReader(r io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		return r
+	}
+	return &maxSizeReader{r: r, max: max}
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	if m.read > m.max {
+		return 0, m.err()
+	}
+	// read at most one byte more than the maximum, to detect responses that exceed it
+	if rest := m.max - m.read + 1; int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	n, err := m.r.Read(p)
+	m.read += int64(n)
+	if m.read > m.max {
+		return 0, m.err()
+	}
+	return n, err
+}
+
+func (m *maxSizeReader) err() error {
+	return errorsource.DownstreamError(fmt.Errorf("the response of Elasticsearch is larger than the maximum of %d MB, reduce the time range or increase the interval of the query", m.max/(1024*1024)), false)
+}
+
+// decodeMultiSearchResponse decodes a multi search response one search response at a time. Unlike decoding the
+// whole body at once, it does not buffer the body of the response, so only the decoded search responses are kept
+// in memory.
+func decodeMultiSearchResponse(r io.Reader, msr *MultiSearchResponse) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case "responses":
+			if err := decodeSearchResponses(dec, msr); err != nil {
+				return err
+			}
+		case "error":
+			if err := dec.Decode(&msr.Error); err != nil {
+				return err
+			}
+		case "status":
+			if err := dec.Decode(&msr.Status); err != nil {
+				return err
+			}
+		default:
+			// skip the other fields, e.g. took
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func decodeSearchResponses(dec *json.Decoder, msr *MultiSearchResponse) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		msr.Responses = nil
+		return nil
+	}
+	if d, ok := t.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("unexpected %v in the responses of the multi search response", t)
+	}
+	for dec.More() {
+		var sr *SearchResponse
+		if err := dec.Decode(&sr); err != nil {
+			return err
+		}
+		msr.Responses = append(msr.Responses, sr)
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("unexpected %v in the multi search response, expected %v", t, delim)
+	}
+	return nil
+}
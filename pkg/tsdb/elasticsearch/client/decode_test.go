@@ -0,0 +1,69 @@
+package es
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMultiSearchResponse(t *testing.T) {
+	t.Run("should decode the search responses", func(t *testing.T) {
+		var msr MultiSearchResponse
+		err := decodeMultiSearchResponse(strings.NewReader(`{
+			"took": 30,
+			"responses": [
+				{"took": 12, "aggregations": {"2": {"buckets": [{"key": 1, "doc_count": 3}]}}, "status": 200},
+				{"error": {"type": "index_not_found_exception", "reason": "no such index"}, "status": 404}
+			],
+			"status": 200
+		}`), &msr)
+		require.NoError(t, err)
+
+		require.Equal(t, 200, msr.Status)
+		require.Len(t, msr.Responses, 2)
+		require.Equal(t, int64(12), msr.Responses[0].Took)
+		require.Contains(t, msr.Responses[0].Aggregations, "2")
+		require.Equal(t, "no such index", msr.Responses[1].Error["reason"])
+	})
+
+	t.Run("should decode the error of the whole request", func(t *testing.T) {
+		var msr MultiSearchResponse
+		err := decodeMultiSearchResponse(strings.NewReader(`{"error": "detailed errors are disabled", "responses": null}`), &msr)
+		require.NoError(t, err)
+		require.Equal(t, "detailed errors are disabled", msr.Error)
+		require.Empty(t, msr.Responses)
+	})
+
+	t.Run("should return an error for invalid responses", func(t *testing.T) {
+		for _, body := range []string{`Access to the database is forbidden`, `[]`, `{"responses": {}}`, `{"responses": [{"took": 1}`} {
+			var msr MultiSearchResponse
+			require.Errorf(t, decodeMultiSearchResponse(strings.NewReader(body), &msr), "body: %s", body)
+		}
+	})
+}
+
+func TestMaxSizeReader(t *testing.T) {
+	body := strings.Repeat("a", 2*1024*1024)
+
+	t.Run("should read responses up to the maximum size", func(t *testing.T) {
+		b, err := io.ReadAll(newMaxSizeReader(strings.NewReader(body), int64(len(body))))
+		require.NoError(t, err)
+		require.Equal(t, body, string(b))
+	})
+
+	t.Run("should fail responses larger than the maximum size", func(t *testing.T) {
+		_, err := io.ReadAll(newMaxSizeReader(strings.NewReader(body), 1024*1024))
+		require.ErrorContains(t, err, "larger than the maximum of 1 MB")
+		require.Equal(t, backend.ErrorSourceDownstream, errorsource.Response(err).ErrorSource)
+	})
+
+	t.Run("should not limit the size if the maximum is not set", func(t *testing.T) {
+		b, err := io.ReadAll(newMaxSizeReader(strings.NewReader(body), 0))
+		require.NoError(t, err)
+		require.Len(t, b, len(body))
+	})
+}
@@ -30,6 +30,15 @@ var newIndexPattern = func(interval string, pattern string) (indexPattern, error
 	return newDynamicIndexPattern(interval, pattern)
 }
 
+// GetIndices returns the indices of the data source that cover the time range.
+func GetIndices(ds *DatasourceInfo, timeRange backend.TimeRange) ([]string, error) {
+	ip, err := newIndexPattern(ds.Interval, ds.Database)
+	if err != nil {
+		return nil, err
+	}
+	return ip.GetIndices(timeRange)
+}
+
 type staticIndexPattern struct {
 	indexName string
 }
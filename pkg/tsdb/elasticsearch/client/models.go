@@ -14,6 +14,8 @@ type SearchRequest struct {
 	Query       *Query
 	Aggs        AggArray
 	CustomProps map[string]interface{}
+	// QueryType is the type of the query of the request, used in logs. It is not sent to Elasticsearch.
+	QueryType string
 }
 
 // MarshalJSON returns the JSON encoding of the request.
@@ -48,6 +50,8 @@ type SearchResponse struct {
 	Error        map[string]interface{} `json:"error"`
 	Aggregations map[string]interface{} `json:"aggregations"`
 	Hits         *SearchResponseHits    `json:"hits"`
+	// Took is the time Elasticsearch took to execute the search in milliseconds.
+	Took int64 `json:"took"`
 }
 
 // MultiSearchRequest represents a multi search request
@@ -55,10 +59,23 @@ type MultiSearchRequest struct {
 	Requests []*SearchRequest
 }
 
+// TranslatedMultiSearchRequest represents the HTTP request of a multi search request. Its method, path and body can
+// be pasted into Kibana Dev Tools.
+type TranslatedMultiSearchRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Body is the newline delimited JSON body of the request.
+	Body string `json:"body"`
+}
+
 // MultiSearchResponse represents a multi search response
 type MultiSearchResponse struct {
 	Status    int               `json:"status,omitempty"`
 	Responses []*SearchResponse `json:"responses"`
+	// Error is the error of the whole request. It is a string if detailed errors are disabled in Elasticsearch.
+	Error any `json:"error,omitempty"`
+	// Warnings are the messages of the Warning headers of the response, e.g. the use of deprecated features.
+	Warnings []string `json:"-"`
 }
 
 // Query represents a query
@@ -223,6 +240,16 @@ type DateHistogramAgg struct {
 	TimeZone         string          `json:"time_zone,omitempty"`
 }
 
+// AutoDateHistogramAgg represents an auto date histogram aggregation
+type AutoDateHistogramAgg struct {
+	Field           string  `json:"field"`
+	Buckets         int     `json:"buckets"`
+	MinimumInterval string  `json:"minimum_interval,omitempty"`
+	Missing         *string `json:"missing,omitempty"`
+	Format          string  `json:"format"`
+	TimeZone        string  `json:"time_zone,omitempty"`
+}
+
 // GetCalendarIntervals provides the list of intervals used for building calendar bucketAgg
 func GetCalendarIntervals() []string {
 	return []string{"1w", "1M", "1q", "1y"}
@@ -233,6 +260,16 @@ type FiltersAggregation struct {
 	Filters map[string]interface{} `json:"filters"`
 }
 
+// FilterAggregation represents a filter aggregation
+type FilterAggregation struct {
+	Filter Filter
+}
+
+// MarshalJSON returns the JSON encoding of the filter aggregation, which is its filter.
+func (a *FilterAggregation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Filter)
+}
+
 // TermsAggregation represents a terms aggregation
 type TermsAggregation struct {
 	Field       string                 `json:"field"`
@@ -247,6 +284,12 @@ type NestedAggregation struct {
 	Path string `json:"path"`
 }
 
+// RandomSamplerAggregation represents a random sampler aggregation
+type RandomSamplerAggregation struct {
+	Probability float64 `json:"probability"`
+	Seed        *int    `json:"seed,omitempty"`
+}
+
 // ExtendedBounds represents extended bounds
 type ExtendedBounds struct {
 	Min int64 `json:"min"`
@@ -259,6 +302,29 @@ type GeoHashGridAggregation struct {
 	Precision int    `json:"precision"`
 }
 
+// IPRangeAggregation represents an ip range aggregation
+type IPRangeAggregation struct {
+	Field  string    `json:"field"`
+	Ranges []IPRange `json:"ranges"`
+}
+
+// IPRange represents a range of an ip range aggregation, defined either by bounds or by a CIDR mask
+type IPRange struct {
+	Key  string `json:"key,omitempty"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	Mask string `json:"mask,omitempty"`
+}
+
+// IPPrefixAggregation represents an ip prefix aggregation
+type IPPrefixAggregation struct {
+	Field              string `json:"field"`
+	PrefixLength       int    `json:"prefix_length"`
+	IsIPv6             bool   `json:"is_ipv6,omitempty"`
+	AppendPrefixLength bool   `json:"append_prefix_length"`
+	MinDocCount        *int   `json:"min_doc_count,omitempty"`
+}
+
 // MetricAggregation represents a metric aggregation
 type MetricAggregation struct {
 	Type     string
@@ -10,6 +10,8 @@ const (
 	HighlightPostTagsString = "@/HIGHLIGHT@"
 	HighlightFragmentSize   = 2147483647
 	DefaultGeoHashPrecision = 3
+	DefaultIPv4PrefixLength = 24
+	DefaultIPv6PrefixLength = 64
 )
 
 // SearchRequestBuilder represents a builder which can build a search request
@@ -17,6 +19,8 @@ type SearchRequestBuilder struct {
 	interval time.Duration
 	index    string
 	size     int
+	// queryType is the type of the query of the request, used in logs
+	queryType string
 	// Currently sort is map, but based in examples it should be an array https://www.elastic.co/guide/en/elasticsearch/reference/current/sort-search-results.html
 	sort         map[string]any
 	queryBuilder *QueryBuilder
@@ -43,6 +47,7 @@ func (b *SearchRequestBuilder) Build() (*SearchRequest, error) {
 		Size:        b.size,
 		Sort:        b.sort,
 		CustomProps: b.customProps,
+		QueryType:   b.queryType,
 	}
 
 	if b.queryBuilder != nil {
@@ -68,6 +73,12 @@ func (b *SearchRequestBuilder) Build() (*SearchRequest, error) {
 	return &sr, nil
 }
 
+// QueryType sets the type of the query of the search request, e.g. logs or time_series. It is not sent to Elasticsearch.
+func (b *SearchRequestBuilder) QueryType(queryType string) *SearchRequestBuilder {
+	b.queryType = queryType
+	return b
+}
+
 // Size sets the size of the search request
 func (b *SearchRequestBuilder) Size(size int) *SearchRequestBuilder {
 	b.size = size
@@ -297,10 +308,15 @@ func (b *FilterQueryBuilder) AddQueryStringFilter(querystring string, analyseWil
 type AggBuilder interface {
 	Histogram(key, field string, fn func(a *HistogramAgg, b AggBuilder)) AggBuilder
 	DateHistogram(key, field string, fn func(a *DateHistogramAgg, b AggBuilder)) AggBuilder
+	AutoDateHistogram(key, field string, fn func(a *AutoDateHistogramAgg, b AggBuilder)) AggBuilder
 	Terms(key, field string, fn func(a *TermsAggregation, b AggBuilder)) AggBuilder
 	Nested(key, path string, fn func(a *NestedAggregation, b AggBuilder)) AggBuilder
 	Filters(key string, fn func(a *FiltersAggregation, b AggBuilder)) AggBuilder
+	Filter(key string, fn func(a *FilterAggregation, b AggBuilder)) AggBuilder
+	RandomSampler(key string, probability float64, fn func(a *RandomSamplerAggregation, b AggBuilder)) AggBuilder
 	GeoHashGrid(key, field string, fn func(a *GeoHashGridAggregation, b AggBuilder)) AggBuilder
+	IPRange(key, field string, fn func(a *IPRangeAggregation, b AggBuilder)) AggBuilder
+	IPPrefix(key, field string, fn func(a *IPPrefixAggregation, b AggBuilder)) AggBuilder
 	Metric(key, metricType, field string, fn func(a *MetricAggregation)) AggBuilder
 	Pipeline(key, pipelineType string, bucketPath any, fn func(a *PipelineAggregation)) AggBuilder
 	Build() (AggArray, error)
@@ -381,6 +397,26 @@ func (b *aggBuilderImpl) DateHistogram(key, field string, fn func(a *DateHistogr
 	return b
 }
 
+func (b *aggBuilderImpl) AutoDateHistogram(key, field string, fn func(a *AutoDateHistogramAgg, b AggBuilder)) AggBuilder {
+	innerAgg := &AutoDateHistogramAgg{
+		Field: field,
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "auto_date_histogram",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
 const termsOrderTerm = "_term"
 
 func (b *aggBuilderImpl) Terms(key, field string, fn func(a *TermsAggregation, b AggBuilder)) AggBuilder {
@@ -450,6 +486,43 @@ func (b *aggBuilderImpl) Filters(key string, fn func(a *FiltersAggregation, b Ag
 	return b
 }
 
+func (b *aggBuilderImpl) Filter(key string, fn func(a *FilterAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &FilterAggregation{}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "filter",
+		Aggregation: innerAgg,
+	})
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
+func (b *aggBuilderImpl) RandomSampler(key string, probability float64, fn func(a *RandomSamplerAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &RandomSamplerAggregation{
+		Probability: probability,
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "random_sampler",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
 func (b *aggBuilderImpl) GeoHashGrid(key, field string, fn func(a *GeoHashGridAggregation, b AggBuilder)) AggBuilder {
 	innerAgg := &GeoHashGridAggregation{
 		Field:     field,
@@ -471,6 +544,50 @@ func (b *aggBuilderImpl) GeoHashGrid(key, field string, fn func(a *GeoHashGridAg
 	return b
 }
 
+func (b *aggBuilderImpl) IPRange(key, field string, fn func(a *IPRangeAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &IPRangeAggregation{
+		Field:  field,
+		Ranges: make([]IPRange, 0),
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "ip_range",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
+func (b *aggBuilderImpl) IPPrefix(key, field string, fn func(a *IPPrefixAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &IPPrefixAggregation{
+		Field:        field,
+		PrefixLength: DefaultIPv4PrefixLength,
+		// the key of the buckets is the subnet in CIDR notation instead of the network address
+		AppendPrefixLength: true,
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "ip_prefix",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
 func (b *aggBuilderImpl) Metric(key, metricType, field string, fn func(a *MetricAggregation)) AggBuilder {
 	innerAgg := &MetricAggregation{
 		Type:     metricType,
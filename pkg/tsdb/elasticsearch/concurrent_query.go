@@ -0,0 +1,86 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+// maxConcurrentQueries is the maximum number of multi search requests that are sent concurrently for the queries of
+// a single request.
+const maxConcurrentQueries = 5
+
+// groupQueriesByTimeRange groups the queries by their time range, in the order of the first query of each group.
+func groupQueriesByTimeRange(queries []backend.DataQuery) [][]backend.DataQuery {
+	type timeRangeKey struct {
+		from, to int64
+	}
+	var groups [][]backend.DataQuery
+	index := make(map[timeRangeKey]int)
+	for _, q := range queries {
+		key := timeRangeKey{from: q.TimeRange.From.UnixMilli(), to: q.TimeRange.To.UnixMilli()}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], q)
+	}
+	return groups
+}
+
+// executeConcurrentQueries executes each group of queries in its own multi search request, with at most
+// maxConcurrentQueries requests at a time. An error only fails the responses of the queries of its group.
+func executeConcurrentQueries(ctx context.Context, groups [][]backend.DataQuery, dsInfo *es.DatasourceInfo, logger log.Logger, tracer tracing.Tracer) *backend.QueryDataResponse {
+	start := time.Now()
+	result := backend.NewQueryDataResponse()
+	var mtx sync.Mutex
+
+	g := errgroup.Group{}
+	g.SetLimit(maxConcurrentQueries)
+	for _, group := range groups {
+		group := group
+		g.Go(func() error {
+			res, err := executeQueriesRecovering(ctx, group, dsInfo, logger, tracer)
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				for _, q := range group {
+					errorsource.AddErrorToResponse(q.RefID, result, err)
+				}
+				return nil
+			}
+			for refID, r := range res.Responses {
+				result.Responses[refID] = r
+			}
+			return nil
+		})
+	}
+	// the goroutines report their errors in the responses of their queries
+	_ = g.Wait()
+
+	logger.Debug("Executed queries concurrently", "queriesLength", len(result.Responses), "requests", len(groups), "duration", time.Since(start))
+	return result
+}
+
+// executeQueriesRecovering executes the queries and returns an error if they panic, so that a panic only fails the
+// queries of its group.
+func executeQueriesRecovering(ctx context.Context, queries []backend.DataQuery, dsInfo *es.DatasourceInfo, logger log.Logger, tracer tracing.Tracer) (res *backend.QueryDataResponse, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Panic while executing queries", "error", r, "stack", log.Stack(1))
+			res, err = nil, fmt.Errorf("unexpected error while executing the queries: %v", r)
+		}
+	}()
+	return executeQueries(ctx, queries, dsInfo, logger, tracer)
+}
@@ -0,0 +1,85 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestGroupQueriesByTimeRange(t *testing.T) {
+	day1 := backend.TimeRange{From: time.Date(2022, 11, 14, 10, 0, 0, 0, time.UTC), To: time.Date(2022, 11, 14, 11, 0, 0, 0, time.UTC)}
+	day2 := backend.TimeRange{From: time.Date(2022, 11, 15, 10, 0, 0, 0, time.UTC), To: time.Date(2022, 11, 15, 11, 0, 0, 0, time.UTC)}
+	groups := groupQueriesByTimeRange([]backend.DataQuery{
+		{RefID: "A", TimeRange: day1},
+		{RefID: "B", TimeRange: day2},
+		{RefID: "C", TimeRange: backend.TimeRange{From: day1.From.In(time.Local), To: day1.To.In(time.Local)}},
+	})
+
+	require.Len(t, groups, 2)
+	require.Equal(t, []string{"A", "C"}, []string{groups[0][0].RefID, groups[0][1].RefID})
+	require.Equal(t, "B", groups[1][0].RefID)
+}
+
+func TestQueryDataConcurrently(t *testing.T) {
+	day1 := backend.TimeRange{From: time.Date(2022, 11, 14, 10, 0, 0, 0, time.UTC), To: time.Date(2022, 11, 14, 11, 0, 0, 0, time.UTC)}
+	day2 := backend.TimeRange{From: time.Date(2022, 11, 15, 10, 0, 0, 0, time.UTC), To: time.Date(2022, 11, 15, 11, 0, 0, 0, time.UTC)}
+	countQuery := func(refID string) []byte {
+		return []byte(`{
+			"refId": "` + refID + `",
+			"metrics": [{ "type": "count", "id": "1" }],
+			"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+		}`)
+	}
+	queries := []backend.DataQuery{
+		{RefID: "A", TimeRange: day1, JSON: countQuery("A")},
+		{RefID: "B", TimeRange: day2, JSON: countQuery("B")},
+		// scripted metrics are not enabled in the data source
+		{RefID: "C", TimeRange: day1, JSON: []byte(`{
+			"refId": "C",
+			"metrics": [{ "type": "scripted_metric", "id": "1", "settings": { "mapScript": "state.count = 1" } }],
+			"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+		}`)},
+	}
+	response := []byte(`{"responses": [{"aggregations": {"2": {"buckets": [{"doc_count": 10, "key": 1668420000000}]}}}]}`)
+
+	var mtx sync.Mutex
+	var requests []string
+	dsInfo := newFlowTestDsInfo(response, http.StatusOK, func(req *http.Request) error {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		mtx.Lock()
+		requests = append(requests, string(body))
+		mtx.Unlock()
+		if bytes.Contains(body, []byte("testdb-2022.11.15")) {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	result, err := queryData(context.Background(), queries, dsInfo, log.New("test.logger"), tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2, "queries with different time ranges must be sent in separate requests")
+	require.Len(t, result.Responses, 3)
+
+	require.NoError(t, result.Responses["A"].Error)
+	require.Len(t, result.Responses["A"].Frames, 1)
+
+	require.ErrorContains(t, result.Responses["B"].Error, "connection refused")
+	require.ErrorContains(t, result.Responses["C"].Error, "scripted metric aggregations are not enabled")
+	require.Equal(t, backend.ErrorSourcePlugin, result.Responses["C"].ErrorSource)
+}
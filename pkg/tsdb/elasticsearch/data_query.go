@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+	"github.com/grafana/grafana/pkg/tsdb/elasticsearch/instrumentation"
 )
 
 const (
@@ -44,40 +45,100 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 	start := time.Now()
 	response := backend.NewQueryDataResponse()
 	e.logger.Debug("Parsing queries", "queriesLength", len(e.dataQueries))
-	queries, err := parseQuery(e.dataQueries, e.logger)
-	if err != nil {
-		mq, _ := json.Marshal(e.dataQueries)
-		e.logger.Error("Failed to parse queries", "error", err, "queries", string(mq), "queriesLength", len(queries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+	// Queries are parsed and processed one by one, so that an invalid query only fails its own response.
+	queries := make([]*Query, 0, len(e.dataQueries))
+	for _, dq := range e.dataQueries {
+		parsed, err := parseQuery([]backend.DataQuery{dq}, e.logger)
+		if err != nil {
+			e.logger.Error("Failed to parse query", "error", err, "query", string(dq.JSON), "queriesLength", len(e.dataQueries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
+			errorsource.AddPluginErrorToResponse(dq.RefID, response, err)
+			continue
+		}
+		queries = append(queries, parsed...)
 	}
 
 	ms := e.client.MultiSearch()
 
 	from := e.dataQueries[0].TimeRange.From.UnixNano() / int64(time.Millisecond)
 	to := e.dataQueries[0].TimeRange.To.UnixNano() / int64(time.Millisecond)
+	processed := make([]*Query, 0, len(queries))
 	for _, q := range queries {
 		if err := e.processQuery(q, ms, from, to); err != nil {
 			mq, _ := json.Marshal(q)
 			e.logger.Error("Failed to process query to multisearch request builder", "error", err, "query", string(mq), "queriesLength", len(queries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-			return errorsource.AddPluginErrorToResponse(q.RefID, response, err), nil
+			errorsource.AddPluginErrorToResponse(q.RefID, response, err)
+			continue
 		}
+		processed = append(processed, q)
 	}
+	if len(processed) == 0 {
+		return response, nil
+	}
+	queries = processed
 
 	req, err := ms.Build()
 	if err != nil {
+		instrumentation.UpdateClientStageDurationSeconds(e.ctx, instrumentation.StageBuild, time.Since(start), "error")
 		mqs, _ := json.Marshal(e.dataQueries)
 		e.logger.Error("Failed to build multisearch request", "error", err, "queriesLength", len(queries), "queries", string(mqs), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		for _, q := range queries {
+			errorsource.AddPluginErrorToResponse(q.RefID, response, err)
+		}
+		return response, nil
 	}
 
+	instrumentation.UpdateClientStageDurationSeconds(e.ctx, instrumentation.StageBuild, time.Since(start), "ok")
 	e.logger.Info("Prepared request", "queriesLength", len(queries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
 	res, err := e.client.ExecuteMultisearch(req)
 	if err != nil {
 		// We are returning error containing the source that was added trough errorsource.Middleware
-		return errorsource.AddErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		for _, q := range queries {
+			errorsource.AddErrorToResponse(q.RefID, response, err)
+		}
+		return response, nil
+	}
+
+	if res.Error != nil {
+		// the whole request failed, so all the queries failed with the same error
+		for _, q := range queries {
+			response.Responses[q.RefID] = errorResponse(res.Error)
+		}
+		return response, nil
 	}
 
-	return parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.logger, e.tracer)
+	result, err := parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.logger, e.tracer)
+	if err != nil {
+		return result, err
+	}
+	addWarningNotices(result, res.Warnings)
+	for refID, r := range response.Responses {
+		result.Responses[refID] = r
+	}
+	return result, nil
+}
+
+// translate returns the multi search request that execute would send to Elasticsearch, without sending it.
+func (e *elasticsearchDataQuery) translate() (*es.TranslatedMultiSearchRequest, error) {
+	queries, err := parseQuery(e.dataQueries, e.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := e.client.MultiSearch()
+
+	from := e.dataQueries[0].TimeRange.From.UnixNano() / int64(time.Millisecond)
+	to := e.dataQueries[0].TimeRange.To.UnixNano() / int64(time.Millisecond)
+	for _, q := range queries {
+		if err := e.processQuery(q, ms, from, to); err != nil {
+			return nil, fmt.Errorf("query %s: %w", q.RefID, err)
+		}
+	}
+
+	req, err := ms.Build()
+	if err != nil {
+		return nil, err
+	}
+	return e.client.TranslateMultisearch(req)
 }
 
 func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64) error {
@@ -86,10 +147,14 @@ func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchReques
 		err = fmt.Errorf("received invalid query. %w", err)
 		return err
 	}
+	if err := validateScriptedMetrics(q, e.client.ScriptedMetricEnabled()); err != nil {
+		return fmt.Errorf("received invalid query. %w", err)
+	}
 
 	defaultTimeField := e.client.GetConfiguredFields().TimeField
 	b := ms.Search(q.Interval)
 	b.Size(0)
+	b.QueryType(queryType(q))
 	filters := b.Query().Bool().Filter()
 	filters.AddDateRangeFilter(defaultTimeField, to, from, es.DateFormatEpochMS)
 	filters.AddQueryStringFilter(q.RawQuery, true)
@@ -100,6 +165,7 @@ func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchReques
 		processDocumentQuery(q, b, from, to, defaultTimeField)
 	} else {
 		// Otherwise, it is a time series query and we process it
+		q.SamplingProbability = samplingProbability(q, from, to)
 		processTimeSeriesQuery(q, b, from, to, defaultTimeField)
 	}
 
@@ -114,6 +180,12 @@ func setFloatPath(settings *simplejson.Json, path ...string) {
 	}
 }
 
+func deleteEmptyStringPath(settings *simplejson.Json, key string) {
+	if stringValue, err := settings.Get(key).String(); err == nil && stringValue == "" {
+		settings.Del(key)
+	}
+}
+
 func setIntPath(settings *simplejson.Json, path ...string) {
 	if stringValue, err := settings.GetPath(path...).String(); err == nil {
 		if value, err := strconv.ParseInt(stringValue, 10, 64); err == nil {
@@ -134,6 +206,12 @@ func (metricAggregation MetricAgg) generateSettingsForDSL() map[string]any {
 		setFloatPath(metricAggregation.Settings, "settings", "period")
 	case "serial_diff":
 		setFloatPath(metricAggregation.Settings, "lag")
+	case scriptedMetricType:
+		return scriptedMetricSettingsForDSL(metricAggregation.Settings)
+	case "derivative", "cumulative_sum":
+		// the editor stores unset options as empty strings, which are not valid in Elasticsearch
+		deleteEmptyStringPath(metricAggregation.Settings, "unit")
+		deleteEmptyStringPath(metricAggregation.Settings, "format")
 	}
 
 	if isMetricAggregationWithInlineScriptSupport(metricAggregation.Type) {
@@ -148,7 +226,55 @@ func (metricAggregation MetricAgg) generateSettingsForDSL() map[string]any {
 		}
 	}
 
-	return metricAggregation.Settings.MustMap()
+	return withoutDisplaySettings(metricAggregation.Settings.MustMap())
+}
+
+// metricDisplaySettings are the settings of metrics that tell panels how to display their values. They are not
+// part of the aggregations.
+var metricDisplaySettings = []string{"displayUnit", "decimals", "axisPlacement"}
+
+// withoutDisplaySettings returns the settings without the display settings. The settings are copied, because the
+// response parser reads the display settings of the same metrics.
+func withoutDisplaySettings(settings map[string]any) map[string]any {
+	hasDisplaySettings := false
+	for _, key := range metricDisplaySettings {
+		if _, ok := settings[key]; ok {
+			hasDisplaySettings = true
+			break
+		}
+	}
+	if !hasDisplaySettings {
+		return settings
+	}
+
+	dsl := make(map[string]any, len(settings))
+	for k, v := range settings {
+		if !slices.Contains(metricDisplaySettings, k) {
+			dsl[k] = v
+		}
+	}
+	return dsl
+}
+
+// scriptedMetricScripts maps the scripts of a scripted metric in the query model to the ones of the aggregation.
+var scriptedMetricScripts = map[string]string{
+	"initScript":    "init_script",
+	"mapScript":     "map_script",
+	"combineScript": "combine_script",
+	"reduceScript":  "reduce_script",
+}
+
+func scriptedMetricSettingsForDSL(settings *simplejson.Json) map[string]any {
+	dsl := make(map[string]any)
+	for setting, key := range scriptedMetricScripts {
+		if script := settings.Get(setting).MustString(); script != "" {
+			dsl[key] = script
+		}
+	}
+	if params := settings.Get("params").MustMap(); len(params) > 0 {
+		dsl["params"] = params
+	}
+	return dsl
 }
 
 func (bucketAgg BucketAgg) generateSettingsForDSL() map[string]any {
@@ -157,12 +283,22 @@ func (bucketAgg BucketAgg) generateSettingsForDSL() map[string]any {
 	return bucketAgg.Settings.MustMap()
 }
 
-func addDateHistogramAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, timeFrom, timeTo int64, timeField string) es.AggBuilder {
+// autoBucketsIntervalMode is the interval mode of the date histograms that target a number of buckets instead of using
+// an interval. Elasticsearch picks the interval of their auto_date_histogram aggregation.
+const autoBucketsIntervalMode = "buckets"
+
+// defaultAutoDateHistogramBuckets is the target number of buckets if neither the date histogram nor the panel set it.
+const defaultAutoDateHistogramBuckets = 100
+
+func addDateHistogramAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, timeFrom, timeTo int64, timeField string, maxDataPoints int64) es.AggBuilder {
 	// If no field is specified, use the time field
 	field := bucketAgg.Field
 	if field == "" {
 		field = timeField
 	}
+	if bucketAgg.Settings.Get("intervalMode").MustString() == autoBucketsIntervalMode {
+		return addAutoDateHistogramAgg(aggBuilder, bucketAgg, field, maxDataPoints)
+	}
 	aggBuilder.DateHistogram(bucketAgg.ID, field, func(a *es.DateHistogramAgg, b es.AggBuilder) {
 		var interval = bucketAgg.Settings.Get("interval").MustString("auto")
 		if slices.Contains(es.GetCalendarIntervals(), interval) {
@@ -205,6 +341,40 @@ func addDateHistogramAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, timeFro
 	return aggBuilder
 }
 
+func addAutoDateHistogramAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, field string, maxDataPoints int64) es.AggBuilder {
+	aggBuilder.AutoDateHistogram(bucketAgg.ID, field, func(a *es.AutoDateHistogramAgg, b es.AggBuilder) {
+		// the panel cannot show more buckets than data points
+		defaultBuckets := defaultAutoDateHistogramBuckets
+		if maxDataPoints > 0 {
+			defaultBuckets = int(maxDataPoints)
+		}
+		if buckets, err := bucketAgg.Settings.Get("buckets").Int(); err == nil {
+			a.Buckets = buckets
+		} else {
+			a.Buckets = stringToIntWithDefaultValue(bucketAgg.Settings.Get("buckets").MustString(), defaultBuckets)
+		}
+		a.Format = bucketAgg.Settings.Get("format").MustString(es.DateFormatEpochMS)
+
+		if minimumInterval, err := bucketAgg.Settings.Get("minimumInterval").String(); err == nil {
+			a.MinimumInterval = minimumInterval
+		}
+
+		if missing, err := bucketAgg.Settings.Get("missing").String(); err == nil {
+			a.Missing = &missing
+		}
+
+		if timezone, err := bucketAgg.Settings.Get("timeZone").String(); err == nil {
+			if timezone != "utc" {
+				a.TimeZone = timezone
+			}
+		}
+
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
 func addHistogramAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
 	aggBuilder.Histogram(bucketAgg.ID, bucketAgg.Field, func(a *es.HistogramAgg, b es.AggBuilder) {
 		a.Interval = stringToIntWithDefaultValue(bucketAgg.Settings.Get("interval").MustString(), 1000)
@@ -296,6 +466,19 @@ func addFiltersAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder
 	return aggBuilder
 }
 
+func addFilterAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	query := bucketAgg.Settings.Get("query").MustString()
+	if query == "" {
+		query = "*"
+	}
+	aggBuilder.Filter(bucketAgg.ID, func(a *es.FilterAggregation, b es.AggBuilder) {
+		a.Filter = &es.QueryStringFilter{Query: query, AnalyzeWildcard: true}
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
 func addGeoHashGridAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
 	aggBuilder.GeoHashGrid(bucketAgg.ID, bucketAgg.Field, func(a *es.GeoHashGridAggregation, b es.AggBuilder) {
 		a.Precision = stringToIntWithDefaultValue(bucketAgg.Settings.Get("precision").MustString(), es.DefaultGeoHashPrecision)
@@ -305,6 +488,57 @@ func addGeoHashGridAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBui
 	return aggBuilder
 }
 
+func addIPRangeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	ranges := make([]es.IPRange, 0)
+	for _, r := range bucketAgg.Settings.Get("ranges").MustArray() {
+		json := simplejson.NewFromAny(r)
+		ipRange := es.IPRange{
+			Key:  json.Get("key").MustString(),
+			From: json.Get("from").MustString(),
+			To:   json.Get("to").MustString(),
+			Mask: json.Get("mask").MustString(),
+		}
+		// Elasticsearch rejects ranges that have both a mask and bounds, the mask takes precedence
+		if ipRange.Mask != "" {
+			ipRange.From, ipRange.To = "", ""
+		}
+		if ipRange.Mask == "" && ipRange.From == "" && ipRange.To == "" {
+			continue
+		}
+		ranges = append(ranges, ipRange)
+	}
+
+	if len(ranges) > 0 {
+		aggBuilder.IPRange(bucketAgg.ID, bucketAgg.Field, func(a *es.IPRangeAggregation, b es.AggBuilder) {
+			a.Ranges = ranges
+			aggBuilder = b
+		})
+	}
+
+	return aggBuilder
+}
+
+func addIPPrefixAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.IPPrefix(bucketAgg.ID, bucketAgg.Field, func(a *es.IPPrefixAggregation, b es.AggBuilder) {
+		a.IsIPv6 = bucketAgg.Settings.Get("is_ipv6").MustBool(false)
+		defaultPrefixLength := es.DefaultIPv4PrefixLength
+		if a.IsIPv6 {
+			defaultPrefixLength = es.DefaultIPv6PrefixLength
+		}
+		if prefixLength, err := bucketAgg.Settings.Get("prefix_length").Int(); err == nil {
+			a.PrefixLength = prefixLength
+		} else {
+			a.PrefixLength = stringToIntWithDefaultValue(bucketAgg.Settings.Get("prefix_length").MustString(), defaultPrefixLength)
+		}
+		if minDocCount, err := bucketAgg.Settings.Get("min_doc_count").Int(); err == nil {
+			a.MinDocCount = &minDocCount
+		}
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
 func getPipelineAggField(m *MetricAgg) string {
 	// In frontend we are using Field as pipelineAggField
 	// There might be historical reason why in backend we were using PipelineAggregate as pipelineAggField
@@ -328,6 +562,24 @@ func isQueryWithError(query *Query) error {
 	return nil
 }
 
+func validateScriptedMetrics(query *Query, enabled bool) error {
+	for _, m := range query.Metrics {
+		if m.Type != scriptedMetricType {
+			continue
+		}
+		if !enabled {
+			return fmt.Errorf("scripted metric aggregations are not enabled for this data source")
+		}
+		// Elasticsearch requires all the scripts but the init script
+		for _, setting := range []string{"mapScript", "combineScript", "reduceScript"} {
+			if m.Settings.Get(setting).MustString() == "" {
+				return fmt.Errorf("scripted metric %s is missing the %s", m.ID, setting)
+			}
+		}
+	}
+	return nil
+}
+
 func isLogsQuery(query *Query) bool {
 	return query.Metrics[0].Type == logsType
 }
@@ -336,6 +588,20 @@ func isDocumentQuery(query *Query) bool {
 	return isRawDataQuery(query) || isRawDocumentQuery(query)
 }
 
+// queryType returns the type of the query, as it is logged by the client.
+func queryType(query *Query) string {
+	switch {
+	case isLogsQuery(query):
+		return logsType
+	case isRawDataQuery(query):
+		return rawDataType
+	case isRawDocumentQuery(query):
+		return rawDocumentType
+	default:
+		return "time_series"
+	}
+}
+
 func isRawDataQuery(query *Query) bool {
 	return query.Metrics[0].Type == rawDataType
 }
@@ -381,7 +647,7 @@ func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defa
 	bucketAgg.Settings = simplejson.NewFromAny(
 		bucketAgg.generateSettingsForDSL(),
 	)
-	_ = addDateHistogramAgg(aggBuilder, bucketAgg, from, to, defaultTimeField)
+	_ = addDateHistogramAgg(aggBuilder, bucketAgg, from, to, defaultTimeField, q.MaxDataPoints)
 }
 
 func processDocumentQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string) {
@@ -399,6 +665,12 @@ func processDocumentQuery(q *Query, b *es.SearchRequestBuilder, from, to int64,
 
 func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string) {
 	aggBuilder := b.Agg()
+	if q.SamplingProbability > 0 {
+		// the random sampler must be the top-level aggregation, all the aggregations of the query are nested in it
+		aggBuilder.RandomSampler(samplerAggID, q.SamplingProbability, func(_ *es.RandomSamplerAggregation, b es.AggBuilder) {
+			aggBuilder = b
+		})
+	}
 	// Process buckets
 	// iterate backwards to create aggregations bottom-down
 	for _, bucketAgg := range q.BucketAggs {
@@ -407,17 +679,23 @@ func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64
 		)
 		switch bucketAgg.Type {
 		case dateHistType:
-			aggBuilder = addDateHistogramAgg(aggBuilder, bucketAgg, from, to, defaultTimeField)
+			aggBuilder = addDateHistogramAgg(aggBuilder, bucketAgg, from, to, defaultTimeField, q.MaxDataPoints)
 		case histogramType:
 			aggBuilder = addHistogramAgg(aggBuilder, bucketAgg)
 		case filtersType:
 			aggBuilder = addFiltersAgg(aggBuilder, bucketAgg)
+		case filterType:
+			aggBuilder = addFilterAgg(aggBuilder, bucketAgg)
 		case termsType:
 			aggBuilder = addTermsAgg(aggBuilder, bucketAgg, q.Metrics)
 		case geohashGridType:
 			aggBuilder = addGeoHashGridAgg(aggBuilder, bucketAgg)
 		case nestedType:
 			aggBuilder = addNestedAgg(aggBuilder, bucketAgg)
+		case ipRangeType:
+			aggBuilder = addIPRangeAgg(aggBuilder, bucketAgg)
+		case ipPrefixType:
+			aggBuilder = addIPPrefixAgg(aggBuilder, bucketAgg)
 		}
 	}
 
@@ -369,6 +369,45 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, dateHistogramAgg.Aggregation.Aggregation.(*es.DateHistogramAgg).Field, "@timestamp")
 		})
 
+		t.Run("With filter agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "id": "2", "type": "filter", "settings": { "query": "@metric:cpu", "label": "cpu" } },
+					{ "type": "date_histogram", "field": "@timestamp", "id": "4" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			filterAgg := sr.Aggs[0]
+			require.Equal(t, filterAgg.Key, "2")
+			require.Equal(t, filterAgg.Aggregation.Type, "filter")
+			fAgg := filterAgg.Aggregation.Aggregation.(*es.FilterAggregation)
+			require.Equal(t, fAgg.Filter.(*es.QueryStringFilter).Query, "@metric:cpu")
+
+			dateHistogramAgg := sr.Aggs[0].Aggregation.Aggs[0]
+			require.Equal(t, dateHistogramAgg.Key, "4")
+
+			body, err := json.Marshal(filterAgg.Aggregation)
+			require.NoError(t, err)
+			require.Contains(t, string(body), `"filter":{"query_string":{"analyze_wildcard":true,"query":"@metric:cpu"}}`)
+		})
+
+		t.Run("With filter agg without query", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [{ "id": "2", "type": "filter", "settings": {} }],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			fAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.FilterAggregation)
+			require.Equal(t, fAgg.Filter.(*es.QueryStringFilter).Query, "*")
+		})
+
 		t.Run("With filters aggs and empty label (from frontend tests)", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -465,6 +504,50 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, sr.Size, 1337)
 		})
 
+		t.Run("With date histogram agg in buckets interval mode", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "date_histogram",
+						"field": "@timestamp",
+						"settings": { "intervalMode": "buckets", "buckets": "20", "minimumInterval": "minute", "timeZone": "Europe/Paris", "min_doc_count": 2 }
+					}
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Key, "2")
+			require.Equal(t, firstLevel.Aggregation.Type, "auto_date_histogram")
+			hAgg := firstLevel.Aggregation.Aggregation.(*es.AutoDateHistogramAgg)
+			require.Equal(t, hAgg.Field, "@timestamp")
+			require.Equal(t, hAgg.Buckets, 20)
+			require.Equal(t, hAgg.MinimumInterval, "minute")
+			require.Equal(t, hAgg.TimeZone, "Europe/Paris")
+			require.Equal(t, hAgg.Format, "epoch_millis")
+
+			t.Run("Should default to the max data points of the panel", func(t *testing.T) {
+				c := newFakeClient()
+				_, err := executeElasticsearchDataQuery(c, `{
+					"bucketAggs": [
+						{ "id": "2", "type": "date_histogram", "settings": { "intervalMode": "buckets" } }
+					],
+					"metrics": [{"type": "count", "id": "1" }]
+				}`, from, to)
+				require.NoError(t, err)
+				sr := c.multisearchRequests[0].Requests[0]
+
+				hAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.AutoDateHistogramAgg)
+				require.Equal(t, hAgg.Field, "@timestamp")
+				// the test query does not set max data points
+				require.Equal(t, hAgg.Buckets, defaultAutoDateHistogramBuckets)
+			})
+		})
+
 		t.Run("With date histogram agg", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -681,6 +764,106 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, ghGridAgg.Precision, 3)
 		})
 
+		t.Run("With ip range agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "ip_range",
+						"field": "source.ip",
+						"settings": {
+							"ranges": [
+								{ "mask": "10.0.0.0/25", "from": "10.0.0.1" },
+								{ "from": "10.0.0.128", "to": "10.0.1.0", "key": "upper" },
+								{}
+							]
+						}
+					},
+					{ "type": "date_histogram", "field": "@timestamp", "id": "4" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Key, "2")
+			require.Equal(t, firstLevel.Aggregation.Type, "ip_range")
+			ipRangeAgg := firstLevel.Aggregation.Aggregation.(*es.IPRangeAggregation)
+			require.Equal(t, ipRangeAgg.Field, "source.ip")
+			require.Equal(t, []es.IPRange{
+				{Mask: "10.0.0.0/25"},
+				{Key: "upper", From: "10.0.0.128", To: "10.0.1.0"},
+			}, ipRangeAgg.Ranges)
+			require.Equal(t, firstLevel.Aggregation.Aggs[0].Aggregation.Type, "date_histogram")
+		})
+
+		t.Run("With ip range agg without ranges", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "id": "2", "type": "ip_range", "field": "source.ip", "settings": {} },
+					{ "type": "date_histogram", "field": "@timestamp", "id": "4" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, sr.Aggs[0].Aggregation.Type, "date_histogram")
+		})
+
+		t.Run("With ip prefix agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "ip_prefix",
+						"field": "destination.ip",
+						"settings": { "prefix_length": "16", "min_doc_count": "1" }
+					}
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, firstLevel.Key, "2")
+			require.Equal(t, firstLevel.Aggregation.Type, "ip_prefix")
+			ipPrefixAgg := firstLevel.Aggregation.Aggregation.(*es.IPPrefixAggregation)
+			require.Equal(t, ipPrefixAgg.Field, "destination.ip")
+			require.Equal(t, ipPrefixAgg.PrefixLength, 16)
+			require.False(t, ipPrefixAgg.IsIPv6)
+			require.True(t, ipPrefixAgg.AppendPrefixLength)
+			require.Equal(t, *ipPrefixAgg.MinDocCount, 1)
+		})
+
+		t.Run("With ipv6 prefix agg with no prefix length", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "ip_prefix",
+						"field": "destination.ip",
+						"settings": { "is_ipv6": true }
+					}
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			ipPrefixAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.IPPrefixAggregation)
+			require.True(t, ipPrefixAgg.IsIPv6)
+			// It should default to 64
+			require.Equal(t, ipPrefixAgg.PrefixLength, 64)
+			require.Nil(t, ipPrefixAgg.MinDocCount)
+		})
+
 		t.Run("With moving average (from frontend tests)", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -906,6 +1089,69 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, string(topMetricsBytes), `{"metrics":[{"field":"@value"}],"size":"1","sort":[{"@timestamp":"desc"}]}`)
 		})
 
+		t.Run("With scripted_metric", func(t *testing.T) {
+			query := `{
+				"bucketAggs": [
+					{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+				],
+				"metrics": [
+					{
+						"id": "2",
+						"type": "scripted_metric",
+						"settings": {
+							"initScript": "state.values = []",
+							"mapScript": "state.values.add(doc['@value'].value)",
+							"combineScript": "double sum = 0; for (v in state.values) { sum += v } return sum",
+							"reduceScript": "double sum = 0; for (s in states) { sum += s } return sum * params.factor",
+							"params": { "factor": 2 }
+						}
+					}
+				]
+			}`
+
+			t.Run("Should be rejected if the data source does not enable it", func(t *testing.T) {
+				c := newFakeClient()
+				res, err := executeElasticsearchDataQuery(c, query, from, to)
+				require.NoError(t, err)
+				require.Empty(t, c.multisearchRequests)
+				require.Equal(t, res.Responses["A"].ErrorSource, backend.ErrorSourcePlugin)
+				require.ErrorContains(t, res.Responses["A"].Error, "scripted metric aggregations are not enabled")
+			})
+
+			t.Run("Should add the scripts to the aggregation", func(t *testing.T) {
+				c := newFakeClient()
+				c.scriptedMetricEnabled = true
+				_, err := executeElasticsearchDataQuery(c, query, from, to)
+				require.NoError(t, err)
+				sr := c.multisearchRequests[0].Requests[0]
+
+				secondLevel := sr.Aggs[0].Aggregation.Aggs[0]
+				require.Equal(t, secondLevel.Key, "2")
+				require.Equal(t, secondLevel.Aggregation.Type, "scripted_metric")
+
+				scriptedMetricBytes, err := json.Marshal(secondLevel.Aggregation.Aggregation)
+				require.NoError(t, err)
+				require.JSONEq(t, `{
+					"init_script": "state.values = []",
+					"map_script": "state.values.add(doc['@value'].value)",
+					"combine_script": "double sum = 0; for (v in state.values) { sum += v } return sum",
+					"reduce_script": "double sum = 0; for (s in states) { sum += s } return sum * params.factor",
+					"params": { "factor": 2 }
+				}`, string(scriptedMetricBytes))
+			})
+
+			t.Run("Should require the map, combine and reduce scripts", func(t *testing.T) {
+				c := newFakeClient()
+				c.scriptedMetricEnabled = true
+				res, err := executeElasticsearchDataQuery(c, `{
+					"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "3" }],
+					"metrics": [{ "id": "2", "type": "scripted_metric", "settings": { "mapScript": "state.count = 1" } }]
+				}`, from, to)
+				require.NoError(t, err)
+				require.ErrorContains(t, res.Responses["A"].Error, "scripted metric 2 is missing the combineScript")
+			})
+		})
+
 		t.Run("With cumulative sum", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -1037,6 +1283,52 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, plAgg.BucketPath, "3")
 		})
 
+		t.Run("With derivative unit and format", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "type": "date_histogram", "field": "@timestamp", "id": "4" }
+				],
+				"metrics": [
+					{ "id": "3", "type": "sum", "field": "@value" },
+					{
+						"id": "2",
+						"type": "derivative",
+						"pipelineAgg": "3",
+						"settings": { "unit": "1s", "format": "" }
+					}
+				]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			derivativeAgg := sr.Aggs[0].Aggregation.Aggs[1]
+			plAgg := derivativeAgg.Aggregation.Aggregation.(*es.PipelineAggregation)
+			require.Equal(t, map[string]any{"unit": "1s"}, plAgg.Settings)
+		})
+
+		t.Run("With display settings", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "type": "date_histogram", "field": "@timestamp", "id": "4" }
+				],
+				"metrics": [
+					{
+						"id": "3",
+						"type": "avg",
+						"field": "bytes",
+						"settings": { "missing": "0", "displayUnit": "bytes", "decimals": "1", "axisPlacement": "right" }
+					}
+				]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			metricAgg := sr.Aggs[0].Aggregation.Aggs[0].Aggregation.Aggregation.(*es.MetricAggregation)
+			require.Equal(t, map[string]any{"missing": "0"}, metricAgg.Settings)
+		})
+
 		t.Run("With derivative doc count", func(t *testing.T) {
 			// This test is with pipelineAgg and is passing. Same test without pipelineAgg is failing.
 			c := newFakeClient()
@@ -1800,12 +2092,77 @@ func TestSettingsCasting(t *testing.T) {
 	})
 }
 
+func TestAdaptiveSampling(t *testing.T) {
+	to := time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC)
+	query := `{
+		"adaptiveSampling": true,
+		"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }],
+		"metrics": [{"type": "avg", "field": "value", "id": "1" }]
+	}`
+
+	t.Run("does not sample queries over time ranges below the threshold", func(t *testing.T) {
+		c := newFakeClient()
+		_, err := executeElasticsearchDataQuery(c, query, to.Add(-24*time.Hour), to)
+		require.NoError(t, err)
+		sr := c.multisearchRequests[0].Requests[0]
+		require.Equal(t, "2", sr.Aggs[0].Key)
+	})
+
+	t.Run("samples queries over time ranges above the threshold", func(t *testing.T) {
+		c := newFakeClient()
+		c.multiSearchResponse = &es.MultiSearchResponse{
+			Responses: []*es.SearchResponse{{
+				Aggregations: map[string]any{
+					samplerAggID: map[string]any{
+						"doc_count":   10,
+						"probability": 0.1,
+						"2": map[string]any{
+							"buckets": []any{
+								map[string]any{"key": 1000.0, "doc_count": 10.0, "1": map[string]any{"value": 5.0}},
+							},
+						},
+					},
+				},
+			}},
+		}
+		res, err := executeElasticsearchDataQuery(c, query, to.Add(-70*24*time.Hour), to)
+		require.NoError(t, err)
+
+		sr := c.multisearchRequests[0].Requests[0]
+		require.Equal(t, samplerAggID, sr.Aggs[0].Key)
+		require.Equal(t, "random_sampler", sr.Aggs[0].Aggregation.Type)
+		require.Equal(t, 0.1, sr.Aggs[0].Aggregation.Aggregation.(*es.RandomSamplerAggregation).Probability)
+		require.Equal(t, "2", sr.Aggs[0].Aggregation.Aggs[0].Key)
+
+		frames := res.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 1, frames[0].Rows())
+		require.Equal(t, map[string]any{"sampled": true, "samplingProbability": 0.1}, frames[0].Meta.Custom)
+		require.Len(t, frames[0].Meta.Notices, 1)
+	})
+
+	t.Run("uses the sampling threshold of the query", func(t *testing.T) {
+		c := newFakeClient()
+		_, err := executeElasticsearchDataQuery(c, `{
+			"adaptiveSampling": true,
+			"samplingThreshold": "30d",
+			"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }],
+			"metrics": [{"type": "count", "id": "1" }]
+		}`, to.Add(-20*24*time.Hour), to)
+		require.NoError(t, err)
+		sr := c.multisearchRequests[0].Requests[0]
+		require.Equal(t, "2", sr.Aggs[0].Key)
+	})
+}
+
 type fakeClient struct {
 	configuredFields    es.ConfiguredFields
 	multiSearchResponse *es.MultiSearchResponse
 	multiSearchError    error
 	builder             *es.MultiSearchRequestBuilder
 	multisearchRequests []*es.MultiSearchRequest
+	// scriptedMetricEnabled is the data source setting that allows scripted metric aggregations
+	scriptedMetricEnabled bool
 }
 
 func newFakeClient() *fakeClient {
@@ -1826,11 +2183,28 @@ func (c *fakeClient) GetConfiguredFields() es.ConfiguredFields {
 	return c.configuredFields
 }
 
+func (c *fakeClient) ScriptedMetricEnabled() bool {
+	return c.scriptedMetricEnabled
+}
+
 func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSearchResponse, error) {
 	c.multisearchRequests = append(c.multisearchRequests, r)
 	return c.multiSearchResponse, c.multiSearchError
 }
 
+func (c *fakeClient) TranslateMultisearch(r *es.MultiSearchRequest) (*es.TranslatedMultiSearchRequest, error) {
+	c.multisearchRequests = append(c.multisearchRequests, r)
+	body := ""
+	for _, req := range r.Requests {
+		b, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		body += "{}\n" + string(b) + "\n"
+	}
+	return &es.TranslatedMultiSearchRequest{Method: "POST", Path: "_msearch", Body: body}, nil
+}
+
 func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	c.builder = es.NewMultiSearchRequestBuilder()
 	return c.builder
@@ -0,0 +1,129 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+const (
+	detectedFieldTypeString  = "string"
+	detectedFieldTypeInt     = "int"
+	detectedFieldTypeFloat   = "float"
+	detectedFieldTypeBoolean = "boolean"
+	detectedFieldTypeJSON    = "json"
+
+	// jsonParser is the parser of the fields extracted from JSON log messages
+	jsonParser = "json"
+)
+
+// internalLogFields are the fields of the logs frame that are not part of the documents.
+var internalLogFields = map[string]bool{
+	"_id":       true,
+	"_type":     true,
+	"_index":    true,
+	"_source":   true,
+	"sort":      true,
+	"highlight": true,
+	"id":        true,
+}
+
+// detectedField describes a field of the log lines, like the detected fields of Loki.
+type detectedField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Cardinality is the number of distinct values of the field in the returned log lines.
+	Cardinality int      `json:"cardinality"`
+	Parsers     []string `json:"parsers,omitempty"`
+}
+
+// parseJSONMessage extracts the fields of the message of a log line if the message is a JSON object. The fields are
+// flattened like the source of the document, and the ones that already exist in the document get an "_extracted"
+// suffix. It returns the names of the extracted fields.
+func parseJSONMessage(doc map[string]interface{}, messageField string) []string {
+	message, ok := doc[messageField].(string)
+	if !ok || !strings.HasPrefix(strings.TrimSpace(message), "{") {
+		return nil
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(message), &parsed); err != nil {
+		// not every message of a stream has to be JSON
+		return nil
+	}
+
+	extracted := make([]string, 0, len(parsed))
+	for k, v := range flatten(parsed, 10) {
+		name := k
+		if _, exists := doc[name]; exists {
+			name = k + "_extracted"
+		}
+		doc[name] = v
+		extracted = append(extracted, name)
+	}
+	return extracted
+}
+
+// detectFields returns the type and the cardinality of the fields of a logs frame. The time, the message and the
+// internal fields are not detected.
+func detectFields(fields []*data.Field, configuredFields es.ConfiguredFields, parsedFields map[string]bool) []detectedField {
+	detected := make([]detectedField, 0, len(fields))
+	for _, field := range fields {
+		if internalLogFields[field.Name] || field.Name == configuredFields.LogMessageField {
+			continue
+		}
+		fieldType, ok := detectedFieldType(field)
+		if !ok {
+			continue
+		}
+
+		values := make(map[string]struct{})
+		for i := 0; i < field.Len(); i++ {
+			if value, ok := field.ConcreteAt(i); ok {
+				values[detectedFieldValueKey(value)] = struct{}{}
+			}
+		}
+
+		df := detectedField{
+			Name:        field.Name,
+			Type:        fieldType,
+			Cardinality: len(values),
+		}
+		if parsedFields[field.Name] {
+			df.Parsers = []string{jsonParser}
+		}
+		detected = append(detected, df)
+	}
+	return detected
+}
+
+func detectedFieldType(field *data.Field) (string, bool) {
+	switch field.Type() {
+	case data.FieldTypeNullableString:
+		return detectedFieldTypeString, true
+	case data.FieldTypeNullableBool:
+		return detectedFieldTypeBoolean, true
+	case data.FieldTypeNullableJSON:
+		return detectedFieldTypeJSON, true
+	case data.FieldTypeNullableFloat64:
+		for i := 0; i < field.Len(); i++ {
+			if value, ok := field.ConcreteAt(i); ok && value.(float64) != math.Trunc(value.(float64)) {
+				return detectedFieldTypeFloat, true
+			}
+		}
+		return detectedFieldTypeInt, true
+	default:
+		return "", false
+	}
+}
+
+func detectedFieldValueKey(value interface{}) string {
+	if raw, ok := value.(json.RawMessage); ok {
+		return string(raw)
+	}
+	return fmt.Sprint(value)
+}
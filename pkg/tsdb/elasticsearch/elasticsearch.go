@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +22,7 @@ import (
 	exphttpclient "github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource/httpclient"
 
 	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	ngalertmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -29,11 +31,18 @@ import (
 
 var eslog = log.New("tsdb.elasticsearch")
 
+// defaultMaxResponseSizeMB is the maximum size of the responses of Elasticsearch if the data source does not set it.
+const defaultMaxResponseSizeMB int64 = 512
+
 type Service struct {
 	httpClientProvider httpclient.Provider
 	im                 instancemgmt.InstanceManager
 	tracer             tracing.Tracer
 	logger             *log.ConcreteLogger
+	// variableCache caches the results of the template variable queries
+	variableCache *localcache.CacheService
+	// queryCache caches the responses of the queries of the data sources that enable it
+	queryCache *localcache.CacheService
 }
 
 func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Tracer) *Service {
@@ -42,6 +51,8 @@ func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Trace
 		httpClientProvider: httpClientProvider,
 		tracer:             tracer,
 		logger:             eslog,
+		variableCache:      localcache.New(variableQueryCacheTTL, 2*variableQueryCacheTTL),
+		queryCache:         localcache.New(maxQueryCacheTTL, queryCacheCleanupInterval),
 	}
 }
 
@@ -55,6 +66,9 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return &backend.QueryDataResponse{}, err
 	}
 
+	if dsInfo.QueryCacheTTL > 0 && !skipQueryCache(req) {
+		return s.queryDataCached(ctx, req, dsInfo, logger)
+	}
 	return queryData(ctx, req.Queries, dsInfo, logger, s.tracer)
 }
 
@@ -64,6 +78,17 @@ func queryData(ctx context.Context, queries []backend.DataQuery, dsInfo *es.Data
 		return &backend.QueryDataResponse{}, fmt.Errorf("query contains no queries")
 	}
 
+	// the indices of the data source depend on the time range, so queries with different time ranges are sent
+	// in separate multi search requests
+	groups := groupQueriesByTimeRange(queries)
+	if len(groups) > 1 {
+		return executeConcurrentQueries(ctx, groups, dsInfo, logger, tracer), nil
+	}
+	return executeQueries(ctx, queries, dsInfo, logger, tracer)
+}
+
+// executeQueries executes queries with the same time range in a single multi search request.
+func executeQueries(ctx context.Context, queries []backend.DataQuery, dsInfo *es.DatasourceInfo, logger log.Logger, tracer tracing.Tracer) (*backend.QueryDataResponse, error) {
 	client, err := es.NewClient(ctx, dsInfo, queries[0].TimeRange, logger, tracer)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -91,6 +116,15 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 
 		// set the default middlewars from the httpClientProvider
 		httpCliOpts.Middlewares = httpClientProvider.(*sdkhttpclient.Provider).Opts.Middlewares
+
+		// assumed roles are signed by the middleware of the data source, which refreshes their sessions before they
+		// expire and can assume a role per signed-in user
+		sigV4RoleTemplate, _ := jsonData["sigV4AssumeRoleTemplate"].(string)
+		if httpCliOpts.SigV4 != nil && (httpCliOpts.SigV4.AssumeRoleARN != "" || sigV4RoleTemplate != "") {
+			sigV4 := *httpCliOpts.SigV4
+			httpCliOpts.SigV4 = nil
+			httpCliOpts.Middlewares = append(slices.Clone(httpCliOpts.Middlewares), newSigV4Middleware(sigV4, sigV4RoleTemplate))
+		}
 		// enable experimental http client to support errors with source
 		httpCli, err := exphttpclient.New(httpCliOpts)
 		if err != nil {
@@ -155,6 +189,26 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			xpack = false
 		}
 
+		// scripted metrics run arbitrary scripts on the cluster, so they have to be enabled explicitly
+		enableScriptedMetric, ok := jsonData["enableScriptedMetric"].(bool)
+		if !ok {
+			enableScriptedMetric = false
+		}
+
+		// the responses of Elasticsearch are limited, so that a query with too many buckets or documents does not
+		// exhaust the memory of Grafana
+		maxResponseSizeMB := defaultMaxResponseSizeMB
+		if v, ok := jsonData["maxResponseSizeMB"].(float64); ok && v > 0 {
+			maxResponseSizeMB = int64(v)
+		}
+
+		// the responses of queries are only cached if the data source enables it, because cached responses can miss
+		// the most recent documents
+		var queryCacheTTL time.Duration
+		if v, ok := jsonData["queryCacheTTLSeconds"].(float64); ok && v > 0 {
+			queryCacheTTL = min(time.Duration(v)*time.Second, maxQueryCacheTTL)
+		}
+
 		configuredFields := es.ConfiguredFields{
 			TimeField:       timeField,
 			LogLevelField:   logLevelField,
@@ -171,6 +225,9 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			Interval:                   interval,
 			IncludeFrozen:              includeFrozen,
 			XPack:                      xpack,
+			EnableScriptedMetric:       enableScriptedMetric,
+			MaxResponseSize:            maxResponseSizeMB * 1024 * 1024,
+			QueryCacheTTL:              queryCacheTTL,
 		}
 		return model, nil
 	}
@@ -189,6 +246,17 @@ func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext
 
 func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
 	logger := eslog.FromContext(ctx)
+	// template variable queries are executed in the backend, so that they work with every kind of authentication
+	if req.Path == variablesResourcePath {
+		return s.handleVariableQuery(ctx, req, sender)
+	}
+	if req.Path == translateResourcePath {
+		return s.handleTranslate(ctx, req, sender)
+	}
+	if req.Path == indicesResourcePath {
+		return s.handleIndices(ctx, req, sender)
+	}
+
 	// allowed paths for resource calls:
 	// - empty string for fetching db version
 	// - /_mapping for fetching index mapping, e.g. requests going to `index/_mapping`
@@ -269,3 +337,19 @@ func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceReq
 		Body:    body,
 	})
 }
+
+func sendResourceJSON(sender backend.CallResourceResponseSender, status int, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"content-type": {"application/json"}},
+		Body:    body,
+	})
+}
+
+func sendResourceError(sender backend.CallResourceResponseSender, status int, err error) error {
+	return sendResourceJSON(sender, status, map[string]string{"message": err.Error()})
+}
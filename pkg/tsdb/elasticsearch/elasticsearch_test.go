@@ -4,17 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/httpclient"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 )
 
 type datasourceInfo struct {
 	TimeField                  any    `json:"timeField"`
 	MaxConcurrentShardRequests int64  `json:"maxConcurrentShardRequests"`
 	Interval                   string `json:"interval"`
+	MaxResponseSizeMB          int64  `json:"maxResponseSizeMB,omitempty"`
+	QueryCacheTTLSeconds       int64  `json:"queryCacheTTLSeconds,omitempty"`
 }
 
 func TestNewInstanceSettings(t *testing.T) {
@@ -34,6 +38,50 @@ func TestNewInstanceSettings(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("maxResponseSizeMB", func(t *testing.T) {
+		newDsInfo := func(t *testing.T, maxResponseSizeMB int64) es.DatasourceInfo {
+			settingsJSON, err := json.Marshal(datasourceInfo{TimeField: "@timestamp", MaxResponseSizeMB: maxResponseSizeMB})
+			require.NoError(t, err)
+			instance, err := newInstanceSettings(httpclient.NewProvider())(context.Background(), backend.DataSourceInstanceSettings{
+				JSONData: json.RawMessage(settingsJSON),
+			})
+			require.NoError(t, err)
+			return instance.(es.DatasourceInfo)
+		}
+
+		t.Run("defaults to 512 MB", func(t *testing.T) {
+			require.Equal(t, int64(512*1024*1024), newDsInfo(t, 0).MaxResponseSize)
+		})
+
+		t.Run("is set", func(t *testing.T) {
+			require.Equal(t, int64(64*1024*1024), newDsInfo(t, 64).MaxResponseSize)
+		})
+	})
+
+	t.Run("queryCacheTTLSeconds", func(t *testing.T) {
+		newDsInfo := func(t *testing.T, ttlSeconds int64) es.DatasourceInfo {
+			settingsJSON, err := json.Marshal(datasourceInfo{TimeField: "@timestamp", QueryCacheTTLSeconds: ttlSeconds})
+			require.NoError(t, err)
+			instance, err := newInstanceSettings(httpclient.NewProvider())(context.Background(), backend.DataSourceInstanceSettings{
+				JSONData: json.RawMessage(settingsJSON),
+			})
+			require.NoError(t, err)
+			return instance.(es.DatasourceInfo)
+		}
+
+		t.Run("disables caching by default", func(t *testing.T) {
+			require.Zero(t, newDsInfo(t, 0).QueryCacheTTL)
+		})
+
+		t.Run("is set", func(t *testing.T) {
+			require.Equal(t, 30*time.Second, newDsInfo(t, 30).QueryCacheTTL)
+		})
+
+		t.Run("is limited", func(t *testing.T) {
+			require.Equal(t, maxQueryCacheTTL, newDsInfo(t, 86400).QueryCacheTTL)
+		})
+	})
+
 	t.Run("timeField", func(t *testing.T) {
 		t.Run("is nil", func(t *testing.T) {
 			dsInfo := datasourceInfo{
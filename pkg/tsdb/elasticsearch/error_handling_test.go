@@ -3,6 +3,7 @@ package elasticsearch
 import (
 	"testing"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,8 +41,10 @@ func TestErrorAvgMissingField(t *testing.T) {
 	result, err := queryDataTestWithResponseCode(query, 400, response)
 	require.NoError(t, err)
 
-	// FIXME: we should return the received error message
-	require.Len(t, result.response.Responses, 0)
+	require.Len(t, result.response.Responses, 1)
+	dataResponse := result.response.Responses["A"]
+	require.EqualError(t, dataResponse.Error, "Required one of fields [field, script], but none were specified. ")
+	require.Equal(t, backend.ErrorSourcePlugin, dataResponse.ErrorSource)
 }
 
 func TestErrorAvgMissingFieldNoDetailedErrors(t *testing.T) {
@@ -68,8 +71,8 @@ func TestErrorAvgMissingFieldNoDetailedErrors(t *testing.T) {
 	result, err := queryDataTestWithResponseCode(query, 400, response)
 	require.NoError(t, err)
 
-	// FIXME: we should return the received error message
-	require.Len(t, result.response.Responses, 0)
+	require.Len(t, result.response.Responses, 1)
+	require.EqualError(t, result.response.Responses["A"].Error, "No ElasticsearchException found")
 }
 
 func TestErrorTooManyDateHistogramBuckets(t *testing.T) {
@@ -117,6 +120,77 @@ func TestErrorTooManyDateHistogramBuckets(t *testing.T) {
 	require.True(t, ok)
 	require.Len(t, dataResponse.Frames, 0)
 	require.ErrorContains(t, dataResponse.Error, "Trying to create too many buckets. Must be less than or equal to: [65536].")
+	require.Equal(t, backend.ErrorSourceDownstream, dataResponse.ErrorSource)
+}
+
+func TestErrorTaxonomy(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    any
+		message string
+		source  backend.ErrorSource
+	}{
+		{
+			name: "index not found",
+			body: map[string]any{
+				"root_cause": []any{map[string]any{"type": "index_not_found_exception", "reason": "no such index [logs]", "index": "logs"}},
+				"type":       "index_not_found_exception",
+				"reason":     "no such index [logs]",
+				"index":      "logs",
+			},
+			message: "Index not found: logs. Check the index name or pattern in the data source settings",
+			source:  backend.ErrorSourceDownstream,
+		},
+		{
+			name: "parsing exception in a shard failure",
+			body: map[string]any{
+				"root_cause": []any{map[string]any{"type": "query_shard_exception", "reason": "Failed to parse query [foo:(]"}},
+				"type":       "search_phase_execution_exception",
+				"reason":     "all shards failed",
+			},
+			message: "Elasticsearch failed to parse the query: Failed to parse query [foo:(]. Check the syntax of the query",
+			source:  backend.ErrorSourceDownstream,
+		},
+		{
+			name: "circuit breaker",
+			body: map[string]any{
+				"type":   "search_phase_execution_exception",
+				"reason": "",
+				"caused_by": map[string]any{
+					"type":   "circuit_breaking_exception",
+					"reason": "[parent] Data too large",
+				},
+			},
+			message: "Elasticsearch rejected the query because it would use too much memory: [parent] Data too large. Reduce the time range of the query or the number of buckets",
+			source:  backend.ErrorSourceDownstream,
+		},
+		{
+			name: "security exception",
+			body: map[string]any{
+				"root_cause": []any{map[string]any{"type": "security_exception", "reason": "action [indices:data/read/search] is unauthorized"}},
+				"type":       "security_exception",
+				"reason":     "action [indices:data/read/search] is unauthorized",
+			},
+			message: "Elasticsearch denied access: action [indices:data/read/search] is unauthorized. Check the credentials of the data source and the privileges of its user",
+			source:  backend.ErrorSourceDownstream,
+		},
+		{
+			name: "unknown error",
+			body: map[string]any{
+				"type":   "illegal_state_exception",
+				"reason": "something went wrong",
+			},
+			message: "something went wrong",
+			source:  backend.ErrorSourcePlugin,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := errorResponse(tt.body)
+			require.EqualError(t, res.Error, tt.message)
+			require.Equal(t, tt.source, res.ErrorSource)
+		})
+	}
 }
 
 func TestNonElasticError(t *testing.T) {
@@ -0,0 +1,144 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// Types of the errors of Elasticsearch that are caused by the query, the indices or the configuration of the
+// cluster, rather than by Grafana.
+const (
+	indexNotFoundException   = "index_not_found_exception"
+	parsingException         = "parsing_exception"
+	xContentParseException   = "x_content_parse_exception"
+	queryShardException      = "query_shard_exception"
+	circuitBreakingException = "circuit_breaking_exception"
+	securityException        = "security_exception"
+	tooManyBucketsException  = "too_many_buckets_exception"
+)
+
+// elasticsearchError is an error returned by Elasticsearch, with a message that tells the user how to solve it.
+type elasticsearchError struct {
+	// errType is the type of the error that was recognized, or the type of the error if none was recognized.
+	errType string
+	reason  string
+	message string
+}
+
+func (e *elasticsearchError) Error() string {
+	return e.message
+}
+
+// isDownstream returns true if the error is caused by the query, the indices or the configuration of the cluster.
+func (e *elasticsearchError) isDownstream() bool {
+	switch e.errType {
+	case indexNotFoundException, parsingException, xContentParseException, queryShardException,
+		circuitBreakingException, securityException, tooManyBucketsException:
+		return true
+	}
+	return false
+}
+
+// newElasticsearchError parses the error of an Elasticsearch response. The error is usually an object with the type,
+// the reason and the causes of the error, but it is a plain string if detailed errors are disabled in the cluster.
+func newElasticsearchError(body any) *elasticsearchError {
+	if s, ok := body.(string); ok {
+		return &elasticsearchError{reason: s, message: s}
+	}
+	json := simplejson.NewFromAny(body)
+	e := &elasticsearchError{
+		errType: errorType(json),
+		reason:  errorReason(json),
+	}
+
+	switch e.errType {
+	case indexNotFoundException:
+		index := findCause(json, indexNotFoundException).Get("index").MustString()
+		if index == "" {
+			e.message = fmt.Sprintf("Index not found: %s. Check the index name or pattern in the data source settings", e.reason)
+		} else {
+			e.message = fmt.Sprintf("Index not found: %s. Check the index name or pattern in the data source settings", index)
+		}
+	case parsingException, xContentParseException, queryShardException:
+		e.message = fmt.Sprintf("Elasticsearch failed to parse the query: %s. Check the syntax of the query", e.reason)
+	case circuitBreakingException:
+		e.message = fmt.Sprintf("Elasticsearch rejected the query because it would use too much memory: %s. Reduce the time range of the query or the number of buckets", e.reason)
+	case securityException:
+		e.message = fmt.Sprintf("Elasticsearch denied access: %s. Check the credentials of the data source and the privileges of its user", e.reason)
+	case tooManyBucketsException:
+		e.message = fmt.Sprintf("%s Increase the interval or reduce the time range of the query", e.reason)
+	default:
+		e.message = e.reason
+	}
+	return e
+}
+
+// errorResponse returns the data response for an error returned by Elasticsearch. Errors that are caused by the
+// query, the indices or the cluster are downstream errors, other errors are plugin errors.
+func errorResponse(body any) backend.DataResponse {
+	err := newElasticsearchError(body)
+	if err.isDownstream() {
+		return errorsource.Response(errorsource.DownstreamError(err, false))
+	}
+	return errorsource.Response(errorsource.PluginError(err, false))
+}
+
+// errorType returns the first known type among the root causes, the chain of causes and the error itself.
+// If none of the types is known, it returns the type of the error.
+func errorType(json *simplejson.Json) string {
+	for _, t := range errorTypes(json) {
+		switch t {
+		case indexNotFoundException, parsingException, xContentParseException, queryShardException,
+			circuitBreakingException, securityException, tooManyBucketsException:
+			return t
+		}
+	}
+	return json.Get("type").MustString()
+}
+
+func errorTypes(json *simplejson.Json) []string {
+	var types []string
+	for _, rc := range json.Get("root_cause").MustArray() {
+		types = append(types, simplejson.NewFromAny(rc).Get("type").MustString())
+	}
+	for cause := json.Get("caused_by"); cause.Interface() != nil; cause = cause.Get("caused_by") {
+		types = append(types, cause.Get("type").MustString())
+	}
+	return append(types, json.Get("type").MustString())
+}
+
+// findCause returns the root cause or the cause of the error that has the type, or the error itself.
+func findCause(json *simplejson.Json, errType string) *simplejson.Json {
+	for _, rc := range json.Get("root_cause").MustArray() {
+		if cause := simplejson.NewFromAny(rc); cause.Get("type").MustString() == errType {
+			return cause
+		}
+	}
+	for cause := json.Get("caused_by"); cause.Interface() != nil; cause = cause.Get("caused_by") {
+		if cause.Get("type").MustString() == errType {
+			return cause
+		}
+	}
+	return json
+}
+
+func errorReason(json *simplejson.Json) string {
+	reason := json.Get("reason").MustString()
+	rootCauseReason := json.Get("root_cause").GetIndex(0).Get("reason").MustString()
+	causedByReason := json.Get("caused_by").Get("reason").MustString()
+
+	switch {
+	case rootCauseReason != "":
+		return rootCauseReason
+	case reason != "":
+		return reason
+	case causedByReason != "":
+		return causedByReason
+	default:
+		return "Unknown elasticsearch error response"
+	}
+}
@@ -3,13 +3,17 @@ package elasticsearch
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 )
 
 func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
@@ -100,8 +104,48 @@ func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthReque
 		message = "Elasticsearch data source is not healthy"
 	}
 
-	return &backend.CheckHealthResult{
+	result := &backend.CheckHealthResult{
 		Status:  status,
 		Message: message,
-	}, nil
+	}
+	if status == backend.HealthStatusOk && ds.Database != "" {
+		checkIndices(ctx, ds, result)
+	}
+	return result, nil
+}
+
+// checkIndices resolves the index of the data source to its concrete indices and fails the health check if none of
+// them maps the time field. If only some of them map it, for example because an alias spans indices with different
+// mappings, the data source stays healthy but the message names the indices whose documents queries will miss.
+// The concrete indices are returned in the details of the result.
+func checkIndices(ctx context.Context, ds *es.DatasourceInfo, result *backend.CheckHealthResult) {
+	logger := eslog.FromContext(ctx)
+
+	indices, err := resolveIndices(ctx, ds)
+	if err != nil {
+		// users can lack the privileges to get aliases and mappings, which does not make the data source unusable
+		logger.Warn("Failed to resolve indices", "error", err, "index", ds.Database)
+		return
+	}
+	if details, err := json.Marshal(indices); err == nil {
+		result.JSONDetails = details
+	}
+
+	if len(indices.Indices) == 0 {
+		result.Status = backend.HealthStatusError
+		result.Message = fmt.Sprintf("No index matches %s", ds.Database)
+		return
+	}
+	if indices.TimeField == "" {
+		return
+	}
+	missing := indices.missingTimeField()
+	switch {
+	case len(missing) == len(indices.Indices):
+		result.Status = backend.HealthStatusError
+		result.Message = fmt.Sprintf("No index of %s maps the time field %s", ds.Database, indices.TimeField)
+	case len(missing) > 0:
+		result.Message = fmt.Sprintf("%s, but the time field %s is missing in the indices %s", result.Message,
+			indices.TimeField, strings.Join(missing, ", "))
+	}
 }
@@ -0,0 +1,201 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+// indicesResourcePath is the resource path that returns the concrete indices behind the index of the data source
+const indicesResourcePath = "indices"
+
+// indexMetadata is the metadata of a concrete index that the index of the data source resolves to.
+type indexMetadata struct {
+	Name string `json:"name"`
+	// Aliases are the aliases of the index that the index of the data source refers to.
+	Aliases []string `json:"aliases,omitempty"`
+	// WriteIndex is true if the index is the write index of one of the aliases.
+	WriteIndex   bool `json:"writeIndex"`
+	HasTimeField bool `json:"hasTimeField"`
+}
+
+// indicesResponse is the response of the indices resource.
+type indicesResponse struct {
+	TimeField string          `json:"timeField"`
+	Indices   []indexMetadata `json:"indices"`
+}
+
+// missingTimeField returns the names of the indices that do not map the time field.
+func (r indicesResponse) missingTimeField() []string {
+	var names []string
+	for _, idx := range r.Indices {
+		if !idx.HasTimeField {
+			names = append(names, idx.Name)
+		}
+	}
+	return names
+}
+
+// aliasesResponse is the response of the get alias API, by concrete index.
+type aliasesResponse map[string]struct {
+	Aliases map[string]struct {
+		IsWriteIndex *bool `json:"is_write_index"`
+	} `json:"aliases"`
+}
+
+// fieldMappingResponse is the response of the get field mapping API, by concrete index.
+type fieldMappingResponse map[string]struct {
+	Mappings map[string]json.RawMessage `json:"mappings"`
+}
+
+// indexExpression returns the index expression of the data source. For index patterns with an interval, it is the
+// index of the current interval.
+func indexExpression(ds *es.DatasourceInfo) (string, error) {
+	now := time.Now()
+	indices, err := es.GetIndices(ds, backend.TimeRange{From: now, To: now})
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(indices, ","), nil
+}
+
+// resolveIndices resolves the index of the data source, which can be a list of indices, aliases and wildcard patterns,
+// to its concrete indices, and reports for each of them whether it maps the time field. Aliases can span indices with
+// different mappings, and searches of indices without the time field return partial results.
+func resolveIndices(ctx context.Context, ds *es.DatasourceInfo) (indicesResponse, error) {
+	expression, err := indexExpression(ds)
+	if err != nil {
+		return indicesResponse{}, err
+	}
+	patterns := strings.Split(expression, ",")
+	result := indicesResponse{TimeField: ds.ConfiguredFields.TimeField}
+
+	var aliases aliasesResponse
+	if err := getIndexJSON(ctx, ds, path.Join(expression, "_alias"), &aliases); err != nil {
+		return indicesResponse{}, err
+	}
+	hasTimeField := map[string]bool{}
+	if result.TimeField != "" {
+		var mappings fieldMappingResponse
+		if err := getIndexJSON(ctx, ds, path.Join(expression, "_mapping/field", result.TimeField), &mappings); err != nil {
+			return indicesResponse{}, err
+		}
+		for name, m := range mappings {
+			_, ok := m.Mappings[result.TimeField]
+			hasTimeField[name] = ok
+		}
+	}
+
+	result.Indices = make([]indexMetadata, 0, len(aliases))
+	// explicit are the aliases that set is_write_index on any of their indices
+	explicit := map[string]bool{}
+	for name, idx := range aliases {
+		metadata := indexMetadata{Name: name, HasTimeField: hasTimeField[name]}
+		for alias, settings := range idx.Aliases {
+			if !matchesAnyPattern(alias, patterns) {
+				continue
+			}
+			metadata.Aliases = append(metadata.Aliases, alias)
+			if settings.IsWriteIndex != nil {
+				explicit[alias] = true
+				metadata.WriteIndex = metadata.WriteIndex || *settings.IsWriteIndex
+			}
+		}
+		sort.Strings(metadata.Aliases)
+		result.Indices = append(result.Indices, metadata)
+	}
+	sort.Slice(result.Indices, func(i, j int) bool {
+		return result.Indices[i].Name < result.Indices[j].Name
+	})
+	markSingleWriteIndices(result.Indices, explicit)
+	return result, nil
+}
+
+// markSingleWriteIndices marks the indices that are the only index of an alias as its write index, because
+// Elasticsearch writes to the only index of an alias unless the alias sets is_write_index.
+func markSingleWriteIndices(indices []indexMetadata, explicit map[string]bool) {
+	count := map[string]int{}
+	for _, idx := range indices {
+		for _, alias := range idx.Aliases {
+			count[alias]++
+		}
+	}
+	for i, idx := range indices {
+		for _, alias := range idx.Aliases {
+			if count[alias] == 1 && !explicit[alias] {
+				indices[i].WriteIndex = true
+			}
+		}
+	}
+}
+
+// matchesAnyPattern returns true if the name matches one of the index patterns, which can contain wildcards.
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(strings.TrimSpace(p), name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getIndexJSON sends a GET request for the path to Elasticsearch and decodes the JSON response into v.
+func getIndexJSON(ctx context.Context, ds *es.DatasourceInfo, p string, v any) error {
+	esUrl, err := url.Parse(ds.URL)
+	if err != nil {
+		return err
+	}
+	esUrl.Path = path.Join(esUrl.Path, p)
+	esUrl.RawQuery = "ignore_unavailable=true&allow_no_indices=true"
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, esUrl.String(), nil)
+	if err != nil {
+		return err
+	}
+	response, err := ds.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %d from %s: %s", response.StatusCode, p, body)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// handleIndices returns the concrete indices that the index of the data source resolves to, and whether they map the
+// time field.
+func (s *Service) handleIndices(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := eslog.FromContext(ctx)
+
+	ds, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	result, err := resolveIndices(ctx, ds)
+	if err != nil {
+		logger.Error("Failed to resolve indices", "error", err, "index", ds.Database)
+		return sendResourceError(sender, http.StatusBadGateway, err)
+	}
+	return sendResourceJSON(sender, http.StatusOK, result)
+}
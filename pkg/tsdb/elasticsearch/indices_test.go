@@ -0,0 +1,115 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+// newIndicesTestServer returns a server that responds with the aliases and the time field mappings of the index logs.
+func newIndicesTestServer(t *testing.T, aliases, mappings string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_cluster/health":
+			_, _ = w.Write([]byte(`{"status": "green"}`))
+		case "/logs/_alias":
+			_, _ = w.Write([]byte(aliases))
+		case "/logs/_mapping/field/@timestamp":
+			_, _ = w.Write([]byte(mappings))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// the alias logs has the write index logs-2, and the index logs-1 that does not map the time field
+const (
+	testAliases = `{
+		"logs-1": {"aliases": {"logs": {"is_write_index": false}, "archive": {}}},
+		"logs-2": {"aliases": {"logs": {"is_write_index": true}}}
+	}`
+	testMappings = `{
+		"logs-1": {"mappings": {}},
+		"logs-2": {"mappings": {"@timestamp": {"full_name": "@timestamp", "mapping": {"@timestamp": {"type": "date"}}}}}
+	}`
+)
+
+func TestIndicesResource(t *testing.T) {
+	s := newResourceTestService(newIndicesTestServer(t, testAliases, testMappings))
+
+	res := callResource(t, s, indicesResourcePath, "")
+	require.Equal(t, http.StatusOK, res.Status)
+	var body indicesResponse
+	require.NoError(t, json.Unmarshal(res.Body, &body))
+	require.Equal(t, indicesResponse{
+		TimeField: "@timestamp",
+		Indices: []indexMetadata{
+			{Name: "logs-1", Aliases: []string{"logs"}},
+			{Name: "logs-2", Aliases: []string{"logs"}, WriteIndex: true, HasTimeField: true},
+		},
+	}, body)
+}
+
+func TestMarkSingleWriteIndices(t *testing.T) {
+	indices := []indexMetadata{
+		{Name: "a", Aliases: []string{"single"}},
+		{Name: "b", Aliases: []string{"explicit"}},
+		{Name: "c", Aliases: []string{"many"}},
+		{Name: "d", Aliases: []string{"many"}},
+	}
+	markSingleWriteIndices(indices, map[string]bool{"explicit": true})
+	require.True(t, indices[0].WriteIndex)
+	require.False(t, indices[1].WriteIndex)
+	require.False(t, indices[2].WriteIndex)
+	require.False(t, indices[3].WriteIndex)
+}
+
+func TestHealthcheckIndices(t *testing.T) {
+	checkHealth := func(t *testing.T, srv *httptest.Server) *backend.CheckHealthResult {
+		t.Helper()
+		res, err := newResourceTestService(srv).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should name the indices without the time field", func(t *testing.T) {
+		res := checkHealth(t, newIndicesTestServer(t, testAliases, testMappings))
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Elasticsearch data source is healthy, but the time field @timestamp is missing in the indices logs-1", res.Message)
+		require.Contains(t, string(res.JSONDetails), `"writeIndex":true`)
+	})
+
+	t.Run("should fail if no index maps the time field", func(t *testing.T) {
+		res := checkHealth(t, newIndicesTestServer(t, testAliases, `{"logs-1": {"mappings": {}}, "logs-2": {"mappings": {}}}`))
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Equal(t, "No index of logs maps the time field @timestamp", res.Message)
+	})
+
+	t.Run("should fail if no index matches", func(t *testing.T) {
+		res := checkHealth(t, newIndicesTestServer(t, `{}`, `{}`))
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Equal(t, "No index matches logs", res.Message)
+	})
+
+	t.Run("should stay healthy if the indices cannot be resolved", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/_cluster/health" {
+				_, _ = w.Write([]byte(`{"status": "green"}`))
+				return
+			}
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		t.Cleanup(srv.Close)
+		res := checkHealth(t, srv)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Elasticsearch data source is healthy", res.Message)
+	})
+}
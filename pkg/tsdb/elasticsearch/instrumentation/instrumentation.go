@@ -16,15 +16,58 @@ var (
 		Help:      "Duration of Elasticsearch parsing the response in seconds",
 		Buckets:   []float64{.001, 0.0025, .005, .0075, .01, .02, .03, .04, .05, .075, .1, .25, .5, 1, 5, 10, 25},
 	}, []string{"status", "endpoint"})
+
+	clientStageDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "grafana",
+		Name:      "elasticsearch_client_stage_duration_seconds",
+		Help:      "Duration of the stages of the requests of the Elasticsearch client in seconds",
+		Buckets:   []float64{.001, 0.0025, .005, .0075, .01, .02, .03, .04, .05, .075, .1, .25, .5, 1, 5, 10, 25, 60},
+	}, []string{"stage", "status", "endpoint"})
+
+	queryCacheRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "elasticsearch_query_cache_requests_total",
+		Help:      "Total number of queries of data sources with query caching, by whether their response was cached",
+	}, []string{"result"})
 )
 
 const (
 	EndpointQueryData = "queryData"
 )
 
+// Stages of the requests of the Elasticsearch client.
+const (
+	// StageBuild is the building of the search requests from the queries.
+	StageBuild = "build"
+	// StageEncode is the encoding of the search requests to the body of the multi search request.
+	StageEncode = "encode"
+	// StageNetwork is the time until Elasticsearch returns the headers of the response.
+	StageNetwork = "network"
+	// StageDecode is the reading and decoding of the body of the response.
+	StageDecode = "decode"
+)
+
 func UpdatePluginParsingResponseDurationSeconds(ctx context.Context, duration time.Duration, status string) {
-	histogram := pluginParsingResponseDurationSeconds.WithLabelValues(status, EndpointQueryData)
+	observe(ctx, pluginParsingResponseDurationSeconds.WithLabelValues(status, EndpointQueryData), duration)
+}
+
+// UpdateClientStageDurationSeconds observes the duration of a stage of a request of the client.
+func UpdateClientStageDurationSeconds(ctx context.Context, stage string, duration time.Duration, status string) {
+	observe(ctx, clientStageDurationSeconds.WithLabelValues(stage, status, EndpointQueryData), duration)
+}
+
+// Results of the lookups of the query cache.
+const (
+	QueryCacheHit  = "hit"
+	QueryCacheMiss = "miss"
+)
+
+// IncQueryCacheRequests counts a lookup of the response of a query in the query cache.
+func IncQueryCacheRequests(result string) {
+	queryCacheRequestsTotal.WithLabelValues(result).Inc()
+}
 
+func observe(ctx context.Context, histogram prometheus.Observer, duration time.Duration) {
 	if traceID := tracing.TraceIDFromContext(ctx, true); traceID != "" {
 		histogram.(prometheus.ExemplarObserver).ObserveWithExemplar(duration.Seconds(), prometheus.Labels{"traceID": traceID})
 	} else {
@@ -16,6 +16,13 @@ type Query struct {
 	IntervalMs    int64
 	RefID         string
 	MaxDataPoints int64
+
+	// AdaptiveSampling enables the sampling of the documents of time series queries whose time range exceeds
+	// SamplingThreshold.
+	AdaptiveSampling  bool `json:"adaptiveSampling"`
+	SamplingThreshold time.Duration
+	// SamplingProbability is the probability of the random sampler used by the query, or 0 if it is not sampled.
+	SamplingProbability float64
 }
 
 // BucketAgg represents a bucket aggregation of the time series query model of the datasource
@@ -28,6 +35,8 @@ type BucketAgg struct {
 
 // MetricAgg represents a metric aggregation of the time series query model of the datasource
 type MetricAgg struct {
+	// Alias is the pattern of the names of the series of the metric. It takes precedence over the alias of the query.
+	Alias             string            `json:"alias"`
 	Field             string            `json:"field"`
 	Hide              bool              `json:"hide"`
 	ID                string            `json:"id"`
@@ -39,25 +48,26 @@ type MetricAgg struct {
 }
 
 var metricAggType = map[string]string{
-	"count":          "Count",
-	"avg":            "Average",
-	"sum":            "Sum",
-	"max":            "Max",
-	"min":            "Min",
-	"extended_stats": "Extended Stats",
-	"percentiles":    "Percentiles",
-	"top_metrics":    "Top Metrics",
-	"cardinality":    "Unique Count",
-	"moving_avg":     "Moving Average",
-	"moving_fn":      "Moving Function",
-	"cumulative_sum": "Cumulative Sum",
-	"derivative":     "Derivative",
-	"serial_diff":    "Serial Difference",
-	"bucket_script":  "Bucket Script",
-	"raw_document":   "Raw Document",
-	"raw_data":       "Raw Data",
-	"rate":           "Rate",
-	"logs":           "Logs",
+	"count":           "Count",
+	"avg":             "Average",
+	"sum":             "Sum",
+	"max":             "Max",
+	"min":             "Min",
+	"extended_stats":  "Extended Stats",
+	"percentiles":     "Percentiles",
+	"top_metrics":     "Top Metrics",
+	"cardinality":     "Unique Count",
+	"moving_avg":      "Moving Average",
+	"moving_fn":       "Moving Function",
+	"cumulative_sum":  "Cumulative Sum",
+	"derivative":      "Derivative",
+	"serial_diff":     "Serial Difference",
+	"bucket_script":   "Bucket Script",
+	"raw_document":    "Raw Document",
+	"raw_data":        "Raw Data",
+	"rate":            "Rate",
+	"logs":            "Logs",
+	"scripted_metric": "Scripted Metric",
 }
 
 var extendedStats = map[string]string{
@@ -2,6 +2,7 @@ package elasticsearch
 
 import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -33,6 +34,16 @@ func parseQuery(tsdbQuery []backend.DataQuery, logger log.Logger) ([]*Query, err
 		intervalMs := model.Get("intervalMs").MustInt64(0)
 		interval := q.Interval
 
+		adaptiveSampling := model.Get("adaptiveSampling").MustBool(false)
+		samplingThreshold := defaultSamplingThreshold
+		if threshold := model.Get("samplingThreshold").MustString(""); threshold != "" {
+			samplingThreshold, err = gtime.ParseDuration(threshold)
+			if err != nil {
+				logger.Error("Failed to parse sampling threshold in query", "error", err, "model", string(q.JSON))
+				return nil, err
+			}
+		}
+
 		queries = append(queries, &Query{
 			RawQuery:      rawQuery,
 			BucketAggs:    bucketAggs,
@@ -42,6 +53,9 @@ func parseQuery(tsdbQuery []backend.DataQuery, logger log.Logger) ([]*Query, err
 			IntervalMs:    intervalMs,
 			RefID:         q.RefID,
 			MaxDataPoints: q.MaxDataPoints,
+
+			AdaptiveSampling:  adaptiveSampling,
+			SamplingThreshold: samplingThreshold,
 		})
 	}
 
@@ -82,6 +96,7 @@ func parseMetrics(model *simplejson.Json) ([]*MetricAgg, error) {
 		metricJSON := simplejson.NewFromAny(t)
 		metric := &MetricAgg{}
 
+		metric.Alias = metricJSON.Get("alias").MustString()
 		metric.Field = metricJSON.Get("field").MustString()
 		metric.Hide = metricJSON.Get("hide").MustBool(false)
 		metric.ID = metricJSON.Get("id").MustString()
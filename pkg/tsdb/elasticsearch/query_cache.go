@@ -0,0 +1,116 @@
+package elasticsearch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+	"github.com/grafana/grafana/pkg/tsdb/elasticsearch/instrumentation"
+)
+
+const (
+	// maxQueryCacheTTL is the maximum time that the responses of queries are cached.
+	maxQueryCacheTTL = time.Hour
+	// queryCacheCleanupInterval is how often the expired responses are removed from the query cache.
+	queryCacheCleanupInterval = 5 * time.Minute
+	// cacheSkipHeaderName is the header of the requests that bypass the query cache.
+	cacheSkipHeaderName = "X-Cache-Skip"
+)
+
+// cachedResponse is a cached response of a query. The frames are encoded, so that the consumers of a response cannot
+// modify the frames of the responses that are served from the cache later.
+type cachedResponse struct {
+	frames [][]byte
+	status backend.Status
+}
+
+// skipQueryCache returns true if the request asks to bypass the query cache with the X-Cache-Skip header. Alert rule
+// evaluations set it, so that they always query the most recent documents.
+func skipQueryCache(req *backend.QueryDataRequest) bool {
+	return req.Headers[cacheSkipHeaderName] == "true" ||
+		req.GetHTTPHeader(cacheSkipHeaderName) == "true"
+}
+
+// queryCacheKey returns the cache key of the response of a query. The time range of the query is keyed by its exact
+// duration and its end rounded down to the TTL, so that the repeated loads of a dashboard with a relative time range
+// are served from the cache until the TTL elapsed, while time ranges of different durations never share a response.
+// The user is part of the key because the query can be executed with the forwarded OAuth identity of the user.
+func queryCacheKey(pCtx backend.PluginContext, ttl time.Duration, q backend.DataQuery) string {
+	bucket := ttl.Milliseconds()
+	duration := q.TimeRange.Duration().Milliseconds()
+	to := q.TimeRange.To.UnixMilli()
+	to -= to % bucket
+
+	var uid string
+	var updated int64
+	if pCtx.DataSourceInstanceSettings != nil {
+		uid = pCtx.DataSourceInstanceSettings.UID
+		updated = pCtx.DataSourceInstanceSettings.Updated.UnixMilli()
+	}
+	var login string
+	if pCtx.User != nil {
+		login = pCtx.User.Login
+	}
+	query := sha256.Sum256(q.JSON)
+	return fmt.Sprintf("%d/%s/%d/%s/%d/%d/%d/%d/%s", pCtx.OrgID, uid, updated, login, duration, to, q.MaxDataPoints,
+		q.Interval.Milliseconds(), hex.EncodeToString(query[:]))
+}
+
+// queryDataCached serves the responses of the queries from the query cache, and executes the queries whose responses
+// are not cached. Only successful responses are cached.
+func (s *Service) queryDataCached(ctx context.Context, req *backend.QueryDataRequest, dsInfo *es.DatasourceInfo, logger log.Logger) (*backend.QueryDataResponse, error) {
+	result := backend.NewQueryDataResponse()
+	keys := make(map[string]string, len(req.Queries))
+	misses := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		key := queryCacheKey(req.PluginContext, dsInfo.QueryCacheTTL, q)
+		if cached, ok := s.queryCache.Get(key); ok {
+			if res, err := decodeCachedResponse(cached.(cachedResponse)); err == nil {
+				instrumentation.IncQueryCacheRequests(instrumentation.QueryCacheHit)
+				result.Responses[q.RefID] = res
+				continue
+			}
+		}
+		instrumentation.IncQueryCacheRequests(instrumentation.QueryCacheMiss)
+		keys[q.RefID] = key
+		misses = append(misses, q)
+	}
+	logger.Debug("Looked up the responses of the queries in the query cache", "queriesLength", len(req.Queries), "misses", len(misses))
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	res, err := queryData(ctx, misses, dsInfo, logger, s.tracer)
+	if err != nil {
+		return res, err
+	}
+	for refID, r := range res.Responses {
+		result.Responses[refID] = r
+		key, ok := keys[refID]
+		if !ok || r.Error != nil {
+			continue
+		}
+		frames, err := r.Frames.MarshalArrow()
+		if err != nil {
+			logger.Warn("Failed to encode the response of a query for the query cache", "error", err, "refId", refID)
+			continue
+		}
+		s.queryCache.Set(key, cachedResponse{frames: frames, status: r.Status}, dsInfo.QueryCacheTTL)
+	}
+	return result, nil
+}
+
+func decodeCachedResponse(cached cachedResponse) (backend.DataResponse, error) {
+	frames, err := data.UnmarshalArrowFrames(cached.frames)
+	if err != nil {
+		return backend.DataResponse{}, err
+	}
+	return backend.DataResponse{Frames: frames, Status: cached.status}, nil
+}
@@ -0,0 +1,136 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := func(refID string, shift time.Duration) backend.DataQuery {
+		return backend.DataQuery{
+			RefID: refID,
+			JSON: json.RawMessage(`{
+				"refId": "` + refID + `",
+				"metrics": [{"type": "count", "id": "1"}],
+				"bucketAggs": [{"type": "date_histogram", "id": "2", "field": "@timestamp", "settings": {"interval": "1m"}}]
+			}`),
+			TimeRange:     backend.TimeRange{From: from.Add(shift), To: from.Add(time.Hour + shift)},
+			MaxDataPoints: 100,
+			Interval:      time.Minute,
+		}
+	}
+
+	// newService returns a service whose data source caches the responses of queries for 5 minutes, and the number
+	// of the requests to Elasticsearch.
+	newService := func(t *testing.T, status int) (*Service, *int) {
+		t.Helper()
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requests++
+			rw.WriteHeader(status)
+			_, _ = rw.Write([]byte(`{"responses": [{"aggregations": {"2": {"buckets": [{"key": 1704067200000, "doc_count": 5}]}}}]}`))
+		}))
+		t.Cleanup(srv.Close)
+		s := newResourceTestService(srv)
+		s.im.(*resourceInstanceManager).ds.QueryCacheTTL = 5 * time.Minute
+		return s, &requests
+	}
+	queryData := func(t *testing.T, s *Service, headers map[string]string, queries ...backend.DataQuery) *backend.QueryDataResponse {
+		t.Helper()
+		res, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1, User: &backend.User{Login: "viewer"}},
+			Headers:       headers,
+			Queries:       queries,
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should serve repeated queries from the cache", func(t *testing.T) {
+		s, requests := newService(t, http.StatusOK)
+
+		first := queryData(t, s, nil, query("A", 0))
+		require.NoError(t, first.Responses["A"].Error)
+		require.Equal(t, 1, *requests)
+
+		// the time range of a reload of a dashboard moved, but is still in the same bucket
+		second := queryData(t, s, nil, query("A", time.Minute))
+		require.Equal(t, 1, *requests)
+		firstFrames, err := json.Marshal(first.Responses["A"].Frames)
+		require.NoError(t, err)
+		secondFrames, err := json.Marshal(second.Responses["A"].Frames)
+		require.NoError(t, err)
+		require.JSONEq(t, string(firstFrames), string(secondFrames))
+
+		// only the query whose response is not cached is executed
+		third := queryData(t, s, nil, query("A", 0), query("B", 0))
+		require.Equal(t, 2, *requests)
+		require.Len(t, third.Responses, 2)
+	})
+
+	t.Run("should bypass the cache with the X-Cache-Skip header", func(t *testing.T) {
+		s, requests := newService(t, http.StatusOK)
+
+		queryData(t, s, nil, query("A", 0))
+		queryData(t, s, map[string]string{"X-Cache-Skip": "true"}, query("A", 0))
+		queryData(t, s, map[string]string{"http_X-Cache-Skip": "true"}, query("A", 0))
+		require.Equal(t, 3, *requests)
+	})
+
+	t.Run("should not cache failed responses", func(t *testing.T) {
+		s, requests := newService(t, http.StatusInternalServerError)
+
+		queryData(t, s, nil, query("A", 0))
+		queryData(t, s, nil, query("A", 0))
+		require.Equal(t, 2, *requests)
+	})
+
+	t.Run("should not cache the responses of data sources that do not enable it", func(t *testing.T) {
+		s, requests := newService(t, http.StatusOK)
+		s.im.(*resourceInstanceManager).ds.QueryCacheTTL = 0
+
+		queryData(t, s, nil, query("A", 0))
+		queryData(t, s, nil, query("A", 0))
+		require.Equal(t, 2, *requests)
+	})
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := backend.DataQuery{
+		RefID:     "A",
+		JSON:      json.RawMessage(`{"refId": "A"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+	}
+	pCtx := backend.PluginContext{OrgID: 1, User: &backend.User{Login: "viewer"}}
+	key := queryCacheKey(pCtx, 5*time.Minute, q)
+
+	shifted := q
+	shifted.TimeRange = backend.TimeRange{From: from.Add(4 * time.Minute), To: from.Add(time.Hour + 4*time.Minute)}
+	require.Equal(t, key, queryCacheKey(pCtx, 5*time.Minute, shifted))
+
+	shifted.TimeRange = backend.TimeRange{From: from.Add(5 * time.Minute), To: from.Add(time.Hour + 5*time.Minute)}
+	require.NotEqual(t, key, queryCacheKey(pCtx, 5*time.Minute, shifted))
+
+	// absolute time ranges that end in the same bucket, but start at different times
+	resized := q
+	resized.TimeRange = backend.TimeRange{From: from.Add(time.Minute), To: from.Add(time.Hour)}
+	require.NotEqual(t, key, queryCacheKey(pCtx, 5*time.Minute, resized))
+	resized.TimeRange = backend.TimeRange{From: from, To: from.Add(time.Hour + time.Minute)}
+	require.NotEqual(t, key, queryCacheKey(pCtx, 5*time.Minute, resized))
+
+	other := backend.PluginContext{OrgID: 1, User: &backend.User{Login: "editor"}}
+	require.NotEqual(t, key, queryCacheKey(other, 5*time.Minute, q))
+
+	changed := q
+	changed.JSON = json.RawMessage(`{"refId": "A", "query": "status:500"}`)
+	require.NotEqual(t, key, queryCacheKey(pCtx, 5*time.Minute, changed))
+}
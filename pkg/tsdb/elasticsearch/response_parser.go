@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strconv"
@@ -27,17 +28,24 @@ import (
 
 const (
 	// Metric types
-	countType         = "count"
-	percentilesType   = "percentiles"
-	extendedStatsType = "extended_stats"
-	topMetricsType    = "top_metrics"
+	countType          = "count"
+	percentilesType    = "percentiles"
+	extendedStatsType  = "extended_stats"
+	topMetricsType     = "top_metrics"
+	scriptedMetricType = "scripted_metric"
+	// Pipeline types
+	derivativeType    = "derivative"
+	cumulativeSumType = "cumulative_sum"
 	// Bucket types
 	dateHistType    = "date_histogram"
 	nestedType      = "nested"
 	histogramType   = "histogram"
 	filtersType     = "filters"
+	filterType      = "filter"
 	termsType       = "terms"
 	geohashGridType = "geohash_grid"
+	ipRangeType     = "ip_range"
+	ipPrefixType    = "ip_prefix"
 	//  Document types
 	rawDocumentType = "raw_document"
 	rawDataType     = "raw_data"
@@ -60,6 +68,9 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 	defer span.End()
 
 	for i, res := range responses {
+		// the decoded responses are released one by one once they are converted to frames, so that the responses
+		// of a large multi search request and all of their frames are not in memory at the same time
+		responses[i] = nil
 		_, resSpan := tracer.Start(ctx, "datasource.elastic.parseResponse.response", trace.WithAttributes(
 			attribute.String("queryMetricType", targets[i].Metrics[0].Type),
 		))
@@ -73,8 +84,7 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 			resSpan.SetStatus(codes.Error, string(me))
 			resSpan.End()
 			logger.Error("Processing error response from Elasticsearch", "error", string(me), "query", string(mt))
-			errResult := getErrorFromElasticResponse(res)
-			result.Responses[target.RefID] = errorsource.Response(errorsource.PluginError(errors.New(errResult), false))
+			result.Responses[target.RefID] = errorResponse(res.Error)
 			continue
 		}
 
@@ -104,7 +114,11 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 		} else {
 			// Process as metric query result
 			props := make(map[string]string)
-			err := processBuckets(res.Aggregations, target, &queryRes, props, 0)
+			aggregations := res.Aggregations
+			if target.SamplingProbability > 0 {
+				aggregations = unwrapSampledAggregations(aggregations)
+			}
+			err := processBuckets(aggregations, target, &queryRes, props, 0)
 			logger.Debug("Processed metric query response")
 			if err != nil {
 				mt, _ := json.Marshal(target)
@@ -115,10 +129,15 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 				logger.Error("Error processing buckets", "error", err, "query", string(mt), "aggregationsLength", len(res.Aggregations), "stage", es.StageParseResponse)
 				instrumentation.UpdatePluginParsingResponseDurationSeconds(ctx, time.Since(start), "error")
 				resSpan.End()
-				return &backend.QueryDataResponse{}, err
+				// the other queries of the request are not affected by the error
+				result.Responses[target.RefID] = errorsource.Response(errorsource.PluginError(err, false))
+				continue
 			}
 			nameFields(queryRes, target)
 			trimDatapoints(queryRes, target)
+			if target.SamplingProbability > 0 {
+				markSampled(&queryRes, target.SamplingProbability)
+			}
 
 			result.Responses[target.RefID] = queryRes
 		}
@@ -129,10 +148,33 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 	return &result, nil
 }
 
+// addWarningNotices adds the warnings returned by Elasticsearch, such as the use of deprecated features, as notices
+// to the frames of all responses. Warnings are returned for the whole multi-search request, so they cannot be
+// attributed to a single query.
+func addWarningNotices(result *backend.QueryDataResponse, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	notices := make([]data.Notice, 0, len(warnings))
+	for _, w := range warnings {
+		notices = append(notices, data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     w,
+		})
+	}
+	for _, res := range result.Responses {
+		for _, frame := range res.Frames {
+			frame.AppendNotices(notices...)
+		}
+	}
+}
+
 func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields es.ConfiguredFields, queryRes *backend.DataResponse, logger log.Logger) error {
 	propNames := make(map[string]bool)
 	docs := make([]map[string]interface{}, len(res.Hits.Hits))
 	searchWords := make(map[string]bool)
+	parseJSON := target.Metrics[0].Settings.Get("parseJSONMessage").MustBool(false) && configuredFields.LogMessageField != ""
+	parsedFields := make(map[string]bool)
 
 	for hitIdx, hit := range res.Hits.Hits {
 		var flattened map[string]interface{}
@@ -173,6 +215,12 @@ func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields
 			}
 		}
 
+		if parseJSON {
+			for _, name := range parseJSONMessage(doc, configuredFields.LogMessageField) {
+				parsedFields[name] = true
+			}
+		}
+
 		// we are going to add an `id` field with the concatenation of `_id` and `_index`
 		_, ok := doc["id"]
 		if !ok {
@@ -209,6 +257,7 @@ func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields
 	frame := data.NewFrame("", fields...)
 	setPreferredVisType(frame, data.VisTypeLogs)
 	setLogsCustomMeta(frame, searchWords, stringToIntWithDefaultValue(target.Metrics[0].Settings.Get("limit").MustString(), defaultSize))
+	frame.Meta.Custom.(map[string]interface{})["detectedFields"] = detectFields(fields, configuredFields, parsedFields)
 	frames = append(frames, frame)
 	queryRes.Frames = frames
 
@@ -420,6 +469,13 @@ func processBuckets(aggs map[string]interface{}, target *Query,
 			}
 			continue
 		}
+		if aggDef.Type == filterType {
+			err = processFilterBucket(esAgg, aggDef, target, queryResult, props, depth)
+			if err != nil {
+				return err
+			}
+			continue
+		}
 
 		if depth == maxDepth {
 			if aggDef.Type == dateHistType {
@@ -481,6 +537,36 @@ func processBuckets(aggs map[string]interface{}, target *Query,
 	return nil
 }
 
+// processFilterBucket processes the response of a filter aggregation, which is a single bucket without key. The label of
+// the filter is used as key, like the keys of the buckets of a filters aggregation.
+func processFilterBucket(esAgg *simplejson.Json, aggDef *BucketAgg, target *Query,
+	queryResult *backend.DataResponse, props map[string]string, depth int) error {
+	label := filterAggLabel(aggDef)
+	if depth == len(target.BucketAggs)-1 {
+		bucket := esAgg.MustMap()
+		bucket["key"] = label
+		buckets := simplejson.NewFromAny(map[string]any{"buckets": []any{bucket}})
+		return processAggregationDocs(buckets, &BucketAgg{ID: aggDef.ID, Type: aggDef.Type, Field: "filter"}, target, queryResult, props)
+	}
+
+	newProps := make(map[string]string, len(props)+1)
+	for k, v := range props {
+		newProps[k] = v
+	}
+	newProps["filter"] = label
+	return processBuckets(esAgg.MustMap(), target, queryResult, newProps, depth+1)
+}
+
+func filterAggLabel(aggDef *BucketAgg) string {
+	if label := aggDef.Settings.Get("label").MustString(); label != "" {
+		return label
+	}
+	if query := aggDef.Settings.Get("query").MustString(); query != "" {
+		return query
+	}
+	return "*"
+}
+
 func newTimeSeriesFrame(timeData []time.Time, tags map[string]string, values []*float64) *data.Frame {
 	frame := data.NewFrame("",
 		data.NewField(data.TimeSeriesTimeFieldName, nil, timeData),
@@ -681,7 +767,94 @@ func processDefaultMetric(metric *MetricAgg, buckets []*simplejson.Json, props m
 		timeVector = append(timeVector, timeValue)
 		values = append(values, value)
 	}
-	return data.Frames{newTimeSeriesFrame(timeVector, tags, values)}, nil
+	frame := newTimeSeriesFrame(timeVector, tags, values)
+	if metric.Type == derivativeType || metric.Type == cumulativeSumType {
+		setPipelineAggFieldConfig(frame.Fields[1], metric)
+	}
+	return data.Frames{frame}, nil
+}
+
+// Axis placements of the values of a metric, like the axis placements of the time series panel.
+var metricAxisPlacements = map[string]bool{
+	"auto":   true,
+	"left":   true,
+	"right":  true,
+	"hidden": true,
+}
+
+// setMetricDisplayFieldConfig sets the unit, the decimals and the axis placement of the value field of a metric from
+// the display hints of its settings, so that panels with metrics of different units render without overrides.
+// The hints take precedence over the unit and the decimals of pipeline aggregations.
+func setMetricDisplayFieldConfig(field *data.Field, metric *MetricAgg) {
+	unit := metric.Settings.Get("displayUnit").MustString()
+	decimals, hasDecimals := castToUint16(metric.Settings.Get("decimals"))
+	axisPlacement := metric.Settings.Get("axisPlacement").MustString()
+	if unit == "" && !hasDecimals && !metricAxisPlacements[axisPlacement] {
+		return
+	}
+
+	if field.Config == nil {
+		field.Config = &data.FieldConfig{}
+	}
+	if unit != "" {
+		field.Config.Unit = unit
+	}
+	if hasDecimals {
+		field.Config.SetDecimals(decimals)
+	}
+	if metricAxisPlacements[axisPlacement] {
+		if field.Config.Custom == nil {
+			field.Config.Custom = map[string]interface{}{}
+		}
+		field.Config.Custom["axisPlacement"] = axisPlacement
+	}
+}
+
+// castToUint16 returns the value of a setting that is a number or a string with a number, e.g. the decimals.
+func castToUint16(j *simplejson.Json) (uint16, bool) {
+	v, err := castToInt(j)
+	if err != nil || v < 0 || v > math.MaxUint16 {
+		return 0, false
+	}
+	return uint16(v), true
+}
+
+var derivativeUnitRegex = regexp.MustCompile(`^1([a-zA-Z]+)$`)
+
+// setPipelineAggFieldConfig sets the unit and the decimals of the value field of derivative and cumulative sum
+// frames from the unit and the format of the aggregation, so that panels display the values correctly.
+func setPipelineAggFieldConfig(field *data.Field, metric *MetricAgg) {
+	if field.Config == nil {
+		field.Config = &data.FieldConfig{}
+	}
+	// the values of derivatives with a unit are normalized to the unit, e.g. per second for 1s
+	if unit := metric.Settings.Get("unit").MustString(); metric.Type == derivativeType && unit != "" {
+		if m := derivativeUnitRegex.FindStringSubmatch(unit); m != nil {
+			unit = m[1]
+		}
+		field.Config.Unit = "suffix:/" + unit
+	}
+	if decimals, ok := decimalsFromFormat(metric.Settings.Get("format").MustString()); ok {
+		field.Config.SetDecimals(decimals)
+	}
+}
+
+// decimalsFromFormat returns the number of decimals of a DecimalFormat pattern, such as "#,##0.00".
+func decimalsFromFormat(format string) (uint16, bool) {
+	// only the positive subpattern is considered
+	format, _, _ = strings.Cut(format, ";")
+	integer, fraction, _ := strings.Cut(format, ".")
+	if !strings.ContainsAny(integer+fraction, "0#") {
+		return 0, false
+	}
+	var decimals uint16
+	for _, c := range fraction {
+		if c != '0' && c != '#' {
+			break
+		}
+		decimals++
+	}
+	return decimals, true
 }
 
 // nolint:gocyclo
@@ -701,6 +874,7 @@ func processMetrics(esAgg *simplejson.Json, target *Query, query *backend.DataRe
 			continue
 		}
 
+		metricFramesStart := len(frames)
 		switch metric.Type {
 		case countType:
 			countFrames, err := processCountMetric(jsonBuckets, props)
@@ -734,6 +908,11 @@ func processMetrics(esAgg *simplejson.Json, target *Query, query *backend.DataRe
 			}
 			frames = append(frames, defaultFrames...)
 		}
+		for _, frame := range frames[metricFramesStart:] {
+			// the id of the metric selects its alias when the series are named
+			frame.Fields[1].Labels["metricId"] = metric.ID
+			setMetricDisplayFieldConfig(frame.Fields[1], metric)
+		}
 	}
 	if query.Frames != nil {
 		oldFrames := query.Frames
@@ -928,32 +1107,8 @@ func getFieldName(dataField data.Field, target *Query, metricTypeCount int) stri
 		delete(dataField.Labels, "field")
 	}
 
-	if target.Alias != "" {
-		frameName := target.Alias
-
-		subMatches := aliasPatternRegex.FindAllStringSubmatch(target.Alias, -1)
-		for _, subMatch := range subMatches {
-			group := subMatch[0]
-
-			if len(subMatch) > 1 {
-				group = subMatch[1]
-			}
-
-			if strings.Index(group, "term ") == 0 {
-				frameName = strings.Replace(frameName, subMatch[0], dataField.Labels[group[5:]], 1)
-			}
-			if v, ok := dataField.Labels[group]; ok {
-				frameName = strings.Replace(frameName, subMatch[0], v, 1)
-			}
-			if group == "metric" {
-				frameName = strings.Replace(frameName, subMatch[0], metricName, 1)
-			}
-			if group == "field" {
-				frameName = strings.Replace(frameName, subMatch[0], field, 1)
-			}
-		}
-
-		return frameName
+	if alias := seriesAlias(target, dataField.Labels["metricId"]); alias != "" {
+		return renderAlias(alias, dataField.Labels, metricName, field)
 	}
 	// todo, if field and pipelineAgg
 	if isPipelineAgg(metricType) {
@@ -1011,6 +1166,46 @@ func getFieldName(dataField data.Field, target *Query, metricTypeCount int) stri
 	return strings.TrimSpace(name) + " " + metricName
 }
 
+// seriesAlias returns the alias of the metric of a series, or the alias of the query if the metric has none.
+func seriesAlias(target *Query, metricID string) string {
+	for _, metric := range target.Metrics {
+		if metric.ID == metricID && metric.Alias != "" {
+			return metric.Alias
+		}
+	}
+	return target.Alias
+}
+
+// renderAlias replaces the placeholders of an alias pattern: `{{metric}}`, `{{field}}`, `{{term <field>}}` and
+// `{{<field>}}` for the keys of the buckets.
+func renderAlias(alias string, labels data.Labels, metricName, field string) string {
+	frameName := alias
+
+	subMatches := aliasPatternRegex.FindAllStringSubmatch(alias, -1)
+	for _, subMatch := range subMatches {
+		group := subMatch[0]
+
+		if len(subMatch) > 1 {
+			group = subMatch[1]
+		}
+
+		if strings.Index(group, "term ") == 0 {
+			frameName = strings.Replace(frameName, subMatch[0], labels[group[5:]], 1)
+		}
+		if v, ok := labels[group]; ok {
+			frameName = strings.Replace(frameName, subMatch[0], v, 1)
+		}
+		if group == "metric" {
+			frameName = strings.Replace(frameName, subMatch[0], metricName, 1)
+		}
+		if group == "field" {
+			frameName = strings.Replace(frameName, subMatch[0], field, 1)
+		}
+	}
+
+	return frameName
+}
+
 func getMetricName(metric string) string {
 	if text, ok := metricAggType[metric]; ok {
 		return text
@@ -1080,27 +1275,6 @@ func findAgg(target *Query, aggID string) (*BucketAgg, error) {
 	return nil, errors.New("can't found aggDef, aggID:" + aggID)
 }
 
-func getErrorFromElasticResponse(response *es.SearchResponse) string {
-	var errorString string
-	json := simplejson.NewFromAny(response.Error)
-	reason := json.Get("reason").MustString()
-	rootCauseReason := json.Get("root_cause").GetIndex(0).Get("reason").MustString()
-	causedByReason := json.Get("caused_by").Get("reason").MustString()
-
-	switch {
-	case rootCauseReason != "":
-		errorString = rootCauseReason
-	case reason != "":
-		errorString = reason
-	case causedByReason != "":
-		errorString = causedByReason
-	default:
-		errorString = "Unknown elasticsearch error response"
-	}
-
-	return errorString
-}
-
 // flatten flattens multi-level objects to single level objects. It uses dot notation to join keys.
 func flatten(target map[string]interface{}, maxDepth int) map[string]interface{} {
 	// On frontend maxDepth wasn't used but as we are processing on backend
@@ -14,9 +14,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 var update = flag.Bool("update", true, "update golden files")
@@ -108,7 +110,16 @@ func TestProcessLogsResponse(t *testing.T) {
 			logsFrame := frames[0]
 
 			meta := logsFrame.Meta
-			require.Equal(t, map[string]any{"searchWords": []string{"hello", "message"}, "limit": 500}, meta.Custom)
+			require.Equal(t, map[string]any{
+				"searchWords": []string{"hello", "message"},
+				"limit":       500,
+				"detectedFields": []detectedField{
+					{Name: "fields.lvl", Type: "string", Cardinality: 2},
+					{Name: "host", Type: "string", Cardinality: 2},
+					{Name: "level", Type: "string", Cardinality: 2},
+					{Name: "number", Type: "int", Cardinality: 2},
+				},
+			}, meta.Custom)
 			require.Equal(t, data.VisTypeLogs, string(meta.PreferredVisualization))
 
 			logsFieldMap := make(map[string]*data.Field)
@@ -363,6 +374,89 @@ func TestProcessLogsResponse(t *testing.T) {
 		require.Equal(t, data.FieldTypeNullableString, frame.Fields[16].Type())
 	})
 
+	t.Run("Log query with JSON messages", func(t *testing.T) {
+		response := `{
+			"responses": [
+				{
+					"hits": {
+						"hits": [
+							{
+								"_index": "logs-2023.02.08",
+								"_id": "1",
+								"_source": {
+									"@timestamp": "2023-02-08T15:10:55.830Z",
+									"line": "{\"status\": 200, \"duration\": 0.25, \"host\": \"a\", \"req\": {\"method\": \"GET\"}}",
+									"host": "server-1"
+								}
+							},
+							{
+								"_index": "logs-2023.02.08",
+								"_id": "2",
+								"_source": {
+									"@timestamp": "2023-02-08T15:10:56.830Z",
+									"line": "{\"status\": 500, \"duration\": 1, \"host\": \"b\", \"req\": {\"method\": \"GET\"}}",
+									"host": "server-1"
+								}
+							},
+							{
+								"_index": "logs-2023.02.08",
+								"_id": "3",
+								"_source": {
+									"@timestamp": "2023-02-08T15:10:57.830Z",
+									"line": "not json",
+									"host": "server-2"
+								}
+							}
+						]
+					},
+					"status": 200
+				}
+			]
+		}`
+
+		t.Run("should flatten the messages into fields", func(t *testing.T) {
+			result, err := parseTestResponse(map[string]string{
+				"A": `{ "metrics": [{ "type": "logs", "settings": { "parseJSONMessage": true } }] }`,
+			}, response)
+			require.NoError(t, err)
+			frame := result.Responses["A"].Frames[0]
+
+			fields := make(map[string]*data.Field)
+			for _, field := range frame.Fields {
+				fields[field.Name] = field
+			}
+			require.Equal(t, 3, fields["status"].Len())
+			require.Equal(t, 200.0, *fields["status"].At(0).(*float64))
+			require.Nil(t, fields["status"].At(2))
+			require.Equal(t, "GET", *fields["req.method"].At(1).(*string))
+			require.Equal(t, "server-1", *fields["host"].At(0).(*string))
+			require.Equal(t, "a", *fields["host_extracted"].At(0).(*string))
+
+			require.Equal(t, []detectedField{
+				{Name: "duration", Type: "float", Cardinality: 2, Parsers: []string{"json"}},
+				{Name: "host", Type: "string", Cardinality: 2},
+				{Name: "host_extracted", Type: "string", Cardinality: 2, Parsers: []string{"json"}},
+				{Name: "req.method", Type: "string", Cardinality: 1, Parsers: []string{"json"}},
+				{Name: "status", Type: "int", Cardinality: 2, Parsers: []string{"json"}},
+			}, frame.Meta.Custom.(map[string]any)["detectedFields"])
+		})
+
+		t.Run("should not parse the messages by default", func(t *testing.T) {
+			result, err := parseTestResponse(map[string]string{
+				"A": `{ "metrics": [{ "type": "logs" }] }`,
+			}, response)
+			require.NoError(t, err)
+			frame := result.Responses["A"].Frames[0]
+
+			for _, field := range frame.Fields {
+				require.NotEqual(t, "status", field.Name)
+			}
+			require.Equal(t, []detectedField{
+				{Name: "host", Type: "string", Cardinality: 2},
+			}, frame.Meta.Custom.(map[string]any)["detectedFields"])
+		})
+	})
+
 	t.Run("Log query with highlight", func(t *testing.T) {
 		targets := map[string]string{
 			"A": `{
@@ -430,8 +524,9 @@ func TestProcessLogsResponse(t *testing.T) {
 		customMeta := frame.Meta.Custom
 
 		require.Equal(t, map[string]any{
-			"searchWords": []string{"hello", "message"},
-			"limit":       500,
+			"searchWords":    []string{"hello", "message"},
+			"limit":          500,
+			"detectedFields": []detectedField{},
 		}, customMeta)
 	})
 }
@@ -1328,6 +1423,180 @@ func TestProcessBuckets(t *testing.T) {
 		})
 	})
 
+	t.Run("IP range and prefix", func(t *testing.T) {
+		t.Run("Ip prefix agg without date histogram", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [{ "type": "count", "id": "1" }],
+		  "bucketAggs": [{ "id": "2", "type": "ip_prefix", "field": "source.ip", "settings": { "prefix_length": "24" } }]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"buckets": [
+				  { "key": "192.168.1.0/24", "is_ipv6": false, "prefix_length": 24, "netmask": "255.255.255.0", "doc_count": 12 },
+				  { "key": "192.168.2.0/24", "is_ipv6": false, "prefix_length": 24, "netmask": "255.255.255.0", "doc_count": 5 }
+				]
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			requireFrameLength(t, frames[0], 2)
+			require.Len(t, frames[0].Fields, 2)
+
+			requireStringAt(t, "192.168.1.0/24", frames[0].Fields[0], 0)
+			requireStringAt(t, "192.168.2.0/24", frames[0].Fields[0], 1)
+			requireFloatAt(t, 12.0, frames[0].Fields[1], 0)
+			requireFloatAt(t, 5.0, frames[0].Fields[1], 1)
+		})
+
+		t.Run("Ip range agg with date histogram", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [{ "type": "count", "id": "1" }],
+		  "bucketAggs": [
+			{ "id": "2", "type": "ip_range", "field": "source.ip", "settings": { "ranges": [{ "mask": "10.0.0.0/25" }, { "from": "10.0.0.128" }] } },
+			{ "id": "3", "type": "date_histogram", "field": "@timestamp" }
+		  ]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"buckets": [
+				  {
+					"3": { "buckets": [{ "doc_count": 1, "key": 1000 }, { "doc_count": 3, "key": 2000 }] },
+					"key": "10.0.0.0/25",
+					"from": "10.0.0.0",
+					"to": "10.0.0.128",
+					"doc_count": 4
+				  },
+				  {
+					"3": { "buckets": [{ "doc_count": 2, "key": 1000 }, { "doc_count": 0, "key": 2000 }] },
+					"key": "10.0.0.128-*",
+					"from": "10.0.0.128",
+					"doc_count": 2
+				  }
+				]
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 2)
+			requireFrameLength(t, frames[0], 2)
+			requireTimeSeriesName(t, "10.0.0.0/25", frames[0])
+			requireTimeSeriesName(t, "10.0.0.128-*", frames[1])
+		})
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Run("Filter agg with date histogram", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [{ "type": "count", "id": "1" }],
+		  "bucketAggs": [
+			{ "id": "2", "type": "filter", "settings": { "query": "@metric:cpu", "label": "cpu" } },
+			{ "id": "3", "type": "date_histogram", "field": "@timestamp" }
+		  ]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"doc_count": 4,
+				"3": { "buckets": [{ "doc_count": 1, "key": 1000 }, { "doc_count": 3, "key": 2000 }] }
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			requireFrameLength(t, frames[0], 2)
+			requireTimeSeriesName(t, "cpu", frames[0])
+			requireFloatAt(t, 1.0, frames[0].Fields[1], 0)
+			requireFloatAt(t, 3.0, frames[0].Fields[1], 1)
+		})
+
+		t.Run("Filter agg without date histogram", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [{ "type": "avg", "id": "1", "field": "@value" }, { "type": "count", "id": "3" }],
+		  "bucketAggs": [{ "id": "2", "type": "filter", "settings": { "query": "@metric:cpu" } }]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": { "doc_count": 4, "1": { "value": 10 } }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			requireFrameLength(t, frames[0], 1)
+			require.Len(t, frames[0].Fields, 3)
+			require.Equal(t, "filter", frames[0].Fields[0].Name)
+			requireStringAt(t, "@metric:cpu", frames[0].Fields[0], 0)
+			requireFloatAt(t, 10.0, frames[0].Fields[1], 0)
+			requireFloatAt(t, 4.0, frames[0].Fields[2], 0)
+		})
+	})
+
 	t.Run("Top metrics", func(t *testing.T) {
 		t.Run("Top metrics 2 frames", func(t *testing.T) {
 			query := []byte(`
@@ -1713,6 +1982,60 @@ func TestProcessBuckets(t *testing.T) {
 			requireTimeSeriesName(t, "0 Count and {{not_exist}} 0", frames[2])
 		})
 
+		t.Run("Single group with metric alias patterns", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [
+			{ "type": "count", "id": "1" },
+			{ "type": "avg", "field": "duration", "id": "4", "alias": "{{field}} avg {{term @host}}" },
+			{ "type": "percentiles", "field": "duration", "id": "5", "settings": { "percents": ["95"] }, "alias": "{{field}} {{metric}} {{term @host}}" }
+		  ],
+		  "alias": "{{metric}} of {{@host}}",
+		  "bucketAggs": [
+			{ "type": "terms", "field": "@host", "id": "2" },
+			{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+		  ]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"buckets": [
+				  {
+					"3": {
+					  "buckets": [
+						{ "doc_count": 1, "key": 1000, "4": { "value": 10 }, "5": { "values": { "95.0": 20 } } },
+						{ "doc_count": 3, "key": 2000, "4": { "value": 12 }, "5": { "values": { "95.0": 22 } } }
+					  ]
+					},
+					"doc_count": 4,
+					"key": "server1"
+				  }
+				]
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 3)
+			requireTimeSeriesName(t, "Count of server1", frames[0])
+			requireTimeSeriesName(t, "duration avg server1", frames[1])
+			requireTimeSeriesName(t, "duration p95.0 server1", frames[2])
+		})
+
 		t.Run("Single group by query one metric", func(t *testing.T) {
 			targets := map[string]string{
 				"A": `{
@@ -3289,6 +3612,113 @@ func TestParseResponse(t *testing.T) {
 	})
 }
 
+func TestAddWarningNotices(t *testing.T) {
+	result := &backend.QueryDataResponse{
+		Responses: backend.Responses{
+			"A": {Frames: data.Frames{data.NewFrame("a"), data.NewFrame("b")}},
+			"B": {Frames: data.Frames{data.NewFrame("c")}},
+		},
+	}
+	addWarningNotices(result, []string{"[types removal] Specifying types in search requests is deprecated."})
+
+	for _, res := range result.Responses {
+		for _, frame := range res.Frames {
+			require.Equal(t, []data.Notice{{
+				Severity: data.NoticeSeverityWarning,
+				Text:     "[types removal] Specifying types in search requests is deprecated.",
+			}}, frame.Meta.Notices)
+		}
+	}
+}
+
+func TestSetPipelineAggFieldConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   *MetricAgg
+		unit     string
+		decimals *uint16
+	}{
+		{
+			name:   "derivative with unit",
+			metric: &MetricAgg{Type: derivativeType, Settings: simplejson.NewFromAny(map[string]any{"unit": "1s"})},
+			unit:   "suffix:/s",
+		},
+		{
+			name:   "derivative with multiple of a unit",
+			metric: &MetricAgg{Type: derivativeType, Settings: simplejson.NewFromAny(map[string]any{"unit": "5m"})},
+			unit:   "suffix:/5m",
+		},
+		{
+			name:     "cumulative sum with format",
+			metric:   &MetricAgg{Type: cumulativeSumType, Settings: simplejson.NewFromAny(map[string]any{"format": "#,##0.00"})},
+			decimals: util.Pointer(uint16(2)),
+		},
+		{
+			name:     "cumulative sum with integer format",
+			metric:   &MetricAgg{Type: cumulativeSumType, Settings: simplejson.NewFromAny(map[string]any{"format": "#,##0;(#,##0)"})},
+			decimals: util.Pointer(uint16(0)),
+		},
+		{
+			name:   "cumulative sum without format",
+			metric: &MetricAgg{Type: cumulativeSumType, Settings: simplejson.NewFromAny(map[string]any{})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := data.NewField("Value", nil, []*float64{})
+			setPipelineAggFieldConfig(field, tt.metric)
+			require.Equal(t, tt.unit, field.Config.Unit)
+			require.Equal(t, tt.decimals, field.Config.Decimals)
+		})
+	}
+}
+
+func TestSetMetricDisplayFieldConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      map[string]any
+		unit          string
+		decimals      *uint16
+		axisPlacement any
+	}{
+		{
+			name:          "unit, decimals and axis placement",
+			settings:      map[string]any{"displayUnit": "bytes", "decimals": "1", "axisPlacement": "right"},
+			unit:          "bytes",
+			decimals:      util.Pointer(uint16(1)),
+			axisPlacement: "right",
+		},
+		{
+			name:     "numeric decimals",
+			settings: map[string]any{"decimals": 2},
+			decimals: util.Pointer(uint16(2)),
+		},
+		{
+			name:     "unknown axis placement",
+			settings: map[string]any{"displayUnit": "ms", "axisPlacement": "top"},
+			unit:     "ms",
+		},
+		{
+			name:     "without display settings",
+			settings: map[string]any{"missing": "0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := data.NewField("Value", nil, []*float64{})
+			setMetricDisplayFieldConfig(field, &MetricAgg{Type: "avg", Settings: simplejson.NewFromAny(tt.settings)})
+			if field.Config == nil {
+				require.Empty(t, tt.unit)
+				require.Nil(t, tt.decimals)
+				return
+			}
+			require.Equal(t, tt.unit, field.Config.Unit)
+			require.Equal(t, tt.decimals, field.Config.Decimals)
+			require.Equal(t, tt.axisPlacement, field.Config.Custom["axisPlacement"])
+		})
+	}
+}
+
 func TestLabelOrderInFieldName(t *testing.T) {
 	query := []byte(`
 	[
@@ -0,0 +1,67 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// samplerAggID is the key of the random sampler aggregation that wraps the aggregations of sampled queries
+	samplerAggID = "sampler"
+	// defaultSamplingThreshold is the time range above which queries with adaptive sampling are sampled
+	defaultSamplingThreshold = 7 * 24 * time.Hour
+	minSamplingProbability   = 0.0001
+	// Elasticsearch requires the probability to be lower than 0.5 unless it is exactly 1
+	maxSamplingProbability = 0.4
+)
+
+// samplingProbability returns the probability of the random sampler of a query over the time range [from, to] in
+// milliseconds, or 0 if the query is not sampled. The probability decreases as the time range grows, so that roughly
+// as many documents are aggregated as for a time range of the sampling threshold.
+func samplingProbability(q *Query, from, to int64) float64 {
+	if !q.AdaptiveSampling || q.SamplingThreshold <= 0 {
+		return 0
+	}
+	timeRange := time.Duration(to-from) * time.Millisecond
+	if timeRange <= q.SamplingThreshold {
+		return 0
+	}
+	probability := float64(q.SamplingThreshold) / float64(timeRange)
+	return math.Max(minSamplingProbability, math.Min(maxSamplingProbability, probability))
+}
+
+// unwrapSampledAggregations returns the aggregations nested in the random sampler aggregation of a sampled query.
+func unwrapSampledAggregations(aggs map[string]any) map[string]any {
+	sampler, ok := aggs[samplerAggID].(map[string]any)
+	if !ok {
+		return aggs
+	}
+	return sampler
+}
+
+// markSampled marks the frames of a sampled query with a notice and the sampling probability in the custom metadata.
+func markSampled(queryRes *backend.DataResponse, probability float64) {
+	notice := data.Notice{
+		Severity: data.NoticeSeverityInfo,
+		Text: fmt.Sprintf("Results are approximated from a random sample of %s%% of the documents",
+			strconv.FormatFloat(probability*100, 'f', -1, 64)),
+	}
+	for _, frame := range queryRes.Frames {
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		custom, ok := frame.Meta.Custom.(map[string]any)
+		if !ok {
+			custom = map[string]any{}
+		}
+		custom["sampled"] = true
+		custom["samplingProbability"] = probability
+		frame.Meta.Custom = custom
+		frame.AppendNotices(notice)
+	}
+}
@@ -0,0 +1,208 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/grafana/grafana-aws-sdk/pkg/awsds"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+)
+
+const (
+	sigV4MiddlewareName = "elasticsearch-sigv4"
+
+	// sigV4ExpiryWindow is how long before their expiration the temporary credentials are refreshed, so that
+	// requests are not signed with credentials that expire before Elasticsearch handles them.
+	sigV4ExpiryWindow = 5 * time.Minute
+
+	// Placeholders of the template of the role that is assumed for the signed-in user.
+	sigV4RoleTeamPlaceholder  = "${team}"
+	sigV4RoleLoginPlaceholder = "${login}"
+)
+
+// newSigV4BaseCredentials returns the credentials used to sign requests, or to assume roles.
+var newSigV4BaseCredentials = func(cfg sdkhttpclient.SigV4Config) (*credentials.Credentials, error) {
+	authType, err := awsds.ToAuthType(cfg.AuthType)
+	if err != nil {
+		return nil, err
+	}
+	authSettings := awsds.ReadAuthSettingsFromEnvironmentVariables()
+	if !slices.Contains(authSettings.AllowedAuthProviders, authType.String()) {
+		return nil, fmt.Errorf("attempting to use an auth type for SigV4 that is not allowed: %q", authType.String())
+	}
+
+	switch authType {
+	case awsds.AuthTypeKeys:
+		return credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""), nil
+	case awsds.AuthTypeSharedCreds:
+		return credentials.NewSharedCredentials("", cfg.Profile), nil
+	case awsds.AuthTypeEC2IAMRole:
+		sess, err := session.NewSession(&aws.Config{Region: aws.String(cfg.Region)})
+		if err != nil {
+			return nil, err
+		}
+		return credentials.NewCredentials(&ec2rolecreds.EC2RoleProvider{
+			Client:       ec2metadata.New(sess),
+			ExpiryWindow: sigV4ExpiryWindow,
+		}), nil
+	default:
+		sess, err := session.NewSession(&aws.Config{Region: aws.String(cfg.Region)})
+		if err != nil {
+			return nil, err
+		}
+		return sess.Config.Credentials, nil
+	}
+}
+
+// newSigV4RoleCredentials returns the credentials of a role assumed with the base credentials. They are refreshed
+// before they expire.
+var newSigV4RoleCredentials = func(cfg sdkhttpclient.SigV4Config, base *credentials.Credentials, roleARN string) (*credentials.Credentials, error) {
+	if !awsds.ReadAuthSettingsFromEnvironmentVariables().AssumeRoleEnabled {
+		return nil, fmt.Errorf("attempting to use assume role (ARN) for SigV4 which is not enabled")
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(cfg.Region),
+		Credentials: base,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stscreds.NewCredentials(sess, roleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.ExpiryWindow = sigV4ExpiryWindow
+		if cfg.ExternalID != "" {
+			p.ExternalID = aws.String(cfg.ExternalID)
+		}
+	}), nil
+}
+
+// sigV4Middleware signs the requests to Amazon OpenSearch Service. Unlike the SigV4 middleware of the HTTP client
+// provider, it refreshes the temporary credentials of assumed roles before they expire, and it can assume a role
+// that depends on the signed-in user, so that each team only has access to its own indices.
+type sigV4Middleware struct {
+	cfg sdkhttpclient.SigV4Config
+	// roleTemplate is the ARN of the role assumed for the signed-in user, with the placeholders of the user.
+	roleTemplate string
+
+	mu      sync.Mutex
+	base    *credentials.Credentials
+	signers map[string]*v4.Signer
+}
+
+// newSigV4Middleware returns the middleware that signs the requests with the SigV4 configuration.
+func newSigV4Middleware(cfg sdkhttpclient.SigV4Config, roleTemplate string) sdkhttpclient.Middleware {
+	m := &sigV4Middleware{
+		cfg:          cfg,
+		roleTemplate: roleTemplate,
+		signers:      make(map[string]*v4.Signer),
+	}
+	return sdkhttpclient.NamedMiddlewareFunc(sigV4MiddlewareName, func(_ sdkhttpclient.Options, next http.RoundTripper) http.RoundTripper {
+		return sdkhttpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			signed, err := m.sign(req)
+			if err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(signed)
+		})
+	})
+}
+
+// roleARN returns the ARN of the role to assume for the request. Requests without a signed-in user, like the
+// requests of alert rules, use the role of the data source.
+func (m *sigV4Middleware) roleARN(req *http.Request) (string, error) {
+	if m.roleTemplate == "" {
+		return m.cfg.AssumeRoleARN, nil
+	}
+
+	usr, err := appcontext.User(req.Context())
+	if err != nil {
+		if m.cfg.AssumeRoleARN != "" {
+			return m.cfg.AssumeRoleARN, nil
+		}
+		return "", fmt.Errorf("cannot assume the role of the user: %w", err)
+	}
+
+	roleARN := strings.ReplaceAll(m.roleTemplate, sigV4RoleLoginPlaceholder, usr.Login)
+	if strings.Contains(roleARN, sigV4RoleTeamPlaceholder) {
+		if len(usr.Teams) == 0 {
+			return "", fmt.Errorf("cannot assume the role of the user: user %s is not a member of any team", usr.Login)
+		}
+		// users that are members of several teams use the role of the team that was created first
+		team := slices.Min(usr.Teams)
+		roleARN = strings.ReplaceAll(roleARN, sigV4RoleTeamPlaceholder, strconv.FormatInt(team, 10))
+	}
+	return roleARN, nil
+}
+
+func (m *sigV4Middleware) signer(roleARN string) (*v4.Signer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if signer, ok := m.signers[roleARN]; ok {
+		return signer, nil
+	}
+
+	if m.base == nil {
+		base, err := newSigV4BaseCredentials(m.cfg)
+		if err != nil {
+			return nil, err
+		}
+		m.base = base
+	}
+
+	creds := m.base
+	if roleARN != "" {
+		var err error
+		creds, err = newSigV4RoleCredentials(m.cfg, m.base, roleARN)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signer := v4.NewSigner(creds)
+	m.signers[roleARN] = signer
+	return signer, nil
+}
+
+func (m *sigV4Middleware) sign(req *http.Request) (*http.Request, error) {
+	roleARN, err := m.roleARN(req)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := m.signer(roleARN)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		if err := req.Body.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	signed := req.Clone(req.Context())
+	if _, err := signer.Sign(signed, bytes.NewReader(body), m.cfg.Service, m.cfg.Region, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("failed to sign the request: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,98 @@
+package elasticsearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestSigV4Middleware(t *testing.T) {
+	cfg := sdkhttpclient.SigV4Config{AuthType: "keys", Service: "es", Region: "eu-west-1", AccessKey: "base", SecretKey: "secret"}
+
+	origBase, origRole := newSigV4BaseCredentials, newSigV4RoleCredentials
+	t.Cleanup(func() {
+		newSigV4BaseCredentials, newSigV4RoleCredentials = origBase, origRole
+	})
+	newSigV4BaseCredentials = func(cfg sdkhttpclient.SigV4Config) (*credentials.Credentials, error) {
+		return credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""), nil
+	}
+	var assumed []string
+	newSigV4RoleCredentials = func(_ sdkhttpclient.SigV4Config, _ *credentials.Credentials, roleARN string) (*credentials.Credentials, error) {
+		assumed = append(assumed, roleARN)
+		return credentials.NewStaticCredentials(roleARN[strings.LastIndex(roleARN, "/")+1:], "secret", "token"), nil
+	}
+
+	send := func(t *testing.T, mw sdkhttpclient.Middleware, ctx context.Context) (*http.Request, error) {
+		t.Helper()
+		var sent *http.Request
+		rt := mw.CreateMiddleware(sdkhttpclient.Options{}, sdkhttpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://search.eu-west-1.es.amazonaws.com/_msearch", strings.NewReader(`{"query":{}}`))
+		require.NoError(t, err)
+		res, err := rt.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		require.NoError(t, res.Body.Close())
+		return sent, nil
+	}
+
+	t.Run("should sign with the role of the team of the user", func(t *testing.T) {
+		assumed = nil
+		mw := newSigV4Middleware(cfg, "arn:aws:iam::123456789012:role/team-${team}")
+		ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{Login: "alice", Teams: []int64{7, 3}})
+
+		req, err := send(t, mw, ctx)
+		require.NoError(t, err)
+		require.Contains(t, req.Header.Get("Authorization"), "Credential=team-3/")
+		require.Equal(t, "token", req.Header.Get("X-Amz-Security-Token"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"query":{}}`, string(body))
+
+		_, err = send(t, mw, ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"arn:aws:iam::123456789012:role/team-3"}, assumed, "the credentials of a role should be reused")
+	})
+
+	t.Run("should use the role of the data source without a signed-in user", func(t *testing.T) {
+		withRole := cfg
+		withRole.AssumeRoleARN = "arn:aws:iam::123456789012:role/grafana"
+		mw := newSigV4Middleware(withRole, "arn:aws:iam::123456789012:role/user-${login}")
+
+		req, err := send(t, mw, context.Background())
+		require.NoError(t, err)
+		require.Contains(t, req.Header.Get("Authorization"), "Credential=grafana/")
+
+		req, err = send(t, mw, appcontext.WithUser(context.Background(), &user.SignedInUser{Login: "bob"}))
+		require.NoError(t, err)
+		require.Contains(t, req.Header.Get("Authorization"), "Credential=user-bob/")
+	})
+
+	t.Run("should fail if the role of the user cannot be resolved", func(t *testing.T) {
+		mw := newSigV4Middleware(cfg, "arn:aws:iam::123456789012:role/team-${team}")
+
+		_, err := send(t, mw, context.Background())
+		require.ErrorContains(t, err, "cannot assume the role of the user")
+
+		_, err = send(t, mw, appcontext.WithUser(context.Background(), &user.SignedInUser{Login: "carol"}))
+		require.ErrorContains(t, err, "user carol is not a member of any team")
+	})
+
+	t.Run("should sign with the base credentials without a role", func(t *testing.T) {
+		req, err := send(t, newSigV4Middleware(cfg, ""), context.Background())
+		require.NoError(t, err)
+		require.Contains(t, req.Header.Get("Authorization"), "Credential=base/")
+	})
+}
@@ -0,0 +1,87 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+// translateResourcePath is the resource path that returns the DSL of queries without executing them
+const translateResourcePath = "translate"
+
+// translateRequest is the body of a translate request. The queries are the models of the query editor, like in the
+// requests of the query API.
+type translateRequest struct {
+	Queries []json.RawMessage `json:"queries"`
+	// From and To are the time range of the queries in epoch milliseconds.
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+var errInvalidTranslateRequest = errors.New("invalid translate request")
+
+// dataQueries returns the data queries of the request, with the same defaults as the query API.
+func (r *translateRequest) dataQueries() ([]backend.DataQuery, error) {
+	if len(r.Queries) == 0 {
+		return nil, fmt.Errorf("%w: queries are required", errInvalidTranslateRequest)
+	}
+	if r.From == 0 || r.To == 0 || r.From > r.To {
+		return nil, fmt.Errorf("%w: a valid time range is required", errInvalidTranslateRequest)
+	}
+
+	timeRange := backend.TimeRange{From: time.UnixMilli(r.From), To: time.UnixMilli(r.To)}
+	queries := make([]backend.DataQuery, 0, len(r.Queries))
+	for _, raw := range r.Queries {
+		model, err := simplejson.NewJson(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidTranslateRequest, err)
+		}
+		queries = append(queries, backend.DataQuery{
+			RefID:         model.Get("refId").MustString("A"),
+			JSON:          raw,
+			TimeRange:     timeRange,
+			MaxDataPoints: model.Get("maxDataPoints").MustInt64(100),
+			Interval:      time.Duration(model.Get("intervalMs").MustInt64(1000)) * time.Millisecond,
+		})
+	}
+	return queries, nil
+}
+
+// handleTranslate returns the multi search request that the queries would send to Elasticsearch, so that users can
+// inspect it or paste it into Kibana Dev Tools.
+func (s *Service) handleTranslate(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := eslog.FromContext(ctx)
+
+	var r translateRequest
+	if err := json.Unmarshal(req.Body, &r); err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidTranslateRequest, err))
+	}
+	queries, err := r.dataQueries()
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	}
+
+	ds, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	client, err := es.NewClient(ctx, ds, queries[0].TimeRange, logger, s.tracer)
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	}
+	translated, err := newElasticsearchDataQuery(ctx, client, queries, logger, s.tracer).translate()
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	}
+	return sendResourceJSON(sender, http.StatusOK, translated)
+}
@@ -0,0 +1,70 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestTranslate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	to := from + time.Hour.Milliseconds()
+
+	t.Run("should return the multi search request with interpolated intervals", func(t *testing.T) {
+		s := newResourceTestService(nil)
+		res := callResource(t, s, translateResourcePath, fmt.Sprintf(`{
+			"from": %d,
+			"to": %d,
+			"queries": [{
+				"refId": "A",
+				"intervalMs": 30000,
+				"query": "status:500",
+				"metrics": [{"type": "count", "id": "1"}],
+				"bucketAggs": [{"type": "date_histogram", "field": "@timestamp", "id": "2", "settings": {"interval": "auto"}}]
+			}]
+		}`, from, to))
+		require.Equal(t, http.StatusOK, res.Status)
+
+		var translated es.TranslatedMultiSearchRequest
+		require.NoError(t, json.Unmarshal(res.Body, &translated))
+		require.Equal(t, http.MethodPost, translated.Method)
+		require.Equal(t, "_msearch?max_concurrent_shard_requests=5", translated.Path)
+
+		lines := strings.Split(strings.TrimSuffix(translated.Body, "\n"), "\n")
+		require.Len(t, lines, 2)
+		require.Contains(t, lines[0], `"index":"logs"`)
+		require.Contains(t, lines[1], `"fixed_interval":"30000ms"`)
+		require.Contains(t, lines[1], `"query":"status:500"`)
+		require.NotContains(t, lines[1], "$__interval")
+	})
+
+	t.Run("should reject requests without queries or time range", func(t *testing.T) {
+		s := newResourceTestService(nil)
+
+		res := callResource(t, s, translateResourcePath, fmt.Sprintf(`{"from": %d, "to": %d}`, from, to))
+		require.Equal(t, http.StatusBadRequest, res.Status)
+		require.Contains(t, string(res.Body), "queries are required")
+
+		res = callResource(t, s, translateResourcePath, `{"queries": [{"refId": "A"}]}`)
+		require.Equal(t, http.StatusBadRequest, res.Status)
+		require.Contains(t, string(res.Body), "time range")
+	})
+
+	t.Run("should return the error of invalid queries", func(t *testing.T) {
+		s := newResourceTestService(nil)
+		res := callResource(t, s, translateResourcePath, fmt.Sprintf(`{
+			"from": %d,
+			"to": %d,
+			"queries": [{"refId": "B", "metrics": [{"type": "count", "id": "1"}], "bucketAggs": []}]
+		}`, from, to))
+		require.Equal(t, http.StatusBadRequest, res.Status)
+		require.Contains(t, string(res.Body), "query B")
+	})
+}
@@ -0,0 +1,321 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+const (
+	// variablesResourcePath is the resource path of the template variable queries executed in the backend
+	variablesResourcePath = "variables"
+
+	variableQueryFindTerms  = "terms"
+	variableQueryFindFields = "fields"
+
+	defaultVariableTermsSize = 500
+	// variableQueryCacheTTL is how long the results of variable queries are cached. The time range of the queries is
+	// truncated to this duration, so refreshing a dashboard with a relative time range reuses the cached results.
+	variableQueryCacheTTL = time.Minute
+)
+
+// variableFieldTypes maps the types of the Elasticsearch mapping to the generic types of the fields variable query.
+var variableFieldTypes = map[string]string{
+	"float":         "number",
+	"double":        "number",
+	"half_float":    "number",
+	"scaled_float":  "number",
+	"integer":       "number",
+	"long":          "number",
+	"unsigned_long": "number",
+	"short":         "number",
+	"byte":          "number",
+	"histogram":     "number",
+	"date":          "date",
+	"date_nanos":    "date",
+	"string":        "string",
+	"text":          "string",
+	"nested":        "nested",
+}
+
+var errInvalidVariableQuery = errors.New("invalid variable query")
+
+// variableQuery is a template variable query, e.g. `{"find": "terms", "field": "hostname", "query": "status:500"}`.
+type variableQuery struct {
+	Find    string `json:"find"`
+	Field   string `json:"field,omitempty"`
+	Query   string `json:"query,omitempty"`
+	Size    int    `json:"size,omitempty"`
+	OrderBy string `json:"orderBy,omitempty"`
+	Order   string `json:"order,omitempty"`
+	// Type filters the fields of a fields query, either by the type of the mapping or by its generic type, e.g. "number".
+	Type string `json:"type,omitempty"`
+	// From and To are the time range of the query in epoch milliseconds.
+	From int64 `json:"from,omitempty"`
+	To   int64 `json:"to,omitempty"`
+}
+
+// variableValue is a value of a template variable.
+type variableValue struct {
+	Text  string `json:"text"`
+	Value any    `json:"value"`
+}
+
+func (q *variableQuery) validate() error {
+	switch q.Find {
+	case variableQueryFindTerms:
+		if q.Field == "" {
+			return fmt.Errorf("%w: field is required for terms queries", errInvalidVariableQuery)
+		}
+		if q.From == 0 || q.To == 0 {
+			return fmt.Errorf("%w: time range is required for terms queries", errInvalidVariableQuery)
+		}
+	case variableQueryFindFields:
+	default:
+		return fmt.Errorf("%w: unsupported find %q", errInvalidVariableQuery, q.Find)
+	}
+	return nil
+}
+
+func (q *variableQuery) timeRange() backend.TimeRange {
+	if q.From == 0 || q.To == 0 {
+		now := time.Now()
+		return backend.TimeRange{From: now, To: now}
+	}
+	return backend.TimeRange{From: time.UnixMilli(q.From), To: time.UnixMilli(q.To)}
+}
+
+// variableQueryCacheKey returns the cache key of a variable query. The user is part of the key because the query can
+// be executed with the forwarded OAuth identity of the user.
+func variableQueryCacheKey(pCtx backend.PluginContext, q variableQuery) string {
+	ttl := variableQueryCacheTTL.Milliseconds()
+	q.From -= q.From % ttl
+	q.To -= q.To % ttl
+	query, _ := json.Marshal(q)
+
+	var uid string
+	var updated int64
+	if pCtx.DataSourceInstanceSettings != nil {
+		uid = pCtx.DataSourceInstanceSettings.UID
+		updated = pCtx.DataSourceInstanceSettings.Updated.UnixMilli()
+	}
+	var login string
+	if pCtx.User != nil {
+		login = pCtx.User.Login
+	}
+	return fmt.Sprintf("%d/%s/%d/%s/%s", pCtx.OrgID, uid, updated, login, query)
+}
+
+func (s *Service) handleVariableQuery(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := eslog.FromContext(ctx)
+
+	var q variableQuery
+	if err := json.Unmarshal(req.Body, &q); err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidVariableQuery, err))
+	}
+	if err := q.validate(); err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	}
+
+	key := variableQueryCacheKey(req.PluginContext, q)
+	if cached, ok := s.variableCache.Get(key); ok {
+		logger.Debug("Variable query served from cache", "find", q.Find)
+		return sendResourceJSON(sender, http.StatusOK, cached.([]variableValue))
+	}
+
+	ds, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	var values []variableValue
+	switch q.Find {
+	case variableQueryFindTerms:
+		values, err = executeTermsVariableQuery(ctx, ds, q, logger, s.tracer)
+	case variableQueryFindFields:
+		values, err = executeFieldsVariableQuery(ctx, ds, q)
+	}
+	if err != nil {
+		logger.Error("Failed to execute variable query", "error", err, "find", q.Find)
+		return sendResourceError(sender, http.StatusBadGateway, err)
+	}
+
+	s.variableCache.Set(key, values, variableQueryCacheTTL)
+	return sendResourceJSON(sender, http.StatusOK, values)
+}
+
+// executeTermsVariableQuery returns the terms of a field in the documents of the time range that match the query.
+func executeTermsVariableQuery(ctx context.Context, ds *es.DatasourceInfo, q variableQuery, logger log.Logger, tracer tracing.Tracer) ([]variableValue, error) {
+	client, err := es.NewClient(ctx, ds, q.timeRange(), logger, tracer)
+	if err != nil {
+		return nil, err
+	}
+
+	size := q.Size
+	if size <= 0 {
+		size = defaultVariableTermsSize
+	}
+	orderBy, order := "_key", "asc"
+	if q.OrderBy == "doc_count" {
+		orderBy, order = "_count", "desc"
+	}
+	if q.Order != "" {
+		order = q.Order
+	}
+
+	ms := client.MultiSearch()
+	b := ms.Search(0)
+	b.Size(0)
+	filters := b.Query().Bool().Filter()
+	filters.AddDateRangeFilter(ds.ConfiguredFields.TimeField, q.To, q.From, es.DateFormatEpochMS)
+	filters.AddQueryStringFilter(q.Query, true)
+	b.Agg().Terms("1", q.Field, func(a *es.TermsAggregation, _ es.AggBuilder) {
+		a.Size = size
+		a.Order = map[string]interface{}{orderBy: order}
+	})
+
+	req, err := ms.Build()
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.ExecuteMultisearch(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, newElasticsearchError(res.Error)
+	}
+	if len(res.Responses) == 0 {
+		return nil, errors.New("elasticsearch returned no response")
+	}
+	if res.Responses[0].Error != nil {
+		return nil, newElasticsearchError(res.Responses[0].Error)
+	}
+
+	agg, _ := res.Responses[0].Aggregations["1"].(map[string]interface{})
+	buckets, _ := agg["buckets"].([]interface{})
+	values := make([]variableValue, 0, len(buckets))
+	for _, b := range buckets {
+		bucket, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		text, ok := bucket["key_as_string"].(string)
+		if !ok {
+			text = fmt.Sprint(bucket["key"])
+		}
+		values = append(values, variableValue{Text: text, Value: bucket["key"]})
+	}
+	return values, nil
+}
+
+// executeFieldsVariableQuery returns the names of the fields in the mappings of the indices of the time range.
+func executeFieldsVariableQuery(ctx context.Context, ds *es.DatasourceInfo, q variableQuery) ([]variableValue, error) {
+	indices, err := es.GetIndices(ds, q.timeRange())
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(ds.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, strings.Join(indices, ","), "_mapping")
+	u.RawQuery = "ignore_unavailable=true"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ds.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			eslog.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("elasticsearch returned status %d: %s", res.StatusCode, body)
+	}
+
+	var mappings map[string]struct {
+		Mappings map[string]interface{} `json:"mappings"`
+	}
+	if err := json.Unmarshal(body, &mappings); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{})
+	for _, index := range mappings {
+		if properties, ok := index.Mappings["properties"].(map[string]interface{}); ok {
+			collectMappingFields(names, "", properties, q.Type)
+			continue
+		}
+		// mappings of Elasticsearch versions before 7 are grouped by document type
+		for _, docType := range index.Mappings {
+			if mapping, ok := docType.(map[string]interface{}); ok {
+				if properties, ok := mapping["properties"].(map[string]interface{}); ok {
+					collectMappingFields(names, "", properties, q.Type)
+				}
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	values := make([]variableValue, 0, len(sorted))
+	for _, name := range sorted {
+		values = append(values, variableValue{Text: name, Value: name})
+	}
+	return values, nil
+}
+
+// collectMappingFields adds the names of the fields of the mapping properties that have the type. Object fields and
+// multi-fields are walked with their name as prefix, e.g. `hostname.keyword`.
+func collectMappingFields(names map[string]struct{}, prefix string, properties map[string]interface{}, fieldType string) {
+	for name, p := range properties {
+		if prefix == "" && strings.HasPrefix(name, "_") {
+			continue
+		}
+		property, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fullName := prefix + name
+		if esType, ok := property["type"].(string); ok {
+			if fieldType == "" || fieldType == esType || fieldType == variableFieldTypes[esType] {
+				names[fullName] = struct{}{}
+			}
+		}
+		if fields, ok := property["fields"].(map[string]interface{}); ok {
+			collectMappingFields(names, fullName+".", fields, fieldType)
+		}
+		if nested, ok := property["properties"].(map[string]interface{}); ok {
+			collectMappingFields(names, fullName+".", nested, fieldType)
+		}
+	}
+}
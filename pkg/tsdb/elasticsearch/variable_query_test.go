@@ -0,0 +1,154 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestVariableQuery(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	to := from + time.Hour.Milliseconds()
+
+	t.Run("terms query should return the terms of the field and cache them", func(t *testing.T) {
+		var requests int
+		var body string
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requests++
+			require.Equal(t, "/_msearch", r.URL.Path)
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			body = string(b)
+			_, err = rw.Write([]byte(`{"responses":[{"aggregations":{"1":{"buckets":[
+				{"key":"server1","doc_count":5},
+				{"key":1704067200000,"key_as_string":"2024-01-01","doc_count":1}
+			]}}}]}`))
+			require.NoError(t, err)
+		}))
+		t.Cleanup(srv.Close)
+		s := newResourceTestService(srv)
+
+		query := fmt.Sprintf(`{"find":"terms","field":"hostname","query":"status:500","orderBy":"doc_count","from":%d,"to":%d}`, from, to)
+		res := callVariableQuery(t, s, query)
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `[{"text":"server1","value":"server1"},{"text":"2024-01-01","value":1704067200000}]`, string(res.Body))
+		require.Contains(t, body, `"terms":{"field":"hostname","size":500,"order":{"_count":"desc"}}`)
+		require.Contains(t, body, `"query":"status:500"`)
+
+		res = callVariableQuery(t, s, query)
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, 1, requests)
+	})
+
+	t.Run("fields query should return the fields of the type", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/logs/_mapping", r.URL.Path)
+			_, err := rw.Write([]byte(`{"logs":{"mappings":{"properties":{
+				"_meta":{"type":"keyword"},
+				"@timestamp":{"type":"date"},
+				"bytes":{"type":"long"},
+				"hostname":{"type":"text","fields":{"keyword":{"type":"keyword"}}},
+				"request":{"properties":{"duration":{"type":"float"},"method":{"type":"keyword"}}}
+			}}}}`))
+			require.NoError(t, err)
+		}))
+		t.Cleanup(srv.Close)
+		s := newResourceTestService(srv)
+
+		res := callVariableQuery(t, s, `{"find":"fields","type":"keyword"}`)
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `[{"text":"hostname.keyword","value":"hostname.keyword"},{"text":"request.method","value":"request.method"}]`, string(res.Body))
+
+		res = callVariableQuery(t, s, `{"find":"fields","type":"number"}`)
+		require.JSONEq(t, `[{"text":"bytes","value":"bytes"},{"text":"request.duration","value":"request.duration"}]`, string(res.Body))
+	})
+
+	t.Run("should reject invalid queries", func(t *testing.T) {
+		s := newResourceTestService(nil)
+
+		res := callVariableQuery(t, s, `{"find":"terms"}`)
+		require.Equal(t, http.StatusBadRequest, res.Status)
+		require.Contains(t, string(res.Body), "field is required")
+
+		res = callVariableQuery(t, s, `{"find":"values"}`)
+		require.Equal(t, http.StatusBadRequest, res.Status)
+	})
+
+	t.Run("cache key should depend on the user and ignore small changes of the time range", func(t *testing.T) {
+		q := variableQuery{Find: "terms", Field: "hostname", From: from, To: to}
+		moved := q
+		moved.From, moved.To = from+10, to+10
+		pCtx := backend.PluginContext{OrgID: 1, User: &backend.User{Login: "admin"}}
+		require.Equal(t, variableQueryCacheKey(pCtx, q), variableQueryCacheKey(pCtx, moved))
+		require.NotEqual(t, variableQueryCacheKey(pCtx, q), variableQueryCacheKey(backend.PluginContext{OrgID: 1, User: &backend.User{Login: "viewer"}}, q))
+	})
+}
+
+type resourceInstanceManager struct {
+	ds es.DatasourceInfo
+}
+
+func (m *resourceInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.ds, nil
+}
+
+func (*resourceInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+func newResourceTestService(srv *httptest.Server) *Service {
+	ds := es.DatasourceInfo{
+		Database:         "logs",
+		ConfiguredFields: es.ConfiguredFields{TimeField: "@timestamp"},
+	}
+	if srv != nil {
+		ds.URL = srv.URL
+		ds.HTTPClient = srv.Client()
+	}
+	return &Service{
+		im:            &resourceInstanceManager{ds: ds},
+		tracer:        tracing.InitializeTracerForTest(),
+		logger:        eslog,
+		variableCache: localcache.New(variableQueryCacheTTL, 2*variableQueryCacheTTL),
+		queryCache:    localcache.New(maxQueryCacheTTL, queryCacheCleanupInterval),
+	}
+}
+
+type fakeResourceSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *fakeResourceSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
+
+func callVariableQuery(t *testing.T, s *Service, query string) *backend.CallResourceResponse {
+	t.Helper()
+	return callResource(t, s, variablesResourcePath, query)
+}
+
+func callResource(t *testing.T, s *Service, path, query string) *backend.CallResourceResponse {
+	t.Helper()
+	sender := &fakeResourceSender{}
+	err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+		Path:   path,
+		Method: http.MethodPost,
+		Body:   []byte(query),
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.res)
+	return sender.res
+}